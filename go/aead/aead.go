@@ -34,6 +34,10 @@ func init() {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
 
+	if err := registry.RegisterKeyManager(newAESGCMSIVKeyManager()); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+
 	if err := registry.RegisterKeyManager(newChaCha20Poly1305KeyManager()); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
@@ -41,7 +45,18 @@ func init() {
 	if err := registry.RegisterKeyManager(newXChaCha20Poly1305KeyManager()); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
+	if err := registry.RegisterKeyManager(newXAES256GCMKeyManager()); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newAscon128aKeyManager()); err != nil {
+		panic(fmt.Sprintf("aead.init() failed: %v", err))
+	}
 	if err := registry.RegisterKeyManager(newKMSEnvelopeAEADKeyManager()); err != nil {
 		panic(fmt.Sprintf("aead.init() failed: %v", err))
 	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("aead.init() failed: %v", err))
+		}
+	}
 }
@@ -17,7 +17,10 @@
 package aead
 
 import (
+	"errors"
 	"fmt"
+	"runtime"
+	"sync"
 
 	"github.com/google/tink/go/core/cryptofmt"
 	"github.com/google/tink/go/core/primitiveset"
@@ -26,9 +29,57 @@ import (
 	"github.com/google/tink/go/tink"
 )
 
-// New returns an AEAD primitive from the given keyset handle.
-func New(h *keyset.Handle) (tink.AEAD, error) {
-	return NewWithKeyManager(h, nil /*keyManager*/)
+// minBatchPerWorker is the smallest number of messages that EncryptBatch and
+// DecryptBatch hand to a goroutine, so that small batches are not dominated by
+// scheduling.
+const minBatchPerWorker = 64
+
+// Option configures the AEAD primitive returned by New.
+type Option func(*options)
+
+type options struct {
+	mostRecentlyUsedFirst bool
+	// maxRawKeyAttempts is the maximal number of RAW keys that are tried to
+	// decrypt a ciphertext, or zero if all are tried.
+	maxRawKeyAttempts int
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithMostRecentlyUsedFirst makes the AEAD primitive try the keys that match a
+// ciphertext in the order of their most recent successful decryption, instead
+// of the order of the keyset. During long key rotations, most ciphertexts are
+// usually produced by a few keys, which are then tried first.
+func WithMostRecentlyUsedFirst() Option {
+	return func(o *options) {
+		o.mostRecentlyUsedFirst = true
+	}
+}
+
+// WithMaxRawKeyAttempts makes the AEAD primitive try at most n keys with output
+// prefix type RAW to decrypt a ciphertext, which bounds the cost of decrypting
+// invalid ciphertexts. Combined with WithMostRecentlyUsedFirst, the most
+// recently used RAW keys are tried. A value of zero or less means no limit.
+func WithMaxRawKeyAttempts(n int) Option {
+	return func(o *options) {
+		o.maxRawKeyAttempts = n
+	}
+}
+
+// New returns an AEAD primitive from the given keyset handle, configured with
+// the given options.
+func New(h *keyset.Handle, opts ...Option) (tink.AEAD, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newAEAD(ps, newOptions(opts))
 }
 
 // NewWithKeyManager returns an AEAD primitive from the given keyset handle and custom key manager.
@@ -38,14 +89,112 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.AEAD, err
 	if err != nil {
 		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
 	}
+	return newAEAD(ps, options{})
+}
+
+// NewWithRegistry returns an AEAD primitive from the given keyset handle,
+// configured with the given options, using the key managers of r instead of
+// the global registry. This lets libraries restrict which key types they
+// accept without affecting the rest of the binary.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry, opts ...Option) (tink.AEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newAEAD(ps, newOptions(opts))
+}
+
+func newAEAD(ps *primitiveset.PrimitiveSet, opts options) (tink.AEAD, error) {
+	a, err := newWrappedAead(ps)
+	if err != nil {
+		return nil, err
+	}
+	a.opts = opts
+	if opts.mostRecentlyUsedFirst {
+		a.order = &decryptionOrder{entries: make(map[string][]*primitiveset.Entry)}
+	}
+	if s, ok := (ps.Primary.Primitive).(SizedAEAD); ok {
+		return &sizedWrappedAead{wrappedAead: a, primary: s}, nil
+	}
+	return a, nil
+}
+
+// ErrDecryptionFailed is returned when a ciphertext cannot be decrypted with
+// any key of the keyset, as opposed to errors caused by an invalid keyset or
+// configuration. Use errors.Is to test for it.
+var ErrDecryptionFailed = errors.New("aead_factory: decryption failed")
+
+// BufferAEAD is implemented by the AEAD primitives returned by New, and by the
+// AES-GCM, ChaCha20-Poly1305, XChaCha20-Poly1305 and XAES-256-GCM primitives in
+// package subtle. It encrypts and decrypts into caller-provided buffers, so
+// that hot paths can reuse memory instead of allocating for every message.
+type BufferAEAD interface {
+	tink.AEAD
 
-	return newWrappedAead(ps)
+	// EncryptTo appends the result of Encrypt(pt, ad) to dst and returns the
+	// updated slice. If dst has enough spare capacity, no allocation is needed
+	// for the ciphertext. dst must not overlap pt.
+	EncryptTo(dst, pt, ad []byte) ([]byte, error)
+
+	// DecryptTo appends the result of Decrypt(ct, ad) to dst and returns the
+	// updated slice. If dst has enough spare capacity, no allocation is needed
+	// for the plaintext. dst must not overlap ct.
+	DecryptTo(dst, ct, ad []byte) ([]byte, error)
+}
+
+// SizedAEAD is implemented by AEAD primitives that can tell the size of their
+// ciphertexts in advance, so that storage layers can pre-allocate records and
+// enforce size limits without encrypting a probe message. All AEAD primitives
+// in package subtle implement it. The primitive returned by New implements it
+// if the primitive of the primary key does, which is not the case for KMS
+// envelope AEAD.
+type SizedAEAD interface {
+	tink.AEAD
+
+	// CiphertextOverhead returns the number of bytes by which a ciphertext is
+	// longer than its plaintext.
+	CiphertextOverhead() int
+
+	// MaxCiphertextSize returns the maximal size of the ciphertext of a
+	// plaintext of the given size.
+	MaxCiphertextSize(plaintextLen int) int
+}
+
+// BatchAEAD is implemented by the AEAD primitives returned by New. It encrypts
+// and decrypts many messages at once, which is faster than calling Encrypt or
+// Decrypt in a loop.
+type BatchAEAD interface {
+	tink.AEAD
+
+	// EncryptBatch encrypts each plaintext with its associated data. The i-th
+	// returned ciphertext and error are the result of
+	// Encrypt(msgs[i].Plaintext, msgs[i].AssociatedData). Large batches are
+	// encrypted concurrently.
+	EncryptBatch(msgs []struct{ Plaintext, AssociatedData []byte }) ([][]byte, []error)
+
+	// DecryptBatch decrypts each ciphertext with its associated data. The i-th
+	// returned plaintext and error are the result of
+	// Decrypt(msgs[i].Ciphertext, msgs[i].AssociatedData). Large batches are
+	// decrypted concurrently.
+	DecryptBatch(msgs []struct{ Ciphertext, AssociatedData []byte }) ([][]byte, []error)
 }
 
 // wrappedAead is an AEAD implementation that uses the underlying primitive set for encryption
 // and decryption.
 type wrappedAead struct {
-	ps *primitiveset.PrimitiveSet
+	ps   *primitiveset.PrimitiveSet
+	opts options
+	// order is nil unless keys are tried in the order of their most recent
+	// successful decryption.
+	order *decryptionOrder
+}
+
+// decryptionOrder holds the entries for each output prefix, ordered by their
+// most recent successful decryption. The slices are never modified in place,
+// so that they can be used without holding the lock.
+type decryptionOrder struct {
+	mu      sync.Mutex
+	entries map[string][]*primitiveset.Entry
 }
 
 func newWrappedAead(ps *primitiveset.PrimitiveSet) (*wrappedAead, error) {
@@ -71,6 +220,9 @@ func newWrappedAead(ps *primitiveset.PrimitiveSet) (*wrappedAead, error) {
 // It returns the concatenation of the primary's identifier and the ciphertext.
 func (a *wrappedAead) Encrypt(pt, ad []byte) ([]byte, error) {
 	primary := a.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
 	p, ok := (primary.Primitive).(tink.AEAD)
 	if !ok {
 		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
@@ -87,41 +239,241 @@ func (a *wrappedAead) Encrypt(pt, ad []byte) ([]byte, error) {
 // additional authenticated data. It returns the corresponding plaintext if the
 // ciphertext is authenticated.
 func (a *wrappedAead) Decrypt(ct, ad []byte) ([]byte, error) {
+	return a.decryptTo(nil, ct, ad, a.entries)
+}
+
+// EncryptTo is like Encrypt, but appends the result to dst. If the primary
+// primitive does not implement BufferAEAD, the ciphertext is copied into dst.
+func (a *wrappedAead) EncryptTo(dst, pt, ad []byte) ([]byte, error) {
+	primary := a.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
+	p, ok := (primary.Primitive).(tink.AEAD)
+	if !ok {
+		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	}
+
+	ret := append(dst, primary.Prefix...)
+	if b, ok := p.(BufferAEAD); ok {
+		return b.EncryptTo(ret, pt, ad)
+	}
+	ct, err := p.Encrypt(pt, ad)
+	if err != nil {
+		return nil, err
+	}
+	return append(ret, ct...), nil
+}
+
+// DecryptTo is like Decrypt, but appends the result to dst. Primitives that do
+// not implement BufferAEAD decrypt into a new slice, which is copied into dst.
+func (a *wrappedAead) DecryptTo(dst, ct, ad []byte) ([]byte, error) {
+	return a.decryptTo(dst, ct, ad, a.entries)
+}
+
+// decryptTo tries the entries for the prefix of ct, and then the RAW entries,
+// as returned by the given lookup function, and appends the plaintext of the
+// first entry that decrypts ct to dst.
+func (a *wrappedAead) decryptTo(dst, ct, ad []byte, lookup func(prefix string) []*primitiveset.Entry) ([]byte, error) {
 	// try non-raw keys
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(ct) > prefixSize {
-		prefix := ct[:prefixSize]
-		ctNoPrefix := ct[prefixSize:]
-		entries, err := a.ps.EntriesForPrefix(string(prefix))
-		if err == nil {
-			for i := 0; i < len(entries); i++ {
-				p, ok := (entries[i].Primitive).(tink.AEAD)
-				if !ok {
-					return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
-				}
+		prefix := string(ct[:prefixSize])
+		if pt, ok := a.tryEntries(prefix, lookup(prefix), dst, ct[prefixSize:], ad); ok {
+			return pt, nil
+		}
+	}
+	// try raw keys
+	raw := lookup(cryptofmt.RawPrefix)
+	if n := a.opts.maxRawKeyAttempts; n > 0 && len(raw) > n {
+		raw = raw[:n]
+	}
+	if pt, ok := a.tryEntries(cryptofmt.RawPrefix, raw, dst, ct, ad); ok {
+		return pt, nil
+	}
+	// nothing worked
+	return nil, ErrDecryptionFailed
+}
 
-				pt, err := p.Decrypt(ctNoPrefix, ad)
-				if err == nil {
-					return pt, nil
-				}
+// tryEntries returns the plaintext of ct under the first of the given entries
+// for prefix that decrypts it, appended to dst.
+func (a *wrappedAead) tryEntries(prefix string, entries []*primitiveset.Entry, dst, ct, ad []byte) ([]byte, bool) {
+	for i, e := range entries {
+		if pt, err := decryptTo(e, dst, ct, ad); err == nil {
+			if i > 0 && a.order != nil {
+				a.order.promote(prefix, e)
 			}
+			return pt, true
 		}
 	}
-	// try raw keys
-	entries, err := a.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			p, ok := (entries[i].Primitive).(tink.AEAD)
-			if !ok {
-				return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	return nil, false
+}
+
+// entries returns the entries for the given output prefix in the order in which
+// they are tried.
+func (a *wrappedAead) entries(prefix string) []*primitiveset.Entry {
+	if a.order != nil {
+		return a.order.get(a.ps, prefix)
+	}
+	// A failed lookup means that there are no entries for prefix.
+	entries, _ := a.ps.EntriesForPrefix(prefix)
+	return entries
+}
+
+// get returns the entries of ps for the given output prefix, most recently
+// successful first.
+func (o *decryptionOrder) get(ps *primitiveset.PrimitiveSet, prefix string) []*primitiveset.Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries, ok := o.entries[prefix]
+	if !ok {
+		entries, _ = ps.EntriesForPrefix(prefix)
+		o.entries[prefix] = entries
+	}
+	return entries
+}
+
+// promote moves the given entry for the given output prefix to the front.
+func (o *decryptionOrder) promote(prefix string, e *primitiveset.Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	old := o.entries[prefix]
+	if len(old) == 0 || old[0] == e {
+		return
+	}
+	entries := make([]*primitiveset.Entry, 0, len(old))
+	entries = append(entries, e)
+	for _, x := range old {
+		if x != e {
+			entries = append(entries, x)
+		}
+	}
+	o.entries[prefix] = entries
+}
+
+func decryptTo(e *primitiveset.Entry, dst, ct, ad []byte) ([]byte, error) {
+	if b, ok := (e.Primitive).(BufferAEAD); ok {
+		return b.DecryptTo(dst, ct, ad)
+	}
+	p, ok := (e.Primitive).(tink.AEAD)
+	if !ok {
+		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	}
+	pt, err := p.Decrypt(ct, ad)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, pt...), nil
+}
+
+// EncryptBatch encrypts each of the given plaintexts with the primary
+// primitive, which is resolved only once for the whole batch. Large batches
+// are split across GOMAXPROCS goroutines.
+func (a *wrappedAead) EncryptBatch(msgs []struct{ Plaintext, AssociatedData []byte }) ([][]byte, []error) {
+	cts := make([][]byte, len(msgs))
+	errs := make([]error, len(msgs))
+	primary := a.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return cts, errs
+	}
+	p, ok := (primary.Primitive).(tink.AEAD)
+	if !ok {
+		for i := range errs {
+			errs[i] = fmt.Errorf("aead_factory: not an AEAD primitive")
+		}
+		return cts, errs
+	}
+	b, _ := p.(BufferAEAD)
+	sized, _ := p.(SizedAEAD)
+	runBatch(len(msgs), func(start, end int) {
+		for i := start; i < end; i++ {
+			pt, ad := msgs[i].Plaintext, msgs[i].AssociatedData
+			if b == nil {
+				cts[i], errs[i] = a.Encrypt(pt, ad)
+				continue
 			}
+			var dst []byte
+			if sized != nil {
+				dst = make([]byte, 0, len(primary.Prefix)+sized.MaxCiphertextSize(len(pt)))
+			}
+			cts[i], errs[i] = b.EncryptTo(append(dst, primary.Prefix...), pt, ad)
+		}
+	})
+	return cts, errs
+}
 
-			pt, err := p.Decrypt(ct, ad)
-			if err == nil {
-				return pt, nil
+// DecryptBatch decrypts each of the given ciphertexts. Unless keys are tried
+// in the order of their most recent use, the entries for each output prefix are
+// looked up once per goroutine rather than once per message. Large batches are
+// split across GOMAXPROCS goroutines.
+func (a *wrappedAead) DecryptBatch(msgs []struct{ Ciphertext, AssociatedData []byte }) ([][]byte, []error) {
+	pts := make([][]byte, len(msgs))
+	errs := make([]error, len(msgs))
+	runBatch(len(msgs), func(start, end int) {
+		lookup := a.entries
+		if a.order == nil {
+			cache := make(map[string][]*primitiveset.Entry)
+			lookup = func(prefix string) []*primitiveset.Entry {
+				entries, ok := cache[prefix]
+				if !ok {
+					entries = a.entries(prefix)
+					cache[prefix] = entries
+				}
+				return entries
 			}
 		}
+		for i := start; i < end; i++ {
+			pts[i], errs[i] = a.decryptTo(nil, msgs[i].Ciphertext, msgs[i].AssociatedData, lookup)
+		}
+	})
+	return pts, errs
+}
+
+// runBatch calls f on consecutive ranges that cover [0, n). Large batches are
+// split across GOMAXPROCS goroutines.
+func runBatch(n int, f func(start, end int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if w := (n + minBatchPerWorker - 1) / minBatchPerWorker; w < workers {
+		workers = w
 	}
-	// nothing worked
-	return nil, fmt.Errorf("aead_factory: decryption failed")
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// sizedWrappedAead is a wrappedAead whose primary primitive implements
+// SizedAEAD.
+type sizedWrappedAead struct {
+	*wrappedAead
+	primary SizedAEAD
+}
+
+// CiphertextOverhead returns the number of bytes by which a ciphertext of the
+// primary key, including the output prefix, is longer than its plaintext.
+func (a *sizedWrappedAead) CiphertextOverhead() int {
+	return len(a.ps.Primary.Prefix) + a.primary.CiphertextOverhead()
+}
+
+// MaxCiphertextSize returns the maximal size of the ciphertext of a plaintext
+// of the given size, including the output prefix.
+func (a *sizedWrappedAead) MaxCiphertextSize(plaintextLen int) int {
+	return len(a.ps.Primary.Prefix) + a.primary.MaxCiphertextSize(plaintextLen)
 }
@@ -18,12 +18,16 @@ package aead_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/signature"
 	"github.com/google/tink/go/subtle/random"
@@ -164,3 +168,453 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Fatalf("calling New() with good *keyset.Handle failed: %s", err)
 	}
 }
+
+func TestFactoryDecryptionFailedIsErrDecryptionFailed(t *testing.T) {
+	kh, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	a, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), []byte("ad"))
+	if err != nil {
+		t.Fatalf("a.Encrypt failed: %s", err)
+	}
+	if _, err := a.Decrypt(ct, []byte("other ad")); !errors.Is(err, aead.ErrDecryptionFailed) {
+		t.Errorf("a.Decrypt with wrong ad = %v, want aead.ErrDecryptionFailed", err)
+	}
+}
+
+func TestFactoryEncryptToDecryptTo(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		aead.AES128GCMKeyTemplate(),
+		aead.AES256GCMNoPrefixKeyTemplate(),
+		// AES-CTR-HMAC does not implement BufferAEAD.
+		aead.AES128CTRHMACSHA256KeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		p, err := aead.New(kh)
+		if err != nil {
+			t.Fatalf("aead.New failed: %s", err)
+		}
+		a, ok := p.(aead.BufferAEAD)
+		if !ok {
+			t.Fatalf("primitive does not implement aead.BufferAEAD")
+		}
+		pt := random.GetRandomBytes(100)
+		ad := []byte("ad")
+		buf := make([]byte, 0, 1024)
+
+		ct, err := a.EncryptTo(buf, pt, ad)
+		if err != nil {
+			t.Fatalf("a.EncryptTo failed: %s", err)
+		}
+		if &ct[0] != &buf[:1][0] {
+			t.Errorf("a.EncryptTo did not reuse dst")
+		}
+		decrypted, err := a.Decrypt(ct, ad)
+		if err != nil {
+			t.Fatalf("a.Decrypt failed: %s", err)
+		}
+		if !bytes.Equal(decrypted, pt) {
+			t.Errorf("a.Decrypt = %x, want %x", decrypted, pt)
+		}
+
+		out := make([]byte, 0, 1024)
+		decrypted, err = a.DecryptTo(out, ct, ad)
+		if err != nil {
+			t.Fatalf("a.DecryptTo failed: %s", err)
+		}
+		if &decrypted[0] != &out[:1][0] {
+			t.Errorf("a.DecryptTo did not reuse dst")
+		}
+		if !bytes.Equal(decrypted, pt) {
+			t.Errorf("a.DecryptTo = %x, want %x", decrypted, pt)
+		}
+		if _, err := a.DecryptTo(out, ct, []byte("other ad")); !errors.Is(err, aead.ErrDecryptionFailed) {
+			t.Errorf("a.DecryptTo with wrong ad = %v, want aead.ErrDecryptionFailed", err)
+		}
+	}
+}
+
+func TestFactoryCiphertextSize(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		aead.AES128GCMKeyTemplate(),
+		aead.AES256GCMNoPrefixKeyTemplate(),
+		aead.AES128GCMSIVKeyTemplate(),
+		aead.AES128CTRHMACSHA256KeyTemplate(),
+		aead.ChaCha20Poly1305KeyTemplate(),
+		aead.XChaCha20Poly1305KeyTemplate(),
+		aead.XAES256GCMKeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		p, err := aead.New(kh)
+		if err != nil {
+			t.Fatalf("aead.New failed: %s", err)
+		}
+		a, ok := p.(aead.SizedAEAD)
+		if !ok {
+			t.Fatalf("primitive of %s does not implement aead.SizedAEAD", template.TypeUrl)
+		}
+		for _, ptSize := range []int{0, 1, 100} {
+			ct, err := a.Encrypt(make([]byte, ptSize), nil)
+			if err != nil {
+				t.Fatalf("a.Encrypt failed: %s", err)
+			}
+			if got := a.MaxCiphertextSize(ptSize); got != len(ct) {
+				t.Errorf("%s: a.MaxCiphertextSize(%d) = %d, want %d", template.TypeUrl, ptSize, got, len(ct))
+			}
+			if got := a.CiphertextOverhead(); got != len(ct)-ptSize {
+				t.Errorf("%s: a.CiphertextOverhead() = %d, want %d", template.TypeUrl, got, len(ct)-ptSize)
+			}
+		}
+	}
+}
+
+func TestFactoryEncryptDecryptBatch(t *testing.T) {
+	ks := testutil.NewTestAESGCMKeyset(tinkpb.OutputPrefixType_TINK)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	p, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	a, ok := p.(aead.BatchAEAD)
+	if !ok {
+		t.Fatalf("primitive does not implement aead.BatchAEAD")
+	}
+	// A primitive of the non-primary RAW key of the same keyset.
+	rawKey := ks.Key[1]
+	rawKH, err := testkeyset.NewHandle(testutil.NewKeyset(rawKey.KeyId, []*tinkpb.Keyset_Key{rawKey}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	raw, err := aead.New(rawKH)
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+
+	// Large enough to be processed concurrently.
+	const n = 1000
+	msgs := make([]struct{ Plaintext, AssociatedData []byte }, n)
+	for i := range msgs {
+		msgs[i].Plaintext = random.GetRandomBytes(uint32(i % 50))
+		msgs[i].AssociatedData = []byte(fmt.Sprintf("ad %d", i))
+	}
+	cts, errs := a.EncryptBatch(msgs)
+	if len(cts) != n || len(errs) != n {
+		t.Fatalf("EncryptBatch returned %d ciphertexts and %d errors, want %d", len(cts), len(errs), n)
+	}
+	encrypted := make([]struct{ Ciphertext, AssociatedData []byte }, n)
+	for i := range msgs {
+		if errs[i] != nil {
+			t.Fatalf("EncryptBatch()[%d] failed: %s", i, errs[i])
+		}
+		// Each message must decrypt individually.
+		pt, err := a.Decrypt(cts[i], msgs[i].AssociatedData)
+		if err != nil {
+			t.Fatalf("a.Decrypt(EncryptBatch()[%d]) failed: %s", i, err)
+		}
+		if !bytes.Equal(pt, msgs[i].Plaintext) {
+			t.Errorf("a.Decrypt(EncryptBatch()[%d]) = %x, want %x", i, pt, msgs[i].Plaintext)
+		}
+		encrypted[i].Ciphertext = cts[i]
+		encrypted[i].AssociatedData = msgs[i].AssociatedData
+	}
+	// Mix in ciphertexts of the RAW key and invalid ciphertexts.
+	for i := 0; i < n; i += 3 {
+		ct, err := raw.Encrypt(msgs[i].Plaintext, msgs[i].AssociatedData)
+		if err != nil {
+			t.Fatalf("raw.Encrypt failed: %s", err)
+		}
+		encrypted[i].Ciphertext = ct
+	}
+	for i := 1; i < n; i += 7 {
+		encrypted[i].AssociatedData = []byte("other ad")
+	}
+
+	pts, errs := a.DecryptBatch(encrypted)
+	if len(pts) != n || len(errs) != n {
+		t.Fatalf("DecryptBatch returned %d plaintexts and %d errors, want %d", len(pts), len(errs), n)
+	}
+	for i := range encrypted {
+		if i%7 == 1 {
+			if !errors.Is(errs[i], aead.ErrDecryptionFailed) {
+				t.Errorf("DecryptBatch()[%d] = %v, want aead.ErrDecryptionFailed", i, errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("DecryptBatch()[%d] failed: %s", i, errs[i])
+			continue
+		}
+		if !bytes.Equal(pts[i], msgs[i].Plaintext) {
+			t.Errorf("DecryptBatch()[%d] = %x, want %x", i, pts[i], msgs[i].Plaintext)
+		}
+	}
+
+	if cts, errs := a.EncryptBatch(nil); len(cts) != 0 || len(errs) != 0 {
+		t.Errorf("EncryptBatch(nil) = %v, %v, want no results", cts, errs)
+	}
+	if pts, errs := a.DecryptBatch(nil); len(pts) != 0 || len(errs) != 0 {
+		t.Errorf("DecryptBatch(nil) = %v, %v, want no results", pts, errs)
+	}
+}
+
+const attemptCountingTypeURL = "type.googleapis.com/google.crypto.tink.AttemptCountingAesGcmKey"
+
+var (
+	registerAttemptCountingKeyManager sync.Once
+	// decryptAttempts counts the calls to Decrypt of all attemptCountingAEAD.
+	decryptAttempts int32
+)
+
+// attemptCountingAEAD counts the decryption attempts of an AEAD.
+type attemptCountingAEAD struct {
+	tink.AEAD
+}
+
+func (a *attemptCountingAEAD) Decrypt(ct, ad []byte) ([]byte, error) {
+	atomic.AddInt32(&decryptAttempts, 1)
+	return a.AEAD.Decrypt(ct, ad)
+}
+
+// attemptCountingKeyManager wraps the primitives of a key manager in
+// attemptCountingAEAD, for keys of type attemptCountingTypeURL.
+type attemptCountingKeyManager struct {
+	registry.KeyManager
+}
+
+func (km *attemptCountingKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	p, err := km.KeyManager.Primitive(serializedKey)
+	if err != nil {
+		return nil, err
+	}
+	return &attemptCountingAEAD{AEAD: p.(tink.AEAD)}, nil
+}
+
+func (km *attemptCountingKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == attemptCountingTypeURL
+}
+
+func (km *attemptCountingKeyManager) TypeURL() string {
+	return attemptCountingTypeURL
+}
+
+// newAttemptCountingKeyset returns a keyset of AES-GCM keys with IDs 1 to n
+// and the given output prefix types, whose primitives count decryption
+// attempts. The first key is the primary key.
+func newAttemptCountingKeyset(t *testing.T, prefixTypes ...tinkpb.OutputPrefixType) *tinkpb.Keyset {
+	t.Helper()
+	registerAttemptCountingKeyManager.Do(func() {
+		km, err := registry.GetKeyManager(testutil.AESGCMTypeURL)
+		if err != nil {
+			t.Fatalf("registry.GetKeyManager failed: %s", err)
+		}
+		if err := registry.RegisterKeyManager(&attemptCountingKeyManager{KeyManager: km}); err != nil {
+			t.Fatalf("registry.RegisterKeyManager failed: %s", err)
+		}
+	})
+	var keys []*tinkpb.Keyset_Key
+	for i, prefixType := range prefixTypes {
+		keyData := testutil.NewAESGCMKeyData(16)
+		keyData.TypeUrl = attemptCountingTypeURL
+		keys = append(keys, testutil.NewKey(keyData, tinkpb.KeyStatusType_ENABLED, uint32(i+1), prefixType))
+	}
+	return testutil.NewKeyset(1, keys)
+}
+
+// encryptWithKey encrypts pt with the key of ks with the given ID.
+func encryptWithKey(t *testing.T, ks *tinkpb.Keyset, keyID uint32, pt []byte) []byte {
+	t.Helper()
+	for _, key := range ks.Key {
+		if key.KeyId != keyID {
+			continue
+		}
+		kh, err := testkeyset.NewHandle(testutil.NewKeyset(keyID, []*tinkpb.Keyset_Key{key}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		a, err := aead.New(kh)
+		if err != nil {
+			t.Fatalf("aead.New failed: %s", err)
+		}
+		ct, err := a.Encrypt(pt, nil)
+		if err != nil {
+			t.Fatalf("a.Encrypt failed: %s", err)
+		}
+		return ct
+	}
+	t.Fatalf("no key with ID %d", keyID)
+	return nil
+}
+
+// countDecryptAttempts returns the number of decryption attempts of
+// a.Decrypt(ct, nil) and its error.
+func countDecryptAttempts(a tink.AEAD, ct []byte) (int32, error) {
+	before := atomic.LoadInt32(&decryptAttempts)
+	_, err := a.Decrypt(ct, nil)
+	return atomic.LoadInt32(&decryptAttempts) - before, err
+}
+
+func TestFactoryDecryptionOrder(t *testing.T) {
+	raw := tinkpb.OutputPrefixType_RAW
+	ks := newAttemptCountingKeyset(t, raw, raw, raw, raw)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	pt := []byte("plaintext")
+	ct4 := encryptWithKey(t, ks, 4, pt)
+
+	for _, test := range []struct {
+		name string
+		opts []aead.Option
+		// want are the expected numbers of attempts of consecutive decryptions of
+		// a ciphertext of the last key.
+		want []int32
+	}{
+		{
+			name: "keyset order",
+			want: []int32{4, 4, 4},
+		},
+		{
+			name: "most recently used first",
+			opts: []aead.Option{aead.WithMostRecentlyUsedFirst()},
+			want: []int32{4, 1, 1},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := aead.New(kh, test.opts...)
+			if err != nil {
+				t.Fatalf("aead.New failed: %s", err)
+			}
+			for i, want := range test.want {
+				got, err := countDecryptAttempts(a, ct4)
+				if err != nil {
+					t.Fatalf("a.Decrypt failed: %s", err)
+				}
+				if got != want {
+					t.Errorf("decryption %d took %d attempts, want %d", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFactoryMostRecentlyUsedFirstDecryptBatch(t *testing.T) {
+	raw := tinkpb.OutputPrefixType_RAW
+	ks := newAttemptCountingKeyset(t, raw, raw, raw)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	a, err := aead.New(kh, aead.WithMostRecentlyUsedFirst())
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	msgs := make([]struct{ Ciphertext, AssociatedData []byte }, 500)
+	for i := range msgs {
+		msgs[i].Ciphertext = encryptWithKey(t, ks, uint32(i%3+1), []byte{byte(i)})
+	}
+	pts, errs := a.(aead.BatchAEAD).DecryptBatch(msgs)
+	for i := range msgs {
+		if errs[i] != nil {
+			t.Fatalf("DecryptBatch()[%d] failed: %s", i, errs[i])
+		}
+		if !bytes.Equal(pts[i], []byte{byte(i)}) {
+			t.Errorf("DecryptBatch()[%d] = %x, want %x", i, pts[i], []byte{byte(i)})
+		}
+	}
+}
+
+func TestFactoryMaxRawKeyAttempts(t *testing.T) {
+	raw := tinkpb.OutputPrefixType_RAW
+	ks := newAttemptCountingKeyset(t, tinkpb.OutputPrefixType_TINK, raw, raw, raw)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	pt := []byte("plaintext")
+	a, err := aead.New(kh, aead.WithMaxRawKeyAttempts(2))
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	// Keys with a prefix are not affected.
+	if _, err := countDecryptAttempts(a, encryptWithKey(t, ks, 1, pt)); err != nil {
+		t.Errorf("a.Decrypt of ciphertext of TINK key failed: %s", err)
+	}
+	if got, err := countDecryptAttempts(a, encryptWithKey(t, ks, 3, pt)); err != nil || got != 2 {
+		t.Errorf("a.Decrypt of ciphertext of second RAW key = %d attempts, %v, want 2 attempts, nil", got, err)
+	}
+	got, err := countDecryptAttempts(a, encryptWithKey(t, ks, 4, pt))
+	if !errors.Is(err, aead.ErrDecryptionFailed) {
+		t.Errorf("a.Decrypt of ciphertext of third RAW key = %v, want aead.ErrDecryptionFailed", err)
+	}
+	if got != 2 {
+		t.Errorf("a.Decrypt of ciphertext of third RAW key took %d attempts, want 2", got)
+	}
+
+	// With WithMostRecentlyUsedFirst, the most recently used RAW keys are tried.
+	a, err = aead.New(kh, aead.WithMaxRawKeyAttempts(1), aead.WithMostRecentlyUsedFirst())
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	ct3 := encryptWithKey(t, ks, 3, pt)
+	if _, err := a.Decrypt(ct3, nil); err == nil {
+		t.Errorf("a.Decrypt of ciphertext of second RAW key succeeded, want error")
+	}
+}
+
+func TestNewWithRegistry(t *testing.T) {
+	manager := keyset.NewManager()
+	for _, template := range []*tinkpb.KeyTemplate{aead.AES128CTRHMACSHA256KeyTemplate(), aead.AES256GCMKeyTemplate()} {
+		if err := manager.Rotate(template); err != nil {
+			t.Fatalf("manager.Rotate() failed: %v", err)
+		}
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+
+	r, err := registry.NewFromGlobal(testutil.AESGCMTypeURL)
+	if err != nil {
+		t.Fatalf("registry.NewFromGlobal() failed: %v", err)
+	}
+	if _, err := aead.NewWithRegistry(h, r); err == nil {
+		t.Error("aead.NewWithRegistry() with a key type missing from the registry succeeded, want error")
+	}
+	if _, err := aead.NewWithRegistry(h, nil); err == nil {
+		t.Error("aead.NewWithRegistry() with a nil registry succeeded, want error")
+	}
+
+	km, err := registry.GetKeyManager(testutil.AESCTRHMACAEADTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() failed: %v", err)
+	}
+	if err := r.RegisterKeyManager(km); err != nil {
+		t.Fatalf("r.RegisterKeyManager() failed: %v", err)
+	}
+	a, err := aead.NewWithRegistry(h, r)
+	if err != nil {
+		t.Fatalf("aead.NewWithRegistry() failed: %v", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), []byte("ad"))
+	if err != nil {
+		t.Fatalf("a.Encrypt() failed: %v", err)
+	}
+	if pt, err := a.Decrypt(ct, []byte("ad")); err != nil || string(pt) != "plaintext" {
+		t.Errorf("a.Decrypt() = %q, %v, want %q", pt, err, "plaintext")
+	}
+}
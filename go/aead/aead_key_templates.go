@@ -21,6 +21,7 @@ import (
 	ctrpb "github.com/google/tink/go/proto/aes_ctr_go_proto"
 	ctrhmacpb "github.com/google/tink/go/proto/aes_ctr_hmac_aead_go_proto"
 	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	gcmsivpb "github.com/google/tink/go/proto/aes_gcm_siv_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
 	kmsenvpb "github.com/google/tink/go/proto/kms_envelope_go_proto"
@@ -51,6 +52,20 @@ func AES256GCMNoPrefixKeyTemplate() *tinkpb.KeyTemplate {
 	return createAESGCMKeyTemplate(32, tinkpb.OutputPrefixType_RAW)
 }
 
+// AES128GCMSIVKeyTemplate is a KeyTemplate that generates an AES-GCM-SIV key with the following parameters:
+//   - Key size: 16 bytes
+//   - Output prefix type: TINK
+func AES128GCMSIVKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESGCMSIVKeyTemplate(16, tinkpb.OutputPrefixType_TINK)
+}
+
+// AES256GCMSIVKeyTemplate is a KeyTemplate that generates an AES-GCM-SIV key with the following parameters:
+//   - Key size: 32 bytes
+//   - Output prefix type: TINK
+func AES256GCMSIVKeyTemplate() *tinkpb.KeyTemplate {
+	return createAESGCMSIVKeyTemplate(32, tinkpb.OutputPrefixType_TINK)
+}
+
 // AES128CTRHMACSHA256KeyTemplate is a KeyTemplate that generates an AES-CTR-HMAC-AEAD key with the following parameters:
 //  - AES key size: 16 bytes
 //  - AES CTR IV size: 16 bytes
@@ -89,6 +104,24 @@ func XChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
 	}
 }
 
+// XAES256GCMKeyTemplate is a KeyTemplate that generates a XAES_256_GCM key.
+func XAES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		// Don't set value because KeyFormat is not required.
+		TypeUrl:          xAES256GCMTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// Ascon128aKeyTemplate is a KeyTemplate that generates an ASCON128A key.
+func Ascon128aKeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		// Don't set value because KeyFormat is not required.
+		TypeUrl:          ascon128aTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
 // KMSEnvelopeAEADKeyTemplate is a KeyTemplate that generates a KMSEnvelopeAEAD key for
 // a given KEK in remote KMS. Keys generated by this key template uses RAW output prefix
 // to make them compatible with the remote KMS' encrypt/decrypt operations.
@@ -121,6 +154,20 @@ func createAESGCMKeyTemplate(keySize uint32, outputPrefixType tinkpb.OutputPrefi
 	}
 }
 
+// createAESGCMSIVKeyTemplate creates a new AES-GCM-SIV key template with the
+// given key size in bytes.
+func createAESGCMSIVKeyTemplate(keySize uint32, outputPrefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &gcmsivpb.AesGcmSivKeyFormat{
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesGCMSIVTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: outputPrefixType,
+	}
+}
+
 func createAESCTRHMACAEADKeyTemplate(aesKeySize, ivSize, hmacKeySize, tagSize uint32, hash commonpb.HashType) *tinkpb.KeyTemplate {
 	format := &ctrhmacpb.AesCtrHmacAeadKeyFormat{
 		AesCtrKeyFormat: &ctrpb.AesCtrKeyFormat{
@@ -139,3 +186,20 @@ func createAESCTRHMACAEADKeyTemplate(aesKeySize, ivSize, hmacKeySize, tagSize ui
 		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
 	}
 }
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"AES128_GCM":             AES128GCMKeyTemplate,
+	"AES256_GCM":             AES256GCMKeyTemplate,
+	"AES256_GCM_RAW":         AES256GCMNoPrefixKeyTemplate,
+	"AES128_GCM_SIV":         AES128GCMSIVKeyTemplate,
+	"AES256_GCM_SIV":         AES256GCMSIVKeyTemplate,
+	"AES128_CTR_HMAC_SHA256": AES128CTRHMACSHA256KeyTemplate,
+	"AES256_CTR_HMAC_SHA256": AES256CTRHMACSHA256KeyTemplate,
+	"CHACHA20_POLY1305":      ChaCha20Poly1305KeyTemplate,
+	"XCHACHA20_POLY1305":     XChaCha20Poly1305KeyTemplate,
+	"XAES_256_GCM":           XAES256GCMKeyTemplate,
+	"ASCON128A":              Ascon128aKeyTemplate,
+}
@@ -42,6 +42,12 @@ func TestKeyTemplates(t *testing.T) {
 		}, {
 			name:     "AES256_GCM",
 			template: aead.AES256GCMKeyTemplate(),
+		}, {
+			name:     "AES128_GCM_SIV",
+			template: aead.AES128GCMSIVKeyTemplate(),
+		}, {
+			name:     "AES256_GCM_SIV",
+			template: aead.AES256GCMSIVKeyTemplate(),
 		}, {
 			name:     "AES128_CTR_HMAC_SHA256",
 			template: aead.AES128CTRHMACSHA256KeyTemplate(),
@@ -54,6 +60,12 @@ func TestKeyTemplates(t *testing.T) {
 		}, {
 			name:     "XCHACHA20_POLY1305",
 			template: aead.XChaCha20Poly1305KeyTemplate(),
+		}, {
+			name:     "XAES_256_GCM",
+			template: aead.XAES256GCMKeyTemplate(),
+		}, {
+			name:     "ASCON128A",
+			template: aead.Ascon128aKeyTemplate(),
 		},
 	}
 	for _, tc := range testCases {
@@ -18,6 +18,7 @@ package aead
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead/subtle"
@@ -44,6 +45,9 @@ type aesGCMKeyManager struct{}
 // Assert that aesGCMKeyManager implements the KeyManager interface.
 var _ registry.KeyManager = (*aesGCMKeyManager)(nil)
 
+// Assert that aesGCMKeyManager implements the DerivableKeyManager interface.
+var _ registry.DerivableKeyManager = (*aesGCMKeyManager)(nil)
+
 // newAESGCMKeyManager creates a new aesGcmKeyManager.
 func newAESGCMKeyManager() *aesGCMKeyManager {
 	return new(aesGCMKeyManager)
@@ -87,6 +91,30 @@ func (km *aesGCMKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, e
 	}, nil
 }
 
+// DeriveKey derives a new key according to specification in the given
+// serialized AESGCMKeyFormat, using the bytes read from pseudorandomness as
+// key material.
+func (km *aesGCMKeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESGCMKeyFormat
+	}
+	keyFormat := new(gcmpb.AesGcmKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESGCMKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_gcm_key_manager: invalid key format: %s", err)
+	}
+	keyValue := make([]byte, keyFormat.KeySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("aes_gcm_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &gcmpb.AesGcmKey{
+		Version:  aesGCMKeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
 // NewKeyData creates a new KeyData according to specification in the given serialized
 // AESGCMKeyFormat.
 // It should be used solely by the key management API.
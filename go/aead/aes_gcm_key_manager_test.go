@@ -271,3 +271,39 @@ func validateAESGCMPrimitive(p interface{}, key *gcmpb.AesGcmKey) error {
 	}
 	return nil
 }
+
+func TestAESGCMDeriveKey(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESGCMTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-GCM key manager: %s", err)
+	}
+	keyManager, ok := km.(registry.DerivableKeyManager)
+	if !ok {
+		t.Fatal("AES-GCM key manager is not a DerivableKeyManager")
+	}
+	pseudorandomness := random.GetRandomBytes(64)
+	for _, keySize := range keySizes {
+		serializedFormat, err := proto.Marshal(testutil.NewAESGCMKeyFormat(keySize))
+		if err != nil {
+			t.Fatalf("failed to marshal key format: %s", err)
+		}
+		m, err := keyManager.DeriveKey(serializedFormat, bytes.NewReader(pseudorandomness))
+		if err != nil {
+			t.Fatalf("DeriveKey() err = %v", err)
+		}
+		key := m.(*gcmpb.AesGcmKey)
+		if !bytes.Equal(key.KeyValue, pseudorandomness[:keySize]) {
+			t.Errorf("derived key = %x, want %x", key.KeyValue, pseudorandomness[:keySize])
+		}
+		if _, err := keyManager.DeriveKey(serializedFormat, bytes.NewReader(pseudorandomness[:keySize-1])); err == nil {
+			t.Error("DeriveKey() with too little pseudorandomness succeeded")
+		}
+	}
+	serializedFormat, err := proto.Marshal(testutil.NewAESGCMKeyFormat(17))
+	if err != nil {
+		t.Fatalf("failed to marshal key format: %s", err)
+	}
+	if _, err := keyManager.DeriveKey(serializedFormat, bytes.NewReader(pseudorandomness)); err == nil {
+		t.Error("DeriveKey() with invalid key size succeeded")
+	}
+}
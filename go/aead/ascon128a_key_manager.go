@@ -0,0 +1,122 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+
+	asconpb "github.com/google/tink/go/proto/ascon128a_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	ascon128aKeyVersion = 0
+	ascon128aTypeURL    = "type.googleapis.com/google.crypto.tink.Ascon128aKey"
+)
+
+// Common errors.
+var errInvalidAscon128aKey = fmt.Errorf("ascon128a_key_manager: invalid key")
+
+// ascon128aKeyManager is an implementation of KeyManager interface.
+// It generates new Ascon128aKey keys and produces new instances of Ascon128a subtle.
+type ascon128aKeyManager struct{}
+
+// Assert that ascon128aKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*ascon128aKeyManager)(nil)
+
+// newAscon128aKeyManager creates a new ascon128aKeyManager.
+func newAscon128aKeyManager() *ascon128aKeyManager {
+	return new(ascon128aKeyManager)
+}
+
+// Primitive creates an Ascon128a subtle for the given serialized Ascon128aKey proto.
+func (km *ascon128aKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAscon128aKey
+	}
+	key := new(asconpb.Ascon128AKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAscon128aKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewAscon128a(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("ascon128a_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key, ignoring the specification in the given serialized key format
+// because the key size and other params are fixed.
+func (km *ascon128aKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return km.newAscon128aKey(), nil
+}
+
+// NewKeyData creates a new KeyData, ignoring the specification in the given serialized key format
+// because the key size and other params are fixed.
+// It should be used solely by the key management API.
+func (km *ascon128aKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key := km.newAscon128aKey()
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         ascon128aTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *ascon128aKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == ascon128aTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *ascon128aKeyManager) TypeURL() string {
+	return ascon128aTypeURL
+}
+
+func (km *ascon128aKeyManager) newAscon128aKey() *asconpb.Ascon128AKey {
+	keyValue := random.GetRandomBytes(subtle.Ascon128aKeySize)
+	return &asconpb.Ascon128AKey{
+		Version:  ascon128aKeyVersion,
+		KeyValue: keyValue,
+	}
+}
+
+// validateKey validates the given Ascon128aKey.
+func (km *ascon128aKeyManager) validateKey(key *asconpb.Ascon128AKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, ascon128aKeyVersion)
+	if err != nil {
+		return fmt.Errorf("ascon128a_key_manager: %s", err)
+	}
+	keySize := uint32(len(key.KeyValue))
+	if keySize != subtle.Ascon128aKeySize {
+		return fmt.Errorf("ascon128a_key_manager: keySize != %d", subtle.Ascon128aKeySize)
+	}
+	return nil
+}
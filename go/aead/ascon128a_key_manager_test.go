@@ -0,0 +1,187 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+
+	"github.com/google/tink/go/aead/subtle"
+	asconpb "github.com/google/tink/go/proto/ascon128a_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestAscon128aGetPrimitive(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	m, _ := km.NewKey(nil)
+	key, _ := m.(*asconpb.Ascon128AKey)
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Errorf("km.Primitive(%v) = %v; want nil", serializedKey, err)
+	}
+	if err := validateAscon128aPrimitive(p, key); err != nil {
+		t.Errorf("validateAscon128aPrimitive(p, key) = %v; want nil", err)
+	}
+}
+
+func TestAscon128aGetPrimitiveWithInvalidKeys(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	invalidKeys := genInvalidAscon128aKeys()
+	for _, key := range invalidKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("km.Primitive(%v) = _, nil; want _, err", serializedKey)
+		}
+	}
+}
+
+func TestAscon128aNewKey(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	m, err := km.NewKey(nil)
+	if err != nil {
+		t.Errorf("km.NewKey(nil) = _, %v; want _, nil", err)
+	}
+	key, _ := m.(*asconpb.Ascon128AKey)
+	if err := validateAscon128aKey(key); err != nil {
+		t.Errorf("validateAscon128aKey(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestAscon128aNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	kd, err := km.NewKeyData(nil)
+	if err != nil {
+		t.Errorf("km.NewKeyData(nil) = _, %v; want _, nil", err)
+	}
+	if kd.TypeUrl != testutil.Ascon128aTypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, testutil.Ascon128aTypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+	key := new(asconpb.Ascon128AKey)
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		t.Errorf("proto.Unmarshal(%v, key) = %v; want nil", kd.Value, err)
+	}
+	if err := validateAscon128aKey(key); err != nil {
+		t.Errorf("validateAscon128aKey(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestAscon128aDoesSupport(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	if !km.DoesSupport(testutil.Ascon128aTypeURL) {
+		t.Errorf("Ascon128aKeyManager must support %s", testutil.Ascon128aTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("Ascon128aKeyManager must only support %s", testutil.Ascon128aTypeURL)
+	}
+}
+
+func TestAscon128aTypeURL(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Ascon128aTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain Ascon128a key manager: %s", err)
+	}
+	if kt := km.TypeURL(); kt != testutil.Ascon128aTypeURL {
+		t.Errorf("km.TypeURL() = %s; want %s", kt, testutil.Ascon128aTypeURL)
+	}
+}
+
+func genInvalidAscon128aKeys() []*asconpb.Ascon128AKey {
+	return []*asconpb.Ascon128AKey{
+		// Bad key size.
+		&asconpb.Ascon128AKey{
+			Version:  testutil.Ascon128aKeyVersion,
+			KeyValue: random.GetRandomBytes(15),
+		},
+		&asconpb.Ascon128AKey{
+			Version:  testutil.Ascon128aKeyVersion,
+			KeyValue: random.GetRandomBytes(17),
+		},
+		&asconpb.Ascon128AKey{
+			Version:  testutil.Ascon128aKeyVersion,
+			KeyValue: random.GetRandomBytes(32),
+		},
+		// Bad version.
+		&asconpb.Ascon128AKey{
+			Version:  testutil.Ascon128aKeyVersion + 1,
+			KeyValue: random.GetRandomBytes(subtle.Ascon128aKeySize),
+		},
+	}
+}
+
+func validateAscon128aPrimitive(p interface{}, key *asconpb.Ascon128AKey) error {
+	cipher := p.(*subtle.Ascon128a)
+	if !bytes.Equal(cipher.Key, key.KeyValue) {
+		return fmt.Errorf("key and primitive don't match")
+	}
+
+	// Try to encrypt and decrypt.
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil {
+		return fmt.Errorf("decryption failed")
+	}
+	if !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
+
+func validateAscon128aKey(key *asconpb.Ascon128AKey) error {
+	if key.Version != testutil.Ascon128aKeyVersion {
+		return fmt.Errorf("incorrect key version: keyVersion != %d", testutil.Ascon128aKeyVersion)
+	}
+	if uint32(len(key.KeyValue)) != subtle.Ascon128aKeySize {
+		return fmt.Errorf("incorrect key size: keySize != %d", subtle.Ascon128aKeySize)
+	}
+
+	// Try to encrypt and decrypt.
+	p, err := subtle.NewAscon128a(key.KeyValue)
+	if err != nil {
+		return fmt.Errorf("invalid key: %v", key.KeyValue)
+	}
+	return validateAscon128aPrimitive(p, key)
+}
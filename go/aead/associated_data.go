@@ -0,0 +1,89 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import "encoding/binary"
+
+// Type tags of the fields of AssociatedData.
+const (
+	adTagBytes  = 1
+	adTagString = 2
+	adTagInt    = 3
+	adTagUint   = 4
+)
+
+// AssociatedData builds associated data for Encrypt and Decrypt from a sequence
+// of typed fields. Concatenating fields by hand is ambiguous: "ab" || "c" and
+// "a" || "bc" result in the same associated data, so a ciphertext bound to one
+// context also decrypts in the other. AssociatedData encodes each field with
+// its type and length, so that different sequences of fields always result in
+// different associated data.
+//
+// Each field is encoded as a one-byte type tag followed by the value:
+//
+//   - byte slices (tag 1) and strings (tag 2) as the length as 8-byte big-endian
+//     integer followed by the data,
+//   - signed integers (tag 3) as 8-byte big-endian two's complement,
+//   - unsigned integers (tag 4) as 8-byte big-endian integer.
+//
+// The zero value is an empty sequence of fields and ready to use.
+type AssociatedData struct {
+	buf []byte
+}
+
+// NewAssociatedData returns an empty AssociatedData.
+func NewAssociatedData() *AssociatedData {
+	return new(AssociatedData)
+}
+
+// AddBytes appends a byte slice field and returns ad.
+func (ad *AssociatedData) AddBytes(b []byte) *AssociatedData {
+	ad.addUint64(adTagBytes, uint64(len(b)))
+	ad.buf = append(ad.buf, b...)
+	return ad
+}
+
+// AddString appends a string field and returns ad.
+func (ad *AssociatedData) AddString(s string) *AssociatedData {
+	ad.addUint64(adTagString, uint64(len(s)))
+	ad.buf = append(ad.buf, s...)
+	return ad
+}
+
+// AddInt appends a signed integer field and returns ad.
+func (ad *AssociatedData) AddInt(i int64) *AssociatedData {
+	return ad.addUint64(adTagInt, uint64(i))
+}
+
+// AddUint appends an unsigned integer field and returns ad.
+func (ad *AssociatedData) AddUint(u uint64) *AssociatedData {
+	return ad.addUint64(adTagUint, u)
+}
+
+// Bytes returns the encoding of the fields added so far. It returns an empty
+// slice if no field has been added.
+func (ad *AssociatedData) Bytes() []byte {
+	return append([]byte{}, ad.buf...)
+}
+
+func (ad *AssociatedData) addUint64(tag byte, v uint64) *AssociatedData {
+	var b [9]byte
+	b[0] = tag
+	binary.BigEndian.PutUint64(b[1:], v)
+	ad.buf = append(ad.buf, b[:]...)
+	return ad
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+)
+
+func TestAssociatedDataEncoding(t *testing.T) {
+	got := aead.NewAssociatedData().
+		AddString("ab").
+		AddBytes([]byte{0xff}).
+		AddInt(-2).
+		AddUint(258).
+		Bytes()
+	want, _ := hex.DecodeString("02" + "0000000000000002" + "6162" +
+		"01" + "0000000000000001" + "ff" +
+		"03" + "fffffffffffffffe" +
+		"04" + "0000000000000102")
+	if !bytes.Equal(got, want) {
+		t.Errorf("Bytes() = %x, want %x", got, want)
+	}
+	var empty aead.AssociatedData
+	if got := empty.Bytes(); len(got) != 0 {
+		t.Errorf("Bytes() of zero value = %x, want empty", got)
+	}
+}
+
+func TestAssociatedDataIsUnambiguous(t *testing.T) {
+	encodings := []*aead.AssociatedData{
+		aead.NewAssociatedData(),
+		aead.NewAssociatedData().AddString(""),
+		aead.NewAssociatedData().AddBytes(nil),
+		aead.NewAssociatedData().AddString("").AddString(""),
+		aead.NewAssociatedData().AddString("abc"),
+		aead.NewAssociatedData().AddString("ab").AddString("c"),
+		aead.NewAssociatedData().AddString("a").AddString("bc"),
+		aead.NewAssociatedData().AddBytes([]byte("abc")),
+		aead.NewAssociatedData().AddInt(1),
+		aead.NewAssociatedData().AddUint(1),
+		aead.NewAssociatedData().AddInt(-1),
+		aead.NewAssociatedData().AddUint(1).AddUint(2),
+		aead.NewAssociatedData().AddUint(2).AddUint(1),
+	}
+	seen := make(map[string]int)
+	for i, ad := range encodings {
+		b := string(ad.Bytes())
+		if j, ok := seen[b]; ok {
+			t.Errorf("fields %d and %d have the same encoding %x", j, i, b)
+		}
+		seen[b] = i
+	}
+}
+
+func TestAssociatedDataWithAEAD(t *testing.T) {
+	kh, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	a, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	ad := aead.NewAssociatedData().AddString("users").AddInt(42)
+	ct, err := a.Encrypt([]byte("secret"), ad.Bytes())
+	if err != nil {
+		t.Fatalf("a.Encrypt failed: %s", err)
+	}
+	if _, err := a.Decrypt(ct, aead.NewAssociatedData().AddString("users").AddInt(42).Bytes()); err != nil {
+		t.Errorf("a.Decrypt with the same fields failed: %s", err)
+	}
+	if _, err := a.Decrypt(ct, aead.NewAssociatedData().AddString("users").AddInt(43).Bytes()); err == nil {
+		t.Errorf("a.Decrypt with different fields succeeded, want error")
+	}
+}
+
+func TestAssociatedDataBytesIsACopy(t *testing.T) {
+	ad := aead.NewAssociatedData().AddString("a")
+	b := ad.Bytes()
+	b[0] ^= 1
+	ad.AddString("b")
+	if bytes.Equal(ad.Bytes()[:len(b)], b) {
+		t.Errorf("modifying the result of Bytes() modified the builder")
+	}
+}
@@ -18,6 +18,7 @@ package aead
 
 import (
 	"fmt"
+	"io"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"github.com/golang/protobuf/proto"
@@ -72,6 +73,19 @@ func (km *chaCha20Poly1305KeyManager) NewKey(serializedKeyFormat []byte) (proto.
 	return km.newChaCha20Poly1305Key(), nil
 }
 
+// DeriveKey derives a new ChaCha20Poly1305Key, using the bytes read from
+// pseudorandomness as key material. serializedKeyFormat is ignored.
+func (km *chaCha20Poly1305KeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	keyValue := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("chacha20poly1305_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &cppb.ChaCha20Poly1305Key{
+		Version:  chaCha20Poly1305KeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
 // NewKeyData creates a new KeyData ignoring the specification in the given serialized key format
 // because the key size and other params are fixed.
 // It should be used solely by the key management API.
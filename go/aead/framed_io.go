@@ -0,0 +1,259 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// framePlaintextSize is the maximal size of the plaintext of a frame.
+	framePlaintextSize = 64 << 10
+	// maxFrameOverhead bounds the ciphertext overhead of the AEAD that is
+	// accepted by the reader, so that a corrupted length cannot make it
+	// allocate large buffers.
+	maxFrameOverhead = 4 << 10
+	// frameHeaderSize is the size of the header of a frame, which consists of
+	// the size of the ciphertext as 4-byte big-endian integer and a flag that
+	// marks the last frame.
+	frameHeaderSize = 5
+	// streamIDSize is the size of the random ID at the start of a stream.
+	streamIDSize = 16
+
+	frameFlagMore = 0
+	frameFlagLast = 1
+)
+
+var (
+	errWriterClosed    = errors.New("aead_io: write to closed writer")
+	errFrameTruncated  = errors.New("aead_io: stream is truncated")
+	errFrameInvalid    = errors.New("aead_io: invalid frame")
+	errFrameTrailing   = errors.New("aead_io: data after last frame")
+	errFrameDecryption = errors.New("aead_io: frame authentication failed")
+)
+
+// NewEncryptingWriter returns a writer that encrypts data with the given AEAD
+// and writes it to w. The data is split into frames of up to 64 KiB, which are
+// encrypted independently and written with a length prefix. Each frame is bound
+// to aad, to its position and to a random ID of the stream, so that frames
+// cannot be reordered, dropped or moved between streams without detection.
+//
+// This is meant for data that needs to be passed through io plumbing, not for
+// large files that must be decrypted piecewise with random access; use
+// streaming AEAD for those. Close must be called to write the last frame; it
+// does not close w.
+func NewEncryptingWriter(a tink.AEAD, w io.Writer, aad []byte) (io.WriteCloser, error) {
+	if a == nil || w == nil {
+		return nil, errors.New("aead_io: AEAD and writer must not be nil")
+	}
+	return &encryptingWriter{
+		a:   a,
+		w:   w,
+		aad: append([]byte{}, aad...),
+		id:  random.GetRandomBytes(streamIDSize),
+		buf: make([]byte, 0, framePlaintextSize),
+	}, nil
+}
+
+// NewDecryptingReader returns a reader that decrypts the data written by a
+// writer from NewEncryptingWriter with the same AEAD and aad from r. Read
+// returns an error if the data has been modified or truncated; plaintext is
+// only returned after its frame has been authenticated.
+func NewDecryptingReader(a tink.AEAD, r io.Reader, aad []byte) (io.Reader, error) {
+	if a == nil || r == nil {
+		return nil, errors.New("aead_io: AEAD and reader must not be nil")
+	}
+	return &decryptingReader{
+		a:   a,
+		r:   r,
+		aad: append([]byte{}, aad...),
+	}, nil
+}
+
+// frameAD returns the additional data of the frame with the given index and
+// flag of the stream with the given ID.
+func frameAD(aad, id []byte, index uint64, flag byte) []byte {
+	ad := make([]byte, 0, len(aad)+streamIDSize+9)
+	ad = append(ad, aad...)
+	ad = append(ad, id...)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], index)
+	ad = append(ad, b[:]...)
+	return append(ad, flag)
+}
+
+type encryptingWriter struct {
+	a   tink.AEAD
+	w   io.Writer
+	aad []byte
+	id  []byte
+
+	index  uint64
+	buf    []byte
+	closed bool
+	err    error
+}
+
+// Write buffers p and writes all full frames but the last one, which is kept
+// until more data is written or the writer is closed.
+func (w *encryptingWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(w.buf) == framePlaintextSize {
+			if err := w.writeFrame(frameFlagMore); err != nil {
+				return n, err
+			}
+		}
+		m := copy(w.buf[len(w.buf):framePlaintextSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Close writes the last frame. It does not close the underlying writer.
+func (w *encryptingWriter) Close() error {
+	if w.closed {
+		return errWriterClosed
+	}
+	w.closed = true
+	if w.err != nil {
+		return w.err
+	}
+	return w.writeFrame(frameFlagLast)
+}
+
+func (w *encryptingWriter) writeFrame(flag byte) error {
+	var out []byte
+	if w.index == 0 {
+		out = append(out, w.id...)
+	}
+	ct, err := w.a.Encrypt(w.buf, frameAD(w.aad, w.id, w.index, flag))
+	if err != nil {
+		w.err = fmt.Errorf("aead_io: %s", err)
+		return w.err
+	}
+	var header [frameHeaderSize]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(ct)))
+	header[4] = flag
+	out = append(out, header[:]...)
+	out = append(out, ct...)
+	if _, err := w.w.Write(out); err != nil {
+		w.err = err
+		return err
+	}
+	w.index++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+type decryptingReader struct {
+	a   tink.AEAD
+	r   io.Reader
+	aad []byte
+	id  []byte
+
+	index   uint64
+	pending []byte
+	done    bool
+	err     error
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			r.err = r.checkEOF()
+			continue
+		}
+		r.pending, r.err = r.readFrame()
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// readFrame reads and decrypts the next frame.
+func (r *decryptingReader) readFrame() ([]byte, error) {
+	if r.id == nil {
+		id := make([]byte, streamIDSize)
+		if _, err := io.ReadFull(r.r, id); err != nil {
+			return nil, readError(err)
+		}
+		r.id = id
+	}
+	var header [frameHeaderSize]byte
+	if _, err := io.ReadFull(r.r, header[:]); err != nil {
+		return nil, readError(err)
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	flag := header[4]
+	if size > framePlaintextSize+maxFrameOverhead || (flag != frameFlagMore && flag != frameFlagLast) {
+		return nil, errFrameInvalid
+	}
+	ct := make([]byte, size)
+	if _, err := io.ReadFull(r.r, ct); err != nil {
+		return nil, readError(err)
+	}
+	pt, err := r.a.Decrypt(ct, frameAD(r.aad, r.id, r.index, flag))
+	if err != nil {
+		return nil, errFrameDecryption
+	}
+	r.index++
+	r.done = flag == frameFlagLast
+	return pt, nil
+}
+
+// checkEOF returns io.EOF if there is no data after the last frame.
+func (r *decryptingReader) checkEOF() error {
+	var b [1]byte
+	n, err := io.ReadFull(r.r, b[:])
+	if n > 0 {
+		return errFrameTrailing
+	}
+	if err == io.EOF {
+		return io.EOF
+	}
+	return err
+}
+
+// readError maps the end of the stream before the last frame to
+// errFrameTruncated.
+func readError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errFrameTruncated
+	}
+	return err
+}
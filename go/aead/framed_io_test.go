@@ -0,0 +1,203 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+const testFrameSize = 64 << 10
+
+func newFramedIOAEAD(t *testing.T) tink.AEAD {
+	t.Helper()
+	kh, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	a, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("aead.New failed: %s", err)
+	}
+	return a
+}
+
+func encryptFramed(t *testing.T, a tink.AEAD, pt, aad []byte, writeSize int) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w, err := aead.NewEncryptingWriter(a, buf, aad)
+	if err != nil {
+		t.Fatalf("aead.NewEncryptingWriter failed: %s", err)
+	}
+	for p := pt; len(p) > 0; {
+		n := writeSize
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := w.Write(p[:n]); err != nil {
+			t.Fatalf("w.Write failed: %s", err)
+		}
+		p = p[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close failed: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func decryptFramed(a tink.AEAD, ct, aad []byte) ([]byte, error) {
+	r, err := aead.NewDecryptingReader(a, bytes.NewReader(ct), aad)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+// splitFrames splits an encrypted stream into its ID and frames.
+func splitFrames(t *testing.T, ct []byte) ([]byte, [][]byte) {
+	t.Helper()
+	id, rest := ct[:16], ct[16:]
+	var frames [][]byte
+	for len(rest) > 0 {
+		size := int(binary.BigEndian.Uint32(rest)) + 5
+		frames = append(frames, rest[:size])
+		rest = rest[size:]
+	}
+	return id, frames
+}
+
+func TestFramedIORoundtrip(t *testing.T) {
+	a := newFramedIOAEAD(t)
+	aad := []byte("aad")
+	for _, size := range []int{0, 1, 100, testFrameSize, testFrameSize + 1, 3*testFrameSize + 5} {
+		pt := random.GetRandomBytes(uint32(size))
+		for _, writeSize := range []int{1000, testFrameSize, 1 << 20} {
+			ct := encryptFramed(t, a, pt, aad, writeSize)
+			got, err := decryptFramed(a, ct, aad)
+			if err != nil {
+				t.Fatalf("size %d, write size %d: decryption failed: %s", size, writeSize, err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("size %d, write size %d: decrypted data differs from plaintext", size, writeSize)
+			}
+		}
+	}
+}
+
+func TestFramedIOSmallReads(t *testing.T) {
+	a := newFramedIOAEAD(t)
+	pt := random.GetRandomBytes(testFrameSize + 10)
+	ct := encryptFramed(t, a, pt, nil, 1<<20)
+	r, err := aead.NewDecryptingReader(a, bytes.NewReader(ct), nil)
+	if err != nil {
+		t.Fatalf("aead.NewDecryptingReader failed: %s", err)
+	}
+	got := new(bytes.Buffer)
+	buf := make([]byte, 7)
+	for {
+		n, err := r.Read(buf)
+		got.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("r.Read failed: %s", err)
+		}
+	}
+	if !bytes.Equal(got.Bytes(), pt) {
+		t.Errorf("decrypted data differs from plaintext")
+	}
+}
+
+func TestFramedIOModifications(t *testing.T) {
+	a := newFramedIOAEAD(t)
+	aad := []byte("aad")
+	pt := random.GetRandomBytes(3 * testFrameSize)
+	ct := encryptFramed(t, a, pt, aad, 1<<20)
+	id, frames := splitFrames(t, ct)
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+	other := encryptFramed(t, a, pt, aad, 1<<20)
+	_, otherFrames := splitFrames(t, other)
+
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+	flipped := append([]byte{}, ct...)
+	flipped[len(flipped)-1] ^= 1
+	for _, test := range []struct {
+		name string
+		ct   []byte
+		aad  []byte
+	}{
+		{"wrong aad", ct, []byte("other aad")},
+		{"modified", flipped, aad},
+		{"empty", nil, aad},
+		{"only ID", id, aad},
+		{"truncated frame", ct[:len(ct)-1], aad},
+		{"last frame dropped", join(id, frames[0], frames[1]), aad},
+		{"frame dropped", join(id, frames[0], frames[2]), aad},
+		{"frames reordered", join(id, frames[1], frames[0], frames[2]), aad},
+		{"frame from other stream", join(id, frames[0], otherFrames[1], frames[2]), aad},
+		{"trailing data", join(ct, []byte{0}), aad},
+		{"invalid length", join(id, []byte{0xff, 0xff, 0xff, 0xff, 0}, frames[0][5:]), aad},
+	} {
+		if got, err := decryptFramed(a, test.ct, test.aad); err == nil {
+			t.Errorf("%s: decryption succeeded with %d bytes, want error", test.name, len(got))
+		}
+	}
+}
+
+func TestEncryptingWriterClose(t *testing.T) {
+	a := newFramedIOAEAD(t)
+	w, err := aead.NewEncryptingWriter(a, ioutil.Discard, nil)
+	if err != nil {
+		t.Fatalf("aead.NewEncryptingWriter failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close failed: %s", err)
+	}
+	if _, err := w.Write([]byte("data")); err == nil {
+		t.Errorf("w.Write after Close succeeded, want error")
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("second w.Close succeeded, want error")
+	}
+}
+
+func TestFramedIONilArguments(t *testing.T) {
+	a := newFramedIOAEAD(t)
+	if _, err := aead.NewEncryptingWriter(nil, ioutil.Discard, nil); err == nil {
+		t.Errorf("aead.NewEncryptingWriter with nil AEAD succeeded, want error")
+	}
+	if _, err := aead.NewEncryptingWriter(a, nil, nil); err == nil {
+		t.Errorf("aead.NewEncryptingWriter with nil writer succeeded, want error")
+	}
+	if _, err := aead.NewDecryptingReader(nil, bytes.NewReader(nil), nil); err == nil {
+		t.Errorf("aead.NewDecryptingReader with nil AEAD succeeded, want error")
+	}
+	if _, err := aead.NewDecryptingReader(a, nil, nil); err == nil {
+		t.Errorf("aead.NewDecryptingReader with nil reader succeeded, want error")
+	}
+}
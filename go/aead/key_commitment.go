@@ -0,0 +1,186 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/internal"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	// keyCommitmentSaltSize is the size of the random salt of a key
+	// commitment.
+	keyCommitmentSaltSize = 32
+	// keyCommitmentTagSize is the size of the tag of a key commitment.
+	keyCommitmentTagSize = 32
+	// keyCommitmentSize is the size of the key commitment in ciphertexts
+	// produced by NewWithKeyCommitment.
+	keyCommitmentSize = keyCommitmentSaltSize + keyCommitmentTagSize
+
+	keyCommitmentLabel = "tink aead key commitment"
+)
+
+var keysetMaterial = internal.KeysetMaterial.(func(*keyset.Handle) *tinkpb.Keyset)
+
+// NewWithKeyCommitment returns an AEAD primitive from the given keyset handle
+// whose ciphertexts commit to the key that produced them.
+//
+// AEADs such as AES-GCM and ChaCha20-Poly1305 are not key-committing: an
+// attacker who knows several keys can craft a single ciphertext that decrypts
+// under all of them, which enables partitioning oracle attacks against systems
+// that try many keys. The returned primitive prepends a commitment to the key
+// to every ciphertext, and only decrypts a ciphertext with the key whose
+// commitment it contains. The commitment is a fresh random salt followed by
+// HKDF-SHA256 of the key material with that salt, so it neither reveals the
+// key nor links ciphertexts of the same key to each other.
+//
+// The ciphertext format is: output prefix || salt (32 bytes) || tag (32 bytes)
+// || ciphertext of the underlying AEAD. It is not compatible with the primitive
+// returned by New. All enabled keys must hold their key material locally;
+// keys held remotely, such as KMS envelope keys, are rejected.
+func NewWithKeyCommitment(h *keyset.Handle) (tink.AEAD, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("aead_factory: cannot obtain primitive set: %s", err)
+	}
+	// Reuse the validation of the primitive set.
+	if _, err := newWrappedAead(ps); err != nil {
+		return nil, err
+	}
+	keys := make(map[uint32]*tinkpb.KeyData)
+	for _, key := range keysetMaterial(h).Key {
+		if key.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+		if key.GetKeyData().GetKeyMaterialType() != tinkpb.KeyData_SYMMETRIC {
+			return nil, fmt.Errorf("aead_factory: key %d does not hold symmetric key material and cannot be committed to", key.KeyId)
+		}
+		keys[key.KeyId] = key.KeyData
+	}
+	return &keyCommittedAead{ps: ps, keys: keys}, nil
+}
+
+// keyCommitmentTag derives the tag of a commitment to the given key data from
+// the salt of the commitment.
+func keyCommitmentTag(kd *tinkpb.KeyData, salt []byte) ([]byte, error) {
+	info := append([]byte(keyCommitmentLabel+"\x00"), kd.TypeUrl...)
+	return subtle.ComputeHKDF("SHA256", kd.Value, salt, info, keyCommitmentTagSize)
+}
+
+// keyCommittedAead is an AEAD implementation that uses the underlying primitive
+// set for encryption and decryption, and binds ciphertexts to their key.
+type keyCommittedAead struct {
+	ps *primitiveset.PrimitiveSet
+	// keys maps key IDs to the key data that is committed to.
+	keys map[uint32]*tinkpb.KeyData
+}
+
+// Encrypt encrypts the given plaintext with the given additional authenticated
+// data. It returns the concatenation of the primary's identifier, the
+// commitment to the primary key and the ciphertext.
+func (a *keyCommittedAead) Encrypt(pt, ad []byte) ([]byte, error) {
+	primary := a.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
+	p, ok := (primary.Primitive).(tink.AEAD)
+	if !ok {
+		return nil, fmt.Errorf("aead_factory: not an AEAD primitive")
+	}
+
+	kd, ok := a.keys[primary.KeyID]
+	if !ok {
+		return nil, errors.New("aead_factory: no key material for the primary key")
+	}
+	salt := random.GetRandomBytes(keyCommitmentSaltSize)
+	tag, err := keyCommitmentTag(kd, salt)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := p.Encrypt(pt, ad)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]byte, 0, len(primary.Prefix)+keyCommitmentSize+len(ct))
+	ret = append(ret, primary.Prefix...)
+	ret = append(ret, salt...)
+	ret = append(ret, tag...)
+	return append(ret, ct...), nil
+}
+
+// Decrypt decrypts the given ciphertext and authenticates it with the given
+// additional authenticated data, using only the key that the ciphertext
+// commits to. It returns the corresponding plaintext if the ciphertext is
+// authenticated.
+func (a *keyCommittedAead) Decrypt(ct, ad []byte) ([]byte, error) {
+	// try non-raw keys
+	prefixSize := cryptofmt.NonRawPrefixSize
+	if len(ct) > prefixSize+keyCommitmentSize {
+		entries, err := a.ps.EntriesForPrefix(string(ct[:prefixSize]))
+		if err == nil {
+			if pt, ok := a.decryptCommitted(entries, ct[prefixSize:], ad); ok {
+				return pt, nil
+			}
+		}
+	}
+	// try raw keys
+	if len(ct) > keyCommitmentSize {
+		entries, err := a.ps.RawEntries()
+		if err == nil {
+			if pt, ok := a.decryptCommitted(entries, ct, ad); ok {
+				return pt, nil
+			}
+		}
+	}
+	// nothing worked
+	return nil, ErrDecryptionFailed
+}
+
+// decryptCommitted decrypts ct, which starts with a key commitment, with the
+// entries whose key matches the commitment.
+func (a *keyCommittedAead) decryptCommitted(entries []*primitiveset.Entry, ct, ad []byte) ([]byte, bool) {
+	salt := ct[:keyCommitmentSaltSize]
+	tag := ct[keyCommitmentSaltSize:keyCommitmentSize]
+	for _, e := range entries {
+		kd, ok := a.keys[e.KeyID]
+		if !ok {
+			continue
+		}
+		want, err := keyCommitmentTag(kd, salt)
+		if err != nil || !hmac.Equal(tag, want) {
+			continue
+		}
+		p, ok := (e.Primitive).(tink.AEAD)
+		if !ok {
+			continue
+		}
+		if pt, err := p.Decrypt(ct[keyCommitmentSize:], ad); err == nil {
+			return pt, true
+		}
+	}
+	return nil, false
+}
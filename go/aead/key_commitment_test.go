@@ -0,0 +1,208 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/testing/fakekms"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestKeyCommitmentEncryptDecrypt(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		aead.AES128GCMKeyTemplate(),
+		aead.AES256GCMNoPrefixKeyTemplate(),
+		aead.ChaCha20Poly1305KeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		a, err := aead.NewWithKeyCommitment(kh)
+		if err != nil {
+			t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+		}
+		pt := []byte("plaintext")
+		ad := []byte("ad")
+		ct, err := a.Encrypt(pt, ad)
+		if err != nil {
+			t.Fatalf("a.Encrypt failed: %s", err)
+		}
+		decrypted, err := a.Decrypt(ct, ad)
+		if err != nil {
+			t.Fatalf("a.Decrypt failed: %s", err)
+		}
+		if !bytes.Equal(decrypted, pt) {
+			t.Errorf("a.Decrypt = %q, want %q", decrypted, pt)
+		}
+		if _, err := a.Decrypt(ct, []byte("other ad")); !errors.Is(err, aead.ErrDecryptionFailed) {
+			t.Errorf("a.Decrypt with wrong ad = %v, want aead.ErrDecryptionFailed", err)
+		}
+
+		// Ciphertexts are not compatible with those of aead.New.
+		plain, err := aead.New(kh)
+		if err != nil {
+			t.Fatalf("aead.New failed: %s", err)
+		}
+		plainCT, err := plain.Encrypt(pt, ad)
+		if err != nil {
+			t.Fatalf("plain.Encrypt failed: %s", err)
+		}
+		if len(ct) != len(plainCT)+64 {
+			t.Errorf("ciphertext has size %d, want %d", len(ct), len(plainCT)+64)
+		}
+		if _, err := a.Decrypt(plainCT, ad); err == nil {
+			t.Errorf("a.Decrypt succeeded on a ciphertext without commitment")
+		}
+		if _, err := a.Decrypt(ct[:64], ad); err == nil {
+			t.Errorf("a.Decrypt succeeded on a truncated ciphertext")
+		}
+	}
+}
+
+func TestKeyCommitmentAfterRotation(t *testing.T) {
+	manager := keyset.NewManager()
+	if err := manager.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	oldHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+	oldAEAD, err := aead.NewWithKeyCommitment(oldHandle)
+	if err != nil {
+		t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+	}
+	ct, err := oldAEAD.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("oldAEAD.Encrypt failed: %s", err)
+	}
+	if err := manager.Rotate(aead.AES256GCMKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	newHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+	newAEAD, err := aead.NewWithKeyCommitment(newHandle)
+	if err != nil {
+		t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+	}
+	if _, err := newAEAD.Decrypt(ct, nil); err != nil {
+		t.Errorf("newAEAD.Decrypt failed on a ciphertext of the old primary: %s", err)
+	}
+}
+
+func TestKeyCommitmentOnlyDecryptsWithCommittedKey(t *testing.T) {
+	// Two RAW keys, so that the output prefix does not select the key.
+	key1 := testutil.NewKey(testutil.NewAESGCMKeyData(16), tinkpb.KeyStatusType_ENABLED, 1, tinkpb.OutputPrefixType_RAW)
+	key2 := testutil.NewKey(testutil.NewAESGCMKeyData(16), tinkpb.KeyStatusType_ENABLED, 2, tinkpb.OutputPrefixType_RAW)
+	newAEAD := func(primary uint32) (*keyset.Handle, func(pt []byte) []byte) {
+		kh, err := testkeyset.NewHandle(testutil.NewKeyset(primary, []*tinkpb.Keyset_Key{key1, key2}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		a, err := aead.NewWithKeyCommitment(kh)
+		if err != nil {
+			t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+		}
+		return kh, func(pt []byte) []byte {
+			ct, err := a.Encrypt(pt, nil)
+			if err != nil {
+				t.Fatalf("a.Encrypt failed: %s", err)
+			}
+			return ct
+		}
+	}
+	kh, encrypt1 := newAEAD(1)
+	_, encrypt2 := newAEAD(2)
+	ct1 := encrypt1([]byte("plaintext"))
+	ct2 := encrypt2([]byte("plaintext"))
+
+	a, err := aead.NewWithKeyCommitment(kh)
+	if err != nil {
+		t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+	}
+	if _, err := a.Decrypt(ct1, nil); err != nil {
+		t.Errorf("a.Decrypt failed: %s", err)
+	}
+	// Replacing the commitment makes the ciphertext fail to decrypt, even though
+	// the key that produced it is in the keyset.
+	forged := append(append([]byte(nil), ct2[:64]...), ct1[64:]...)
+	if _, err := a.Decrypt(forged, nil); !errors.Is(err, aead.ErrDecryptionFailed) {
+		t.Errorf("a.Decrypt with the commitment of another key = %v, want aead.ErrDecryptionFailed", err)
+	}
+}
+
+func TestKeyCommitmentIsNotAFingerprint(t *testing.T) {
+	// A RAW key, so that the ciphertext starts with the commitment.
+	kh, err := keyset.NewHandle(aead.AES256GCMNoPrefixKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	a, err := aead.NewWithKeyCommitment(kh)
+	if err != nil {
+		t.Fatalf("aead.NewWithKeyCommitment failed: %s", err)
+	}
+	ct1, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt failed: %s", err)
+	}
+	ct2, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt failed: %s", err)
+	}
+	// Commitments are salted, so ciphertexts of the same key can't be linked.
+	if bytes.Equal(ct1[:64], ct2[:64]) {
+		t.Errorf("two ciphertexts of the same key have the same commitment")
+	}
+	if bytes.Equal(ct1[32:64], ct2[32:64]) {
+		t.Errorf("two ciphertexts of the same key have the same commitment tag")
+	}
+	// The salt is authenticated by the tag.
+	ct1[0] ^= 1
+	if _, err := a.Decrypt(ct1, nil); !errors.Is(err, aead.ErrDecryptionFailed) {
+		t.Errorf("a.Decrypt with a modified salt = %v, want aead.ErrDecryptionFailed", err)
+	}
+}
+
+func TestKeyCommitmentRejectsRemoteKeys(t *testing.T) {
+	client, err := fakekms.NewClient("fake-kms://")
+	if err != nil {
+		t.Fatalf("fakekms.NewClient failed: %s", err)
+	}
+	registry.RegisterKMSClient(client)
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI failed: %s", err)
+	}
+	kh, err := keyset.NewHandle(aead.KMSEnvelopeAEADKeyTemplate(keyURI, aead.AES128GCMKeyTemplate()))
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	if _, err := aead.NewWithKeyCommitment(kh); err == nil {
+		t.Errorf("aead.NewWithKeyCommitment with a KMS envelope key succeeded, want error")
+	}
+}
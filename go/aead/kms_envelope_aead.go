@@ -18,9 +18,12 @@ package aead
 
 import (
 	"bytes"
+	"container/list"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/registry"
@@ -36,6 +39,25 @@ const (
 type KMSEnvelopeAEAD struct {
 	dekTemplate *tinkpb.KeyTemplate
 	remote      tink.AEAD
+	// cache is nil unless the instance was created by
+	// NewKMSEnvelopeAEADWithDEKCache.
+	cache *dekCache
+}
+
+// DEKCacheConfig configures the DEK cache of a KMSEnvelopeAEAD.
+type DEKCacheConfig struct {
+	// MaxEntries is the maximal number of decrypted DEKs that are kept for
+	// decryption. When the cache is full, the least recently used DEK is
+	// evicted. It must be positive.
+	MaxEntries int
+
+	// TTL is how long a DEK is used for encryption, and how long a decrypted DEK
+	// is kept for decryption, after it was obtained. It must be positive.
+	TTL time.Duration
+
+	// MaxMessagesPerDEK is the maximal number of messages that are encrypted
+	// with one DEK. Zero means no limit other than TTL.
+	MaxMessagesPerDEK int
 }
 
 // NewKMSEnvelopeAEAD creates an new instance of KMSEnvelopeAEAD.
@@ -55,35 +77,77 @@ func NewKMSEnvelopeAEAD2(kt *tinkpb.KeyTemplate, remote tink.AEAD) *KMSEnvelopeA
 	}
 }
 
+// NewKMSEnvelopeAEADWithDEKCache creates an new instance of KMSEnvelopeAEAD
+// that caches DEKs, so that not every message needs a round trip to the remote
+// AEAD. Encrypt reuses a DEK for up to config.MaxMessagesPerDEK messages and
+// for at most config.TTL, and Decrypt keeps up to config.MaxEntries decrypted
+// DEKs for at most config.TTL each.
+//
+// Ciphertexts are compatible with those of NewKMSEnvelopeAEAD2. Note that
+// ciphertexts encrypted with the same DEK can be recognized as such, and that
+// cached DEKs are kept in memory in plaintext.
+func NewKMSEnvelopeAEADWithDEKCache(kt *tinkpb.KeyTemplate, remote tink.AEAD, config DEKCacheConfig) (*KMSEnvelopeAEAD, error) {
+	if config.MaxEntries <= 0 {
+		return nil, errors.New("kms_envelope_aead: DEK cache size must be positive")
+	}
+	if config.TTL <= 0 {
+		return nil, errors.New("kms_envelope_aead: DEK cache TTL must be positive")
+	}
+	if config.MaxMessagesPerDEK < 0 {
+		return nil, errors.New("kms_envelope_aead: maximal number of messages per DEK must not be negative")
+	}
+	return &KMSEnvelopeAEAD{
+		remote:      remote,
+		dekTemplate: kt,
+		cache:       newDEKCache(config),
+	}, nil
+}
+
 // Encrypt implements the tink.AEAD interface for encryption.
 func (a *KMSEnvelopeAEAD) Encrypt(pt, aad []byte) ([]byte, error) {
-	dekM, err := registry.NewKey(a.dekTemplate)
+	encryptedDEK, primitive, err := a.encryptionDEK()
 	if err != nil {
 		return nil, err
 	}
-	dek, err := proto.Marshal(dekM)
+	payload, err := primitive.Encrypt(pt, aad)
 	if err != nil {
 		return nil, err
 	}
+	return buildCipherText(encryptedDEK, payload)
+}
+
+// encryptionDEK returns the encrypted DEK and the primitive to encrypt a
+// message with. It generates a new DEK unless the cache has a usable one.
+func (a *KMSEnvelopeAEAD) encryptionDEK() ([]byte, tink.AEAD, error) {
+	if a.cache != nil {
+		if d := a.cache.current(); d != nil {
+			return d.encryptedDEK, d.primitive, nil
+		}
+	}
+	dekM, err := registry.NewKey(a.dekTemplate)
+	if err != nil {
+		return nil, nil, err
+	}
+	dek, err := proto.Marshal(dekM)
+	if err != nil {
+		return nil, nil, err
+	}
 	encryptedDEK, err := a.remote.Encrypt(dek, []byte{})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	p, err := registry.Primitive(a.dekTemplate.TypeUrl, dek)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	primitive, ok := p.(tink.AEAD)
 	if !ok {
-		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
+		return nil, nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
 	}
-
-	payload, err := primitive.Encrypt(pt, aad)
-	if err != nil {
-		return nil, err
+	if a.cache != nil {
+		a.cache.setCurrent(encryptedDEK, primitive)
 	}
-	return buildCipherText(encryptedDEK, payload)
-
+	return encryptedDEK, primitive, nil
 }
 
 // Decrypt implements the tink.AEAD interface for decryption.
@@ -107,6 +171,24 @@ func (a *KMSEnvelopeAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
 	payload := ct[ed:]
 	ct = nil
 
+	primitive, err := a.decryptionDEK(encryptedDEK)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt the payload.
+	return primitive.Decrypt(payload, aad)
+}
+
+// decryptionDEK returns the primitive of the given encrypted DEK. It decrypts
+// the DEK with the remote AEAD unless the cache has it.
+func (a *KMSEnvelopeAEAD) decryptionDEK(encryptedDEK []byte) (tink.AEAD, error) {
+	if a.cache != nil {
+		if p := a.cache.get(encryptedDEK); p != nil {
+			return p, nil
+		}
+	}
+
 	// Decrypt the DEK.
 	dek, err := a.remote.Decrypt(encryptedDEK, []byte{})
 	if err != nil {
@@ -122,9 +204,10 @@ func (a *KMSEnvelopeAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
 	if !ok {
 		return nil, errors.New("kms_envelope_aead: failed to convert AEAD primitive")
 	}
-
-	// Decrypt the payload.
-	return primitive.Decrypt(payload, aad)
+	if a.cache != nil {
+		a.cache.add(encryptedDEK, primitive)
+	}
+	return primitive, nil
 }
 
 // buildCipherText builds the cipher text by appending the length DEK, encrypted DEK
@@ -151,3 +234,107 @@ func buildCipherText(encryptedDEK, payload []byte) ([]byte, error) {
 	}
 	return b.Bytes(), nil
 }
+
+// cachedDEK is a DEK in a dekCache.
+type cachedDEK struct {
+	encryptedDEK []byte
+	primitive    tink.AEAD
+	expiry       time.Time
+	// messages is the number of messages encrypted with the DEK.
+	messages int
+}
+
+// dekCache holds the DEK used for encryption and recently decrypted DEKs of a
+// KMSEnvelopeAEAD. It is safe for concurrent use.
+type dekCache struct {
+	config DEKCacheConfig
+	now    func() time.Time
+
+	mu         sync.Mutex
+	encryption *cachedDEK
+	// decryption maps encrypted DEKs to elements of lru, whose values are
+	// *cachedDEK. The front of lru is the most recently used DEK.
+	decryption map[string]*list.Element
+	lru        *list.List
+}
+
+func newDEKCache(config DEKCacheConfig) *dekCache {
+	return &dekCache{
+		config:     config,
+		now:        time.Now,
+		decryption: make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// current returns the DEK to encrypt the next message with, or nil if a new DEK
+// is needed.
+func (c *dekCache) current() *cachedDEK {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d := c.encryption
+	if d == nil || !c.now().Before(d.expiry) {
+		return nil
+	}
+	if c.config.MaxMessagesPerDEK > 0 && d.messages >= c.config.MaxMessagesPerDEK {
+		return nil
+	}
+	d.messages++
+	return d
+}
+
+// setCurrent makes the given DEK the one to encrypt messages with, counting
+// the message it is generated for. It also becomes available for decryption.
+func (c *dekCache) setCurrent(encryptedDEK []byte, p tink.AEAD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.encryption = &cachedDEK{
+		encryptedDEK: encryptedDEK,
+		primitive:    p,
+		expiry:       c.now().Add(c.config.TTL),
+		messages:     1,
+	}
+	c.addLocked(encryptedDEK, p)
+}
+
+// get returns the primitive of the given encrypted DEK, or nil if it is not
+// cached or has expired.
+func (c *dekCache) get(encryptedDEK []byte) tink.AEAD {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.decryption[string(encryptedDEK)]
+	if !ok {
+		return nil
+	}
+	d := e.Value.(*cachedDEK)
+	if !c.now().Before(d.expiry) {
+		c.lru.Remove(e)
+		delete(c.decryption, string(encryptedDEK))
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return d.primitive
+}
+
+// add caches the primitive of the given encrypted DEK for decryption.
+func (c *dekCache) add(encryptedDEK []byte, p tink.AEAD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addLocked(encryptedDEK, p)
+}
+
+func (c *dekCache) addLocked(encryptedDEK []byte, p tink.AEAD) {
+	if e, ok := c.decryption[string(encryptedDEK)]; ok {
+		c.lru.Remove(e)
+	}
+	for c.lru.Len() >= c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.decryption, string(oldest.Value.(*cachedDEK).encryptedDEK))
+	}
+	c.decryption[string(encryptedDEK)] = c.lru.PushFront(&cachedDEK{
+		encryptedDEK: encryptedDEK,
+		primitive:    p,
+		expiry:       c.now().Add(c.config.TTL),
+	})
+}
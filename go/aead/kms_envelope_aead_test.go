@@ -17,7 +17,10 @@
 package aead_test
 
 import (
+	"bytes"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
@@ -70,3 +73,197 @@ func TestKMSEnvelopeShortCiphertext(t *testing.T) {
 	}
 
 }
+
+// countingAEAD counts the calls to a remote AEAD.
+type countingAEAD struct {
+	tink.AEAD
+	encrypts int32
+	decrypts int32
+}
+
+func (c *countingAEAD) Encrypt(pt, aad []byte) ([]byte, error) {
+	atomic.AddInt32(&c.encrypts, 1)
+	return c.AEAD.Encrypt(pt, aad)
+}
+
+func (c *countingAEAD) Decrypt(ct, aad []byte) ([]byte, error) {
+	atomic.AddInt32(&c.decrypts, 1)
+	return c.AEAD.Decrypt(ct, aad)
+}
+
+func newCountingAEAD(t *testing.T) *countingAEAD {
+	t.Helper()
+	kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("failed to create new handle: %v", err)
+	}
+	parentAEAD, err := aead.New(kh)
+	if err != nil {
+		t.Fatalf("failed to create parent AEAD: %v", err)
+	}
+	return &countingAEAD{AEAD: parentAEAD}
+}
+
+func newCachedKMSEnvelopeAEAD(t *testing.T, remote tink.AEAD, config aead.DEKCacheConfig) tink.AEAD {
+	t.Helper()
+	a, err := aead.NewKMSEnvelopeAEADWithDEKCache(aead.AES256GCMKeyTemplate(), remote, config)
+	if err != nil {
+		t.Fatalf("NewKMSEnvelopeAEADWithDEKCache() failed: %v", err)
+	}
+	return a
+}
+
+func encryptMessages(t *testing.T, a tink.AEAD, n int) [][]byte {
+	t.Helper()
+	var cts [][]byte
+	for i := 0; i < n; i++ {
+		ct, err := a.Encrypt([]byte{byte(i)}, []byte("aad"))
+		if err != nil {
+			t.Fatalf("failed to encrypt: %v", err)
+		}
+		cts = append(cts, ct)
+	}
+	return cts
+}
+
+func decryptMessages(t *testing.T, a tink.AEAD, cts [][]byte) {
+	t.Helper()
+	for i, ct := range cts {
+		pt, err := a.Decrypt(ct, []byte("aad"))
+		if err != nil {
+			t.Fatalf("failed to decrypt: %v", err)
+		}
+		if !bytes.Equal(pt, []byte{byte(i)}) {
+			t.Errorf("Decrypt() = %x, want %x", pt, []byte{byte(i)})
+		}
+	}
+}
+
+func TestKMSEnvelopeDEKCacheReusesDEK(t *testing.T) {
+	remote := newCountingAEAD(t)
+	config := aead.DEKCacheConfig{MaxEntries: 10, TTL: time.Hour}
+	a := newCachedKMSEnvelopeAEAD(t, remote, config)
+
+	cts := encryptMessages(t, a, 10)
+	if remote.encrypts != 1 {
+		t.Errorf("remote encryptions = %d, want 1", remote.encrypts)
+	}
+	decryptMessages(t, a, cts)
+	if remote.decrypts != 0 {
+		t.Errorf("remote decryptions = %d, want 0", remote.decrypts)
+	}
+
+	// Another instance has to decrypt the DEK once.
+	b := newCachedKMSEnvelopeAEAD(t, remote, config)
+	decryptMessages(t, b, cts)
+	if remote.decrypts != 1 {
+		t.Errorf("remote decryptions = %d, want 1", remote.decrypts)
+	}
+
+	// The ciphertexts are compatible with an instance without cache.
+	decryptMessages(t, aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), remote), cts)
+}
+
+func TestKMSEnvelopeDEKCacheMaxMessagesPerDEK(t *testing.T) {
+	remote := newCountingAEAD(t)
+	a := newCachedKMSEnvelopeAEAD(t, remote, aead.DEKCacheConfig{
+		MaxEntries:        10,
+		TTL:               time.Hour,
+		MaxMessagesPerDEK: 3,
+	})
+	cts := encryptMessages(t, a, 10)
+	if remote.encrypts != 4 {
+		t.Errorf("remote encryptions = %d, want 4", remote.encrypts)
+	}
+	decryptMessages(t, a, cts)
+}
+
+func TestKMSEnvelopeDEKCacheTTL(t *testing.T) {
+	remote := newCountingAEAD(t)
+	a := newCachedKMSEnvelopeAEAD(t, remote, aead.DEKCacheConfig{
+		MaxEntries: 10,
+		TTL:        20 * time.Millisecond,
+	})
+	cts := encryptMessages(t, a, 1)
+	time.Sleep(40 * time.Millisecond)
+	cts = append(cts, encryptMessages(t, a, 2)[1])
+	if remote.encrypts != 2 {
+		t.Errorf("remote encryptions = %d, want 2", remote.encrypts)
+	}
+	// The DEK of the first message has expired and is decrypted again.
+	decryptMessages(t, a, cts[:1])
+	if remote.decrypts != 1 {
+		t.Errorf("remote decryptions = %d, want 1", remote.decrypts)
+	}
+}
+
+func TestKMSEnvelopeDEKCacheEviction(t *testing.T) {
+	remote := newCountingAEAD(t)
+	uncached := aead.NewKMSEnvelopeAEAD2(aead.AES256GCMKeyTemplate(), remote)
+	cts := encryptMessages(t, uncached, 3)
+
+	a := newCachedKMSEnvelopeAEAD(t, remote, aead.DEKCacheConfig{MaxEntries: 2, TTL: time.Hour})
+	decryptMessages(t, a, cts)
+	if remote.decrypts != 3 {
+		t.Errorf("remote decryptions = %d, want 3", remote.decrypts)
+	}
+	// The DEKs of the last two messages are cached, the first one was evicted.
+	if _, err := a.Decrypt(cts[2], []byte("aad")); err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if _, err := a.Decrypt(cts[1], []byte("aad")); err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if remote.decrypts != 3 {
+		t.Errorf("remote decryptions = %d, want 3", remote.decrypts)
+	}
+	if _, err := a.Decrypt(cts[0], []byte("aad")); err != nil {
+		t.Fatalf("failed to decrypt: %v", err)
+	}
+	if remote.decrypts != 4 {
+		t.Errorf("remote decryptions = %d, want 4", remote.decrypts)
+	}
+}
+
+func TestKMSEnvelopeDEKCacheConcurrentUse(t *testing.T) {
+	remote := newCountingAEAD(t)
+	a := newCachedKMSEnvelopeAEAD(t, remote, aead.DEKCacheConfig{
+		MaxEntries:        4,
+		TTL:               time.Hour,
+		MaxMessagesPerDEK: 5,
+	})
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		go func() {
+			for j := 0; j < 20; j++ {
+				ct, err := a.Encrypt([]byte("plaintext"), nil)
+				if err == nil {
+					_, err = a.Decrypt(ct, nil)
+				}
+				if err != nil {
+					done <- err
+					return
+				}
+			}
+			done <- nil
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if err := <-done; err != nil {
+			t.Errorf("concurrent roundtrip failed: %v", err)
+		}
+	}
+}
+
+func TestNewKMSEnvelopeAEADWithDEKCacheInvalidConfig(t *testing.T) {
+	remote := newCountingAEAD(t)
+	for _, config := range []aead.DEKCacheConfig{
+		{MaxEntries: 0, TTL: time.Hour},
+		{MaxEntries: 1, TTL: 0},
+		{MaxEntries: 1, TTL: time.Hour, MaxMessagesPerDEK: -1},
+	} {
+		if _, err := aead.NewKMSEnvelopeAEADWithDEKCache(aead.AES256GCMKeyTemplate(), remote, config); err == nil {
+			t.Errorf("NewKMSEnvelopeAEADWithDEKCache(%+v) succeeded, want error", config)
+		}
+	}
+}
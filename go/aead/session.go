@@ -0,0 +1,206 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf"
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// SessionIDSize is the size of session IDs in bytes.
+	SessionIDSize = 16
+
+	sessionKeySize   = 32
+	sessionNonceSize = 12
+
+	sessionInitiatorLabel = "tink aead session initiator"
+	sessionResponderLabel = "tink aead session responder"
+)
+
+var (
+	errSessionDecryption = errors.New("aead_session: decryption failed, message is invalid, replayed or out of order")
+	errSessionExhausted  = errors.New("aead_session: nonce sequence exhausted")
+)
+
+// Session encrypts and decrypts an ordered sequence of messages exchanged
+// between two parties, e.g. over a socket.
+//
+// Both parties derive the keys of the session from the primary key of a shared
+// PRF keyset and a random session ID, one for each direction. Every message is
+// encrypted with AES-256-GCM under a nonce that is derived from its position in
+// the sequence, so that ciphertexts only add the 16-byte tag to the plaintext.
+// Messages must be decrypted in the order in which they were encrypted; a
+// message that is replayed, reordered or dropped fails to decrypt. A failed
+// decryption does not advance the sequence.
+//
+// A Session is safe for concurrent use, but concurrent calls to Encrypt (or
+// Decrypt) are ordered arbitrarily.
+type Session struct {
+	id []byte
+
+	encMu  sync.Mutex
+	sealer *sessionDirection
+	decMu  sync.Mutex
+	opener *sessionDirection
+}
+
+// sessionDirection holds the state of one direction of a session.
+type sessionDirection struct {
+	aead cipher.AEAD
+	// iv is XORed with the sequence number to obtain the nonce of a message.
+	iv  [sessionNonceSize]byte
+	seq uint64
+}
+
+// NewSession starts a new session with a random session ID, using the primary
+// key of the given PRF keyset handle. The session ID is not secret and must be
+// sent to the other party, who joins the session with JoinSession.
+//
+// The session keys are computed with the PRF of the primary key, which must be
+// unexpired and produce outputs of at least 32 bytes, such as the keys of
+// prf.HMACSHA256PRFKeyTemplate and prf.HKDFSHA256PRFKeyTemplate. Use a
+// dedicated keyset for sessions rather than sharing keys with other
+// primitives.
+func NewSession(h *keyset.Handle) (*Session, error) {
+	return newSession(h, random.GetRandomBytes(SessionIDSize), sessionInitiatorLabel, sessionResponderLabel)
+}
+
+// JoinSession joins the session with the given ID that was started by the other
+// party with NewSession, using the primary key of the given PRF keyset handle.
+func JoinSession(h *keyset.Handle, id []byte) (*Session, error) {
+	if len(id) != SessionIDSize {
+		return nil, fmt.Errorf("aead_session: invalid session ID size %d, want %d", len(id), SessionIDSize)
+	}
+	return newSession(h, id, sessionResponderLabel, sessionInitiatorLabel)
+}
+
+func newSession(h *keyset.Handle, id []byte, sealLabel, openLabel string) (*Session, error) {
+	if h == nil {
+		return nil, errors.New("aead_session: keyset handle must not be nil")
+	}
+	// Obtaining the primitives validates the keyset and its keys, and checks
+	// them against the key type policy.
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("aead_session: cannot obtain primitive set: %s", err)
+	}
+	primary := ps.Primary
+	if primary == nil {
+		return nil, errors.New("aead_session: keyset has no enabled primary key")
+	}
+	p, ok := primary.Primitive.(prf.PRF)
+	if !ok {
+		return nil, errors.New("aead_session: primary key must be a PRF key")
+	}
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
+	set := prf.Set{PrimaryID: primary.KeyID, PRFs: map[uint32]prf.PRF{primary.KeyID: p}}
+	sealer, err := newSessionDirection(set, id, sealLabel)
+	if err != nil {
+		return nil, err
+	}
+	opener, err := newSessionDirection(set, id, openLabel)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		id:     append([]byte{}, id...),
+		sealer: sealer,
+		opener: opener,
+	}, nil
+}
+
+// newSessionDirection computes the key and IV of one direction of the session
+// with the given ID with the primary PRF of set.
+func newSessionDirection(set prf.Set, id []byte, label string) (*sessionDirection, error) {
+	key, err := set.ComputePRFWithLabel(label+" key", id, sessionKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("aead_session: cannot compute session key: %s", err)
+	}
+	iv, err := set.ComputePRFWithLabel(label+" iv", id, sessionNonceSize)
+	if err != nil {
+		return nil, fmt.Errorf("aead_session: cannot compute session IV: %s", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aead_session: %s", err)
+	}
+	a, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aead_session: %s", err)
+	}
+	d := &sessionDirection{aead: a}
+	copy(d.iv[:], iv)
+	return d, nil
+}
+
+// nonce returns the nonce of the current message.
+func (d *sessionDirection) nonce() []byte {
+	nonce := d.iv
+	var seq [8]byte
+	binary.BigEndian.PutUint64(seq[:], d.seq)
+	for i, b := range seq {
+		nonce[sessionNonceSize-8+i] ^= b
+	}
+	return nonce[:]
+}
+
+// ID returns the ID of the session.
+func (s *Session) ID() []byte {
+	return append([]byte{}, s.id...)
+}
+
+// Encrypt encrypts the next message of the session with the given additional
+// authenticated data.
+func (s *Session) Encrypt(pt, aad []byte) ([]byte, error) {
+	s.encMu.Lock()
+	defer s.encMu.Unlock()
+	if s.sealer.seq == math.MaxUint64 {
+		return nil, errSessionExhausted
+	}
+	ct := s.sealer.aead.Seal(nil, s.sealer.nonce(), pt, aad)
+	s.sealer.seq++
+	return ct, nil
+}
+
+// Decrypt decrypts the next message of the session and authenticates it with
+// the given additional authenticated data. It fails if ct is not the next
+// message that the other party encrypted.
+func (s *Session) Decrypt(ct, aad []byte) ([]byte, error) {
+	s.decMu.Lock()
+	defer s.decMu.Unlock()
+	if s.opener.seq == math.MaxUint64 {
+		return nil, errSessionExhausted
+	}
+	pt, err := s.opener.aead.Open(nil, s.opener.nonce(), ct, aad)
+	if err != nil {
+		return nil, errSessionDecryption
+	}
+	s.opener.seq++
+	return pt, nil
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/prf"
+	"github.com/google/tink/go/signature"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func newSessionPair(t *testing.T) (*aead.Session, *aead.Session) {
+	t.Helper()
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	initiator, err := aead.NewSession(kh)
+	if err != nil {
+		t.Fatalf("aead.NewSession() failed: %v", err)
+	}
+	responder, err := aead.JoinSession(kh, initiator.ID())
+	if err != nil {
+		t.Fatalf("aead.JoinSession() failed: %v", err)
+	}
+	return initiator, responder
+}
+
+func TestSessionRoundtrip(t *testing.T) {
+	initiator, responder := newSessionPair(t)
+	aad := []byte("aad")
+	for i := 0; i < 10; i++ {
+		for _, s := range []struct {
+			name     string
+			sender   *aead.Session
+			receiver *aead.Session
+		}{
+			{"initiator to responder", initiator, responder},
+			{"responder to initiator", responder, initiator},
+		} {
+			pt := []byte(fmt.Sprintf("message %d", i))
+			ct, err := s.sender.Encrypt(pt, aad)
+			if err != nil {
+				t.Fatalf("%s: Encrypt() failed: %v", s.name, err)
+			}
+			if len(ct) != len(pt)+16 {
+				t.Errorf("%s: len(ct) = %d, want %d", s.name, len(ct), len(pt)+16)
+			}
+			got, err := s.receiver.Decrypt(ct, aad)
+			if err != nil {
+				t.Fatalf("%s: Decrypt() failed: %v", s.name, err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("%s: Decrypt() = %q, want %q", s.name, got, pt)
+			}
+		}
+	}
+}
+
+func TestSessionDirectionsUseDifferentKeys(t *testing.T) {
+	initiator, responder := newSessionPair(t)
+	ct1, err := initiator.Encrypt([]byte("message"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	ct2, err := responder.Encrypt([]byte("message"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if bytes.Equal(ct1, ct2) {
+		t.Error("both directions produced the same ciphertext")
+	}
+	// A session cannot decrypt its own messages.
+	if _, err := initiator.Decrypt(ct1, nil); err == nil {
+		t.Error("Decrypt() of own message succeeded, want error")
+	}
+}
+
+func TestSessionDetectsReplayAndReorder(t *testing.T) {
+	initiator, responder := newSessionPair(t)
+	var cts [][]byte
+	for i := 0; i < 3; i++ {
+		ct, err := initiator.Encrypt([]byte{byte(i)}, nil)
+		if err != nil {
+			t.Fatalf("Encrypt() failed: %v", err)
+		}
+		cts = append(cts, ct)
+	}
+	if _, err := responder.Decrypt(cts[1], nil); err == nil {
+		t.Error("Decrypt() of out-of-order message succeeded, want error")
+	}
+	if _, err := responder.Decrypt(cts[0], nil); err != nil {
+		t.Fatalf("Decrypt() failed: %v", err)
+	}
+	if _, err := responder.Decrypt(cts[0], nil); err == nil {
+		t.Error("Decrypt() of replayed message succeeded, want error")
+	}
+	modified := append([]byte{}, cts[1]...)
+	modified[0] ^= 1
+	if _, err := responder.Decrypt(modified, nil); err == nil {
+		t.Error("Decrypt() of modified message succeeded, want error")
+	}
+	if _, err := responder.Decrypt(cts[1], []byte("other aad")); err == nil {
+		t.Error("Decrypt() with wrong additional data succeeded, want error")
+	}
+	// Failed decryptions do not advance the sequence.
+	for i := 1; i < 3; i++ {
+		pt, err := responder.Decrypt(cts[i], nil)
+		if err != nil {
+			t.Fatalf("Decrypt() failed: %v", err)
+		}
+		if !bytes.Equal(pt, []byte{byte(i)}) {
+			t.Errorf("Decrypt() = %x, want %x", pt, []byte{byte(i)})
+		}
+	}
+}
+
+func TestSessionIDsDiffer(t *testing.T) {
+	kh, err := keyset.NewHandle(prf.HKDFSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	s1, err := aead.NewSession(kh)
+	if err != nil {
+		t.Fatalf("aead.NewSession() failed: %v", err)
+	}
+	s2, err := aead.NewSession(kh)
+	if err != nil {
+		t.Fatalf("aead.NewSession() failed: %v", err)
+	}
+	if len(s1.ID()) != aead.SessionIDSize {
+		t.Errorf("len(ID()) = %d, want %d", len(s1.ID()), aead.SessionIDSize)
+	}
+	if bytes.Equal(s1.ID(), s2.ID()) {
+		t.Error("two sessions have the same ID")
+	}
+	ct, err := s1.Encrypt([]byte("message"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	other, err := aead.JoinSession(kh, s2.ID())
+	if err != nil {
+		t.Fatalf("aead.JoinSession() failed: %v", err)
+	}
+	if _, err := other.Decrypt(ct, nil); err == nil {
+		t.Error("Decrypt() in another session succeeded, want error")
+	}
+}
+
+func TestSessionDifferentKeysets(t *testing.T) {
+	initiator, _ := newSessionPair(t)
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	responder, err := aead.JoinSession(kh, initiator.ID())
+	if err != nil {
+		t.Fatalf("aead.JoinSession() failed: %v", err)
+	}
+	ct, err := initiator.Encrypt([]byte("message"), nil)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	if _, err := responder.Decrypt(ct, nil); err == nil {
+		t.Error("Decrypt() with another keyset succeeded, want error")
+	}
+}
+
+func TestSessionInvalidArguments(t *testing.T) {
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	for _, id := range [][]byte{nil, make([]byte, aead.SessionIDSize-1), make([]byte, aead.SessionIDSize+1)} {
+		if _, err := aead.JoinSession(kh, id); err == nil {
+			t.Errorf("aead.JoinSession() with ID of size %d succeeded, want error", len(id))
+		}
+	}
+	if _, err := aead.NewSession(nil); err == nil {
+		t.Error("aead.NewSession(nil) succeeded, want error")
+	}
+	sigKH, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	if _, err := aead.NewSession(sigKH); err == nil {
+		t.Error("aead.NewSession() with asymmetric key succeeded, want error")
+	}
+}
+
+func TestSessionRequiresUsablePRFKey(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		aead.AES256GCMKeyTemplate(),
+		mac.HMACSHA256Tag256KeyTemplate(),
+		// AES-CMAC PRF outputs are too short for a session key.
+		prf.AESCMACPRFKeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() failed: %v", err)
+		}
+		if _, err := aead.NewSession(kh); err == nil {
+			t.Errorf("aead.NewSession() with a key of type %s succeeded, want error", template.GetTypeUrl())
+		}
+	}
+
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	expired, err := kh.WithKeyExpiration(kh.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("kh.WithKeyExpiration() failed: %v", err)
+	}
+	if _, err := aead.NewSession(expired); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("aead.NewSession() with an expired key err = %v, want ErrKeyExpired", err)
+	}
+	if _, err := aead.JoinSession(expired, make([]byte, aead.SessionIDSize)); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("aead.JoinSession() with an expired key err = %v, want ErrKeyExpired", err)
+	}
+}
+
+func TestSessionChecksKeyTypePolicy(t *testing.T) {
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	registry.SetKeyTypePolicy(&registry.KeyTypeRules{
+		DeniedTypeURLs: []string{"type.googleapis.com/google.crypto.tink.HmacPrfKey"},
+	})
+	defer registry.SetKeyTypePolicy(nil)
+	if _, err := aead.NewSession(kh); err == nil {
+		t.Error("aead.NewSession() with a key denied by policy succeeded, want error")
+	}
+}
@@ -89,6 +89,12 @@ func (a *AESCTR) Decrypt(ciphertext []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext, which is the IV size.
+func (a *AESCTR) CiphertextOverhead() int {
+	return a.IVSize
+}
+
 // newIV creates a new IV for encryption.
 func (a *AESCTR) newIV() []byte {
 	return random.GetRandomBytes(uint32(a.IVSize))
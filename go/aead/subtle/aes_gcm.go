@@ -59,6 +59,15 @@ func NewAESGCM(key []byte) (*AESGCM, error) {
 // Note: AES-GCM implementation of crypto library always returns ciphertext with
 // 128-bit tag.
 func (a *AESGCM) Encrypt(pt, aad []byte) ([]byte, error) {
+	if len(pt) > maxPtSize() {
+		return nil, fmt.Errorf("aes_gcm: plaintext too long")
+	}
+	return a.EncryptTo(make([]byte, 0, AESGCMIVSize+len(pt)+AESGCMTagSize), pt, aad)
+}
+
+// EncryptTo is like Encrypt, but appends the ciphertext to dst and returns the
+// updated slice. dst must not overlap pt.
+func (a *AESGCM) EncryptTo(dst, pt, aad []byte) ([]byte, error) {
 	// Although Seal() function already checks for plaintext length,
 	// this check is repeated here to avoid panic.
 	if len(pt) > maxPtSize() {
@@ -69,12 +78,17 @@ func (a *AESGCM) Encrypt(pt, aad []byte) ([]byte, error) {
 		return nil, err
 	}
 	iv := a.newIV()
-	ct := cipher.Seal(nil, iv, pt, aad)
-	return append(iv, ct...), nil
+	return cipher.Seal(append(dst, iv...), iv, pt, aad), nil
 }
 
 // Decrypt decrypts ct with aad as the additional authenticated data.
 func (a *AESGCM) Decrypt(ct, aad []byte) ([]byte, error) {
+	return a.DecryptTo(nil, ct, aad)
+}
+
+// DecryptTo is like Decrypt, but appends the plaintext to dst and returns the
+// updated slice. dst must not overlap ct.
+func (a *AESGCM) DecryptTo(dst, ct, aad []byte) ([]byte, error) {
 	if len(ct) < AESGCMIVSize+AESGCMTagSize {
 		return nil, fmt.Errorf("aes_gcm: ciphertext too short")
 	}
@@ -83,13 +97,25 @@ func (a *AESGCM) Decrypt(ct, aad []byte) ([]byte, error) {
 		return nil, err
 	}
 	iv := ct[:AESGCMIVSize]
-	pt, err := cipher.Open(nil, iv, ct[AESGCMIVSize:], aad)
+	pt, err := cipher.Open(dst, iv, ct[AESGCMIVSize:], aad)
 	if err != nil {
 		return nil, fmt.Errorf("aes_gcm: %s", err)
 	}
 	return pt, nil
 }
 
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (a *AESGCM) CiphertextOverhead() int {
+	return AESGCMIVSize + AESGCMTagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (a *AESGCM) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + a.CiphertextOverhead()
+}
+
 // newIV creates a new IV for encryption.
 func (a *AESGCM) newIV() []byte {
 	return random.GetRandomBytes(AESGCMIVSize)
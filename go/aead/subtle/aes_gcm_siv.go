@@ -150,6 +150,18 @@ func (a *AESGCMSIV) Decrypt(ct, aad []byte) ([]byte, error) {
 	return pt, nil
 }
 
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (a *AESGCMSIV) CiphertextOverhead() int {
+	return AESGCMSIVNonceSize + aesgcmsivTagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (a *AESGCMSIV) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + a.CiphertextOverhead()
+}
+
 // The KDF as described by the RFC #8452. This uses the AES-GCM-SIV key and
 // nonce to generate the authentication key and the encryption key.
 func (a *AESGCMSIV) deriveKeys(nonce []byte) ([]byte, []byte, error) {
@@ -0,0 +1,248 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// Ascon128aKeySize is the only key size of Ascon-128a.
+	Ascon128aKeySize = 16
+	// Ascon128aNonceSize is the size of the random nonce of Ascon-128a.
+	Ascon128aNonceSize = 16
+	// Ascon128aTagSize is the size of the tag of Ascon-128a.
+	Ascon128aTagSize = 16
+
+	ascon128aRate = 16
+	ascon128aIV   = 0x80800c0800000000
+)
+
+var errAsconOpen = errors.New("ascon128a: message authentication failed")
+
+// asconRoundConstants are the round constants of the Ascon permutation. The
+// permutation with n rounds uses the last n constants.
+var asconRoundConstants = [12]uint64{0xf0, 0xe1, 0xd2, 0xc3, 0xb4, 0xa5, 0x96, 0x87, 0x78, 0x69, 0x5a, 0x4b}
+
+// Ascon128a is an implementation of AEAD interface. It implements Ascon-128a
+// as specified in version 1.2 of the Ascon submission to the NIST lightweight
+// cryptography standardization process.
+type Ascon128a struct {
+	Key []byte
+
+	k0, k1 uint64
+}
+
+// Assert that Ascon128a implements the AEAD interface.
+var _ tink.AEAD = (*Ascon128a)(nil)
+
+// NewAscon128a returns an Ascon128a instance.
+// The key argument should be a 16-bytes key.
+func NewAscon128a(key []byte) (*Ascon128a, error) {
+	if len(key) != Ascon128aKeySize {
+		return nil, fmt.Errorf("ascon128a: bad key length")
+	}
+	return &Ascon128a{
+		Key: key,
+		k0:  binary.BigEndian.Uint64(key),
+		k1:  binary.BigEndian.Uint64(key[8:]),
+	}, nil
+}
+
+// Encrypt encrypts pt with aad as additional authenticated data.
+// The resulting ciphertext consists of two parts:
+// (1) the nonce used for encryption and (2) the actual ciphertext.
+func (a *Ascon128a) Encrypt(pt, aad []byte) ([]byte, error) {
+	if len(pt) > maxInt-Ascon128aNonceSize-Ascon128aTagSize {
+		return nil, fmt.Errorf("ascon128a: plaintext too long")
+	}
+	n := random.GetRandomBytes(Ascon128aNonceSize)
+	ret := make([]byte, Ascon128aNonceSize+len(pt)+Ascon128aTagSize)
+	copy(ret, n)
+	a.seal(ret[Ascon128aNonceSize:], n, pt, aad)
+	return ret, nil
+}
+
+// Decrypt decrypts ct with aad as the additional authenticated data.
+func (a *Ascon128a) Decrypt(ct, aad []byte) ([]byte, error) {
+	if len(ct) < Ascon128aNonceSize+Ascon128aTagSize {
+		return nil, fmt.Errorf("ascon128a: ciphertext too short")
+	}
+	pt := make([]byte, len(ct)-Ascon128aNonceSize-Ascon128aTagSize)
+	if err := a.open(pt, ct[:Ascon128aNonceSize], ct[Ascon128aNonceSize:], aad); err != nil {
+		return nil, err
+	}
+	return pt, nil
+}
+
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (a *Ascon128a) CiphertextOverhead() int {
+	return Ascon128aNonceSize + Ascon128aTagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (a *Ascon128a) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + a.CiphertextOverhead()
+}
+
+// asconState is the 320-bit state of Ascon.
+type asconState [5]uint64
+
+// permute applies the given number of rounds of the Ascon permutation.
+func (s *asconState) permute(rounds int) {
+	x0, x1, x2, x3, x4 := s[0], s[1], s[2], s[3], s[4]
+	for _, c := range asconRoundConstants[12-rounds:] {
+		// Addition of constants.
+		x2 ^= c
+		// Substitution layer.
+		x0 ^= x4
+		x4 ^= x3
+		x2 ^= x1
+		t0 := ^x0 & x1
+		t1 := ^x1 & x2
+		t2 := ^x2 & x3
+		t3 := ^x3 & x4
+		t4 := ^x4 & x0
+		x0 ^= t1
+		x1 ^= t2
+		x2 ^= t3
+		x3 ^= t4
+		x4 ^= t0
+		x1 ^= x0
+		x0 ^= x4
+		x3 ^= x2
+		x2 = ^x2
+		// Linear diffusion layer.
+		x0 ^= bits.RotateLeft64(x0, -19) ^ bits.RotateLeft64(x0, -28)
+		x1 ^= bits.RotateLeft64(x1, -61) ^ bits.RotateLeft64(x1, -39)
+		x2 ^= bits.RotateLeft64(x2, -1) ^ bits.RotateLeft64(x2, -6)
+		x3 ^= bits.RotateLeft64(x3, -10) ^ bits.RotateLeft64(x3, -17)
+		x4 ^= bits.RotateLeft64(x4, -7) ^ bits.RotateLeft64(x4, -41)
+	}
+	s[0], s[1], s[2], s[3], s[4] = x0, x1, x2, x3, x4
+}
+
+// absorbPadded XORs the given partial block, padded with 0x80 and zeros, into
+// the rate of the state.
+func (s *asconState) absorbPadded(b []byte) {
+	var block [ascon128aRate]byte
+	copy(block[:], b)
+	block[len(b)] = 0x80
+	s[0] ^= binary.BigEndian.Uint64(block[:])
+	s[1] ^= binary.BigEndian.Uint64(block[8:])
+}
+
+// squeezePartial writes the first len(dst) bytes of the rate of the state XORed
+// with src to dst.
+func (s *asconState) squeezePartial(dst, src []byte) {
+	var block [ascon128aRate]byte
+	binary.BigEndian.PutUint64(block[:], s[0])
+	binary.BigEndian.PutUint64(block[8:], s[1])
+	for i := range dst {
+		dst[i] = block[i] ^ src[i]
+	}
+}
+
+// start initializes the state with the given nonce and absorbs aad.
+func (a *Ascon128a) start(nonce, aad []byte) *asconState {
+	s := &asconState{
+		ascon128aIV,
+		a.k0,
+		a.k1,
+		binary.BigEndian.Uint64(nonce),
+		binary.BigEndian.Uint64(nonce[8:]),
+	}
+	s.permute(12)
+	s[3] ^= a.k0
+	s[4] ^= a.k1
+	if len(aad) > 0 {
+		for ; len(aad) >= ascon128aRate; aad = aad[ascon128aRate:] {
+			s[0] ^= binary.BigEndian.Uint64(aad)
+			s[1] ^= binary.BigEndian.Uint64(aad[8:])
+			s.permute(8)
+		}
+		s.absorbPadded(aad)
+		s.permute(8)
+	}
+	s[4] ^= 1
+	return s
+}
+
+// finish computes the tag from the state and writes it to tag.
+func (a *Ascon128a) finish(s *asconState, tag []byte) {
+	s[2] ^= a.k0
+	s[3] ^= a.k1
+	s.permute(12)
+	binary.BigEndian.PutUint64(tag, s[3]^a.k0)
+	binary.BigEndian.PutUint64(tag[8:], s[4]^a.k1)
+}
+
+// seal writes the ciphertext and tag of pt to dst, which must have size
+// len(pt)+Ascon128aTagSize.
+func (a *Ascon128a) seal(dst, nonce, pt, aad []byte) {
+	s := a.start(nonce, aad)
+	c := dst
+	for ; len(pt) >= ascon128aRate; pt = pt[ascon128aRate:] {
+		s[0] ^= binary.BigEndian.Uint64(pt)
+		s[1] ^= binary.BigEndian.Uint64(pt[8:])
+		binary.BigEndian.PutUint64(c, s[0])
+		binary.BigEndian.PutUint64(c[8:], s[1])
+		c = c[ascon128aRate:]
+		s.permute(8)
+	}
+	s.absorbPadded(pt)
+	s.squeezePartial(c[:len(pt)], make([]byte, len(pt)))
+	a.finish(s, c[len(pt):])
+}
+
+// open verifies ct, which is the ciphertext followed by the tag, and writes
+// the plaintext to dst, which must have size len(ct)-Ascon128aTagSize.
+func (a *Ascon128a) open(dst, nonce, ct, aad []byte) error {
+	tag := ct[len(ct)-Ascon128aTagSize:]
+	ct = ct[:len(ct)-Ascon128aTagSize]
+	s := a.start(nonce, aad)
+	p := dst
+	for ; len(ct) >= ascon128aRate; ct = ct[ascon128aRate:] {
+		c0 := binary.BigEndian.Uint64(ct)
+		c1 := binary.BigEndian.Uint64(ct[8:])
+		binary.BigEndian.PutUint64(p, s[0]^c0)
+		binary.BigEndian.PutUint64(p[8:], s[1]^c1)
+		s[0], s[1] = c0, c1
+		p = p[ascon128aRate:]
+		s.permute(8)
+	}
+	s.squeezePartial(p, ct)
+	s.absorbPadded(p)
+	var expected [Ascon128aTagSize]byte
+	a.finish(s, expected[:])
+	if subtle.ConstantTimeCompare(expected[:], tag) != 1 {
+		for i := range dst {
+			dst[i] = 0
+		}
+		return errAsconOpen
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestAscon128aVectors(t *testing.T) {
+	// Test vectors from the known answer tests of the Ascon reference
+	// implementation, with key and nonce 000102...0f.
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	nonce := key
+	for i, test := range []struct {
+		plaintext  string
+		aad        string
+		ciphertext string
+	}{
+		{
+			plaintext:  "",
+			aad:        "",
+			ciphertext: "7a834e6f09210957067b10fd831f0078",
+		},
+		{
+			plaintext:  "",
+			aad:        "00",
+			ciphertext: "af3031b07b129ec84153373ddcaba528",
+		},
+	} {
+		a, err := subtle.NewAscon128a(key)
+		if err != nil {
+			t.Fatalf("#%d: NewAscon128a failed: %s", i, err)
+		}
+		ct, _ := hex.DecodeString(test.ciphertext)
+		aad, _ := hex.DecodeString(test.aad)
+		want, _ := hex.DecodeString(test.plaintext)
+		pt, err := a.Decrypt(append(append([]byte{}, nonce...), ct...), aad)
+		if err != nil {
+			t.Errorf("#%d: Decrypt failed: %s", i, err)
+			continue
+		}
+		if !bytes.Equal(pt, want) {
+			t.Errorf("#%d: Decrypt = %x, want %x", i, pt, want)
+		}
+	}
+}
+
+func TestAscon128aEncryptDecrypt(t *testing.T) {
+	key := random.GetRandomBytes(subtle.Ascon128aKeySize)
+	a, err := subtle.NewAscon128a(key)
+	if err != nil {
+		t.Fatalf("NewAscon128a failed: %s", err)
+	}
+	for _, ptSize := range []uint32{0, 1, 15, 16, 17, 32, 1000} {
+		pt := random.GetRandomBytes(ptSize)
+		aad := random.GetRandomBytes(20)
+		ct, err := a.Encrypt(pt, aad)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %s", err)
+		}
+		if len(ct) != len(pt)+subtle.Ascon128aNonceSize+subtle.Ascon128aTagSize {
+			t.Errorf("ciphertext has size %d, want %d", len(ct), len(pt)+subtle.Ascon128aNonceSize+subtle.Ascon128aTagSize)
+		}
+		decrypted, err := a.Decrypt(ct, aad)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %s", err)
+		}
+		if !bytes.Equal(pt, decrypted) {
+			t.Errorf("Decrypt = %x, want %x", decrypted, pt)
+		}
+		if _, err := a.Decrypt(ct, append(aad, 0)); err == nil {
+			t.Errorf("Decrypt succeeded with modified aad")
+		}
+		for i := range ct {
+			modified := append([]byte(nil), ct...)
+			modified[i] ^= 1
+			if _, err := a.Decrypt(modified, aad); err == nil {
+				t.Errorf("Decrypt succeeded with ciphertext modified at byte %d", i)
+			}
+		}
+	}
+}
+
+func TestAscon128aRandomNonce(t *testing.T) {
+	a, err := subtle.NewAscon128a(random.GetRandomBytes(subtle.Ascon128aKeySize))
+	if err != nil {
+		t.Fatalf("NewAscon128a failed: %s", err)
+	}
+	nonces := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		ct, err := a.Encrypt(nil, nil)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %s", err)
+		}
+		n := string(ct[:subtle.Ascon128aNonceSize])
+		if nonces[n] {
+			t.Fatalf("nonce is repeated after %d encryptions", i)
+		}
+		nonces[n] = true
+	}
+}
+
+func TestAscon128aInvalidInput(t *testing.T) {
+	for _, keySize := range []uint32{0, 15, 17, 24, 32} {
+		if _, err := subtle.NewAscon128a(random.GetRandomBytes(keySize)); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+	a, err := subtle.NewAscon128a(random.GetRandomBytes(subtle.Ascon128aKeySize))
+	if err != nil {
+		t.Fatalf("NewAscon128a failed: %s", err)
+	}
+	if _, err := a.Decrypt(make([]byte, subtle.Ascon128aNonceSize+subtle.Ascon128aTagSize-1), nil); err == nil {
+		t.Errorf("expect an error when ciphertext is too short")
+	}
+}
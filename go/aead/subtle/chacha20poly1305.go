@@ -51,6 +51,15 @@ func NewChaCha20Poly1305(key []byte) (*ChaCha20Poly1305, error) {
 // authenticated data. The resulting ciphertext consists of two parts:
 // (1) the nonce used for encryption and (2) the actual ciphertext.
 func (ca *ChaCha20Poly1305) Encrypt(pt []byte, aad []byte) ([]byte, error) {
+	if len(pt) > maxInt-chacha20poly1305.NonceSize-poly1305TagSize {
+		return nil, fmt.Errorf("chacha20poly1305: plaintext too long")
+	}
+	return ca.EncryptTo(make([]byte, 0, chacha20poly1305.NonceSize+len(pt)+poly1305TagSize), pt, aad)
+}
+
+// EncryptTo is like Encrypt, but appends the ciphertext to dst and returns the
+// updated slice. dst must not overlap pt.
+func (ca *ChaCha20Poly1305) EncryptTo(dst, pt, aad []byte) ([]byte, error) {
 	if len(pt) > maxInt-chacha20poly1305.NonceSize-poly1305TagSize {
 		return nil, fmt.Errorf("chacha20poly1305: plaintext too long")
 	}
@@ -60,12 +69,17 @@ func (ca *ChaCha20Poly1305) Encrypt(pt []byte, aad []byte) ([]byte, error) {
 	}
 
 	n := ca.newNonce()
-	ct := c.Seal(nil, n, pt, aad)
-	return append(n, ct...), nil
+	return c.Seal(append(dst, n...), n, pt, aad), nil
 }
 
 // Decrypt decrypts {@code ct} with {@code aad} as the additionalauthenticated data.
 func (ca *ChaCha20Poly1305) Decrypt(ct []byte, aad []byte) ([]byte, error) {
+	return ca.DecryptTo(nil, ct, aad)
+}
+
+// DecryptTo is like Decrypt, but appends the plaintext to dst and returns the
+// updated slice. dst must not overlap ct.
+func (ca *ChaCha20Poly1305) DecryptTo(dst, ct, aad []byte) ([]byte, error) {
 	if len(ct) < chacha20poly1305.NonceSize+poly1305TagSize {
 		return nil, fmt.Errorf("chacha20poly1305: ciphertext too short")
 	}
@@ -76,13 +90,25 @@ func (ca *ChaCha20Poly1305) Decrypt(ct []byte, aad []byte) ([]byte, error) {
 	}
 
 	n := ct[:chacha20poly1305.NonceSize]
-	pt, err := c.Open(nil, n, ct[chacha20poly1305.NonceSize:], aad)
+	pt, err := c.Open(dst, n, ct[chacha20poly1305.NonceSize:], aad)
 	if err != nil {
 		return nil, fmt.Errorf("ChaCha20Poly1305.Decrypt: %s", err)
 	}
 	return pt, nil
 }
 
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (ca *ChaCha20Poly1305) CiphertextOverhead() int {
+	return chacha20poly1305.NonceSize + poly1305TagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (ca *ChaCha20Poly1305) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + ca.CiphertextOverhead()
+}
+
 // newNonce creates a new nonce for encryption.
 func (ca *ChaCha20Poly1305) newNonce() []byte {
 	return random.GetRandomBytes(chacha20poly1305.NonceSize)
@@ -115,3 +115,21 @@ func (e *EncryptThenAuthenticate) Decrypt(ciphertext, additionalData []byte) ([]
 
 	return plaintext, nil
 }
+
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext. It includes the overhead of the IND-CPA cipher
+// only if the cipher reports it with a CiphertextOverhead method, as AESCTR
+// does.
+func (e *EncryptThenAuthenticate) CiphertextOverhead() int {
+	overhead := e.tagSize
+	if c, ok := e.indCPACipher.(interface{ CiphertextOverhead() int }); ok {
+		overhead += c.CiphertextOverhead()
+	}
+	return overhead
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (e *EncryptThenAuthenticate) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + e.CiphertextOverhead()
+}
@@ -17,10 +17,12 @@
 package subtle_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
 	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
 	"github.com/google/tink/go/testutil"
 )
 
@@ -69,3 +71,82 @@ func TestValidateAESKeySize(t *testing.T) {
 		}
 	}
 }
+
+// bufferAEAD is implemented by the primitives that can encrypt and decrypt into
+// caller-provided buffers.
+type bufferAEAD interface {
+	Encrypt(pt, aad []byte) ([]byte, error)
+	Decrypt(ct, aad []byte) ([]byte, error)
+	EncryptTo(dst, pt, aad []byte) ([]byte, error)
+	DecryptTo(dst, ct, aad []byte) ([]byte, error)
+}
+
+func TestEncryptToDecryptTo(t *testing.T) {
+	key := random.GetRandomBytes(32)
+	aesGCM, err := subtle.NewAESGCM(key)
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM failed: %s", err)
+	}
+	chacha, err := subtle.NewChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatalf("subtle.NewChaCha20Poly1305 failed: %s", err)
+	}
+	xchacha, err := subtle.NewXChaCha20Poly1305(key)
+	if err != nil {
+		t.Fatalf("subtle.NewXChaCha20Poly1305 failed: %s", err)
+	}
+	xaes, err := subtle.NewXAES256GCM(key)
+	if err != nil {
+		t.Fatalf("subtle.NewXAES256GCM failed: %s", err)
+	}
+	for name, a := range map[string]bufferAEAD{
+		"AESGCM":            aesGCM,
+		"ChaCha20Poly1305":  chacha,
+		"XChaCha20Poly1305": xchacha,
+		"XAES256GCM":        xaes,
+	} {
+		pt := random.GetRandomBytes(100)
+		aad := random.GetRandomBytes(10)
+		header := []byte("header")
+		buf := make([]byte, len(header), 1024)
+		copy(buf, header)
+
+		ct, err := a.EncryptTo(buf, pt, aad)
+		if err != nil {
+			t.Fatalf("%s: EncryptTo failed: %s", name, err)
+		}
+		if &ct[0] != &buf[0] {
+			t.Errorf("%s: EncryptTo did not reuse dst", name)
+		}
+		if !bytes.Equal(ct[:len(header)], header) {
+			t.Errorf("%s: EncryptTo modified the contents of dst", name)
+		}
+		decrypted, err := a.Decrypt(ct[len(header):], aad)
+		if err != nil {
+			t.Fatalf("%s: Decrypt failed: %s", name, err)
+		}
+		if !bytes.Equal(decrypted, pt) {
+			t.Errorf("%s: Decrypt = %x, want %x", name, decrypted, pt)
+		}
+
+		ct, err = a.Encrypt(pt, aad)
+		if err != nil {
+			t.Fatalf("%s: Encrypt failed: %s", name, err)
+		}
+		out := make([]byte, len(header), 1024)
+		copy(out, header)
+		decrypted, err = a.DecryptTo(out, ct, aad)
+		if err != nil {
+			t.Fatalf("%s: DecryptTo failed: %s", name, err)
+		}
+		if &decrypted[0] != &out[0] {
+			t.Errorf("%s: DecryptTo did not reuse dst", name)
+		}
+		if !bytes.Equal(decrypted, append(header, pt...)) {
+			t.Errorf("%s: DecryptTo = %x, want %x", name, decrypted, append(header, pt...))
+		}
+		if _, err := a.DecryptTo(out, ct, nil); err == nil {
+			t.Errorf("%s: DecryptTo succeeded with wrong aad", name)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// XAES256GCMKeySize is the only key size of XAES-256-GCM.
+	XAES256GCMKeySize = 32
+	// XAES256GCMNonceSize is the size of the random nonce of XAES-256-GCM.
+	XAES256GCMNonceSize = 24
+)
+
+// XAES256GCM is an implementation of AEAD interface. It implements
+// XAES-256-GCM as specified at https://c2sp.org/XAES-256-GCM, which derives a
+// fresh AES-256-GCM key from the first 12 bytes of a 24-byte nonce and uses
+// the last 12 bytes as the AES-GCM nonce. Random nonces can therefore be used
+// for a practically unlimited number of messages under one key.
+type XAES256GCM struct {
+	Key []byte
+
+	block cipher.Block
+	// k1 is the first CMAC subkey of block, which the key derivation uses.
+	k1 [aes.BlockSize]byte
+}
+
+// Assert that XAES256GCM implements the AEAD interface.
+var _ tink.AEAD = (*XAES256GCM)(nil)
+
+// NewXAES256GCM returns an XAES256GCM instance.
+// The key argument should be a 32-bytes key.
+func NewXAES256GCM(key []byte) (*XAES256GCM, error) {
+	if len(key) != XAES256GCMKeySize {
+		return nil, fmt.Errorf("xaes_256_gcm: bad key length")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("xaes_256_gcm: %s", err)
+	}
+	x := &XAES256GCM{Key: key, block: block}
+	block.Encrypt(x.k1[:], x.k1[:])
+	msb := x.k1[0] >> 7
+	for i := 0; i < len(x.k1)-1; i++ {
+		x.k1[i] = x.k1[i]<<1 | x.k1[i+1]>>7
+	}
+	x.k1[len(x.k1)-1] = x.k1[len(x.k1)-1]<<1 ^ msb*0x87
+	return x, nil
+}
+
+// Encrypt encrypts pt with aad as additional authenticated data.
+// The resulting ciphertext consists of two parts:
+// (1) the nonce used for encryption and (2) the actual ciphertext.
+func (x *XAES256GCM) Encrypt(pt, aad []byte) ([]byte, error) {
+	if len(pt) > maxInt-XAES256GCMNonceSize-AESGCMTagSize || len(pt) > maxAESGCMPlaintextSize {
+		return nil, fmt.Errorf("xaes_256_gcm: plaintext too long")
+	}
+	return x.EncryptTo(make([]byte, 0, XAES256GCMNonceSize+len(pt)+AESGCMTagSize), pt, aad)
+}
+
+// EncryptTo is like Encrypt, but appends the ciphertext to dst and returns the
+// updated slice. dst must not overlap pt.
+func (x *XAES256GCM) EncryptTo(dst, pt, aad []byte) ([]byte, error) {
+	if len(pt) > maxInt-XAES256GCMNonceSize-AESGCMTagSize || len(pt) > maxAESGCMPlaintextSize {
+		return nil, fmt.Errorf("xaes_256_gcm: plaintext too long")
+	}
+	n := random.GetRandomBytes(XAES256GCMNonceSize)
+	c, err := x.deriveCipher(n[:12])
+	if err != nil {
+		return nil, err
+	}
+	return c.Seal(append(dst, n...), n[12:], pt, aad), nil
+}
+
+// Decrypt decrypts ct with aad as the additional authenticated data.
+func (x *XAES256GCM) Decrypt(ct, aad []byte) ([]byte, error) {
+	return x.DecryptTo(nil, ct, aad)
+}
+
+// DecryptTo is like Decrypt, but appends the plaintext to dst and returns the
+// updated slice. dst must not overlap ct.
+func (x *XAES256GCM) DecryptTo(dst, ct, aad []byte) ([]byte, error) {
+	if len(ct) < XAES256GCMNonceSize+AESGCMTagSize {
+		return nil, fmt.Errorf("xaes_256_gcm: ciphertext too short")
+	}
+	n := ct[:XAES256GCMNonceSize]
+	c, err := x.deriveCipher(n[:12])
+	if err != nil {
+		return nil, err
+	}
+	pt, err := c.Open(dst, n[12:], ct[XAES256GCMNonceSize:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("xaes_256_gcm: %s", err)
+	}
+	return pt, nil
+}
+
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (x *XAES256GCM) CiphertextOverhead() int {
+	return XAES256GCMNonceSize + AESGCMTagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (x *XAES256GCM) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + x.CiphertextOverhead()
+}
+
+// deriveCipher returns the AES-256-GCM cipher for the given first half of a
+// nonce. The derived key is the two-block output of CMAC with the prefixes
+// 0x00 0x01 'X' 0x00 and 0x00 0x02 'X' 0x00, which for single-block messages
+// reduces to encrypting each block XORed with k1.
+func (x *XAES256GCM) deriveCipher(n []byte) (cipher.AEAD, error) {
+	var m [aes.BlockSize]byte
+	key := make([]byte, XAES256GCMKeySize)
+	for i := byte(1); i <= 2; i++ {
+		m[0], m[1], m[2], m[3] = 0, i, 'X', 0
+		copy(m[4:], n)
+		for j := range m {
+			m[j] ^= x.k1[j]
+		}
+		x.block.Encrypt(key[(i-1)*aes.BlockSize:], m[:])
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errCipher
+	}
+	ret, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errCipher
+	}
+	return ret, nil
+}
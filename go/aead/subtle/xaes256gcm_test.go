@@ -0,0 +1,136 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestXAES256GCMVectors(t *testing.T) {
+	// Test vectors from https://c2sp.org/XAES-256-GCM.
+	for i, test := range []struct {
+		key        []byte
+		nonce      string
+		plaintext  string
+		aad        string
+		ciphertext string
+	}{
+		{
+			key:        bytes.Repeat([]byte{0x01}, 32),
+			nonce:      "ABCDEFGHIJKLMNOPQRSTUVWX",
+			plaintext:  "XAES-256-GCM",
+			aad:        "",
+			ciphertext: "ce546ef63c9cc60765923609b33a9a1974e96e52daf2fcf7075e2271",
+		},
+		{
+			key:        bytes.Repeat([]byte{0x03}, 32),
+			nonce:      "ABCDEFGHIJKLMNOPQRSTUVWX",
+			plaintext:  "XAES-256-GCM",
+			aad:        "c2sp.org/XAES-256-GCM",
+			ciphertext: "986ec1832593df5443a179437fd083bf3fdb41abd740a21f71eb769d",
+		},
+	} {
+		x, err := subtle.NewXAES256GCM(test.key)
+		if err != nil {
+			t.Fatalf("#%d: NewXAES256GCM failed: %s", i, err)
+		}
+		ct, _ := hex.DecodeString(test.ciphertext)
+		pt, err := x.Decrypt(append([]byte(test.nonce), ct...), []byte(test.aad))
+		if err != nil {
+			t.Errorf("#%d: Decrypt failed: %s", i, err)
+			continue
+		}
+		if string(pt) != test.plaintext {
+			t.Errorf("#%d: Decrypt = %q, want %q", i, pt, test.plaintext)
+		}
+	}
+}
+
+func TestXAES256GCMEncryptDecrypt(t *testing.T) {
+	key := random.GetRandomBytes(subtle.XAES256GCMKeySize)
+	x, err := subtle.NewXAES256GCM(key)
+	if err != nil {
+		t.Fatalf("NewXAES256GCM failed: %s", err)
+	}
+	for _, ptSize := range []uint32{0, 1, 16, 17, 1000} {
+		pt := random.GetRandomBytes(ptSize)
+		aad := random.GetRandomBytes(20)
+		ct, err := x.Encrypt(pt, aad)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %s", err)
+		}
+		if len(ct) != len(pt)+subtle.XAES256GCMNonceSize+subtle.AESGCMTagSize {
+			t.Errorf("ciphertext has size %d, want %d", len(ct), len(pt)+subtle.XAES256GCMNonceSize+subtle.AESGCMTagSize)
+		}
+		decrypted, err := x.Decrypt(ct, aad)
+		if err != nil {
+			t.Fatalf("Decrypt failed: %s", err)
+		}
+		if !bytes.Equal(pt, decrypted) {
+			t.Errorf("Decrypt = %x, want %x", decrypted, pt)
+		}
+		if _, err := x.Decrypt(ct, append(aad, 0)); err == nil {
+			t.Errorf("Decrypt succeeded with modified aad")
+		}
+		for i := range ct {
+			modified := append([]byte(nil), ct...)
+			modified[i] ^= 1
+			if _, err := x.Decrypt(modified, aad); err == nil {
+				t.Errorf("Decrypt succeeded with ciphertext modified at byte %d", i)
+			}
+		}
+	}
+}
+
+func TestXAES256GCMRandomNonce(t *testing.T) {
+	x, err := subtle.NewXAES256GCM(random.GetRandomBytes(subtle.XAES256GCMKeySize))
+	if err != nil {
+		t.Fatalf("NewXAES256GCM failed: %s", err)
+	}
+	nonces := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		ct, err := x.Encrypt(nil, nil)
+		if err != nil {
+			t.Fatalf("Encrypt failed: %s", err)
+		}
+		n := string(ct[:subtle.XAES256GCMNonceSize])
+		if nonces[n] {
+			t.Fatalf("nonce is repeated after %d encryptions", i)
+		}
+		nonces[n] = true
+	}
+}
+
+func TestXAES256GCMInvalidInput(t *testing.T) {
+	for _, keySize := range []uint32{0, 16, 24, 31, 33} {
+		if _, err := subtle.NewXAES256GCM(random.GetRandomBytes(keySize)); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+	x, err := subtle.NewXAES256GCM(random.GetRandomBytes(subtle.XAES256GCMKeySize))
+	if err != nil {
+		t.Fatalf("NewXAES256GCM failed: %s", err)
+	}
+	if _, err := x.Decrypt(make([]byte, subtle.XAES256GCMNonceSize+subtle.AESGCMTagSize-1), nil); err == nil {
+		t.Errorf("expect an error when ciphertext is too short")
+	}
+}
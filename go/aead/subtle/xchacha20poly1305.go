@@ -47,6 +47,15 @@ func NewXChaCha20Poly1305(key []byte) (*XChaCha20Poly1305, error) {
 // authenticated data. The resulting ciphertext consists of two parts:
 // (1) the nonce used for encryption and (2) the actual ciphertext.
 func (x *XChaCha20Poly1305) Encrypt(pt []byte, aad []byte) ([]byte, error) {
+	if len(pt) > maxInt-chacha20poly1305.NonceSizeX-poly1305TagSize {
+		return nil, fmt.Errorf("xchacha20poly1305: plaintext too long")
+	}
+	return x.EncryptTo(make([]byte, 0, chacha20poly1305.NonceSizeX+len(pt)+poly1305TagSize), pt, aad)
+}
+
+// EncryptTo is like Encrypt, but appends the ciphertext to dst and returns the
+// updated slice. dst must not overlap pt.
+func (x *XChaCha20Poly1305) EncryptTo(dst, pt, aad []byte) ([]byte, error) {
 	if len(pt) > maxInt-chacha20poly1305.NonceSizeX-poly1305TagSize {
 		return nil, fmt.Errorf("xchacha20poly1305: plaintext too long")
 	}
@@ -56,12 +65,17 @@ func (x *XChaCha20Poly1305) Encrypt(pt []byte, aad []byte) ([]byte, error) {
 	}
 
 	n := x.newNonce()
-	ct := c.Seal(nil, n, pt, aad)
-	return append(n, ct...), nil
+	return c.Seal(append(dst, n...), n, pt, aad), nil
 }
 
 // Decrypt decrypts {@code ct} with {@code aad} as the additionalauthenticated data.
 func (x *XChaCha20Poly1305) Decrypt(ct []byte, aad []byte) ([]byte, error) {
+	return x.DecryptTo(nil, ct, aad)
+}
+
+// DecryptTo is like Decrypt, but appends the plaintext to dst and returns the
+// updated slice. dst must not overlap ct.
+func (x *XChaCha20Poly1305) DecryptTo(dst, ct, aad []byte) ([]byte, error) {
 	if len(ct) < chacha20poly1305.NonceSizeX+poly1305TagSize {
 		return nil, fmt.Errorf("xchacha20poly1305: ciphertext too short")
 	}
@@ -72,13 +86,25 @@ func (x *XChaCha20Poly1305) Decrypt(ct []byte, aad []byte) ([]byte, error) {
 	}
 
 	n := ct[:chacha20poly1305.NonceSizeX]
-	pt, err := c.Open(nil, n, ct[chacha20poly1305.NonceSizeX:], aad)
+	pt, err := c.Open(dst, n, ct[chacha20poly1305.NonceSizeX:], aad)
 	if err != nil {
 		return nil, fmt.Errorf("XChaCha20Poly1305.Decrypt: %s", err)
 	}
 	return pt, nil
 }
 
+// CiphertextOverhead returns the number of bytes by which a ciphertext is
+// longer than its plaintext.
+func (x *XChaCha20Poly1305) CiphertextOverhead() int {
+	return chacha20poly1305.NonceSizeX + poly1305TagSize
+}
+
+// MaxCiphertextSize returns the size of the ciphertext of a plaintext of the
+// given size.
+func (x *XChaCha20Poly1305) MaxCiphertextSize(plaintextLen int) int {
+	return plaintextLen + x.CiphertextOverhead()
+}
+
 // newNonce creates a new nonce for encryption.
 func (x *XChaCha20Poly1305) newNonce() []byte {
 	return random.GetRandomBytes(chacha20poly1305.NonceSizeX)
@@ -0,0 +1,122 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xaesgcmpb "github.com/google/tink/go/proto/xaes_256_gcm_go_proto"
+)
+
+const (
+	xAES256GCMKeyVersion = 0
+	xAES256GCMTypeURL    = "type.googleapis.com/google.crypto.tink.XAes256GcmKey"
+)
+
+// Common errors.
+var errInvalidXAES256GCMKey = fmt.Errorf("xaes256gcm_key_manager: invalid key")
+
+// xAES256GCMKeyManager is an implementation of KeyManager interface.
+// It generates new XAES256GCMKey keys and produces new instances of XAES256GCM subtle.
+type xAES256GCMKeyManager struct{}
+
+// Assert that xAES256GCMKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*xAES256GCMKeyManager)(nil)
+
+// newXAES256GCMKeyManager creates a new xAES256GCMKeyManager.
+func newXAES256GCMKeyManager() *xAES256GCMKeyManager {
+	return new(xAES256GCMKeyManager)
+}
+
+// Primitive creates an XAES256GCM subtle for the given serialized XAES256GCMKey proto.
+func (km *xAES256GCMKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidXAES256GCMKey
+	}
+	key := new(xaesgcmpb.XAes256GcmKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidXAES256GCMKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewXAES256GCM(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("xaes256gcm_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key, ignoring the specification in the given serialized key format
+// because the key size and other params are fixed.
+func (km *xAES256GCMKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return km.newXAES256GCMKey(), nil
+}
+
+// NewKeyData creates a new KeyData, ignoring the specification in the given serialized key format
+// because the key size and other params are fixed.
+// It should be used solely by the key management API.
+func (km *xAES256GCMKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key := km.newXAES256GCMKey()
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         xAES256GCMTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *xAES256GCMKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == xAES256GCMTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *xAES256GCMKeyManager) TypeURL() string {
+	return xAES256GCMTypeURL
+}
+
+func (km *xAES256GCMKeyManager) newXAES256GCMKey() *xaesgcmpb.XAes256GcmKey {
+	keyValue := random.GetRandomBytes(subtle.XAES256GCMKeySize)
+	return &xaesgcmpb.XAes256GcmKey{
+		Version:  xAES256GCMKeyVersion,
+		KeyValue: keyValue,
+	}
+}
+
+// validateKey validates the given XAES256GCMKey.
+func (km *xAES256GCMKeyManager) validateKey(key *xaesgcmpb.XAes256GcmKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, xAES256GCMKeyVersion)
+	if err != nil {
+		return fmt.Errorf("xaes256gcm_key_manager: %s", err)
+	}
+	keySize := uint32(len(key.KeyValue))
+	if keySize != subtle.XAES256GCMKeySize {
+		return fmt.Errorf("xaes256gcm_key_manager: keySize != %d", subtle.XAES256GCMKeySize)
+	}
+	return nil
+}
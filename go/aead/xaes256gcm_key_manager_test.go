@@ -0,0 +1,187 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package aead_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+
+	"github.com/google/tink/go/aead/subtle"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xaesgcmpb "github.com/google/tink/go/proto/xaes_256_gcm_go_proto"
+)
+
+func TestXAES256GCMGetPrimitive(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	m, _ := km.NewKey(nil)
+	key, _ := m.(*xaesgcmpb.XAes256GcmKey)
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Errorf("km.Primitive(%v) = %v; want nil", serializedKey, err)
+	}
+	if err := validateXAES256GCMPrimitive(p, key); err != nil {
+		t.Errorf("validateXAES256GCMPrimitive(p, key) = %v; want nil", err)
+	}
+}
+
+func TestXAES256GCMGetPrimitiveWithInvalidKeys(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	invalidKeys := genInvalidXAES256GCMKeys()
+	for _, key := range invalidKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("km.Primitive(%v) = _, nil; want _, err", serializedKey)
+		}
+	}
+}
+
+func TestXAES256GCMNewKey(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	m, err := km.NewKey(nil)
+	if err != nil {
+		t.Errorf("km.NewKey(nil) = _, %v; want _, nil", err)
+	}
+	key, _ := m.(*xaesgcmpb.XAes256GcmKey)
+	if err := validateXAES256GCMKey(key); err != nil {
+		t.Errorf("validateXAES256GCMKey(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestXAES256GCMNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	kd, err := km.NewKeyData(nil)
+	if err != nil {
+		t.Errorf("km.NewKeyData(nil) = _, %v; want _, nil", err)
+	}
+	if kd.TypeUrl != testutil.XAES256GCMTypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, testutil.XAES256GCMTypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+	key := new(xaesgcmpb.XAes256GcmKey)
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		t.Errorf("proto.Unmarshal(%v, key) = %v; want nil", kd.Value, err)
+	}
+	if err := validateXAES256GCMKey(key); err != nil {
+		t.Errorf("validateXAES256GCMKey(%v) = %v; want nil", key, err)
+	}
+}
+
+func TestXAES256GCMDoesSupport(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	if !km.DoesSupport(testutil.XAES256GCMTypeURL) {
+		t.Errorf("XAES256GCMKeyManager must support %s", testutil.XAES256GCMTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("XAES256GCMKeyManager must only support %s", testutil.XAES256GCMTypeURL)
+	}
+}
+
+func TestXAES256GCMTypeURL(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.XAES256GCMTypeURL)
+	if err != nil {
+		t.Errorf("cannot obtain XAES256GCM key manager: %s", err)
+	}
+	if kt := km.TypeURL(); kt != testutil.XAES256GCMTypeURL {
+		t.Errorf("km.TypeURL() = %s; want %s", kt, testutil.XAES256GCMTypeURL)
+	}
+}
+
+func genInvalidXAES256GCMKeys() []*xaesgcmpb.XAes256GcmKey {
+	return []*xaesgcmpb.XAes256GcmKey{
+		// Bad key size.
+		&xaesgcmpb.XAes256GcmKey{
+			Version:  testutil.XAES256GCMKeyVersion,
+			KeyValue: random.GetRandomBytes(17),
+		},
+		&xaesgcmpb.XAes256GcmKey{
+			Version:  testutil.XAES256GCMKeyVersion,
+			KeyValue: random.GetRandomBytes(25),
+		},
+		&xaesgcmpb.XAes256GcmKey{
+			Version:  testutil.XAES256GCMKeyVersion,
+			KeyValue: random.GetRandomBytes(33),
+		},
+		// Bad version.
+		&xaesgcmpb.XAes256GcmKey{
+			Version:  testutil.XAES256GCMKeyVersion + 1,
+			KeyValue: random.GetRandomBytes(subtle.XAES256GCMKeySize),
+		},
+	}
+}
+
+func validateXAES256GCMPrimitive(p interface{}, key *xaesgcmpb.XAes256GcmKey) error {
+	cipher := p.(*subtle.XAES256GCM)
+	if !bytes.Equal(cipher.Key, key.KeyValue) {
+		return fmt.Errorf("key and primitive don't match")
+	}
+
+	// Try to encrypt and decrypt.
+	pt := random.GetRandomBytes(32)
+	aad := random.GetRandomBytes(32)
+	ct, err := cipher.Encrypt(pt, aad)
+	if err != nil {
+		return fmt.Errorf("encryption failed")
+	}
+	decrypted, err := cipher.Decrypt(ct, aad)
+	if err != nil {
+		return fmt.Errorf("decryption failed")
+	}
+	if !bytes.Equal(decrypted, pt) {
+		return fmt.Errorf("decryption failed")
+	}
+	return nil
+}
+
+func validateXAES256GCMKey(key *xaesgcmpb.XAes256GcmKey) error {
+	if key.Version != testutil.XAES256GCMKeyVersion {
+		return fmt.Errorf("incorrect key version: keyVersion != %d", testutil.XAES256GCMKeyVersion)
+	}
+	if uint32(len(key.KeyValue)) != subtle.XAES256GCMKeySize {
+		return fmt.Errorf("incorrect key size: keySize != %d", subtle.XAES256GCMKeySize)
+	}
+
+	// Try to encrypt and decrypt.
+	p, err := subtle.NewXAES256GCM(key.KeyValue)
+	if err != nil {
+		return fmt.Errorf("invalid key: %v", key.KeyValue)
+	}
+	return validateXAES256GCMPrimitive(p, key)
+}
@@ -18,6 +18,7 @@ package aead
 
 import (
 	"fmt"
+	"io"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"github.com/golang/protobuf/proto"
@@ -45,6 +46,10 @@ type xChaCha20Poly1305KeyManager struct{}
 // Assert that xChaCha20Poly1305KeyManager implements the KeyManager interface.
 var _ registry.KeyManager = (*xChaCha20Poly1305KeyManager)(nil)
 
+// Assert that xChaCha20Poly1305KeyManager implements the DerivableKeyManager
+// interface.
+var _ registry.DerivableKeyManager = (*xChaCha20Poly1305KeyManager)(nil)
+
 // newXChaCha20Poly1305KeyManager creates a new xChaCha20Poly1305KeyManager.
 func newXChaCha20Poly1305KeyManager() *xChaCha20Poly1305KeyManager {
 	return new(xChaCha20Poly1305KeyManager)
@@ -75,6 +80,19 @@ func (km *xChaCha20Poly1305KeyManager) NewKey(serializedKeyFormat []byte) (proto
 	return km.newXChaCha20Poly1305Key(), nil
 }
 
+// DeriveKey derives a new XChaCha20Poly1305Key, using the bytes read from
+// pseudorandomness as key material. serializedKeyFormat is ignored.
+func (km *xChaCha20Poly1305KeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	keyValue := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("xchacha20poly1305_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &xcppb.XChaCha20Poly1305Key{
+		Version:  xChaCha20Poly1305KeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
 // NewKeyData creates a new KeyData, ignoring the specification in the given serialized key format
 // because the key size and other params are fixed.
 // It should be used solely by the key management API.
@@ -0,0 +1,190 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package blindindex computes blind indexes, which allow to look up rows of a
+// database by the value of an encrypted field without decrypting it.
+//
+// A blind index is a short keyed hash of a field value that is stored next to
+// the ciphertext of the value. To find the rows with a given value, compute
+// its blind index and query for rows with an equal index. The index is
+// computed with a PRF or deterministic AEAD keyset, which should be
+// different from the keyset that encrypts the field.
+//
+// Each field is hashed with its own salt, which is derived from the keyset
+// and the field name, so that equal values in different fields have unrelated
+// indexes. Like deterministic encryption, blind indexes reveal which rows have
+// equal values in a field. Shorter indexes leak less, at the cost of false
+// positives that have to be filtered out after decryption.
+package blindindex
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// MinIndexSize is the minimal size of blind indexes in bytes.
+	MinIndexSize = 4
+	// MaxIndexSize is the maximal size of blind indexes in bytes.
+	MaxIndexSize = 16
+
+	saltSize = 16
+
+	saltInput  = 0
+	valueInput = 1
+)
+
+// Indexer computes blind indexes of field values.
+type Indexer struct {
+	size    uint32
+	primary *indexKey
+	// keys are the keys of the keyset, with the primary key first.
+	keys []*indexKey
+}
+
+// New returns an Indexer that computes blind indexes of the given size in
+// bytes with the keys of the given keyset handle. The keyset must contain PRF
+// or deterministic AEAD keys.
+func New(h *keyset.Handle, size uint32) (*Indexer, error) {
+	if size < MinIndexSize || size > MaxIndexSize {
+		return nil, fmt.Errorf("blindindex: index size must be between %d and %d bytes, got %d", MinIndexSize, MaxIndexSize, size)
+	}
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("blindindex: cannot obtain primitive set: %s", err)
+	}
+	return newIndexer(ps, size)
+}
+
+func newIndexer(ps *primitiveset.PrimitiveSet, size uint32) (*Indexer, error) {
+	var entries []*primitiveset.Entry
+	for _, e := range ps.Entries {
+		entries = append(entries, e...)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i] == ps.Primary || entries[j] == ps.Primary {
+			return entries[i] == ps.Primary
+		}
+		return entries[i].KeyID < entries[j].KeyID
+	})
+	x := &Indexer{size: size}
+	for _, e := range entries {
+		k := &indexKey{}
+		switch p := e.Primitive.(type) {
+		case prf.PRF:
+			k.compute = func(input []byte) ([]byte, error) {
+				return p.ComputePRF(input, MaxIndexSize)
+			}
+		case tink.DeterministicAEAD:
+			// The raw ciphertext is a deterministic function of the key and the
+			// input, which is compressed to an index of fixed size.
+			k.compute = func(input []byte) ([]byte, error) {
+				ct, err := p.EncryptDeterministically(input, nil)
+				if err != nil {
+					return nil, err
+				}
+				h := sha256.Sum256(ct)
+				return h[:MaxIndexSize], nil
+			}
+		default:
+			return nil, errors.New("blindindex: not a PRF or DeterministicAEAD primitive")
+		}
+		if e == ps.Primary {
+			x.primary = k
+		}
+		x.keys = append(x.keys, k)
+	}
+	if x.primary == nil {
+		return nil, errors.New("blindindex: keyset has no primary key")
+	}
+	return x, nil
+}
+
+// Index returns the blind index of value in the given field, computed with
+// the primary key. Store it next to the encrypted value.
+func (x *Indexer) Index(field string, value []byte) ([]byte, error) {
+	return x.primary.index(field, value, x.size)
+}
+
+// LookupIndexes returns the blind indexes of value in the given field under
+// all keys of the keyset, starting with the primary key. A row matches value
+// if its index is one of them, which allows lookups while stored indexes are
+// being recomputed after a key rotation.
+func (x *Indexer) LookupIndexes(field string, value []byte) ([][]byte, error) {
+	indexes := make([][]byte, 0, len(x.keys))
+	seen := make(map[string]bool)
+	for _, k := range x.keys {
+		idx, err := k.index(field, value, x.size)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[string(idx)] {
+			seen[string(idx)] = true
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes, nil
+}
+
+// indexKey computes indexes with one key of the keyset.
+type indexKey struct {
+	compute func(input []byte) ([]byte, error)
+	// salts maps field names to their salts.
+	salts sync.Map
+}
+
+func (k *indexKey) index(field string, value []byte, size uint32) ([]byte, error) {
+	salt, err := k.salt(field)
+	if err != nil {
+		return nil, err
+	}
+	input := make([]byte, 0, 1+len(salt)+len(value))
+	input = append(input, valueInput)
+	input = append(input, salt...)
+	input = append(input, value...)
+	out, err := k.compute(input)
+	if err != nil {
+		return nil, fmt.Errorf("blindindex: %s", err)
+	}
+	return out[:size], nil
+}
+
+// salt returns the salt of the given field, which is derived from the key.
+func (k *indexKey) salt(field string) ([]byte, error) {
+	if s, ok := k.salts.Load(field); ok {
+		return s.([]byte), nil
+	}
+	input := make([]byte, 9, 9+len(field))
+	input[0] = saltInput
+	binary.BigEndian.PutUint64(input[1:], uint64(len(field)))
+	input = append(input, field...)
+	out, err := k.compute(input)
+	if err != nil {
+		return nil, fmt.Errorf("blindindex: %s", err)
+	}
+	salt := out[:saltSize]
+	k.salts.Store(field, salt)
+	return salt, nil
+}
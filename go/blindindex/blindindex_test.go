@@ -0,0 +1,189 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package blindindex_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/blindindex"
+	"github.com/google/tink/go/daead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestIndex(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"HMAC-SHA256-PRF", prf.HMACSHA256PRFKeyTemplate()},
+		{"HKDF-SHA256-PRF", prf.HKDFSHA256PRFKeyTemplate()},
+		{"AES-CMAC-PRF", prf.AESCMACPRFKeyTemplate()},
+		{"AES-SIV", daead.AESSIVKeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			x, err := blindindex.New(kh, 8)
+			if err != nil {
+				t.Fatalf("blindindex.New() err = %v", err)
+			}
+			idx, err := x.Index("email", []byte("alice@example.com"))
+			if err != nil {
+				t.Fatalf("x.Index() err = %v", err)
+			}
+			if len(idx) != 8 {
+				t.Errorf("len(idx) = %d, want 8", len(idx))
+			}
+			again, err := x.Index("email", []byte("alice@example.com"))
+			if err != nil {
+				t.Fatalf("x.Index() err = %v", err)
+			}
+			if !bytes.Equal(idx, again) {
+				t.Errorf("x.Index() is not deterministic: %x != %x", idx, again)
+			}
+			other, err := x.Index("email", []byte("bob@example.com"))
+			if err != nil {
+				t.Fatalf("x.Index() err = %v", err)
+			}
+			if bytes.Equal(idx, other) {
+				t.Errorf("different values have the same index %x", idx)
+			}
+			otherField, err := x.Index("name", []byte("alice@example.com"))
+			if err != nil {
+				t.Fatalf("x.Index() err = %v", err)
+			}
+			if bytes.Equal(idx, otherField) {
+				t.Errorf("different fields have the same index %x", idx)
+			}
+		})
+	}
+}
+
+func TestIndexDependsOnKey(t *testing.T) {
+	var indexes [][]byte
+	for i := 0; i < 2; i++ {
+		kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v", err)
+		}
+		x, err := blindindex.New(kh, blindindex.MaxIndexSize)
+		if err != nil {
+			t.Fatalf("blindindex.New() err = %v", err)
+		}
+		idx, err := x.Index("field", []byte("value"))
+		if err != nil {
+			t.Fatalf("x.Index() err = %v", err)
+		}
+		indexes = append(indexes, idx)
+	}
+	if bytes.Equal(indexes[0], indexes[1]) {
+		t.Errorf("different keys give the same index %x", indexes[0])
+	}
+}
+
+func TestIndexSizeIsPrefix(t *testing.T) {
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	short, err := blindindex.New(kh, blindindex.MinIndexSize)
+	if err != nil {
+		t.Fatalf("blindindex.New() err = %v", err)
+	}
+	long, err := blindindex.New(kh, blindindex.MaxIndexSize)
+	if err != nil {
+		t.Fatalf("blindindex.New() err = %v", err)
+	}
+	s, err := short.Index("field", []byte("value"))
+	if err != nil {
+		t.Fatalf("short.Index() err = %v", err)
+	}
+	l, err := long.Index("field", []byte("value"))
+	if err != nil {
+		t.Fatalf("long.Index() err = %v", err)
+	}
+	if !bytes.HasPrefix(l, s) {
+		t.Errorf("index of size %d (%x) is not a prefix of index of size %d (%x)", len(s), s, len(l), l)
+	}
+}
+
+func TestLookupIndexesAfterRotation(t *testing.T) {
+	oldHandle, err := keyset.NewHandle(daead.AESSIVKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	old, err := blindindex.New(oldHandle, 8)
+	if err != nil {
+		t.Fatalf("blindindex.New() err = %v", err)
+	}
+	oldIdx, err := old.Index("ssn", []byte("123-45-6789"))
+	if err != nil {
+		t.Fatalf("old.Index() err = %v", err)
+	}
+
+	manager := keyset.NewManagerFromHandle(oldHandle)
+	if err := manager.Rotate(daead.AESSIVKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() err = %v", err)
+	}
+	kh, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	x, err := blindindex.New(kh, 8)
+	if err != nil {
+		t.Fatalf("blindindex.New() err = %v", err)
+	}
+	newIdx, err := x.Index("ssn", []byte("123-45-6789"))
+	if err != nil {
+		t.Fatalf("x.Index() err = %v", err)
+	}
+	if bytes.Equal(newIdx, oldIdx) {
+		t.Errorf("index did not change after rotation")
+	}
+	got, err := x.LookupIndexes("ssn", []byte("123-45-6789"))
+	if err != nil {
+		t.Fatalf("x.LookupIndexes() err = %v", err)
+	}
+	if len(got) != 2 || !bytes.Equal(got[0], newIdx) || !bytes.Equal(got[1], oldIdx) {
+		t.Errorf("x.LookupIndexes() = %x, want [%x %x]", got, newIdx, oldIdx)
+	}
+}
+
+func TestNewWithInvalidInput(t *testing.T) {
+	kh, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	for _, size := range []uint32{0, blindindex.MinIndexSize - 1, blindindex.MaxIndexSize + 1} {
+		if _, err := blindindex.New(kh, size); err == nil {
+			t.Errorf("blindindex.New() with size %d succeeded", size)
+		}
+	}
+	aeadHandle, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	if _, err := blindindex.New(aeadHandle, 8); err == nil {
+		t.Errorf("blindindex.New() with an AEAD keyset succeeded")
+	}
+}
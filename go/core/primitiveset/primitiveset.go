@@ -23,7 +23,9 @@
 package primitiveset
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/tink/go/core/cryptofmt"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
@@ -37,6 +39,25 @@ type Entry struct {
 	Prefix     string
 	PrefixType tinkpb.OutputPrefixType
 	Status     tinkpb.KeyStatusType
+	// TypeURL is the type URL of the key of the primitive.
+	TypeURL string
+	// Expiration is the time after which the key must not be used for new
+	// computations, or the zero time if the key doesn't expire.
+	Expiration time.Time
+}
+
+// ErrKeyExpired is returned by primitives when a new computation would use an
+// expired key. Use errors.Is to test for it.
+var ErrKeyExpired = errors.New("primitive_set: key expired")
+
+// CheckNotExpired returns an error wrapping ErrKeyExpired if the key of e has
+// expired. Wrapped primitives call it before computing new ciphertexts, MACs
+// or signatures with the primary key; decryption and verification don't.
+func (e *Entry) CheckNotExpired() error {
+	if !e.Expiration.IsZero() && !time.Now().Before(e.Expiration) {
+		return fmt.Errorf("%w: key %d expired at %s", ErrKeyExpired, e.KeyID, e.Expiration.Format(time.RFC3339))
+	}
+	return nil
 }
 
 func newEntry(keyID uint32, p interface{}, prefix string, prefixType tinkpb.OutputPrefixType, status tinkpb.KeyStatusType) *Entry {
@@ -68,6 +89,10 @@ type PrimitiveSet struct {
 	// primitives sharing the prefix). This allows quickly retrieving the
 	// primitives sharing some particular prefix.
 	Entries map[string][]*Entry
+
+	// Annotations of the keyset handle the set was created from, e.g. for
+	// monitoring.
+	Annotations map[string]string
 }
 
 // New returns an empty instance of PrimitiveSet.
@@ -105,6 +130,7 @@ func (ps *PrimitiveSet) Add(p interface{}, key *tinkpb.Keyset_Key) (*Entry, erro
 		return nil, fmt.Errorf("primitive_set: %s", err)
 	}
 	e := newEntry(key.KeyId, p, prefix, key.OutputPrefixType, key.Status)
+	e.TypeURL = key.GetKeyData().GetTypeUrl()
 	ps.Entries[prefix] = append(ps.Entries[prefix], e)
 	return e, nil
 }
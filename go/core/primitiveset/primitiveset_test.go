@@ -17,9 +17,11 @@
 package primitiveset_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/tink/go/core/cryptofmt"
 	"github.com/google/tink/go/core/primitiveset"
@@ -175,3 +177,26 @@ func validateEntry(entry *primitiveset.Entry,
 	}
 	return true
 }
+
+func TestEntryCheckNotExpired(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		expiration time.Time
+		expired    bool
+	}{
+		{"no expiration", time.Time{}, false},
+		{"future", time.Now().Add(time.Hour), false},
+		{"past", time.Now().Add(-time.Hour), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &primitiveset.Entry{KeyID: 42, Expiration: tc.expiration}
+			err := e.CheckNotExpired()
+			if got := errors.Is(err, primitiveset.ErrKeyExpired); got != tc.expired {
+				t.Errorf("errors.Is(e.CheckNotExpired(), ErrKeyExpired) = %v, want %v (err = %v)", got, tc.expired, err)
+			}
+			if !tc.expired && err != nil {
+				t.Errorf("e.CheckNotExpired() failed: %v", err)
+			}
+		})
+	}
+}
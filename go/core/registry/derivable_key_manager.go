@@ -0,0 +1,35 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// DerivableKeyManager is a special type of KeyManager that can deterministically
+// derive keys from a source of pseudorandomness, e.g. to derive keysets with
+// package keyderivation.
+type DerivableKeyManager interface {
+	KeyManager
+
+	// DeriveKey derives a new key according to specification in
+	// serializedKeyFormat, using the bytes read from pseudorandomness as key
+	// material.
+	DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error)
+}
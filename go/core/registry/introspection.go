@@ -0,0 +1,130 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// tinkPackagePrefix is the import path prefix of the Tink packages.
+const tinkPackagePrefix = "github.com/google/tink/go/"
+
+// KeyManagerInfo describes a registered key manager.
+type KeyManagerInfo struct {
+	// TypeURL is the type URL of the keys of the key manager.
+	TypeURL string
+	// Primitive is the name of the primitive interface the key manager
+	// creates, e.g. "AEAD" or "Signer". It is empty for key managers outside
+	// of Tink, since it cannot be determined without a key.
+	Primitive string
+	// Package is the import path of the package which implements the key
+	// manager.
+	Package string
+	// ModuleVersion is the version of the Go module of Package, as recorded in
+	// the binary, e.g. "v1.6.1" or "(devel)". It is empty if it is unknown,
+	// for example in binaries built without module support.
+	ModuleVersion string
+	// Private is whether the key manager implements PrivateKeyManager.
+	Private bool
+	// Derivable is whether the key manager implements DerivableKeyManager.
+	Derivable bool
+}
+
+// tinkPrimitives maps the Tink packages to the primitives created by their
+// key managers, for public and private keys.
+var tinkPrimitives = map[string][2]string{
+	"aead":           {"AEAD", "AEAD"},
+	"daead":          {"DeterministicAEAD", "DeterministicAEAD"},
+	"hybrid":         {"HybridEncrypt", "HybridDecrypt"},
+	"keyderivation":  {"KeysetDeriver", "KeysetDeriver"},
+	"mac":            {"MAC", "MAC"},
+	"prf":            {"PRF", "PRF"},
+	"signature":      {"Verifier", "Signer"},
+	"streamingaead":  {"StreamingAEAD", "StreamingAEAD"},
+	"streamingdaead": {"StreamingDeterministicAEAD", "StreamingDeterministicAEAD"},
+}
+
+// RegisteredKeyManagers describes the key managers in the global registry,
+// sorted by type URL. Operational tooling can use it to check at startup that
+// the expected algorithms are available.
+func RegisteredKeyManagers() []KeyManagerInfo {
+	return global.KeyManagers()
+}
+
+// KeyManagers describes the key managers in r, sorted by type URL.
+func (r *Registry) KeyManagers() []KeyManagerInfo {
+	r.mu.RLock()
+	kms := make([]KeyManager, 0, len(r.keyManagers))
+	for _, km := range r.keyManagers {
+		kms = append(kms, km)
+	}
+	r.mu.RUnlock()
+
+	buildInfo, _ := debug.ReadBuildInfo()
+	infos := make([]KeyManagerInfo, 0, len(kms))
+	for _, km := range kms {
+		t := reflect.TypeOf(km)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		info := KeyManagerInfo{
+			TypeURL:       km.TypeURL(),
+			Package:       t.PkgPath(),
+			ModuleVersion: moduleVersion(buildInfo, t.PkgPath()),
+		}
+		_, info.Private = km.(PrivateKeyManager)
+		_, info.Derivable = km.(DerivableKeyManager)
+		if strings.HasPrefix(info.Package, tinkPackagePrefix) {
+			primitives := tinkPrimitives[strings.TrimPrefix(info.Package, tinkPackagePrefix)]
+			info.Primitive = primitives[0]
+			if info.Private {
+				info.Primitive = primitives[1]
+			}
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].TypeURL < infos[j].TypeURL })
+	return infos
+}
+
+// moduleVersion returns the version of the module which contains the package
+// with the given import path, or "" if it is unknown.
+func moduleVersion(buildInfo *debug.BuildInfo, pkgPath string) string {
+	if buildInfo == nil {
+		return ""
+	}
+	var version string
+	longest := -1
+	modules := append([]*debug.Module{&buildInfo.Main}, buildInfo.Deps...)
+	for _, m := range modules {
+		if m == nil || m.Path == "" || len(m.Path) <= longest {
+			continue
+		}
+		if pkgPath != m.Path && !strings.HasPrefix(pkgPath, m.Path+"/") {
+			continue
+		}
+		longest = len(m.Path)
+		version = m.Version
+		if m.Replace != nil && m.Replace.Version != "" {
+			version = m.Replace.Version
+		}
+	}
+	return version
+}
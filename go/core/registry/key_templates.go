@@ -0,0 +1,94 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+var (
+	keyTemplatesMu sync.RWMutex
+	keyTemplates   = make(map[string]func() *tinkpb.KeyTemplate) // name -> key template
+)
+
+// RegisterKeyTemplate registers the key template returned by kt under the
+// given name, e.g. "AES256_GCM", so that configuration files and command line
+// tools can refer to it by name. The packages of the primitives register their
+// key templates under the names that Tinkey uses.
+// Does not allow to overwrite existing key templates.
+func RegisterKeyTemplate(name string, kt func() *tinkpb.KeyTemplate) error {
+	if name == "" || kt == nil {
+		return fmt.Errorf("registry.RegisterKeyTemplate: name and key template must not be empty")
+	}
+	keyTemplatesMu.Lock()
+	defer keyTemplatesMu.Unlock()
+	if _, existed := keyTemplates[name]; existed {
+		return fmt.Errorf("registry.RegisterKeyTemplate: key template %q has already been registered", name)
+	}
+	keyTemplates[name] = kt
+	return nil
+}
+
+// KeyTemplateFromName returns the key template registered under the given
+// name, e.g. "AES256_GCM".
+func KeyTemplateFromName(name string) (*tinkpb.KeyTemplate, error) {
+	keyTemplatesMu.RLock()
+	kt, existed := keyTemplates[name]
+	keyTemplatesMu.RUnlock()
+	if !existed {
+		return nil, fmt.Errorf("registry.KeyTemplateFromName: unknown key template %q", name)
+	}
+	return kt(), nil
+}
+
+// KeyTemplateName returns the name under which kt is registered. The key
+// template must match a registered one exactly, i.e. have the same type URL,
+// output prefix type and serialized key format.
+func KeyTemplateName(kt *tinkpb.KeyTemplate) (string, error) {
+	if kt == nil {
+		return "", fmt.Errorf("registry.KeyTemplateName: key template must not be nil")
+	}
+	for _, name := range KeyTemplateNames() {
+		registered, err := KeyTemplateFromName(name)
+		if err != nil {
+			continue
+		}
+		if registered.GetTypeUrl() == kt.GetTypeUrl() &&
+			registered.GetOutputPrefixType() == kt.GetOutputPrefixType() &&
+			bytes.Equal(registered.GetValue(), kt.GetValue()) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("registry.KeyTemplateName: no key template registered for type URL %q", kt.GetTypeUrl())
+}
+
+// KeyTemplateNames returns the names of the registered key templates, sorted.
+func KeyTemplateNames() []string {
+	keyTemplatesMu.RLock()
+	defer keyTemplatesMu.RUnlock()
+	names := make([]string, 0, len(keyTemplates))
+	for name := range keyTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
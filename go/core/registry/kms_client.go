@@ -16,7 +16,11 @@
 
 package registry
 
-import "github.com/google/tink/go/tink"
+import (
+	"context"
+
+	"github.com/google/tink/go/tink"
+)
 
 // KMSClient knows how to produce primitives backed by keys stored in remote KMS services.
 type KMSClient interface {
@@ -26,3 +30,8 @@ type KMSClient interface {
 	// GetAEAD  gets an AEAD backend by keyURI.
 	GetAEAD(keyURI string) (tink.AEAD, error)
 }
+
+// KMSClientFactory creates a KMS client for the given key URI when the client
+// is first needed, e.g. resolving the credentials for the key. ctx is the
+// context of the operation which needs the client.
+type KMSClientFactory func(ctx context.Context, keyURI string) (KMSClient, error)
@@ -0,0 +1,194 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/tink/go/tink"
+)
+
+// KMSClientCacheConfig configures the cache of NewCachingKMSClientFactory.
+type KMSClientCacheConfig struct {
+	// MaxEntries is the maximal number of key URIs whose clients are kept.
+	// When the cache is full, the client of the least recently used key URI is
+	// evicted. It must be positive.
+	MaxEntries int
+
+	// TTL is how long a client, and the AEADs it returned, are reused after the
+	// client was created. It must be positive.
+	TTL time.Duration
+
+	// CacheKey, if set, derives a key from the context of a call, such as the
+	// tenant or the credentials the context carries, and clients are cached
+	// per key URI and cache key. If it is nil, clients are cached per key URI
+	// alone.
+	CacheKey func(ctx context.Context) string
+}
+
+// NewCachingKMSClientFactory returns a KMSClientFactory which caches the
+// clients that f creates per key URI, as well as the AEADs the clients return,
+// so that loading keysets encrypted with the same key doesn't repeat the
+// authentication and bootstrapping of the client:
+//
+//	f, err := registry.NewCachingKMSClientFactory(newClient, registry.KMSClientCacheConfig{
+//		MaxEntries: 100,
+//		TTL:        time.Hour,
+//	})
+//	...
+//	registry.RegisterKMSClientFactory("gcp-kms://", f)
+//
+// The context of the call which creates a client is passed to f, and the
+// client is then returned to all calls for the same key URI, whatever their
+// context. Without a CacheKey, the cache is therefore only suitable for
+// factories whose clients don't depend on the context, e.g. on credentials or
+// a tenant it carries; otherwise set CacheKey so that such calls get separate
+// clients.
+//
+// Errors of f are not cached. The returned factory is safe for concurrent
+// use.
+func NewCachingKMSClientFactory(f KMSClientFactory, config KMSClientCacheConfig) (KMSClientFactory, error) {
+	if f == nil {
+		return nil, errors.New("registry.NewCachingKMSClientFactory: factory must not be nil")
+	}
+	if config.MaxEntries <= 0 {
+		return nil, errors.New("registry.NewCachingKMSClientFactory: cache size must be positive")
+	}
+	if config.TTL <= 0 {
+		return nil, errors.New("registry.NewCachingKMSClientFactory: cache TTL must be positive")
+	}
+	c := &kmsClientCache{
+		factory: f,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[kmsClientCacheKey]*list.Element),
+		lru:     list.New(),
+	}
+	return c.get, nil
+}
+
+// kmsClientCache holds the clients created by a KMSClientFactory. It is safe
+// for concurrent use.
+type kmsClientCache struct {
+	factory KMSClientFactory
+	config  KMSClientCacheConfig
+	now     func() time.Time
+
+	mu sync.Mutex
+	// entries maps cache keys to elements of lru, whose values are
+	// *cachedKMSClient. The front of lru is the most recently used client.
+	entries map[kmsClientCacheKey]*list.Element
+	lru     *list.List
+}
+
+// kmsClientCacheKey identifies the clients in a kmsClientCache.
+type kmsClientCacheKey struct {
+	keyURI string
+	// ctxKey is the key that KMSClientCacheConfig.CacheKey derived from the
+	// context, if it is set.
+	ctxKey string
+}
+
+// get returns the cached client of keyURI and ctx, or creates one with the
+// factory if there is none or it has expired.
+func (c *kmsClientCache) get(ctx context.Context, keyURI string) (KMSClient, error) {
+	key := kmsClientCacheKey{keyURI: keyURI}
+	if c.config.CacheKey != nil {
+		key.ctxKey = c.config.CacheKey(ctx)
+	}
+	if client := c.cached(key); client != nil {
+		return client, nil
+	}
+	// The factory is called without holding the lock, as it may talk to the
+	// KMS. Concurrent calls for the same key may both create a client; the
+	// last one is kept.
+	client, err := c.factory(ctx, keyURI)
+	if err != nil {
+		return nil, err
+	}
+	cached := &cachedKMSClient{
+		KMSClient: client,
+		key:       key,
+		aeads:     make(map[string]tink.AEAD),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached.expiry = c.now().Add(c.config.TTL)
+	if e, ok := c.entries[key]; ok {
+		c.lru.Remove(e)
+	}
+	for c.lru.Len() >= c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedKMSClient).key)
+	}
+	c.entries[key] = c.lru.PushFront(cached)
+	return cached, nil
+}
+
+// cached returns the client with the given key, or nil if it is not cached or
+// has expired.
+func (c *kmsClientCache) cached(key kmsClientCacheKey) *cachedKMSClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	client := e.Value.(*cachedKMSClient)
+	if !c.now().Before(client.expiry) {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return client
+}
+
+// cachedKMSClient is a KMSClient in a kmsClientCache, which also caches the
+// AEADs it returns until the client expires.
+type cachedKMSClient struct {
+	KMSClient
+	key    kmsClientCacheKey
+	expiry time.Time
+
+	mu    sync.Mutex
+	aeads map[string]tink.AEAD // key URI -> AEAD
+}
+
+// GetAEAD returns the AEAD of keyURI, reusing the one returned before if
+// there is one.
+func (c *cachedKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	c.mu.Lock()
+	a, ok := c.aeads[keyURI]
+	c.mu.Unlock()
+	if ok {
+		return a, nil
+	}
+	a, err := c.KMSClient.GetAEAD(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.aeads[keyURI] = a
+	return a, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"fmt"
+	"sync"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+var (
+	keyTypePolicyMu sync.RWMutex
+	keyTypePolicy   KeyTypePolicy
+)
+
+// KeyTypePolicy decides which keys may be used in the process, so that
+// security teams can enforce an algorithm policy centrally.
+type KeyTypePolicy interface {
+	// CheckKey returns an error if no primitive may be created for key. It is
+	// called for every enabled key when the primitives of a keyset handle are
+	// created.
+	CheckKey(key *tinkpb.Keyset_Key) error
+
+	// CheckTemplate returns an error if no key may be generated from
+	// template.
+	CheckTemplate(template *tinkpb.KeyTemplate) error
+}
+
+// SetKeyTypePolicy sets the policy which is enforced when primitives are
+// created from keyset handles and when keys are generated, by all registries.
+// A nil policy allows all keys, which is the default.
+//
+// The policy should be set at startup, before any primitives are created.
+// Primitives which exist when it is set are not affected.
+func SetKeyTypePolicy(p KeyTypePolicy) {
+	keyTypePolicyMu.Lock()
+	defer keyTypePolicyMu.Unlock()
+	keyTypePolicy = p
+}
+
+// CheckKey checks key against the policy set with SetKeyTypePolicy.
+func CheckKey(key *tinkpb.Keyset_Key) error {
+	keyTypePolicyMu.RLock()
+	p := keyTypePolicy
+	keyTypePolicyMu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckKey(key); err != nil {
+		return fmt.Errorf("registry: key %d of type %s not allowed by policy: %w", key.GetKeyId(), key.GetKeyData().GetTypeUrl(), err)
+	}
+	return nil
+}
+
+// CheckTemplate checks template against the policy set with
+// SetKeyTypePolicy.
+func CheckTemplate(template *tinkpb.KeyTemplate) error {
+	keyTypePolicyMu.RLock()
+	p := keyTypePolicy
+	keyTypePolicyMu.RUnlock()
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckTemplate(template); err != nil {
+		return fmt.Errorf("registry: key template of type %s not allowed by policy: %w", template.GetTypeUrl(), err)
+	}
+	return nil
+}
+
+// KeyTypeRules is a KeyTypePolicy made of allow and deny lists. Policies
+// which depend on key parameters, such as the key size, have to implement
+// KeyTypePolicy themselves.
+type KeyTypeRules struct {
+	// AllowedTypeURLs are the only type URLs allowed, if not empty.
+	AllowedTypeURLs []string
+	// DeniedTypeURLs are type URLs which are not allowed.
+	DeniedTypeURLs []string
+	// DeniedOutputPrefixTypes are output prefix types which are not allowed,
+	// e.g. LEGACY.
+	DeniedOutputPrefixTypes []tinkpb.OutputPrefixType
+}
+
+var _ KeyTypePolicy = (*KeyTypeRules)(nil)
+
+// CheckKey implements KeyTypePolicy.
+func (r *KeyTypeRules) CheckKey(key *tinkpb.Keyset_Key) error {
+	return r.check(key.GetKeyData().GetTypeUrl(), key.GetOutputPrefixType())
+}
+
+// CheckTemplate implements KeyTypePolicy.
+func (r *KeyTypeRules) CheckTemplate(template *tinkpb.KeyTemplate) error {
+	return r.check(template.GetTypeUrl(), template.GetOutputPrefixType())
+}
+
+func (r *KeyTypeRules) check(typeURL string, outputPrefixType tinkpb.OutputPrefixType) error {
+	if len(r.AllowedTypeURLs) > 0 && !containsString(r.AllowedTypeURLs, typeURL) {
+		return fmt.Errorf("type URL %s is not allowed", typeURL)
+	}
+	if containsString(r.DeniedTypeURLs, typeURL) {
+		return fmt.Errorf("type URL %s is denied", typeURL)
+	}
+	for _, t := range r.DeniedOutputPrefixTypes {
+		if t == outputPrefixType {
+			return fmt.Errorf("output prefix type %s is denied", outputPrefixType)
+		}
+	}
+	return nil
+}
+
+func containsString(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
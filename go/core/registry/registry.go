@@ -31,7 +31,10 @@
 package registry
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -39,30 +42,75 @@ import (
 )
 
 var (
-	keyManagersMu sync.RWMutex
-	keyManagers   = make(map[string]KeyManager) // typeURL -> KeyManager
-	kmsClientsMu  sync.RWMutex
-	kmsClients    = []KMSClient{}
+	global       = New()
+	kmsClientsMu sync.RWMutex
+	kmsClients   = []KMSClient{}
+	kmsFactories = make(map[string]KMSClientFactory) // key URI prefix -> KMSClientFactory
+	wrappersMu   sync.RWMutex
+	wrappers     = make(map[reflect.Type]interface{}) // primitive type -> PrimitiveWrapper
 )
 
+// Registry holds key managers, keyed by the type URL of their keys. The
+// package-level functions use a global Registry, which the packages of the
+// primitives register their key managers with. Libraries which are embedded
+// in larger binaries can create their own Registry to accept only some key
+// types, without affecting the global one, and pass it to the
+// NewWithRegistry factories of the primitives.
+type Registry struct {
+	mu          sync.RWMutex
+	keyManagers map[string]KeyManager // typeURL -> KeyManager
+}
+
+// New returns an empty Registry.
+func New() *Registry {
+	return &Registry{keyManagers: make(map[string]KeyManager)}
+}
+
+// NewFromGlobal returns a Registry with the key managers which are registered
+// globally for the given type URLs. It returns an error if one isn't.
+func NewFromGlobal(typeURLs ...string) (*Registry, error) {
+	r := New()
+	for _, typeURL := range typeURLs {
+		km, err := GetKeyManager(typeURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.RegisterKeyManager(km); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
 // RegisterKeyManager registers the given key manager.
 // Does not allow to overwrite existing key managers.
-func RegisterKeyManager(km KeyManager) error {
-	keyManagersMu.Lock()
-	defer keyManagersMu.Unlock()
+func (r *Registry) RegisterKeyManager(km KeyManager) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	typeURL := km.TypeURL()
-	if _, existed := keyManagers[typeURL]; existed {
+	if _, existed := r.keyManagers[typeURL]; existed {
 		return fmt.Errorf("registry.RegisterKeyManager: type %s already registered", typeURL)
 	}
-	keyManagers[typeURL] = km
+	r.keyManagers[typeURL] = km
+	return nil
+}
+
+// UnregisterKeyManager removes the key manager for the given typeURL.
+func (r *Registry) UnregisterKeyManager(typeURL string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, existed := r.keyManagers[typeURL]; !existed {
+		return fmt.Errorf("registry.UnregisterKeyManager: type %s not registered", typeURL)
+	}
+	delete(r.keyManagers, typeURL)
 	return nil
 }
 
 // GetKeyManager returns the key manager for the given typeURL if existed.
-func GetKeyManager(typeURL string) (KeyManager, error) {
-	keyManagersMu.RLock()
-	defer keyManagersMu.RUnlock()
-	km, existed := keyManagers[typeURL]
+func (r *Registry) GetKeyManager(typeURL string) (KeyManager, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	km, existed := r.keyManagers[typeURL]
 	if !existed {
 		return nil, fmt.Errorf("registry.GetKeyManager: unsupported key type: %s", typeURL)
 	}
@@ -70,11 +118,14 @@ func GetKeyManager(typeURL string) (KeyManager, error) {
 }
 
 // NewKeyData generates a new KeyData for the given key template.
-func NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
+func (r *Registry) NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
 	if kt == nil {
 		return nil, fmt.Errorf("registry.NewKeyData: invalid key template")
 	}
-	km, err := GetKeyManager(kt.TypeUrl)
+	if err := CheckTemplate(kt); err != nil {
+		return nil, err
+	}
+	km, err := r.GetKeyManager(kt.TypeUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -82,11 +133,14 @@ func NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
 }
 
 // NewKey generates a new key for the given key template.
-func NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
+func (r *Registry) NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
 	if kt == nil {
 		return nil, fmt.Errorf("registry.NewKey: invalid key template")
 	}
-	km, err := GetKeyManager(kt.TypeUrl)
+	if err := CheckTemplate(kt); err != nil {
+		return nil, err
+	}
+	km, err := r.GetKeyManager(kt.TypeUrl)
 	if err != nil {
 		return nil, err
 	}
@@ -94,48 +148,204 @@ func NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
 }
 
 // PrimitiveFromKeyData creates a new primitive for the key given in the given KeyData.
-func PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
+func (r *Registry) PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
 	if kd == nil {
 		return nil, fmt.Errorf("registry.PrimitiveFromKeyData: invalid key data")
 	}
-	return Primitive(kd.TypeUrl, kd.Value)
+	return r.Primitive(kd.TypeUrl, kd.Value)
 }
 
 // Primitive creates a new primitive for the given serialized key using the KeyManager
 // identified by the given typeURL.
-func Primitive(typeURL string, sk []byte) (interface{}, error) {
+func (r *Registry) Primitive(typeURL string, sk []byte) (interface{}, error) {
 	if len(sk) == 0 {
 		return nil, fmt.Errorf("registry.Primitive: invalid serialized key")
 	}
-	km, err := GetKeyManager(typeURL)
+	km, err := r.GetKeyManager(typeURL)
 	if err != nil {
 		return nil, err
 	}
 	return km.Primitive(sk)
 }
 
+// RegisterKeyManager registers the given key manager.
+// Does not allow to overwrite existing key managers.
+func RegisterKeyManager(km KeyManager) error {
+	return global.RegisterKeyManager(km)
+}
+
+// UnregisterKeyManager removes the key manager for the given typeURL, so that
+// another one can be registered. It is intended for tests of alternative key
+// manager implementations; see also Snapshot.
+func UnregisterKeyManager(typeURL string) error {
+	return global.UnregisterKeyManager(typeURL)
+}
+
+// ReplaceKeyManager registers the given key manager, replacing the one
+// registered for its type URL, if any. Like UnregisterKeyManager, it is
+// intended for tests.
+func ReplaceKeyManager(km KeyManager) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.keyManagers[km.TypeURL()] = km
+}
+
+// Snapshot records the registered key managers, KMS clients, primitive
+// wrappers and key templates, and returns a function that restores them. It
+// lets tests change the registry without affecting other tests in the same
+// process:
+//
+//	restore := registry.Snapshot()
+//	defer restore()
+//	registry.ReplaceKeyManager(fakeKeyManager)
+//
+// Tests that change the registry must not run in parallel with tests that
+// use it.
+func Snapshot() (restore func()) {
+	global.mu.RLock()
+	savedKeyManagers := make(map[string]KeyManager, len(global.keyManagers))
+	for typeURL, km := range global.keyManagers {
+		savedKeyManagers[typeURL] = km
+	}
+	global.mu.RUnlock()
+	kmsClientsMu.RLock()
+	savedKMSClients := append([]KMSClient{}, kmsClients...)
+	savedKMSClientFactories := make(map[string]KMSClientFactory, len(kmsFactories))
+	for prefix, f := range kmsFactories {
+		savedKMSClientFactories[prefix] = f
+	}
+	kmsClientsMu.RUnlock()
+	wrappersMu.RLock()
+	savedWrappers := make(map[reflect.Type]interface{}, len(wrappers))
+	for t, w := range wrappers {
+		savedWrappers[t] = w
+	}
+	wrappersMu.RUnlock()
+	keyTemplatesMu.RLock()
+	savedKeyTemplates := make(map[string]func() *tinkpb.KeyTemplate, len(keyTemplates))
+	for name, kt := range keyTemplates {
+		savedKeyTemplates[name] = kt
+	}
+	keyTemplatesMu.RUnlock()
+	return func() {
+		global.mu.Lock()
+		global.keyManagers = make(map[string]KeyManager, len(savedKeyManagers))
+		for typeURL, km := range savedKeyManagers {
+			global.keyManagers[typeURL] = km
+		}
+		global.mu.Unlock()
+		kmsClientsMu.Lock()
+		kmsClients = append([]KMSClient{}, savedKMSClients...)
+		kmsFactories = make(map[string]KMSClientFactory, len(savedKMSClientFactories))
+		for prefix, f := range savedKMSClientFactories {
+			kmsFactories[prefix] = f
+		}
+		kmsClientsMu.Unlock()
+		wrappersMu.Lock()
+		wrappers = make(map[reflect.Type]interface{}, len(savedWrappers))
+		for t, w := range savedWrappers {
+			wrappers[t] = w
+		}
+		wrappersMu.Unlock()
+		keyTemplatesMu.Lock()
+		keyTemplates = make(map[string]func() *tinkpb.KeyTemplate, len(savedKeyTemplates))
+		for name, kt := range savedKeyTemplates {
+			keyTemplates[name] = kt
+		}
+		keyTemplatesMu.Unlock()
+	}
+}
+
+// GetKeyManager returns the key manager for the given typeURL if existed.
+func GetKeyManager(typeURL string) (KeyManager, error) {
+	return global.GetKeyManager(typeURL)
+}
+
+// NewKeyData generates a new KeyData for the given key template.
+func NewKeyData(kt *tinkpb.KeyTemplate) (*tinkpb.KeyData, error) {
+	return global.NewKeyData(kt)
+}
+
+// NewKey generates a new key for the given key template.
+func NewKey(kt *tinkpb.KeyTemplate) (proto.Message, error) {
+	return global.NewKey(kt)
+}
+
+// PrimitiveFromKeyData creates a new primitive for the key given in the given KeyData.
+func PrimitiveFromKeyData(kd *tinkpb.KeyData) (interface{}, error) {
+	return global.PrimitiveFromKeyData(kd)
+}
+
+// Primitive creates a new primitive for the given serialized key using the KeyManager
+// identified by the given typeURL.
+func Primitive(typeURL string, sk []byte) (interface{}, error) {
+	return global.Primitive(typeURL, sk)
+}
+
 // RegisterKMSClient is used to register a new KMS client
+//
+// Deprecated: use RegisterKMSClientFactory, which creates the client when it
+// is first needed instead of at registration, e.g. at init time.
 func RegisterKMSClient(k KMSClient) {
 	kmsClientsMu.Lock()
 	defer kmsClientsMu.Unlock()
 	kmsClients = append(kmsClients, k)
 }
 
+// RegisterKMSClientFactory registers a factory for the KMS clients of the key
+// URIs which start with prefix, e.g. "gcp-kms://". The factory is called with
+// the key URI each time a client is needed, so that credentials are resolved
+// lazily and per key; factories which are expensive should cache the clients
+// they create. If several prefixes match a key URI, the longest one is used.
+// Registering a factory for a prefix again replaces the previous factory.
+func RegisterKMSClientFactory(prefix string, f KMSClientFactory) {
+	kmsClientsMu.Lock()
+	defer kmsClientsMu.Unlock()
+	kmsFactories[prefix] = f
+}
+
 // GetKMSClient fetches a KMSClient by a given URI.
 func GetKMSClient(keyURI string) (KMSClient, error) {
+	return GetKMSClientWithContext(context.Background(), keyURI)
+}
+
+// GetKMSClientWithContext fetches a KMSClient by a given URI. Clients
+// registered with RegisterKMSClient take precedence; otherwise the client is
+// created with the factory registered for the longest matching prefix of the
+// URI, passing ctx to it.
+func GetKMSClientWithContext(ctx context.Context, keyURI string) (KMSClient, error) {
 	kmsClientsMu.RLock()
-	defer kmsClientsMu.RUnlock()
 	for _, k := range kmsClients {
 		if k.Supported(keyURI) {
+			kmsClientsMu.RUnlock()
 			return k, nil
 		}
 	}
-	return nil, fmt.Errorf("KMS client supporting %s not found", keyURI)
+	var (
+		factory KMSClientFactory
+		longest string
+	)
+	for prefix, f := range kmsFactories {
+		if strings.HasPrefix(keyURI, prefix) && (factory == nil || len(prefix) > len(longest)) {
+			factory, longest = f, prefix
+		}
+	}
+	kmsClientsMu.RUnlock()
+	if factory == nil {
+		return nil, fmt.Errorf("KMS client supporting %s not found", keyURI)
+	}
+	client, err := factory(ctx, keyURI)
+	if err != nil {
+		return nil, fmt.Errorf("registry.GetKMSClient: cannot create KMS client for %s: %w", keyURI, err)
+	}
+	return client, nil
 }
 
-// ClearKMSClients removes all registered KMS clients.
+// ClearKMSClients removes all registered KMS clients and KMS client
+// factories.
 func ClearKMSClients() {
 	kmsClientsMu.Lock()
 	defer kmsClientsMu.Unlock()
 	kmsClients = []KMSClient{}
+	kmsFactories = make(map[string]KMSClientFactory)
 }
@@ -17,15 +17,21 @@
 package registry_test
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/mac/subtle"
 	"github.com/google/tink/go/testing/fakekms"
 	"github.com/google/tink/go/testutil"
+	"github.com/google/tink/go/tink"
 	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
@@ -206,3 +212,518 @@ func TestRegisterKmsClient(t *testing.T) {
 		t.Errorf("registry.GetKMSClient('bad-kms://unknown-prefix') succeeded, want fail")
 	}
 }
+
+func TestRegisterKMSClientFactory(t *testing.T) {
+	restore := registry.Snapshot()
+	defer restore()
+	registry.ClearKMSClients()
+
+	type ctxKey struct{}
+	var gotURIs []string
+	registry.RegisterKMSClientFactory("fake-kms://", func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		if ctx.Value(ctxKey{}) != "credentials" {
+			return nil, errors.New("missing credentials")
+		}
+		gotURIs = append(gotURIs, keyURI)
+		return fakekms.NewClient(keyURI)
+	})
+	wantErr := errors.New("no access")
+	registry.RegisterKMSClientFactory("fake-kms://denied", func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		return nil, wantErr
+	})
+
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "credentials")
+	client, err := registry.GetKMSClientWithContext(ctx, keyURI)
+	if err != nil {
+		t.Fatalf("registry.GetKMSClientWithContext() failed: %v", err)
+	}
+	if !client.Supported(keyURI) {
+		t.Errorf("client.Supported(%q) = false, want true", keyURI)
+	}
+	if len(gotURIs) != 1 || gotURIs[0] != keyURI {
+		t.Errorf("factory called with %q, want [%q]", gotURIs, keyURI)
+	}
+	if _, err := registry.GetKMSClient(keyURI); err == nil {
+		t.Error("registry.GetKMSClient() without credentials in the context succeeded, want error")
+	}
+	if _, err := registry.GetKMSClientWithContext(ctx, "fake-kms://denied-key"); !errors.Is(err, wantErr) {
+		t.Errorf("registry.GetKMSClientWithContext() of the longest prefix err = %v, want %v", err, wantErr)
+	}
+	if _, err := registry.GetKMSClientWithContext(ctx, "other-kms://key"); err == nil {
+		t.Error("registry.GetKMSClientWithContext() of an unknown prefix succeeded, want error")
+	}
+
+	registry.ClearKMSClients()
+	if _, err := registry.GetKMSClientWithContext(ctx, keyURI); err == nil {
+		t.Error("registry.GetKMSClientWithContext() succeeded after ClearKMSClients(), want error")
+	}
+}
+
+// countingKMSClient counts the AEADs it returns.
+type countingKMSClient struct {
+	registry.KMSClient
+	aeads int
+}
+
+func (c *countingKMSClient) GetAEAD(keyURI string) (tink.AEAD, error) {
+	c.aeads++
+	return c.KMSClient.GetAEAD(keyURI)
+}
+
+func TestCachingKMSClientFactory(t *testing.T) {
+	var clients []*countingKMSClient
+	factory := func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		client, err := fakekms.NewClient(keyURI)
+		if err != nil {
+			return nil, err
+		}
+		c := &countingKMSClient{KMSClient: client}
+		clients = append(clients, c)
+		return c, nil
+	}
+	f, err := registry.NewCachingKMSClientFactory(factory, registry.KMSClientCacheConfig{MaxEntries: 1, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("registry.NewCachingKMSClientFactory() failed: %v", err)
+	}
+	keyURI1, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	keyURI2, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		client, err := f(context.Background(), keyURI1)
+		if err != nil {
+			t.Fatalf("f() failed: %v", err)
+		}
+		if _, err := client.GetAEAD(keyURI1); err != nil {
+			t.Fatalf("client.GetAEAD() failed: %v", err)
+		}
+	}
+	if len(clients) != 1 || clients[0].aeads != 1 {
+		t.Fatalf("created %d clients, want 1 client which returned 1 AEAD", len(clients))
+	}
+	// The cache holds a single entry, so keyURI2 evicts the client of keyURI1.
+	if _, err := f(context.Background(), keyURI2); err != nil {
+		t.Fatalf("f() failed: %v", err)
+	}
+	if _, err := f(context.Background(), keyURI1); err != nil {
+		t.Fatalf("f() failed: %v", err)
+	}
+	if len(clients) != 3 {
+		t.Errorf("created %d clients, want 3", len(clients))
+	}
+
+	wantErr := errors.New("no access")
+	failing, err := registry.NewCachingKMSClientFactory(func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		return nil, wantErr
+	}, registry.KMSClientCacheConfig{MaxEntries: 1, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("registry.NewCachingKMSClientFactory() failed: %v", err)
+	}
+	if _, err := failing(context.Background(), keyURI1); err != wantErr {
+		t.Errorf("failing() err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestCachingKMSClientFactoryTTL(t *testing.T) {
+	var created int
+	factory := func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		created++
+		return fakekms.NewClient(keyURI)
+	}
+	f, err := registry.NewCachingKMSClientFactory(factory, registry.KMSClientCacheConfig{MaxEntries: 10, TTL: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("registry.NewCachingKMSClientFactory() failed: %v", err)
+	}
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	if _, err := f(context.Background(), keyURI); err != nil {
+		t.Fatalf("f() failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := f(context.Background(), keyURI); err != nil {
+		t.Fatalf("f() failed: %v", err)
+	}
+	if created != 2 {
+		t.Errorf("created %d clients, want 2 after the first one expired", created)
+	}
+}
+
+type tenantKey struct{}
+
+func TestCachingKMSClientFactoryCacheKey(t *testing.T) {
+	var tenants []string
+	factory := func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		tenants = append(tenants, ctx.Value(tenantKey{}).(string))
+		return fakekms.NewClient(keyURI)
+	}
+	f, err := registry.NewCachingKMSClientFactory(factory, registry.KMSClientCacheConfig{
+		MaxEntries: 10,
+		TTL:        time.Hour,
+		CacheKey: func(ctx context.Context) string {
+			return ctx.Value(tenantKey{}).(string)
+		},
+	})
+	if err != nil {
+		t.Fatalf("registry.NewCachingKMSClientFactory() failed: %v", err)
+	}
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	for _, tenant := range []string{"a", "b", "a", "b"} {
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		if _, err := f(ctx, keyURI); err != nil {
+			t.Fatalf("f() failed: %v", err)
+		}
+	}
+	// Each tenant gets its own client, created with its own context.
+	if want := []string{"a", "b"}; !reflect.DeepEqual(tenants, want) {
+		t.Errorf("created clients for tenants %v, want %v", tenants, want)
+	}
+}
+
+func TestNewCachingKMSClientFactoryInvalidConfig(t *testing.T) {
+	factory := func(ctx context.Context, keyURI string) (registry.KMSClient, error) {
+		return fakekms.NewClient(keyURI)
+	}
+	for _, config := range []registry.KMSClientCacheConfig{
+		{MaxEntries: 0, TTL: time.Hour},
+		{MaxEntries: 1, TTL: 0},
+	} {
+		if _, err := registry.NewCachingKMSClientFactory(factory, config); err == nil {
+			t.Errorf("registry.NewCachingKMSClientFactory(%+v) succeeded, want error", config)
+		}
+	}
+	if _, err := registry.NewCachingKMSClientFactory(nil, registry.KMSClientCacheConfig{MaxEntries: 1, TTL: time.Hour}); err == nil {
+		t.Error("registry.NewCachingKMSClientFactory(nil) succeeded, want error")
+	}
+}
+
+func TestUnregisterAndReplaceKeyManager(t *testing.T) {
+	restore := registry.Snapshot()
+	defer restore()
+	original, err := registry.GetKeyManager(testutil.AESGCMTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() failed: %v", err)
+	}
+
+	if err := registry.UnregisterKeyManager(testutil.AESGCMTypeURL); err != nil {
+		t.Fatalf("registry.UnregisterKeyManager() failed: %v", err)
+	}
+	if _, err := registry.GetKeyManager(testutil.AESGCMTypeURL); err == nil {
+		t.Error("registry.GetKeyManager() of an unregistered type succeeded, want error")
+	}
+	if err := registry.UnregisterKeyManager(testutil.AESGCMTypeURL); err == nil {
+		t.Error("registry.UnregisterKeyManager() of an unregistered type succeeded, want error")
+	}
+	dummy := new(testutil.DummyAEADKeyManager)
+	if err := registry.RegisterKeyManager(dummy); err != nil {
+		t.Fatalf("registry.RegisterKeyManager() after unregistering failed: %v", err)
+	}
+
+	registry.ReplaceKeyManager(original)
+	if km, err := registry.GetKeyManager(testutil.AESGCMTypeURL); err != nil || km != original {
+		t.Errorf("registry.GetKeyManager() = %v, %v, want the replacing key manager", km, err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	original, err := registry.GetKeyManager(testutil.AESGCMTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() failed: %v", err)
+	}
+	restore := registry.Snapshot()
+	registry.ReplaceKeyManager(new(testutil.DummyAEADKeyManager))
+	if err := registry.UnregisterKeyManager(testutil.HMACTypeURL); err != nil {
+		t.Fatalf("registry.UnregisterKeyManager() failed: %v", err)
+	}
+	c, err := fakekms.NewClient("fake-kms://snapshot")
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() failed: %v", err)
+	}
+	registry.RegisterKMSClient(c)
+	restore()
+
+	if km, err := registry.GetKeyManager(testutil.AESGCMTypeURL); err != nil || km != original {
+		t.Errorf("registry.GetKeyManager() after restore = %v, %v, want the original key manager", km, err)
+	}
+	if _, err := registry.GetKeyManager(testutil.HMACTypeURL); err != nil {
+		t.Errorf("registry.GetKeyManager() of an unregistered type after restore failed: %v", err)
+	}
+	if _, err := registry.GetKMSClient("fake-kms://snapshot-key"); err == nil {
+		t.Error("registry.GetKMSClient() of a client registered after the snapshot succeeded, want error")
+	}
+}
+
+func TestRegistryInstance(t *testing.T) {
+	r := registry.New()
+	if _, err := r.GetKeyManager(testutil.AESGCMTypeURL); err == nil {
+		t.Error("r.GetKeyManager() of a new registry succeeded, want error")
+	}
+	km, err := registry.GetKeyManager(testutil.AESGCMTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() failed: %v", err)
+	}
+	if err := r.RegisterKeyManager(km); err != nil {
+		t.Fatalf("r.RegisterKeyManager() failed: %v", err)
+	}
+	if err := r.RegisterKeyManager(km); err == nil {
+		t.Error("registering a key manager twice succeeded, want error")
+	}
+	template := aead.AES128GCMKeyTemplate()
+	keyData, err := r.NewKeyData(template)
+	if err != nil {
+		t.Fatalf("r.NewKeyData() failed: %v", err)
+	}
+	if _, err := r.PrimitiveFromKeyData(keyData); err != nil {
+		t.Errorf("r.PrimitiveFromKeyData() failed: %v", err)
+	}
+	if _, err := r.NewKeyData(mac.HMACSHA256Tag128KeyTemplate()); err == nil {
+		t.Error("r.NewKeyData() of an unregistered key type succeeded, want error")
+	}
+
+	// The global registry is not affected.
+	if err := r.UnregisterKeyManager(testutil.AESGCMTypeURL); err != nil {
+		t.Fatalf("r.UnregisterKeyManager() failed: %v", err)
+	}
+	if _, err := registry.GetKeyManager(testutil.AESGCMTypeURL); err != nil {
+		t.Errorf("registry.GetKeyManager() failed after unregistering from another registry: %v", err)
+	}
+}
+
+func TestNewFromGlobal(t *testing.T) {
+	r, err := registry.NewFromGlobal(testutil.AESGCMTypeURL, testutil.HMACTypeURL)
+	if err != nil {
+		t.Fatalf("registry.NewFromGlobal() failed: %v", err)
+	}
+	for _, typeURL := range []string{testutil.AESGCMTypeURL, testutil.HMACTypeURL} {
+		if _, err := r.GetKeyManager(typeURL); err != nil {
+			t.Errorf("r.GetKeyManager(%q) failed: %v", typeURL, err)
+		}
+	}
+	if _, err := r.GetKeyManager(testutil.AESCTRHMACAEADTypeURL); err == nil {
+		t.Error("r.GetKeyManager() of a key type not taken from the global registry succeeded, want error")
+	}
+	if _, err := registry.NewFromGlobal("unknown"); err == nil {
+		t.Error("registry.NewFromGlobal() with an unknown type URL succeeded, want error")
+	}
+}
+
+func TestRegisteredKeyManagers(t *testing.T) {
+	infos := registry.RegisteredKeyManagers()
+	byTypeURL := make(map[string]registry.KeyManagerInfo)
+	for i, info := range infos {
+		if i > 0 && infos[i-1].TypeURL >= info.TypeURL {
+			t.Errorf("key managers are not sorted by type URL: %q before %q", infos[i-1].TypeURL, info.TypeURL)
+		}
+		byTypeURL[info.TypeURL] = info
+	}
+	for _, want := range []registry.KeyManagerInfo{
+		{TypeURL: testutil.AESGCMTypeURL, Primitive: "AEAD", Package: "github.com/google/tink/go/aead", Derivable: true},
+		{TypeURL: testutil.HMACTypeURL, Primitive: "MAC", Package: "github.com/google/tink/go/mac", Derivable: true},
+	} {
+		got, ok := byTypeURL[want.TypeURL]
+		if !ok {
+			t.Errorf("registry.RegisteredKeyManagers() does not contain %q", want.TypeURL)
+			continue
+		}
+		got.ModuleVersion = ""
+		if got != want {
+			t.Errorf("registry.RegisteredKeyManagers() contains %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestRegistryKeyManagers(t *testing.T) {
+	r := registry.New()
+	if infos := r.KeyManagers(); len(infos) != 0 {
+		t.Errorf("r.KeyManagers() of a new registry = %v, want none", infos)
+	}
+	if err := r.RegisterKeyManager(new(testutil.DummyAEADKeyManager)); err != nil {
+		t.Fatalf("r.RegisterKeyManager() failed: %v", err)
+	}
+	infos := r.KeyManagers()
+	if len(infos) != 1 || infos[0].TypeURL != testutil.AESGCMTypeURL || infos[0].Package != "github.com/google/tink/go/testutil" || infos[0].Primitive != "" {
+		t.Errorf("r.KeyManagers() = %+v, want the dummy key manager without primitive", infos)
+	}
+}
+
+// aes128Policy forbids AES-GCM keys of 128 bits.
+type aes128Policy struct{}
+
+var errAES128 = errors.New("AES-128 is forbidden")
+
+func (aes128Policy) CheckKey(key *tinkpb.Keyset_Key) error {
+	if key.GetKeyData().GetTypeUrl() != testutil.AESGCMTypeURL {
+		return nil
+	}
+	k := new(gcmpb.AesGcmKey)
+	if err := proto.Unmarshal(key.GetKeyData().GetValue(), k); err != nil {
+		return err
+	}
+	if len(k.KeyValue) == 16 {
+		return errAES128
+	}
+	return nil
+}
+
+func (aes128Policy) CheckTemplate(template *tinkpb.KeyTemplate) error {
+	if template.GetTypeUrl() != testutil.AESGCMTypeURL {
+		return nil
+	}
+	f := new(gcmpb.AesGcmKeyFormat)
+	if err := proto.Unmarshal(template.GetValue(), f); err != nil {
+		return err
+	}
+	if f.KeySize == 16 {
+		return errAES128
+	}
+	return nil
+}
+
+func TestKeyTypePolicy(t *testing.T) {
+	aes128, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	registry.SetKeyTypePolicy(aes128Policy{})
+	defer registry.SetKeyTypePolicy(nil)
+
+	if _, err := keyset.NewHandle(aead.AES128GCMKeyTemplate()); err == nil {
+		t.Error("keyset.NewHandle() of a forbidden template succeeded, want error")
+	}
+	if _, err := registry.NewKeyData(aead.AES128GCMKeyTemplate()); !errors.Is(err, errAES128) {
+		t.Errorf("registry.NewKeyData() of a forbidden template = %v, want errAES128", err)
+	}
+	if _, err := aead.New(aes128); err == nil {
+		t.Error("aead.New() of a forbidden key succeeded, want error")
+	}
+	ps, err := aes128.Primitives()
+	if !errors.Is(err, errAES128) {
+		t.Errorf("aes128.Primitives() = %v, %v, want errAES128", ps, err)
+	}
+	aes256, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() of an allowed template failed: %v", err)
+	}
+	if _, err := aead.New(aes256); err != nil {
+		t.Errorf("aead.New() of an allowed key failed: %v", err)
+	}
+
+	registry.SetKeyTypePolicy(nil)
+	if _, err := aead.New(aes128); err != nil {
+		t.Errorf("aead.New() without policy failed: %v", err)
+	}
+}
+
+func TestKeyTypeRules(t *testing.T) {
+	key := func(typeURL string, prefix tinkpb.OutputPrefixType) *tinkpb.Keyset_Key {
+		return &tinkpb.Keyset_Key{KeyData: &tinkpb.KeyData{TypeUrl: typeURL}, OutputPrefixType: prefix}
+	}
+	for _, tc := range []struct {
+		name    string
+		rules   registry.KeyTypeRules
+		key     *tinkpb.Keyset_Key
+		allowed bool
+	}{
+		{"empty rules", registry.KeyTypeRules{}, key("a", tinkpb.OutputPrefixType_LEGACY), true},
+		{"allowed", registry.KeyTypeRules{AllowedTypeURLs: []string{"a"}}, key("a", tinkpb.OutputPrefixType_TINK), true},
+		{"not allowed", registry.KeyTypeRules{AllowedTypeURLs: []string{"a"}}, key("b", tinkpb.OutputPrefixType_TINK), false},
+		{"denied", registry.KeyTypeRules{DeniedTypeURLs: []string{"a"}}, key("a", tinkpb.OutputPrefixType_TINK), false},
+		{"denied prefix", registry.KeyTypeRules{DeniedOutputPrefixTypes: []tinkpb.OutputPrefixType{tinkpb.OutputPrefixType_LEGACY}}, key("a", tinkpb.OutputPrefixType_LEGACY), false},
+		{"other prefix", registry.KeyTypeRules{DeniedOutputPrefixTypes: []tinkpb.OutputPrefixType{tinkpb.OutputPrefixType_LEGACY}}, key("a", tinkpb.OutputPrefixType_RAW), true},
+	} {
+		keyErr := tc.rules.CheckKey(tc.key)
+		templateErr := tc.rules.CheckTemplate(&tinkpb.KeyTemplate{TypeUrl: tc.key.KeyData.TypeUrl, OutputPrefixType: tc.key.OutputPrefixType})
+		if (keyErr == nil) != tc.allowed || (templateErr == nil) != tc.allowed {
+			t.Errorf("%s: CheckKey() = %v and CheckTemplate() = %v, want allowed = %t", tc.name, keyErr, templateErr, tc.allowed)
+		}
+	}
+}
+
+func TestKeyTemplateFromName(t *testing.T) {
+	kt, err := registry.KeyTemplateFromName("AES256_GCM")
+	if err != nil {
+		t.Fatalf("registry.KeyTemplateFromName(%q) failed: %v", "AES256_GCM", err)
+	}
+	if !proto.Equal(kt, aead.AES256GCMKeyTemplate()) {
+		t.Errorf("registry.KeyTemplateFromName(%q) = %v, want %v", "AES256_GCM", kt, aead.AES256GCMKeyTemplate())
+	}
+	name, err := registry.KeyTemplateName(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("registry.KeyTemplateName() failed: %v", err)
+	}
+	if name != "HMAC_SHA256_128BITTAG" {
+		t.Errorf("registry.KeyTemplateName() = %q, want %q", name, "HMAC_SHA256_128BITTAG")
+	}
+	if _, err := registry.KeyTemplateFromName("UNKNOWN"); err == nil {
+		t.Errorf("registry.KeyTemplateFromName(%q) succeeded, want error", "UNKNOWN")
+	}
+	unknown := aead.AES256GCMKeyTemplate()
+	unknown.OutputPrefixType = tinkpb.OutputPrefixType_CRUNCHY
+	if _, err := registry.KeyTemplateName(unknown); err == nil {
+		t.Error("registry.KeyTemplateName() of an unregistered template succeeded, want error")
+	}
+}
+
+func TestKeyTemplateNamesRoundTrip(t *testing.T) {
+	names := registry.KeyTemplateNames()
+	if len(names) == 0 {
+		t.Fatal("registry.KeyTemplateNames() is empty")
+	}
+	for _, name := range names {
+		kt, err := registry.KeyTemplateFromName(name)
+		if err != nil {
+			t.Errorf("registry.KeyTemplateFromName(%q) failed: %v", name, err)
+			continue
+		}
+		got, err := registry.KeyTemplateName(kt)
+		if err != nil {
+			t.Errorf("registry.KeyTemplateName(%q) failed: %v", name, err)
+			continue
+		}
+		if got != name {
+			t.Errorf("registry.KeyTemplateName(%q) = %q, want %q", name, got, name)
+		}
+		if _, err := registry.NewKeyData(kt); err != nil {
+			t.Errorf("registry.NewKeyData(%q) failed: %v", name, err)
+		}
+	}
+}
+
+func TestRegisterKeyTemplate(t *testing.T) {
+	restore := registry.Snapshot()
+	defer restore()
+
+	custom := func() *tinkpb.KeyTemplate {
+		kt := aead.AES128GCMKeyTemplate()
+		kt.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+		return kt
+	}
+	if err := registry.RegisterKeyTemplate("CUSTOM_AES128_GCM_RAW", custom); err != nil {
+		t.Fatalf("registry.RegisterKeyTemplate() failed: %v", err)
+	}
+	if err := registry.RegisterKeyTemplate("CUSTOM_AES128_GCM_RAW", custom); err == nil {
+		t.Error("registry.RegisterKeyTemplate() of an existing name succeeded, want error")
+	}
+	if err := registry.RegisterKeyTemplate("", custom); err == nil {
+		t.Error("registry.RegisterKeyTemplate() with an empty name succeeded, want error")
+	}
+	if name, err := registry.KeyTemplateName(custom()); err != nil || name != "CUSTOM_AES128_GCM_RAW" {
+		t.Errorf("registry.KeyTemplateName() = %q, %v, want %q, nil", name, err, "CUSTOM_AES128_GCM_RAW")
+	}
+	restore()
+	if _, err := registry.KeyTemplateFromName("CUSTOM_AES128_GCM_RAW"); err == nil {
+		t.Error("registry.KeyTemplateFromName() succeeded after restore, want error")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+// UpgradableKeyManager is a special type of KeyManager that can upgrade keys
+// which were serialized by older versions of the key manager, e.g. with an
+// older key version, to the current version. keyset.Manager.UpgradeKeys
+// applies the upgrades, so that stored keys don't fall behind when the key
+// manager evolves.
+type UpgradableKeyManager interface {
+	KeyManager
+
+	// UpgradeKey returns the serialized key re-serialized in the current
+	// version. upgraded is false if the key already is in the current
+	// version, in which case the key is left as it is.
+	UpgradeKey(serializedKey []byte) (upgradedKey []byte, upgraded bool, err error)
+}
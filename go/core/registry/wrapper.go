@@ -0,0 +1,83 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/tink/go/core/primitiveset"
+)
+
+// PrimitiveWrapper combines the primitives of the keys of a keyset into a
+// single primitive of type T. It usually uses the primary key for new outputs,
+// e.g. ciphertexts, and selects keys by the output prefix otherwise, which
+// gives the primitive key rotation support.
+//
+// The wrapper must check that the entries of the primitive set have the
+// primitive type it expects, which needn't be T, e.g. the wrapper for prf.Set
+// combines entries of type prf.PRF.
+type PrimitiveWrapper[T any] func(ps *primitiveset.PrimitiveSet) (T, error)
+
+// RegisterPrimitiveWrapper registers the wrapper for primitives of type T, so
+// that keyset.Wrap[T] can create them. The packages of the primitives register
+// their wrappers, e.g. the aead package registers one for tink.AEAD; custom
+// primitives can do the same. Does not allow to overwrite existing wrappers.
+func RegisterPrimitiveWrapper[T any](w PrimitiveWrapper[T]) error {
+	if w == nil {
+		return fmt.Errorf("registry.RegisterPrimitiveWrapper: wrapper must not be nil")
+	}
+	t := primitiveType[T]()
+	wrappersMu.Lock()
+	defer wrappersMu.Unlock()
+	if _, existed := wrappers[t]; existed {
+		return fmt.Errorf("registry.RegisterPrimitiveWrapper: wrapper for %s has already been registered", t)
+	}
+	wrappers[t] = w
+	return nil
+}
+
+// GetPrimitiveWrapper returns the wrapper registered for primitives of type T.
+func GetPrimitiveWrapper[T any]() (PrimitiveWrapper[T], error) {
+	t := primitiveType[T]()
+	wrappersMu.RLock()
+	defer wrappersMu.RUnlock()
+	w, existed := wrappers[t]
+	if !existed {
+		return nil, fmt.Errorf("registry.GetPrimitiveWrapper: unsupported primitive type: %s", t)
+	}
+	return w.(PrimitiveWrapper[T]), nil
+}
+
+// Wrap combines the primitives in ps into a primitive of type T with the
+// wrapper registered for T.
+func Wrap[T any](ps *primitiveset.PrimitiveSet) (T, error) {
+	w, err := GetPrimitiveWrapper[T]()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return w(ps)
+}
+
+// primitiveType returns the type that wrappers for T are registered under.
+func primitiveType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
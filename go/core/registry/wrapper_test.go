@@ -0,0 +1,139 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package registry_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// tokenIssuer is a custom primitive which issues tokens that contain the
+// identifier of the key that issued them.
+type tokenIssuer interface {
+	Issue() []byte
+	Check(token []byte) bool
+}
+
+type testTokenIssuer struct{ secret string }
+
+func (i *testTokenIssuer) Issue() []byte { return []byte(i.secret) }
+
+func (i *testTokenIssuer) Check(token []byte) bool { return string(token) == i.secret }
+
+type wrappedTokenIssuer struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+func (w *wrappedTokenIssuer) Issue() []byte {
+	return append([]byte(w.ps.Primary.Prefix), w.ps.Primary.Primitive.(tokenIssuer).Issue()...)
+}
+
+func (w *wrappedTokenIssuer) Check(token []byte) bool {
+	for prefix, entries := range w.ps.Entries {
+		if !bytes.HasPrefix(token, []byte(prefix)) {
+			continue
+		}
+		for _, e := range entries {
+			if e.Primitive.(tokenIssuer).Check(token[len(prefix):]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func wrapTokenIssuer(ps *primitiveset.PrimitiveSet) (tokenIssuer, error) {
+	for _, entries := range ps.Entries {
+		for _, e := range entries {
+			if _, ok := e.Primitive.(tokenIssuer); !ok {
+				return nil, fmt.Errorf("not a tokenIssuer primitive")
+			}
+		}
+	}
+	return &wrappedTokenIssuer{ps: ps}, nil
+}
+
+func tokenIssuerKey(keyID uint32) *tinkpb.Keyset_Key {
+	return &tinkpb.Keyset_Key{
+		KeyData:          &tinkpb.KeyData{TypeUrl: "type.googleapis.com/test.TokenIssuerKey"},
+		Status:           tinkpb.KeyStatusType_ENABLED,
+		KeyId:            keyID,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+func TestRegisterPrimitiveWrapper(t *testing.T) {
+	restore := registry.Snapshot()
+	defer restore()
+
+	if _, err := registry.GetPrimitiveWrapper[tokenIssuer](); err == nil {
+		t.Fatal("registry.GetPrimitiveWrapper[tokenIssuer]() succeeded before registration, want error")
+	}
+	if err := registry.RegisterPrimitiveWrapper(wrapTokenIssuer); err != nil {
+		t.Fatalf("registry.RegisterPrimitiveWrapper() failed: %v", err)
+	}
+	if err := registry.RegisterPrimitiveWrapper(wrapTokenIssuer); err == nil {
+		t.Error("registry.RegisterPrimitiveWrapper() of a second wrapper succeeded, want error")
+	}
+
+	ps := primitiveset.New()
+	oldEntry, err := ps.Add(&testTokenIssuer{secret: "old"}, tokenIssuerKey(1))
+	if err != nil {
+		t.Fatalf("ps.Add() failed: %v", err)
+	}
+	primary, err := ps.Add(&testTokenIssuer{secret: "new"}, tokenIssuerKey(2))
+	if err != nil {
+		t.Fatalf("ps.Add() failed: %v", err)
+	}
+	ps.Primary = primary
+	issuer, err := registry.Wrap[tokenIssuer](ps)
+	if err != nil {
+		t.Fatalf("registry.Wrap[tokenIssuer]() failed: %v", err)
+	}
+	token := issuer.Issue()
+	if !bytes.HasPrefix(token, []byte(primary.Prefix)) {
+		t.Errorf("issuer.Issue() = %q, want prefix of the primary key %q", token, primary.Prefix)
+	}
+	if !issuer.Check(token) {
+		t.Error("issuer.Check() of a token of the primary key = false, want true")
+	}
+	if !issuer.Check(append([]byte(oldEntry.Prefix), "old"...)) {
+		t.Error("issuer.Check() of a token of the old key = false, want true")
+	}
+	if issuer.Check(append([]byte(oldEntry.Prefix), "new"...)) {
+		t.Error("issuer.Check() of a token with the wrong prefix = true, want false")
+	}
+
+	restore()
+	if _, err := registry.Wrap[tokenIssuer](ps); err == nil {
+		t.Error("registry.Wrap[tokenIssuer]() succeeded after restore, want error")
+	}
+}
+
+func TestRegisterPrimitiveWrapperNil(t *testing.T) {
+	if err := registry.RegisterPrimitiveWrapper[tokenIssuer](nil); err == nil {
+		t.Error("registry.RegisterPrimitiveWrapper(nil) succeeded, want error")
+	}
+}
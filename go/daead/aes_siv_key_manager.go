@@ -18,6 +18,7 @@ package daead
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/daead/subtle"
@@ -62,19 +63,22 @@ func (km *aesSIVKeyManager) Primitive(serializedKey []byte) (interface{}, error)
 	return ret, nil
 }
 
-// NewKey creates a new key. serializedKeyFormat is not required, because there is only one
-// valid key format.
+// NewKey creates a new key according to the given serialized AesSivKeyFormat.
+// serializedKeyFormat is not required; without it, a key of the default size
+// subtle.AESSIVKeySize is created.
 func (km *aesSIVKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	keySize := uint32(subtle.AESSIVKeySize)
 	if serializedKeyFormat != nil {
 		keyFormat := new(aspb.AesSivKeyFormat)
 		if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
 			return nil, fmt.Errorf("aes_siv_key_manager: invalid key format")
 		}
-		if keyFormat.KeySize != subtle.AESSIVKeySize {
-			return nil, fmt.Errorf("aes_siv_key_manager: keyFormat.KeySize != %d", subtle.AESSIVKeySize)
+		if err := subtle.ValidateAESSIVKeySize(keyFormat.KeySize); err != nil {
+			return nil, fmt.Errorf("aes_siv_key_manager: %s", err)
 		}
+		keySize = keyFormat.KeySize
 	}
-	keyValue := random.GetRandomBytes(subtle.AESSIVKeySize)
+	keyValue := random.GetRandomBytes(keySize)
 	key := &aspb.AesSivKey{
 		Version:  aesSIVKeyVersion,
 		KeyValue: keyValue,
@@ -82,8 +86,33 @@ func (km *aesSIVKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, e
 	return key, nil
 }
 
-// NewKeyData creates a new KeyData. serializedKeyFormat is not required, because there is only one
-// valid key format.
+// DeriveKey derives a new key according to specification in the given
+// serialized AesSivKeyFormat, using the bytes read from pseudorandomness as
+// key material.
+func (km *aesSIVKeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	keySize := uint32(subtle.AESSIVKeySize)
+	if serializedKeyFormat != nil {
+		keyFormat := new(aspb.AesSivKeyFormat)
+		if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+			return nil, fmt.Errorf("aes_siv_key_manager: invalid key format")
+		}
+		if err := subtle.ValidateAESSIVKeySize(keyFormat.KeySize); err != nil {
+			return nil, fmt.Errorf("aes_siv_key_manager: %s", err)
+		}
+		keySize = keyFormat.KeySize
+	}
+	keyValue := make([]byte, keySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("aes_siv_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &aspb.AesSivKey{
+		Version:  aesSIVKeyVersion,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the given serialized AesSivKeyFormat.
+// serializedKeyFormat is not required; see NewKey.
 // It should be used solely by the key management API.
 func (km *aesSIVKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
 	key, err := km.NewKey(serializedKeyFormat)
@@ -117,9 +146,8 @@ func (km *aesSIVKeyManager) validateKey(key *aspb.AesSivKey) error {
 	if err != nil {
 		return fmt.Errorf("aes_siv_key_manager: %s", err)
 	}
-	keySize := uint32(len(key.KeyValue))
-	if keySize != subtle.AESSIVKeySize {
-		return fmt.Errorf("aes_siv_key_manager: keySize != %d", subtle.AESSIVKeySize)
+	if err := subtle.ValidateAESSIVKeySize(uint32(len(key.KeyValue))); err != nil {
+		return fmt.Errorf("aes_siv_key_manager: %s", err)
 	}
 	return nil
 }
@@ -104,6 +104,35 @@ func TestAESSIVNewKeyData(t *testing.T) {
 	}
 }
 
+func TestAESSIVNewKeyWithKeySize(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AESSIV key manager: %s", err)
+	}
+	for _, keySize := range []uint32{32, 48, 64} {
+		serializedKeyFormat, err := proto.Marshal(&aspb.AesSivKeyFormat{KeySize: keySize})
+		if err != nil {
+			t.Fatalf("proto.Marshal(keyFormat) = %v; want nil", err)
+		}
+		m, err := km.NewKey(serializedKeyFormat)
+		if err != nil {
+			t.Fatalf("km.NewKey(serializedKeyFormat) = _, %v; want _, nil", err)
+		}
+		key := m.(*aspb.AesSivKey)
+		if uint32(len(key.KeyValue)) != keySize {
+			t.Errorf("len(key.KeyValue) = %d; want %d", len(key.KeyValue), keySize)
+		}
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Fatalf("km.Primitive(%v) = %v; want nil", serializedKey, err)
+		}
+		if err := validateAESSIVPrimitive(p, key); err != nil {
+			t.Errorf("validateAESSIVPrimitive(p, key) = %v; want nil", err)
+		}
+	}
+}
+
 func TestAESSIVNewKeyInvalid(t *testing.T) {
 	km, err := registry.GetKeyManager(testutil.AESSIVTypeURL)
 	if err != nil {
@@ -189,7 +218,7 @@ func genInvalidAESSIVKeys() []*aspb.AesSivKey {
 		},
 		&aspb.AesSivKey{
 			Version:  testutil.AESSIVKeyVersion,
-			KeyValue: random.GetRandomBytes(32),
+			KeyValue: random.GetRandomBytes(33),
 		},
 		&aspb.AesSivKey{
 			Version:  testutil.AESSIVKeyVersion,
@@ -30,4 +30,9 @@ func init() {
 	if err := registry.RegisterKeyManager(newAESSIVKeyManager()); err != nil {
 		panic(fmt.Sprintf("daead.init() failed: %v", err))
 	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("daead.init() failed: %v", err))
+		}
+	}
 }
@@ -17,6 +17,7 @@
 package daead
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/tink/go/core/cryptofmt"
@@ -38,7 +39,20 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Determini
 	if err != nil {
 		return nil, fmt.Errorf("daead_factory: cannot obtain primitive set: %s", err)
 	}
+	return newWrappedDeterministicAEAD(ps)
+}
 
+// NewWithRegistry returns a DeterministicAEAD primitive from the given keyset
+// handle, using the key managers of r instead of the global registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.DeterministicAEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("daead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedDeterministicAEAD(ps)
+}
+
+func newWrappedDeterministicAEAD(ps *primitiveset.PrimitiveSet) (tink.DeterministicAEAD, error) {
 	if _, ok := (ps.Primary.Primitive).(tink.DeterministicAEAD); !ok {
 		return nil, fmt.Errorf("daead_factory: not a DeterministicAEAD primitive")
 	}
@@ -56,6 +70,11 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Determini
 	return tink.DeterministicAEAD(ret), nil
 }
 
+// ErrDecryptionFailed is returned when a ciphertext cannot be decrypted with
+// any key of the keyset, as opposed to errors caused by an invalid keyset or
+// configuration. Use errors.Is to test for it.
+var ErrDecryptionFailed = errors.New("daead_factory: decryption failed")
+
 // wrappedDeterministicAEAD is an DeterministicAEAD implementation that uses an underlying primitive set
 // for deterministic encryption and decryption.
 type wrappedDeterministicAEAD struct {
@@ -69,6 +88,9 @@ var _ tink.DeterministicAEAD = (*wrappedDeterministicAEAD)(nil)
 // It returns the concatenation of the primary's identifier and the ciphertext.
 func (d *wrappedDeterministicAEAD) EncryptDeterministically(pt, aad []byte) ([]byte, error) {
 	primary := d.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
 	p, ok := (primary.Primitive).(tink.DeterministicAEAD)
 	if !ok {
 		return nil, fmt.Errorf("daead_factory: not a DeterministicAEAD primitive")
@@ -123,5 +145,5 @@ func (d *wrappedDeterministicAEAD) DecryptDeterministically(ct, aad []byte) ([]b
 	}
 
 	// nothing worked
-	return nil, fmt.Errorf("daead_factory: decryption failed")
+	return nil, ErrDecryptionFailed
 }
@@ -18,6 +18,7 @@ package daead_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -161,3 +162,21 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Fatalf("calling New() with good *keyset.Handle failed: %s", err)
 	}
 }
+
+func TestFactoryDecryptionFailedIsErrDecryptionFailed(t *testing.T) {
+	kh, err := keyset.NewHandle(daead.AESSIVKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	d, err := daead.New(kh)
+	if err != nil {
+		t.Fatalf("daead.New failed: %s", err)
+	}
+	ct, err := d.EncryptDeterministically([]byte("plaintext"), []byte("ad"))
+	if err != nil {
+		t.Fatalf("d.EncryptDeterministically failed: %s", err)
+	}
+	if _, err := d.DecryptDeterministically(ct, []byte("other ad")); !errors.Is(err, daead.ErrDecryptionFailed) {
+		t.Errorf("d.DecryptDeterministically with wrong ad = %v, want daead.ErrDecryptionFailed", err)
+	}
+}
@@ -17,7 +17,10 @@
 package daead
 
 import (
+	"fmt"
+
 	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/daead/subtle"
 	aspb "github.com/google/tink/go/proto/aes_siv_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
@@ -34,3 +37,67 @@ func AESSIVKeyTemplate() *tinkpb.KeyTemplate {
 		Value:            serializedFormat,
 	}
 }
+
+// AESSIVKeyTemplateBuilder builds AES-SIV key templates with a selectable key
+// size and output prefix type, e.g. a RAW template for blind indexes whose
+// ciphertexts are compared across key rotations:
+//
+//	template, err := daead.NewAESSIVKeyTemplateBuilder().
+//		WithOutputPrefixType(tinkpb.OutputPrefixType_RAW).
+//		Build()
+type AESSIVKeyTemplateBuilder struct {
+	keySize          uint32
+	outputPrefixType tinkpb.OutputPrefixType
+}
+
+// NewAESSIVKeyTemplateBuilder returns a builder whose templates are equal to
+// AESSIVKeyTemplate unless configured otherwise.
+func NewAESSIVKeyTemplateBuilder() *AESSIVKeyTemplateBuilder {
+	return &AESSIVKeyTemplateBuilder{
+		keySize:          subtle.AESSIVKeySize,
+		outputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// WithKeySize sets the key size in bytes, which must be 32, 48 or 64. Keys
+// shorter than 64 bytes are susceptible to multi-user attacks, see
+// subtle.AESSIV.
+func (b *AESSIVKeyTemplateBuilder) WithKeySize(keySize uint32) *AESSIVKeyTemplateBuilder {
+	b.keySize = keySize
+	return b
+}
+
+// WithOutputPrefixType sets the output prefix type.
+func (b *AESSIVKeyTemplateBuilder) WithOutputPrefixType(t tinkpb.OutputPrefixType) *AESSIVKeyTemplateBuilder {
+	b.outputPrefixType = t
+	return b
+}
+
+// Build returns the configured key template.
+func (b *AESSIVKeyTemplateBuilder) Build() (*tinkpb.KeyTemplate, error) {
+	if err := subtle.ValidateAESSIVKeySize(b.keySize); err != nil {
+		return nil, fmt.Errorf("daead: %s", err)
+	}
+	if _, ok := tinkpb.OutputPrefixType_name[int32(b.outputPrefixType)]; !ok || b.outputPrefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
+		return nil, fmt.Errorf("daead: invalid output prefix type %v", b.outputPrefixType)
+	}
+	format := &aspb.AesSivKeyFormat{
+		KeySize: b.keySize,
+	}
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		return nil, fmt.Errorf("daead: cannot marshal key format: %s", err)
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesSIVTypeURL,
+		OutputPrefixType: b.outputPrefixType,
+		Value:            serializedFormat,
+	}, nil
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"AES256_SIV": AESSIVKeyTemplate,
+}
@@ -54,6 +54,52 @@ func TestKeyTemplates(t *testing.T) {
 	}
 }
 
+func TestAESSIVKeyTemplateBuilder(t *testing.T) {
+	template, err := daead.NewAESSIVKeyTemplateBuilder().Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %s", err)
+	}
+	if !proto.Equal(template, daead.AESSIVKeyTemplate()) {
+		t.Errorf("default template = %v, want %v", template, daead.AESSIVKeyTemplate())
+	}
+
+	for _, keySize := range []uint32{32, 48, 64} {
+		for _, prefixType := range []tinkpb.OutputPrefixType{
+			tinkpb.OutputPrefixType_TINK,
+			tinkpb.OutputPrefixType_LEGACY,
+			tinkpb.OutputPrefixType_RAW,
+			tinkpb.OutputPrefixType_CRUNCHY,
+		} {
+			template, err := daead.NewAESSIVKeyTemplateBuilder().
+				WithKeySize(keySize).
+				WithOutputPrefixType(prefixType).
+				Build()
+			if err != nil {
+				t.Fatalf("Build() with key size %d and %s failed: %s", keySize, prefixType, err)
+			}
+			if template.OutputPrefixType != prefixType {
+				t.Errorf("OutputPrefixType = %s, want %s", template.OutputPrefixType, prefixType)
+			}
+			if err := testEncryptDecrypt(template); err != nil {
+				t.Errorf("key size %d and %s: %v", keySize, prefixType, err)
+			}
+		}
+	}
+}
+
+func TestAESSIVKeyTemplateBuilderInvalid(t *testing.T) {
+	for _, keySize := range []uint32{0, 16, 33, 65} {
+		if _, err := daead.NewAESSIVKeyTemplateBuilder().WithKeySize(keySize).Build(); err == nil {
+			t.Errorf("Build() with key size %d succeeded, want error", keySize)
+		}
+	}
+	for _, prefixType := range []tinkpb.OutputPrefixType{tinkpb.OutputPrefixType_UNKNOWN_PREFIX, 42} {
+		if _, err := daead.NewAESSIVKeyTemplateBuilder().WithOutputPrefixType(prefixType).Build(); err == nil {
+			t.Errorf("Build() with output prefix type %d succeeded, want error", prefixType)
+		}
+	}
+}
+
 func testEncryptDecrypt(template *tinkpb.KeyTemplate) error {
 	handle, err := keyset.NewHandle(template)
 	if err != nil {
@@ -47,9 +47,10 @@ import (
 // then it is possible  to find one of the MAC keys in time 2^b / k
 // where b is the size of the MAC key. A consequence of this attack
 // is that 128-bit MAC keys give unsufficient security.
-// Since 192-bit AES keys are not supported by tink for voodoo reasons
-// and RFC 5297 only supports same size encryption and MAC keys this
-// implies that keys must be 64 bytes (2*256 bits) long.
+// Since RFC 5297 only supports same size encryption and MAC keys this
+// implies that keys should be 64 bytes (2*256 bits) long, which is the
+// default. Keys of 32 and 48 bytes are supported for interoperability and
+// for uses such as blind indexes where this attack is not a concern.
 type AESSIV struct {
 	K1     []byte
 	K2     []byte
@@ -59,19 +60,31 @@ type AESSIV struct {
 }
 
 const (
-	// AESSIVKeySize is the key size in bytes.
+	// AESSIVKeySize is the default key size in bytes.
 	AESSIVKeySize = 64
 	maxInt        = int(^uint(0) >> 1)
 )
 
-// NewAESSIV returns an AESSIV instance.
+// ValidateAESSIVKeySize checks if the given key size is a valid AES-SIV key
+// size, that is 32, 48 or 64 bytes.
+func ValidateAESSIVKeySize(sizeInBytes uint32) error {
+	switch sizeInBytes {
+	case 32, 48, 64:
+		return nil
+	default:
+		return fmt.Errorf("invalid AES-SIV key size; want 32, 48 or 64, got %d", sizeInBytes)
+	}
+}
+
+// NewAESSIV returns an AESSIV instance. The key must be 32, 48 or 64 bytes
+// long; its first half is the MAC key and its second half the encryption key.
 func NewAESSIV(key []byte) (*AESSIV, error) {
-	if len(key) != AESSIVKeySize {
+	if err := ValidateAESSIVKeySize(uint32(len(key))); err != nil {
 		return nil, fmt.Errorf("aes_siv: invalid key size %d", len(key))
 	}
 
-	k1 := key[:32]
-	k2 := key[32:]
+	k1 := key[:len(key)/2]
+	k2 := key[len(key)/2:]
 	c, err := aes.NewCipher(k1)
 	if err != nil {
 		return nil, fmt.Errorf("aes_siv: aes.NewCipher(%s) failed, %v", k1, err)
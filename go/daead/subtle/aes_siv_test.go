@@ -148,15 +148,42 @@ func TestAESSIV_KeySizes(t *testing.T) {
 
 	for i := 0; i < len(key); i++ {
 		_, err := subtle.NewAESSIV(key[:i])
-		if i == subtle.AESSIVKeySize && err != nil {
+		valid := i == 32 || i == 48 || i == subtle.AESSIVKeySize
+		if valid && err != nil {
 			t.Errorf("Rejected valid key size: %v, %v", i, err)
 		}
-		if i != subtle.AESSIVKeySize && err == nil {
+		if !valid && err == nil {
 			t.Errorf("Allowed invalid key size: %v", i)
 		}
 	}
 }
 
+func TestAESSIV_RFC5297Vector(t *testing.T) {
+	// Deterministic authenticated encryption example from Appendix A.1 of
+	// RFC 5297, which uses a 32-byte key.
+	key, _ := hex.DecodeString("fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	aad, _ := hex.DecodeString("101112131415161718191a1b1c1d1e1f2021222324252627")
+	msg, _ := hex.DecodeString("112233445566778899aabbccddee")
+	want, _ := hex.DecodeString("85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c")
+
+	a, err := subtle.NewAESSIV(key)
+	if err != nil {
+		t.Fatalf("NewAESSIV(key) = _, %v, want _, nil", err)
+	}
+	ct, err := a.EncryptDeterministically(msg, aad)
+	if err != nil {
+		t.Fatalf("Unexpected encryption error: %v", err)
+	}
+	if !bytes.Equal(ct, want) {
+		t.Errorf("EncryptDeterministically() = %x, want %x", ct, want)
+	}
+	if pt, err := a.DecryptDeterministically(want, aad); err != nil {
+		t.Errorf("Unexpected decryption error: %v", err)
+	} else if !bytes.Equal(pt, msg) {
+		t.Errorf("Mismatched plaintexts: got %x, want %x", pt, msg)
+	}
+}
+
 func TestAESSIV_MessageSizes(t *testing.T) {
 	keyStr :=
 		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
@@ -268,7 +295,7 @@ func TestAESSIV_WycheproofVectors(t *testing.T) {
 	}
 
 	for _, g := range data.TestGroups {
-		if g.KeySize/8 != subtle.AESSIVKeySize {
+		if err := subtle.ValidateAESSIVKeySize(g.KeySize / 8); err != nil {
 			continue
 		}
 
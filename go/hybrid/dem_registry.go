@@ -0,0 +1,94 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/tink/go/core/registry"
+)
+
+// minDEMKeySize is the minimum amount of key material, in bytes, that a DEM
+// may request from the KEM.
+const minDEMKeySize = 16
+
+// DEM describes how to key a symmetric AEAD or deterministic AEAD key type
+// when it is used as the data encapsulation mechanism (DEM) of an ECIES or
+// ML-KEM+X25519 hybrid key. The symmetric key material is derived by the KEM
+// for every message and written into a key generated by the key type's
+// KeyManager.
+//
+// HPKE keys are not affected: their AEADs are fixed by RFC 9180.
+type DEM interface {
+	// SymmetricKeySize returns the number of bytes of key material the KEM must
+	// derive for keys of the given serialized key format.
+	SymmetricKeySize(serializedKeyFormat []byte) (uint32, error)
+
+	// SetKeyValue returns serializedKey, a key generated by the key type's
+	// KeyManager, with its key material replaced by symmetricKeyValue.
+	SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error)
+}
+
+var (
+	demsMu sync.RWMutex
+	dems   = map[string]DEM{ // typeURL -> DEM
+		aesGCMTypeURL:           aesGCMDEM{},
+		aesCTRHMACAEADTypeURL:   aesCTRHMACAEADDEM{},
+		aesSIVTypeURL:           aesSIVDEM{},
+		chaCha20Poly1305TypeURL: chaCha20Poly1305DEM{},
+	}
+)
+
+// RegisterDEM registers dem as the DEM for keys of the given type, so that
+// templates with that type URL can be used as the AEAD DEM of hybrid keys.
+// A KeyManager for typeURL must already be registered, and its primitive must
+// be a tink.AEAD or a tink.DeterministicAEAD. Does not allow to overwrite
+// existing DEMs, including the built-in ones.
+func RegisterDEM(typeURL string, dem DEM) error {
+	if typeURL == "" {
+		return errors.New("hybrid.RegisterDEM: empty type URL")
+	}
+	if dem == nil {
+		return errors.New("hybrid.RegisterDEM: nil DEM")
+	}
+	km, err := registry.GetKeyManager(typeURL)
+	if err != nil {
+		return fmt.Errorf("hybrid.RegisterDEM: %s", err)
+	}
+	if _, ok := km.(registry.PrivateKeyManager); ok {
+		return fmt.Errorf("hybrid.RegisterDEM: type %s is not a symmetric key type", typeURL)
+	}
+	demsMu.Lock()
+	defer demsMu.Unlock()
+	if _, existed := dems[typeURL]; existed {
+		return fmt.Errorf("hybrid.RegisterDEM: type %s already registered", typeURL)
+	}
+	dems[typeURL] = dem
+	return nil
+}
+
+func lookupDEM(typeURL string) (DEM, error) {
+	demsMu.RLock()
+	defer demsMu.RUnlock()
+	dem, existed := dems[typeURL]
+	if !existed {
+		return nil, fmt.Errorf("unsupported AEAD DEM key type: %s", typeURL)
+	}
+	return dem, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
+)
+
+const xChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+
+// xChaCha20Poly1305DEM is a DEM for XChaCha20Poly1305Key, which is not one of
+// the built-in DEMs.
+type xChaCha20Poly1305DEM struct{}
+
+func (xChaCha20Poly1305DEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	return 32, nil
+}
+
+func (xChaCha20Poly1305DEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	key := new(xchachapb.XChaCha20Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, err
+	}
+	key.KeyValue = symmetricKeyValue
+	return proto.Marshal(key)
+}
+
+func TestRegisterDEM(t *testing.T) {
+	template := createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.HashType_SHA256, commonpb.EcPointFormat_UNCOMPRESSED, aead.XChaCha20Poly1305KeyTemplate(), []byte{})
+	privateHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v", err)
+	}
+	if _, err := NewHybridEncrypt(publicHandle); err == nil {
+		t.Fatalf("NewHybridEncrypt() with an unregistered DEM err = nil, want error")
+	}
+
+	if err := RegisterDEM(xChaCha20Poly1305TypeURL, xChaCha20Poly1305DEM{}); err != nil {
+		t.Fatalf("RegisterDEM() err = %v", err)
+	}
+	defer func() {
+		demsMu.Lock()
+		delete(dems, xChaCha20Poly1305TypeURL)
+		demsMu.Unlock()
+	}()
+	enc, err := NewHybridEncrypt(publicHandle)
+	if err != nil {
+		t.Fatalf("NewHybridEncrypt() err = %v", err)
+	}
+	dec, err := NewHybridDecrypt(privateHandle)
+	if err != nil {
+		t.Fatalf("NewHybridDecrypt() err = %v", err)
+	}
+	pt := random.GetRandomBytes(20)
+	ci := []byte("context info")
+	ct, err := enc.Encrypt(pt, ci)
+	if err != nil {
+		t.Fatalf("enc.Encrypt() err = %v", err)
+	}
+	got, err := dec.Decrypt(ct, ci)
+	if err != nil {
+		t.Fatalf("dec.Decrypt() err = %v", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("dec.Decrypt() = %x, want %x", got, pt)
+	}
+
+	if err := RegisterDEM(xChaCha20Poly1305TypeURL, xChaCha20Poly1305DEM{}); err == nil {
+		t.Errorf("RegisterDEM() twice for the same type err = nil, want error")
+	}
+}
+
+func TestRegisterDEMWithInvalidArguments(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		typeURL string
+		dem     DEM
+	}{
+		{name: "empty type URL", typeURL: "", dem: xChaCha20Poly1305DEM{}},
+		{name: "nil DEM", typeURL: xChaCha20Poly1305TypeURL, dem: nil},
+		{name: "no key manager", typeURL: "type.googleapis.com/google.crypto.tink.UnknownKey", dem: xChaCha20Poly1305DEM{}},
+		{name: "asymmetric key type", typeURL: eciesAEADHKDFPrivateKeyTypeURL, dem: xChaCha20Poly1305DEM{}},
+		{name: "built-in DEM", typeURL: aesGCMTypeURL, dem: xChaCha20Poly1305DEM{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := RegisterDEM(tc.typeURL, tc.dem); err == nil {
+				t.Errorf("RegisterDEM(%q) err = nil, want error", tc.typeURL)
+			}
+		})
+	}
+}
+
+type shortKeyDEM struct{}
+
+func (shortKeyDEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	return minDEMKeySize - 1, nil
+}
+
+func (shortKeyDEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	return serializedKey, nil
+}
+
+func TestDEMHelperRejectsShortKeys(t *testing.T) {
+	demsMu.Lock()
+	dems["type.googleapis.com/test.ShortKey"] = shortKeyDEM{}
+	demsMu.Unlock()
+	defer func() {
+		demsMu.Lock()
+		delete(dems, "type.googleapis.com/test.ShortKey")
+		demsMu.Unlock()
+	}()
+	if _, err := newRegisterECIESAEADHKDFDemHelper(&tinkpb.KeyTemplate{TypeUrl: "type.googleapis.com/test.ShortKey"}); err == nil {
+		t.Errorf("newRegisterECIESAEADHKDFDemHelper() with a short DEM key err = nil, want error")
+	}
+}
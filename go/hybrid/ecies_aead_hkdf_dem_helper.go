@@ -19,7 +19,6 @@ package hybrid
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/registry"
@@ -28,78 +27,49 @@ import (
 	ctrhmacpb "github.com/google/tink/go/proto/aes_ctr_hmac_aead_go_proto"
 	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
 	sivpb "github.com/google/tink/go/proto/aes_siv_go_proto"
+	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
 const (
-	aesGCMTypeURL         = "type.googleapis.com/google.crypto.tink.AesGcmKey"
-	aesCTRHMACAEADTypeURL = "type.googleapis.com/google.crypto.tink.AesCtrHmacAeadKey"
-	aesSIVTypeURL         = "type.googleapis.com/google.crypto.tink.AesSivKey"
+	aesGCMTypeURL           = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	aesCTRHMACAEADTypeURL   = "type.googleapis.com/google.crypto.tink.AesCtrHmacAeadKey"
+	aesSIVTypeURL           = "type.googleapis.com/google.crypto.tink.AesSivKey"
+	chaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
+
+	chaCha20Poly1305KeySize = 32
 )
 
 // eciesAEADHKDFDEMHelper generates AEAD or DeterministicAEAD primitives for the specified KeyTemplate and key material.
 // in order to implement the EciesAEADHKDFDEMHelper interface.
 type eciesAEADHKDFDEMHelper struct {
 	demKeyURL        string
+	dem              DEM
 	keyData          []byte
 	symmetricKeySize uint32
-	aesCTRSize       uint32
 }
 
 var _ subtle.EciesAEADHKDFDEMHelper = (*eciesAEADHKDFDEMHelper)(nil)
 
 // newRegisterECIESAEADHKDFDemHelper initializes and returns a RegisterECIESAEADHKDFDemHelper
 func newRegisterECIESAEADHKDFDemHelper(k *tinkpb.KeyTemplate) (*eciesAEADHKDFDEMHelper, error) {
-	var len uint32
-	var aesCTRSize uint32
-	var keyFormat []byte
-
-	if strings.Compare(k.TypeUrl, aesGCMTypeURL) == 0 {
-		gcmKeyFormat := new(gcmpb.AesGcmKeyFormat)
-		var err error
-		if err = proto.Unmarshal(k.Value, gcmKeyFormat); err != nil {
-			return nil, err
-		}
-		len = gcmKeyFormat.KeySize
-		keyFormat, err = proto.Marshal(gcmKeyFormat)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key format, error :%v", err)
-		}
-	} else if strings.Compare(k.TypeUrl, aesCTRHMACAEADTypeURL) == 0 {
-		aeadKeyFormat := new(ctrhmacpb.AesCtrHmacAeadKeyFormat)
-		var err error
-		if err = proto.Unmarshal(k.Value, aeadKeyFormat); err != nil {
-			return nil, err
-		}
-		if aeadKeyFormat.AesCtrKeyFormat == nil || aeadKeyFormat.HmacKeyFormat == nil {
-			return nil, fmt.Errorf("failed to deserialize key format")
-		}
-		aesCTRSize = aeadKeyFormat.AesCtrKeyFormat.KeySize
-		len = aesCTRSize + aeadKeyFormat.HmacKeyFormat.KeySize
-		keyFormat, err = proto.Marshal(aeadKeyFormat)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key format, error :%v", err)
-		}
-	} else if strings.Compare(k.TypeUrl, aesSIVTypeURL) == 0 {
-		daeadKeyFormat := new(sivpb.AesSivKeyFormat)
-		var err error
-		if err = proto.Unmarshal(k.Value, daeadKeyFormat); err != nil {
-			return nil, err
-		}
-		len = daeadKeyFormat.KeySize
-		keyFormat, err = proto.Marshal(daeadKeyFormat)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key format, error :%v", err)
-		}
-	} else {
-		return nil, fmt.Errorf("unsupported AEAD DEM key type: %s", k.TypeUrl)
+	dem, err := lookupDEM(k.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	len, err := dem.SymmetricKeySize(k.Value)
+	if err != nil {
+		return nil, err
+	}
+	if len < minDEMKeySize {
+		return nil, fmt.Errorf("DEM key size too small: %d bytes", len)
 	}
 	km, err := registry.GetKeyManager(k.TypeUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch KeyManager, error: %v", err)
 	}
 
-	key, err := km.NewKey(keyFormat)
+	key, err := km.NewKey(k.Value)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch key, error: %v", err)
 	}
@@ -110,9 +80,9 @@ func newRegisterECIESAEADHKDFDemHelper(k *tinkpb.KeyTemplate) (*eciesAEADHKDFDEM
 
 	return &eciesAEADHKDFDEMHelper{
 		demKeyURL:        k.TypeUrl,
+		dem:              dem,
 		keyData:          sk,
 		symmetricKeySize: len,
-		aesCTRSize:       aesCTRSize,
 	}, nil
 }
 
@@ -124,49 +94,12 @@ func (r *eciesAEADHKDFDEMHelper) GetSymmetricKeySize() uint32 {
 
 // GetAEADOrDAEAD returns the AEAD or deterministic AEAD primitive from the DEM
 func (r *eciesAEADHKDFDEMHelper) GetAEADOrDAEAD(symmetricKeyValue []byte) (interface{}, error) {
-	var sk []byte
 	if uint32(len(symmetricKeyValue)) != r.GetSymmetricKeySize() {
 		return nil, errors.New("symmetric key has incorrect length")
 	}
-	if strings.Compare(r.demKeyURL, aesGCMTypeURL) == 0 {
-		gcmKey := new(gcmpb.AesGcmKey)
-		var err error
-		if err := proto.Unmarshal(r.keyData, gcmKey); err != nil {
-			return nil, err
-		}
-		gcmKey.KeyValue = symmetricKeyValue
-		sk, err = proto.Marshal(gcmKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key, error: %v", err)
-		}
-
-	} else if strings.Compare(r.demKeyURL, aesCTRHMACAEADTypeURL) == 0 {
-		aesCTR := new(ctrhmacpb.AesCtrHmacAeadKey)
-		var err error
-		if err := proto.Unmarshal(r.keyData, aesCTR); err != nil {
-			return nil, err
-		}
-		aesCTR.AesCtrKey.KeyValue = symmetricKeyValue[:r.aesCTRSize]
-		aesCTR.HmacKey.KeyValue = symmetricKeyValue[r.aesCTRSize:]
-		sk, err = proto.Marshal(aesCTR)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key, error: %v", err)
-		}
-
-	} else if strings.Compare(r.demKeyURL, aesSIVTypeURL) == 0 {
-		sivKey := new(sivpb.AesSivKey)
-		var err error
-		if err := proto.Unmarshal(r.keyData, sivKey); err != nil {
-			return nil, err
-		}
-		sivKey.KeyValue = symmetricKeyValue
-		sk, err = proto.Marshal(sivKey)
-		if err != nil {
-			return nil, fmt.Errorf("failed to serialize key, error: %v", err)
-		}
-
-	} else {
-		return nil, fmt.Errorf("unsupported AEAD DEM key type: %s", r.demKeyURL)
+	sk, err := r.dem.SetKeyValue(r.keyData, symmetricKeyValue)
+	if err != nil {
+		return nil, err
 	}
 
 	p, err := registry.Primitive(r.demKeyURL, sk)
@@ -180,3 +113,108 @@ func (r *eciesAEADHKDFDEMHelper) GetAEADOrDAEAD(symmetricKeyValue []byte) (inter
 		return nil, fmt.Errorf("Unexpected primitive type returned by the registry for the DEM: %T", p)
 	}
 }
+
+// aesGCMDEM is the built-in DEM for AesGcmKey.
+type aesGCMDEM struct{}
+
+func (aesGCMDEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	gcmKeyFormat := new(gcmpb.AesGcmKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, gcmKeyFormat); err != nil {
+		return 0, err
+	}
+	return gcmKeyFormat.KeySize, nil
+}
+
+func (aesGCMDEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	gcmKey := new(gcmpb.AesGcmKey)
+	if err := proto.Unmarshal(serializedKey, gcmKey); err != nil {
+		return nil, err
+	}
+	gcmKey.KeyValue = symmetricKeyValue
+	sk, err := proto.Marshal(gcmKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize key, error: %v", err)
+	}
+	return sk, nil
+}
+
+// aesCTRHMACAEADDEM is the built-in DEM for AesCtrHmacAeadKey. The derived
+// key material is split into the AES-CTR key followed by the HMAC key.
+type aesCTRHMACAEADDEM struct{}
+
+func (aesCTRHMACAEADDEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	aeadKeyFormat := new(ctrhmacpb.AesCtrHmacAeadKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, aeadKeyFormat); err != nil {
+		return 0, err
+	}
+	if aeadKeyFormat.AesCtrKeyFormat == nil || aeadKeyFormat.HmacKeyFormat == nil {
+		return 0, fmt.Errorf("failed to deserialize key format")
+	}
+	return aeadKeyFormat.AesCtrKeyFormat.KeySize + aeadKeyFormat.HmacKeyFormat.KeySize, nil
+}
+
+func (aesCTRHMACAEADDEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	aesCTR := new(ctrhmacpb.AesCtrHmacAeadKey)
+	if err := proto.Unmarshal(serializedKey, aesCTR); err != nil {
+		return nil, err
+	}
+	if aesCTR.AesCtrKey == nil || aesCTR.HmacKey == nil {
+		return nil, fmt.Errorf("failed to deserialize key")
+	}
+	aesCTRSize := len(aesCTR.AesCtrKey.KeyValue)
+	if aesCTRSize > len(symmetricKeyValue) {
+		return nil, errors.New("symmetric key has incorrect length")
+	}
+	aesCTR.AesCtrKey.KeyValue = symmetricKeyValue[:aesCTRSize]
+	aesCTR.HmacKey.KeyValue = symmetricKeyValue[aesCTRSize:]
+	sk, err := proto.Marshal(aesCTR)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize key, error: %v", err)
+	}
+	return sk, nil
+}
+
+// aesSIVDEM is the built-in DEM for AesSivKey.
+type aesSIVDEM struct{}
+
+func (aesSIVDEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	daeadKeyFormat := new(sivpb.AesSivKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, daeadKeyFormat); err != nil {
+		return 0, err
+	}
+	return daeadKeyFormat.KeySize, nil
+}
+
+func (aesSIVDEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	sivKey := new(sivpb.AesSivKey)
+	if err := proto.Unmarshal(serializedKey, sivKey); err != nil {
+		return nil, err
+	}
+	sivKey.KeyValue = symmetricKeyValue
+	sk, err := proto.Marshal(sivKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize key, error: %v", err)
+	}
+	return sk, nil
+}
+
+// chaCha20Poly1305DEM is the built-in DEM for ChaCha20Poly1305Key, which has
+// no key format parameters.
+type chaCha20Poly1305DEM struct{}
+
+func (chaCha20Poly1305DEM) SymmetricKeySize(serializedKeyFormat []byte) (uint32, error) {
+	return chaCha20Poly1305KeySize, nil
+}
+
+func (chaCha20Poly1305DEM) SetKeyValue(serializedKey, symmetricKeyValue []byte) ([]byte, error) {
+	chachaKey := new(chachapb.ChaCha20Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, chachaKey); err != nil {
+		return nil, err
+	}
+	chachaKey.KeyValue = symmetricKeyValue
+	sk, err := proto.Marshal(chachaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize key, error: %v", err)
+	}
+	return sk, nil
+}
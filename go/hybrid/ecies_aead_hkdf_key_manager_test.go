@@ -0,0 +1,109 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	eahpb "github.com/google/tink/go/proto/ecies_aead_hkdf_go_proto"
+)
+
+func TestECIESAEADHKDFPrivateKeyManagerNewKeyNISTCurves(t *testing.T) {
+	km, err := registry.GetKeyManager(eciesAEADHKDFPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	for _, c := range []commonpb.EllipticCurveType{
+		commonpb.EllipticCurveType_NIST_P256,
+		commonpb.EllipticCurveType_NIST_P384,
+		commonpb.EllipticCurveType_NIST_P521,
+	} {
+		template := createECIESAEADHKDFKeyTemplate(c, commonpb.HashType_SHA512, commonpb.EcPointFormat_UNCOMPRESSED, aead.AES256GCMKeyTemplate(), nil)
+		m, err := km.NewKey(template.Value)
+		if err != nil {
+			t.Fatalf("km.NewKey(%s) err = %v", c, err)
+		}
+		key, ok := m.(*eahpb.EciesAeadHkdfPrivateKey)
+		if !ok {
+			t.Fatalf("km.NewKey(%s) returned %T, want *EciesAeadHkdfPrivateKey", c, m)
+		}
+		curve, err := subtle.GetCurve(c.String())
+		if err != nil {
+			t.Fatalf("subtle.GetCurve(%s) err = %v", c, err)
+		}
+		x := new(big.Int).SetBytes(key.PublicKey.X)
+		y := new(big.Int).SetBytes(key.PublicKey.Y)
+		if !curve.IsOnCurve(x, y) {
+			t.Errorf("public key of the %s key is not on the curve", c)
+		}
+		gotX, gotY := curve.ScalarBaseMult(key.KeyValue)
+		if gotX.Cmp(x) != 0 || gotY.Cmp(y) != 0 {
+			t.Errorf("public key of the %s key does not match its private key", c)
+		}
+	}
+}
+
+func TestECIESAEADHKDFNISTCurvesEncryptDecrypt(t *testing.T) {
+	var testCases = []struct {
+		curve commonpb.EllipticCurveType
+		hash  commonpb.HashType
+	}{
+		{commonpb.EllipticCurveType_NIST_P384, commonpb.HashType_SHA384},
+		{commonpb.EllipticCurveType_NIST_P384, commonpb.HashType_SHA512},
+		{commonpb.EllipticCurveType_NIST_P521, commonpb.HashType_SHA512},
+	}
+	for _, tc := range testCases {
+		for _, f := range []commonpb.EcPointFormat{commonpb.EcPointFormat_UNCOMPRESSED, commonpb.EcPointFormat_COMPRESSED} {
+			template := createECIESAEADHKDFKeyTemplate(tc.curve, tc.hash, f, aead.AES256GCMKeyTemplate(), []byte("salt"))
+			privateHandle, err := keyset.NewHandle(template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			publicHandle, err := privateHandle.Public()
+			if err != nil {
+				t.Fatalf("privateHandle.Public() err = %v", err)
+			}
+			enc, err := NewHybridEncrypt(publicHandle)
+			if err != nil {
+				t.Fatalf("NewHybridEncrypt() err = %v", err)
+			}
+			dec, err := NewHybridDecrypt(privateHandle)
+			if err != nil {
+				t.Fatalf("NewHybridDecrypt() err = %v", err)
+			}
+			pt := []byte("plaintext")
+			ct, err := enc.Encrypt(pt, []byte("context info"))
+			if err != nil {
+				t.Fatalf("%s/%s/%s: enc.Encrypt() err = %v", tc.curve, tc.hash, f, err)
+			}
+			got, err := dec.Decrypt(ct, []byte("context info"))
+			if err != nil {
+				t.Fatalf("%s/%s/%s: dec.Decrypt() err = %v", tc.curve, tc.hash, f, err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("%s/%s/%s: dec.Decrypt() = %q, want %q", tc.curve, tc.hash, f, got, pt)
+			}
+		}
+	}
+}
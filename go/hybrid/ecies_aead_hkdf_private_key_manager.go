@@ -62,17 +62,20 @@ func (km *eciesAEADHKDFPrivateKeyKeyManager) Primitive(serializedKey []byte) (in
 	if err := km.validateKey(key); err != nil {
 		return nil, errInvalidECIESAEADHKDFPrivateKeyKey
 	}
-	curve, err := subtle.GetCurve(key.PublicKey.Params.KemParams.CurveType.String())
-	if err != nil {
-		return nil, err
-	}
-	pvt := subtle.GetECPrivateKey(curve, key.KeyValue)
 	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.PublicKey.Params.DemParams.AeadDem)
 	if err != nil {
 		return nil, err
 	}
 	salt := key.PublicKey.Params.KemParams.HkdfSalt
 	hash := key.PublicKey.Params.KemParams.HkdfHashType.String()
+	if key.PublicKey.Params.KemParams.CurveType == commonpb.EllipticCurveType_CURVE25519 {
+		return subtle.NewECIESX25519HKDFHybridDecrypt(key.KeyValue, salt, hash, rDem)
+	}
+	curve, err := subtle.GetCurve(key.PublicKey.Params.KemParams.CurveType.String())
+	if err != nil {
+		return nil, err
+	}
+	pvt := subtle.GetECPrivateKey(curve, key.KeyValue)
 	ptFormat := key.PublicKey.Params.EcPointFormat.String()
 	return subtle.NewECIESAEADHKDFHybridDecrypt(pvt, salt, hash, ptFormat, rDem)
 }
@@ -89,6 +92,21 @@ func (km *eciesAEADHKDFPrivateKeyKeyManager) NewKey(serializedKeyFormat []byte)
 	if err := km.validateKeyFormat(keyFormat); err != nil {
 		return nil, errInvalidECIESAEADHKDFPrivateKeyKeyFormat
 	}
+	if keyFormat.Params.KemParams.CurveType == commonpb.EllipticCurveType_CURVE25519 {
+		pvt, pub, err := subtle.GenerateX25519KeyPair()
+		if err != nil {
+			return nil, err
+		}
+		return &eahpb.EciesAeadHkdfPrivateKey{
+			Version:  eciesAEADHKDFPrivateKeyKeyVersion,
+			KeyValue: pvt,
+			PublicKey: &eahpb.EciesAeadHkdfPublicKey{
+				Version: eciesAEADHKDFPrivateKeyKeyVersion,
+				Params:  keyFormat.Params,
+				X:       pub,
+			},
+		}, nil
+	}
 	curve, err := subtle.GetCurve(keyFormat.Params.KemParams.CurveType.String())
 	if err != nil {
 		return nil, err
@@ -169,8 +187,12 @@ func (km *eciesAEADHKDFPrivateKeyKeyManager) validateKeyFormat(format *eahpb.Eci
 }
 
 func checkECIESAEADHKDFParams(params *eahpb.EciesAeadHkdfParams) error {
-	_, err := subtle.GetCurve(params.KemParams.CurveType.String())
-	if err != nil {
+	if params.KemParams.CurveType == commonpb.EllipticCurveType_CURVE25519 {
+		// X25519 public keys are always encoded as 32-byte u-coordinates.
+		if params.EcPointFormat != commonpb.EcPointFormat_COMPRESSED {
+			return errors.New("CURVE25519 requires the COMPRESSED point format")
+		}
+	} else if _, err := subtle.GetCurve(params.KemParams.CurveType.String()); err != nil {
 		return err
 	}
 	if params.KemParams.HkdfHashType == commonpb.HashType_UNKNOWN_HASH {
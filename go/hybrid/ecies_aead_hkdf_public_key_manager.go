@@ -25,6 +25,7 @@ import (
 	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/hybrid/subtle"
 	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	eahpb "github.com/google/tink/go/proto/ecies_aead_hkdf_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
@@ -63,6 +64,15 @@ func (km *eciesAEADHKDFPublicKeyKeyManager) Primitive(serializedKey []byte) (int
 	if err := km.validateKey(key); err != nil {
 		return nil, errInvalidECIESAEADHKDFPublicKeyKey
 	}
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.Params.DemParams.AeadDem)
+	if err != nil {
+		return nil, err
+	}
+	salt := key.Params.KemParams.HkdfSalt
+	hash := key.Params.KemParams.HkdfHashType.String()
+	if key.Params.KemParams.CurveType == commonpb.EllipticCurveType_CURVE25519 {
+		return subtle.NewECIESX25519HKDFHybridEncrypt(key.X, salt, hash, rDem)
+	}
 	curve, err := subtle.GetCurve(key.Params.KemParams.CurveType.String())
 	if err != nil {
 		return nil, err
@@ -74,12 +84,6 @@ func (km *eciesAEADHKDFPublicKeyKeyManager) Primitive(serializedKey []byte) (int
 			Y: new(big.Int).SetBytes(key.Y),
 		},
 	}
-	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.Params.DemParams.AeadDem)
-	if err != nil {
-		return nil, err
-	}
-	salt := key.Params.KemParams.HkdfSalt
-	hash := key.Params.KemParams.HkdfHashType.String()
 	ptFormat := key.Params.EcPointFormat.String()
 
 	return subtle.NewECIESAEADHKDFHybridEncrypt(&pub, salt, hash, ptFormat, rDem)
@@ -0,0 +1,200 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/daead"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	eahpb "github.com/google/tink/go/proto/ecies_aead_hkdf_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestECIESX25519EncryptDecrypt(t *testing.T) {
+	templates := map[string]*tinkpb.KeyTemplate{
+		"AES128GCM":        aead.AES128GCMKeyTemplate(),
+		"AES256GCM":        aead.AES256GCMKeyTemplate(),
+		"ChaCha20Poly1305": aead.ChaCha20Poly1305KeyTemplate(),
+		"AES128CTRHMAC":    aead.AES128CTRHMACSHA256KeyTemplate(),
+		"AESSIV":           daead.AESSIVKeyTemplate(),
+	}
+	for name, k := range templates {
+		t.Run(name, func(t *testing.T) {
+			pvt, pub, err := subtle.GenerateX25519KeyPair()
+			if err != nil {
+				t.Fatalf("subtle.GenerateX25519KeyPair() err = %v", err)
+			}
+			salt := random.GetRandomBytes(8)
+			pt := random.GetRandomBytes(20)
+			context := []byte("context info")
+			rDem, err := newRegisterECIESAEADHKDFDemHelper(k)
+			if err != nil {
+				t.Fatalf("error generating a DEM helper :%s", err)
+			}
+			e, err := subtle.NewECIESX25519HKDFHybridEncrypt(pub, salt, "SHA256", rDem)
+			if err != nil {
+				t.Fatalf("error generating an encryption construct :%s", err)
+			}
+			d, err := subtle.NewECIESX25519HKDFHybridDecrypt(pvt, salt, "SHA256", rDem)
+			if err != nil {
+				t.Fatalf("error generating an decryption construct :%s", err)
+			}
+			ct, err := e.Encrypt(pt, context)
+			if err != nil {
+				t.Fatalf("encryption error :%s", err)
+			}
+			ct2, err := e.Encrypt(pt, context)
+			if err != nil {
+				t.Fatalf("encryption error :%s", err)
+			}
+			if bytes.Equal(ct, ct2) {
+				t.Errorf("encryption is not randomized")
+			}
+			dt, err := d.Decrypt(ct, context)
+			if err != nil {
+				t.Fatalf("decryption error :%s", err)
+			}
+			if !bytes.Equal(dt, pt) {
+				t.Errorf("decryption not inverse of encryption")
+			}
+			if _, err := d.Decrypt(ct, []byte("other context")); err == nil {
+				t.Errorf("decryption with wrong context info succeeded")
+			}
+			for i := 0; i < len(ct); i++ {
+				modified := append([]byte(nil), ct...)
+				modified[i] ^= 0x01
+				if _, err := d.Decrypt(modified, context); err == nil {
+					t.Errorf("decryption of ciphertext modified at byte %d succeeded", i)
+				}
+			}
+			if _, err := d.Decrypt(ct[:subtle.X25519KeySize-1], context); err == nil {
+				t.Errorf("decryption of truncated ciphertext succeeded")
+			}
+		})
+	}
+}
+
+func TestECIESX25519InvalidKeySizes(t *testing.T) {
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("error generating a DEM helper :%s", err)
+	}
+	if _, err := subtle.NewECIESX25519HKDFHybridEncrypt(make([]byte, 31), nil, "SHA256", rDem); err == nil {
+		t.Errorf("NewECIESX25519HKDFHybridEncrypt() with a 31-byte public key succeeded")
+	}
+	if _, err := subtle.NewECIESX25519HKDFHybridDecrypt(make([]byte, 33), nil, "SHA256", rDem); err == nil {
+		t.Errorf("NewECIESX25519HKDFHybridDecrypt() with a 33-byte private key succeeded")
+	}
+}
+
+func TestECIESX25519KeyManagers(t *testing.T) {
+	templates := []*tinkpb.KeyTemplate{
+		ECIESX25519HKDFAES128GCMKeyTemplate(),
+		ECIESX25519HKDFAES256GCMKeyTemplate(),
+		ECIESX25519HKDFChaCha20Poly1305KeyTemplate(),
+	}
+	for _, tmpl := range templates {
+		privateHandle, err := keyset.NewHandle(tmpl)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v", err)
+		}
+		publicHandle, err := privateHandle.Public()
+		if err != nil {
+			t.Fatalf("privateHandle.Public() err = %v", err)
+		}
+		info := publicHandle.KeysetInfo()
+		if got := info.GetKeyInfo()[0].GetTypeUrl(); got != eciesAEADHKDFPublicKeyTypeURL {
+			t.Errorf("public key type URL = %q, want %q", got, eciesAEADHKDFPublicKeyTypeURL)
+		}
+		enc, err := NewHybridEncrypt(publicHandle)
+		if err != nil {
+			t.Fatalf("NewHybridEncrypt() err = %v", err)
+		}
+		dec, err := NewHybridDecrypt(privateHandle)
+		if err != nil {
+			t.Fatalf("NewHybridDecrypt() err = %v", err)
+		}
+		pt := random.GetRandomBytes(32)
+		ci := []byte("context info")
+		ct, err := enc.Encrypt(pt, ci)
+		if err != nil {
+			t.Fatalf("enc.Encrypt() err = %v", err)
+		}
+		got, err := dec.Decrypt(ct, ci)
+		if err != nil {
+			t.Fatalf("dec.Decrypt() err = %v", err)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("dec.Decrypt() = %x, want %x", got, pt)
+		}
+	}
+}
+
+func TestECIESX25519PrivateKeyManagerNewKey(t *testing.T) {
+	km, err := registry.GetKeyManager(eciesAEADHKDFPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	m, err := km.NewKey(ECIESX25519HKDFChaCha20Poly1305KeyTemplate().GetValue())
+	if err != nil {
+		t.Fatalf("km.NewKey() err = %v", err)
+	}
+	key, ok := m.(*eahpb.EciesAeadHkdfPrivateKey)
+	if !ok {
+		t.Fatalf("km.NewKey() returned %T, want *EciesAeadHkdfPrivateKey", m)
+	}
+	if len(key.GetKeyValue()) != subtle.X25519KeySize {
+		t.Errorf("len(KeyValue) = %d, want %d", len(key.GetKeyValue()), subtle.X25519KeySize)
+	}
+	pub, err := subtle.X25519PublicKey(key.GetKeyValue())
+	if err != nil {
+		t.Fatalf("subtle.X25519PublicKey() err = %v", err)
+	}
+	if !bytes.Equal(key.GetPublicKey().GetX(), pub) {
+		t.Errorf("public key X = %x, want %x", key.GetPublicKey().GetX(), pub)
+	}
+	if len(key.GetPublicKey().GetY()) != 0 {
+		t.Errorf("public key Y = %x, want empty", key.GetPublicKey().GetY())
+	}
+}
+
+func TestECIESX25519PrivateKeyManagerRejectsUncompressedFormat(t *testing.T) {
+	km, err := registry.GetKeyManager(eciesAEADHKDFPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	format := new(eahpb.EciesAeadHkdfKeyFormat)
+	if err := proto.Unmarshal(ECIESX25519HKDFAES128GCMKeyTemplate().GetValue(), format); err != nil {
+		t.Fatalf("proto.Unmarshal() err = %v", err)
+	}
+	format.Params.EcPointFormat = commonpb.EcPointFormat_UNCOMPRESSED
+	serialized, err := proto.Marshal(format)
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v", err)
+	}
+	if _, err := km.NewKey(serialized); err == nil {
+		t.Errorf("km.NewKey() with UNCOMPRESSED point format succeeded")
+	}
+}
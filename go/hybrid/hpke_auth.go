@@ -0,0 +1,194 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+)
+
+var errHPKEAuthDecryptionFailed = errors.New("hpke_auth: decryption failed")
+
+// NewHPKEAuthEncrypt returns a HybridEncrypt primitive that encrypts to the
+// primary key of the public HPKE keyset recipient in HPKE Auth mode, as in
+// RFC 9180. The encapsulation is authenticated with the primary key of the
+// private HPKE keyset sender, so that a successful decryption proves that the
+// ciphertext was produced by the holder of the sender key. Both keys must use
+// the same HPKE parameters, and neither may have expired.
+//
+// If psk is not empty, AuthPSK mode is used instead and the recipient must
+// know the same psk and pskID. The returned ciphertexts are prefixed with the
+// output prefix of the recipient key.
+func NewHPKEAuthEncrypt(recipient, sender *keyset.Handle, psk, pskID []byte) (tink.HybridEncrypt, error) {
+	recipientEntry, err := hpkePrimary(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_auth: recipient: %w", err)
+	}
+	senderEntry, err := hpkePrimary(sender)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_auth: sender: %w", err)
+	}
+	recipientEnc, ok := recipientEntry.Primitive.(*subtle.HPKEHybridEncrypt)
+	if !ok {
+		return nil, errors.New("hpke_auth: recipient primary key is not an HPKE public key")
+	}
+	senderDec, ok := senderEntry.Primitive.(*subtle.HPKEHybridDecrypt)
+	if !ok {
+		return nil, errors.New("hpke_auth: sender primary key is not an HPKE private key")
+	}
+	e, err := subtle.NewHPKEAuthHybridEncryptFromBase(recipientEnc, senderDec, psk, pskID)
+	if errors.Is(err, subtle.ErrHPKEAlgorithmMismatch) {
+		return nil, errors.New("hpke_auth: recipient and sender keys have different parameters")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hpkeAuthEncrypt{prefix: recipientEntry.Prefix, e: e}, nil
+}
+
+// NewHPKEAuthDecrypt returns a HybridDecrypt primitive that decrypts
+// ciphertexts of NewHPKEAuthEncrypt with the private HPKE keyset recipient,
+// and only accepts them if they were authenticated by a key of the HPKE keyset
+// sender. sender may be a public or a private keyset; all of its enabled keys
+// are accepted, which allows the sender to rotate keys.
+func NewHPKEAuthDecrypt(recipient, sender *keyset.Handle, psk, pskID []byte) (tink.HybridDecrypt, error) {
+	senderEncs, err := hpkeSenderKeys(sender)
+	if err != nil {
+		return nil, fmt.Errorf("hpke_auth: sender: %w", err)
+	}
+	ps, err := recipient.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("hpke_auth: recipient: cannot obtain primitive set: %w", err)
+	}
+	ret := &hpkeAuthDecrypt{entries: make(map[string][]tink.HybridDecrypt)}
+	for prefix, entries := range ps.Entries {
+		for _, entry := range entries {
+			recipientDec, ok := entry.Primitive.(*subtle.HPKEHybridDecrypt)
+			if !ok {
+				return nil, fmt.Errorf("hpke_auth: recipient key %d is not an HPKE private key", entry.KeyID)
+			}
+			for _, senderEnc := range senderEncs {
+				d, err := subtle.NewHPKEAuthHybridDecryptFromBase(recipientDec, senderEnc, psk, pskID)
+				if errors.Is(err, subtle.ErrHPKEAlgorithmMismatch) {
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				ret.entries[prefix] = append(ret.entries[prefix], d)
+			}
+		}
+	}
+	if len(ret.entries) == 0 {
+		return nil, errors.New("hpke_auth: no recipient key matches the parameters of a sender key")
+	}
+	return ret, nil
+}
+
+// hpkePrimary returns the primary entry of the primitive set of h, which is
+// obtained through the key managers and checked against the key type policy.
+// It fails if the primary key has expired.
+func hpkePrimary(h *keyset.Handle) (*primitiveset.Entry, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain primitive set: %w", err)
+	}
+	if ps.Primary == nil {
+		return nil, errors.New("keyset has no enabled primary key")
+	}
+	if err := ps.Primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
+	return ps.Primary, nil
+}
+
+// hpkeSenderKeys returns the base mode HPKE encryption primitives of the
+// enabled keys of the public or private keyset h.
+func hpkeSenderKeys(h *keyset.Handle) ([]*subtle.HPKEHybridEncrypt, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain primitive set: %w", err)
+	}
+	if ps.Primary == nil {
+		return nil, errors.New("keyset has no enabled primary key")
+	}
+	// The public keys of a private keyset are obtained from its public keyset.
+	if _, ok := ps.Primary.Primitive.(*subtle.HPKEHybridDecrypt); ok {
+		pub, err := h.Public()
+		if err != nil {
+			return nil, err
+		}
+		if ps, err = pub.Primitives(); err != nil {
+			return nil, fmt.Errorf("cannot obtain primitive set: %w", err)
+		}
+	}
+	var ret []*subtle.HPKEHybridEncrypt
+	for _, entries := range ps.Entries {
+		for _, entry := range entries {
+			e, ok := entry.Primitive.(*subtle.HPKEHybridEncrypt)
+			if !ok {
+				return nil, fmt.Errorf("key %d is not an HPKE key", entry.KeyID)
+			}
+			ret = append(ret, e)
+		}
+	}
+	return ret, nil
+}
+
+type hpkeAuthEncrypt struct {
+	prefix string
+	e      tink.HybridEncrypt
+}
+
+func (a *hpkeAuthEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	ct, err := a.e.Encrypt(plaintext, contextInfo)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(a.prefix), ct...), nil
+}
+
+type hpkeAuthDecrypt struct {
+	// entries maps output prefixes to the decrypters of the recipient keys
+	// with that prefix, one per matching sender key.
+	entries map[string][]tink.HybridDecrypt
+}
+
+func (a *hpkeAuthDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	// try non-raw keys
+	prefixSize := cryptofmt.NonRawPrefixSize
+	if len(ciphertext) > prefixSize {
+		for _, d := range a.entries[string(ciphertext[:prefixSize])] {
+			if pt, err := d.Decrypt(ciphertext[prefixSize:], contextInfo); err == nil {
+				return pt, nil
+			}
+		}
+	}
+	// try raw keys
+	for _, d := range a.entries[cryptofmt.RawPrefix] {
+		if pt, err := d.Decrypt(ciphertext, contextInfo); err == nil {
+			return pt, nil
+		}
+	}
+	return nil, errHPKEAuthDecryptionFailed
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestHPKEAuthEncryptDecrypt(t *testing.T) {
+	psk := random.GetRandomBytes(32)
+	for _, template := range []*tinkpb.KeyTemplate{
+		DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate(),
+		DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate(),
+	} {
+		recipientPriv, recipientPub := hybridKeyHandles(t, template)
+		senderPriv, senderPub := hybridKeyHandles(t, template)
+		for _, tc := range []struct {
+			name  string
+			psk   []byte
+			pskID []byte
+		}{
+			{"Auth", nil, nil},
+			{"AuthPSK", psk, []byte("psk id")},
+		} {
+			enc, err := NewHPKEAuthEncrypt(recipientPub, senderPriv, tc.psk, tc.pskID)
+			if err != nil {
+				t.Fatalf("%s: NewHPKEAuthEncrypt() err = %v", tc.name, err)
+			}
+			pt := random.GetRandomBytes(50)
+			ci := []byte("context info")
+			ct, err := enc.Encrypt(pt, ci)
+			if err != nil {
+				t.Fatalf("%s: enc.Encrypt() err = %v", tc.name, err)
+			}
+			// The sender may be given as a public or private keyset.
+			for _, sender := range []*keyset.Handle{senderPub, senderPriv} {
+				dec, err := NewHPKEAuthDecrypt(recipientPriv, sender, tc.psk, tc.pskID)
+				if err != nil {
+					t.Fatalf("%s: NewHPKEAuthDecrypt() err = %v", tc.name, err)
+				}
+				got, err := dec.Decrypt(ct, ci)
+				if err != nil {
+					t.Fatalf("%s: dec.Decrypt() err = %v", tc.name, err)
+				}
+				if !bytes.Equal(got, pt) {
+					t.Errorf("%s: dec.Decrypt() = %x, want %x", tc.name, got, pt)
+				}
+				if _, err := dec.Decrypt(ct, []byte("other context")); err == nil {
+					t.Errorf("%s: dec.Decrypt() with wrong context info succeeded", tc.name)
+				}
+			}
+			// Base mode decryption must not accept authenticated ciphertexts.
+			base, err := NewHybridDecrypt(recipientPriv)
+			if err != nil {
+				t.Fatalf("NewHybridDecrypt() err = %v", err)
+			}
+			if _, err := base.Decrypt(ct, ci); err == nil {
+				t.Errorf("%s: base mode decryption succeeded", tc.name)
+			}
+		}
+	}
+}
+
+func TestHPKEAuthDecryptWithWrongSender(t *testing.T) {
+	template := DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate()
+	recipientPriv, recipientPub := hybridKeyHandles(t, template)
+	senderPriv, _ := hybridKeyHandles(t, template)
+	_, otherPub := hybridKeyHandles(t, template)
+	enc, err := NewHPKEAuthEncrypt(recipientPub, senderPriv, nil, nil)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthEncrypt() err = %v", err)
+	}
+	ct, err := enc.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("enc.Encrypt() err = %v", err)
+	}
+	dec, err := NewHPKEAuthDecrypt(recipientPriv, otherPub, nil, nil)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthDecrypt() err = %v", err)
+	}
+	if _, err := dec.Decrypt(ct, nil); err == nil {
+		t.Errorf("dec.Decrypt() with the wrong sender succeeded")
+	}
+}
+
+func TestHPKEAuthDecryptAfterSenderRotation(t *testing.T) {
+	template := DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate()
+	recipientPriv, recipientPub := hybridKeyHandles(t, template)
+	senderPriv, _ := hybridKeyHandles(t, template)
+	oldEnc, err := NewHPKEAuthEncrypt(recipientPub, senderPriv, nil, nil)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthEncrypt() err = %v", err)
+	}
+	oldCT, err := oldEnc.Encrypt([]byte("old"), nil)
+	if err != nil {
+		t.Fatalf("oldEnc.Encrypt() err = %v", err)
+	}
+	manager := keyset.NewManagerFromHandle(senderPriv)
+	if err := manager.Rotate(template); err != nil {
+		t.Fatalf("manager.Rotate() err = %v", err)
+	}
+	rotated, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	rotatedPub, err := rotated.Public()
+	if err != nil {
+		t.Fatalf("rotated.Public() err = %v", err)
+	}
+	newEnc, err := NewHPKEAuthEncrypt(recipientPub, rotated, nil, nil)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthEncrypt() err = %v", err)
+	}
+	newCT, err := newEnc.Encrypt([]byte("new"), nil)
+	if err != nil {
+		t.Fatalf("newEnc.Encrypt() err = %v", err)
+	}
+	dec, err := NewHPKEAuthDecrypt(recipientPriv, rotatedPub, nil, nil)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthDecrypt() err = %v", err)
+	}
+	for _, ct := range [][]byte{oldCT, newCT} {
+		if _, err := dec.Decrypt(ct, nil); err != nil {
+			t.Errorf("dec.Decrypt() err = %v", err)
+		}
+	}
+}
+
+func TestHPKEAuthInvalidKeysets(t *testing.T) {
+	hpkePriv, hpkePub := hybridKeyHandles(t, DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate())
+	otherParamsPriv, otherParamsPub := hybridKeyHandles(t, DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate())
+	eciesPriv, eciesPub := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	for _, tc := range []struct {
+		name      string
+		recipient *keyset.Handle
+		sender    *keyset.Handle
+	}{
+		{"private recipient", hpkePriv, hpkePriv},
+		{"public sender", hpkePub, hpkePub},
+		{"ECIES recipient", eciesPub, hpkePriv},
+		{"ECIES sender", hpkePub, eciesPriv},
+		{"different parameters", hpkePub, otherParamsPriv},
+	} {
+		if _, err := NewHPKEAuthEncrypt(tc.recipient, tc.sender, nil, nil); err == nil {
+			t.Errorf("%s: NewHPKEAuthEncrypt() err = nil, want error", tc.name)
+		}
+	}
+	for _, tc := range []struct {
+		name      string
+		recipient *keyset.Handle
+		sender    *keyset.Handle
+	}{
+		{"public recipient", hpkePub, hpkePub},
+		{"ECIES recipient", eciesPriv, hpkePub},
+		{"ECIES sender", hpkePriv, eciesPub},
+		{"different parameters", hpkePriv, otherParamsPub},
+	} {
+		if _, err := NewHPKEAuthDecrypt(tc.recipient, tc.sender, nil, nil); err == nil {
+			t.Errorf("%s: NewHPKEAuthDecrypt() err = nil, want error", tc.name)
+		}
+	}
+}
+
+func TestHPKEAuthRejectsExpiredKeys(t *testing.T) {
+	template := DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate()
+	recipientPriv, recipientPub := hybridKeyHandles(t, template)
+	senderPriv, _ := hybridKeyHandles(t, template)
+	expire := func(h *keyset.Handle) *keyset.Handle {
+		expired, err := h.WithKeyExpiration(h.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("h.WithKeyExpiration() err = %v", err)
+		}
+		return expired
+	}
+	if _, err := NewHPKEAuthEncrypt(recipientPub, expire(senderPriv), nil, nil); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("NewHPKEAuthEncrypt() with an expired sender key err = %v, want ErrKeyExpired", err)
+	}
+	if _, err := NewHPKEAuthEncrypt(expire(recipientPub), senderPriv, nil, nil); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("NewHPKEAuthEncrypt() with an expired recipient key err = %v, want ErrKeyExpired", err)
+	}
+	// Expired keys may still decrypt existing ciphertexts.
+	if _, err := NewHPKEAuthDecrypt(expire(recipientPriv), senderPriv, nil, nil); err != nil {
+		t.Errorf("NewHPKEAuthDecrypt() with an expired recipient key err = %v", err)
+	}
+}
+
+func TestHPKEAuthChecksKeyTypePolicy(t *testing.T) {
+	template := DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate()
+	recipientPriv, recipientPub := hybridKeyHandles(t, template)
+	senderPriv, senderPub := hybridKeyHandles(t, template)
+	for _, typeURL := range []string{hpkePrivateKeyTypeURL, hpkePublicKeyTypeURL} {
+		registry.SetKeyTypePolicy(&registry.KeyTypeRules{DeniedTypeURLs: []string{typeURL}})
+		if _, err := NewHPKEAuthEncrypt(recipientPub, senderPriv, nil, nil); err == nil {
+			t.Errorf("NewHPKEAuthEncrypt() with %s denied by policy succeeded", typeURL)
+		}
+		if _, err := NewHPKEAuthDecrypt(recipientPriv, senderPub, nil, nil); err == nil {
+			t.Errorf("NewHPKEAuthDecrypt() with %s denied by policy succeeded", typeURL)
+		}
+		registry.SetKeyTypePolicy(nil)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestHPKEPrivateKeyManagerNewKey(t *testing.T) {
+	km := newHPKEPrivateKeyKeyManager()
+	for _, a := range []hpkepb.HpkeAead{hpkepb.HpkeAead_AES_128_GCM, hpkepb.HpkeAead_AES_256_GCM, hpkepb.HpkeAead_CHACHA20_POLY1305} {
+		kt := createHPKEKeyTemplate(a, tinkpb.OutputPrefixType_TINK)
+		m, err := km.NewKey(kt.Value)
+		if err != nil {
+			t.Fatalf("km.NewKey(%s) err = %v", a, err)
+		}
+		key := m.(*hpkepb.HpkePrivateKey)
+		pub, err := subtle.X25519PublicKey(key.PrivateKey)
+		if err != nil {
+			t.Fatalf("subtle.X25519PublicKey() err = %v", err)
+		}
+		if !bytes.Equal(pub, key.PublicKey.PublicKey) {
+			t.Errorf("public key does not match private key")
+		}
+		if key.PublicKey.Params.Aead != a {
+			t.Errorf("key.PublicKey.Params.Aead = %s, want %s", key.PublicKey.Params.Aead, a)
+		}
+	}
+}
+
+func TestHPKEPrivateKeyManagerNewKeyWithInvalidFormat(t *testing.T) {
+	km := newHPKEPrivateKeyKeyManager()
+	for _, params := range []*hpkepb.HpkeParams{
+		nil,
+		{Kem: hpkepb.HpkeKem_KEM_UNKNOWN, Kdf: hpkepb.HpkeKdf_HKDF_SHA256, Aead: hpkepb.HpkeAead_AES_128_GCM},
+		{Kem: hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256, Kdf: hpkepb.HpkeKdf_KDF_UNKNOWN, Aead: hpkepb.HpkeAead_AES_128_GCM},
+		{Kem: hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256, Kdf: hpkepb.HpkeKdf_HKDF_SHA256, Aead: hpkepb.HpkeAead_AEAD_UNKNOWN},
+	} {
+		serializedFormat, _ := proto.Marshal(&hpkepb.HpkeKeyFormat{Params: params})
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("km.NewKey() with params %v succeeded", params)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("km.NewKey(nil) succeeded")
+	}
+}
+
+func TestHPKEKeyManagersPrimitive(t *testing.T) {
+	privKM := newHPKEPrivateKeyKeyManager()
+	pubKM := newHPKEPublicKeyKeyManager()
+	kd, err := privKM.NewKeyData(DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate().Value)
+	if err != nil {
+		t.Fatalf("privKM.NewKeyData() err = %v", err)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PRIVATE {
+		t.Errorf("kd.KeyMaterialType = %s, want ASYMMETRIC_PRIVATE", kd.KeyMaterialType)
+	}
+	pubKD, err := privKM.PublicKeyData(kd.Value)
+	if err != nil {
+		t.Fatalf("privKM.PublicKeyData() err = %v", err)
+	}
+	if pubKD.TypeUrl != hpkePublicKeyTypeURL {
+		t.Errorf("pubKD.TypeUrl = %s, want %s", pubKD.TypeUrl, hpkePublicKeyTypeURL)
+	}
+	d, err := privKM.Primitive(kd.Value)
+	if err != nil {
+		t.Fatalf("privKM.Primitive() err = %v", err)
+	}
+	e, err := pubKM.Primitive(pubKD.Value)
+	if err != nil {
+		t.Fatalf("pubKM.Primitive() err = %v", err)
+	}
+	pt := random.GetRandomBytes(20)
+	ct, err := e.(*subtle.HPKEHybridEncrypt).Encrypt(pt, []byte("context"))
+	if err != nil {
+		t.Fatalf("Encrypt() err = %v", err)
+	}
+	got, err := d.(*subtle.HPKEHybridDecrypt).Decrypt(ct, []byte("context"))
+	if err != nil {
+		t.Fatalf("Decrypt() err = %v", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("Decrypt() = %x, want %x", got, pt)
+	}
+}
+
+func TestHPKEKeyManagersPrimitiveWithInvalidKey(t *testing.T) {
+	privKM := newHPKEPrivateKeyKeyManager()
+	pubKM := newHPKEPublicKeyKeyManager()
+	m, err := privKM.NewKey(DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate().Value)
+	if err != nil {
+		t.Fatalf("privKM.NewKey() err = %v", err)
+	}
+	key := m.(*hpkepb.HpkePrivateKey)
+
+	badVersion := proto.Clone(key).(*hpkepb.HpkePrivateKey)
+	badVersion.Version = hpkePrivateKeyKeyVersion + 1
+	badSize := proto.Clone(key).(*hpkepb.HpkePrivateKey)
+	badSize.PrivateKey = badSize.PrivateKey[1:]
+	noPublicKey := proto.Clone(key).(*hpkepb.HpkePrivateKey)
+	noPublicKey.PublicKey = nil
+	for _, k := range []*hpkepb.HpkePrivateKey{badVersion, badSize, noPublicKey} {
+		serializedKey, _ := proto.Marshal(k)
+		if _, err := privKM.Primitive(serializedKey); err == nil {
+			t.Errorf("privKM.Primitive(%v) succeeded", k)
+		}
+	}
+
+	badPubVersion := proto.Clone(key.PublicKey).(*hpkepb.HpkePublicKey)
+	badPubVersion.Version = hpkePublicKeyKeyVersion + 1
+	badPubSize := proto.Clone(key.PublicKey).(*hpkepb.HpkePublicKey)
+	badPubSize.PublicKey = badPubSize.PublicKey[1:]
+	for _, k := range []*hpkepb.HpkePublicKey{badPubVersion, badPubSize} {
+		serializedKey, _ := proto.Marshal(k)
+		if _, err := pubKM.Primitive(serializedKey); err == nil {
+			t.Errorf("pubKM.Primitive(%v) succeeded", k)
+		}
+	}
+}
+
+func TestHPKEFactoryWithRawAndTinkKeys(t *testing.T) {
+	for _, kt := range []*tinkpb.KeyTemplate{
+		DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate(),
+		DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate(),
+	} {
+		privateHandle, err := keyset.NewHandle(kt)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() err = %v", err)
+		}
+		publicHandle, err := privateHandle.Public()
+		if err != nil {
+			t.Fatalf("privateHandle.Public() err = %v", err)
+		}
+		enc, err := NewHybridEncrypt(publicHandle)
+		if err != nil {
+			t.Fatalf("NewHybridEncrypt() err = %v", err)
+		}
+		dec, err := NewHybridDecrypt(privateHandle)
+		if err != nil {
+			t.Fatalf("NewHybridDecrypt() err = %v", err)
+		}
+		pt := []byte("plaintext")
+		ct, err := enc.Encrypt(pt, nil)
+		if err != nil {
+			t.Fatalf("enc.Encrypt() err = %v", err)
+		}
+		prefixSize := 0
+		if kt.OutputPrefixType == tinkpb.OutputPrefixType_TINK {
+			prefixSize = 5
+		}
+		if want := prefixSize + subtle.X25519KeySize + len(pt) + 16; len(ct) != want {
+			t.Errorf("len(ct) = %d, want %d", len(ct), want)
+		}
+		got, err := dec.Decrypt(ct, nil)
+		if err != nil {
+			t.Fatalf("dec.Decrypt() err = %v", err)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("dec.Decrypt() = %q, want %q", got, pt)
+		}
+	}
+}
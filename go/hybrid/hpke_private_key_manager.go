@@ -0,0 +1,195 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	hpkePrivateKeyKeyVersion = 0
+	hpkePrivateKeyTypeURL    = "type.googleapis.com/google.crypto.tink.HpkePrivateKey"
+)
+
+// common errors
+var errInvalidHPKEPrivateKeyKey = fmt.Errorf("hpke_private_key_manager: invalid key")
+var errInvalidHPKEPrivateKeyKeyFormat = fmt.Errorf("hpke_private_key_manager: invalid key format")
+
+// hpkePrivateKeyKeyManager is an implementation of PrivateKeyManager interface.
+// It generates new HpkePrivateKey keys and produces new instances of HPKEHybridDecrypt subtle.
+type hpkePrivateKeyKeyManager struct{}
+
+// Assert that hpkePrivateKeyKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*hpkePrivateKeyKeyManager)(nil)
+
+// newHPKEPrivateKeyKeyManager creates a new hpkePrivateKeyKeyManager.
+func newHPKEPrivateKeyKeyManager() *hpkePrivateKeyKeyManager {
+	return new(hpkePrivateKeyKeyManager)
+}
+
+// Primitive creates an HPKEHybridDecrypt subtle for the given serialized HpkePrivateKey proto.
+func (km *hpkePrivateKeyKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidHPKEPrivateKeyKey
+	}
+	key := new(hpkepb.HpkePrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidHPKEPrivateKeyKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, errInvalidHPKEPrivateKeyKey
+	}
+	kem, kdf, aead, err := hpkeAlgorithms(key.PublicKey.Params)
+	if err != nil {
+		return nil, err
+	}
+	return subtle.NewHPKEHybridDecrypt(key.PrivateKey, kem, kdf, aead)
+}
+
+// NewKey creates a new key according to specification the given serialized HpkeKeyFormat.
+func (km *hpkePrivateKeyKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidHPKEPrivateKeyKeyFormat
+	}
+	keyFormat := new(hpkepb.HpkeKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidHPKEPrivateKeyKeyFormat
+	}
+	if _, _, _, err := hpkeAlgorithms(keyFormat.Params); err != nil {
+		return nil, errInvalidHPKEPrivateKeyKeyFormat
+	}
+	pvt, pub, err := subtle.GenerateX25519KeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	return &hpkepb.HpkePrivateKey{
+		Version:    hpkePrivateKeyKeyVersion,
+		PrivateKey: pvt,
+		PublicKey: &hpkepb.HpkePublicKey{
+			Version:   hpkePrivateKeyKeyVersion,
+			Params:    keyFormat.Params,
+			PublicKey: pub,
+		},
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// HpkeKeyFormat.
+// It should be used solely by the key management API.
+func (km *hpkePrivateKeyKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         hpkePrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *hpkePrivateKeyKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(hpkepb.HpkePrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidHPKEPrivateKeyKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidHPKEPrivateKeyKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         hpkePublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *hpkePrivateKeyKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == hpkePrivateKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *hpkePrivateKeyKeyManager) TypeURL() string {
+	return hpkePrivateKeyTypeURL
+}
+
+// validateKey validates the given HpkePrivateKey.
+func (km *hpkePrivateKeyKeyManager) validateKey(key *hpkepb.HpkePrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, hpkePrivateKeyKeyVersion); err != nil {
+		return fmt.Errorf("hpke_private_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil {
+		return errors.New("hpke_private_key_manager: missing public key")
+	}
+	if len(key.PrivateKey) != subtle.X25519KeySize {
+		return errors.New("hpke_private_key_manager: invalid private key size")
+	}
+	_, _, _, err := hpkeAlgorithms(key.PublicKey.Params)
+	return err
+}
+
+// hpkeAlgorithms returns the HPKE algorithm identifiers of the given params.
+func hpkeAlgorithms(params *hpkepb.HpkeParams) (subtle.HPKEKEM, subtle.HPKEKDF, subtle.HPKEAEAD, error) {
+	if params == nil {
+		return 0, 0, 0, errors.New("missing HPKE params")
+	}
+	var kem subtle.HPKEKEM
+	switch params.Kem {
+	case hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256:
+		kem = subtle.HPKEDHKEMX25519HKDFSHA256
+	default:
+		return 0, 0, 0, fmt.Errorf("unsupported HPKE KEM %s", params.Kem)
+	}
+	var kdf subtle.HPKEKDF
+	switch params.Kdf {
+	case hpkepb.HpkeKdf_HKDF_SHA256:
+		kdf = subtle.HPKEHKDFSHA256
+	case hpkepb.HpkeKdf_HKDF_SHA384:
+		kdf = subtle.HPKEHKDFSHA384
+	case hpkepb.HpkeKdf_HKDF_SHA512:
+		kdf = subtle.HPKEHKDFSHA512
+	default:
+		return 0, 0, 0, fmt.Errorf("unsupported HPKE KDF %s", params.Kdf)
+	}
+	var aead subtle.HPKEAEAD
+	switch params.Aead {
+	case hpkepb.HpkeAead_AES_128_GCM:
+		aead = subtle.HPKEAES128GCM
+	case hpkepb.HpkeAead_AES_256_GCM:
+		aead = subtle.HPKEAES256GCM
+	case hpkepb.HpkeAead_CHACHA20_POLY1305:
+		aead = subtle.HPKEChaCha20Poly1305
+	default:
+		return 0, 0, 0, fmt.Errorf("unsupported HPKE AEAD %s", params.Aead)
+	}
+	return kem, kdf, aead, nil
+}
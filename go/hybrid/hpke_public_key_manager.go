@@ -0,0 +1,100 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	hpkePublicKeyKeyVersion = 0
+	hpkePublicKeyTypeURL    = "type.googleapis.com/google.crypto.tink.HpkePublicKey"
+)
+
+// common errors
+var errInvalidHPKEPublicKeyKey = fmt.Errorf("hpke_public_key_manager: invalid key")
+
+// hpkePublicKeyKeyManager is an implementation of KeyManager interface.
+// It produces new instances of HPKEHybridEncrypt subtle.
+type hpkePublicKeyKeyManager struct{}
+
+// Assert that hpkePublicKeyKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*hpkePublicKeyKeyManager)(nil)
+
+// newHPKEPublicKeyKeyManager creates a new hpkePublicKeyKeyManager.
+func newHPKEPublicKeyKeyManager() *hpkePublicKeyKeyManager {
+	return new(hpkePublicKeyKeyManager)
+}
+
+// Primitive creates an HPKEHybridEncrypt subtle for the given serialized HpkePublicKey proto.
+func (km *hpkePublicKeyKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidHPKEPublicKeyKey
+	}
+	key := new(hpkepb.HpkePublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidHPKEPublicKeyKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, errInvalidHPKEPublicKeyKey
+	}
+	kem, kdf, aead, err := hpkeAlgorithms(key.Params)
+	if err != nil {
+		return nil, err
+	}
+	return subtle.NewHPKEHybridEncrypt(key.PublicKey, kem, kdf, aead)
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *hpkePublicKeyKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == hpkePublicKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *hpkePublicKeyKeyManager) TypeURL() string {
+	return hpkePublicKeyTypeURL
+}
+
+// validateKey validates the given HpkePublicKey.
+func (km *hpkePublicKeyKeyManager) validateKey(key *hpkepb.HpkePublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, hpkePublicKeyKeyVersion); err != nil {
+		return fmt.Errorf("hpke_public_key_manager: invalid key: %s", err)
+	}
+	if len(key.PublicKey) != subtle.X25519KeySize {
+		return errors.New("hpke_public_key_manager: invalid public key size")
+	}
+	_, _, _, err := hpkeAlgorithms(key.Params)
+	return err
+}
+
+// NewKey is not implemented for public key manager.
+func (km *hpkePublicKeyKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("public key manager does not implement NewKey")
+}
+
+// NewKeyData is not implemented for public key manager.
+func (km *hpkePublicKeyKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("public key manager does not implement NewKeyData")
+}
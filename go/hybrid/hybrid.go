@@ -44,4 +44,15 @@ func init() {
 	if err := registry.RegisterKeyManager(newECIESAEADHKDFPublicKeyKeyManager()); err != nil {
 		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
 	}
+	if err := registry.RegisterKeyManager(newHPKEPrivateKeyKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newHPKEPublicKeyKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+		}
+	}
 }
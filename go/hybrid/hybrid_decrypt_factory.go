@@ -17,6 +17,7 @@
 package hybrid
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/google/tink/go/core/cryptofmt"
@@ -43,6 +44,46 @@ func NewHybridDecryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (t
 	return newWrappedHybridDecrypt(ps)
 }
 
+// NewHybridDecryptWithRegistry returns an HybridDecrypt primitive from the
+// given keyset handle, using the key managers of r instead of the global
+// registry.
+func NewHybridDecryptWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.HybridDecrypt, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedHybridDecrypt(ps)
+}
+
+// KeyIDHybridDecrypt is a HybridDecrypt that can also report which key of the
+// keyset decrypted a ciphertext. This is useful to monitor key rotation, e.g.
+// to find out whether old keys are still receiving traffic before disabling
+// them.
+type KeyIDHybridDecrypt interface {
+	tink.HybridDecrypt
+
+	// DecryptWithKeyID decrypts ciphertext verifying the integrity of
+	// contextInfo. It returns the plaintext and the ID of the key that
+	// successfully decrypted the ciphertext.
+	DecryptWithKeyID(ciphertext, contextInfo []byte) ([]byte, uint32, error)
+}
+
+// NewHybridDecryptWithKeyID returns a KeyIDHybridDecrypt primitive from the
+// given keyset handle.
+func NewHybridDecryptWithKeyID(h *keyset.Handle) (KeyIDHybridDecrypt, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+
+	return newWrappedHybridDecrypt(ps)
+}
+
+// ErrDecryptionFailed is returned when a ciphertext cannot be decrypted with
+// any key of the keyset, as opposed to errors caused by an invalid keyset or
+// configuration. Use errors.Is to test for it.
+var ErrDecryptionFailed = errors.New("hybrid_factory: decryption failed")
+
 // wrappedHybridDecrypt is an HybridDecrypt implementation that uses the underlying primitive set
 // for decryption.
 type wrappedHybridDecrypt struct {
@@ -72,6 +113,13 @@ func newWrappedHybridDecrypt(ps *primitiveset.PrimitiveSet) (*wrappedHybridDecry
 // additional authenticated data. It returns the corresponding plaintext if the
 // ciphertext is authenticated.
 func (a *wrappedHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
+	pt, _, err := a.DecryptWithKeyID(ct, ad)
+	return pt, err
+}
+
+// DecryptWithKeyID is like Decrypt, but also returns the ID of the key that
+// decrypted the ciphertext.
+func (a *wrappedHybridDecrypt) DecryptWithKeyID(ct, ad []byte) ([]byte, uint32, error) {
 	// try non-raw keys
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(ct) > prefixSize {
@@ -82,12 +130,12 @@ func (a *wrappedHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
 			for i := 0; i < len(entries); i++ {
 				p, ok := (entries[i].Primitive).(tink.HybridDecrypt)
 				if !ok {
-					return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+					return nil, 0, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
 				}
 
 				pt, err := p.Decrypt(ctNoPrefix, ad)
 				if err == nil {
-					return pt, nil
+					return pt, entries[i].KeyID, nil
 				}
 			}
 		}
@@ -99,16 +147,16 @@ func (a *wrappedHybridDecrypt) Decrypt(ct, ad []byte) ([]byte, error) {
 		for i := 0; i < len(entries); i++ {
 			p, ok := (entries[i].Primitive).(tink.HybridDecrypt)
 			if !ok {
-				return nil, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
+				return nil, 0, fmt.Errorf("hybrid_factory: not a HybridDecrypt primitive")
 			}
 
 			pt, err := p.Decrypt(ct, ad)
 			if err == nil {
-				return pt, nil
+				return pt, entries[i].KeyID, nil
 			}
 		}
 	}
 
 	// nothing worked
-	return nil, fmt.Errorf("hybrid_factory: decryption failed")
+	return nil, 0, ErrDecryptionFailed
 }
@@ -41,6 +41,17 @@ func NewHybridEncryptWithKeyManager(h *keyset.Handle, km registry.KeyManager) (t
 	return newEncryptPrimitiveSet(ps)
 }
 
+// NewHybridEncryptWithRegistry returns an HybridEncrypt primitive from the
+// given keyset handle, using the key managers of r instead of the global
+// registry.
+func NewHybridEncryptWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.HybridEncrypt, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("hybrid_factory: cannot obtain primitive set: %s", err)
+	}
+	return newEncryptPrimitiveSet(ps)
+}
+
 // encryptPrimitiveSet is an HybridEncrypt implementation that uses the underlying primitive set for encryption.
 type wrappedHybridEncrypt struct {
 	ps *primitiveset.PrimitiveSet
@@ -69,6 +80,9 @@ func newEncryptPrimitiveSet(ps *primitiveset.PrimitiveSet) (*wrappedHybridEncryp
 // It returns the concatenation of the primary's identifier and the ciphertext.
 func (a *wrappedHybridEncrypt) Encrypt(pt, ad []byte) ([]byte, error) {
 	primary := a.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
 	p, ok := (primary.Primitive).(tink.HybridEncrypt)
 	if !ok {
 		return nil, fmt.Errorf("hybrid_factory: not a HybridEncrypt primitive")
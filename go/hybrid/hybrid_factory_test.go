@@ -18,6 +18,7 @@ package hybrid
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -141,3 +142,105 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Fatalf("calling NewHybridDecrypt() with good *keyset.Handle failed %s", err)
 	}
 }
+
+func TestFactoryDecryptionFailedIsErrDecryptionFailed(t *testing.T) {
+	privKH, err := keyset.NewHandle(ECIESHKDFAES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	pubKH, err := privKH.Public()
+	if err != nil {
+		t.Fatalf("privKH.Public failed: %s", err)
+	}
+	e, err := NewHybridEncrypt(pubKH)
+	if err != nil {
+		t.Fatalf("NewHybridEncrypt failed: %s", err)
+	}
+	d, err := NewHybridDecrypt(privKH)
+	if err != nil {
+		t.Fatalf("NewHybridDecrypt failed: %s", err)
+	}
+	ct, err := e.Encrypt([]byte("plaintext"), []byte("context info"))
+	if err != nil {
+		t.Fatalf("e.Encrypt failed: %s", err)
+	}
+	if _, err := d.Decrypt(ct, []byte("other context info")); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("d.Decrypt with wrong context info = %v, want ErrDecryptionFailed", err)
+	}
+}
+
+func TestFactoryDecryptWithKeyIDReportsMatchingKey(t *testing.T) {
+	privKH, pubKH := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	oldKeyID := privKH.KeysetInfo().GetPrimaryKeyId()
+	oldEnc, err := NewHybridEncrypt(pubKH)
+	if err != nil {
+		t.Fatalf("NewHybridEncrypt failed: %s", err)
+	}
+	ci := []byte("context info")
+	oldCT, err := oldEnc.Encrypt([]byte("old plaintext"), ci)
+	if err != nil {
+		t.Fatalf("oldEnc.Encrypt failed: %s", err)
+	}
+
+	// Rotate to a RAW key, so that both prefixed and raw entries are covered.
+	manager := keyset.NewManagerFromHandle(privKH)
+	if err := manager.Rotate(DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMRawKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	rotatedPrivKH, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+	newKeyID := rotatedPrivKH.KeysetInfo().GetPrimaryKeyId()
+	if newKeyID == oldKeyID {
+		t.Fatalf("primary key ID did not change after rotation")
+	}
+	rotatedPubKH, err := rotatedPrivKH.Public()
+	if err != nil {
+		t.Fatalf("rotatedPrivKH.Public failed: %s", err)
+	}
+	newEnc, err := NewHybridEncrypt(rotatedPubKH)
+	if err != nil {
+		t.Fatalf("NewHybridEncrypt failed: %s", err)
+	}
+	newCT, err := newEnc.Encrypt([]byte("new plaintext"), ci)
+	if err != nil {
+		t.Fatalf("newEnc.Encrypt failed: %s", err)
+	}
+
+	d, err := NewHybridDecryptWithKeyID(rotatedPrivKH)
+	if err != nil {
+		t.Fatalf("NewHybridDecryptWithKeyID failed: %s", err)
+	}
+	for _, tc := range []struct {
+		name      string
+		ct        []byte
+		wantPT    []byte
+		wantKeyID uint32
+	}{
+		{"old key", oldCT, []byte("old plaintext"), oldKeyID},
+		{"new key", newCT, []byte("new plaintext"), newKeyID},
+	} {
+		pt, keyID, err := d.DecryptWithKeyID(tc.ct, ci)
+		if err != nil {
+			t.Fatalf("%s: d.DecryptWithKeyID failed: %s", tc.name, err)
+		}
+		if !bytes.Equal(pt, tc.wantPT) {
+			t.Errorf("%s: d.DecryptWithKeyID plaintext = %q, want %q", tc.name, pt, tc.wantPT)
+		}
+		if keyID != tc.wantKeyID {
+			t.Errorf("%s: d.DecryptWithKeyID key ID = %d, want %d", tc.name, keyID, tc.wantKeyID)
+		}
+		pt, err = d.Decrypt(tc.ct, ci)
+		if err != nil {
+			t.Fatalf("%s: d.Decrypt failed: %s", tc.name, err)
+		}
+		if !bytes.Equal(pt, tc.wantPT) {
+			t.Errorf("%s: d.Decrypt plaintext = %q, want %q", tc.name, pt, tc.wantPT)
+		}
+	}
+
+	if _, _, err := d.DecryptWithKeyID(newCT, []byte("other context info")); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("d.DecryptWithKeyID with wrong context info = %v, want ErrDecryptionFailed", err)
+	}
+}
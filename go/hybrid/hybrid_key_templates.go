@@ -21,6 +21,8 @@ import (
 	"github.com/google/tink/go/aead"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	eciespb "github.com/google/tink/go/proto/ecies_aead_hkdf_go_proto"
+	hpkepb "github.com/google/tink/go/proto/hpke_go_proto"
+	mkxpb "github.com/google/tink/go/proto/ml_kem_x25519_hkdf_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
@@ -49,6 +51,130 @@ func ECIESHKDFAES128CTRHMACSHA256KeyTemplate() *tinkpb.KeyTemplate {
 	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.HashType_SHA256, commonpb.EcPointFormat_UNCOMPRESSED, aead.AES128CTRHMACSHA256KeyTemplate(), empty)
 }
 
+// ECIESP384HKDFSHA384AES256GCMKeyTemplate is a KeyTemplate that generates an ECDH P-384 and decapsulation key AES256-GCM key with the following parameters:
+//  - KEM: ECDH over NIST P-384
+//  - DEM: AES256-GCM
+//  - KDF: HKDF-HMAC-SHA384 with an empty salt
+// These parameters are compatible with the CNSA suite.
+func ECIESP384HKDFSHA384AES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	empty := []byte{}
+	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_NIST_P384, commonpb.HashType_SHA384, commonpb.EcPointFormat_UNCOMPRESSED, aead.AES256GCMKeyTemplate(), empty)
+}
+
+// ECIESP521HKDFSHA512AES256GCMKeyTemplate is a KeyTemplate that generates an ECDH P-521 and decapsulation key AES256-GCM key with the following parameters:
+//  - KEM: ECDH over NIST P-521
+//  - DEM: AES256-GCM
+//  - KDF: HKDF-HMAC-SHA512 with an empty salt
+func ECIESP521HKDFSHA512AES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	empty := []byte{}
+	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_NIST_P521, commonpb.HashType_SHA512, commonpb.EcPointFormat_UNCOMPRESSED, aead.AES256GCMKeyTemplate(), empty)
+}
+
+// ECIESX25519HKDFAES128GCMKeyTemplate is a KeyTemplate that generates an X25519 and decapsulation key AES128-GCM key with the following parameters:
+//  - KEM: X25519 over Curve25519
+//  - DEM: AES128-GCM
+//  - KDF: HKDF-HMAC-SHA256 with an empty salt
+func ECIESX25519HKDFAES128GCMKeyTemplate() *tinkpb.KeyTemplate {
+	empty := []byte{}
+	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_CURVE25519, commonpb.HashType_SHA256, commonpb.EcPointFormat_COMPRESSED, aead.AES128GCMKeyTemplate(), empty)
+}
+
+// ECIESX25519HKDFAES256GCMKeyTemplate is a KeyTemplate that generates an X25519 and decapsulation key AES256-GCM key with the following parameters:
+//  - KEM: X25519 over Curve25519
+//  - DEM: AES256-GCM
+//  - KDF: HKDF-HMAC-SHA256 with an empty salt
+func ECIESX25519HKDFAES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	empty := []byte{}
+	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_CURVE25519, commonpb.HashType_SHA256, commonpb.EcPointFormat_COMPRESSED, aead.AES256GCMKeyTemplate(), empty)
+}
+
+// ECIESX25519HKDFChaCha20Poly1305KeyTemplate is a KeyTemplate that generates an X25519 and decapsulation key ChaCha20-Poly1305 key with the following parameters:
+//  - KEM: X25519 over Curve25519
+//  - DEM: ChaCha20-Poly1305
+//  - KDF: HKDF-HMAC-SHA256 with an empty salt
+func ECIESX25519HKDFChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	empty := []byte{}
+	return createECIESAEADHKDFKeyTemplate(commonpb.EllipticCurveType_CURVE25519, commonpb.HashType_SHA256, commonpb.EcPointFormat_COMPRESSED, aead.ChaCha20Poly1305KeyTemplate(), empty)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: AES-128-GCM
+//  - OutputPrefixType: TINK
+func DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_AES_128_GCM, tinkpb.OutputPrefixType_TINK)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMRawKeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: AES-128-GCM
+//  - OutputPrefixType: RAW
+func DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMRawKeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_AES_128_GCM, tinkpb.OutputPrefixType_RAW)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: AES-256-GCM
+//  - OutputPrefixType: TINK
+func DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_AES_256_GCM, tinkpb.OutputPrefixType_TINK)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMRawKeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: AES-256-GCM
+//  - OutputPrefixType: RAW
+func DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMRawKeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_AES_256_GCM, tinkpb.OutputPrefixType_RAW)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: ChaCha20-Poly1305
+//  - OutputPrefixType: TINK
+func DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_CHACHA20_POLY1305, tinkpb.OutputPrefixType_TINK)
+}
+
+// DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate is a KeyTemplate that generates an HPKE key in base mode
+// with the following parameters:
+//  - KEM: DHKEM(X25519, HKDF-SHA256)
+//  - KDF: HKDF-SHA256
+//  - AEAD: ChaCha20-Poly1305
+//  - OutputPrefixType: RAW
+func DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate() *tinkpb.KeyTemplate {
+	return createHPKEKeyTemplate(hpkepb.HpkeAead_CHACHA20_POLY1305, tinkpb.OutputPrefixType_RAW)
+}
+
+// MLKEM768X25519HKDFAES256GCMKeyTemplate is a KeyTemplate that generates a post-quantum hybrid key
+// with the following parameters:
+//  - KEM: ML-KEM-768 and X25519, combined with SHA3-256
+//  - DEM: AES256-GCM
+//  - KDF: HKDF-HMAC-SHA256 with an empty salt
+func MLKEM768X25519HKDFAES256GCMKeyTemplate() *tinkpb.KeyTemplate {
+	return createMLKEMX25519HKDFKeyTemplate(aead.AES256GCMKeyTemplate())
+}
+
+// MLKEM768X25519HKDFChaCha20Poly1305KeyTemplate is a KeyTemplate that generates a post-quantum hybrid key
+// with the following parameters:
+//  - KEM: ML-KEM-768 and X25519, combined with SHA3-256
+//  - DEM: ChaCha20-Poly1305
+//  - KDF: HKDF-HMAC-SHA256 with an empty salt
+func MLKEM768X25519HKDFChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return createMLKEMX25519HKDFKeyTemplate(aead.ChaCha20Poly1305KeyTemplate())
+}
+
 // createEciesAEADHKDFKeyTemplate creates a new ECIES-AEAD-HKDF key template with the given key
 // size in bytes.
 func createECIESAEADHKDFKeyTemplate(c commonpb.EllipticCurveType, ht commonpb.HashType, ptfmt commonpb.EcPointFormat, dekT *tinkpb.KeyTemplate, salt []byte) *tinkpb.KeyTemplate {
@@ -72,3 +198,60 @@ func createECIESAEADHKDFKeyTemplate(c commonpb.EllipticCurveType, ht commonpb.Ha
 		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
 	}
 }
+
+// createHPKEKeyTemplate creates a new HPKE key template with DHKEM(X25519,
+// HKDF-SHA256), HKDF-SHA256 and the given AEAD.
+func createHPKEKeyTemplate(a hpkepb.HpkeAead, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &hpkepb.HpkeKeyFormat{
+		Params: &hpkepb.HpkeParams{
+			Kem:  hpkepb.HpkeKem_DHKEM_X25519_HKDF_SHA256,
+			Kdf:  hpkepb.HpkeKdf_HKDF_SHA256,
+			Aead: a,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          hpkePrivateKeyTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// createMLKEMX25519HKDFKeyTemplate creates a new ML-KEM-768+X25519 key template with
+// HKDF-HMAC-SHA256, an empty salt and the given DEM.
+func createMLKEMX25519HKDFKeyTemplate(dekT *tinkpb.KeyTemplate) *tinkpb.KeyTemplate {
+	format := &mkxpb.MlKemX25519HkdfKeyFormat{
+		Params: &mkxpb.MlKemX25519HkdfParams{
+			HkdfHashType: commonpb.HashType_SHA256,
+			HkdfSalt:     []byte{},
+			AeadDem:      dekT,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          mlKEMX25519HKDFPrivateKeyTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"ECIES_P256_HKDF_HMAC_SHA256_AES128_GCM":                     ECIESHKDFAES128GCMKeyTemplate,
+	"ECIES_P256_HKDF_HMAC_SHA256_AES128_CTR_HMAC_SHA256":         ECIESHKDFAES128CTRHMACSHA256KeyTemplate,
+	"ECIES_P384_HKDF_HMAC_SHA384_AES256_GCM":                     ECIESP384HKDFSHA384AES256GCMKeyTemplate,
+	"ECIES_P521_HKDF_HMAC_SHA512_AES256_GCM":                     ECIESP521HKDFSHA512AES256GCMKeyTemplate,
+	"ECIES_X25519_HKDF_HMAC_SHA256_AES128_GCM":                   ECIESX25519HKDFAES128GCMKeyTemplate,
+	"ECIES_X25519_HKDF_HMAC_SHA256_AES256_GCM":                   ECIESX25519HKDFAES256GCMKeyTemplate,
+	"ECIES_X25519_HKDF_HMAC_SHA256_CHACHA20_POLY1305":            ECIESX25519HKDFChaCha20Poly1305KeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_128_GCM":           DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_128_GCM_RAW":       DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMRawKeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_256_GCM":           DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_256_GCM_RAW":       DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMRawKeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_CHACHA20_POLY1305":     DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate,
+	"DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_CHACHA20_POLY1305_RAW": DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate,
+	"ML_KEM_768_X25519_HKDF_AES_256_GCM":                         MLKEM768X25519HKDFAES256GCMKeyTemplate,
+	"ML_KEM_768_X25519_HKDF_CHACHA20_POLY1305":                   MLKEM768X25519HKDFChaCha20Poly1305KeyTemplate,
+}
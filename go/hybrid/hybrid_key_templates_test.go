@@ -36,6 +36,28 @@ func TestKeyTemplates(t *testing.T) {
 			template: ECIESHKDFAES128GCMKeyTemplate()},
 		{name: "ECIES_P256_HKDF_HMAC_SHA256_AES128_CTR_HMAC_SHA256",
 			template: ECIESHKDFAES128CTRHMACSHA256KeyTemplate()},
+		{name: "ECIES_P384_HKDF_HMAC_SHA384_AES256_GCM",
+			template: ECIESP384HKDFSHA384AES256GCMKeyTemplate()},
+		{name: "ECIES_P521_HKDF_HMAC_SHA512_AES256_GCM",
+			template: ECIESP521HKDFSHA512AES256GCMKeyTemplate()},
+		{name: "ECIES_X25519_HKDF_HMAC_SHA256_AES128_GCM",
+			template: ECIESX25519HKDFAES128GCMKeyTemplate()},
+		{name: "ECIES_X25519_HKDF_HMAC_SHA256_AES256_GCM",
+			template: ECIESX25519HKDFAES256GCMKeyTemplate()},
+		{name: "ECIES_X25519_HKDF_HMAC_SHA256_CHACHA20_POLY1305",
+			template: ECIESX25519HKDFChaCha20Poly1305KeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_128_GCM",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_128_GCM_RAW",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMRawKeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_256_GCM",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMKeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_AES_256_GCM_RAW",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMRawKeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_CHACHA20_POLY1305",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate()},
+		{name: "DHKEM_X25519_HKDF_SHA256_HKDF_SHA256_CHACHA20_POLY1305_RAW",
+			template: DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305RawKeyTemplate()},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
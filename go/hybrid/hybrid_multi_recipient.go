@@ -0,0 +1,145 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	subtleaead "github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+// Multi-recipient ciphertexts have the following format:
+//
+//   version (1 byte) || recipient count (2 bytes, big endian) ||
+//   for each recipient: encapsulation length (4 bytes, big endian) || encapsulation ||
+//   payload
+//
+// where each encapsulation is the HybridEncrypt ciphertext of a single data
+// encryption key under contextInfo, and payload is the AES256-GCM ciphertext
+// of the plaintext under that key. The associated data of the payload is the
+// header (everything preceding it) followed by contextInfo, so the recipient
+// list cannot be altered without detection.
+//
+// Note that every recipient learns the data encryption key and can therefore
+// produce ciphertexts that the other recipients accept; the scheme provides
+// confidentiality towards non-recipients only.
+const (
+	multiRecipientVersion = 1
+	multiRecipientKeySize = 32
+	// MaxRecipients is the maximum number of recipients of a single ciphertext.
+	MaxRecipients = 1024
+)
+
+var errMultiRecipientCiphertext = errors.New("hybrid_multi_recipient: invalid ciphertext")
+
+// EncryptForRecipients encrypts plaintext once and makes it decryptable with
+// the private keyset of any of the given public keysets. contextInfo is bound
+// to the ciphertext as in HybridEncrypt.
+func EncryptForRecipients(recipients []*keyset.Handle, plaintext, contextInfo []byte) ([]byte, error) {
+	if len(recipients) == 0 || len(recipients) > MaxRecipients {
+		return nil, fmt.Errorf("hybrid_multi_recipient: number of recipients must be between 1 and %d, got %d", MaxRecipients, len(recipients))
+	}
+	encs := make([]tink.HybridEncrypt, len(recipients))
+	for i, h := range recipients {
+		enc, err := NewHybridEncrypt(h)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid_multi_recipient: recipient %d: %s", i, err)
+		}
+		encs[i] = enc
+	}
+	key := random.GetRandomBytes(multiRecipientKeySize)
+	header := []byte{multiRecipientVersion, 0, 0}
+	binary.BigEndian.PutUint16(header[1:], uint16(len(recipients)))
+	for i, enc := range encs {
+		encapsulation, err := enc.Encrypt(key, contextInfo)
+		if err != nil {
+			return nil, fmt.Errorf("hybrid_multi_recipient: recipient %d: %s", i, err)
+		}
+		if len(encapsulation) > maxEncapsulationSize {
+			return nil, fmt.Errorf("hybrid_multi_recipient: recipient %d: encapsulation too large: %d bytes", i, len(encapsulation))
+		}
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(encapsulation)))
+		header = append(header, size[:]...)
+		header = append(header, encapsulation...)
+	}
+	a, err := subtleaead.NewAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := a.Encrypt(plaintext, multiRecipientAssociatedData(header, contextInfo))
+	if err != nil {
+		return nil, err
+	}
+	return append(header, payload...), nil
+}
+
+// DecryptForRecipient decrypts a ciphertext produced by EncryptForRecipients
+// with the private keyset h, which must correspond to one of the recipients.
+func DecryptForRecipient(h *keyset.Handle, ciphertext, contextInfo []byte) ([]byte, error) {
+	dec, err := NewHybridDecrypt(h)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < 3 || ciphertext[0] != multiRecipientVersion {
+		return nil, errMultiRecipientCiphertext
+	}
+	count := int(binary.BigEndian.Uint16(ciphertext[1:3]))
+	if count == 0 || count > MaxRecipients {
+		return nil, errMultiRecipientCiphertext
+	}
+	var key []byte
+	offset := 3
+	for i := 0; i < count; i++ {
+		if len(ciphertext)-offset < 4 {
+			return nil, errMultiRecipientCiphertext
+		}
+		n := int(binary.BigEndian.Uint32(ciphertext[offset : offset+4]))
+		offset += 4
+		if n > maxEncapsulationSize || len(ciphertext)-offset < n {
+			return nil, errMultiRecipientCiphertext
+		}
+		encapsulation := ciphertext[offset : offset+n]
+		offset += n
+		if key != nil {
+			continue
+		}
+		if k, err := dec.Decrypt(encapsulation, contextInfo); err == nil && len(k) == multiRecipientKeySize {
+			key = k
+		}
+	}
+	if key == nil {
+		return nil, errors.New("hybrid_multi_recipient: no matching recipient")
+	}
+	a, err := subtleaead.NewAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	header := ciphertext[:offset]
+	return a.Decrypt(ciphertext[offset:], multiRecipientAssociatedData(header, contextInfo))
+}
+
+func multiRecipientAssociatedData(header, contextInfo []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(contextInfo))
+	ad = append(ad, header...)
+	return append(ad, contextInfo...)
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestMultiRecipientEncryptDecrypt(t *testing.T) {
+	templates := []*tinkpb.KeyTemplate{
+		ECIESHKDFAES128GCMKeyTemplate(),
+		ECIESX25519HKDFAES256GCMKeyTemplate(),
+		DHKEMX25519HKDFSHA256HKDFSHA256ChaCha20Poly1305KeyTemplate(),
+	}
+	var privateHandles, publicHandles []*keyset.Handle
+	for _, template := range templates {
+		privateHandle, publicHandle := hybridKeyHandles(t, template)
+		privateHandles = append(privateHandles, privateHandle)
+		publicHandles = append(publicHandles, publicHandle)
+	}
+	pt := random.GetRandomBytes(100)
+	contextInfo := []byte("context info")
+	ct, err := EncryptForRecipients(publicHandles, pt, contextInfo)
+	if err != nil {
+		t.Fatalf("EncryptForRecipients() err = %v", err)
+	}
+	for i, h := range privateHandles {
+		got, err := DecryptForRecipient(h, ct, contextInfo)
+		if err != nil {
+			t.Fatalf("DecryptForRecipient() for recipient %d err = %v", i, err)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("DecryptForRecipient() for recipient %d = %x, want %x", i, got, pt)
+		}
+		if _, err := DecryptForRecipient(h, ct, []byte("other context")); err == nil {
+			t.Errorf("DecryptForRecipient() for recipient %d with wrong context info err = nil, want error", i)
+		}
+	}
+}
+
+func TestMultiRecipientDecryptWithNonRecipient(t *testing.T) {
+	_, publicHandle := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	otherPrivateHandle, _ := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	ct, err := EncryptForRecipients([]*keyset.Handle{publicHandle}, []byte("some data"), nil)
+	if err != nil {
+		t.Fatalf("EncryptForRecipients() err = %v", err)
+	}
+	if _, err := DecryptForRecipient(otherPrivateHandle, ct, nil); err == nil {
+		t.Errorf("DecryptForRecipient() with a non-recipient key err = nil, want error")
+	}
+}
+
+func TestMultiRecipientModifiedCiphertext(t *testing.T) {
+	privateHandle1, publicHandle1 := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	_, publicHandle2 := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	ct, err := EncryptForRecipients([]*keyset.Handle{publicHandle1, publicHandle2}, []byte("some data"), nil)
+	if err != nil {
+		t.Fatalf("EncryptForRecipients() err = %v", err)
+	}
+	for i := 0; i < len(ct); i++ {
+		modified := append([]byte(nil), ct...)
+		modified[i] ^= 0x01
+		if _, err := DecryptForRecipient(privateHandle1, modified, nil); err == nil {
+			t.Errorf("decryption of ciphertext modified at byte %d succeeded", i)
+		}
+	}
+	for n := 0; n < len(ct); n += 7 {
+		if _, err := DecryptForRecipient(privateHandle1, ct[:n], nil); err == nil {
+			t.Errorf("decryption of ciphertext truncated to %d bytes succeeded", n)
+		}
+	}
+}
+
+func TestEncryptForRecipientsInvalidRecipients(t *testing.T) {
+	if _, err := EncryptForRecipients(nil, []byte("some data"), nil); err == nil {
+		t.Errorf("EncryptForRecipients() with no recipients err = nil, want error")
+	}
+	privateHandle, _ := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	if _, err := EncryptForRecipients([]*keyset.Handle{privateHandle}, []byte("some data"), nil); err == nil {
+		t.Errorf("EncryptForRecipients() with a private keyset err = nil, want error")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+	subtlestreamingaead "github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+// Streaming hybrid ciphertexts have the following format:
+//
+//   encapsulation length (4 bytes, big endian) || encapsulation || stream
+//
+// where encapsulation is the HybridEncrypt ciphertext of a fresh streaming
+// key under contextInfo, and stream is the AES256-GCM-HKDF streaming AEAD
+// ciphertext of the plaintext under that key, with contextInfo as associated
+// data. The public key operation thus happens once per stream, not once per
+// segment.
+const (
+	streamingKeySize     = 32
+	streamingSegmentSize = 1 << 20
+	// maxEncapsulationSize bounds the allocation made for the header when
+	// decrypting untrusted input.
+	maxEncapsulationSize = 1 << 16
+)
+
+var errStreamingHeader = errors.New("hybrid_streaming: invalid ciphertext header")
+
+func newStreamingAEAD(key []byte) (*subtlestreamingaead.AESGCMHKDF, error) {
+	return subtlestreamingaead.NewAESGCMHKDF(key, "SHA256", streamingKeySize, streamingSegmentSize, 0)
+}
+
+// NewEncryptingWriter returns a writer that encrypts data written to it to
+// the public keyset h and writes the ciphertext to w. contextInfo is bound to
+// the ciphertext as in HybridEncrypt. The encapsulated stream key is written
+// to w before NewEncryptingWriter returns; Close must be called to flush the
+// final segment.
+func NewEncryptingWriter(h *keyset.Handle, w io.Writer, contextInfo []byte) (io.WriteCloser, error) {
+	enc, err := NewHybridEncrypt(h)
+	if err != nil {
+		return nil, err
+	}
+	key := random.GetRandomBytes(streamingKeySize)
+	encapsulation, err := enc.Encrypt(key, contextInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(encapsulation) > maxEncapsulationSize {
+		return nil, fmt.Errorf("hybrid_streaming: encapsulation too large: %d bytes", len(encapsulation))
+	}
+	s, err := newStreamingAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 4, 4+len(encapsulation))
+	binary.BigEndian.PutUint32(header, uint32(len(encapsulation)))
+	if _, err := w.Write(append(header, encapsulation...)); err != nil {
+		return nil, err
+	}
+	return s.NewEncryptingWriter(w, contextInfo)
+}
+
+// NewDecryptingReader returns a reader that decrypts a ciphertext read from
+// r with the private keyset h. It reads and decapsulates the header before
+// returning; the payload is decrypted and authenticated segment by segment as
+// it is read.
+func NewDecryptingReader(h *keyset.Handle, r io.Reader, contextInfo []byte) (io.Reader, error) {
+	dec, err := NewHybridDecrypt(h)
+	if err != nil {
+		return nil, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, errStreamingHeader
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxEncapsulationSize {
+		return nil, errStreamingHeader
+	}
+	encapsulation := make([]byte, n)
+	if _, err := io.ReadFull(r, encapsulation); err != nil {
+		return nil, errStreamingHeader
+	}
+	key, err := dec.Decrypt(encapsulation, contextInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != streamingKeySize {
+		return nil, errStreamingHeader
+	}
+	s, err := newStreamingAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.NewDecryptingReader(r, contextInfo)
+}
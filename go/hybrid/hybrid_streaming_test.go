@@ -0,0 +1,127 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func hybridKeyHandles(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *keyset.Handle) {
+	t.Helper()
+	privateHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v", err)
+	}
+	return privateHandle, publicHandle
+}
+
+func streamingEncrypt(t *testing.T, h *keyset.Handle, pt, contextInfo []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewEncryptingWriter(h, &buf, contextInfo)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriter() err = %v", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("w.Write() err = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() err = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestStreamingEncryptDecrypt(t *testing.T) {
+	templates := map[string]*tinkpb.KeyTemplate{
+		"ECIES_P256":   ECIESHKDFAES128GCMKeyTemplate(),
+		"ECIES_X25519": ECIESX25519HKDFChaCha20Poly1305KeyTemplate(),
+		"HPKE":         DHKEMX25519HKDFSHA256HKDFSHA256AES256GCMRawKeyTemplate(),
+	}
+	sizes := []int{0, 1, streamingSegmentSize - 100, 3*streamingSegmentSize + 7}
+	for name, template := range templates {
+		privateHandle, publicHandle := hybridKeyHandles(t, template)
+		for _, size := range sizes {
+			pt := random.GetRandomBytes(uint32(size))
+			contextInfo := []byte("context info")
+			ct := streamingEncrypt(t, publicHandle, pt, contextInfo)
+			r, err := NewDecryptingReader(privateHandle, bytes.NewReader(ct), contextInfo)
+			if err != nil {
+				t.Fatalf("%s: NewDecryptingReader() err = %v", name, err)
+			}
+			got, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("%s: ioutil.ReadAll() err = %v", name, err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("%s: decrypted %d bytes do not match the %d-byte plaintext", name, len(got), size)
+			}
+		}
+	}
+}
+
+func TestStreamingDecryptWithWrongContextInfo(t *testing.T) {
+	privateHandle, publicHandle := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	ct := streamingEncrypt(t, publicHandle, []byte("some data"), []byte("context info"))
+	if _, err := NewDecryptingReader(privateHandle, bytes.NewReader(ct), []byte("other context")); err == nil {
+		t.Errorf("NewDecryptingReader() with wrong context info err = nil, want error")
+	}
+}
+
+func TestStreamingDecryptWithWrongKey(t *testing.T) {
+	_, publicHandle := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	otherPrivateHandle, _ := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	ct := streamingEncrypt(t, publicHandle, []byte("some data"), nil)
+	if _, err := NewDecryptingReader(otherPrivateHandle, bytes.NewReader(ct), nil); err == nil {
+		t.Errorf("NewDecryptingReader() with wrong key err = nil, want error")
+	}
+}
+
+func TestStreamingDecryptModifiedCiphertext(t *testing.T) {
+	privateHandle, publicHandle := hybridKeyHandles(t, ECIESHKDFAES128GCMKeyTemplate())
+	pt := random.GetRandomBytes(100)
+	ct := streamingEncrypt(t, publicHandle, pt, nil)
+	for _, i := range []int{0, 3, 10, len(ct) - 1} {
+		modified := append([]byte(nil), ct...)
+		modified[i] ^= 0x01
+		r, err := NewDecryptingReader(privateHandle, bytes.NewReader(modified), nil)
+		if err != nil {
+			continue
+		}
+		if _, err := ioutil.ReadAll(r); err == nil {
+			t.Errorf("decryption of ciphertext modified at byte %d succeeded", i)
+		}
+	}
+	for _, n := range []int{0, 3, 10, len(ct) - 1} {
+		r, err := NewDecryptingReader(privateHandle, bytes.NewReader(ct[:n]), nil)
+		if err != nil {
+			continue
+		}
+		if _, err := ioutil.ReadAll(r); err == nil {
+			t.Errorf("decryption of ciphertext truncated to %d bytes succeeded", n)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build go1.24
+// +build go1.24
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+)
+
+// The ML-KEM-768+X25519 key managers are only registered with Go 1.24 or
+// later, since they need crypto/mlkem.
+func init() {
+	if err := registry.RegisterKeyManager(newMLKEMX25519HKDFPrivateKeyKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newMLKEMX25519HKDFPublicKeyKeyManager()); err != nil {
+		panic(fmt.Sprintf("hybrid.init() failed: %v", err))
+	}
+}
@@ -0,0 +1,304 @@
+//go:build go1.24
+// +build go1.24
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/daead"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	mkxpb "github.com/google/tink/go/proto/ml_kem_x25519_hkdf_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestMLKEMX25519EncryptDecrypt(t *testing.T) {
+	templates := map[string]*tinkpb.KeyTemplate{
+		"AES256GCM":        aead.AES256GCMKeyTemplate(),
+		"ChaCha20Poly1305": aead.ChaCha20Poly1305KeyTemplate(),
+		"AESSIV":           daead.AESSIVKeyTemplate(),
+	}
+	for name, k := range templates {
+		t.Run(name, func(t *testing.T) {
+			pvt, pub, err := subtle.GenerateMLKEMX25519KeyPair()
+			if err != nil {
+				t.Fatalf("subtle.GenerateMLKEMX25519KeyPair() err = %v", err)
+			}
+			salt := random.GetRandomBytes(8)
+			pt := random.GetRandomBytes(20)
+			context := []byte("context info")
+			rDem, err := newRegisterECIESAEADHKDFDemHelper(k)
+			if err != nil {
+				t.Fatalf("error generating a DEM helper :%s", err)
+			}
+			e, err := subtle.NewMLKEMX25519HKDFHybridEncrypt(pub, salt, "SHA256", rDem)
+			if err != nil {
+				t.Fatalf("error generating an encryption construct :%s", err)
+			}
+			d, err := subtle.NewMLKEMX25519HKDFHybridDecrypt(pvt, salt, "SHA256", rDem)
+			if err != nil {
+				t.Fatalf("error generating an decryption construct :%s", err)
+			}
+			ct, err := e.Encrypt(pt, context)
+			if err != nil {
+				t.Fatalf("encryption error :%s", err)
+			}
+			ct2, err := e.Encrypt(pt, context)
+			if err != nil {
+				t.Fatalf("encryption error :%s", err)
+			}
+			if bytes.Equal(ct, ct2) {
+				t.Errorf("encryption is not randomized")
+			}
+			dt, err := d.Decrypt(ct, context)
+			if err != nil {
+				t.Fatalf("decryption error :%s", err)
+			}
+			if !bytes.Equal(dt, pt) {
+				t.Errorf("decryption not inverse of encryption")
+			}
+			if _, err := d.Decrypt(ct, []byte("other context")); err == nil {
+				t.Errorf("decryption with wrong context info succeeded")
+			}
+			// Flip a bit in the ML-KEM ciphertext, the X25519 public key and the DEM ciphertext.
+			for _, i := range []int{0, subtle.MLKEMX25519EncapsulationSize - 1, len(ct) - 1} {
+				modified := append([]byte(nil), ct...)
+				modified[i] ^= 0x01
+				if _, err := d.Decrypt(modified, context); err == nil {
+					t.Errorf("decryption of ciphertext modified at byte %d succeeded", i)
+				}
+			}
+			if _, err := d.Decrypt(ct[:subtle.MLKEMX25519EncapsulationSize-1], context); err == nil {
+				t.Errorf("decryption of truncated ciphertext succeeded")
+			}
+		})
+	}
+}
+
+func TestMLKEMX25519WrongPrivateKey(t *testing.T) {
+	_, pub, err := subtle.GenerateMLKEMX25519KeyPair()
+	if err != nil {
+		t.Fatalf("subtle.GenerateMLKEMX25519KeyPair() err = %v", err)
+	}
+	otherPvt, _, err := subtle.GenerateMLKEMX25519KeyPair()
+	if err != nil {
+		t.Fatalf("subtle.GenerateMLKEMX25519KeyPair() err = %v", err)
+	}
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("error generating a DEM helper :%s", err)
+	}
+	e, err := subtle.NewMLKEMX25519HKDFHybridEncrypt(pub, nil, "SHA256", rDem)
+	if err != nil {
+		t.Fatalf("error generating an encryption construct :%s", err)
+	}
+	d, err := subtle.NewMLKEMX25519HKDFHybridDecrypt(otherPvt, nil, "SHA256", rDem)
+	if err != nil {
+		t.Fatalf("error generating an decryption construct :%s", err)
+	}
+	ct, err := e.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("encryption error :%s", err)
+	}
+	if _, err := d.Decrypt(ct, nil); err == nil {
+		t.Errorf("decryption with the wrong private key succeeded")
+	}
+}
+
+func TestMLKEMX25519InvalidKeySizes(t *testing.T) {
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("error generating a DEM helper :%s", err)
+	}
+	if _, err := subtle.NewMLKEMX25519HKDFHybridEncrypt(make([]byte, subtle.MLKEMX25519PublicKeySize-1), nil, "SHA256", rDem); err == nil {
+		t.Errorf("NewMLKEMX25519HKDFHybridEncrypt() with a short public key succeeded")
+	}
+	if _, err := subtle.NewMLKEMX25519HKDFHybridDecrypt(make([]byte, subtle.MLKEMX25519PrivateKeySize+1), nil, "SHA256", rDem); err == nil {
+		t.Errorf("NewMLKEMX25519HKDFHybridDecrypt() with a long private key succeeded")
+	}
+}
+
+func TestMLKEMX25519PrivateKeyManagerNewKey(t *testing.T) {
+	km, err := registry.GetKeyManager(mlKEMX25519HKDFPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	m, err := km.NewKey(MLKEM768X25519HKDFAES256GCMKeyTemplate().GetValue())
+	if err != nil {
+		t.Fatalf("km.NewKey() err = %v", err)
+	}
+	key, ok := m.(*mkxpb.MlKemX25519HkdfPrivateKey)
+	if !ok {
+		t.Fatalf("km.NewKey() returned %T, want *MlKemX25519HkdfPrivateKey", m)
+	}
+	if len(key.GetPrivateKey()) != subtle.MLKEMX25519PrivateKeySize {
+		t.Errorf("len(PrivateKey) = %d, want %d", len(key.GetPrivateKey()), subtle.MLKEMX25519PrivateKeySize)
+	}
+	pub, err := subtle.MLKEMX25519PublicKey(key.GetPrivateKey())
+	if err != nil {
+		t.Fatalf("subtle.MLKEMX25519PublicKey() err = %v", err)
+	}
+	if !bytes.Equal(key.GetPublicKey().GetPublicKey(), pub) {
+		t.Errorf("public key does not match the private key")
+	}
+}
+
+func TestMLKEMX25519PrivateKeyManagerNewKeyWithInvalidFormat(t *testing.T) {
+	km, err := registry.GetKeyManager(mlKEMX25519HKDFPrivateKeyTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	formats := []*mkxpb.MlKemX25519HkdfKeyFormat{
+		{},
+		{Params: &mkxpb.MlKemX25519HkdfParams{AeadDem: aead.AES256GCMKeyTemplate()}},
+		{Params: &mkxpb.MlKemX25519HkdfParams{HkdfHashType: commonpb.HashType_SHA256}},
+	}
+	for _, f := range formats {
+		serialized, err := proto.Marshal(f)
+		if err != nil {
+			t.Fatalf("proto.Marshal() err = %v", err)
+		}
+		if _, err := km.NewKey(serialized); err == nil {
+			t.Errorf("km.NewKey(%v) err = nil, want error", f)
+		}
+	}
+}
+
+func TestMLKEMX25519Factory(t *testing.T) {
+	privateHandle, err := keyset.NewHandle(MLKEM768X25519HKDFChaCha20Poly1305KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	publicHandle, err := privateHandle.Public()
+	if err != nil {
+		t.Fatalf("privateHandle.Public() err = %v", err)
+	}
+	if got := publicHandle.KeysetInfo().GetKeyInfo()[0].GetTypeUrl(); got != mlKEMX25519HKDFPublicKeyTypeURL {
+		t.Errorf("public key type URL = %q, want %q", got, mlKEMX25519HKDFPublicKeyTypeURL)
+	}
+	enc, err := NewHybridEncrypt(publicHandle)
+	if err != nil {
+		t.Fatalf("NewHybridEncrypt() err = %v", err)
+	}
+	dec, err := NewHybridDecrypt(privateHandle)
+	if err != nil {
+		t.Fatalf("NewHybridDecrypt() err = %v", err)
+	}
+	pt := random.GetRandomBytes(32)
+	ci := []byte("context info")
+	ct, err := enc.Encrypt(pt, ci)
+	if err != nil {
+		t.Fatalf("enc.Encrypt() err = %v", err)
+	}
+	got, err := dec.Decrypt(ct, ci)
+	if err != nil {
+		t.Fatalf("dec.Decrypt() err = %v", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Errorf("dec.Decrypt() = %x, want %x", got, pt)
+	}
+}
+
+func TestMLKEMX25519KeyTemplates(t *testing.T) {
+	testutil.SkipTestIfTestSrcDirIsNotSet(t)
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{name: "ML_KEM_768_X25519_HKDF_HMAC_SHA256_AES256_GCM",
+			template: MLKEM768X25519HKDFAES256GCMKeyTemplate()},
+		{name: "ML_KEM_768_X25519_HKDF_HMAC_SHA256_CHACHA20_POLY1305",
+			template: MLKEM768X25519HKDFChaCha20Poly1305KeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := testutil.KeyTemplateProto("hybrid", tc.name)
+			if err != nil {
+				t.Fatalf("testutil.KeyTemplateProto('hybrid', tc.name) failed: %s", err)
+			}
+			if !proto.Equal(want, tc.template) {
+				t.Errorf("template %s is not equal to '%s'", tc.name, tc.template)
+			}
+			privateHandle, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			got, err := privateHandle.TemplateFor(privateHandle.KeysetInfo().GetPrimaryKeyId())
+			if err != nil {
+				t.Fatalf("privateHandle.TemplateFor() err = %v", err)
+			}
+			if !proto.Equal(got, tc.template) {
+				t.Errorf("privateHandle.TemplateFor() = %v, want %v", got, tc.template)
+			}
+		})
+	}
+}
+
+func TestMLKEMX25519StreamingEncryptDecrypt(t *testing.T) {
+	privateHandle, publicHandle := hybridKeyHandles(t, MLKEM768X25519HKDFAES256GCMKeyTemplate())
+	pt := random.GetRandomBytes(3*streamingSegmentSize + 7)
+	contextInfo := []byte("context info")
+	ct := streamingEncrypt(t, publicHandle, pt, contextInfo)
+	r, err := NewDecryptingReader(privateHandle, bytes.NewReader(ct), contextInfo)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader() err = %v", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll() err = %v", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Error("decrypted data does not match the plaintext")
+	}
+}
+
+func TestMLKEMX25519MultiRecipientEncryptDecrypt(t *testing.T) {
+	var privateHandles, publicHandles []*keyset.Handle
+	for _, template := range []*tinkpb.KeyTemplate{
+		ECIESX25519HKDFAES256GCMKeyTemplate(),
+		MLKEM768X25519HKDFAES256GCMKeyTemplate(),
+	} {
+		privateHandle, publicHandle := hybridKeyHandles(t, template)
+		privateHandles = append(privateHandles, privateHandle)
+		publicHandles = append(publicHandles, publicHandle)
+	}
+	pt := random.GetRandomBytes(100)
+	contextInfo := []byte("context info")
+	ct, err := EncryptForRecipients(publicHandles, pt, contextInfo)
+	if err != nil {
+		t.Fatalf("EncryptForRecipients() err = %v", err)
+	}
+	for i, h := range privateHandles {
+		got, err := DecryptForRecipient(h, ct, contextInfo)
+		if err != nil {
+			t.Fatalf("DecryptForRecipient() for recipient %d err = %v", i, err)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("DecryptForRecipient() for recipient %d = %x, want %x", i, got, pt)
+		}
+	}
+}
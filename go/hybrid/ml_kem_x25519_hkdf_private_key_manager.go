@@ -0,0 +1,179 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	mkxpb "github.com/google/tink/go/proto/ml_kem_x25519_hkdf_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	mlKEMX25519HKDFPrivateKeyKeyVersion = 0
+	mlKEMX25519HKDFPrivateKeyTypeURL    = "type.googleapis.com/google.crypto.tink.MlKemX25519HkdfPrivateKey"
+)
+
+// common errors
+var errInvalidMLKEMX25519HKDFPrivateKeyKey = fmt.Errorf("ml_kem_x25519_hkdf_private_key_manager: invalid key")
+var errInvalidMLKEMX25519HKDFPrivateKeyKeyFormat = fmt.Errorf("ml_kem_x25519_hkdf_private_key_manager: invalid key format")
+
+// mlKEMX25519HKDFPrivateKeyKeyManager is an implementation of PrivateKeyManager interface.
+// It generates new MlKemX25519HkdfPrivateKey keys and produces new instances of
+// MLKEMX25519HKDFHybridDecrypt subtle.
+type mlKEMX25519HKDFPrivateKeyKeyManager struct{}
+
+// Assert that mlKEMX25519HKDFPrivateKeyKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*mlKEMX25519HKDFPrivateKeyKeyManager)(nil)
+
+// newMLKEMX25519HKDFPrivateKeyKeyManager creates a new mlKEMX25519HKDFPrivateKeyKeyManager.
+func newMLKEMX25519HKDFPrivateKeyKeyManager() *mlKEMX25519HKDFPrivateKeyKeyManager {
+	return new(mlKEMX25519HKDFPrivateKeyKeyManager)
+}
+
+// Primitive creates an MLKEMX25519HKDFHybridDecrypt subtle for the given serialized
+// MlKemX25519HkdfPrivateKey proto.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKey
+	}
+	key := new(mkxpb.MlKemX25519HkdfPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKey
+	}
+	params := key.PublicKey.Params
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(params.AeadDem)
+	if err != nil {
+		return nil, err
+	}
+	return subtle.NewMLKEMX25519HKDFHybridDecrypt(key.PrivateKey, params.HkdfSalt, params.HkdfHashType.String(), rDem)
+}
+
+// NewKey creates a new key according to specification the given serialized MlKemX25519HkdfKeyFormat.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKeyFormat
+	}
+	keyFormat := new(mkxpb.MlKemX25519HkdfKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKeyFormat
+	}
+	if err := checkMLKEMX25519HKDFParams(keyFormat.Params); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKeyFormat
+	}
+	pvt, pub, err := subtle.GenerateMLKEMX25519KeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	return &mkxpb.MlKemX25519HkdfPrivateKey{
+		Version:    mlKEMX25519HKDFPrivateKeyKeyVersion,
+		PrivateKey: pvt,
+		PublicKey: &mkxpb.MlKemX25519HkdfPublicKey{
+			Version:   mlKEMX25519HKDFPrivateKeyKeyVersion,
+			Params:    keyFormat.Params,
+			PublicKey: pub,
+		},
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized
+// MlKemX25519HkdfKeyFormat.
+// It should be used solely by the key management API.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         mlKEMX25519HKDFPrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(mkxpb.MlKemX25519HkdfPrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPrivateKeyKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         mlKEMX25519HKDFPublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == mlKEMX25519HKDFPrivateKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) TypeURL() string {
+	return mlKEMX25519HKDFPrivateKeyTypeURL
+}
+
+// validateKey validates the given MlKemX25519HkdfPrivateKey.
+func (km *mlKEMX25519HKDFPrivateKeyKeyManager) validateKey(key *mkxpb.MlKemX25519HkdfPrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, mlKEMX25519HKDFPrivateKeyKeyVersion); err != nil {
+		return fmt.Errorf("ml_kem_x25519_hkdf_private_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil {
+		return errors.New("ml_kem_x25519_hkdf_private_key_manager: missing public key")
+	}
+	if len(key.PrivateKey) != subtle.MLKEMX25519PrivateKeySize {
+		return errors.New("ml_kem_x25519_hkdf_private_key_manager: invalid private key size")
+	}
+	return checkMLKEMX25519HKDFParams(key.PublicKey.Params)
+}
+
+func checkMLKEMX25519HKDFParams(params *mkxpb.MlKemX25519HkdfParams) error {
+	if params == nil {
+		return errors.New("missing ML-KEM X25519 params")
+	}
+	if params.HkdfHashType == commonpb.HashType_UNKNOWN_HASH {
+		return errors.New("hash unsupported for HMAC")
+	}
+	if params.AeadDem == nil {
+		return errors.New("missing AEAD DEM")
+	}
+	km, err := registry.GetKeyManager(params.AeadDem.TypeUrl)
+	if err != nil {
+		return err
+	}
+	_, err = km.NewKeyData(params.AeadDem.Value)
+	return err
+}
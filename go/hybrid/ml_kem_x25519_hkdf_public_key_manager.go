@@ -0,0 +1,100 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+	mkxpb "github.com/google/tink/go/proto/ml_kem_x25519_hkdf_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	mlKEMX25519HKDFPublicKeyKeyVersion = 0
+	mlKEMX25519HKDFPublicKeyTypeURL    = "type.googleapis.com/google.crypto.tink.MlKemX25519HkdfPublicKey"
+)
+
+// common errors
+var errInvalidMLKEMX25519HKDFPublicKeyKey = fmt.Errorf("ml_kem_x25519_hkdf_public_key_manager: invalid key")
+
+// mlKEMX25519HKDFPublicKeyKeyManager is an implementation of KeyManager interface.
+// It produces new instances of MLKEMX25519HKDFHybridEncrypt subtle.
+type mlKEMX25519HKDFPublicKeyKeyManager struct{}
+
+// Assert that mlKEMX25519HKDFPublicKeyKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*mlKEMX25519HKDFPublicKeyKeyManager)(nil)
+
+// newMLKEMX25519HKDFPublicKeyKeyManager creates a new mlKEMX25519HKDFPublicKeyKeyManager.
+func newMLKEMX25519HKDFPublicKeyKeyManager() *mlKEMX25519HKDFPublicKeyKeyManager {
+	return new(mlKEMX25519HKDFPublicKeyKeyManager)
+}
+
+// Primitive creates an MLKEMX25519HKDFHybridEncrypt subtle for the given serialized
+// MlKemX25519HkdfPublicKey proto.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidMLKEMX25519HKDFPublicKeyKey
+	}
+	key := new(mkxpb.MlKemX25519HkdfPublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPublicKeyKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, errInvalidMLKEMX25519HKDFPublicKeyKey
+	}
+	rDem, err := newRegisterECIESAEADHKDFDemHelper(key.Params.AeadDem)
+	if err != nil {
+		return nil, err
+	}
+	return subtle.NewMLKEMX25519HKDFHybridEncrypt(key.PublicKey, key.Params.HkdfSalt, key.Params.HkdfHashType.String(), rDem)
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == mlKEMX25519HKDFPublicKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) TypeURL() string {
+	return mlKEMX25519HKDFPublicKeyTypeURL
+}
+
+// validateKey validates the given MlKemX25519HkdfPublicKey.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) validateKey(key *mkxpb.MlKemX25519HkdfPublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, mlKEMX25519HKDFPublicKeyKeyVersion); err != nil {
+		return fmt.Errorf("ml_kem_x25519_hkdf_public_key_manager: invalid key: %s", err)
+	}
+	if len(key.PublicKey) != subtle.MLKEMX25519PublicKeySize {
+		return errors.New("ml_kem_x25519_hkdf_public_key_manager: invalid public key size")
+	}
+	return checkMLKEMX25519HKDFParams(key.Params)
+}
+
+// NewKey is not implemented for public key manager.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errors.New("public key manager does not implement NewKey")
+}
+
+// NewKeyData is not implemented for public key manager.
+func (km *mlKEMX25519HKDFPublicKeyKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errors.New("public key manager does not implement NewKeyData")
+}
@@ -0,0 +1,42 @@
+//go:build !go1.24
+// +build !go1.24
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hybrid
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/hybrid/subtle"
+	"github.com/google/tink/go/keyset"
+)
+
+func TestMLKEMX25519Unsupported(t *testing.T) {
+	for _, typeURL := range []string{mlKEMX25519HKDFPrivateKeyTypeURL, mlKEMX25519HKDFPublicKeyTypeURL} {
+		if _, err := registry.GetKeyManager(typeURL); err == nil {
+			t.Errorf("registry.GetKeyManager(%q) err = nil, want error", typeURL)
+		}
+	}
+	if _, err := keyset.NewHandle(MLKEM768X25519HKDFAES256GCMKeyTemplate()); err == nil {
+		t.Error("keyset.NewHandle() with an ML-KEM template err = nil, want error")
+	}
+	if _, _, err := subtle.GenerateMLKEMX25519KeyPair(); err == nil {
+		t.Error("subtle.GenerateMLKEMX25519KeyPair() err = nil, want error")
+	}
+}
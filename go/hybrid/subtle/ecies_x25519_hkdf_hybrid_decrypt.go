@@ -0,0 +1,79 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+
+	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/tink"
+)
+
+// ECIESX25519HKDFHybridDecrypt is an instance of ECIES decryption with an X25519 HKDF-KEM (key
+// encapsulation mechanism) and AEAD-DEM (data encapsulation mechanism).
+type ECIESX25519HKDFHybridDecrypt struct {
+	privateKey   []byte
+	hkdfSalt     []byte
+	hkdfHMACAlgo string
+	demHelper    EciesAEADHKDFDEMHelper
+}
+
+// Assert that ECIESX25519HKDFHybridDecrypt implements the HybridDecrypt interface.
+var _ tink.HybridDecrypt = (*ECIESX25519HKDFHybridDecrypt)(nil)
+
+// NewECIESX25519HKDFHybridDecrypt returns ECIES decryption construct with an X25519 HKDF-KEM (key
+// encapsulation mechanism) and AEAD-DEM (data encapsulation mechanism).
+func NewECIESX25519HKDFHybridDecrypt(pvt []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*ECIESX25519HKDFHybridDecrypt, error) {
+	if len(pvt) != X25519KeySize {
+		return nil, errors.New("ecies_x25519: invalid private key")
+	}
+	return &ECIESX25519HKDFHybridDecrypt{
+		privateKey:   append([]byte(nil), pvt...),
+		hkdfSalt:     hkdfSalt,
+		hkdfHMACAlgo: hkdfHMACAlgo,
+		demHelper:    demHelper,
+	}, nil
+}
+
+// Decrypt is used to decrypt using ECIES with an X25519 HKDF-KEM and AEAD-DEM mechanisms.
+func (e *ECIESX25519HKDFHybridDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	if len(ciphertext) < X25519KeySize {
+		return nil, errors.New("ciphertext too short")
+	}
+	ephemeralPublicKey := ciphertext[:X25519KeySize]
+	ct := ciphertext[X25519KeySize:]
+	secret, err := computeX25519SharedSecret(e.privateKey, ephemeralPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	symmetricKey, err := subtle.ComputeHKDF(e.hkdfHMACAlgo, append(append([]byte(nil), ephemeralPublicKey...), secret...), e.hkdfSalt, contextInfo, e.demHelper.GetSymmetricKeySize())
+	if err != nil {
+		return nil, err
+	}
+	prim, err := e.demHelper.GetAEADOrDAEAD(symmetricKey)
+	if err != nil {
+		return nil, err
+	}
+	switch a := prim.(type) {
+	case tink.AEAD:
+		return a.Decrypt(ct, []byte{})
+	case tink.DeterministicAEAD:
+		return a.DecryptDeterministically(ct, []byte{})
+	default:
+		return nil, errors.New("Internal error: unexpected primitive type")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/tink"
+)
+
+// ECIESX25519HKDFHybridEncrypt is an instance of ECIES encryption with an X25519 HKDF-KEM (key
+// encapsulation mechanism) and AEAD-DEM (data encapsulation mechanism).
+type ECIESX25519HKDFHybridEncrypt struct {
+	publicKey    []byte
+	hkdfSalt     []byte
+	hkdfHMACAlgo string
+	demHelper    EciesAEADHKDFDEMHelper
+}
+
+// Assert that ECIESX25519HKDFHybridEncrypt implements the HybridEncrypt interface.
+var _ tink.HybridEncrypt = (*ECIESX25519HKDFHybridEncrypt)(nil)
+
+// NewECIESX25519HKDFHybridEncrypt returns ECIES encryption construct with an X25519 HKDF-KEM (key
+// encapsulation mechanism) and AEAD-DEM (data encapsulation mechanism).
+func NewECIESX25519HKDFHybridEncrypt(pub []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*ECIESX25519HKDFHybridEncrypt, error) {
+	if len(pub) != X25519KeySize {
+		return nil, errors.New("ecies_x25519: invalid public key")
+	}
+	return &ECIESX25519HKDFHybridEncrypt{
+		publicKey:    append([]byte(nil), pub...),
+		hkdfSalt:     hkdfSalt,
+		hkdfHMACAlgo: hkdfHMACAlgo,
+		demHelper:    demHelper,
+	}, nil
+}
+
+// Encrypt is used to encrypt using ECIES with an X25519 HKDF-KEM and AEAD-DEM mechanisms.
+func (e *ECIESX25519HKDFHybridEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	var b bytes.Buffer
+	ephemeralPrivateKey, ephemeralPublicKey, err := GenerateX25519KeyPair()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := computeX25519SharedSecret(ephemeralPrivateKey, e.publicKey)
+	if err != nil {
+		return nil, err
+	}
+	symmetricKey, err := subtle.ComputeHKDF(e.hkdfHMACAlgo, append(append([]byte(nil), ephemeralPublicKey...), secret...), e.hkdfSalt, contextInfo, e.demHelper.GetSymmetricKeySize())
+	if err != nil {
+		return nil, err
+	}
+	prim, err := e.demHelper.GetAEADOrDAEAD(symmetricKey)
+	if err != nil {
+		return nil, err
+	}
+	var ct []byte
+	switch a := prim.(type) {
+	case tink.AEAD:
+		ct, err = a.Encrypt(plaintext, []byte{})
+	case tink.DeterministicAEAD:
+		ct, err = a.EncryptDeterministically(plaintext, []byte{})
+	default:
+		err = errors.New("Internal error: unexpected primitive type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.Write(ephemeralPublicKey)
+	b.Write(ct)
+	return b.Bytes(), nil
+}
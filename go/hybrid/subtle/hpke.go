@@ -0,0 +1,444 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+// HPKEKEM identifies an HPKE key encapsulation mechanism, as in
+// https://www.rfc-editor.org/rfc/rfc9180.html#section-7.1.
+type HPKEKEM uint16
+
+// HPKEKDF identifies an HPKE key derivation function, as in
+// https://www.rfc-editor.org/rfc/rfc9180.html#section-7.2.
+type HPKEKDF uint16
+
+// HPKEAEAD identifies an HPKE AEAD, as in
+// https://www.rfc-editor.org/rfc/rfc9180.html#section-7.3.
+type HPKEAEAD uint16
+
+// Supported HPKE algorithms.
+const (
+	HPKEDHKEMX25519HKDFSHA256 HPKEKEM = 0x0020
+
+	HPKEHKDFSHA256 HPKEKDF = 0x0001
+	HPKEHKDFSHA384 HPKEKDF = 0x0002
+	HPKEHKDFSHA512 HPKEKDF = 0x0003
+
+	HPKEAES128GCM        HPKEAEAD = 0x0001
+	HPKEAES256GCM        HPKEAEAD = 0x0002
+	HPKEChaCha20Poly1305 HPKEAEAD = 0x0003
+)
+
+const (
+	hpkeModeBase    = 0
+	hpkeModeAuth    = 2
+	hpkeModeAuthPSK = 3
+	hpkeVersion     = "HPKE-v1"
+
+	// HPKEMinPSKSize is the minimum size of a pre-shared key in bytes, as
+	// recommended in https://www.rfc-editor.org/rfc/rfc9180.html#section-5.1.2.
+	HPKEMinPSKSize = 32
+)
+
+// ErrHPKEAlgorithmMismatch is returned when sender and recipient keys of the
+// authenticated modes use different algorithms.
+var ErrHPKEAlgorithmMismatch = errors.New("hpke: sender and recipient keys use different algorithms")
+
+// HPKEHybridEncrypt is an instance of HPKE encryption in base, Auth or
+// AuthPSK mode, as specified in RFC 9180. The ciphertext is the encapsulated
+// key followed by the AEAD ciphertext of the plaintext; contextInfo is the HPKE
+// info and the AEAD associated data is empty.
+type HPKEHybridEncrypt struct {
+	recipientPublicKey []byte
+	suite              *hpkeSuite
+	mode               byte
+	// The following are only set in Auth and AuthPSK modes.
+	senderPrivateKey []byte
+	senderPublicKey  []byte
+	psk              []byte
+	pskID            []byte
+}
+
+// Assert that HPKEHybridEncrypt implements the HybridEncrypt interface.
+var _ tink.HybridEncrypt = (*HPKEHybridEncrypt)(nil)
+
+// NewHPKEHybridEncrypt returns an HPKE encryption construct for the given
+// recipient public key and algorithms.
+func NewHPKEHybridEncrypt(recipientPublicKey []byte, kem HPKEKEM, kdf HPKEKDF, aead HPKEAEAD) (*HPKEHybridEncrypt, error) {
+	suite, err := newHPKESuite(kem, kdf, aead)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipientPublicKey) != X25519KeySize {
+		return nil, errors.New("hpke: invalid recipient public key")
+	}
+	return &HPKEHybridEncrypt{
+		recipientPublicKey: append([]byte(nil), recipientPublicKey...),
+		suite:              suite,
+		mode:               hpkeModeBase,
+	}, nil
+}
+
+// NewHPKEAuthHybridEncrypt returns an HPKE encryption construct in Auth mode
+// for the given recipient public key, authenticated with the given sender
+// private key. If psk is not empty, AuthPSK mode is used instead, and the
+// recipient must also know psk and pskID.
+func NewHPKEAuthHybridEncrypt(recipientPublicKey, senderPrivateKey, psk, pskID []byte, kem HPKEKEM, kdf HPKEKDF, aead HPKEAEAD) (*HPKEHybridEncrypt, error) {
+	e, err := NewHPKEHybridEncrypt(recipientPublicKey, kem, kdf, aead)
+	if err != nil {
+		return nil, err
+	}
+	e.mode, err = hpkeAuthMode(psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	e.senderPublicKey, err = X25519PublicKey(senderPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	e.senderPrivateKey = append([]byte(nil), senderPrivateKey...)
+	e.psk = append([]byte(nil), psk...)
+	e.pskID = append([]byte(nil), pskID...)
+	return e, nil
+}
+
+// NewHPKEAuthHybridEncryptFromBase returns an HPKE encryption construct in
+// Auth mode, or AuthPSK mode if psk is not empty, from the base mode
+// construct of the recipient public key and the base mode decryption
+// construct of the sender private key, which must use the same algorithms.
+func NewHPKEAuthHybridEncryptFromBase(recipient *HPKEHybridEncrypt, sender *HPKEHybridDecrypt, psk, pskID []byte) (*HPKEHybridEncrypt, error) {
+	if recipient.mode != hpkeModeBase || sender.mode != hpkeModeBase {
+		return nil, errors.New("hpke: recipient and sender must be base mode constructs")
+	}
+	if !bytes.Equal(recipient.suite.suiteID, sender.suite.suiteID) {
+		return nil, ErrHPKEAlgorithmMismatch
+	}
+	mode, err := hpkeAuthMode(psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &HPKEHybridEncrypt{
+		recipientPublicKey: recipient.recipientPublicKey,
+		suite:              recipient.suite,
+		mode:               mode,
+		senderPrivateKey:   sender.recipientPrivateKey,
+		senderPublicKey:    sender.recipientPublicKey,
+		psk:                append([]byte(nil), psk...),
+		pskID:              append([]byte(nil), pskID...),
+	}, nil
+}
+
+// Encrypt encrypts plaintext with contextInfo as HPKE info.
+func (e *HPKEHybridEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	return e.encrypt(plaintext, contextInfo, nil, random.GetRandomBytes(X25519KeySize))
+}
+
+func (e *HPKEHybridEncrypt) encrypt(plaintext, info, aad, ephemeralPrivateKey []byte) ([]byte, error) {
+	enc, err := X25519PublicKey(ephemeralPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	dh, err := computeX25519SharedSecret(ephemeralPrivateKey, e.recipientPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	kemContext := append(append([]byte(nil), enc...), e.recipientPublicKey...)
+	if e.mode != hpkeModeBase {
+		dhS, err := computeX25519SharedSecret(e.senderPrivateKey, e.recipientPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("hpke: %s", err)
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, e.senderPublicKey...)
+	}
+	sharedSecret, err := e.suite.sharedSecret(dh, kemContext)
+	if err != nil {
+		return nil, err
+	}
+	a, nonce, err := e.suite.keySchedule(e.mode, sharedSecret, info, e.psk, e.pskID)
+	if err != nil {
+		return nil, err
+	}
+	return a.Seal(enc, nonce, plaintext, aad), nil
+}
+
+// HPKEHybridDecrypt is an instance of HPKE decryption in base, Auth or
+// AuthPSK mode, as specified in RFC 9180. It decrypts ciphertexts of
+// HPKEHybridEncrypt.
+type HPKEHybridDecrypt struct {
+	recipientPrivateKey []byte
+	recipientPublicKey  []byte
+	suite               *hpkeSuite
+	mode                byte
+	// The following are only set in Auth and AuthPSK modes.
+	senderPublicKey []byte
+	psk             []byte
+	pskID           []byte
+}
+
+// Assert that HPKEHybridDecrypt implements the HybridDecrypt interface.
+var _ tink.HybridDecrypt = (*HPKEHybridDecrypt)(nil)
+
+// NewHPKEHybridDecrypt returns an HPKE decryption construct for the given
+// recipient private key and algorithms.
+func NewHPKEHybridDecrypt(recipientPrivateKey []byte, kem HPKEKEM, kdf HPKEKDF, aead HPKEAEAD) (*HPKEHybridDecrypt, error) {
+	suite, err := newHPKESuite(kem, kdf, aead)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := X25519PublicKey(recipientPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	return &HPKEHybridDecrypt{
+		recipientPrivateKey: append([]byte(nil), recipientPrivateKey...),
+		recipientPublicKey:  pub,
+		suite:               suite,
+		mode:                hpkeModeBase,
+	}, nil
+}
+
+// NewHPKEAuthHybridDecrypt returns an HPKE decryption construct in Auth mode
+// for the given recipient private key, which only accepts ciphertexts
+// authenticated with the private key of the given sender public key. If psk
+// is not empty, AuthPSK mode is used instead.
+func NewHPKEAuthHybridDecrypt(recipientPrivateKey, senderPublicKey, psk, pskID []byte, kem HPKEKEM, kdf HPKEKDF, aead HPKEAEAD) (*HPKEHybridDecrypt, error) {
+	d, err := NewHPKEHybridDecrypt(recipientPrivateKey, kem, kdf, aead)
+	if err != nil {
+		return nil, err
+	}
+	d.mode, err = hpkeAuthMode(psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	if len(senderPublicKey) != X25519KeySize {
+		return nil, errors.New("hpke: invalid sender public key")
+	}
+	d.senderPublicKey = append([]byte(nil), senderPublicKey...)
+	d.psk = append([]byte(nil), psk...)
+	d.pskID = append([]byte(nil), pskID...)
+	return d, nil
+}
+
+// NewHPKEAuthHybridDecryptFromBase returns an HPKE decryption construct in
+// Auth mode, or AuthPSK mode if psk is not empty, from the base mode
+// construct of the recipient private key and the base mode encryption
+// construct of the sender public key, which must use the same algorithms.
+func NewHPKEAuthHybridDecryptFromBase(recipient *HPKEHybridDecrypt, sender *HPKEHybridEncrypt, psk, pskID []byte) (*HPKEHybridDecrypt, error) {
+	if recipient.mode != hpkeModeBase || sender.mode != hpkeModeBase {
+		return nil, errors.New("hpke: recipient and sender must be base mode constructs")
+	}
+	if !bytes.Equal(recipient.suite.suiteID, sender.suite.suiteID) {
+		return nil, ErrHPKEAlgorithmMismatch
+	}
+	mode, err := hpkeAuthMode(psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &HPKEHybridDecrypt{
+		recipientPrivateKey: recipient.recipientPrivateKey,
+		recipientPublicKey:  recipient.recipientPublicKey,
+		suite:               recipient.suite,
+		mode:                mode,
+		senderPublicKey:     sender.recipientPublicKey,
+		psk:                 append([]byte(nil), psk...),
+		pskID:               append([]byte(nil), pskID...),
+	}, nil
+}
+
+// Decrypt decrypts ciphertext with contextInfo as HPKE info.
+func (d *HPKEHybridDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	return d.decrypt(ciphertext, contextInfo, nil)
+}
+
+func (d *HPKEHybridDecrypt) decrypt(ciphertext, info, aad []byte) ([]byte, error) {
+	if len(ciphertext) < X25519KeySize {
+		return nil, errors.New("hpke: ciphertext too short")
+	}
+	enc := ciphertext[:X25519KeySize]
+	dh, err := computeX25519SharedSecret(d.recipientPrivateKey, enc)
+	if err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	kemContext := append(append([]byte(nil), enc...), d.recipientPublicKey...)
+	if d.mode != hpkeModeBase {
+		dhS, err := computeX25519SharedSecret(d.recipientPrivateKey, d.senderPublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("hpke: %s", err)
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, d.senderPublicKey...)
+	}
+	sharedSecret, err := d.suite.sharedSecret(dh, kemContext)
+	if err != nil {
+		return nil, err
+	}
+	a, nonce, err := d.suite.keySchedule(d.mode, sharedSecret, info, d.psk, d.pskID)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := a.Open(nil, nonce, ciphertext[X25519KeySize:], aad)
+	if err != nil {
+		return nil, errors.New("hpke: decryption failed")
+	}
+	return pt, nil
+}
+
+// hpkeSuite holds the algorithms of an HPKE ciphersuite.
+type hpkeSuite struct {
+	kemSuiteID []byte
+	suiteID    []byte
+	kdfHash    func() hash.Hash
+	keySize    int
+	newAEAD    func(key []byte) (cipher.AEAD, error)
+}
+
+func newHPKESuite(kem HPKEKEM, kdf HPKEKDF, aead HPKEAEAD) (*hpkeSuite, error) {
+	if kem != HPKEDHKEMX25519HKDFSHA256 {
+		return nil, fmt.Errorf("hpke: unsupported KEM 0x%04x", uint16(kem))
+	}
+	s := &hpkeSuite{
+		kemSuiteID: []byte("KEM"),
+		suiteID:    []byte("HPKE"),
+	}
+	s.kemSuiteID = appendUint16(s.kemSuiteID, uint16(kem))
+	s.suiteID = appendUint16(appendUint16(appendUint16(s.suiteID, uint16(kem)), uint16(kdf)), uint16(aead))
+
+	switch kdf {
+	case HPKEHKDFSHA256:
+		s.kdfHash = sha256.New
+	case HPKEHKDFSHA384:
+		s.kdfHash = sha512.New384
+	case HPKEHKDFSHA512:
+		s.kdfHash = sha512.New
+	default:
+		return nil, fmt.Errorf("hpke: unsupported KDF 0x%04x", uint16(kdf))
+	}
+
+	switch aead {
+	case HPKEAES128GCM, HPKEAES256GCM:
+		s.keySize = 16
+		if aead == HPKEAES256GCM {
+			s.keySize = 32
+		}
+		s.newAEAD = func(key []byte) (cipher.AEAD, error) {
+			c, err := aes.NewCipher(key)
+			if err != nil {
+				return nil, err
+			}
+			return cipher.NewGCM(c)
+		}
+	case HPKEChaCha20Poly1305:
+		s.keySize = chacha20poly1305.KeySize
+		s.newAEAD = chacha20poly1305.New
+	default:
+		return nil, fmt.Errorf("hpke: unsupported AEAD 0x%04x", uint16(aead))
+	}
+	return s, nil
+}
+
+// hpkeAuthMode returns the mode for a sender-authenticated context with the
+// given pre-shared key, which is optional.
+func hpkeAuthMode(psk, pskID []byte) (byte, error) {
+	if len(psk) == 0 && len(pskID) == 0 {
+		return hpkeModeAuth, nil
+	}
+	if len(psk) == 0 || len(pskID) == 0 {
+		return 0, errors.New("hpke: psk and psk ID must both be set or both be empty")
+	}
+	if len(psk) < HPKEMinPSKSize {
+		return 0, fmt.Errorf("hpke: psk must be at least %d bytes", HPKEMinPSKSize)
+	}
+	return hpkeModeAuthPSK, nil
+}
+
+// sharedSecret computes the shared secret of DHKEM(X25519, HKDF-SHA256) from
+// the Diffie-Hellman output and the KEM context, which is the encapsulated key
+// followed by the recipient public key and, in the authenticated modes, the
+// sender public key.
+func (s *hpkeSuite) sharedSecret(dh, kemContext []byte) ([]byte, error) {
+	prk := labeledExtract(sha256.New, s.kemSuiteID, nil, "eae_prk", dh)
+	return labeledExpand(sha256.New, s.kemSuiteID, prk, "shared_secret", kemContext, sha256.Size)
+}
+
+// keySchedule returns the AEAD and the nonce of the first message of an HPKE
+// context in the given mode. Since only one message is encrypted per context,
+// the nonce is the base nonce.
+func (s *hpkeSuite) keySchedule(mode byte, sharedSecret, info, psk, pskID []byte) (cipher.AEAD, []byte, error) {
+	pskIDHash := labeledExtract(s.kdfHash, s.suiteID, nil, "psk_id_hash", pskID)
+	infoHash := labeledExtract(s.kdfHash, s.suiteID, nil, "info_hash", info)
+	context := append(append([]byte{mode}, pskIDHash...), infoHash...)
+	secret := labeledExtract(s.kdfHash, s.suiteID, sharedSecret, "secret", psk)
+
+	key, err := labeledExpand(s.kdfHash, s.suiteID, secret, "key", context, s.keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := s.newAEAD(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("hpke: %s", err)
+	}
+	nonce, err := labeledExpand(s.kdfHash, s.suiteID, secret, "base_nonce", context, a.NonceSize())
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, nonce, nil
+}
+
+func labeledExtract(h func() hash.Hash, suiteID, salt []byte, label string, ikm []byte) []byte {
+	labeledIKM := make([]byte, 0, len(hpkeVersion)+len(suiteID)+len(label)+len(ikm))
+	labeledIKM = append(labeledIKM, hpkeVersion...)
+	labeledIKM = append(labeledIKM, suiteID...)
+	labeledIKM = append(labeledIKM, label...)
+	labeledIKM = append(labeledIKM, ikm...)
+	return hkdf.Extract(h, labeledIKM, salt)
+}
+
+func labeledExpand(h func() hash.Hash, suiteID, prk []byte, label string, info []byte, length int) ([]byte, error) {
+	labeledInfo := appendUint16(nil, uint16(length))
+	labeledInfo = append(labeledInfo, hpkeVersion...)
+	labeledInfo = append(labeledInfo, suiteID...)
+	labeledInfo = append(labeledInfo, label...)
+	labeledInfo = append(labeledInfo, info...)
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(h, prk, labeledInfo), out); err != nil {
+		return nil, fmt.Errorf("hpke: %s", err)
+	}
+	return out, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,289 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+func mustHexDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) failed: %v", s, err)
+	}
+	return b
+}
+
+func TestHPKEVector(t *testing.T) {
+	// Test vector from RFC 9180, Appendix A.1.1: DHKEM(X25519, HKDF-SHA256),
+	// HKDF-SHA256, AES-128-GCM in base mode, first message.
+	info := mustHexDecode(t, "4f6465206f6e2061204772656369616e2055726e")
+	skEm := mustHexDecode(t, "52c4a758a802cd8b936eceea314432798d5baf2d7e9235dc084ab1b9cfa2f736")
+	skRm := mustHexDecode(t, "4612c550263fc8ad58375df3f557aac531d26850903e55a9f23f21d8534e8ac8")
+	pkRm := mustHexDecode(t, "3948cfe0ad1ddb695d780e59077195da6c56506b027329794ab02bca80815c4d")
+	enc := mustHexDecode(t, "37fda3567bdbd628e88668c3c8d7e97d1d1253b6d4ea6d44c150f741f1bf4431")
+	pt := mustHexDecode(t, "4265617574792069732074727574682c20747275746820626561757479")
+	aad := mustHexDecode(t, "436f756e742d30")
+	ct := mustHexDecode(t, "f938558b5d72f1a23810b4be2ab4f84331acc02fc97babc53a52ae8218a355a96d8770ac83d07bea87e13c512a")
+	want := append(append([]byte(nil), enc...), ct...)
+
+	pub, err := X25519PublicKey(skRm)
+	if err != nil {
+		t.Fatalf("X25519PublicKey() err = %v", err)
+	}
+	if !bytes.Equal(pub, pkRm) {
+		t.Errorf("X25519PublicKey() = %x, want %x", pub, pkRm)
+	}
+
+	e, err := NewHPKEHybridEncrypt(pkRm, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+	if err != nil {
+		t.Fatalf("NewHPKEHybridEncrypt() err = %v", err)
+	}
+	got, err := e.encrypt(pt, info, aad, skEm)
+	if err != nil {
+		t.Fatalf("e.encrypt() err = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("e.encrypt() = %x, want %x", got, want)
+	}
+
+	d, err := NewHPKEHybridDecrypt(skRm, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+	if err != nil {
+		t.Fatalf("NewHPKEHybridDecrypt() err = %v", err)
+	}
+	decrypted, err := d.decrypt(want, info, aad)
+	if err != nil {
+		t.Fatalf("d.decrypt() err = %v", err)
+	}
+	if !bytes.Equal(decrypted, pt) {
+		t.Errorf("d.decrypt() = %x, want %x", decrypted, pt)
+	}
+}
+
+func TestHPKEEncryptDecrypt(t *testing.T) {
+	for _, kdf := range []HPKEKDF{HPKEHKDFSHA256, HPKEHKDFSHA384, HPKEHKDFSHA512} {
+		for _, aead := range []HPKEAEAD{HPKEAES128GCM, HPKEAES256GCM, HPKEChaCha20Poly1305} {
+			priv := random.GetRandomBytes(X25519KeySize)
+			pub, err := X25519PublicKey(priv)
+			if err != nil {
+				t.Fatalf("X25519PublicKey() err = %v", err)
+			}
+			e, err := NewHPKEHybridEncrypt(pub, HPKEDHKEMX25519HKDFSHA256, kdf, aead)
+			if err != nil {
+				t.Fatalf("NewHPKEHybridEncrypt(kdf=%d, aead=%d) err = %v", kdf, aead, err)
+			}
+			d, err := NewHPKEHybridDecrypt(priv, HPKEDHKEMX25519HKDFSHA256, kdf, aead)
+			if err != nil {
+				t.Fatalf("NewHPKEHybridDecrypt(kdf=%d, aead=%d) err = %v", kdf, aead, err)
+			}
+			pt := random.GetRandomBytes(100)
+			info := []byte("context info")
+			ct, err := e.Encrypt(pt, info)
+			if err != nil {
+				t.Fatalf("e.Encrypt() err = %v", err)
+			}
+			if len(ct) != X25519KeySize+len(pt)+16 {
+				t.Errorf("len(ct) = %d, want %d", len(ct), X25519KeySize+len(pt)+16)
+			}
+			got, err := d.Decrypt(ct, info)
+			if err != nil {
+				t.Fatalf("d.Decrypt() err = %v", err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("d.Decrypt() = %x, want %x", got, pt)
+			}
+			if _, err := d.Decrypt(ct, []byte("other context info")); err == nil {
+				t.Errorf("d.Decrypt() with wrong context info succeeded")
+			}
+			for i := range ct {
+				modified := append([]byte(nil), ct...)
+				modified[i] ^= 1
+				if _, err := d.Decrypt(modified, info); err == nil {
+					t.Errorf("d.Decrypt() of ciphertext modified at byte %d succeeded", i)
+				}
+			}
+			if _, err := d.Decrypt(ct[:X25519KeySize-1], info); err == nil {
+				t.Errorf("d.Decrypt() of short ciphertext succeeded")
+			}
+		}
+	}
+}
+
+func TestHPKEInvalidParameters(t *testing.T) {
+	priv := random.GetRandomBytes(X25519KeySize)
+	pub, err := X25519PublicKey(priv)
+	if err != nil {
+		t.Fatalf("X25519PublicKey() err = %v", err)
+	}
+	for _, tc := range []struct {
+		kem  HPKEKEM
+		kdf  HPKEKDF
+		aead HPKEAEAD
+	}{
+		{0x0010, HPKEHKDFSHA256, HPKEAES128GCM},
+		{HPKEDHKEMX25519HKDFSHA256, 0, HPKEAES128GCM},
+		{HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, 0xffff},
+	} {
+		if _, err := NewHPKEHybridEncrypt(pub, tc.kem, tc.kdf, tc.aead); err == nil {
+			t.Errorf("NewHPKEHybridEncrypt(%v) succeeded", tc)
+		}
+		if _, err := NewHPKEHybridDecrypt(priv, tc.kem, tc.kdf, tc.aead); err == nil {
+			t.Errorf("NewHPKEHybridDecrypt(%v) succeeded", tc)
+		}
+	}
+	if _, err := NewHPKEHybridEncrypt(pub[1:], HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+		t.Errorf("NewHPKEHybridEncrypt() with short public key succeeded")
+	}
+	if _, err := NewHPKEHybridDecrypt(priv[1:], HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+		t.Errorf("NewHPKEHybridDecrypt() with short private key succeeded")
+	}
+	// A low-order public key gives an all-zero shared secret.
+	e, err := NewHPKEHybridEncrypt(make([]byte, X25519KeySize), HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+	if err != nil {
+		t.Fatalf("NewHPKEHybridEncrypt() err = %v", err)
+	}
+	if _, err := e.Encrypt([]byte("plaintext"), nil); err == nil {
+		t.Errorf("e.Encrypt() to a low-order public key succeeded")
+	}
+}
+
+func mustX25519KeyPair(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	priv, pub, err := GenerateX25519KeyPair()
+	if err != nil {
+		t.Fatalf("GenerateX25519KeyPair() err = %v", err)
+	}
+	return priv, pub
+}
+
+func TestHPKEAuthEncryptDecrypt(t *testing.T) {
+	recipientPriv, recipientPub := mustX25519KeyPair(t)
+	senderPriv, senderPub := mustX25519KeyPair(t)
+	_, otherPub := mustX25519KeyPair(t)
+	psk := random.GetRandomBytes(HPKEMinPSKSize)
+	pskID := []byte("psk id")
+	for _, tc := range []struct {
+		name  string
+		psk   []byte
+		pskID []byte
+	}{
+		{"Auth", nil, nil},
+		{"AuthPSK", psk, pskID},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := NewHPKEAuthHybridEncrypt(recipientPub, senderPriv, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+			if err != nil {
+				t.Fatalf("NewHPKEAuthHybridEncrypt() err = %v", err)
+			}
+			d, err := NewHPKEAuthHybridDecrypt(recipientPriv, senderPub, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+			if err != nil {
+				t.Fatalf("NewHPKEAuthHybridDecrypt() err = %v", err)
+			}
+			pt := random.GetRandomBytes(100)
+			info := []byte("context info")
+			ct, err := e.Encrypt(pt, info)
+			if err != nil {
+				t.Fatalf("e.Encrypt() err = %v", err)
+			}
+			got, err := d.Decrypt(ct, info)
+			if err != nil {
+				t.Fatalf("d.Decrypt() err = %v", err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("d.Decrypt() = %x, want %x", got, pt)
+			}
+
+			base, err := NewHPKEHybridDecrypt(recipientPriv, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+			if err != nil {
+				t.Fatalf("NewHPKEHybridDecrypt() err = %v", err)
+			}
+			if _, err := base.Decrypt(ct, info); err == nil {
+				t.Errorf("base mode decryption of an authenticated ciphertext succeeded")
+			}
+			wrongSender, err := NewHPKEAuthHybridDecrypt(recipientPriv, otherPub, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM)
+			if err != nil {
+				t.Fatalf("NewHPKEAuthHybridDecrypt() err = %v", err)
+			}
+			if _, err := wrongSender.Decrypt(ct, info); err == nil {
+				t.Errorf("decryption with the wrong sender public key succeeded")
+			}
+		})
+	}
+}
+
+func TestHPKEAuthPSKMismatch(t *testing.T) {
+	recipientPriv, recipientPub := mustX25519KeyPair(t)
+	senderPriv, senderPub := mustX25519KeyPair(t)
+	psk := random.GetRandomBytes(HPKEMinPSKSize)
+	e, err := NewHPKEAuthHybridEncrypt(recipientPub, senderPriv, psk, []byte("id"), HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEChaCha20Poly1305)
+	if err != nil {
+		t.Fatalf("NewHPKEAuthHybridEncrypt() err = %v", err)
+	}
+	ct, err := e.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("e.Encrypt() err = %v", err)
+	}
+	for _, tc := range []struct {
+		name  string
+		psk   []byte
+		pskID []byte
+	}{
+		{"no psk", nil, nil},
+		{"wrong psk", random.GetRandomBytes(HPKEMinPSKSize), []byte("id")},
+		{"wrong psk id", psk, []byte("other id")},
+	} {
+		d, err := NewHPKEAuthHybridDecrypt(recipientPriv, senderPub, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEChaCha20Poly1305)
+		if err != nil {
+			t.Fatalf("%s: NewHPKEAuthHybridDecrypt() err = %v", tc.name, err)
+		}
+		if _, err := d.Decrypt(ct, nil); err == nil {
+			t.Errorf("%s: d.Decrypt() succeeded", tc.name)
+		}
+	}
+}
+
+func TestHPKEAuthInvalidParameters(t *testing.T) {
+	recipientPriv, recipientPub := mustX25519KeyPair(t)
+	senderPriv, senderPub := mustX25519KeyPair(t)
+	for _, tc := range []struct {
+		name  string
+		psk   []byte
+		pskID []byte
+	}{
+		{"psk without id", random.GetRandomBytes(HPKEMinPSKSize), nil},
+		{"id without psk", nil, []byte("id")},
+		{"short psk", random.GetRandomBytes(HPKEMinPSKSize - 1), []byte("id")},
+	} {
+		if _, err := NewHPKEAuthHybridEncrypt(recipientPub, senderPriv, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+			t.Errorf("%s: NewHPKEAuthHybridEncrypt() succeeded", tc.name)
+		}
+		if _, err := NewHPKEAuthHybridDecrypt(recipientPriv, senderPub, tc.psk, tc.pskID, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+			t.Errorf("%s: NewHPKEAuthHybridDecrypt() succeeded", tc.name)
+		}
+	}
+	if _, err := NewHPKEAuthHybridEncrypt(recipientPub, senderPriv[1:], nil, nil, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+		t.Errorf("NewHPKEAuthHybridEncrypt() with short sender private key succeeded")
+	}
+	if _, err := NewHPKEAuthHybridDecrypt(recipientPriv, senderPub[1:], nil, nil, HPKEDHKEMX25519HKDFSHA256, HPKEHKDFSHA256, HPKEAES128GCM); err == nil {
+		t.Errorf("NewHPKEAuthHybridDecrypt() with short sender public key succeeded")
+	}
+}
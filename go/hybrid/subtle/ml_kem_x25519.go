@@ -0,0 +1,241 @@
+//go:build go1.24
+// +build go1.24
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/mlkem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/sha3"
+	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// MLKEMX25519PrivateKeySize is the size in bytes of an ML-KEM-768+X25519
+	// private key, which is a seed for both component private keys.
+	MLKEMX25519PrivateKeySize = 32
+	// MLKEMX25519PublicKeySize is the size in bytes of an ML-KEM-768+X25519
+	// public key: the ML-KEM-768 encapsulation key followed by the X25519
+	// public key.
+	MLKEMX25519PublicKeySize = mlkem.EncapsulationKeySize768 + X25519KeySize
+	// MLKEMX25519EncapsulationSize is the size in bytes of the encapsulated
+	// key that prefixes every ciphertext: the ML-KEM-768 ciphertext followed by
+	// the ephemeral X25519 public key.
+	MLKEMX25519EncapsulationSize = mlkem.CiphertextSize768 + X25519KeySize
+)
+
+// mlkemX25519Label is the domain separator of the combiner, as in X-Wing.
+var mlkemX25519Label = []byte(`\.//^\`)
+
+// mlkemX25519PrivateKey holds the component keys expanded from a seed.
+type mlkemX25519PrivateKey struct {
+	mlkem     *mlkem.DecapsulationKey768
+	x25519    []byte
+	x25519Pub []byte
+}
+
+// expandMLKEMX25519PrivateKey derives the ML-KEM-768 and X25519 private keys
+// from a 32-byte seed with SHAKE256.
+func expandMLKEMX25519PrivateKey(seed []byte) (*mlkemX25519PrivateKey, error) {
+	if len(seed) != MLKEMX25519PrivateKeySize {
+		return nil, errors.New("mlkem_x25519: invalid private key")
+	}
+	expanded := make([]byte, mlkem.SeedSize+X25519KeySize)
+	sha3.ShakeSum256(expanded, seed)
+	dk, err := mlkem.NewDecapsulationKey768(expanded[:mlkem.SeedSize])
+	if err != nil {
+		return nil, fmt.Errorf("mlkem_x25519: %s", err)
+	}
+	x25519Priv := expanded[mlkem.SeedSize:]
+	x25519Pub, err := X25519PublicKey(x25519Priv)
+	if err != nil {
+		return nil, err
+	}
+	return &mlkemX25519PrivateKey{mlkem: dk, x25519: x25519Priv, x25519Pub: x25519Pub}, nil
+}
+
+// GenerateMLKEMX25519KeyPair generates a new ML-KEM-768+X25519 private key and
+// returns it together with its public key.
+func GenerateMLKEMX25519KeyPair() (privateKey, publicKey []byte, err error) {
+	privateKey = random.GetRandomBytes(MLKEMX25519PrivateKeySize)
+	publicKey, err = MLKEMX25519PublicKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// MLKEMX25519PublicKey returns the ML-KEM-768+X25519 public key of the given
+// private key.
+func MLKEMX25519PublicKey(privateKey []byte) ([]byte, error) {
+	k, err := expandMLKEMX25519PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(k.mlkem.EncapsulationKey().Bytes(), k.x25519Pub...), nil
+}
+
+// combineMLKEMX25519 combines the component shared secrets into a single
+// secret. The X25519 ciphertext and public key are bound to the result, while
+// the ML-KEM ciphertext is not needed because ML-KEM is itself binding.
+func combineMLKEMX25519(mlkemSecret, x25519Secret, x25519Ciphertext, x25519Pub []byte) []byte {
+	h := sha3.New256()
+	h.Write(mlkemSecret)
+	h.Write(x25519Secret)
+	h.Write(x25519Ciphertext)
+	h.Write(x25519Pub)
+	h.Write(mlkemX25519Label)
+	return h.Sum(nil)
+}
+
+// MLKEMX25519HKDFHybridEncrypt is an instance of hybrid encryption with an
+// ML-KEM-768+X25519 KEM and an AEAD-DEM keyed through HKDF.
+type MLKEMX25519HKDFHybridEncrypt struct {
+	mlkemPub     *mlkem.EncapsulationKey768
+	x25519Pub    []byte
+	hkdfSalt     []byte
+	hkdfHMACAlgo string
+	demHelper    EciesAEADHKDFDEMHelper
+}
+
+// Assert that MLKEMX25519HKDFHybridEncrypt implements the HybridEncrypt interface.
+var _ tink.HybridEncrypt = (*MLKEMX25519HKDFHybridEncrypt)(nil)
+
+// NewMLKEMX25519HKDFHybridEncrypt returns an ML-KEM-768+X25519 hybrid
+// encryption construct for the given public key.
+func NewMLKEMX25519HKDFHybridEncrypt(pub []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*MLKEMX25519HKDFHybridEncrypt, error) {
+	if len(pub) != MLKEMX25519PublicKeySize {
+		return nil, errors.New("mlkem_x25519: invalid public key")
+	}
+	ek, err := mlkem.NewEncapsulationKey768(pub[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, fmt.Errorf("mlkem_x25519: %s", err)
+	}
+	return &MLKEMX25519HKDFHybridEncrypt{
+		mlkemPub:     ek,
+		x25519Pub:    append([]byte(nil), pub[mlkem.EncapsulationKeySize768:]...),
+		hkdfSalt:     hkdfSalt,
+		hkdfHMACAlgo: hkdfHMACAlgo,
+		demHelper:    demHelper,
+	}, nil
+}
+
+// Encrypt encrypts plaintext, binding contextInfo to the derived DEM key.
+func (e *MLKEMX25519HKDFHybridEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	mlkemSecret, mlkemCiphertext := e.mlkemPub.Encapsulate()
+	ephemeralPriv, ephemeralPub, err := GenerateX25519KeyPair()
+	if err != nil {
+		return nil, err
+	}
+	x25519Secret, err := computeX25519SharedSecret(ephemeralPriv, e.x25519Pub)
+	if err != nil {
+		return nil, err
+	}
+	secret := combineMLKEMX25519(mlkemSecret, x25519Secret, ephemeralPub, e.x25519Pub)
+	symmetricKey, err := subtle.ComputeHKDF(e.hkdfHMACAlgo, secret, e.hkdfSalt, contextInfo, e.demHelper.GetSymmetricKeySize())
+	if err != nil {
+		return nil, err
+	}
+	prim, err := e.demHelper.GetAEADOrDAEAD(symmetricKey)
+	if err != nil {
+		return nil, err
+	}
+	var ct []byte
+	switch a := prim.(type) {
+	case tink.AEAD:
+		ct, err = a.Encrypt(plaintext, []byte{})
+	case tink.DeterministicAEAD:
+		ct, err = a.EncryptDeterministically(plaintext, []byte{})
+	default:
+		err = errors.New("Internal error: unexpected primitive type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, MLKEMX25519EncapsulationSize+len(ct))
+	out = append(out, mlkemCiphertext...)
+	out = append(out, ephemeralPub...)
+	return append(out, ct...), nil
+}
+
+// MLKEMX25519HKDFHybridDecrypt is an instance of hybrid decryption with an
+// ML-KEM-768+X25519 KEM and an AEAD-DEM keyed through HKDF.
+type MLKEMX25519HKDFHybridDecrypt struct {
+	privateKey   *mlkemX25519PrivateKey
+	hkdfSalt     []byte
+	hkdfHMACAlgo string
+	demHelper    EciesAEADHKDFDEMHelper
+}
+
+// Assert that MLKEMX25519HKDFHybridDecrypt implements the HybridDecrypt interface.
+var _ tink.HybridDecrypt = (*MLKEMX25519HKDFHybridDecrypt)(nil)
+
+// NewMLKEMX25519HKDFHybridDecrypt returns an ML-KEM-768+X25519 hybrid
+// decryption construct for the given private key seed.
+func NewMLKEMX25519HKDFHybridDecrypt(pvt []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*MLKEMX25519HKDFHybridDecrypt, error) {
+	k, err := expandMLKEMX25519PrivateKey(pvt)
+	if err != nil {
+		return nil, err
+	}
+	return &MLKEMX25519HKDFHybridDecrypt{
+		privateKey:   k,
+		hkdfSalt:     hkdfSalt,
+		hkdfHMACAlgo: hkdfHMACAlgo,
+		demHelper:    demHelper,
+	}, nil
+}
+
+// Decrypt decrypts ciphertext, verifying that it was encrypted with contextInfo.
+func (d *MLKEMX25519HKDFHybridDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	if len(ciphertext) < MLKEMX25519EncapsulationSize {
+		return nil, errors.New("ciphertext too short")
+	}
+	mlkemCiphertext := ciphertext[:mlkem.CiphertextSize768]
+	ephemeralPub := ciphertext[mlkem.CiphertextSize768:MLKEMX25519EncapsulationSize]
+	ct := ciphertext[MLKEMX25519EncapsulationSize:]
+	mlkemSecret, err := d.privateKey.mlkem.Decapsulate(mlkemCiphertext)
+	if err != nil {
+		return nil, fmt.Errorf("mlkem_x25519: %s", err)
+	}
+	x25519Secret, err := computeX25519SharedSecret(d.privateKey.x25519, ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+	secret := combineMLKEMX25519(mlkemSecret, x25519Secret, ephemeralPub, d.privateKey.x25519Pub)
+	symmetricKey, err := subtle.ComputeHKDF(d.hkdfHMACAlgo, secret, d.hkdfSalt, contextInfo, d.demHelper.GetSymmetricKeySize())
+	if err != nil {
+		return nil, err
+	}
+	prim, err := d.demHelper.GetAEADOrDAEAD(symmetricKey)
+	if err != nil {
+		return nil, err
+	}
+	switch a := prim.(type) {
+	case tink.AEAD:
+		return a.Decrypt(ct, []byte{})
+	case tink.DeterministicAEAD:
+		return a.DecryptDeterministically(ct, []byte{})
+	default:
+		return nil, errors.New("Internal error: unexpected primitive type")
+	}
+}
@@ -0,0 +1,92 @@
+//go:build !go1.24
+// +build !go1.24
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// MLKEMX25519PrivateKeySize is the size in bytes of an ML-KEM-768+X25519
+	// private key, which is a seed for both component private keys.
+	MLKEMX25519PrivateKeySize = 32
+	// MLKEMX25519PublicKeySize is the size in bytes of an ML-KEM-768+X25519
+	// public key: the ML-KEM-768 encapsulation key followed by the X25519
+	// public key.
+	MLKEMX25519PublicKeySize = 1184 + X25519KeySize
+	// MLKEMX25519EncapsulationSize is the size in bytes of the encapsulated
+	// key that prefixes every ciphertext: the ML-KEM-768 ciphertext followed by
+	// the ephemeral X25519 public key.
+	MLKEMX25519EncapsulationSize = 1088 + X25519KeySize
+)
+
+// errMLKEMX25519Unsupported is returned by the ML-KEM-768+X25519 functions
+// when the binary is built with a Go version that lacks crypto/mlkem.
+var errMLKEMX25519Unsupported = errors.New("mlkem_x25519: ML-KEM requires Go 1.24 or later")
+
+// GenerateMLKEMX25519KeyPair is not supported before Go 1.24 and always
+// returns an error.
+func GenerateMLKEMX25519KeyPair() (privateKey, publicKey []byte, err error) {
+	return nil, nil, errMLKEMX25519Unsupported
+}
+
+// MLKEMX25519PublicKey is not supported before Go 1.24 and always returns an
+// error.
+func MLKEMX25519PublicKey(privateKey []byte) ([]byte, error) {
+	return nil, errMLKEMX25519Unsupported
+}
+
+// MLKEMX25519HKDFHybridEncrypt is an instance of hybrid encryption with an
+// ML-KEM-768+X25519 KEM. It is not supported before Go 1.24.
+type MLKEMX25519HKDFHybridEncrypt struct{}
+
+// Assert that MLKEMX25519HKDFHybridEncrypt implements the HybridEncrypt interface.
+var _ tink.HybridEncrypt = (*MLKEMX25519HKDFHybridEncrypt)(nil)
+
+// NewMLKEMX25519HKDFHybridEncrypt is not supported before Go 1.24 and always
+// returns an error.
+func NewMLKEMX25519HKDFHybridEncrypt(pub []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*MLKEMX25519HKDFHybridEncrypt, error) {
+	return nil, errMLKEMX25519Unsupported
+}
+
+// Encrypt always returns an error.
+func (e *MLKEMX25519HKDFHybridEncrypt) Encrypt(plaintext, contextInfo []byte) ([]byte, error) {
+	return nil, errMLKEMX25519Unsupported
+}
+
+// MLKEMX25519HKDFHybridDecrypt is an instance of hybrid decryption with an
+// ML-KEM-768+X25519 KEM. It is not supported before Go 1.24.
+type MLKEMX25519HKDFHybridDecrypt struct{}
+
+// Assert that MLKEMX25519HKDFHybridDecrypt implements the HybridDecrypt interface.
+var _ tink.HybridDecrypt = (*MLKEMX25519HKDFHybridDecrypt)(nil)
+
+// NewMLKEMX25519HKDFHybridDecrypt is not supported before Go 1.24 and always
+// returns an error.
+func NewMLKEMX25519HKDFHybridDecrypt(pvt []byte, hkdfSalt []byte, hkdfHMACAlgo string, demHelper EciesAEADHKDFDEMHelper) (*MLKEMX25519HKDFHybridDecrypt, error) {
+	return nil, errMLKEMX25519Unsupported
+}
+
+// Decrypt always returns an error.
+func (d *MLKEMX25519HKDFHybridDecrypt) Decrypt(ciphertext, contextInfo []byte) ([]byte, error) {
+	return nil, errMLKEMX25519Unsupported
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+	"github.com/google/tink/go/subtle/random"
+)
+
+// X25519KeySize is the size of X25519 private and public keys in bytes.
+const X25519KeySize = 32
+
+// GenerateX25519KeyPair generates a new X25519 private key and returns it
+// together with its public key.
+func GenerateX25519KeyPair() (privateKey, publicKey []byte, err error) {
+	privateKey = random.GetRandomBytes(X25519KeySize)
+	publicKey, err = X25519PublicKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// X25519PublicKey returns the X25519 public key of the given private key.
+func X25519PublicKey(privateKey []byte) ([]byte, error) {
+	if len(privateKey) != X25519KeySize {
+		return nil, errors.New("x25519: invalid private key")
+	}
+	pub, err := curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("x25519: %s", err)
+	}
+	return pub, nil
+}
+
+// computeX25519SharedSecret computes the X25519 shared secret of the given
+// private and public keys. It fails if the shared secret is all zeros, which
+// happens for public keys of small order.
+func computeX25519SharedSecret(privateKey, publicKey []byte) ([]byte, error) {
+	if len(publicKey) != X25519KeySize {
+		return nil, errors.New("x25519: invalid public key")
+	}
+	secret, err := curve25519.X25519(privateKey, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("x25519: %s", err)
+	}
+	return secret, nil
+}
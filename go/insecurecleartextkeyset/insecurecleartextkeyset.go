@@ -1,3 +1,6 @@
+//go:build !tink_nocleartext
+// +build !tink_nocleartext
+
 // Copyright 2019 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -19,6 +22,11 @@
 //
 // This package contains dangerous functions, and is separate from the rest of
 // Tink so that its usage can be restricted and audited.
+//
+// Building with the tink_nocleartext build tag makes every import of this
+// package, and of packages that depend on it, fail to compile. Production
+// binaries built with the tag are thereby guaranteed not to read or write
+// cleartext keysets.
 package insecurecleartextkeyset
 
 import (
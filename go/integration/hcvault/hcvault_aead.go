@@ -17,6 +17,7 @@
 package hcvault
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -27,19 +28,45 @@ import (
 	"github.com/google/tink/go/tink"
 )
 
+// AEAD is implemented by the AEADs of the clients of this package. In
+// addition to tink.AEAD, it supports contexts, and encrypting and decrypting
+// many values with a single request to the batch endpoints of the transit
+// secrets engine, e.g. for envelope encryption of many DEKs.
+type AEAD interface {
+	tink.AEAD
+
+	// EncryptWithContext is Encrypt with a context for the request to Vault.
+	EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error)
+
+	// DecryptWithContext is Decrypt with a context for the request to Vault.
+	DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error)
+
+	// EncryptBatch encrypts plaintexts[i] with additionalData[i] for every i
+	// in one request. additionalData must be nil or have the same length as
+	// plaintexts. It fails if any plaintext cannot be encrypted.
+	EncryptBatch(ctx context.Context, plaintexts, additionalData [][]byte) ([][]byte, error)
+
+	// DecryptBatch decrypts ciphertexts[i] with additionalData[i] for every i
+	// in one request. additionalData must be nil or have the same length as
+	// ciphertexts. It fails if any ciphertext cannot be decrypted.
+	DecryptBatch(ctx context.Context, ciphertexts, additionalData [][]byte) ([][]byte, error)
+}
+
 // vaultAEAD represents a HashiCorp Vault service to a particular URI.
 type vaultAEAD struct {
 	keyURI string
-	client *api.Logical
+	client *api.Client
+	tokens *tokenManager
 }
 
-var _ tink.AEAD = (*vaultAEAD)(nil)
+var _ AEAD = (*vaultAEAD)(nil)
 
 // newHCVaultAEAD returns a new HashiCorp Vault service.
-func newHCVaultAEAD(keyURI string, client *api.Logical) tink.AEAD {
+func newHCVaultAEAD(keyURI string, client *api.Client, tokens *tokenManager) AEAD {
 	return &vaultAEAD{
 		keyURI: keyURI,
 		client: client,
+		tokens: tokens,
 	}
 }
 
@@ -47,6 +74,12 @@ func newHCVaultAEAD(keyURI string, client *api.Logical) tink.AEAD {
 // additionalData parameter is used as a context for key derivation, more
 // information available https://www.vaultproject.io/docs/secrets/transit/index.html.
 func (a *vaultAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	return a.EncryptWithContext(context.Background(), plaintext, additionalData)
+}
+
+// EncryptWithContext encrypts the plaintext data using a key stored in
+// HashiCorp Vault, using ctx for the request.
+func (a *vaultAEAD) EncryptWithContext(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
 	encryptionPath, err := a.getEncryptionPath(a.keyURI)
 	if err != nil {
 		return nil, err
@@ -57,11 +90,14 @@ func (a *vaultAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
 		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
 		"context":   base64.StdEncoding.EncodeToString(additionalData),
 	}
-	secret, err := a.client.Write(encryptionPath, req)
+	secret, err := a.write(ctx, encryptionPath, req)
 	if err != nil {
 		return nil, err
 	}
-	ciphertext := secret.Data["ciphertext"].(string)
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok {
+		return nil, errors.New("malformed encryption response")
+	}
 	return []byte(ciphertext), nil
 }
 
@@ -69,6 +105,12 @@ func (a *vaultAEAD) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
 // additionalData parameter is used as a context for key derivation, more
 // information available https://www.vaultproject.io/docs/secrets/transit/index.html.
 func (a *vaultAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
+	return a.DecryptWithContext(context.Background(), ciphertext, additionalData)
+}
+
+// DecryptWithContext decrypts the ciphertext using a key stored in HashiCorp
+// Vault, using ctx for the request.
+func (a *vaultAEAD) DecryptWithContext(ctx context.Context, ciphertext, additionalData []byte) ([]byte, error) {
 	decryptionPath, err := a.getDecryptionPath(a.keyURI)
 	if err != nil {
 		return nil, err
@@ -79,11 +121,14 @@ func (a *vaultAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
 		"ciphertext": string(ciphertext),
 		"context":    base64.StdEncoding.EncodeToString(additionalData),
 	}
-	secret, err := a.client.Write(decryptionPath, req)
+	secret, err := a.write(ctx, decryptionPath, req)
 	if err != nil {
 		return nil, err
 	}
-	plaintext64 := secret.Data["plaintext"].(string)
+	plaintext64, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("malformed decryption response")
+	}
 	plaintext, err := base64.StdEncoding.DecodeString(plaintext64)
 	if err != nil {
 		return nil, err
@@ -91,6 +136,136 @@ func (a *vaultAEAD) Decrypt(ciphertext, additionalData []byte) ([]byte, error) {
 	return plaintext, nil
 }
 
+// EncryptBatch encrypts the plaintexts using a key stored in HashiCorp Vault
+// with a single request.
+func (a *vaultAEAD) EncryptBatch(ctx context.Context, plaintexts, additionalData [][]byte) ([][]byte, error) {
+	if additionalData != nil && len(additionalData) != len(plaintexts) {
+		return nil, errors.New("plaintexts and additional data must have the same length")
+	}
+	encryptionPath, err := a.getEncryptionPath(a.keyURI)
+	if err != nil {
+		return nil, err
+	}
+	// Create a batch encryption request according to Vault REST API:
+	// https://www.vaultproject.io/api/secret/transit/index.html#batch_input.
+	batch := make([]map[string]interface{}, len(plaintexts))
+	for i, plaintext := range plaintexts {
+		batch[i] = map[string]interface{}{
+			"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+			"context":   base64.StdEncoding.EncodeToString(batchItem(additionalData, i)),
+		}
+	}
+	results, err := a.writeBatch(ctx, encryptionPath, batch)
+	if err != nil {
+		return nil, err
+	}
+	ciphertexts := make([][]byte, len(results))
+	for i, result := range results {
+		ciphertext, ok := result["ciphertext"].(string)
+		if !ok {
+			return nil, fmt.Errorf("malformed encryption response for item %d", i)
+		}
+		ciphertexts[i] = []byte(ciphertext)
+	}
+	return ciphertexts, nil
+}
+
+// DecryptBatch decrypts the ciphertexts using a key stored in HashiCorp Vault
+// with a single request.
+func (a *vaultAEAD) DecryptBatch(ctx context.Context, ciphertexts, additionalData [][]byte) ([][]byte, error) {
+	if additionalData != nil && len(additionalData) != len(ciphertexts) {
+		return nil, errors.New("ciphertexts and additional data must have the same length")
+	}
+	decryptionPath, err := a.getDecryptionPath(a.keyURI)
+	if err != nil {
+		return nil, err
+	}
+	batch := make([]map[string]interface{}, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		batch[i] = map[string]interface{}{
+			"ciphertext": string(ciphertext),
+			"context":    base64.StdEncoding.EncodeToString(batchItem(additionalData, i)),
+		}
+	}
+	results, err := a.writeBatch(ctx, decryptionPath, batch)
+	if err != nil {
+		return nil, err
+	}
+	plaintexts := make([][]byte, len(results))
+	for i, result := range results {
+		plaintext64, ok := result["plaintext"].(string)
+		if !ok {
+			return nil, fmt.Errorf("malformed decryption response for item %d", i)
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(plaintext64)
+		if err != nil {
+			return nil, err
+		}
+		plaintexts[i] = plaintext
+	}
+	return plaintexts, nil
+}
+
+// batchItem returns the i-th additional data of a batch, which is empty if
+// no additional data is given.
+func batchItem(additionalData [][]byte, i int) []byte {
+	if additionalData == nil {
+		return nil
+	}
+	return additionalData[i]
+}
+
+// writeBatch sends batch as the batch_input of a request to path, and returns
+// the batch_results, failing if any item failed.
+func (a *vaultAEAD) writeBatch(ctx context.Context, path string, batch []map[string]interface{}) ([]map[string]interface{}, error) {
+	secret, err := a.write(ctx, path, map[string]interface{}{"batch_input": batch})
+	if err != nil {
+		return nil, err
+	}
+	items, ok := secret.Data["batch_results"].([]interface{})
+	if !ok || len(items) != len(batch) {
+		return nil, errors.New("malformed batch response")
+	}
+	results := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		result, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("malformed batch response for item %d", i)
+		}
+		if msg, ok := result["error"].(string); ok && msg != "" {
+			return nil, fmt.Errorf("item %d failed: %s", i, msg)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// write sends a request to Vault, making sure that the client has a valid
+// token first. If Vault denies the request and the client logs in with a
+// LoginFunc, it logs in again and retries once.
+func (a *vaultAEAD) write(ctx context.Context, path string, data map[string]interface{}) (*api.Secret, error) {
+	if err := a.tokens.ensure(ctx); err != nil {
+		return nil, fmt.Errorf("cannot obtain Vault token: %s", err)
+	}
+	secret, err := write(ctx, a.client, path, data)
+	if isPermissionDenied(err) {
+		canLogin, loginErr := a.tokens.reauth(ctx)
+		if loginErr != nil {
+			return nil, fmt.Errorf("cannot obtain Vault token: %s", loginErr)
+		}
+		if canLogin {
+			secret, err = write(ctx, a.client, path, data)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, errors.New("empty response from Vault")
+	}
+	return secret, nil
+}
+
 // getEncryptionPath transforms keyURL to a Vault encryption path.
 // For example a keyURL "transit/keys/key-foo" will be transformed to "transit/encrypt/key-foo".
 func (a *vaultAEAD) getEncryptionPath(keyURL string) (string, error) {
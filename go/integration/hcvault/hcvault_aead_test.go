@@ -17,6 +17,7 @@ package hcvault
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
@@ -24,10 +25,15 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
 )
 
 const (
@@ -223,3 +229,242 @@ func decrypt(ctb, context []byte) ([]byte, error) {
 	}
 	return pt, nil
 }
+
+// fakeVault is a transit secrets engine which accepts the tokens in tokens.
+type fakeVault struct {
+	t         *testing.T
+	namespace string
+
+	mu       sync.Mutex
+	tokens   map[string]bool
+	renewals int
+}
+
+func newFakeVault(t *testing.T, namespace string, tokens ...string) (*fakeVault, string) {
+	v := &fakeVault{t: t, namespace: namespace, tokens: make(map[string]bool)}
+	for _, token := range tokens {
+		v.tokens[token] = true
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(v.handle))
+	t.Cleanup(server.Close)
+	return v, "hcvault://" + strings.TrimPrefix(server.URL, "https://") + "/"
+}
+
+func (v *fakeVault) setToken(token string, valid bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokens[token] = valid
+}
+
+func (v *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	valid := v.tokens[r.Header.Get("X-Vault-Token")]
+	v.mu.Unlock()
+	if !valid || r.Header.Get("X-Vault-Namespace") != v.namespace {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+	var req map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		v.t.Errorf("Cannot decode request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var data map[string]interface{}
+	switch r.URL.Path {
+	case "/v1/auth/token/renew-self":
+		v.mu.Lock()
+		v.renewals++
+		v.mu.Unlock()
+		v.writeJSON(w, map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   r.Header.Get("X-Vault-Token"),
+				"lease_duration": 60,
+				"renewable":      true,
+			},
+		})
+		return
+	case "/v1/transit/encrypt/key-1":
+		data = v.batch(req, func(item map[string]interface{}) map[string]interface{} {
+			pt, _ := base64.StdEncoding.DecodeString(item["plaintext"].(string))
+			context, _ := base64.StdEncoding.DecodeString(item["context"].(string))
+			return map[string]interface{}{"ciphertext": string(encrypt(pt, context))}
+		})
+	case "/v1/transit/decrypt/key-1":
+		data = v.batch(req, func(item map[string]interface{}) map[string]interface{} {
+			context, _ := base64.StdEncoding.DecodeString(item["context"].(string))
+			pt, err := decrypt([]byte(item["ciphertext"].(string)), context)
+			if err != nil {
+				return map[string]interface{}{"error": err.Error()}
+			}
+			return map[string]interface{}{"plaintext": base64.StdEncoding.EncodeToString(pt)}
+		})
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	v.writeJSON(w, map[string]interface{}{"data": data})
+}
+
+// batch applies op to the batch_input of req, or to req itself if it isn't
+// a batch request.
+func (v *fakeVault) batch(req map[string]interface{}, op func(map[string]interface{}) map[string]interface{}) map[string]interface{} {
+	input, ok := req["batch_input"].([]interface{})
+	if !ok {
+		return op(req)
+	}
+	results := make([]interface{}, len(input))
+	for i, item := range input {
+		results[i] = op(item.(map[string]interface{}))
+	}
+	return map[string]interface{}{"batch_results": results}
+}
+
+func (v *fakeVault) writeJSON(w http.ResponseWriter, resp interface{}) {
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		v.t.Errorf("Cannot encode response: %v", err)
+		return
+	}
+	w.Write(respBytes)
+}
+
+func TestVaultAEADBatch(t *testing.T) {
+	_, uriPrefix := newFakeVault(t, "", token)
+	client, err := NewClientWithOptions(uriPrefix, WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), WithToken(token))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() failed: %v", err)
+	}
+	a, err := client.GetAEAD(uriPrefix + "transit/keys/key-1")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() failed: %v", err)
+	}
+	batchAEAD, ok := a.(AEAD)
+	if !ok {
+		t.Fatal("client.GetAEAD() is not an AEAD")
+	}
+	ctx := context.Background()
+	plaintexts := [][]byte{[]byte("dek-1"), []byte("dek-2"), []byte("dek-3")}
+	additionalData := [][]byte{[]byte("ad-1"), []byte("ad-2"), []byte("ad-3")}
+	ciphertexts, err := batchAEAD.EncryptBatch(ctx, plaintexts, additionalData)
+	if err != nil {
+		t.Fatalf("EncryptBatch() failed: %v", err)
+	}
+	if len(ciphertexts) != len(plaintexts) {
+		t.Fatalf("EncryptBatch() returned %d ciphertexts, want %d", len(ciphertexts), len(plaintexts))
+	}
+	// Items of a batch can be decrypted on their own.
+	pt, err := batchAEAD.DecryptWithContext(ctx, ciphertexts[1], additionalData[1])
+	if err != nil || !bytes.Equal(pt, plaintexts[1]) {
+		t.Errorf("DecryptWithContext() = %q, %v, want %q, nil", pt, err, plaintexts[1])
+	}
+	got, err := batchAEAD.DecryptBatch(ctx, ciphertexts, additionalData)
+	if err != nil {
+		t.Fatalf("DecryptBatch() failed: %v", err)
+	}
+	for i := range plaintexts {
+		if !bytes.Equal(got[i], plaintexts[i]) {
+			t.Errorf("DecryptBatch()[%d] = %q, want %q", i, got[i], plaintexts[i])
+		}
+	}
+
+	if _, err := batchAEAD.DecryptBatch(ctx, ciphertexts, [][]byte{nil, nil, nil}); err == nil {
+		t.Error("DecryptBatch() with wrong additional data succeeded, want error")
+	}
+	if _, err := batchAEAD.EncryptBatch(ctx, plaintexts, additionalData[:1]); err == nil {
+		t.Error("EncryptBatch() with too little additional data succeeded, want error")
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := batchAEAD.EncryptWithContext(canceled, plaintexts[0], nil); err == nil {
+		t.Error("EncryptWithContext() with a canceled context succeeded, want error")
+	}
+}
+
+func TestVaultAEADNamespace(t *testing.T) {
+	_, uriPrefix := newFakeVault(t, "team-a/", token)
+	keyURI := uriPrefix + "transit/keys/key-1"
+	client, err := NewClientWithOptions(uriPrefix, WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), WithToken(token), WithNamespace("team-a/"))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() failed: %v", err)
+	}
+	a, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() failed: %v", err)
+	}
+	if _, err := a.Encrypt([]byte("data"), nil); err != nil {
+		t.Errorf("a.Encrypt() failed: %v", err)
+	}
+
+	client, err = NewClient(uriPrefix, &tls.Config{InsecureSkipVerify: true}, token)
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	a, err = client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() failed: %v", err)
+	}
+	if _, err := a.Encrypt([]byte("data"), nil); err == nil {
+		t.Error("a.Encrypt() without the namespace succeeded, want error")
+	}
+}
+
+func TestVaultAEADLogin(t *testing.T) {
+	v, uriPrefix := newFakeVault(t, "")
+	var logins int
+	login := func(ctx context.Context, c *api.Client) (*api.Secret, error) {
+		logins++
+		token := fmt.Sprintf("token-%d", logins)
+		v.setToken(token, true)
+		return &api.Secret{Auth: &api.SecretAuth{ClientToken: token, LeaseDuration: 60, Renewable: true}}, nil
+	}
+	client, err := NewClientWithOptions(uriPrefix, WithTLSConfig(&tls.Config{InsecureSkipVerify: true}), WithLogin(login))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() failed: %v", err)
+	}
+	now := time.Now()
+	client.(*vaultClient).tokens.now = func() time.Time { return now }
+	a, err := client.GetAEAD(uriPrefix + "transit/keys/key-1")
+	if err != nil {
+		t.Fatalf("client.GetAEAD() failed: %v", err)
+	}
+	ct, err := a.Encrypt([]byte("data"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() failed: %v", err)
+	}
+	if logins != 1 {
+		t.Errorf("logged in %d times, want 1", logins)
+	}
+
+	// The token is renewed when less than a third of its TTL is left.
+	now = now.Add(45 * time.Second)
+	if _, err := a.Decrypt(ct, nil); err != nil {
+		t.Fatalf("a.Decrypt() failed: %v", err)
+	}
+	if v.renewals != 1 || logins != 1 {
+		t.Errorf("renewed %d times and logged in %d times, want 1 and 1", v.renewals, logins)
+	}
+
+	// A revoked token is replaced by logging in again.
+	v.setToken("token-1", false)
+	if _, err := a.Decrypt(ct, nil); err != nil {
+		t.Fatalf("a.Decrypt() with a revoked token failed: %v", err)
+	}
+	if logins != 2 {
+		t.Errorf("logged in %d times, want 2", logins)
+	}
+
+	// An expired token is replaced by logging in again.
+	now = now.Add(2 * time.Minute)
+	if _, err := a.Decrypt(ct, nil); err != nil {
+		t.Fatalf("a.Decrypt() with an expired token failed: %v", err)
+	}
+	if logins != 3 {
+		t.Errorf("logged in %d times, want 3", logins)
+	}
+
+	if _, err := NewClientWithOptions(uriPrefix, WithToken(token), WithLogin(login)); err == nil {
+		t.Error("NewClientWithOptions() with a token and a login function succeeded, want error")
+	}
+}
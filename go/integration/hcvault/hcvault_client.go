@@ -18,6 +18,7 @@
 package hcvault
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -37,11 +38,51 @@ const (
 // vaultClient represents a client that connects to the HashiCorp Vault backend.
 type vaultClient struct {
 	keyURIPrefix string
-	client       *api.Logical
+	client       *api.Client
+	tokens       *tokenManager
 }
 
 var _ registry.KMSClient = (*vaultClient)(nil)
 
+// ClientOption is an option for creating a client with NewClientWithOptions.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	tlsCfg    *tls.Config
+	token     string
+	namespace string
+	login     LoginFunc
+}
+
+// LoginFunc logs in to Vault with c, e.g. with the AppRole or Kubernetes auth
+// method, and returns the secret whose Auth holds the new token. It must not
+// change the token of c.
+type LoginFunc func(ctx context.Context, c *api.Client) (*api.Secret, error)
+
+// WithTLSConfig sets the tls.Config which is used to communicate with the
+// Vault server via HTTPS. By default, tls.Config{} is used.
+func WithTLSConfig(tlsCfg *tls.Config) ClientOption {
+	return func(o *clientOptions) { o.tlsCfg = tlsCfg }
+}
+
+// WithToken sets a static Vault token.
+func WithToken(token string) ClientOption {
+	return func(o *clientOptions) { o.token = token }
+}
+
+// WithNamespace sets the Vault Enterprise namespace of the keys, e.g.
+// "team-a/". Key paths are relative to the namespace.
+func WithNamespace(namespace string) ClientOption {
+	return func(o *clientOptions) { o.namespace = namespace }
+}
+
+// WithLogin makes the client obtain its token with login when it is first
+// used. The token is renewed before it expires if it is renewable, and login
+// is called again if it isn't, if renewal fails, or if Vault denies a request.
+func WithLogin(login LoginFunc) ClientOption {
+	return func(o *clientOptions) { o.login = login }
+}
+
 // NewClient returns a new client to HashiCorp Vault.
 // uriPrefix parameter is a valid URI which must have "hcvault" scheme and
 // vault server address and port. Specific key URIs will be matched against this
@@ -50,12 +91,29 @@ var _ registry.KMSClient = (*vaultClient)(nil)
 // server via HTTPS protocol. If not specified a default tls.Config{} will be
 // used.
 func NewClient(uriPrefix string, tlsCfg *tls.Config, token string) (registry.KMSClient, error) {
+	return NewClientWithOptions(uriPrefix, WithTLSConfig(tlsCfg), WithToken(token))
+}
+
+// NewClientWithOptions returns a new client to HashiCorp Vault, configured
+// with the given options. uriPrefix is as for NewClient.
+//
+// The AEADs returned by the client implement AEAD, which adds context support
+// and batch operations.
+func NewClientWithOptions(uriPrefix string, opts ...ClientOption) (registry.KMSClient, error) {
 	if !strings.HasPrefix(strings.ToLower(uriPrefix), vaultPrefix) {
 		return nil, fmt.Errorf("key URI must start with %s", vaultPrefix)
 	}
+	o := new(clientOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.token != "" && o.login != nil {
+		return nil, errors.New("only one of a token and a login function can be set")
+	}
 
 	httpClient := api.DefaultConfig().HttpClient
 	transport := httpClient.Transport.(*http.Transport)
+	tlsCfg := o.tlsCfg
 	if tlsCfg == nil {
 		tlsCfg = &tls.Config{}
 	} else {
@@ -78,12 +136,19 @@ func NewClient(uriPrefix string, tlsCfg *tls.Config, token string) (registry.KMS
 		return nil, err
 	}
 
-	client.SetToken(token)
+	client.SetToken(o.token)
+	if o.namespace != "" {
+		client.SetNamespace(o.namespace)
+	}
+	var tokens *tokenManager
+	if o.login != nil {
+		tokens = newTokenManager(client, o.login)
+	}
 	return &vaultClient{
 		keyURIPrefix: uriPrefix,
-		client:       client.Logical(),
+		client:       client,
+		tokens:       tokens,
 	}, nil
-
 }
 
 // Supported returns true if this client does support keyURI.
@@ -97,5 +162,5 @@ func (c *vaultClient) GetAEAD(keyURI string) (tink.AEAD, error) {
 		return nil, errors.New("unsupported keyURI")
 	}
 
-	return newHCVaultAEAD(keyURI, c.client), nil
+	return newHCVaultAEAD(keyURI, c.client, c.tokens), nil
 }
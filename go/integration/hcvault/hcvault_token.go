@@ -0,0 +1,141 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package hcvault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// tokenManager keeps the token of a Vault client obtained with a LoginFunc
+// valid. It is safe for concurrent use.
+type tokenManager struct {
+	client *api.Client
+	login  LoginFunc
+	now    func() time.Time
+
+	mu sync.Mutex
+	// refresh is when the token should be renewed, a third of its TTL before
+	// it expires, and expiry when it expires. Both are zero before the first
+	// login, and for tokens that don't expire.
+	refresh   time.Time
+	expiry    time.Time
+	ttl       time.Duration
+	renewable bool
+	loggedIn  bool
+}
+
+func newTokenManager(client *api.Client, login LoginFunc) *tokenManager {
+	return &tokenManager{
+		client: client,
+		login:  login,
+		now:    time.Now,
+	}
+}
+
+// ensure makes sure that the client has a token which is not about to
+// expire. It logs in the first time, and then renews the token or logs in
+// again when it is due. A nil tokenManager leaves the static token as it is.
+func (m *tokenManager) ensure(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.loggedIn && (m.refresh.IsZero() || m.now().Before(m.refresh)) {
+		return nil
+	}
+	if m.loggedIn && m.renewable && m.now().Before(m.expiry) {
+		secret, err := write(ctx, m.client, "auth/token/renew-self", map[string]interface{}{
+			"increment": int(m.ttl.Seconds()),
+		})
+		if err == nil && secret != nil && secret.Auth != nil {
+			m.set(secret.Auth)
+			return nil
+		}
+		// Fall back to logging in again.
+	}
+	return m.loginLocked(ctx)
+}
+
+// reauth logs in again, e.g. after Vault denied a request because the token
+// was revoked. It reports whether there is a login function to do so.
+func (m *tokenManager) reauth(ctx context.Context) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return true, m.loginLocked(ctx)
+}
+
+func (m *tokenManager) loginLocked(ctx context.Context) error {
+	secret, err := m.login(ctx, m.client)
+	if err != nil {
+		return err
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.New("login returned no token")
+	}
+	m.set(secret.Auth)
+	return nil
+}
+
+// set makes auth the token of the client.
+func (m *tokenManager) set(auth *api.SecretAuth) {
+	if auth.ClientToken != "" {
+		m.client.SetToken(auth.ClientToken)
+	}
+	m.loggedIn = true
+	m.renewable = auth.Renewable
+	m.ttl = time.Duration(auth.LeaseDuration) * time.Second
+	if m.ttl <= 0 {
+		m.refresh, m.expiry = time.Time{}, time.Time{}
+		return
+	}
+	now := m.now()
+	m.expiry = now.Add(m.ttl)
+	m.refresh = m.expiry.Add(-m.ttl / 3)
+}
+
+// write writes data to path like api.Logical.Write, but with a context.
+func write(ctx context.Context, client *api.Client, path string, data interface{}) (*api.Secret, error) {
+	r := client.NewRequest("PUT", "/v1/"+path)
+	if err := r.SetJSONBody(data); err != nil {
+		return nil, err
+	}
+	resp, err := client.RawRequestWithContext(ctx, r)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseSecret(resp.Body)
+}
+
+// isPermissionDenied reports whether err is Vault denying a request, e.g.
+// because the token expired or was revoked.
+func isPermissionDenied(err error) bool {
+	var respErr *api.ResponseError
+	return errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden
+}
@@ -16,7 +16,9 @@
 
 // Package internal provides a coordination point for package keyset, package
 // insecurecleartextkeyset, and package testkeyset.  internal must only be
-// imported by these three packages.
+// imported by these three packages, by package aead, which reads key
+// material to compute key commitments, and by package keyderivation, which
+// creates handles of derived keysets.
 package internal
 
 // KeysetHandle is a raw constructor of keyset.Handle.
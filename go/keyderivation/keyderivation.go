@@ -0,0 +1,46 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package keyderivation provides implementations of the keyset deriver
+// primitive.
+//
+// A keyset deriver deterministically derives a keyset from a salt, e.g. to
+// obtain per-tenant or per-file keys from a single keyset without storing
+// them. The derived keysets are of the key template that the deriving keys
+// were created with, which must be of a key type that supports key derivation.
+package keyderivation
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+)
+
+// KeysetDeriver is the interface for deriving keysets from salts.
+//
+// Deriving a keyset from the same salt always returns a keyset with the same
+// key material. Different salts result in independent keysets.
+type KeysetDeriver interface {
+	// DeriveKeyset derives the keyset for the given salt.
+	DeriveKeyset(salt []byte) (*keyset.Handle, error)
+}
+
+func init() {
+	if err := registry.RegisterKeyManager(new(prfBasedDeriverKeyManager)); err != nil {
+		panic(fmt.Sprintf("keyderivation.init() failed: %v", err))
+	}
+}
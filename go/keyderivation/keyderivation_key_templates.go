@@ -0,0 +1,54 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	prfderpb "github.com/google/tink/go/proto/prf_based_deriver_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// CreatePRFBasedKeyTemplate returns a KeyTemplate that generates keys which
+// derive keysets of derivedKeyTemplate, using a PRF key generated from
+// prfKeyTemplate. Only HKDF PRF key templates, e.g.
+// prf.HKDFSHA256PRFKeyTemplate(), are supported as prfKeyTemplate. The output
+// prefix type of the returned template and of the derived keys is the one of
+// derivedKeyTemplate.
+//
+// An error is returned if keys of the templates cannot be generated or
+// derived, e.g. because their key managers are not registered.
+func CreatePRFBasedKeyTemplate(prfKeyTemplate, derivedKeyTemplate *tinkpb.KeyTemplate) (*tinkpb.KeyTemplate, error) {
+	serializedFormat, err := proto.Marshal(&prfderpb.PrfBasedDeriverKeyFormat{
+		PrfKeyTemplate: prfKeyTemplate,
+		Params: &prfderpb.PrfBasedDeriverParams{
+			DerivedKeyTemplate: derivedKeyTemplate,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key format: %s", err)
+	}
+	if _, err := new(prfBasedDeriverKeyManager).NewKey(serializedFormat); err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          prfBasedDeriverTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: derivedKeyTemplate.GetOutputPrefixType(),
+	}, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/internal"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+var keysetHandle = internal.KeysetHandle.(func(*tinkpb.Keyset) *keyset.Handle)
+
+// keyDataDeriver is implemented by the keyset deriver primitives.
+type keyDataDeriver interface {
+	deriveKeyData(salt []byte) (*tinkpb.KeyData, error)
+}
+
+// New returns a KeysetDeriver primitive from the given keyset handle.
+//
+// The keysets derived by the primitive contain a derived key for each enabled
+// key of h, with the same key ID and output prefix type, and the same primary
+// key.
+func New(h *keyset.Handle) (KeysetDeriver, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("keyset_deriver_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedKeysetDeriver(ps, h.KeysetInfo())
+}
+
+// wrappedKeysetDeriver is a KeysetDeriver implementation that derives a key
+// with each primitive of the underlying primitive set.
+type wrappedKeysetDeriver struct {
+	primaryKeyID uint32
+	// entries are the entries of the primitive set, in keyset order.
+	entries []*primitiveset.Entry
+}
+
+// Asserts that wrappedKeysetDeriver implements the KeysetDeriver interface.
+var _ KeysetDeriver = (*wrappedKeysetDeriver)(nil)
+
+func newWrappedKeysetDeriver(ps *primitiveset.PrimitiveSet, info *tinkpb.KeysetInfo) (*wrappedKeysetDeriver, error) {
+	byKeyID := make(map[uint32]*primitiveset.Entry)
+	for _, entries := range ps.Entries {
+		for _, e := range entries {
+			if _, ok := (e.Primitive).(keyDataDeriver); !ok {
+				return nil, fmt.Errorf("keyset_deriver_factory: not a KeysetDeriver primitive")
+			}
+			byKeyID[e.KeyID] = e
+		}
+	}
+	ret := &wrappedKeysetDeriver{primaryKeyID: ps.Primary.KeyID}
+	for _, k := range info.GetKeyInfo() {
+		if e, ok := byKeyID[k.KeyId]; ok {
+			ret.entries = append(ret.entries, e)
+		}
+	}
+	return ret, nil
+}
+
+// DeriveKeyset derives the keyset for the given salt.
+func (w *wrappedKeysetDeriver) DeriveKeyset(salt []byte) (*keyset.Handle, error) {
+	ks := &tinkpb.Keyset{PrimaryKeyId: w.primaryKeyID}
+	for _, e := range w.entries {
+		keyData, err := e.Primitive.(keyDataDeriver).deriveKeyData(salt)
+		if err != nil {
+			return nil, fmt.Errorf("keyset_deriver_factory: cannot derive key %d: %s", e.KeyID, err)
+		}
+		ks.Key = append(ks.Key, &tinkpb.Keyset_Key{
+			KeyData:          keyData,
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            e.KeyID,
+			OutputPrefixType: e.PrefixType,
+		})
+	}
+	if err := keyset.Validate(ks); err != nil {
+		return nil, fmt.Errorf("keyset_deriver_factory: invalid derived keyset: %s", err)
+	}
+	return keysetHandle(ks), nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/daead"
+	"github.com/google/tink/go/keyderivation"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/prf"
+	"github.com/google/tink/go/testkeyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func newDeriverHandle(t *testing.T, derivedKeyTemplate *tinkpb.KeyTemplate) *keyset.Handle {
+	t.Helper()
+	template, err := keyderivation.CreatePRFBasedKeyTemplate(prf.HKDFSHA256PRFKeyTemplate(), derivedKeyTemplate)
+	if err != nil {
+		t.Fatalf("keyderivation.CreatePRFBasedKeyTemplate() err = %v", err)
+	}
+	h, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	return h
+}
+
+func deriveKeyset(t *testing.T, d keyderivation.KeysetDeriver, salt string) *tinkpb.Keyset {
+	t.Helper()
+	h, err := d.DeriveKeyset([]byte(salt))
+	if err != nil {
+		t.Fatalf("DeriveKeyset(%q) err = %v", salt, err)
+	}
+	return testkeyset.KeysetMaterial(h)
+}
+
+func TestDeriveKeyset(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"AES128GCM", aead.AES128GCMKeyTemplate()},
+		{"AES256GCM", aead.AES256GCMKeyTemplate()},
+		{"ChaCha20Poly1305", aead.ChaCha20Poly1305KeyTemplate()},
+		{"XChaCha20Poly1305", aead.XChaCha20Poly1305KeyTemplate()},
+		{"AESSIV", daead.AESSIVKeyTemplate()},
+		{"HMACSHA256Tag256", mac.HMACSHA256Tag256KeyTemplate()},
+		{"HMACSHA256PRF", prf.HMACSHA256PRFKeyTemplate()},
+		{"HKDFSHA256PRF", prf.HKDFSHA256PRFKeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := keyderivation.New(newDeriverHandle(t, tc.template))
+			if err != nil {
+				t.Fatalf("keyderivation.New() err = %v", err)
+			}
+			ks1 := deriveKeyset(t, d, "tenant-1")
+			if !proto.Equal(ks1, deriveKeyset(t, d, "tenant-1")) {
+				t.Error("deriving a keyset twice from the same salt returned different keysets")
+			}
+			ks2 := deriveKeyset(t, d, "tenant-2")
+			if bytes.Equal(ks1.Key[0].KeyData.Value, ks2.Key[0].KeyData.Value) {
+				t.Error("keysets derived from different salts have the same key")
+			}
+			if got, want := ks1.Key[0].KeyData.TypeUrl, tc.template.TypeUrl; got != want {
+				t.Errorf("derived key type = %q, want %q", got, want)
+			}
+			if got, want := ks1.Key[0].OutputPrefixType, tc.template.OutputPrefixType; got != want {
+				t.Errorf("derived output prefix type = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDeriveKeysetIsUsable(t *testing.T) {
+	h := newDeriverHandle(t, aead.AES256GCMKeyTemplate())
+	d, err := keyderivation.New(h)
+	if err != nil {
+		t.Fatalf("keyderivation.New() err = %v", err)
+	}
+	derived1, err := d.DeriveKeyset([]byte("file-1"))
+	if err != nil {
+		t.Fatalf("DeriveKeyset() err = %v", err)
+	}
+	derived2, err := d.DeriveKeyset([]byte("file-1"))
+	if err != nil {
+		t.Fatalf("DeriveKeyset() err = %v", err)
+	}
+	a1, err := aead.New(derived1)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v", err)
+	}
+	a2, err := aead.New(derived2)
+	if err != nil {
+		t.Fatalf("aead.New() err = %v", err)
+	}
+	ct, err := a1.Encrypt([]byte("plaintext"), []byte("aad"))
+	if err != nil {
+		t.Fatalf("Encrypt() err = %v", err)
+	}
+	pt, err := a2.Decrypt(ct, []byte("aad"))
+	if err != nil || string(pt) != "plaintext" {
+		t.Errorf("Decrypt() = %q, %v, want %q", pt, err, "plaintext")
+	}
+}
+
+func TestDeriveKeysetMultipleKeys(t *testing.T) {
+	template, err := keyderivation.CreatePRFBasedKeyTemplate(prf.HKDFSHA256PRFKeyTemplate(), aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyderivation.CreatePRFBasedKeyTemplate() err = %v", err)
+	}
+	manager := keyset.NewManager()
+	for i := 0; i < 3; i++ {
+		if err := manager.Rotate(template); err != nil {
+			t.Fatalf("manager.Rotate() err = %v", err)
+		}
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() err = %v", err)
+	}
+	d, err := keyderivation.New(h)
+	if err != nil {
+		t.Fatalf("keyderivation.New() err = %v", err)
+	}
+	ks := deriveKeyset(t, d, "salt")
+	info := h.KeysetInfo()
+	if ks.PrimaryKeyId != info.PrimaryKeyId {
+		t.Errorf("derived primary key ID = %d, want %d", ks.PrimaryKeyId, info.PrimaryKeyId)
+	}
+	if len(ks.Key) != len(info.KeyInfo) {
+		t.Fatalf("derived keyset has %d keys, want %d", len(ks.Key), len(info.KeyInfo))
+	}
+	for i, k := range ks.Key {
+		if k.KeyId != info.KeyInfo[i].KeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			t.Errorf("derived key %d has ID %d and status %v, want %d and ENABLED", i, k.KeyId, k.Status, info.KeyInfo[i].KeyId)
+		}
+		for _, other := range ks.Key[:i] {
+			if bytes.Equal(k.KeyData.Value, other.KeyData.Value) {
+				t.Errorf("derived keys %d and %d are equal", other.KeyId, k.KeyId)
+			}
+		}
+	}
+}
+
+func TestNewWithInvalidKeyset(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	if _, err := keyderivation.New(h); err == nil {
+		t.Error("keyderivation.New() with an AEAD keyset succeeded")
+	}
+}
+
+func TestCreatePRFBasedKeyTemplateWithInvalidTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name                         string
+		prfTemplate, derivedTemplate *tinkpb.KeyTemplate
+	}{
+		{"HMAC PRF key", prf.HMACSHA256PRFKeyTemplate(), aead.AES128GCMKeyTemplate()},
+		{"not derivable", prf.HKDFSHA256PRFKeyTemplate(), aead.AES128CTRHMACSHA256KeyTemplate()},
+		{"missing PRF key template", nil, aead.AES128GCMKeyTemplate()},
+		{"missing derived key template", prf.HKDFSHA256PRFKeyTemplate(), nil},
+	} {
+		if _, err := keyderivation.CreatePRFBasedKeyTemplate(tc.prfTemplate, tc.derivedTemplate); err == nil {
+			t.Errorf("%s: keyderivation.CreatePRFBasedKeyTemplate() succeeded", tc.name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	prfsubtle "github.com/google/tink/go/prf/subtle"
+	hkdfpb "github.com/google/tink/go/proto/hkdf_prf_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	hkdfPRFKeyVersion = 0
+	hkdfPRFTypeURL    = "type.googleapis.com/google.crypto.tink.HkdfPrfKey"
+)
+
+// prfBasedDeriver derives keys of a key template from the output of HKDF,
+// using the salt given for derivation as the HKDF info.
+type prfBasedDeriver struct {
+	prf                *prfsubtle.HKDFStreamingPRF
+	derivedKeyTemplate *tinkpb.KeyTemplate
+}
+
+// newPRFBasedDeriver returns a prfBasedDeriver that uses the HKDF PRF key in
+// prfKey to derive keys of derivedKeyTemplate.
+func newPRFBasedDeriver(prfKey *tinkpb.KeyData, derivedKeyTemplate *tinkpb.KeyTemplate) (*prfBasedDeriver, error) {
+	if prfKey.GetTypeUrl() != hkdfPRFTypeURL {
+		return nil, fmt.Errorf("unsupported PRF key type %q, only HKDF PRF keys are supported", prfKey.GetTypeUrl())
+	}
+	key := new(hkdfpb.HkdfPrfKey)
+	if err := proto.Unmarshal(prfKey.GetValue(), key); err != nil {
+		return nil, errors.New("invalid HKDF PRF key")
+	}
+	if err := keyset.ValidateKeyVersion(key.Version, hkdfPRFKeyVersion); err != nil {
+		return nil, err
+	}
+	prf, err := prfsubtle.NewHKDFStreamingPRF(key.GetParams().GetHash().String(), key.KeyValue, key.GetParams().GetSalt())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDerivedKeyTemplate(derivedKeyTemplate); err != nil {
+		return nil, err
+	}
+	return &prfBasedDeriver{
+		prf:                prf,
+		derivedKeyTemplate: derivedKeyTemplate,
+	}, nil
+}
+
+// validateDerivedKeyTemplate checks that keys of the given template can be
+// derived.
+func validateDerivedKeyTemplate(template *tinkpb.KeyTemplate) error {
+	if template == nil {
+		return errors.New("missing derived key template")
+	}
+	km, err := registry.GetKeyManager(template.TypeUrl)
+	if err != nil {
+		return err
+	}
+	if _, ok := km.(registry.DerivableKeyManager); !ok {
+		return fmt.Errorf("key type %q does not support key derivation", template.TypeUrl)
+	}
+	return nil
+}
+
+// deriveKeyData derives the key data for the given salt.
+func (d *prfBasedDeriver) deriveKeyData(salt []byte) (*tinkpb.KeyData, error) {
+	km, err := registry.GetKeyManager(d.derivedKeyTemplate.TypeUrl)
+	if err != nil {
+		return nil, err
+	}
+	dkm, ok := km.(registry.DerivableKeyManager)
+	if !ok {
+		return nil, fmt.Errorf("key type %q does not support key derivation", d.derivedKeyTemplate.TypeUrl)
+	}
+	key, err := dkm.DeriveKey(d.derivedKeyTemplate.Value, d.prf.ComputePRF(salt))
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         d.derivedKeyTemplate.TypeUrl,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
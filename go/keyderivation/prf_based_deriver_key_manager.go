@@ -0,0 +1,124 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	prfderpb "github.com/google/tink/go/proto/prf_based_deriver_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	prfBasedDeriverKeyVersion = 0
+	prfBasedDeriverTypeURL    = "type.googleapis.com/google.crypto.tink.PrfBasedDeriverKey"
+)
+
+var (
+	errInvalidPRFBasedDeriverKey       = errors.New("prf_based_deriver_key_manager: invalid key")
+	errInvalidPRFBasedDeriverKeyFormat = errors.New("prf_based_deriver_key_manager: invalid key format")
+)
+
+// prfBasedDeriverKeyManager is an implementation of KeyManager interface.
+// It generates new PrfBasedDeriverKey keys and produces new instances of
+// prfBasedDeriver.
+type prfBasedDeriverKeyManager struct{}
+
+// Assert that prfBasedDeriverKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*prfBasedDeriverKeyManager)(nil)
+
+// Primitive creates a prfBasedDeriver for the given serialized
+// PrfBasedDeriverKey proto.
+func (km *prfBasedDeriverKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidPRFBasedDeriverKey
+	}
+	key := new(prfderpb.PrfBasedDeriverKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidPRFBasedDeriverKey
+	}
+	if err := keyset.ValidateKeyVersion(key.Version, prfBasedDeriverKeyVersion); err != nil {
+		return nil, fmt.Errorf("prf_based_deriver_key_manager: %s", err)
+	}
+	d, err := newPRFBasedDeriver(key.GetPrfKey(), key.GetParams().GetDerivedKeyTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("prf_based_deriver_key_manager: cannot create new primitive: %s", err)
+	}
+	return d, nil
+}
+
+// NewKey creates a new key according to specification in the given serialized
+// PrfBasedDeriverKeyFormat.
+func (km *prfBasedDeriverKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidPRFBasedDeriverKeyFormat
+	}
+	keyFormat := new(prfderpb.PrfBasedDeriverKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidPRFBasedDeriverKeyFormat
+	}
+	if keyFormat.GetPrfKeyTemplate() == nil {
+		return nil, fmt.Errorf("prf_based_deriver_key_manager: invalid key format: missing PRF key template")
+	}
+	prfKey, err := registry.NewKeyData(keyFormat.GetPrfKeyTemplate())
+	if err != nil {
+		return nil, fmt.Errorf("prf_based_deriver_key_manager: cannot generate PRF key: %s", err)
+	}
+	// Creating the primitive validates the PRF key and the derived key
+	// template.
+	if _, err := newPRFBasedDeriver(prfKey, keyFormat.GetParams().GetDerivedKeyTemplate()); err != nil {
+		return nil, fmt.Errorf("prf_based_deriver_key_manager: invalid key format: %s", err)
+	}
+	return &prfderpb.PrfBasedDeriverKey{
+		Version: prfBasedDeriverKeyVersion,
+		PrfKey:  prfKey,
+		Params:  keyFormat.GetParams(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized PrfBasedDeriverKeyFormat.
+// It should be used solely by the key management API.
+func (km *prfBasedDeriverKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         prfBasedDeriverTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *prfBasedDeriverKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == prfBasedDeriverTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *prfBasedDeriverKeyManager) TypeURL() string {
+	return prfBasedDeriverTypeURL
+}
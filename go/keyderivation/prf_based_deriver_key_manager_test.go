@@ -0,0 +1,105 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyderivation_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/prf"
+	prfderpb "github.com/google/tink/go/proto/prf_based_deriver_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const prfBasedDeriverTypeURL = "type.googleapis.com/google.crypto.tink.PrfBasedDeriverKey"
+
+func TestPRFBasedDeriverKeyManagerPrimitiveWithInvalidKeys(t *testing.T) {
+	km, err := registry.GetKeyManager(prfBasedDeriverTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	hkdfKey, err := registry.NewKeyData(prf.HKDFSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("registry.NewKeyData() err = %v", err)
+	}
+	hmacKey, err := registry.NewKeyData(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("registry.NewKeyData() err = %v", err)
+	}
+	params := &prfderpb.PrfBasedDeriverParams{DerivedKeyTemplate: aead.AES128GCMKeyTemplate()}
+
+	valid, err := proto.Marshal(&prfderpb.PrfBasedDeriverKey{PrfKey: hkdfKey, Params: params})
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v", err)
+	}
+	if _, err := km.Primitive(valid); err != nil {
+		t.Errorf("km.Primitive() err = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		key  *prfderpb.PrfBasedDeriverKey
+	}{
+		{"bad version", &prfderpb.PrfBasedDeriverKey{Version: 1, PrfKey: hkdfKey, Params: params}},
+		{"missing PRF key", &prfderpb.PrfBasedDeriverKey{Params: params}},
+		{"unsupported PRF key", &prfderpb.PrfBasedDeriverKey{PrfKey: hmacKey, Params: params}},
+		{"missing derived key template", &prfderpb.PrfBasedDeriverKey{PrfKey: hkdfKey}},
+		{"derived key type not derivable", &prfderpb.PrfBasedDeriverKey{PrfKey: hkdfKey, Params: &prfderpb.PrfBasedDeriverParams{
+			DerivedKeyTemplate: aead.AES128CTRHMACSHA256KeyTemplate(),
+		}}},
+	} {
+		serializedKey, err := proto.Marshal(tc.key)
+		if err != nil {
+			t.Fatalf("proto.Marshal() err = %v", err)
+		}
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("%s: km.Primitive() succeeded", tc.name)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Error("km.Primitive(nil) succeeded")
+	}
+}
+
+func TestPRFBasedDeriverKeyManagerNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(prfBasedDeriverTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() err = %v", err)
+	}
+	serializedFormat, err := proto.Marshal(&prfderpb.PrfBasedDeriverKeyFormat{
+		PrfKeyTemplate: prf.HKDFSHA256PRFKeyTemplate(),
+		Params:         &prfderpb.PrfBasedDeriverParams{DerivedKeyTemplate: aead.AES128GCMKeyTemplate()},
+	})
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v", err)
+	}
+	keyData, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyData() err = %v", err)
+	}
+	if keyData.TypeUrl != prfBasedDeriverTypeURL || keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("km.NewKeyData() = %v, want type URL %q and SYMMETRIC key material", keyData, prfBasedDeriverTypeURL)
+	}
+	if _, err := km.Primitive(keyData.Value); err != nil {
+		t.Errorf("km.Primitive() err = %v", err)
+	}
+	if !km.DoesSupport(prfBasedDeriverTypeURL) || km.DoesSupport("some bad type") {
+		t.Errorf("km.DoesSupport() must support only %q", prfBasedDeriverTypeURL)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedAnnotationPrefix is the prefix of the annotation keys reserved for
+// the keyset package, such as those of key expiration times and the audit log.
+const reservedAnnotationPrefix = "tink."
+
+// AnnotationsReader is implemented by Readers which can also read the
+// annotations of a keyset handle. Annotations are stored alongside the keyset,
+// not inside it.
+type AnnotationsReader interface {
+	// ReadAnnotations returns the annotations from the underlying source.
+	ReadAnnotations() (map[string]string, error)
+}
+
+// AnnotationsWriter is implemented by Writers which can also write the
+// annotations of a keyset handle. Annotations are stored alongside the keyset,
+// not inside it.
+type AnnotationsWriter interface {
+	// WriteAnnotations writes annotations to some storage system.
+	WriteAnnotations(annotations map[string]string) error
+}
+
+// WithAnnotations returns a handle for the same keyset as h with the given
+// annotations attached, replacing any existing ones. Annotations are string
+// labels, such as the tenant or purpose a keyset serves. They are passed on
+// to primitive sets created from the handle, for monitoring.
+//
+// Annotations are carried by the handle next to the keyset, not inside it.
+// They are written and read only by writers and readers which implement
+// AnnotationsWriter and AnnotationsReader, such as MemReaderWriter; the
+// binary, JSON and CBOR formats hold the keyset alone. Annotations are
+// neither encrypted nor authenticated by the master key.
+//
+// Keys starting with "tink." are reserved and cannot be set. The annotations
+// the keyset package keeps under them, such as key expiration times and the
+// audit log, are retained.
+func (h *Handle) WithAnnotations(annotations map[string]string) (*Handle, error) {
+	c := make(map[string]string)
+	for k, v := range h.annotations {
+		if isReservedAnnotation(k) {
+			c[k] = v
+		}
+	}
+	for k, v := range annotations {
+		if isReservedAnnotation(k) {
+			return nil, fmt.Errorf("keyset.Handle: annotation key %q is reserved", k)
+		}
+		c[k] = v
+	}
+	return &Handle{ks: h.ks, annotations: c}, nil
+}
+
+// Annotations returns a copy of the annotations attached to h, without the
+// reserved ones.
+func (h *Handle) Annotations() map[string]string {
+	return userAnnotations(h.annotations)
+}
+
+func isReservedAnnotation(key string) bool {
+	return strings.HasPrefix(key, reservedAnnotationPrefix)
+}
+
+// userAnnotations returns a copy of the annotations which are not reserved, or
+// nil if there are none.
+func userAnnotations(annotations map[string]string) map[string]string {
+	var c map[string]string
+	for k, v := range annotations {
+		if isReservedAnnotation(k) {
+			continue
+		}
+		if c == nil {
+			c = make(map[string]string)
+		}
+		c[k] = v
+	}
+	return c
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	c := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		c[k] = v
+	}
+	return c
+}
+
+// withAnnotation returns a copy of annotations in which the annotation with
+// the given key is set to value. Annotations may be shared between handles,
+// so they are never modified in place.
+func withAnnotation(annotations map[string]string, key, value string) map[string]string {
+	c := copyAnnotations(annotations)
+	if c == nil {
+		c = make(map[string]string)
+	}
+	c[key] = value
+	return c
+}
+
+// withoutAnnotation returns a copy of annotations without the annotation with
+// the given key.
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	if _, ok := annotations[key]; !ok {
+		return annotations
+	}
+	c := copyAnnotations(annotations)
+	delete(c, key)
+	return c
+}
+
+// readAnnotations reads annotations if reader supports them.
+func readAnnotations(reader Reader) (map[string]string, error) {
+	r, ok := reader.(AnnotationsReader)
+	if !ok {
+		return nil, nil
+	}
+	annotations, err := r.ReadAnnotations()
+	if err != nil {
+		return nil, err
+	}
+	return copyAnnotations(annotations), nil
+}
+
+// writeAnnotations writes annotations if writer supports them.
+func writeAnnotations(writer Writer, annotations map[string]string) error {
+	w, ok := writer.(AnnotationsWriter)
+	if !ok {
+		return nil
+	}
+	return w.WriteAnnotations(copyAnnotations(annotations))
+}
@@ -0,0 +1,268 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+)
+
+func TestHandleAnnotations(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	if got := h.Annotations(); got != nil {
+		t.Errorf("h.Annotations() = %v, want nil", got)
+	}
+	annotations := map[string]string{"tenant": "a", "purpose": "tokens"}
+	annotated, err := h.WithAnnotations(annotations)
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+	if got := annotated.Annotations(); !reflect.DeepEqual(got, annotations) {
+		t.Errorf("annotated.Annotations() = %v, want %v", got, annotations)
+	}
+	if got := h.Annotations(); got != nil {
+		t.Errorf("h.Annotations() = %v after WithAnnotations, want nil", got)
+	}
+	if annotated.String() != h.String() {
+		t.Errorf("annotated handle has keyset %s, want %s", annotated, h)
+	}
+
+	// Changing the given or returned maps doesn't change the annotations.
+	annotations["tenant"] = "b"
+	annotated.Annotations()["tenant"] = "c"
+	if got := annotated.Annotations()["tenant"]; got != "a" {
+		t.Errorf("annotated.Annotations()[\"tenant\"] = %q, want \"a\"", got)
+	}
+
+	ps, err := annotated.Primitives()
+	if err != nil {
+		t.Fatalf("annotated.Primitives() failed: %v", err)
+	}
+	want := map[string]string{"tenant": "a", "purpose": "tokens"}
+	if !reflect.DeepEqual(ps.Annotations, want) {
+		t.Errorf("ps.Annotations = %v, want %v", ps.Annotations, want)
+	}
+
+	m := keyset.NewManagerFromHandle(annotated)
+	if err := m.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	rotated, err := m.Handle()
+	if err != nil {
+		t.Fatalf("m.Handle() failed: %v", err)
+	}
+	if got := rotated.Annotations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("rotated.Annotations() = %v, want %v", got, want)
+	}
+}
+
+func TestAnnotationsWrittenAlongsideKeyset(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	annotations := map[string]string{"tenant": "a", "purpose": "tokens"}
+	h, err = h.WithAnnotations(annotations)
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		write func(keyset.Writer) error
+		read  func(keyset.Reader) (*keyset.Handle, error)
+	}{
+		{
+			name:  "Write",
+			write: func(w keyset.Writer) error { return h.Write(w, masterKey) },
+			read:  func(r keyset.Reader) (*keyset.Handle, error) { return keyset.Read(r, masterKey) },
+		},
+		{
+			name:  "WriteEnvelope",
+			write: func(w keyset.Writer) error { return h.WriteEnvelope(w, masterKey, nil) },
+			read:  func(r keyset.Reader) (*keyset.Handle, error) { return keyset.ReadEnvelope(r, masterKey, nil) },
+		},
+		{
+			name:  "WriteWithPassword",
+			write: func(w keyset.Writer) error { return h.WriteWithPassword(w, []byte("password")) },
+			read:  func(r keyset.Reader) (*keyset.Handle, error) { return keyset.ReadWithPassword(r, []byte("password")) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			memKeyset := &keyset.MemReaderWriter{}
+			if err := tc.write(memKeyset); err != nil {
+				t.Fatalf("writing the keyset failed: %v", err)
+			}
+			if !reflect.DeepEqual(memKeyset.Annotations, annotations) {
+				t.Errorf("written annotations = %v, want %v", memKeyset.Annotations, annotations)
+			}
+			read, err := tc.read(memKeyset)
+			if err != nil {
+				t.Fatalf("reading the keyset failed: %v", err)
+			}
+			if got := read.Annotations(); !reflect.DeepEqual(got, annotations) {
+				t.Errorf("read.Annotations() = %v, want %v", got, annotations)
+			}
+
+			// Writers without support for annotations only write the keyset.
+			buf := new(bytes.Buffer)
+			if err := tc.write(keyset.NewJSONWriter(buf)); err != nil {
+				t.Fatalf("writing the keyset failed: %v", err)
+			}
+			if bytes.Contains(buf.Bytes(), []byte("tenant")) {
+				t.Errorf("annotations were written inside the keyset: %s", buf)
+			}
+			read, err = tc.read(keyset.NewJSONReader(buf))
+			if err != nil {
+				t.Fatalf("reading the keyset failed: %v", err)
+			}
+			if got := read.Annotations(); got != nil {
+				t.Errorf("read.Annotations() = %v, want nil", got)
+			}
+		})
+	}
+}
+
+func TestAnnotationsOfPublicKeyset(t *testing.T) {
+	h, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	annotations := map[string]string{"tenant": "a"}
+	h, err = h.WithAnnotations(annotations)
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+	pub, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() failed: %v", err)
+	}
+	if got := pub.Annotations(); !reflect.DeepEqual(got, annotations) {
+		t.Errorf("pub.Annotations() = %v, want %v", got, annotations)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := pub.WriteWithNoSecrets(memKeyset); err != nil {
+		t.Fatalf("pub.WriteWithNoSecrets() failed: %v", err)
+	}
+	read, err := keyset.ReadWithNoSecrets(memKeyset)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithNoSecrets() failed: %v", err)
+	}
+	if got := read.Annotations(); !reflect.DeepEqual(got, annotations) {
+		t.Errorf("read.Annotations() = %v, want %v", got, annotations)
+	}
+}
+
+func TestPublicKeysetDropsReservedAnnotations(t *testing.T) {
+	m := keyset.NewManager(keyset.WithAuditLog("alice"))
+	if err := m.Rotate(signature.ECDSAP256KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	keyID := h.KeysetInfo().GetPrimaryKeyId()
+	h, err := h.WithKeyExpiration(keyID, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	annotations := map[string]string{"tenant": "a"}
+	h, err = h.WithAnnotations(annotations)
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+
+	pub, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() failed: %v", err)
+	}
+	if _, ok, err := pub.KeyExpiration(keyID); ok || err != nil {
+		t.Errorf("pub.KeyExpiration() = _, %v, %v, want false, nil", ok, err)
+	}
+	if events, err := pub.AuditLog(); err != nil || len(events) != 0 {
+		t.Errorf("pub.AuditLog() = %+v, %v, want no events", events, err)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := pub.WriteWithNoSecrets(memKeyset); err != nil {
+		t.Fatalf("pub.WriteWithNoSecrets() failed: %v", err)
+	}
+	if !reflect.DeepEqual(memKeyset.Annotations, annotations) {
+		t.Errorf("written annotations = %v, want %v", memKeyset.Annotations, annotations)
+	}
+
+	// The private handle is unchanged.
+	if _, ok, _ := h.KeyExpiration(keyID); !ok {
+		t.Error("h.KeyExpiration() = _, false, _ after h.Public(), want true")
+	}
+	if events, _ := h.AuditLog(); len(events) != 2 {
+		t.Errorf("h.AuditLog() returned %d events after h.Public(), want 2", len(events))
+	}
+}
+
+func TestReservedAnnotations(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	for _, key := range []string{"tink.key_expiration.1", "tink.audit_log", "tink.other"} {
+		if _, err := h.WithAnnotations(map[string]string{key: "value"}); err == nil {
+			t.Errorf("h.WithAnnotations() with reserved key %q succeeded, want error", key)
+		}
+	}
+
+	keyID := h.KeysetInfo().GetPrimaryKeyId()
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	h, err = h.WithKeyExpiration(keyID, expiration)
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	// Reserved annotations are neither returned nor replaced.
+	if got := h.Annotations(); got != nil {
+		t.Errorf("h.Annotations() = %v, want nil", got)
+	}
+	annotations := map[string]string{"tenant": "a"}
+	h, err = h.WithAnnotations(annotations)
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+	if got := h.Annotations(); !reflect.DeepEqual(got, annotations) {
+		t.Errorf("h.Annotations() = %v, want %v", got, annotations)
+	}
+	if got, ok, err := h.KeyExpiration(keyID); err != nil || !ok || !got.Equal(expiration) {
+		t.Errorf("h.KeyExpiration() = %v, %v, %v after WithAnnotations, want %v, true, nil", got, ok, err, expiration)
+	}
+	ps, err := h.Primitives()
+	if err != nil {
+		t.Fatalf("h.Primitives() failed: %v", err)
+	}
+	if !reflect.DeepEqual(ps.Annotations, annotations) {
+		t.Errorf("ps.Annotations = %v, want %v", ps.Annotations, annotations)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// auditLogAnnotation is the annotation which holds the audit log of a keyset,
+// a JSON array of KeyEvents.
+const auditLogAnnotation = reservedAnnotationPrefix + "audit_log"
+
+// maxAuditLogEvents is the number of events kept in the audit log of a
+// keyset. Older events are dropped, so that the log doesn't grow without bound.
+const maxAuditLogEvents = 1000
+
+// KeyEventType is the type of a key lifecycle event.
+type KeyEventType string
+
+// Key lifecycle events recorded by Managers with an audit log.
+const (
+	KeyCreated   KeyEventType = "created"
+	KeyPromoted  KeyEventType = "promoted"
+	KeyEnabled   KeyEventType = "enabled"
+	KeyDisabled  KeyEventType = "disabled"
+	KeyDestroyed KeyEventType = "destroyed"
+	KeyDeleted   KeyEventType = "deleted"
+	KeyUpgraded  KeyEventType = "upgraded"
+)
+
+// KeyEvent is an entry of the audit log of a keyset.
+type KeyEvent struct {
+	Time  time.Time    `json:"time"`
+	KeyID uint32       `json:"keyId"`
+	Type  KeyEventType `json:"type"`
+	// Actor is who made the change, as given to WithAuditLog.
+	Actor string `json:"actor,omitempty"`
+}
+
+// ManagerOption is an option for creating a Manager.
+type ManagerOption func(*Manager)
+
+// WithAuditLog makes the Manager record the lifecycle events of keys, that
+// is their creation, promotion to primary, enabling, disabling, destruction,
+// deletion and upgrades, attributed to actor. Events are appended to the audit
+// log in the annotations of the keyset, so that they are persisted by writers
+// which implement AnnotationsWriter, and can be retrieved with Handle.AuditLog
+// or Inspect as compliance evidence. Only the latest 1000 events are kept.
+//
+// Like all annotations, the audit log is stored next to the keyset in
+// cleartext, and is neither encrypted nor authenticated by the master key.
+// Anyone who can write to the storage can alter or remove it, so it records,
+// but does not prove, what happened.
+func WithAuditLog(actor string) ManagerOption {
+	return func(km *Manager) {
+		km.auditLog = true
+		km.actor = actor
+	}
+}
+
+// AuditLog returns the audit log of h, oldest event first. It is empty if the
+// keyset was never managed by a Manager with WithAuditLog.
+func (h *Handle) AuditLog() ([]KeyEvent, error) {
+	return parseAuditLog(h.annotations)
+}
+
+func parseAuditLog(annotations map[string]string) ([]KeyEvent, error) {
+	v, ok := annotations[auditLogAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var events []KeyEvent
+	if err := json.Unmarshal([]byte(v), &events); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid audit log: %v", err)
+	}
+	return events, nil
+}
+
+// initAuditLog loads the existing audit log of km, if km keeps one. If it is
+// malformed, km refuses to make changes, rather than overwrite it.
+func (km *Manager) initAuditLog() {
+	if !km.auditLog {
+		return
+	}
+	events, err := parseAuditLog(km.annotations)
+	if err != nil {
+		km.auditLogErr = fmt.Errorf("keyset_manager: %v", err)
+		return
+	}
+	km.events = events
+}
+
+// record appends an event for the key with the given ID to the audit log, if
+// km keeps one.
+func (km *Manager) record(keyID uint32, t KeyEventType) {
+	if !km.auditLog {
+		return
+	}
+	km.events = append(km.events, KeyEvent{
+		Time:  time.Now().UTC(),
+		KeyID: keyID,
+		Type:  t,
+		Actor: km.actor,
+	})
+	if n := len(km.events) - maxAuditLogEvents; n > 0 {
+		km.events = append([]KeyEvent{}, km.events[n:]...)
+	}
+	// KeyEvents always encode.
+	b, _ := json.Marshal(km.events)
+	km.annotations = withAnnotation(km.annotations, auditLogAnnotation, string(b))
+}
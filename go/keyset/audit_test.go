@@ -0,0 +1,218 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+)
+
+func TestManagerAuditLog(t *testing.T) {
+	start := time.Now()
+	m := keyset.NewManager(keyset.WithAuditLog("alice"))
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := m.Rotate(kt); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	oldID, err := m.Add(kt)
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	firstID := h.KeysetInfo().PrimaryKeyId
+
+	// Continue with another manager, as when a keyset is loaded again.
+	m = keyset.NewManagerFromHandle(h, keyset.WithAuditLog("bob"))
+	for _, op := range []func(uint32) error{m.Disable, m.Enable, m.SetPrimary} {
+		if err := op(oldID); err != nil {
+			t.Fatalf("changing key %d failed: %v", oldID, err)
+		}
+	}
+	for _, op := range []func(uint32) error{m.Disable, m.Destroy, m.Delete} {
+		if err := op(firstID); err != nil {
+			t.Fatalf("changing key %d failed: %v", firstID, err)
+		}
+	}
+	h2, _ := m.Handle()
+
+	want := []keyset.KeyEvent{
+		{KeyID: firstID, Type: keyset.KeyCreated, Actor: "alice"},
+		{KeyID: firstID, Type: keyset.KeyPromoted, Actor: "alice"},
+		{KeyID: oldID, Type: keyset.KeyCreated, Actor: "alice"},
+		{KeyID: oldID, Type: keyset.KeyDisabled, Actor: "bob"},
+		{KeyID: oldID, Type: keyset.KeyEnabled, Actor: "bob"},
+		{KeyID: oldID, Type: keyset.KeyPromoted, Actor: "bob"},
+		{KeyID: firstID, Type: keyset.KeyDisabled, Actor: "bob"},
+		{KeyID: firstID, Type: keyset.KeyDestroyed, Actor: "bob"},
+		{KeyID: firstID, Type: keyset.KeyDeleted, Actor: "bob"},
+	}
+	got, err := h2.AuditLog()
+	if err != nil {
+		t.Fatalf("h2.AuditLog() failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("h2.AuditLog() returned %d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.KeyID != w.KeyID || g.Type != w.Type || g.Actor != w.Actor {
+			t.Errorf("event %d = %+v, want %+v", i, g, w)
+		}
+		if g.Time.Before(start.Add(-time.Second)) || g.Time.After(time.Now()) {
+			t.Errorf("event %d has time %v, want a time since the test started", i, g.Time)
+		}
+	}
+
+	// The log of the first handle is unchanged.
+	if events, _ := h.AuditLog(); len(events) != 3 {
+		t.Errorf("h.AuditLog() returned %d events, want 3", len(events))
+	}
+	if r := keyset.Inspect(h2); len(r.AuditLog) != len(want) {
+		t.Errorf("keyset.Inspect() returned %d events, want %d", len(r.AuditLog), len(want))
+	}
+}
+
+func TestManagerAuditLogIsPersisted(t *testing.T) {
+	m := keyset.NewManager(keyset.WithAuditLog("alice"))
+	if err := m.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	// Replacing the annotations keeps the audit log.
+	h, err := h.WithAnnotations(map[string]string{"tenant": "a"})
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.Write(memKeyset, masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	h2, err := keyset.Read(memKeyset, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() failed: %v", err)
+	}
+	if events, err := h2.AuditLog(); err != nil || len(events) != 2 {
+		t.Errorf("h2.AuditLog() = %+v, %v, want 2 events", events, err)
+	}
+
+	// A manager for the read keyset appends to the persisted log.
+	m = keyset.NewManagerFromHandle(h2, keyset.WithAuditLog("bob"))
+	if _, err := m.Add(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	h3, _ := m.Handle()
+	events, err := h3.AuditLog()
+	if err != nil || len(events) != 3 {
+		t.Fatalf("h3.AuditLog() = %+v, %v, want 3 events", events, err)
+	}
+	if events[0].Actor != "alice" || events[2].Actor != "bob" {
+		t.Errorf("h3.AuditLog() = %+v, want events by alice, then bob", events)
+	}
+}
+
+func TestManagerAuditLogIsCapped(t *testing.T) {
+	const maxEvents = 1000
+	m := keyset.NewManager(keyset.WithAuditLog("alice"))
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := m.Rotate(kt); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	keyID, err := m.Add(kt)
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	// 3 events so far, and 2 more per iteration.
+	for i := 0; i < maxEvents/2; i++ {
+		if err := m.Disable(keyID); err != nil {
+			t.Fatalf("m.Disable() failed: %v", err)
+		}
+		if err := m.Enable(keyID); err != nil {
+			t.Fatalf("m.Enable() failed: %v", err)
+		}
+	}
+	h, _ := m.Handle()
+	events, err := h.AuditLog()
+	if err != nil {
+		t.Fatalf("h.AuditLog() failed: %v", err)
+	}
+	if len(events) != maxEvents {
+		t.Fatalf("h.AuditLog() returned %d events, want %d", len(events), maxEvents)
+	}
+	// The 3 oldest events, which created both keys, are dropped.
+	if events[0].Type != keyset.KeyDisabled || events[0].KeyID != keyID {
+		t.Errorf("events[0] = %+v, want the disabling of key %d", events[0], keyID)
+	}
+	if last := events[len(events)-1]; last.Type != keyset.KeyEnabled || last.KeyID != keyID {
+		t.Errorf("last event = %+v, want the enabling of key %d", last, keyID)
+	}
+
+	// Managers for a keyset with a full log keep it at the cap.
+	m = keyset.NewManagerFromHandle(h, keyset.WithAuditLog("bob"))
+	if err := m.Disable(keyID); err != nil {
+		t.Fatalf("m.Disable() failed: %v", err)
+	}
+	h, _ = m.Handle()
+	events, err = h.AuditLog()
+	if err != nil || len(events) != maxEvents {
+		t.Fatalf("h.AuditLog() = %d events, %v, want %d events", len(events), err, maxEvents)
+	}
+	if last := events[len(events)-1]; last.Type != keyset.KeyDisabled || last.Actor != "bob" {
+		t.Errorf("last event = %+v, want a disabling by bob", last)
+	}
+}
+
+func TestManagerWithoutAuditLog(t *testing.T) {
+	m := keyset.NewManager()
+	if err := m.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	if events, err := h.AuditLog(); err != nil || len(events) != 0 {
+		t.Errorf("h.AuditLog() = %+v, %v, want no events", events, err)
+	}
+	if len(h.Annotations()) != 0 {
+		t.Errorf("h.Annotations() = %v, want none", h.Annotations())
+	}
+}
+
+func TestManagerWithMalformedAuditLog(t *testing.T) {
+	m := keyset.NewManager()
+	if err := m.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	h = withReservedAnnotation(t, h, "tink.audit_log", "not JSON")
+	if _, err := h.AuditLog(); err == nil {
+		t.Error("h.AuditLog() succeeded, want error")
+	}
+	m = keyset.NewManagerFromHandle(h, keyset.WithAuditLog("alice"))
+	if _, err := m.Add(mac.HMACSHA256Tag128KeyTemplate()); err == nil {
+		t.Error("m.Add() with a malformed audit log succeeded, want error")
+	}
+	if err := m.Disable(h.KeysetInfo().PrimaryKeyId); err == nil {
+		t.Error("m.Disable() with a malformed audit log succeeded, want error")
+	}
+}
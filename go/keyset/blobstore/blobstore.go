@@ -0,0 +1,255 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package blobstore provides a keyset.Store backed by an object store such as
+// GCS or S3.
+//
+// Every version of a keyset is stored as a separate immutable object named
+// "<prefix><name>/<version>", where the version is zero-padded so that object
+// names sort by version. Updates rely on the object store to create an object
+// only if it doesn't exist yet, which GCS provides with the DoesNotExist
+// precondition and S3 with the If-None-Match: * header.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/tink/go/keyset"
+)
+
+// ErrObjectExists is returned by Bucket.Create if the object already exists.
+var ErrObjectExists = errors.New("blobstore: object exists")
+
+// ErrObjectNotExist is returned by Bucket.Read if the object doesn't exist.
+var ErrObjectNotExist = errors.New("blobstore: object doesn't exist")
+
+// Bucket is the part of an object store client that Store uses. Applications
+// implement it with the client of their object store.
+type Bucket interface {
+	// Read returns the content of the object, or an error wrapping
+	// ErrObjectNotExist.
+	Read(ctx context.Context, object string) ([]byte, error)
+
+	// Create atomically creates the object with the given content if it
+	// doesn't exist, and otherwise returns an error wrapping ErrObjectExists.
+	Create(ctx context.Context, object string, data []byte) error
+
+	// List returns the names of the objects which start with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Store is a keyset.Store backed by a Bucket.
+type Store struct {
+	bucket Bucket
+	prefix string
+}
+
+var _ keyset.Store = (*Store)(nil)
+
+// New returns a Store which keeps keysets in bucket, with object names
+// starting with prefix.
+func New(bucket Bucket, prefix string) (*Store, error) {
+	if bucket == nil {
+		return nil, errors.New("blobstore: bucket must not be nil")
+	}
+	return &Store{bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *Store) object(name string, version int64) string {
+	return fmt.Sprintf("%s%s/%020d", s.prefix, name, version)
+}
+
+// Get returns the latest version of the keyset.
+func (s *Store) Get(ctx context.Context, name string) (*keyset.StoredKeyset, error) {
+	versions, err := s.ListVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %q", keyset.ErrKeysetNotFound, name)
+	}
+	return s.GetVersion(ctx, name, versions[len(versions)-1])
+}
+
+// GetVersion returns the given version of the keyset.
+func (s *Store) GetVersion(ctx context.Context, name string, version int64) (*keyset.StoredKeyset, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	data, err := s.bucket.Read(ctx, s.object(name, version))
+	if errors.Is(err, ErrObjectNotExist) {
+		return nil, fmt.Errorf("%w: %q version %d", keyset.ErrKeysetNotFound, name, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: cannot read keyset %q: %v", name, err)
+	}
+	return &keyset.StoredKeyset{Version: version, Data: data}, nil
+}
+
+// ListVersions returns the versions of the keyset in increasing order.
+func (s *Store) ListVersions(ctx context.Context, name string) ([]int64, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	prefix := s.prefix + name + "/"
+	objects, err := s.bucket.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: cannot list versions of keyset %q: %v", name, err)
+	}
+	var versions []int64
+	for _, object := range objects {
+		v, err := strconv.ParseInt(strings.TrimPrefix(object, prefix), 10, 64)
+		if err != nil || v < 1 {
+			// Not a version of this keyset, e.g. an object of a keyset whose
+			// name starts with name + "/".
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
+
+// Update stores data as a new version of the keyset if its latest version is
+// expectedVersion.
+func (s *Store) Update(ctx context.Context, name string, data []byte, expectedVersion int64) (int64, error) {
+	versions, err := s.ListVersions(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	if len(versions) > 0 {
+		latest = versions[len(versions)-1]
+	}
+	if latest != expectedVersion {
+		return 0, fmt.Errorf("%w: %q is at version %d, not %d", keyset.ErrVersionConflict, name, latest, expectedVersion)
+	}
+	version := expectedVersion + 1
+	err = s.bucket.Create(ctx, s.object(name, version), data)
+	if errors.Is(err, ErrObjectExists) {
+		return 0, fmt.Errorf("%w: %q was updated concurrently", keyset.ErrVersionConflict, name)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("blobstore: cannot update keyset %q: %v", name, err)
+	}
+	return version, nil
+}
+
+func validateName(name string) error {
+	if name == "" || strings.HasSuffix(name, "/") {
+		return fmt.Errorf("blobstore: invalid keyset name %q", name)
+	}
+	return nil
+}
+
+// DirBucket is a Bucket which stores objects as files in a directory, with
+// slashes in object names mapped to subdirectories.
+type DirBucket struct {
+	dir string
+}
+
+var _ Bucket = (*DirBucket)(nil)
+
+// NewDirBucket returns a Bucket which stores objects in dir.
+func NewDirBucket(dir string) *DirBucket {
+	return &DirBucket{dir: dir}
+}
+
+func (b *DirBucket) path(object string) (string, error) {
+	p := filepath.Join(b.dir, filepath.FromSlash(object))
+	if !strings.HasPrefix(p, filepath.Clean(b.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("blobstore: invalid object name %q", object)
+	}
+	return p, nil
+}
+
+// Read returns the content of the object.
+func (b *DirBucket) Read(ctx context.Context, object string) ([]byte, error) {
+	p, err := b.path(object)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %q", ErrObjectNotExist, object)
+	}
+	return data, err
+}
+
+// Create creates the object if it doesn't exist. The content is written to a
+// temporary file first and then linked to the object's name, so that readers
+// never see a partial object.
+func (b *DirBucket) Create(ctx context.Context, object string, data []byte) error {
+	p, err := b.path(object)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Link(tmp.Name(), p); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("%w: %q", ErrObjectExists, object)
+		}
+		return err
+	}
+	return nil
+}
+
+// List returns the names of the objects which start with prefix.
+func (b *DirBucket) List(ctx context.Context, prefix string) ([]string, error) {
+	var objects []string
+	err := filepath.Walk(b.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		rel, err := filepath.Rel(b.dir, p)
+		if err != nil {
+			return err
+		}
+		if object := filepath.ToSlash(rel); strings.HasPrefix(object, prefix) {
+			objects = append(objects, object)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return objects, err
+}
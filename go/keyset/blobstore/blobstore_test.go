@@ -0,0 +1,132 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package blobstore_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/keyset/blobstore"
+)
+
+func newTestBucket(t *testing.T) *blobstore.DirBucket {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "blobstore")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return blobstore.NewDirBucket(dir)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s, err := blobstore.New(newTestBucket(t), "keysets/")
+	if err != nil {
+		t.Fatalf("blobstore.New() failed: %v", err)
+	}
+
+	if _, err := s.Get(ctx, "tenant-1"); !errors.Is(err, keyset.ErrKeysetNotFound) {
+		t.Errorf("s.Get() of a missing keyset err = %v, want ErrKeysetNotFound", err)
+	}
+	v1, err := s.Update(ctx, "tenant-1", []byte("first"), 0)
+	if err != nil || v1 != 1 {
+		t.Fatalf("s.Update() = %d, %v, want 1, nil", v1, err)
+	}
+	if _, err := s.Update(ctx, "tenant-1", []byte("conflicting"), 0); !errors.Is(err, keyset.ErrVersionConflict) {
+		t.Errorf("s.Update() with a stale version err = %v, want ErrVersionConflict", err)
+	}
+	v2, err := s.Update(ctx, "tenant-1", []byte("second"), v1)
+	if err != nil || v2 != 2 {
+		t.Fatalf("s.Update() = %d, %v, want 2, nil", v2, err)
+	}
+	// A keyset whose name extends another one's doesn't add versions to it.
+	if _, err := s.Update(ctx, "tenant-1/sub", []byte("other"), 0); err != nil {
+		t.Fatalf("s.Update() failed: %v", err)
+	}
+
+	latest, err := s.Get(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("s.Get() failed: %v", err)
+	}
+	if latest.Version != 2 || string(latest.Data) != "second" {
+		t.Errorf("s.Get() = %d, %q, want 2, %q", latest.Version, latest.Data, "second")
+	}
+	first, err := s.GetVersion(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("s.GetVersion() failed: %v", err)
+	}
+	if string(first.Data) != "first" {
+		t.Errorf("s.GetVersion(1).Data = %q, want %q", first.Data, "first")
+	}
+	if _, err := s.GetVersion(ctx, "tenant-1", 3); !errors.Is(err, keyset.ErrKeysetNotFound) {
+		t.Errorf("s.GetVersion(3) err = %v, want ErrKeysetNotFound", err)
+	}
+	versions, err := s.ListVersions(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("s.ListVersions() failed: %v", err)
+	}
+	if want := []int64{1, 2}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("s.ListVersions() = %v, want %v", versions, want)
+	}
+}
+
+// racingBucket creates every object just before Store does, as a concurrent
+// writer would.
+type racingBucket struct {
+	*blobstore.DirBucket
+}
+
+func (b racingBucket) Create(ctx context.Context, object string, data []byte) error {
+	if err := b.DirBucket.Create(ctx, object, []byte("concurrent")); err != nil {
+		return err
+	}
+	return b.DirBucket.Create(ctx, object, data)
+}
+
+func TestStoreConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	s, err := blobstore.New(racingBucket{newTestBucket(t)}, "")
+	if err != nil {
+		t.Fatalf("blobstore.New() failed: %v", err)
+	}
+	if _, err := s.Update(ctx, "tenant-1", []byte("first"), 0); !errors.Is(err, keyset.ErrVersionConflict) {
+		t.Errorf("s.Update() err = %v, want ErrVersionConflict", err)
+	}
+	latest, err := s.Get(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("s.Get() failed: %v", err)
+	}
+	if string(latest.Data) != "concurrent" {
+		t.Errorf("s.Get().Data = %q, want %q", latest.Data, "concurrent")
+	}
+}
+
+func TestDirBucketInvalidObject(t *testing.T) {
+	b := newTestBucket(t)
+	if err := b.Create(context.Background(), "../escape", []byte("data")); err == nil {
+		t.Error("b.Create() of an object outside the directory succeeded, want error")
+	}
+	if _, err := b.Read(context.Background(), "missing"); !errors.Is(err, blobstore.ErrObjectNotExist) {
+		t.Errorf("b.Read() of a missing object err = %v, want ErrObjectNotExist", err)
+	}
+}
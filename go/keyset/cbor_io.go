@@ -0,0 +1,479 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// The CBOR (RFC 8949) format of keysets mirrors the JSON format: messages are
+// maps with the JSON field names as text keys, key material and ciphertexts
+// are byte strings, and key IDs and enums are unsigned integers. The writers
+// produce deterministically encoded CBOR, so equal keysets encode to equal
+// bytes.
+
+const (
+	cborUint  = 0
+	cborBytes = 2
+	cborText  = 3
+	cborArray = 4
+	cborMap   = 5
+
+	// cborMaxDepth bounds the nesting of decoded items. Keysets are nested
+	// three levels deep.
+	cborMaxDepth = 8
+)
+
+// CBORReader deserializes a keyset from CBOR format.
+type CBORReader struct {
+	r io.Reader
+}
+
+// NewCBORReader returns a new CBORReader that will read from r.
+func NewCBORReader(r io.Reader) *CBORReader {
+	return &CBORReader{r: r}
+}
+
+// Read parses a (cleartext) keyset from the underlying io.Reader.
+func (c *CBORReader) Read() (*tinkpb.Keyset, error) {
+	m, err := c.readMap()
+	if err != nil {
+		return nil, err
+	}
+	ks := &tinkpb.Keyset{}
+	if ks.PrimaryKeyId, err = m.keyID("primaryKeyId"); err != nil {
+		return nil, err
+	}
+	keys, err := m.array("key")
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		km, ok := k.(cborValues)
+		if !ok {
+			return nil, errors.New("keyset.CBORReader: key is not a map")
+		}
+		key, err := cborKeysetKey(km)
+		if err != nil {
+			return nil, err
+		}
+		ks.Key = append(ks.Key, key)
+	}
+	return ks, nil
+}
+
+// ReadEncrypted parses an EncryptedKeyset from the underlying io.Reader.
+func (c *CBORReader) ReadEncrypted() (*tinkpb.EncryptedKeyset, error) {
+	m, err := c.readMap()
+	if err != nil {
+		return nil, err
+	}
+	ks := &tinkpb.EncryptedKeyset{}
+	if ks.EncryptedKeyset, err = m.bytes("encryptedKeyset"); err != nil {
+		return nil, err
+	}
+	if info, ok := m["keysetInfo"]; ok {
+		im, ok := info.(cborValues)
+		if !ok {
+			return nil, errors.New("keyset.CBORReader: keysetInfo is not a map")
+		}
+		if ks.KeysetInfo, err = cborKeysetInfo(im); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func (c *CBORReader) readMap() (cborValues, error) {
+	data, err := ioutil.ReadAll(c.r)
+	if err != nil {
+		return nil, err
+	}
+	d := &cborDecoder{data: data}
+	v, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, errors.New("keyset.CBORReader: trailing data")
+	}
+	m, ok := v.(cborValues)
+	if !ok {
+		return nil, errors.New("keyset.CBORReader: keyset is not a map")
+	}
+	return m, nil
+}
+
+func cborKeysetKey(m cborValues) (*tinkpb.Keyset_Key, error) {
+	key := &tinkpb.Keyset_Key{}
+	var err error
+	if key.KeyId, err = m.keyID("keyId"); err != nil {
+		return nil, err
+	}
+	status, err := m.enum("status")
+	if err != nil {
+		return nil, err
+	}
+	key.Status = tinkpb.KeyStatusType(status)
+	prefixType, err := m.enum("outputPrefixType")
+	if err != nil {
+		return nil, err
+	}
+	key.OutputPrefixType = tinkpb.OutputPrefixType(prefixType)
+	if kd, ok := m["keyData"]; ok {
+		kdm, ok := kd.(cborValues)
+		if !ok {
+			return nil, errors.New("keyset.CBORReader: keyData is not a map")
+		}
+		key.KeyData = &tinkpb.KeyData{}
+		if key.KeyData.TypeUrl, err = kdm.text("typeUrl"); err != nil {
+			return nil, err
+		}
+		if key.KeyData.Value, err = kdm.bytes("value"); err != nil {
+			return nil, err
+		}
+		materialType, err := kdm.enum("keyMaterialType")
+		if err != nil {
+			return nil, err
+		}
+		key.KeyData.KeyMaterialType = tinkpb.KeyData_KeyMaterialType(materialType)
+	}
+	return key, nil
+}
+
+func cborKeysetInfo(m cborValues) (*tinkpb.KeysetInfo, error) {
+	info := &tinkpb.KeysetInfo{}
+	var err error
+	if info.PrimaryKeyId, err = m.keyID("primaryKeyId"); err != nil {
+		return nil, err
+	}
+	keyInfos, err := m.array("keyInfo")
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keyInfos {
+		km, ok := k.(cborValues)
+		if !ok {
+			return nil, errors.New("keyset.CBORReader: keyInfo is not a map")
+		}
+		keyInfo := &tinkpb.KeysetInfo_KeyInfo{}
+		if keyInfo.TypeUrl, err = km.text("typeUrl"); err != nil {
+			return nil, err
+		}
+		if keyInfo.KeyId, err = km.keyID("keyId"); err != nil {
+			return nil, err
+		}
+		status, err := km.enum("status")
+		if err != nil {
+			return nil, err
+		}
+		keyInfo.Status = tinkpb.KeyStatusType(status)
+		prefixType, err := km.enum("outputPrefixType")
+		if err != nil {
+			return nil, err
+		}
+		keyInfo.OutputPrefixType = tinkpb.OutputPrefixType(prefixType)
+		info.KeyInfo = append(info.KeyInfo, keyInfo)
+	}
+	return info, nil
+}
+
+// CBORWriter serializes a keyset into CBOR format.
+type CBORWriter struct {
+	w io.Writer
+}
+
+// NewCBORWriter returns a new CBORWriter that will write to w.
+func NewCBORWriter(w io.Writer) *CBORWriter {
+	return &CBORWriter{w: w}
+}
+
+// Write writes the keyset to the underlying io.Writer.
+func (c *CBORWriter) Write(ks *tinkpb.Keyset) error {
+	keys := make([]interface{}, 0, len(ks.GetKey()))
+	for _, key := range ks.GetKey() {
+		km := cborValues{
+			"keyId":            uint64(key.GetKeyId()),
+			"status":           uint64(key.GetStatus()),
+			"outputPrefixType": uint64(key.GetOutputPrefixType()),
+		}
+		if kd := key.GetKeyData(); kd != nil {
+			km["keyData"] = cborValues{
+				"typeUrl":         kd.GetTypeUrl(),
+				"value":           kd.GetValue(),
+				"keyMaterialType": uint64(kd.GetKeyMaterialType()),
+			}
+		}
+		keys = append(keys, km)
+	}
+	return c.write(cborValues{
+		"primaryKeyId": uint64(ks.GetPrimaryKeyId()),
+		"key":          keys,
+	})
+}
+
+// WriteEncrypted writes the encrypted keyset to the underlying io.Writer.
+func (c *CBORWriter) WriteEncrypted(ks *tinkpb.EncryptedKeyset) error {
+	m := cborValues{"encryptedKeyset": ks.GetEncryptedKeyset()}
+	if info := ks.GetKeysetInfo(); info != nil {
+		keyInfos := make([]interface{}, 0, len(info.GetKeyInfo()))
+		for _, k := range info.GetKeyInfo() {
+			keyInfos = append(keyInfos, cborValues{
+				"typeUrl":          k.GetTypeUrl(),
+				"keyId":            uint64(k.GetKeyId()),
+				"status":           uint64(k.GetStatus()),
+				"outputPrefixType": uint64(k.GetOutputPrefixType()),
+			})
+		}
+		m["keysetInfo"] = cborValues{
+			"primaryKeyId": uint64(info.GetPrimaryKeyId()),
+			"keyInfo":      keyInfos,
+		}
+	}
+	return c.write(m)
+}
+
+func (c *CBORWriter) write(m cborValues) error {
+	e := &cborEncoder{}
+	e.encode(m)
+	_, err := c.w.Write(e.buf)
+	return err
+}
+
+// cborValues is a decoded CBOR map with text keys. Its values are uint64,
+// []byte, string, []interface{} or cborValues.
+type cborValues map[string]interface{}
+
+func (m cborValues) uint(name string, max uint64) (uint64, error) {
+	v, ok := m[name]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(uint64)
+	if !ok || n > max {
+		return 0, fmt.Errorf("keyset.CBORReader: %s is not an unsigned integer up to %d", name, max)
+	}
+	return n, nil
+}
+
+func (m cborValues) keyID(name string) (uint32, error) {
+	n, err := m.uint(name, math.MaxUint32)
+	return uint32(n), err
+}
+
+func (m cborValues) enum(name string) (int32, error) {
+	n, err := m.uint(name, math.MaxInt32)
+	return int32(n), err
+}
+
+func (m cborValues) bytes(name string) ([]byte, error) {
+	v, ok := m[name]
+	if !ok {
+		return nil, nil
+	}
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("keyset.CBORReader: %s is not a byte string", name)
+	}
+	return b, nil
+}
+
+func (m cborValues) text(name string) (string, error) {
+	v, ok := m[name]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("keyset.CBORReader: %s is not a text string", name)
+	}
+	return s, nil
+}
+
+func (m cborValues) array(name string) ([]interface{}, error) {
+	v, ok := m[name]
+	if !ok {
+		return nil, nil
+	}
+	a, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("keyset.CBORReader: %s is not an array", name)
+	}
+	return a, nil
+}
+
+// cborEncoder encodes the values of cborValues deterministically, as in
+// section 4.2 of RFC 8949.
+type cborEncoder struct {
+	buf []byte
+}
+
+func (e *cborEncoder) head(major byte, n uint64) {
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major<<5|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major<<5|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, major<<5|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, major<<5|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, major<<5|27)
+		for i := 56; i >= 0; i -= 8 {
+			e.buf = append(e.buf, byte(n>>uint(i)))
+		}
+	}
+}
+
+func (e *cborEncoder) encode(v interface{}) {
+	switch v := v.(type) {
+	case uint64:
+		e.head(cborUint, v)
+	case []byte:
+		e.head(cborBytes, uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	case string:
+		e.head(cborText, uint64(len(v)))
+		e.buf = append(e.buf, v...)
+	case []interface{}:
+		e.head(cborArray, uint64(len(v)))
+		for _, item := range v {
+			e.encode(item)
+		}
+	case cborValues:
+		// Keys are sorted by their encoding, which for text strings means
+		// shorter keys first, then bytewise.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		e.head(cborMap, uint64(len(v)))
+		for _, k := range keys {
+			e.encode(k)
+			e.encode(v[k])
+		}
+	default:
+		panic(fmt.Sprintf("keyset: cannot encode %T as CBOR", v))
+	}
+}
+
+// cborDecoder decodes the subset of CBOR produced by cborEncoder: unsigned
+// integers, byte and text strings, arrays and maps with text keys, all of
+// definite length.
+type cborDecoder struct {
+	data []byte
+}
+
+func (d *cborDecoder) head() (byte, uint64, error) {
+	if len(d.data) == 0 {
+		return 0, 0, errors.New("keyset.CBORReader: unexpected end of data")
+	}
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	if info > 27 {
+		return 0, 0, errors.New("keyset.CBORReader: indefinite lengths are not supported")
+	}
+	size := 1 << (info - 24)
+	if len(d.data) < size {
+		return 0, 0, errors.New("keyset.CBORReader: unexpected end of data")
+	}
+	var n uint64
+	for _, b := range d.data[:size] {
+		n = n<<8 | uint64(b)
+	}
+	d.data = d.data[size:]
+	return major, n, nil
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > cborMaxDepth {
+		return nil, errors.New("keyset.CBORReader: nesting too deep")
+	}
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return n, nil
+	case cborBytes, cborText:
+		if n > uint64(len(d.data)) {
+			return nil, errors.New("keyset.CBORReader: unexpected end of data")
+		}
+		s := d.data[:n]
+		d.data = d.data[n:]
+		if major == cborText {
+			return string(s), nil
+		}
+		return append([]byte{}, s...), nil
+	case cborArray:
+		// Every item takes at least one byte.
+		if n > uint64(len(d.data)) {
+			return nil, errors.New("keyset.CBORReader: unexpected end of data")
+		}
+		a := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, item)
+		}
+		return a, nil
+	case cborMap:
+		if n > uint64(len(d.data))/2 {
+			return nil, errors.New("keyset.CBORReader: unexpected end of data")
+		}
+		m := make(cborValues, n)
+		for i := uint64(0); i < n; i++ {
+			k, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.New("keyset.CBORReader: map key is not a text string")
+			}
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("keyset.CBORReader: duplicate map key %q", key)
+			}
+			if m[key], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("keyset.CBORReader: unsupported major type %d", major)
+	}
+}
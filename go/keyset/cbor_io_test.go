@@ -0,0 +1,154 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestCBORIOUnencrypted(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := keyset.NewCBORWriter(buf)
+	r := keyset.NewCBORReader(buf)
+
+	manager := testutil.NewHMACKeysetManager()
+	h, err := manager.Handle()
+	if h == nil || err != nil {
+		t.Fatalf("cannot get keyset handle: %v", err)
+	}
+
+	ks1 := testkeyset.KeysetMaterial(h)
+	if err := w.Write(ks1); err != nil {
+		t.Fatalf("cannot write keyset: %v", err)
+	}
+
+	ks2, err := r.Read()
+	if err != nil {
+		t.Fatalf("cannot read keyset: %v", err)
+	}
+
+	if !proto.Equal(ks1, ks2) {
+		t.Errorf("written keyset (%s) doesn't match read keyset (%s)", ks1, ks2)
+	}
+}
+
+func TestCBORIOEncrypted(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := testutil.NewHMACKeysetManager().Handle()
+	if err != nil {
+		t.Fatalf("cannot get keyset handle: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := h.Write(keyset.NewCBORWriter(buf), masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	h2, err := keyset.Read(keyset.NewCBORReader(buf), masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() failed: %v", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+		t.Errorf("written keyset (%s) doesn't match read keyset (%s)", h, h2)
+	}
+	if !proto.Equal(h.KeysetInfo(), h2.KeysetInfo()) {
+		t.Errorf("written keyset info (%s) doesn't match read keyset info (%s)", h.KeysetInfo(), h2.KeysetInfo())
+	}
+}
+
+func TestCBORWriterEncoding(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := keyset.NewCBORWriter(buf).WriteEncrypted(&tinkpb.EncryptedKeyset{EncryptedKeyset: []byte{1, 2}}); err != nil {
+		t.Fatalf("WriteEncrypted() failed: %v", err)
+	}
+	// {"encryptedKeyset": h'0102'}
+	if got, want := hex.EncodeToString(buf.Bytes()), "a16f656e637279707465644b6579736574420102"; got != want {
+		t.Errorf("WriteEncrypted() wrote %s, want %s", got, want)
+	}
+
+	ks := &tinkpb.Keyset{
+		PrimaryKeyId: 1000,
+		Key: []*tinkpb.Keyset_Key{{
+			KeyData:          &tinkpb.KeyData{TypeUrl: "t", Value: []byte{0xff}, KeyMaterialType: tinkpb.KeyData_SYMMETRIC},
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            1000,
+			OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+		}},
+	}
+	buf.Reset()
+	if err := keyset.NewCBORWriter(buf).Write(ks); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	// {"key": [{"keyId": 1000, "status": 1, "keyData": {"value": h'ff',
+	// "typeUrl": "t", "keyMaterialType": 1}, "outputPrefixType": 1}],
+	// "primaryKeyId": 1000}, with keys sorted by length, then bytewise.
+	want := "a2" +
+		"636b6579" + "81" + "a4" +
+		"656b65794964" + "1903e8" +
+		"66737461747573" + "01" +
+		"676b657944617461" + "a3" +
+		"6576616c7565" + "41ff" +
+		"677479706555726c" + "6174" +
+		"6f6b65794d6174657269616c54797065" + "01" +
+		"706f757470757450726566697854797065" + "01" +
+		"6c7072696d6172794b65794964" + "1903e8"
+	if got := hex.EncodeToString(buf.Bytes()); got != want {
+		t.Errorf("Write() wrote %s, want %s", got, want)
+	}
+}
+
+func TestCBORReaderInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		hex  string
+	}{
+		{"empty", ""},
+		{"not a map", "01"},
+		{"truncated", "a16f656e637279707465644b65797365744201"},
+		{"trailing data", "a0" + "00"},
+		{"indefinite length", "bf" + "ff"},
+		{"negative integer", "a1" + "6c7072696d6172794b65794964" + "20"},
+		{"key ID too large", "a1" + "6c7072696d6172794b65794964" + "1b0000000100000000"},
+		{"wrong type", "a1" + "6c7072696d6172794b65794964" + "6161"},
+		{"integer map key", "a1" + "01" + "01"},
+		{"duplicate map key", "a2" + "6161" + "01" + "6161" + "01"},
+		{"huge array", "a1" + "636b6579" + "9b7fffffffffffffff"},
+		{"too deep", strings.Repeat("81", 20) + "00"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			if err != nil {
+				t.Fatalf("hex.DecodeString() failed: %v", err)
+			}
+			if ks, err := keyset.NewCBORReader(bytes.NewReader(data)).Read(); err == nil {
+				t.Errorf("Read() = %v, want error", ks)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// Keysets written with WriteEnvelope start with a header that describes how
+// they are encrypted, followed by the ciphertext of the serialized keyset
+// under the master key. Keysets written with Write have no header, and are
+// called legacy keysets here.
+//
+// Header layout:
+//   - magic: 4 bytes, 0xd3 'T' 'K' 'E'
+//   - format version: 1 byte
+//   - flags: 1 byte, bit 0 is set if the keyset is bound to associated data
+//   - wrap suite length: 1 byte
+//   - wrap suite: the name of the cipher suite of the master key
+//
+// The master key encrypts with the header followed by the associated data as
+// its associated data, so the header cannot be changed without detection.
+const (
+	envelopeVersion = 1
+
+	envelopeFlagAssociatedData = 1 << 0
+
+	// DefaultWrapSuite is the wrap suite recorded by WriteEnvelope if none is
+	// given, for master keys of unspecified algorithm, such as KMS keys.
+	DefaultWrapSuite = "tink-aead"
+)
+
+var envelopeMagic = []byte{0xd3, 'T', 'K', 'E'}
+
+// EnvelopeHeader describes how an encrypted keyset is encrypted.
+type EnvelopeHeader struct {
+	// Version is the format version, or 0 for legacy keysets.
+	Version int
+	// WrapSuite is the name of the cipher suite of the master key, e.g. the
+	// name of the KMS or "AES256-GCM". It is empty for legacy keysets.
+	WrapSuite string
+	// AssociatedData is whether the keyset is bound to associated data. It is
+	// unknown, and false, for legacy keysets.
+	AssociatedData bool
+}
+
+// EnvelopeOption configures how WriteEnvelope writes a keyset.
+type EnvelopeOption func(*envelopeOptions)
+
+type envelopeOptions struct {
+	wrapSuite string
+}
+
+// WithWrapSuite records name as the cipher suite of the master key, so that
+// tooling can tell which keysets need to be rewrapped when the master key
+// algorithm changes. It must be at most 255 bytes long.
+func WithWrapSuite(name string) EnvelopeOption {
+	return func(o *envelopeOptions) {
+		o.wrapSuite = name
+	}
+}
+
+// WriteEnvelope encrypts the enclosing keyset with masterKey and writes it in
+// the versioned envelope format, which records the format version, the wrap
+// suite and whether associatedData is bound to the keyset. associatedData may
+// be nil.
+//
+// Keysets written with WriteEnvelope can only be read with ReadEnvelope. To
+// migrate a legacy keyset, read it with ReadEnvelope and write it with
+// WriteEnvelope.
+func (h *Handle) WriteEnvelope(writer Writer, masterKey tink.AEAD, associatedData []byte, opts ...EnvelopeOption) error {
+	if masterKey == nil {
+		return errors.New("keyset.Handle: invalid master key")
+	}
+	o := &envelopeOptions{wrapSuite: DefaultWrapSuite}
+	for _, opt := range opts {
+		opt(o)
+	}
+	header := &EnvelopeHeader{
+		Version:        envelopeVersion,
+		WrapSuite:      o.wrapSuite,
+		AssociatedData: len(associatedData) > 0,
+	}
+	if len(header.WrapSuite) > 255 {
+		return fmt.Errorf("keyset.Handle: wrap suite name too long")
+	}
+	b := header.marshal()
+	serializedKeyset, err := proto.Marshal(h.ks)
+	if err != nil {
+		return errInvalidKeyset
+	}
+	ct, err := masterKey.Encrypt(serializedKeyset, envelopeAssociatedData(b, associatedData))
+	if err != nil {
+		return fmt.Errorf("keyset.Handle: encryption failed: %s", err)
+	}
+	if err := writer.WriteEncrypted(&tinkpb.EncryptedKeyset{
+		EncryptedKeyset: append(b, ct...),
+		KeysetInfo:      getKeysetInfo(h.ks),
+	}); err != nil {
+		return err
+	}
+	return writeAnnotations(writer, h.annotations)
+}
+
+// ReadEnvelope tries to create a Handle from an encrypted keyset obtained via
+// reader, which was written with WriteEnvelope or, for legacy keysets, with
+// Write or WriteWithAssociatedData. associatedData must be the one the keyset
+// was written with, and may be nil.
+func ReadEnvelope(reader Reader, masterKey tink.AEAD, associatedData []byte) (*Handle, error) {
+	encryptedKeyset, err := reader.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+	if encryptedKeyset == nil || masterKey == nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
+	}
+	ks, err := decryptEnvelope(encryptedKeyset.EncryptedKeyset, masterKey, associatedData)
+	if err != nil {
+		// Legacy keysets are bare ciphertexts, which may start with the magic
+		// bytes by chance.
+		legacy, legacyErr := decrypt(encryptedKeyset, masterKey, associatedData)
+		if legacyErr != nil {
+			return nil, err
+		}
+		ks = legacy
+	}
+	annotations, err := readAnnotations(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Handle{ks: ks, annotations: annotations}, nil
+}
+
+// ReadEnvelopeHeader returns the header of an encrypted keyset, without
+// decrypting it. It returns a header with version 0 for legacy keysets. The
+// header is only authenticated when the keyset is decrypted.
+func ReadEnvelopeHeader(encryptedKeyset *tinkpb.EncryptedKeyset) (*EnvelopeHeader, error) {
+	header, _, err := parseEnvelopeHeader(encryptedKeyset.GetEncryptedKeyset())
+	if errors.Is(err, errNotEnvelope) {
+		return &EnvelopeHeader{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %s", err)
+	}
+	return header, nil
+}
+
+var errNotEnvelope = errors.New("not an envelope")
+
+func decryptEnvelope(b []byte, masterKey tink.AEAD, associatedData []byte) (*tinkpb.Keyset, error) {
+	header, headerLen, err := parseEnvelopeHeader(b)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %s", err)
+	}
+	if header.AssociatedData != (len(associatedData) > 0) {
+		if header.AssociatedData {
+			return nil, errors.New("keyset.Handle: keyset is bound to associated data, but none was given")
+		}
+		return nil, errors.New("keyset.Handle: keyset is not bound to associated data, but some was given")
+	}
+	decrypted, err := masterKey.Decrypt(b[headerLen:], envelopeAssociatedData(b[:headerLen], associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: decryption failed: %s", err)
+	}
+	ks := new(tinkpb.Keyset)
+	if err := proto.Unmarshal(decrypted, ks); err != nil {
+		return nil, errInvalidKeyset
+	}
+	return ks, nil
+}
+
+func (h *EnvelopeHeader) marshal() []byte {
+	b := append([]byte(nil), envelopeMagic...)
+	var flags byte
+	if h.AssociatedData {
+		flags |= envelopeFlagAssociatedData
+	}
+	b = append(b, byte(h.Version), flags, byte(len(h.WrapSuite)))
+	return append(b, h.WrapSuite...)
+}
+
+// parseEnvelopeHeader parses the header at the start of b. It returns the
+// header and its length, or errNotEnvelope if b does not start with the magic
+// bytes.
+func parseEnvelopeHeader(b []byte) (*EnvelopeHeader, int, error) {
+	if !bytes.HasPrefix(b, envelopeMagic) {
+		return nil, 0, errNotEnvelope
+	}
+	n := len(envelopeMagic)
+	if len(b) < n+3 {
+		return nil, 0, errors.New("envelope header too short")
+	}
+	version, flags, suiteLen := b[n], b[n+1], int(b[n+2])
+	if version != envelopeVersion {
+		return nil, 0, fmt.Errorf("unsupported envelope version %d", version)
+	}
+	if flags&^envelopeFlagAssociatedData != 0 {
+		return nil, 0, fmt.Errorf("unknown envelope flags %#x", flags)
+	}
+	headerLen := n + 3 + suiteLen
+	if len(b) < headerLen {
+		return nil, 0, errors.New("envelope header too short")
+	}
+	return &EnvelopeHeader{
+		Version:        int(version),
+		WrapSuite:      string(b[n+3 : headerLen]),
+		AssociatedData: flags&envelopeFlagAssociatedData != 0,
+	}, headerLen, nil
+}
+
+// envelopeAssociatedData returns the associated data passed to the master key
+// for the given header and associated data.
+func envelopeAssociatedData(header, associatedData []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(associatedData))
+	ad = append(ad, header...)
+	return append(ad, associatedData...)
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/tink"
+)
+
+func newEnvelopeTestHandle(t *testing.T) (*keyset.Handle, tink.AEAD) {
+	t.Helper()
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	return h, masterKey
+}
+
+func TestEnvelope(t *testing.T) {
+	h, masterKey := newEnvelopeTestHandle(t)
+	for _, tc := range []struct {
+		name      string
+		ad        []byte
+		opts      []keyset.EnvelopeOption
+		wrapSuite string
+	}{
+		{"default", nil, nil, keyset.DefaultWrapSuite},
+		{"associated data", []byte("tenant-1"), nil, keyset.DefaultWrapSuite},
+		{"wrap suite", nil, []keyset.EnvelopeOption{keyset.WithWrapSuite("AES256-GCM")}, "AES256-GCM"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := &keyset.MemReaderWriter{}
+			if err := h.WriteEnvelope(mem, masterKey, tc.ad, tc.opts...); err != nil {
+				t.Fatalf("h.WriteEnvelope() failed: %v", err)
+			}
+			header, err := keyset.ReadEnvelopeHeader(mem.EncryptedKeyset)
+			if err != nil {
+				t.Fatalf("keyset.ReadEnvelopeHeader() failed: %v", err)
+			}
+			want := keyset.EnvelopeHeader{Version: 1, WrapSuite: tc.wrapSuite, AssociatedData: tc.ad != nil}
+			if *header != want {
+				t.Errorf("keyset.ReadEnvelopeHeader() = %+v, want %+v", *header, want)
+			}
+			h2, err := keyset.ReadEnvelope(mem, masterKey, tc.ad)
+			if err != nil {
+				t.Fatalf("keyset.ReadEnvelope() failed: %v", err)
+			}
+			if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+				t.Error("keyset.ReadEnvelope() returned a different keyset")
+			}
+			if _, err := keyset.Read(mem, masterKey); err == nil {
+				t.Error("keyset.Read() of an envelope succeeded, want error")
+			}
+			if _, err := keyset.ReadEnvelope(mem, masterKey, []byte("other")); err == nil {
+				t.Error("keyset.ReadEnvelope() with other associated data succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestEnvelopeReadsLegacyKeysets(t *testing.T) {
+	h, masterKey := newEnvelopeTestHandle(t)
+	for _, ad := range [][]byte{nil, []byte("tenant-1")} {
+		mem := &keyset.MemReaderWriter{}
+		if err := h.WriteWithAssociatedData(mem, masterKey, ad); err != nil {
+			t.Fatalf("h.WriteWithAssociatedData() failed: %v", err)
+		}
+		header, err := keyset.ReadEnvelopeHeader(mem.EncryptedKeyset)
+		if err != nil || header.Version != 0 {
+			t.Errorf("keyset.ReadEnvelopeHeader() of a legacy keyset = %+v, %v, want version 0", header, err)
+		}
+		h2, err := keyset.ReadEnvelope(mem, masterKey, ad)
+		if err != nil {
+			t.Fatalf("keyset.ReadEnvelope() of a legacy keyset failed: %v", err)
+		}
+
+		// Migrate the keyset to the envelope format.
+		migrated := &keyset.MemReaderWriter{}
+		if err := h2.WriteEnvelope(migrated, masterKey, ad); err != nil {
+			t.Fatalf("h2.WriteEnvelope() failed: %v", err)
+		}
+		h3, err := keyset.ReadEnvelope(migrated, masterKey, ad)
+		if err != nil {
+			t.Fatalf("keyset.ReadEnvelope() of a migrated keyset failed: %v", err)
+		}
+		if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h3)) {
+			t.Error("migrated keyset differs from the original")
+		}
+	}
+}
+
+func TestEnvelopeHeaderIsAuthenticated(t *testing.T) {
+	h, masterKey := newEnvelopeTestHandle(t)
+	mem := &keyset.MemReaderWriter{}
+	if err := h.WriteEnvelope(mem, masterKey, nil, keyset.WithWrapSuite("AES256-GCM")); err != nil {
+		t.Fatalf("h.WriteEnvelope() failed: %v", err)
+	}
+	ct := mem.EncryptedKeyset.EncryptedKeyset
+	i := bytes.Index(ct, []byte("AES256-GCM"))
+	for _, tc := range []struct {
+		name   string
+		modify func(b []byte) []byte
+	}{
+		{"wrap suite", func(b []byte) []byte { b[i] = 'X'; return b }},
+		{"version", func(b []byte) []byte { b[4] = 2; return b }},
+		{"flags", func(b []byte) []byte { b[5] = 1; return b }},
+		{"truncated", func(b []byte) []byte { return b[:6] }},
+	} {
+		mem.EncryptedKeyset.EncryptedKeyset = tc.modify(append([]byte(nil), ct...))
+		if _, err := keyset.ReadEnvelope(mem, masterKey, nil); err == nil {
+			t.Errorf("%s: keyset.ReadEnvelope() of a modified envelope succeeded, want error", tc.name)
+		}
+	}
+	mem.EncryptedKeyset.EncryptedKeyset = ct
+	if _, err := keyset.ReadEnvelope(mem, masterKey, nil); err != nil {
+		t.Errorf("keyset.ReadEnvelope() failed: %v", err)
+	}
+}
+
+func TestWriteEnvelopeWithLongWrapSuite(t *testing.T) {
+	h, masterKey := newEnvelopeTestHandle(t)
+	if err := h.WriteEnvelope(&keyset.MemReaderWriter{}, masterKey, nil, keyset.WithWrapSuite(strings.Repeat("a", 256))); err == nil {
+		t.Error("h.WriteEnvelope() with a long wrap suite succeeded, want error")
+	}
+}
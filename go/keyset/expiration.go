@@ -0,0 +1,73 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// keyExpirationAnnotationPrefix is the prefix of the reserved annotations
+// which hold the expiration times of keys, followed by the decimal key ID. The
+// value is the expiration time in RFC 3339 format.
+const keyExpirationAnnotationPrefix = reservedAnnotationPrefix + "key_expiration."
+
+// WithKeyExpiration returns a handle for the same keyset as h in which the key
+// with the given ID expires at expiration. Primitives created from the handle
+// refuse to compute new ciphertexts, MACs or signatures with an expired
+// primary key, but still decrypt and verify with it, so that keys can be
+// time-boxed without losing access to existing data.
+//
+// Expiration times are stored in reserved annotations of the handle, so they
+// cannot be changed with WithAnnotations, and are only persisted by writers
+// which implement AnnotationsWriter.
+func (h *Handle) WithKeyExpiration(keyID uint32, expiration time.Time) (*Handle, error) {
+	if !h.hasKey(keyID) {
+		return nil, fmt.Errorf("keyset.Handle: key %d not found", keyID)
+	}
+	value := expiration.UTC().Format(time.RFC3339Nano)
+	return &Handle{ks: h.ks, annotations: withAnnotation(h.annotations, keyExpirationAnnotation(keyID), value)}, nil
+}
+
+// KeyExpiration returns the expiration time of the key with the given ID, and
+// false if the key doesn't expire. It returns an error if the stored
+// expiration time is malformed.
+func (h *Handle) KeyExpiration(keyID uint32) (time.Time, bool, error) {
+	v, ok := h.annotations[keyExpirationAnnotation(keyID)]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("keyset.Handle: invalid expiration of key %d: %v", keyID, err)
+	}
+	return t, true, nil
+}
+
+func keyExpirationAnnotation(keyID uint32) string {
+	return keyExpirationAnnotationPrefix + strconv.FormatUint(uint64(keyID), 10)
+}
+
+func (h *Handle) hasKey(keyID uint32) bool {
+	for _, key := range h.ks.GetKey() {
+		if key.GetKeyId() == keyID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+)
+
+func TestKeyExpiration(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	keyID := h.KeysetInfo().GetPrimaryKeyId()
+	if _, ok, err := h.KeyExpiration(keyID); ok || err != nil {
+		t.Errorf("h.KeyExpiration() = _, %v, %v, want false, nil", ok, err)
+	}
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 6, time.UTC)
+	expiring, err := h.WithKeyExpiration(keyID, expiration)
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	got, ok, err := expiring.KeyExpiration(keyID)
+	if err != nil || !ok || !got.Equal(expiration) {
+		t.Errorf("expiring.KeyExpiration() = %v, %v, %v, want %v, true, nil", got, ok, err, expiration)
+	}
+	if _, ok, _ := h.KeyExpiration(keyID); ok {
+		t.Error("h.WithKeyExpiration() modified h")
+	}
+	ps, err := expiring.Primitives()
+	if err != nil {
+		t.Fatalf("expiring.Primitives() failed: %v", err)
+	}
+	if !ps.Primary.Expiration.Equal(expiration) {
+		t.Errorf("ps.Primary.Expiration = %v, want %v", ps.Primary.Expiration, expiration)
+	}
+	if _, err := h.WithKeyExpiration(keyID+1, expiration); err == nil {
+		t.Error("h.WithKeyExpiration() of an unknown key succeeded, want error")
+	}
+
+	malformed := withReservedAnnotation(t, h, "tink.key_expiration.1", "tomorrow")
+	if _, _, err := malformed.KeyExpiration(1); err == nil {
+		t.Error("KeyExpiration() of a malformed expiration succeeded, want error")
+	}
+}
+
+// withReservedAnnotation returns a handle for the keyset of h with an
+// annotation set that WithAnnotations refuses, as if it was tampered with in
+// storage.
+func withReservedAnnotation(t *testing.T, h *keyset.Handle, key, value string) *keyset.Handle {
+	t.Helper()
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.Write(memKeyset, masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	if memKeyset.Annotations == nil {
+		memKeyset.Annotations = make(map[string]string)
+	}
+	memKeyset.Annotations[key] = value
+	h, err = keyset.Read(memKeyset, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() failed: %v", err)
+	}
+	return h
+}
+
+func TestKeyExpirationWrittenAlongsideKeyset(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	keyID := h.KeysetInfo().GetPrimaryKeyId()
+	expiration := time.Now().Add(-time.Minute).UTC()
+	expired, err := h.WithKeyExpiration(keyID, expiration)
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := expired.Write(memKeyset, masterKey); err != nil {
+		t.Fatalf("expired.Write() failed: %v", err)
+	}
+	read, err := keyset.Read(memKeyset, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() failed: %v", err)
+	}
+	got, ok, err := read.KeyExpiration(keyID)
+	if err != nil || !ok || !got.Equal(expiration) {
+		t.Errorf("read.KeyExpiration() = %v, %v, %v, want %v, true, nil", got, ok, err, expiration)
+	}
+	a, err := aead.New(read)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %v", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), nil); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("a.Encrypt() err = %v, want ErrKeyExpired", err)
+	}
+}
+
+func TestKeyExpirationRemovedWithKey(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	m := keyset.NewManager()
+	kt := aead.AES128GCMKeyTemplate()
+	if err := m.Rotate(kt); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	destroyedID, err := m.Add(kt)
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	deletedID, err := m.Add(kt)
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	expiration := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, keyID := range []uint32{destroyedID, deletedID} {
+		if h, err = h.WithKeyExpiration(keyID, expiration); err != nil {
+			t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+		}
+	}
+
+	m = keyset.NewManagerFromHandle(h)
+	if err := m.Destroy(destroyedID); err != nil {
+		t.Fatalf("m.Destroy() failed: %v", err)
+	}
+	if err := m.Delete(deletedID); err != nil {
+		t.Fatalf("m.Delete() failed: %v", err)
+	}
+	h2, _ := m.Handle()
+	for _, keyID := range []uint32{destroyedID, deletedID} {
+		if _, ok, err := h2.KeyExpiration(keyID); ok || err != nil {
+			t.Errorf("h2.KeyExpiration(%d) = _, %v, %v, want false, nil", keyID, ok, err)
+		}
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h2.Write(memKeyset, masterKey); err != nil {
+		t.Fatalf("h2.Write() failed: %v", err)
+	}
+	if len(memKeyset.Annotations) != 0 {
+		t.Errorf("written annotations = %v, want none", memKeyset.Annotations)
+	}
+
+	// The expirations of the original handle are unchanged.
+	for _, keyID := range []uint32{destroyedID, deletedID} {
+		if _, ok, _ := h.KeyExpiration(keyID); !ok {
+			t.Errorf("h.KeyExpiration(%d) = _, false, _, want true", keyID)
+		}
+	}
+}
+
+func TestExpiredPrimaryKeyAEAD(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	a, err := aead.New(h)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %v", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), []byte("ad"))
+	if err != nil {
+		t.Fatalf("a.Encrypt() failed: %v", err)
+	}
+
+	expired, err := h.WithKeyExpiration(h.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	a, err = aead.New(expired)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %v", err)
+	}
+	if _, err := a.Encrypt([]byte("plaintext"), []byte("ad")); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("a.Encrypt() err = %v, want ErrKeyExpired", err)
+	}
+	if _, err := a.Decrypt(ct, []byte("ad")); err != nil {
+		t.Errorf("a.Decrypt() with an expired key failed: %v", err)
+	}
+}
+
+func TestExpiredPrimaryKeyMAC(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	m, err := mac.New(h)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	tag, err := m.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("m.ComputeMAC() failed: %v", err)
+	}
+
+	expired, err := h.WithKeyExpiration(h.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	m, err = mac.New(expired)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	if _, err := m.ComputeMAC([]byte("data")); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("m.ComputeMAC() err = %v, want ErrKeyExpired", err)
+	}
+	if err := m.VerifyMAC(tag, []byte("data")); err != nil {
+		t.Errorf("m.VerifyMAC() with an expired key failed: %v", err)
+	}
+}
+
+func TestExpiredPrimaryKeySignature(t *testing.T) {
+	h, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	expiring, err := h.WithKeyExpiration(h.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	signer, err := signature.NewSigner(expiring)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() failed: %v", err)
+	}
+	sig, err := signer.Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("signer.Sign() with a key that expires in the future failed: %v", err)
+	}
+
+	expired, err := h.WithKeyExpiration(h.KeysetInfo().GetPrimaryKeyId(), time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("h.WithKeyExpiration() failed: %v", err)
+	}
+	signer, err = signature.NewSigner(expired)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() failed: %v", err)
+	}
+	if _, err := signer.Sign([]byte("data")); !errors.Is(err, primitiveset.ErrKeyExpired) {
+		t.Errorf("signer.Sign() err = %v, want ErrKeyExpired", err)
+	}
+	pub, err := expired.Public()
+	if err != nil {
+		t.Fatalf("expired.Public() failed: %v", err)
+	}
+	verifier, err := signature.NewVerifier(pub)
+	if err != nil {
+		t.Fatalf("signature.NewVerifier() failed: %v", err)
+	}
+	if err := verifier.Verify(sig, []byte("data")); err != nil {
+		t.Errorf("verifier.Verify() with an expired key failed: %v", err)
+	}
+}
@@ -33,7 +33,8 @@ var errInvalidKeyset = fmt.Errorf("keyset.Handle: invalid keyset")
 // Handle provides access to a Keyset protobuf, to limit the exposure of actual protocol
 // buffers that hold sensitive key material.
 type Handle struct {
-	ks *tinkpb.Keyset
+	ks          *tinkpb.Keyset
+	annotations map[string]string
 }
 
 // NewHandle creates a keyset handle that contains a single fresh key generated according
@@ -57,7 +58,7 @@ func NewHandleWithNoSecrets(ks *tinkpb.Keyset) (*Handle, error) {
 	if ks == nil {
 		return nil, errors.New("keyset.Handle: nil keyset")
 	}
-	h := &Handle{ks}
+	h := &Handle{ks: ks}
 	if h.hasSecrets() {
 		// If you need to do this, you have to use func insecurecleartextkeyset.Read() instead.
 		return nil, errors.New("importing unencrypted secret key material is forbidden")
@@ -67,15 +68,25 @@ func NewHandleWithNoSecrets(ks *tinkpb.Keyset) (*Handle, error) {
 
 // Read tries to create a Handle from an encrypted keyset obtained via reader.
 func Read(reader Reader, masterKey tink.AEAD) (*Handle, error) {
+	return ReadWithAssociatedData(reader, masterKey, []byte{})
+}
+
+// ReadWithAssociatedData tries to create a Handle from an encrypted keyset
+// obtained via reader, using the associated data the keyset was written with.
+func ReadWithAssociatedData(reader Reader, masterKey tink.AEAD, associatedData []byte) (*Handle, error) {
 	encryptedKeyset, err := reader.ReadEncrypted()
 	if err != nil {
 		return nil, err
 	}
-	ks, err := decrypt(encryptedKeyset, masterKey)
+	ks, err := decrypt(encryptedKeyset, masterKey, associatedData)
 	if err != nil {
 		return nil, err
 	}
-	return &Handle{ks}, nil
+	annotations, err := readAnnotations(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Handle{ks: ks, annotations: annotations}, nil
 }
 
 // ReadWithNoSecrets tries to create a keyset.Handle from a keyset obtained via reader.
@@ -84,10 +95,19 @@ func ReadWithNoSecrets(reader Reader) (*Handle, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewHandleWithNoSecrets(ks)
+	h, err := NewHandleWithNoSecrets(ks)
+	if err != nil {
+		return nil, err
+	}
+	if h.annotations, err = readAnnotations(reader); err != nil {
+		return nil, err
+	}
+	return h, nil
 }
 
 // Public returns a Handle of the public keys if the managed keyset contains private keys.
+// The handle keeps the annotations of h, except the reserved ones, such as key
+// expiration times and the audit log, which concern the private keys.
 func (h *Handle) Public() (*Handle, error) {
 	privKeys := h.ks.Key
 	pubKeys := make([]*tinkpb.Keyset_Key, len(privKeys))
@@ -112,7 +132,7 @@ func (h *Handle) Public() (*Handle, error) {
 		PrimaryKeyId: h.ks.PrimaryKeyId,
 		Key:          pubKeys,
 	}
-	return &Handle{ks}, nil
+	return &Handle{ks: ks, annotations: userAnnotations(h.annotations)}, nil
 }
 
 // String returns a string representation of the managed keyset.
@@ -129,11 +149,22 @@ func (h *Handle) KeysetInfo() *tinkpb.KeysetInfo {
 
 // Write encrypts and writes the enclosing keyset.
 func (h *Handle) Write(writer Writer, masterKey tink.AEAD) error {
-	encrypted, err := encrypt(h.ks, masterKey)
+	return h.WriteWithAssociatedData(writer, masterKey, []byte{})
+}
+
+// WriteWithAssociatedData encrypts and writes the enclosing keyset, passing
+// associatedData to masterKey. This binds the encrypted keyset to some
+// context, such as its storage location or tenant ID, so that it can only be
+// read with ReadWithAssociatedData and the same associated data.
+func (h *Handle) WriteWithAssociatedData(writer Writer, masterKey tink.AEAD, associatedData []byte) error {
+	encrypted, err := encrypt(h.ks, masterKey, associatedData)
 	if err != nil {
 		return err
 	}
-	return writer.WriteEncrypted(encrypted)
+	if err := writer.WriteEncrypted(encrypted); err != nil {
+		return err
+	}
+	return writeAnnotations(writer, h.annotations)
 }
 
 // WriteWithNoSecrets exports the keyset in h to the given Writer w returning an error if the keyset
@@ -143,7 +174,10 @@ func (h *Handle) WriteWithNoSecrets(w Writer) error {
 		return errors.New("exporting unencrypted secret key material is forbidden")
 	}
 
-	return w.Write(h.ks)
+	if err := w.Write(h.ks); err != nil {
+		return err
+	}
+	return writeAnnotations(w, h.annotations)
 }
 
 // Primitives creates a set of primitives corresponding to the keys with
@@ -169,28 +203,52 @@ func (h *Handle) Primitives() (*primitiveset.PrimitiveSet, error) {
 // The returned set is usually later "wrapped" into a class that implements
 // the corresponding Primitive-interface.
 func (h *Handle) PrimitivesWithKeyManager(km registry.KeyManager) (*primitiveset.PrimitiveSet, error) {
+	return h.primitives("registry.PrimitivesWithKeyManager", func(kd *tinkpb.KeyData) (interface{}, error) {
+		if km != nil && km.DoesSupport(kd.TypeUrl) {
+			return km.Primitive(kd.Value)
+		}
+		return registry.PrimitiveFromKeyData(kd)
+	})
+}
+
+// PrimitivesWithRegistry is like Primitives, but uses the key managers of r
+// instead of the globally registered ones. It fails if the keyset has an
+// enabled key of a type which is not registered in r.
+func (h *Handle) PrimitivesWithRegistry(r *registry.Registry) (*primitiveset.PrimitiveSet, error) {
+	if r == nil {
+		return nil, errors.New("keyset.Handle: invalid registry")
+	}
+	return h.primitives("keyset.Handle", r.PrimitiveFromKeyData)
+}
+
+// primitives creates a set of primitives for the enabled keys in h, getting
+// each primitive from newPrimitive. Errors are prefixed by name.
+func (h *Handle) primitives(name string, newPrimitive func(*tinkpb.KeyData) (interface{}, error)) (*primitiveset.PrimitiveSet, error) {
 	if err := Validate(h.ks); err != nil {
-		return nil, fmt.Errorf("registry.PrimitivesWithKeyManager: invalid keyset: %s", err)
+		return nil, fmt.Errorf("%s: invalid keyset: %s", name, err)
 	}
 	primitiveSet := primitiveset.New()
+	primitiveSet.Annotations = userAnnotations(h.annotations)
 	for _, key := range h.ks.Key {
 		if key.Status != tinkpb.KeyStatusType_ENABLED {
 			continue
 		}
-		var primitive interface{}
-		var err error
-		if km != nil && km.DoesSupport(key.KeyData.TypeUrl) {
-			primitive, err = km.Primitive(key.KeyData.Value)
-		} else {
-			primitive, err = registry.PrimitiveFromKeyData(key.KeyData)
+		if err := registry.CheckKey(key); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
 		}
+		primitive, err := newPrimitive(key.KeyData)
 		if err != nil {
-			return nil, fmt.Errorf("registry.PrimitivesWithKeyManager: cannot get primitive from key: %s", err)
+			return nil, fmt.Errorf("%s: cannot get primitive from key: %s", name, err)
 		}
 		entry, err := primitiveSet.Add(primitive, key)
 		if err != nil {
-			return nil, fmt.Errorf("registry.PrimitivesWithKeyManager: cannot add primitive: %s", err)
+			return nil, fmt.Errorf("%s: cannot add primitive: %s", name, err)
+		}
+		expiration, _, err := h.KeyExpiration(key.KeyId)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
 		}
+		entry.Expiration = expiration
 		if key.KeyId == h.ks.PrimaryKeyId {
 			primitiveSet.Primary = entry
 		}
@@ -234,11 +292,11 @@ func publicKeyData(privKeyData *tinkpb.KeyData) (*tinkpb.KeyData, error) {
 	return pkm.PublicKeyData(privKeyData.Value)
 }
 
-func decrypt(encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEAD) (*tinkpb.Keyset, error) {
+func decrypt(encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEAD, associatedData []byte) (*tinkpb.Keyset, error) {
 	if encryptedKeyset == nil || masterKey == nil {
 		return nil, fmt.Errorf("keyset.Handle: invalid encrypted keyset")
 	}
-	decrypted, err := masterKey.Decrypt(encryptedKeyset.EncryptedKeyset, []byte{})
+	decrypted, err := masterKey.Decrypt(encryptedKeyset.EncryptedKeyset, associatedData)
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: decryption failed: %s", err)
 	}
@@ -249,12 +307,12 @@ func decrypt(encryptedKeyset *tinkpb.EncryptedKeyset, masterKey tink.AEAD) (*tin
 	return keyset, nil
 }
 
-func encrypt(keyset *tinkpb.Keyset, masterKey tink.AEAD) (*tinkpb.EncryptedKeyset, error) {
+func encrypt(keyset *tinkpb.Keyset, masterKey tink.AEAD, associatedData []byte) (*tinkpb.EncryptedKeyset, error) {
 	serializedKeyset, err := proto.Marshal(keyset)
 	if err != nil {
 		return nil, errInvalidKeyset
 	}
-	encrypted, err := masterKey.Encrypt(serializedKeyset, []byte{})
+	encrypted, err := masterKey.Encrypt(serializedKeyset, associatedData)
 	if err != nil {
 		return nil, fmt.Errorf("keyset.Handle: encrypted failed: %s", err)
 	}
@@ -169,3 +169,44 @@ func TestKeysetInfo(t *testing.T) {
 		t.Errorf("Expected primary key id: %d, but got: %d", info.KeyInfo[0].KeyId, info.PrimaryKeyId)
 	}
 }
+
+func TestWriteAndReadWithAssociatedData(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM(): %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle(): %v", err)
+	}
+	associatedData := []byte("tenant 1")
+
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.WriteWithAssociatedData(memKeyset, masterKey, associatedData); err != nil {
+		t.Fatalf("h.WriteWithAssociatedData(): %v", err)
+	}
+	h2, err := keyset.ReadWithAssociatedData(memKeyset, masterKey, associatedData)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithAssociatedData(): %v", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+		t.Errorf("keyset.ReadWithAssociatedData() = %v, want %v", h2, h)
+	}
+	if _, err := keyset.ReadWithAssociatedData(memKeyset, masterKey, []byte("tenant 2")); err == nil {
+		t.Error("keyset.ReadWithAssociatedData() with different associated data succeeded, want error")
+	}
+	if _, err := keyset.Read(memKeyset, masterKey); err == nil {
+		t.Error("keyset.Read() of a keyset written with associated data succeeded, want error")
+	}
+
+	// Write and Read use empty associated data.
+	if err := h.Write(memKeyset, masterKey); err != nil {
+		t.Fatalf("h.Write(): %v", err)
+	}
+	if _, err := keyset.ReadWithAssociatedData(memKeyset, masterKey, nil); err != nil {
+		t.Errorf("keyset.ReadWithAssociatedData() with empty associated data failed: %v", err)
+	}
+	if _, err := keyset.ReadWithAssociatedData(memKeyset, masterKey, associatedData); err == nil {
+		t.Error("keyset.ReadWithAssociatedData() of a keyset written without associated data succeeded, want error")
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const typeURLPrefix = "type.googleapis.com/"
+
+// KeyReport describes a key of a keyset without its key material.
+type KeyReport struct {
+	KeyID            uint32
+	Primary          bool
+	Status           tinkpb.KeyStatusType
+	OutputPrefixType tinkpb.OutputPrefixType
+	TypeURL          string
+	// Algorithm is the name of the key type, e.g. "AesGcmKey".
+	Algorithm       string
+	KeyMaterialType tinkpb.KeyData_KeyMaterialType
+	// KeySize is the size of the key in bytes, that is the length of its key
+	// value, or of the modulus of RSA keys. It is zero if it cannot be
+	// determined, for example for destroyed keys or key types whose protos are
+	// not linked into the binary.
+	KeySize int
+}
+
+// Report describes a keyset without its key material. Keysets do not record
+// when keys were created, so there are no creation times, unless the keyset
+// has an audit log.
+type Report struct {
+	PrimaryKeyID uint32
+	Keys         []KeyReport
+	// AuditLog is the audit log of the keyset, see WithAuditLog. It is empty
+	// if the keyset has none or it is malformed; use Handle.AuditLog to tell
+	// these apart.
+	AuditLog []KeyEvent
+}
+
+// KeyStatusChange is a change of the status of a key.
+type KeyStatusChange struct {
+	KeyID     uint32
+	OldStatus tinkpb.KeyStatusType
+	NewStatus tinkpb.KeyStatusType
+}
+
+// DiffReport describes the changes from one keyset to another.
+type DiffReport struct {
+	// Added are the keys only in the new keyset.
+	Added []KeyReport
+	// Removed are the keys only in the old keyset. A key whose key ID is in
+	// both keysets but whose key data or output prefix differ is reported as
+	// both removed and added.
+	Removed []KeyReport
+	// StatusChanged are the keys in both keysets whose status changed.
+	StatusChanged   []KeyStatusChange
+	OldPrimaryKeyID uint32
+	NewPrimaryKeyID uint32
+}
+
+// Inspect returns a report of the keys in h, for example for change review
+// tooling. It does not contain any key material.
+func Inspect(h *Handle) *Report {
+	r := &Report{PrimaryKeyID: h.ks.PrimaryKeyId}
+	for _, key := range h.ks.Key {
+		r.Keys = append(r.Keys, keyReport(key, h.ks.PrimaryKeyId))
+	}
+	r.AuditLog, _ = h.AuditLog()
+	return r
+}
+
+// Diff returns the changes from the keyset in a to the keyset in b. It does
+// not contain any key material.
+func Diff(a, b *Handle) *DiffReport {
+	d := &DiffReport{
+		OldPrimaryKeyID: a.ks.PrimaryKeyId,
+		NewPrimaryKeyID: b.ks.PrimaryKeyId,
+	}
+	oldKeys := make(map[uint32]*tinkpb.Keyset_Key)
+	for _, key := range a.ks.Key {
+		oldKeys[key.KeyId] = key
+	}
+	newKeys := make(map[uint32]*tinkpb.Keyset_Key)
+	for _, key := range b.ks.Key {
+		newKeys[key.KeyId] = key
+	}
+	for _, key := range a.ks.Key {
+		if n, ok := newKeys[key.KeyId]; !ok || !sameKey(key, n) {
+			d.Removed = append(d.Removed, keyReport(key, a.ks.PrimaryKeyId))
+		}
+	}
+	for _, key := range b.ks.Key {
+		o, ok := oldKeys[key.KeyId]
+		switch {
+		case !ok || !sameKey(o, key):
+			d.Added = append(d.Added, keyReport(key, b.ks.PrimaryKeyId))
+		case o.Status != key.Status:
+			d.StatusChanged = append(d.StatusChanged, KeyStatusChange{
+				KeyID:     key.KeyId,
+				OldStatus: o.Status,
+				NewStatus: key.Status,
+			})
+		}
+	}
+	return d
+}
+
+// sameKey returns whether a and b are the same key, ignoring their status.
+func sameKey(a, b *tinkpb.Keyset_Key) bool {
+	return a.OutputPrefixType == b.OutputPrefixType && proto.Equal(a.KeyData, b.KeyData)
+}
+
+func keyReport(key *tinkpb.Keyset_Key, primaryKeyID uint32) KeyReport {
+	typeURL := key.GetKeyData().GetTypeUrl()
+	return KeyReport{
+		KeyID:            key.KeyId,
+		Primary:          key.KeyId == primaryKeyID,
+		Status:           key.Status,
+		OutputPrefixType: key.OutputPrefixType,
+		TypeURL:          typeURL,
+		Algorithm:        typeURL[strings.LastIndex(typeURL, ".")+1:],
+		KeyMaterialType:  key.GetKeyData().GetKeyMaterialType(),
+		KeySize:          keySize(key.GetKeyData()),
+	}
+}
+
+// keySize returns the size of the key in keyData, or zero if it cannot be
+// determined.
+func keySize(keyData *tinkpb.KeyData) int {
+	if !strings.HasPrefix(keyData.GetTypeUrl(), typeURLPrefix) {
+		return 0
+	}
+	t := proto.MessageType(strings.TrimPrefix(keyData.TypeUrl, typeURLPrefix))
+	if t == nil || t.Kind() != reflect.Ptr {
+		return 0
+	}
+	m, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok || proto.Unmarshal(keyData.Value, m) != nil {
+		return 0
+	}
+	return keyValueSize(reflect.ValueOf(m).Elem())
+}
+
+// keyValueSize returns the length of the key_value field of the key proto in
+// v, or of its n field for RSA keys. If there is neither, it looks into the
+// nested messages of v, such as the public key of RSA private keys.
+func keyValueSize(v reflect.Value) int {
+	for _, name := range []string{"key_value", "n"} {
+		if f, ok := protoField(v, name); ok && f.Kind() == reflect.Slice {
+			b, _ := f.Interface().([]byte)
+			// Moduli may be encoded with leading zeros.
+			for name == "n" && len(b) > 0 && b[0] == 0 {
+				b = b[1:]
+			}
+			if len(b) > 0 {
+				return len(b)
+			}
+		}
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if _, ok := v.Type().Field(i).Tag.Lookup("protobuf"); !ok {
+			continue
+		}
+		if f.Kind() == reflect.Ptr && !f.IsNil() && f.Elem().Kind() == reflect.Struct {
+			if size := keyValueSize(f.Elem()); size > 0 {
+				return size
+			}
+		}
+	}
+	return 0
+}
+
+// protoField returns the field of the generated proto struct v with the given
+// proto field name.
+func protoField(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("protobuf")
+		for _, part := range strings.Split(tag, ",") {
+			if part == "name="+name {
+				return v.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestInspect(t *testing.T) {
+	m := keyset.NewManager()
+	for _, template := range []*tinkpb.KeyTemplate{
+		aead.AES128GCMKeyTemplate(),
+		mac.HMACSHA256Tag256KeyTemplate(),
+		signature.RSASSAPKCS13072SHA256F4KeyTemplate(),
+		signature.ED25519KeyWithoutPrefixTemplate(),
+	} {
+		if err := m.Rotate(template); err != nil {
+			t.Fatalf("m.Rotate() failed: %v", err)
+		}
+	}
+	h, _ := m.Handle()
+	r := keyset.Inspect(h)
+	if r.PrimaryKeyID != h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("r.PrimaryKeyID = %d, want %d", r.PrimaryKeyID, h.KeysetInfo().PrimaryKeyId)
+	}
+	want := []struct {
+		algorithm  string
+		keySize    int
+		prefixType tinkpb.OutputPrefixType
+		primary    bool
+	}{
+		{"AesGcmKey", 16, tinkpb.OutputPrefixType_TINK, false},
+		{"HmacKey", 32, tinkpb.OutputPrefixType_TINK, false},
+		{"RsaSsaPkcs1PrivateKey", 384, tinkpb.OutputPrefixType_TINK, false},
+		{"Ed25519PrivateKey", 32, tinkpb.OutputPrefixType_RAW, true},
+	}
+	if len(r.Keys) != len(want) {
+		t.Fatalf("len(r.Keys) = %d, want %d", len(r.Keys), len(want))
+	}
+	for i, w := range want {
+		k := r.Keys[i]
+		if k.Algorithm != w.algorithm || k.KeySize != w.keySize || k.OutputPrefixType != w.prefixType || k.Primary != w.primary {
+			t.Errorf("r.Keys[%d] = %+v, want algorithm %s, key size %d, prefix %s, primary %t", i, k, w.algorithm, w.keySize, w.prefixType, w.primary)
+		}
+		if k.KeyID != h.KeysetInfo().KeyInfo[i].KeyId || k.Status != tinkpb.KeyStatusType_ENABLED {
+			t.Errorf("r.Keys[%d] = %+v, want key ID %d and status ENABLED", i, k, h.KeysetInfo().KeyInfo[i].KeyId)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	m := keyset.NewManager()
+	var keyIDs []uint32
+	for i := 0; i < 3; i++ {
+		keyID, err := m.Add(mac.HMACSHA256Tag256KeyTemplate())
+		if err != nil {
+			t.Fatalf("m.Add() failed: %v", err)
+		}
+		keyIDs = append(keyIDs, keyID)
+	}
+	if err := m.SetPrimary(keyIDs[0]); err != nil {
+		t.Fatalf("m.SetPrimary() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	old, err := h.Subset(keyIDs...)
+	if err != nil {
+		t.Fatalf("h.Subset() failed: %v", err)
+	}
+
+	if err := m.Rotate(mac.HMACSHA256Tag256KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	if err := m.Disable(keyIDs[1]); err != nil {
+		t.Fatalf("m.Disable() failed: %v", err)
+	}
+	if err := m.Delete(keyIDs[2]); err != nil {
+		t.Fatalf("m.Delete() failed: %v", err)
+	}
+	h, _ = m.Handle()
+	newPrimary := h.KeysetInfo().PrimaryKeyId
+
+	d := keyset.Diff(old, h)
+	if d.OldPrimaryKeyID != keyIDs[0] || d.NewPrimaryKeyID != newPrimary {
+		t.Errorf("primary key IDs = %d, %d, want %d, %d", d.OldPrimaryKeyID, d.NewPrimaryKeyID, keyIDs[0], newPrimary)
+	}
+	if len(d.Added) != 1 || d.Added[0].KeyID != newPrimary || !d.Added[0].Primary {
+		t.Errorf("d.Added = %+v, want the new primary key %d", d.Added, newPrimary)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].KeyID != keyIDs[2] {
+		t.Errorf("d.Removed = %+v, want key %d", d.Removed, keyIDs[2])
+	}
+	wantChange := keyset.KeyStatusChange{KeyID: keyIDs[1], OldStatus: tinkpb.KeyStatusType_ENABLED, NewStatus: tinkpb.KeyStatusType_DISABLED}
+	if len(d.StatusChanged) != 1 || d.StatusChanged[0] != wantChange {
+		t.Errorf("d.StatusChanged = %+v, want %+v", d.StatusChanged, wantChange)
+	}
+
+	d = keyset.Diff(h, h)
+	if len(d.Added) != 0 || len(d.Removed) != 0 || len(d.StatusChanged) != 0 {
+		t.Errorf("keyset.Diff(h, h) = %+v, want no changes", d)
+	}
+}
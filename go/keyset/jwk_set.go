@@ -0,0 +1,412 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	rsassapkcs1pb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// The type URLs of the public signature keys that can be converted to JWKs.
+// The key managers are registered by the signature package.
+const (
+	jwkECDSATypeURL       = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
+	jwkED25519TypeURL     = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+	jwkRSASSAPKCS1TypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
+	jwkRSASSAPSSTypeURL   = "type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey"
+)
+
+// jwk is a JSON Web Key (RFC 7517) holding a public signature key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// jwkSet is a JSON Web Key Set (RFC 7517, section 5).
+type jwkSet struct {
+	Keys []*jwk `json:"keys"`
+}
+
+// jwkECDSAParams are the ECDSA parameters of a JWS algorithm (RFC 7518,
+// section 3.4).
+type jwkECDSAParams struct {
+	alg   string
+	crv   string
+	curve commonpb.EllipticCurveType
+	hash  commonpb.HashType
+	// size is the length of the encoded coordinates in bytes.
+	size int
+}
+
+var jwkECDSAAlgorithms = []jwkECDSAParams{
+	{"ES256", "P-256", commonpb.EllipticCurveType_NIST_P256, commonpb.HashType_SHA256, 32},
+	{"ES384", "P-384", commonpb.EllipticCurveType_NIST_P384, commonpb.HashType_SHA384, 48},
+	{"ES512", "P-521", commonpb.EllipticCurveType_NIST_P521, commonpb.HashType_SHA512, 66},
+}
+
+// jwkRSAHashes maps hash functions to the suffix of the JWS algorithm names of
+// RSA keys, and to the PSS salt length used by the PS algorithms.
+var jwkRSAHashes = []struct {
+	suffix     string
+	hash       commonpb.HashType
+	saltLength int32
+}{
+	{"256", commonpb.HashType_SHA256, 32},
+	{"384", commonpb.HashType_SHA384, 48},
+	{"512", commonpb.HashType_SHA512, 64},
+}
+
+// PublicJWKSet returns the enabled public keys of h as a JSON Web Key Set
+// (RFC 7517), e.g. to be published at a JWKS endpoint. h may contain either
+// public or private keys. The key IDs are exported as "kid" values, which
+// NewHandleFromPublicJWKSet converts back. The key managers of the keys must
+// be registered, e.g. by importing the signature package.
+//
+// Keys must have output prefix RAW, since verifiers outside of Tink do not
+// expect a prefix on signatures, and must be one of:
+//   - ECDSA with IEEE P1363 encoding and the hash function of the curve
+//     (ES256, ES384, ES512),
+//   - ED25519 without pre-hashing or context (EdDSA),
+//   - RSA-SSA-PKCS1 with SHA256, SHA384 or SHA512 (RS256, RS384, RS512),
+//   - RSA-SSA-PSS with the same hash function for signatures and MGF1, and a
+//     salt as long as the hash (PS256, PS384, PS512).
+func PublicJWKSet(h *Handle) ([]byte, error) {
+	if h == nil {
+		return nil, errors.New("keyset.PublicJWKSet: keyset handle must not be nil")
+	}
+	pub := h
+	if h.hasSecrets() {
+		var err error
+		if pub, err = h.Public(); err != nil {
+			return nil, fmt.Errorf("keyset.PublicJWKSet: cannot obtain public keyset: %s", err)
+		}
+	}
+	set := &jwkSet{Keys: []*jwk{}}
+	for _, k := range pub.ks.Key {
+		if k.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+		if k.OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+			return nil, fmt.Errorf("keyset.PublicJWKSet: cannot export key %d with output prefix %s as JWK, only RAW keys are supported", k.KeyId, k.OutputPrefixType)
+		}
+		j, err := keyDataToJWK(k.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("keyset.PublicJWKSet: cannot export key %d as JWK: %s", k.KeyId, err)
+		}
+		j.Kid = keyIDToKid(k.KeyId)
+		j.Use = "sig"
+		set.Keys = append(set.Keys, j)
+	}
+	return json.Marshal(set)
+}
+
+// NewHandleFromPublicJWKSet returns a keyset handle with the public keys in
+// the given JSON Web Key Set, such as one published at a JWKS endpoint. It
+// supports the same keys as PublicJWKSet. The keys are enabled and have
+// output prefix RAW. Their key IDs are taken from "kid" values written by
+// PublicJWKSet, and chosen randomly for other "kid" values.
+func NewHandleFromPublicJWKSet(jwks []byte) (*Handle, error) {
+	set := new(jwkSet)
+	if err := json.Unmarshal(jwks, set); err != nil {
+		return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: invalid JWK set: %s", err)
+	}
+	if len(set.Keys) == 0 {
+		return nil, errors.New("keyset.NewHandleFromPublicJWKSet: JWK set contains no keys")
+	}
+	ks := &tinkpb.Keyset{}
+	usedIDs := make(map[uint32]bool)
+	for i, j := range set.Keys {
+		if j == nil {
+			return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: invalid JWK %d", i)
+		}
+		if j.Use != "" && j.Use != "sig" {
+			return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: JWK %d has use %q, want \"sig\"", i, j.Use)
+		}
+		keyData, err := jwkToKeyData(j)
+		if err != nil {
+			return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: cannot import JWK %d: %s", i, err)
+		}
+		if _, err := registry.PrimitiveFromKeyData(keyData); err != nil {
+			return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: cannot import JWK %d: %s", i, err)
+		}
+		keyID, ok := kidToKeyID(j.Kid)
+		if ok && usedIDs[keyID] {
+			return nil, fmt.Errorf("keyset.NewHandleFromPublicJWKSet: duplicate kid %q", j.Kid)
+		}
+		for !ok || usedIDs[keyID] {
+			keyID, ok = random.GetRandomUint32(), true
+		}
+		usedIDs[keyID] = true
+		ks.Key = append(ks.Key, &tinkpb.Keyset_Key{
+			KeyData:          keyData,
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            keyID,
+			OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+		})
+	}
+	ks.PrimaryKeyId = ks.Key[0].KeyId
+	return NewHandleWithNoSecrets(ks)
+}
+
+// keyIDToKid returns the "kid" of the key with the given ID, which is the
+// base64url encoding of the big-endian key ID.
+func keyIDToKid(keyID uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], keyID)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// kidToKeyID returns the key ID encoded in kid by keyIDToKid, if any.
+func kidToKeyID(kid string) (uint32, bool) {
+	b, err := base64.RawURLEncoding.DecodeString(kid)
+	if err != nil || len(b) != 4 {
+		return 0, false
+	}
+	keyID := binary.BigEndian.Uint32(b)
+	return keyID, keyID != 0
+}
+
+// keyDataToJWK converts the public key in keyData to a JWK, without "kid" and
+// "use". The key is validated by its registered key manager.
+func keyDataToJWK(keyData *tinkpb.KeyData) (*jwk, error) {
+	if keyData == nil {
+		return nil, errors.New("missing key data")
+	}
+	switch keyData.TypeUrl {
+	case jwkECDSATypeURL, jwkED25519TypeURL, jwkRSASSAPKCS1TypeURL, jwkRSASSAPSSTypeURL:
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", keyData.TypeUrl)
+	}
+	if _, err := registry.PrimitiveFromKeyData(keyData); err != nil {
+		return nil, err
+	}
+	switch keyData.TypeUrl {
+	case jwkECDSATypeURL:
+		key := new(ecdsapb.EcdsaPublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errors.New("invalid ECDSA public key")
+		}
+		if key.GetParams().GetEncoding() != ecdsapb.EcdsaSignatureEncoding_IEEE_P1363 {
+			return nil, fmt.Errorf("unsupported signature encoding %s", key.GetParams().GetEncoding())
+		}
+		for _, p := range jwkECDSAAlgorithms {
+			if p.curve == key.Params.Curve && p.hash == key.Params.HashType {
+				return &jwk{
+					Kty: "EC",
+					Alg: p.alg,
+					Crv: p.crv,
+					X:   base64.RawURLEncoding.EncodeToString(new(big.Int).SetBytes(key.X).FillBytes(make([]byte, p.size))),
+					Y:   base64.RawURLEncoding.EncodeToString(new(big.Int).SetBytes(key.Y).FillBytes(make([]byte, p.size))),
+				}, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported curve %s with hash %s", key.Params.Curve, key.Params.HashType)
+	case jwkED25519TypeURL:
+		key := new(ed25519pb.Ed25519PublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errors.New("invalid ED25519 public key")
+		}
+		if key.GetParams().GetVariant() != ed25519pb.Ed25519Variant_ED25519 {
+			return nil, fmt.Errorf("unsupported ED25519 variant %s", key.GetParams().GetVariant())
+		}
+		return &jwk{
+			Kty: "OKP",
+			Alg: "EdDSA",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key.KeyValue),
+		}, nil
+	case jwkRSASSAPKCS1TypeURL:
+		key := new(rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errors.New("invalid RSA-SSA-PKCS1 public key")
+		}
+		for _, h := range jwkRSAHashes {
+			if h.hash == key.GetParams().GetHashType() {
+				return newRSAJWK("RS"+h.suffix, key.N, key.E), nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported hash %s", key.GetParams().GetHashType())
+	default:
+		key := new(rsassapsspb.RsaSsaPssPublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errors.New("invalid RSA-SSA-PSS public key")
+		}
+		params := key.GetParams()
+		for _, h := range jwkRSAHashes {
+			if h.hash == params.GetSigHash() && h.hash == params.GetMgf1Hash() && h.saltLength == params.GetSaltLength() {
+				return newRSAJWK("PS"+h.suffix, key.N, key.E), nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported parameters %s", params)
+	}
+}
+
+func newRSAJWK(alg string, n, e []byte) *jwk {
+	return &jwk{
+		Kty: "RSA",
+		Alg: alg,
+		N:   base64.RawURLEncoding.EncodeToString(new(big.Int).SetBytes(n).Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(new(big.Int).SetBytes(e).Bytes()),
+	}
+}
+
+// jwkCurve returns the elliptic curve with the given type.
+func jwkCurve(curve commonpb.EllipticCurveType) elliptic.Curve {
+	switch curve {
+	case commonpb.EllipticCurveType_NIST_P256:
+		return elliptic.P256()
+	case commonpb.EllipticCurveType_NIST_P384:
+		return elliptic.P384()
+	default:
+		return elliptic.P521()
+	}
+}
+
+// jwkToKeyData converts j to the KeyData of a public key.
+func jwkToKeyData(j *jwk) (*tinkpb.KeyData, error) {
+	var typeURL string
+	var key proto.Message
+	switch j.Kty {
+	case "EC":
+		var params *jwkECDSAParams
+		for i, p := range jwkECDSAAlgorithms {
+			if p.crv == j.Crv && (j.Alg == "" || j.Alg == p.alg) {
+				params = &jwkECDSAAlgorithms[i]
+			}
+		}
+		if params == nil {
+			return nil, fmt.Errorf("unsupported curve %q with algorithm %q", j.Crv, j.Alg)
+		}
+		x, err := decodeJWKField("x", j.X, params.size)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKField("y", j.Y, params.size)
+		if err != nil {
+			return nil, err
+		}
+		if !jwkCurve(params.curve).IsOnCurve(new(big.Int).SetBytes(x), new(big.Int).SetBytes(y)) {
+			return nil, errors.New("point is not on the curve")
+		}
+		typeURL = jwkECDSATypeURL
+		key = &ecdsapb.EcdsaPublicKey{
+			Params: &ecdsapb.EcdsaParams{
+				HashType: params.hash,
+				Curve:    params.curve,
+				Encoding: ecdsapb.EcdsaSignatureEncoding_IEEE_P1363,
+			},
+			X: x,
+			Y: y,
+		}
+	case "OKP":
+		if j.Crv != "Ed25519" || (j.Alg != "" && j.Alg != "EdDSA") {
+			return nil, fmt.Errorf("unsupported curve %q with algorithm %q", j.Crv, j.Alg)
+		}
+		x, err := decodeJWKField("x", j.X, 32)
+		if err != nil {
+			return nil, err
+		}
+		typeURL = jwkED25519TypeURL
+		key = &ed25519pb.Ed25519PublicKey{KeyValue: x}
+	case "RSA":
+		n, err := decodeJWKField("n", j.N, 0)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKField("e", j.E, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(j.Alg) != 5 {
+			return nil, fmt.Errorf("unsupported algorithm %q", j.Alg)
+		}
+		for _, h := range jwkRSAHashes {
+			if j.Alg[2:] != h.suffix {
+				continue
+			}
+			switch j.Alg[:2] {
+			case "RS":
+				typeURL = jwkRSASSAPKCS1TypeURL
+				key = &rsassapkcs1pb.RsaSsaPkcs1PublicKey{
+					Params: &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: h.hash},
+					N:      n,
+					E:      e,
+				}
+			case "PS":
+				typeURL = jwkRSASSAPSSTypeURL
+				key = &rsassapsspb.RsaSsaPssPublicKey{
+					Params: &rsassapsspb.RsaSsaPssParams{
+						SigHash:    h.hash,
+						Mgf1Hash:   h.hash,
+						SaltLength: h.saltLength,
+					},
+					N: n,
+					E: e,
+				}
+			}
+		}
+		if key == nil {
+			return nil, fmt.Errorf("unsupported algorithm %q", j.Alg)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// decodeJWKField decodes the base64url encoded field. If size is not zero,
+// the decoded value must be exactly size bytes long.
+func decodeJWKField(name, value string, size int) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid %q", name)
+	}
+	if size != 0 && len(b) != size {
+		return nil, fmt.Errorf("invalid %q length %d, want %d", name, len(b), size)
+	}
+	return b, nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+type testJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type testJWKSet struct {
+	Keys []testJWK `json:"keys"`
+}
+
+func ecdsaP1363KeyTemplate(hashType commonpb.HashType, curve commonpb.EllipticCurveType, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &ecdsapb.EcdsaKeyFormat{
+		Params: &ecdsapb.EcdsaParams{
+			HashType: hashType,
+			Curve:    curve,
+			Encoding: ecdsapb.EcdsaSignatureEncoding_IEEE_P1363,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey",
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+func TestPublicJWKSetRoundTrip(t *testing.T) {
+	data := []byte("data to be signed")
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		kty, alg string
+	}{
+		{"ES256", ecdsaP1363KeyTemplate(commonpb.HashType_SHA256, commonpb.EllipticCurveType_NIST_P256, tinkpb.OutputPrefixType_RAW), "EC", "ES256"},
+		{"ES384", ecdsaP1363KeyTemplate(commonpb.HashType_SHA384, commonpb.EllipticCurveType_NIST_P384, tinkpb.OutputPrefixType_RAW), "EC", "ES384"},
+		{"ES512", ecdsaP1363KeyTemplate(commonpb.HashType_SHA512, commonpb.EllipticCurveType_NIST_P521, tinkpb.OutputPrefixType_RAW), "EC", "ES512"},
+		{"EdDSA", signature.ED25519KeyWithoutPrefixTemplate(), "OKP", "EdDSA"},
+		{"RS256", signature.RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate(), "RSA", "RS256"},
+		{"PS256", signature.RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate(), "RSA", "PS256"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			jwks, err := keyset.PublicJWKSet(kh)
+			if err != nil {
+				t.Fatalf("keyset.PublicJWKSet() err = %v", err)
+			}
+			set := new(testJWKSet)
+			if err := json.Unmarshal(jwks, set); err != nil {
+				t.Fatalf("json.Unmarshal() err = %v", err)
+			}
+			if len(set.Keys) != 1 {
+				t.Fatalf("len(set.Keys) = %d, want 1", len(set.Keys))
+			}
+			if k := set.Keys[0]; k.Kty != tc.kty || k.Alg != tc.alg || k.Use != "sig" || k.Kid == "" {
+				t.Errorf("JWK = %+v, want kty %q, alg %q, use \"sig\" and a kid", k, tc.kty, tc.alg)
+			}
+
+			imported, err := keyset.NewHandleFromPublicJWKSet(jwks)
+			if err != nil {
+				t.Fatalf("keyset.NewHandleFromPublicJWKSet() err = %v", err)
+			}
+			if got, want := imported.KeysetInfo().GetKeyInfo()[0].GetKeyId(), kh.KeysetInfo().GetPrimaryKeyId(); got != want {
+				t.Errorf("imported key ID = %d, want %d", got, want)
+			}
+			signer, err := signature.NewSigner(kh)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v", err)
+			}
+			sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			verifier, err := signature.NewVerifier(imported)
+			if err != nil {
+				t.Fatalf("signature.NewVerifier() err = %v", err)
+			}
+			if err := verifier.Verify(sig, data); err != nil {
+				t.Errorf("verifier.Verify() err = %v", err)
+			}
+
+			// Exporting the imported keyset gives the same JWK set.
+			reexported, err := keyset.PublicJWKSet(imported)
+			if err != nil {
+				t.Fatalf("keyset.PublicJWKSet() err = %v", err)
+			}
+			if string(reexported) != string(jwks) {
+				t.Errorf("keyset.PublicJWKSet() = %s, want %s", reexported, jwks)
+			}
+		})
+	}
+}
+
+func decodeTestJWKField(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("base64.RawURLEncoding.DecodeString(%q) err = %v", s, err)
+	}
+	return b
+}
+
+func TestPublicJWKSetVerifiesOutsideTink(t *testing.T) {
+	data := []byte("data to be signed")
+	manager := keyset.NewManager()
+	if err := manager.Rotate(signature.ED25519KeyWithoutPrefixTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() err = %v", err)
+	}
+	edHandle, _ := manager.Handle()
+	edSigner, err := signature.NewSigner(edHandle)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v", err)
+	}
+	edSig, err := edSigner.Sign(data)
+	if err != nil {
+		t.Fatalf("edSigner.Sign() err = %v", err)
+	}
+	if err := manager.Rotate(ecdsaP1363KeyTemplate(commonpb.HashType_SHA256, commonpb.EllipticCurveType_NIST_P256, tinkpb.OutputPrefixType_RAW)); err != nil {
+		t.Fatalf("manager.Rotate() err = %v", err)
+	}
+	h, _ := manager.Handle()
+	ecSigner, err := signature.NewSigner(h)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v", err)
+	}
+	ecSig, err := ecSigner.Sign(data)
+	if err != nil {
+		t.Fatalf("ecSigner.Sign() err = %v", err)
+	}
+
+	jwks, err := keyset.PublicJWKSet(h)
+	if err != nil {
+		t.Fatalf("keyset.PublicJWKSet() err = %v", err)
+	}
+	set := new(testJWKSet)
+	if err := json.Unmarshal(jwks, set); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v", err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("len(set.Keys) = %d, want 2", len(set.Keys))
+	}
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "OKP":
+			if !ed25519.Verify(decodeTestJWKField(t, k.X), data, edSig) {
+				t.Error("ED25519 signature does not verify with the exported JWK")
+			}
+		case "EC":
+			pub := &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(decodeTestJWKField(t, k.X)),
+				Y:     new(big.Int).SetBytes(decodeTestJWKField(t, k.Y)),
+			}
+			digest := sha256.Sum256(data)
+			r := new(big.Int).SetBytes(ecSig[:32])
+			s := new(big.Int).SetBytes(ecSig[32:])
+			if !ecdsa.Verify(pub, digest[:], r, s) {
+				t.Error("ECDSA signature does not verify with the exported JWK")
+			}
+		default:
+			t.Errorf("unexpected JWK %+v", k)
+		}
+	}
+}
+
+func TestPublicJWKSetFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"TINK prefix", signature.ED25519KeyTemplate()},
+		{"DER encoding", signature.ECDSAP256KeyWithoutPrefixTemplate()},
+		{"ED25519ph", signature.ED25519PhKeyTemplate()},
+		{"ED448", signature.ED448KeyWithoutPrefixTemplate()},
+		{"MAC", mac.HMACSHA256Tag256KeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.template.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+			if tc.name == "TINK prefix" {
+				tc.template.OutputPrefixType = tinkpb.OutputPrefixType_TINK
+			}
+			kh, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			if _, err := keyset.PublicJWKSet(kh); err == nil {
+				t.Error("keyset.PublicJWKSet() err = nil, want error")
+			}
+		})
+	}
+	if _, err := keyset.PublicJWKSet(nil); err == nil {
+		t.Error("keyset.PublicJWKSet(nil) err = nil, want error")
+	}
+}
+
+func TestNewHandleFromPublicJWKSet(t *testing.T) {
+	// RFC 7517, appendix A.1, with the RSA key removed.
+	jwks := `{"keys": [{"kty": "EC", "crv": "P-256",
+		"x": "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		"y": "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+		"use": "sig", "kid": "1"}]}`
+	h, err := keyset.NewHandleFromPublicJWKSet([]byte(jwks))
+	if err != nil {
+		t.Fatalf("keyset.NewHandleFromPublicJWKSet() err = %v", err)
+	}
+	info := h.KeysetInfo().GetKeyInfo()
+	if len(info) != 1 || info[0].GetKeyId() == 0 || info[0].GetOutputPrefixType() != tinkpb.OutputPrefixType_RAW {
+		t.Errorf("KeysetInfo = %v, want one RAW key with a random key ID", h.KeysetInfo())
+	}
+	if _, err := signature.NewVerifier(h); err != nil {
+		t.Errorf("signature.NewVerifier() err = %v", err)
+	}
+}
+
+func TestNewHandleFromPublicJWKSetFailures(t *testing.T) {
+	const x = "MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"
+	const y = "4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM"
+	for _, tc := range []struct {
+		name string
+		jwks string
+	}{
+		{"invalid JSON", `{"keys": [`},
+		{"no keys", `{"keys": []}`},
+		{"null key", `{"keys": [null]}`},
+		{"unknown kty", `{"keys": [{"kty": "oct", "k": "AAAA"}]}`},
+		{"encryption key", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "` + x + `", "y": "` + y + `", "use": "enc"}]}`},
+		{"wrong alg", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "` + x + `", "y": "` + y + `", "alg": "ES384"}]}`},
+		{"point not on curve", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "` + x + `", "y": "` + x + `"}]}`},
+		{"short coordinate", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AAAA", "y": "` + y + `"}]}`},
+		{"unknown curve", `{"keys": [{"kty": "OKP", "crv": "X25519", "x": "` + x + `"}]}`},
+		{"RSA without alg", `{"keys": [{"kty": "RSA", "n": "` + x + `", "e": "AQAB"}]}`},
+		{"duplicate kid", `{"keys": [
+			{"kty": "EC", "crv": "P-256", "x": "` + x + `", "y": "` + y + `", "kid": "AAAAAQ"},
+			{"kty": "EC", "crv": "P-256", "x": "` + x + `", "y": "` + y + `", "kid": "AAAAAQ"}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := keyset.NewHandleFromPublicJWKSet([]byte(tc.jwks)); err == nil {
+				t.Error("keyset.NewHandleFromPublicJWKSet() err = nil, want error")
+			}
+		})
+	}
+}
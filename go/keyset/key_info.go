@@ -0,0 +1,198 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// PrimaryKeyInfo returns the KeyInfo of the primary key of h.
+func (h *Handle) PrimaryKeyInfo() (*tinkpb.KeysetInfo_KeyInfo, error) {
+	return h.KeyInfo(h.ks.GetPrimaryKeyId())
+}
+
+// KeyInfo returns the KeyInfo of the key with the given ID. It does not
+// contain any key material.
+func (h *Handle) KeyInfo(keyID uint32) (*tinkpb.KeysetInfo_KeyInfo, error) {
+	key, err := h.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return getKeyInfo(key), nil
+}
+
+// TemplateFor returns a key template which generates keys with the same
+// parameters as the key with the given ID, such as its key size, hash
+// function or curve, and with the same output prefix type. This is useful
+// for adding rotation keys which are compatible with existing ones.
+//
+// Key templates are not stored in keysets, so TemplateFor recovers the key
+// format from the key itself, on a best-effort basis. It fails for destroyed
+// keys, public keys, key types whose protos are not linked into the binary,
+// and key formats which cannot be recovered from their keys, such as those
+// of PRF-based deriver keys.
+func (h *Handle) TemplateFor(keyID uint32) (*tinkpb.KeyTemplate, error) {
+	key, err := h.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	keyData := key.GetKeyData()
+	if key.Status == tinkpb.KeyStatusType_DESTROYED || len(keyData.GetValue()) == 0 {
+		return nil, fmt.Errorf("keyset.Handle: key %d has no key material", keyID)
+	}
+	if !strings.HasPrefix(keyData.GetTypeUrl(), typeURLPrefix) {
+		return nil, fmt.Errorf("keyset.Handle: unsupported type URL %q", keyData.GetTypeUrl())
+	}
+	name := strings.TrimPrefix(keyData.TypeUrl, typeURLPrefix)
+	keyType, formatType := proto.MessageType(name), proto.MessageType(keyFormatName(name))
+	if keyType == nil || formatType == nil || keyType.Kind() != reflect.Ptr || formatType.Kind() != reflect.Ptr {
+		return nil, fmt.Errorf("keyset.Handle: cannot recover the key format of %s keys", name)
+	}
+	k, ok := reflect.New(keyType.Elem()).Interface().(proto.Message)
+	if !ok || proto.Unmarshal(keyData.Value, k) != nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid key %d", keyID)
+	}
+	format := reflect.New(formatType.Elem())
+	if err := fillKeyFormat(format.Elem(), reflect.ValueOf(k).Elem()); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: cannot recover the key format of %s keys: %v", name, err)
+	}
+	serializedFormat, err := proto.Marshal(format.Interface().(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          keyData.TypeUrl,
+		Value:            serializedFormat,
+		OutputPrefixType: key.OutputPrefixType,
+	}, nil
+}
+
+// key returns the key with the given key ID.
+func (h *Handle) key(keyID uint32) (*tinkpb.Keyset_Key, error) {
+	for _, key := range h.ks.GetKey() {
+		if key.GetKeyId() == keyID {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("keyset.Handle: key %d not found", keyID)
+}
+
+// keyFormatName returns the name of the key format proto of the key proto
+// with the given name, e.g. "google.crypto.tink.EcdsaKeyFormat" for
+// "google.crypto.tink.EcdsaPrivateKey".
+func keyFormatName(keyName string) string {
+	if strings.HasSuffix(keyName, "PrivateKey") {
+		return strings.TrimSuffix(keyName, "PrivateKey") + "KeyFormat"
+	}
+	return keyName + "Format"
+}
+
+// fillKeyFormat sets the fields of the key format proto struct format from
+// the key proto struct key. Key formats mostly consist of the params of the
+// key, which private keys hold in their public key, and of key sizes.
+func fillKeyFormat(format, key reflect.Value) error {
+	// Some keys, such as KMS envelope keys, hold their key format as params.
+	if p, ok := protoField(key, "params"); ok && p.Type() == reflect.PtrTo(format.Type()) && !p.IsNil() {
+		format.Set(reflect.ValueOf(proto.Clone(p.Interface().(proto.Message))).Elem())
+		return nil
+	}
+	var publicKey reflect.Value
+	if p, ok := protoField(key, "public_key"); ok && p.Kind() == reflect.Ptr && !p.IsNil() {
+		publicKey = p.Elem()
+	}
+	lookup := func(name string) (reflect.Value, bool) {
+		if f, ok := protoField(key, name); ok {
+			return f, true
+		}
+		if publicKey.IsValid() {
+			return protoField(publicKey, name)
+		}
+		return reflect.Value{}, false
+	}
+	for i := 0; i < format.NumField(); i++ {
+		name := protoFieldName(format.Type().Field(i).Tag.Get("protobuf"))
+		if name == "" || name == "version" {
+			continue
+		}
+		f := format.Field(i)
+		src, ok := lookup(strings.TrimSuffix(name, "_format"))
+		switch name {
+		case "key_size":
+			src, ok = protoField(key, "key_value")
+			if !ok || src.Kind() != reflect.Slice {
+				return fmt.Errorf("no key value")
+			}
+			f.SetUint(uint64(src.Len()))
+		case "modulus_size_in_bits":
+			src, ok = lookup("n")
+			b, _ := src.Interface().([]byte)
+			if !ok || len(b) == 0 {
+				return fmt.Errorf("no modulus")
+			}
+			f.SetUint(uint64(new(big.Int).SetBytes(b).BitLen()))
+		case "public_exponent":
+			src, ok = lookup("e")
+			if !ok || src.Type() != f.Type() {
+				return fmt.Errorf("no public exponent")
+			}
+			f.Set(reflect.ValueOf(append([]byte(nil), src.Bytes()...)))
+		default:
+			if !ok && name == "params" {
+				// The key type has optional params, such as Ed25519 keys.
+				continue
+			}
+			if !ok || f.Kind() != reflect.Ptr || src.Kind() != reflect.Ptr {
+				return fmt.Errorf("cannot recover field %s", name)
+			}
+			if src.IsNil() {
+				continue
+			}
+			if src.Type() == f.Type() {
+				f.Set(reflect.ValueOf(proto.Clone(src.Interface().(proto.Message))))
+				continue
+			}
+			// Formats of keys which consist of other keys, such as AES-CTR-HMAC
+			// AEAD keys, hold the formats of these keys.
+			if !strings.HasSuffix(name, "_key_format") || f.Type().Elem().Kind() != reflect.Struct {
+				return fmt.Errorf("cannot recover field %s", name)
+			}
+			v := reflect.New(f.Type().Elem())
+			if err := fillKeyFormat(v.Elem(), src.Elem()); err != nil {
+				return err
+			}
+			f.Set(v)
+		}
+	}
+	return nil
+}
+
+// protoFieldName returns the proto field name in the given protobuf struct
+// tag, or "" if there is none.
+func protoFieldName(tag string) string {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.HasPrefix(part, "name=") {
+			return strings.TrimPrefix(part, "name=")
+		}
+	}
+	return ""
+}
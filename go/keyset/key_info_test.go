@@ -0,0 +1,154 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/daead"
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/prf"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/streamingaead"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestKeyInfo(t *testing.T) {
+	m := keyset.NewManager()
+	keyID, err := m.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	if err := m.Rotate(aead.AES128GCMKeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	info := h.KeysetInfo()
+
+	primary, err := h.PrimaryKeyInfo()
+	if err != nil {
+		t.Fatalf("h.PrimaryKeyInfo() failed: %v", err)
+	}
+	if !proto.Equal(primary, info.KeyInfo[1]) {
+		t.Errorf("h.PrimaryKeyInfo() = %v, want %v", primary, info.KeyInfo[1])
+	}
+	got, err := h.KeyInfo(keyID)
+	if err != nil {
+		t.Fatalf("h.KeyInfo() failed: %v", err)
+	}
+	if !proto.Equal(got, info.KeyInfo[0]) {
+		t.Errorf("h.KeyInfo(%d) = %v, want %v", keyID, got, info.KeyInfo[0])
+	}
+	if _, err := h.KeyInfo(keyID + 1); err == nil {
+		t.Error("h.KeyInfo() with an unknown key ID succeeded, want error")
+	}
+	empty, _ := keyset.NewManager().Handle()
+	if _, err := empty.PrimaryKeyInfo(); err == nil {
+		t.Error("h.PrimaryKeyInfo() of an empty keyset succeeded, want error")
+	}
+}
+
+func TestTemplateFor(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"AES128GCM", aead.AES128GCMKeyTemplate()},
+		{"AES256GCMNoPrefix", aead.AES256GCMNoPrefixKeyTemplate()},
+		{"AES256GCMSIV", aead.AES256GCMSIVKeyTemplate()},
+		{"AES256CTRHMACSHA256", aead.AES256CTRHMACSHA256KeyTemplate()},
+		{"ChaCha20Poly1305", aead.ChaCha20Poly1305KeyTemplate()},
+		{"XChaCha20Poly1305", aead.XChaCha20Poly1305KeyTemplate()},
+		{"XAES256GCM", aead.XAES256GCMKeyTemplate()},
+		{"Ascon128a", aead.Ascon128aKeyTemplate()},
+		{"AESSIV", daead.AESSIVKeyTemplate()},
+		{"HMACSHA512Tag256", mac.HMACSHA512Tag256KeyTemplate()},
+		{"AESCMACTag128", mac.AESCMACTag128KeyTemplate()},
+		{"KMAC128Tag256", mac.KMAC128Tag256KeyTemplate()},
+		{"SipHash24Tag64", mac.SipHash24Tag64KeyTemplate()},
+		{"Poly1305", mac.Poly1305KeyTemplate()},
+		{"AES256GMAC", mac.AES256GMACKeyTemplate()},
+		{"ECDSAP384", signature.ECDSAP384KeyTemplate()},
+		{"ECDSAP256Deterministic", signature.ECDSAP256DeterministicKeyTemplate()},
+		{"ED25519", signature.ED25519KeyTemplate()},
+		{"ED25519Ph", signature.ED25519PhKeyTemplate()},
+		{"ED448", signature.ED448KeyTemplate()},
+		{"RSASSAPKCS13072SHA256F4", signature.RSASSAPKCS13072SHA256F4KeyTemplate()},
+		{"RSASSAPSS3072SHA256SHA25632F4", signature.RSASSAPSS3072SHA256SHA25632F4KeyTemplate()},
+		{"SLHDSASHA2128F", signature.SLHDSASHA2128FKeyTemplate()},
+		{"ECIESHKDFAES128CTRHMACSHA256", hybrid.ECIESHKDFAES128CTRHMACSHA256KeyTemplate()},
+		{"ECIESX25519HKDFAES256GCM", hybrid.ECIESX25519HKDFAES256GCMKeyTemplate()},
+		{"DHKEMX25519HKDFSHA256HKDFSHA256AES128GCM", hybrid.DHKEMX25519HKDFSHA256HKDFSHA256AES128GCMKeyTemplate()},
+		{"AES128GCMHKDF4KB", streamingaead.AES128GCMHKDF4KBKeyTemplate()},
+		{"AES256CTRHMACSHA256Segment1MB", streamingaead.AES256CTRHMACSHA256Segment1MBKeyTemplate()},
+		{"ChaCha20Poly1305HKDF4KB", streamingaead.ChaCha20Poly1305HKDF4KBKeyTemplate()},
+		{"HMACSHA512PRF", prf.HMACSHA512PRFKeyTemplate()},
+		{"HKDFSHA256PRF", prf.HKDFSHA256PRFKeyTemplate()},
+		{"AESCMACPRF", prf.AESCMACPRFKeyTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() failed: %v", err)
+			}
+			got, err := h.TemplateFor(h.KeysetInfo().PrimaryKeyId)
+			if err != nil {
+				t.Fatalf("h.TemplateFor() failed: %v", err)
+			}
+			if !proto.Equal(got, tc.template) {
+				t.Errorf("h.TemplateFor() = %v, want %v", got, tc.template)
+			}
+		})
+	}
+}
+
+func TestTemplateForFailures(t *testing.T) {
+	m := keyset.NewManager()
+	keyID, err := m.Add(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("m.Add() failed: %v", err)
+	}
+	if err := m.Rotate(signature.ECDSAP256KeyTemplate()); err != nil {
+		t.Fatalf("m.Rotate() failed: %v", err)
+	}
+	if err := m.Destroy(keyID); err != nil {
+		t.Fatalf("m.Destroy() failed: %v", err)
+	}
+	h, _ := m.Handle()
+	if _, err := h.TemplateFor(keyID); err == nil {
+		t.Error("h.TemplateFor() of a destroyed key succeeded, want error")
+	}
+	if _, err := h.TemplateFor(keyID + 1); err == nil {
+		t.Error("h.TemplateFor() with an unknown key ID succeeded, want error")
+	}
+	h, err = keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	pub, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() failed: %v", err)
+	}
+	if _, err := pub.TemplateFor(pub.KeysetInfo().PrimaryKeyId); err == nil {
+		t.Error("h.TemplateFor() of a public key succeeded, want error")
+	}
+}
@@ -23,16 +23,16 @@ import (
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
-// keysetHandle is used by package insecurecleartextkeyset and package
-// testkeyset (via package internal) to create a keyset.Handle from cleartext
-// key material.
+// keysetHandle is used by package insecurecleartextkeyset, package testkeyset
+// and package keyderivation (via package internal) to create a keyset.Handle
+// from cleartext key material.
 func keysetHandle(ks *tinkpb.Keyset) *Handle {
-	return &Handle{ks}
+	return &Handle{ks: ks}
 }
 
-// keysetMaterial is used by package insecurecleartextkeyset and package
-// testkeyset (via package internal) to read the key material in a
-// keyset.Handle.
+// keysetMaterial is used by package insecurecleartextkeyset, package
+// testkeyset, package aead and package hybrid (via package internal) to read
+// the key material in a keyset.Handle.
 func keysetMaterial(h *Handle) *tinkpb.Keyset {
 	return h.ks
 }
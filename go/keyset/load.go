@@ -0,0 +1,97 @@
+//go:build go1.16
+// +build go1.16
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/google/tink/go/core/registry"
+)
+
+// LoadOption configures ReadFromFS and ReadFromEnv.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	associatedData []byte
+}
+
+// WithAssociatedData sets the associated data the keyset was encrypted with,
+// as by Handle.WriteWithAssociatedData.
+func WithAssociatedData(associatedData []byte) LoadOption {
+	return func(o *loadOptions) {
+		o.associatedData = associatedData
+	}
+}
+
+// ReadFromFS reads the encrypted keyset at path in fsys and decrypts it with
+// the key at kmsKeyURI, using the KMS client registered for it with
+// registry.RegisterKMSClient. fsys is usually an embed.FS that is compiled
+// into the binary, or os.DirFS for keysets deployed as files. The keyset may
+// be in JSON or binary format.
+func ReadFromFS(fsys fs.FS, path, kmsKeyURI string, opts ...LoadOption) (*Handle, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: cannot read keyset: %v", err)
+	}
+	return readEncrypted(data, kmsKeyURI, opts)
+}
+
+// ReadFromEnv reads the base64-encoded encrypted keyset from the environment
+// variable with the given name and decrypts it with the key at kmsKeyURI,
+// like ReadFromFS.
+func ReadFromEnv(name, kmsKeyURI string, opts ...LoadOption) (*Handle, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("keyset.Handle: environment variable %s is not set", name)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: environment variable %s is not base64: %v", name, err)
+	}
+	return readEncrypted(data, kmsKeyURI, opts)
+}
+
+func readEncrypted(data []byte, kmsKeyURI string, opts []LoadOption) (*Handle, error) {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	client, err := registry.GetKMSClient(kmsKeyURI)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: %v", err)
+	}
+	masterKey, err := client.GetAEAD(kmsKeyURI)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: cannot get master key: %v", err)
+	}
+	// Binary encrypted keysets start with a field tag, never with '{'.
+	var reader Reader
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		reader = NewJSONReader(bytes.NewReader(data))
+	} else {
+		reader = NewBinaryReader(bytes.NewReader(data))
+	}
+	return ReadWithAssociatedData(reader, masterKey, o.associatedData)
+}
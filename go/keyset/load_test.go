@@ -0,0 +1,126 @@
+//go:build go1.16
+// +build go1.16
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/testing/fakekms"
+	"github.com/google/tink/go/tink"
+)
+
+func newLoadTestKeyset(t *testing.T) (*keyset.Handle, string, tink.AEAD) {
+	t.Helper()
+	client, err := fakekms.NewClient("fake-kms://")
+	if err != nil {
+		t.Fatalf("fakekms.NewClient() failed: %v", err)
+	}
+	registry.RegisterKMSClient(client)
+	keyURI, err := fakekms.NewKeyURI()
+	if err != nil {
+		t.Fatalf("fakekms.NewKeyURI() failed: %v", err)
+	}
+	masterKey, err := client.GetAEAD(keyURI)
+	if err != nil {
+		t.Fatalf("client.GetAEAD() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	return h, keyURI, masterKey
+}
+
+func TestReadFromFS(t *testing.T) {
+	h, keyURI, masterKey := newLoadTestKeyset(t)
+	binaryKeyset, jsonKeyset, withAD := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	if err := h.Write(keyset.NewBinaryWriter(binaryKeyset), masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	if err := h.Write(keyset.NewJSONWriter(jsonKeyset), masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	if err := h.WriteWithAssociatedData(keyset.NewBinaryWriter(withAD), masterKey, []byte("service")); err != nil {
+		t.Fatalf("h.WriteWithAssociatedData() failed: %v", err)
+	}
+	fsys := fstest.MapFS{
+		"keysets/mac.bin":     {Data: binaryKeyset.Bytes()},
+		"keysets/mac.json":    {Data: jsonKeyset.Bytes()},
+		"keysets/mac_ad.bin":  {Data: withAD.Bytes()},
+		"keysets/garbage.bin": {Data: []byte("garbage")},
+	}
+	for _, tc := range []struct {
+		path string
+		opts []keyset.LoadOption
+	}{
+		{"keysets/mac.bin", nil},
+		{"keysets/mac.json", nil},
+		{"keysets/mac_ad.bin", []keyset.LoadOption{keyset.WithAssociatedData([]byte("service"))}},
+	} {
+		got, err := keyset.ReadFromFS(fsys, tc.path, keyURI, tc.opts...)
+		if err != nil {
+			t.Fatalf("keyset.ReadFromFS(%q) failed: %v", tc.path, err)
+		}
+		if got.KeysetInfo().GetPrimaryKeyId() != h.KeysetInfo().GetPrimaryKeyId() {
+			t.Errorf("keyset.ReadFromFS(%q) returned a different keyset", tc.path)
+		}
+	}
+	for _, path := range []string{"keysets/missing.bin", "keysets/garbage.bin", "keysets/mac_ad.bin"} {
+		if _, err := keyset.ReadFromFS(fsys, path, keyURI); err == nil {
+			t.Errorf("keyset.ReadFromFS(%q) succeeded, want error", path)
+		}
+	}
+	if _, err := keyset.ReadFromFS(fsys, "keysets/mac.bin", "unknown-kms://key"); err == nil {
+		t.Error("keyset.ReadFromFS() with an unregistered KMS succeeded, want error")
+	}
+}
+
+func TestReadFromEnv(t *testing.T) {
+	h, keyURI, masterKey := newLoadTestKeyset(t)
+	buf := &bytes.Buffer{}
+	if err := h.Write(keyset.NewBinaryWriter(buf), masterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	const name = "TINK_KEYSET_LOAD_TEST"
+	defer os.Unsetenv(name)
+
+	if _, err := keyset.ReadFromEnv(name, keyURI); err == nil {
+		t.Error("keyset.ReadFromEnv() of an unset variable succeeded, want error")
+	}
+	os.Setenv(name, base64.StdEncoding.EncodeToString(buf.Bytes())+"\n")
+	got, err := keyset.ReadFromEnv(name, keyURI)
+	if err != nil {
+		t.Fatalf("keyset.ReadFromEnv() failed: %v", err)
+	}
+	if got.KeysetInfo().GetPrimaryKeyId() != h.KeysetInfo().GetPrimaryKeyId() {
+		t.Error("keyset.ReadFromEnv() returned a different keyset")
+	}
+	os.Setenv(name, "not base64!")
+	if _, err := keyset.ReadFromEnv(name, keyURI); err == nil {
+		t.Error("keyset.ReadFromEnv() of an invalid value succeeded, want error")
+	}
+}
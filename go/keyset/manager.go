@@ -19,6 +19,7 @@ package keyset
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/subtle/random"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
@@ -27,20 +28,33 @@ import (
 // Manager manages a Keyset-proto, with convenience methods that rotate, disable, enable or destroy keys.
 // Note: It is not thread-safe.
 type Manager struct {
-	ks *tinkpb.Keyset
+	ks          *tinkpb.Keyset
+	annotations map[string]string
+	auditLog    bool
+	actor       string
+	events      []KeyEvent
+	auditLogErr error
 }
 
 // NewManager creates a new instance with an empty Keyset.
-func NewManager() *Manager {
+func NewManager(opts ...ManagerOption) *Manager {
 	ret := new(Manager)
 	ret.ks = new(tinkpb.Keyset)
+	for _, opt := range opts {
+		opt(ret)
+	}
 	return ret
 }
 
 // NewManagerFromHandle creates a new instance from the given Handle.
-func NewManagerFromHandle(kh *Handle) *Manager {
+func NewManagerFromHandle(kh *Handle, opts ...ManagerOption) *Manager {
 	ret := new(Manager)
-	ret.ks = kh.ks
+	ret.ks = proto.Clone(kh.ks).(*tinkpb.Keyset)
+	ret.annotations = kh.annotations
+	for _, opt := range opts {
+		opt(ret)
+	}
+	ret.initAuditLog()
 	return ret
 }
 
@@ -50,12 +64,31 @@ func (km *Manager) Rotate(kt *tinkpb.KeyTemplate) error {
 	if kt == nil {
 		return fmt.Errorf("keyset_manager: cannot rotate, need key template")
 	}
+	keyID, err := km.Add(kt)
+	if err != nil {
+		return err
+	}
+	// Set the new key as the primary key
+	km.ks.PrimaryKeyId = keyID
+	km.record(keyID, KeyPromoted)
+	return nil
+}
+
+// Add generates and adds a fresh key using the given key template, and returns
+// its key ID. The new key is enabled but does not become the primary key.
+func (km *Manager) Add(kt *tinkpb.KeyTemplate) (uint32, error) {
+	if kt == nil {
+		return 0, fmt.Errorf("keyset_manager: cannot add key, need key template")
+	}
+	if km.auditLogErr != nil {
+		return 0, km.auditLogErr
+	}
 	if kt.OutputPrefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
-		return fmt.Errorf("keyset_manager: unknown output prefix type")
+		return 0, fmt.Errorf("keyset_manager: unknown output prefix type")
 	}
 	keyData, err := registry.NewKeyData(kt)
 	if err != nil {
-		return fmt.Errorf("keyset_manager: cannot create KeyData: %s", err)
+		return 0, fmt.Errorf("keyset_manager: cannot create KeyData: %s", err)
 	}
 	keyID := km.newKeyID()
 	key := &tinkpb.Keyset_Key{
@@ -64,15 +97,164 @@ func (km *Manager) Rotate(kt *tinkpb.KeyTemplate) error {
 		KeyId:            keyID,
 		OutputPrefixType: kt.OutputPrefixType,
 	}
-	// Set the new key as the primary key
 	km.ks.Key = append(km.ks.Key, key)
+	km.record(keyID, KeyCreated)
+	return keyID, nil
+}
+
+// SetPrimary sets the key with the given key ID as the primary key. The key
+// must be enabled.
+func (km *Manager) SetPrimary(keyID uint32) error {
+	key, err := km.key(keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status != tinkpb.KeyStatusType_ENABLED {
+		return fmt.Errorf("keyset_manager: cannot set key %d with status %s as primary", keyID, key.Status)
+	}
 	km.ks.PrimaryKeyId = keyID
+	km.record(keyID, KeyPromoted)
+	return nil
+}
+
+// Enable enables the key with the given key ID. Destroyed keys cannot be
+// enabled.
+func (km *Manager) Enable(keyID uint32) error {
+	key, err := km.key(keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status != tinkpb.KeyStatusType_ENABLED && key.Status != tinkpb.KeyStatusType_DISABLED {
+		return fmt.Errorf("keyset_manager: cannot enable key %d with status %s", keyID, key.Status)
+	}
+	key.Status = tinkpb.KeyStatusType_ENABLED
+	km.record(keyID, KeyEnabled)
+	return nil
+}
+
+// Disable disables the key with the given key ID. The primary key cannot be
+// disabled.
+func (km *Manager) Disable(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot disable the primary key %d", keyID)
+	}
+	key, err := km.key(keyID)
+	if err != nil {
+		return err
+	}
+	if key.Status != tinkpb.KeyStatusType_ENABLED && key.Status != tinkpb.KeyStatusType_DISABLED {
+		return fmt.Errorf("keyset_manager: cannot disable key %d with status %s", keyID, key.Status)
+	}
+	key.Status = tinkpb.KeyStatusType_DISABLED
+	km.record(keyID, KeyDisabled)
 	return nil
 }
 
+// Destroy destroys the key material of the key with the given key ID. The key
+// itself stays in the keyset with status DESTROYED, so that its key ID is not
+// reused, but its expiration time is removed. The primary key cannot be
+// destroyed.
+func (km *Manager) Destroy(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot destroy the primary key %d", keyID)
+	}
+	key, err := km.key(keyID)
+	if err != nil {
+		return err
+	}
+	key.Status = tinkpb.KeyStatusType_DESTROYED
+	// Keep the type URL and key material type so that the key still shows up
+	// in the KeysetInfo.
+	key.KeyData = &tinkpb.KeyData{
+		TypeUrl:         key.KeyData.GetTypeUrl(),
+		KeyMaterialType: key.KeyData.GetKeyMaterialType(),
+	}
+	km.annotations = withoutAnnotation(km.annotations, keyExpirationAnnotation(keyID))
+	km.record(keyID, KeyDestroyed)
+	return nil
+}
+
+// Delete removes the key with the given key ID and its expiration time from
+// the keyset. The primary key cannot be deleted.
+func (km *Manager) Delete(keyID uint32) error {
+	if keyID == km.ks.PrimaryKeyId {
+		return fmt.Errorf("keyset_manager: cannot delete the primary key %d", keyID)
+	}
+	if km.auditLogErr != nil {
+		return km.auditLogErr
+	}
+	for i, key := range km.ks.Key {
+		if key.KeyId == keyID {
+			km.ks.Key = append(km.ks.Key[:i], km.ks.Key[i+1:]...)
+			km.annotations = withoutAnnotation(km.annotations, keyExpirationAnnotation(keyID))
+			km.record(keyID, KeyDeleted)
+			return nil
+		}
+	}
+	return fmt.Errorf("keyset_manager: key %d not found", keyID)
+}
+
+// UpgradeKeys upgrades the keys whose key managers implement
+// registry.UpgradableKeyManager to the current versions of their key managers,
+// and returns the IDs of the upgraded keys. Keys whose key managers aren't
+// registered and destroyed keys are left as they are. If a key cannot be
+// upgraded, no key is.
+func (km *Manager) UpgradeKeys() ([]uint32, error) {
+	if km.auditLogErr != nil {
+		return nil, km.auditLogErr
+	}
+	upgradedKeys := make(map[uint32][]byte)
+	var keyIDs []uint32
+	for _, key := range km.ks.Key {
+		if key.Status == tinkpb.KeyStatusType_DESTROYED || len(key.GetKeyData().GetValue()) == 0 {
+			continue
+		}
+		manager, err := registry.GetKeyManager(key.KeyData.TypeUrl)
+		if err != nil {
+			continue
+		}
+		upgradable, ok := manager.(registry.UpgradableKeyManager)
+		if !ok {
+			continue
+		}
+		upgradedKey, upgraded, err := upgradable.UpgradeKey(key.KeyData.Value)
+		if err != nil {
+			return nil, fmt.Errorf("keyset_manager: cannot upgrade key %d: %s", key.KeyId, err)
+		}
+		if upgraded {
+			upgradedKeys[key.KeyId] = upgradedKey
+			keyIDs = append(keyIDs, key.KeyId)
+		}
+	}
+	for _, key := range km.ks.Key {
+		if upgradedKey, ok := upgradedKeys[key.KeyId]; ok {
+			key.KeyData = &tinkpb.KeyData{
+				TypeUrl:         key.KeyData.TypeUrl,
+				Value:           upgradedKey,
+				KeyMaterialType: key.KeyData.KeyMaterialType,
+			}
+			km.record(key.KeyId, KeyUpgraded)
+		}
+	}
+	return keyIDs, nil
+}
+
 // Handle creates a new Handle for the managed keyset.
 func (km *Manager) Handle() (*Handle, error) {
-	return &Handle{km.ks}, nil
+	return &Handle{ks: proto.Clone(km.ks).(*tinkpb.Keyset), annotations: km.annotations}, nil
+}
+
+// key returns the key with the given key ID.
+func (km *Manager) key(keyID uint32) (*tinkpb.Keyset_Key, error) {
+	if km.auditLogErr != nil {
+		return nil, km.auditLogErr
+	}
+	for _, key := range km.ks.Key {
+		if key.KeyId == keyID {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("keyset_manager: key %d not found", keyID)
 }
 
 // newKeyID generates a key id that has not been used by any key in the keyset.
@@ -17,13 +17,17 @@
 package keyset_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/testkeyset"
 
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/testutil"
+	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
@@ -94,3 +98,252 @@ func TestUnknowOutputPrefixTypeFails(t *testing.T) {
 		t.Errorf("ksm1.Rotate(kt) where kt has an unknown prefix succeeded, want error")
 	}
 }
+
+func keyByID(t *testing.T, h *keyset.Handle, keyID uint32) *tinkpb.Keyset_Key {
+	t.Helper()
+	for _, key := range testkeyset.KeysetMaterial(h).Key {
+		if key.KeyId == keyID {
+			return key
+		}
+	}
+	return nil
+}
+
+func TestKeysetManagerAddAndSetPrimary(t *testing.T) {
+	ksm := keyset.NewManager()
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("ksm.Rotate(kt) failed: %s", err)
+	}
+	h, _ := ksm.Handle()
+	primaryID := h.KeysetInfo().PrimaryKeyId
+	keyID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("ksm.Add(kt) failed: %s", err)
+	}
+	h, _ = ksm.Handle()
+	if h.KeysetInfo().PrimaryKeyId != primaryID {
+		t.Errorf("primary key ID = %d after Add, want %d", h.KeysetInfo().PrimaryKeyId, primaryID)
+	}
+	if key := keyByID(t, h, keyID); key == nil || key.Status != tinkpb.KeyStatusType_ENABLED {
+		t.Errorf("added key %d = %v, want an enabled key", keyID, key)
+	}
+	if err := ksm.SetPrimary(keyID); err != nil {
+		t.Fatalf("ksm.SetPrimary(%d) failed: %s", keyID, err)
+	}
+	h, _ = ksm.Handle()
+	if h.KeysetInfo().PrimaryKeyId != keyID {
+		t.Errorf("primary key ID = %d, want %d", h.KeysetInfo().PrimaryKeyId, keyID)
+	}
+	if _, err := mac.New(h); err != nil {
+		t.Errorf("mac.New(h) failed: %s", err)
+	}
+	if _, err := ksm.Add(nil); err == nil {
+		t.Error("ksm.Add(nil) succeeded, want error")
+	}
+	if err := ksm.SetPrimary(keyID + 1); err == nil {
+		t.Error("ksm.SetPrimary() with an unknown key ID succeeded, want error")
+	}
+}
+
+func TestKeysetManagerKeyLifecycle(t *testing.T) {
+	ksm := keyset.NewManager()
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	keyID, err := ksm.Add(kt)
+	if err != nil {
+		t.Fatalf("ksm.Add(kt) failed: %s", err)
+	}
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("ksm.Rotate(kt) failed: %s", err)
+	}
+	h, _ := ksm.Handle()
+	primaryID := h.KeysetInfo().PrimaryKeyId
+
+	if err := ksm.Disable(keyID); err != nil {
+		t.Fatalf("ksm.Disable(%d) failed: %s", keyID, err)
+	}
+	h, _ = ksm.Handle()
+	if key := keyByID(t, h, keyID); key.Status != tinkpb.KeyStatusType_DISABLED {
+		t.Errorf("key status = %s after Disable, want DISABLED", key.Status)
+	}
+	if err := ksm.SetPrimary(keyID); err == nil {
+		t.Error("ksm.SetPrimary() with a disabled key succeeded, want error")
+	}
+	if err := ksm.Enable(keyID); err != nil {
+		t.Fatalf("ksm.Enable(%d) failed: %s", keyID, err)
+	}
+	h, _ = ksm.Handle()
+	if key := keyByID(t, h, keyID); key.Status != tinkpb.KeyStatusType_ENABLED {
+		t.Errorf("key status = %s after Enable, want ENABLED", key.Status)
+	}
+
+	if err := ksm.Destroy(keyID); err != nil {
+		t.Fatalf("ksm.Destroy(%d) failed: %s", keyID, err)
+	}
+	h, _ = ksm.Handle()
+	key := keyByID(t, h, keyID)
+	if key.Status != tinkpb.KeyStatusType_DESTROYED {
+		t.Errorf("key status = %s after Destroy, want DESTROYED", key.Status)
+	}
+	if len(key.KeyData.Value) != 0 {
+		t.Error("key material was not removed by Destroy")
+	}
+	if key.KeyData.TypeUrl != testutil.HMACTypeURL {
+		t.Errorf("type URL = %q after Destroy, want %q", key.KeyData.TypeUrl, testutil.HMACTypeURL)
+	}
+	if err := keyset.Validate(testkeyset.KeysetMaterial(h)); err != nil {
+		t.Errorf("keyset.Validate() failed after Destroy: %s", err)
+	}
+	if _, err := mac.New(h); err != nil {
+		t.Errorf("mac.New(h) failed after Destroy: %s", err)
+	}
+	if err := ksm.Enable(keyID); err == nil {
+		t.Error("ksm.Enable() with a destroyed key succeeded, want error")
+	}
+	if err := ksm.Disable(keyID); err == nil {
+		t.Error("ksm.Disable() with a destroyed key succeeded, want error")
+	}
+
+	if err := ksm.Delete(keyID); err != nil {
+		t.Fatalf("ksm.Delete(%d) failed: %s", keyID, err)
+	}
+	h, _ = ksm.Handle()
+	if keyByID(t, h, keyID) != nil {
+		t.Error("key is still in the keyset after Delete")
+	}
+	if len(h.KeysetInfo().KeyInfo) != 1 {
+		t.Errorf("number of keys = %d after Delete, want 1", len(h.KeysetInfo().KeyInfo))
+	}
+	if err := ksm.Delete(keyID); err == nil {
+		t.Error("ksm.Delete() with a deleted key succeeded, want error")
+	}
+
+	for name, f := range map[string]func(uint32) error{
+		"Disable": ksm.Disable,
+		"Destroy": ksm.Destroy,
+		"Delete":  ksm.Delete,
+	} {
+		if err := f(primaryID); err == nil {
+			t.Errorf("ksm.%s() with the primary key succeeded, want error", name)
+		}
+	}
+	if err := ksm.Enable(keyID); err == nil {
+		t.Error("ksm.Enable() with an unknown key ID succeeded, want error")
+	}
+}
+
+func TestKeysetManagerDoesNotModifyHandles(t *testing.T) {
+	kt := mac.HMACSHA256Tag128KeyTemplate()
+	src, err := keyset.NewHandle(kt)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle(kt) failed: %s", err)
+	}
+	want := proto.Clone(testkeyset.KeysetMaterial(src))
+	ksm := keyset.NewManagerFromHandle(src)
+	if err := ksm.Rotate(kt); err != nil {
+		t.Fatalf("ksm.Rotate(kt) failed: %s", err)
+	}
+	h, _ := ksm.Handle()
+	snapshot := proto.Clone(testkeyset.KeysetMaterial(h))
+	keyID := src.KeysetInfo().PrimaryKeyId
+	if err := ksm.Destroy(keyID); err != nil {
+		t.Fatalf("ksm.Destroy(%d) failed: %s", keyID, err)
+	}
+	if got := testkeyset.KeysetMaterial(src); !proto.Equal(got, want) {
+		t.Errorf("source handle keyset = %s after Destroy, want %s", got, want)
+	}
+	if got := testkeyset.KeysetMaterial(h); !proto.Equal(got, snapshot) {
+		t.Errorf("ksm.Handle() keyset = %s after Destroy, want %s", got, snapshot)
+	}
+	if _, err := mac.New(src); err != nil {
+		t.Errorf("mac.New(src) failed after Destroy: %s", err)
+	}
+}
+
+// upgradingHMACKeyManager stands in for an HMAC key manager whose key format
+// changed: it upgrades keys with 16 byte tags to 32 byte tags.
+type upgradingHMACKeyManager struct {
+	registry.KeyManager
+	err error
+}
+
+func (km *upgradingHMACKeyManager) UpgradeKey(serializedKey []byte) ([]byte, bool, error) {
+	if km.err != nil {
+		return nil, false, km.err
+	}
+	key := new(hmacpb.HmacKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, false, err
+	}
+	if key.GetParams().GetTagSize() != 16 {
+		return serializedKey, false, nil
+	}
+	key.Params.TagSize = 32
+	upgradedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, false, err
+	}
+	return upgradedKey, true, nil
+}
+
+func TestKeysetManagerUpgradeKeys(t *testing.T) {
+	hmacKeyManager, err := registry.GetKeyManager(testutil.HMACTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager() failed: %v", err)
+	}
+	restore := registry.Snapshot()
+	defer restore()
+	upgrading := &upgradingHMACKeyManager{KeyManager: hmacKeyManager}
+	registry.ReplaceKeyManager(upgrading)
+
+	ksm := keyset.NewManager(keyset.WithAuditLog("test"))
+	oldKeyID, err := ksm.Add(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("ksm.Add() failed: %v", err)
+	}
+	if err := ksm.Rotate(mac.HMACSHA256Tag256KeyTemplate()); err != nil {
+		t.Fatalf("ksm.Rotate() failed: %v", err)
+	}
+
+	upgrading.err = errors.New("cannot upgrade")
+	if _, err := ksm.UpgradeKeys(); err == nil {
+		t.Error("ksm.UpgradeKeys() succeeded with a failing key manager, want error")
+	}
+	upgrading.err = nil
+	keyIDs, err := ksm.UpgradeKeys()
+	if err != nil {
+		t.Fatalf("ksm.UpgradeKeys() failed: %v", err)
+	}
+	if len(keyIDs) != 1 || keyIDs[0] != oldKeyID {
+		t.Errorf("ksm.UpgradeKeys() = %v, want [%d]", keyIDs, oldKeyID)
+	}
+	h, err := ksm.Handle()
+	if err != nil {
+		t.Fatalf("ksm.Handle() failed: %v", err)
+	}
+	for _, key := range testkeyset.KeysetMaterial(h).Key {
+		hmacKey := new(hmacpb.HmacKey)
+		if err := proto.Unmarshal(key.GetKeyData().GetValue(), hmacKey); err != nil {
+			t.Fatalf("proto.Unmarshal() failed: %v", err)
+		}
+		if hmacKey.GetParams().GetTagSize() != 32 {
+			t.Errorf("tag size of key %d = %d, want 32", key.KeyId, hmacKey.GetParams().GetTagSize())
+		}
+	}
+	events, err := h.AuditLog()
+	if err != nil {
+		t.Fatalf("h.AuditLog() failed: %v", err)
+	}
+	if last := events[len(events)-1]; last.KeyID != oldKeyID || last.Type != keyset.KeyUpgraded {
+		t.Errorf("last audit log event = %+v, want %s of key %d", last, keyset.KeyUpgraded, oldKeyID)
+	}
+
+	// Keys in the current version aren't upgraded again.
+	keyIDs, err = ksm.UpgradeKeys()
+	if err != nil {
+		t.Fatalf("ksm.UpgradeKeys() failed: %v", err)
+	}
+	if len(keyIDs) != 0 {
+		t.Errorf("ksm.UpgradeKeys() = %v, want none", keyIDs)
+	}
+}
@@ -22,11 +22,14 @@ import tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 type MemReaderWriter struct {
 	Keyset          *tinkpb.Keyset
 	EncryptedKeyset *tinkpb.EncryptedKeyset
+	Annotations     map[string]string
 }
 
-// MemReaderWriter implements Reader and Writer.
+// MemReaderWriter implements Reader and Writer, including annotations.
 var _ Reader = &MemReaderWriter{}
 var _ Writer = &MemReaderWriter{}
+var _ AnnotationsReader = &MemReaderWriter{}
+var _ AnnotationsWriter = &MemReaderWriter{}
 
 // Read returns *tinkpb.Keyset from memory.
 func (m *MemReaderWriter) Read() (*tinkpb.Keyset, error) {
@@ -49,3 +52,14 @@ func (m *MemReaderWriter) WriteEncrypted(keyset *tinkpb.EncryptedKeyset) error {
 	m.EncryptedKeyset = keyset
 	return nil
 }
+
+// ReadAnnotations returns the annotations from memory.
+func (m *MemReaderWriter) ReadAnnotations() (map[string]string, error) {
+	return m.Annotations, nil
+}
+
+// WriteAnnotations writes annotations to memory.
+func (m *MemReaderWriter) WriteAnnotations(annotations map[string]string) error {
+	m.Annotations = annotations
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// Merge returns a handle for a keyset with the keys of all given handles. The
+// primary key and the annotations are those of the first handle.
+//
+// Keys with the same key ID and the same key material are only included once.
+// Keys with RAW output prefix whose key ID is already used by another key are
+// given a fresh key ID. Other key ID collisions are an error, since the key ID
+// is part of the output of such keys.
+func Merge(handles ...*Handle) (*Handle, error) {
+	if len(handles) == 0 {
+		return nil, errors.New("keyset.Merge: no keyset handles")
+	}
+	for _, h := range handles {
+		if h == nil {
+			return nil, errors.New("keyset.Merge: nil keyset handle")
+		}
+	}
+	ks := &tinkpb.Keyset{PrimaryKeyId: handles[0].ks.PrimaryKeyId}
+	km := &Manager{ks: ks}
+	for _, h := range handles {
+		for _, key := range h.ks.Key {
+			existing, err := km.key(key.KeyId)
+			if err != nil {
+				ks.Key = append(ks.Key, proto.Clone(key).(*tinkpb.Keyset_Key))
+				continue
+			}
+			if proto.Equal(existing, key) {
+				continue
+			}
+			if key.OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+				return nil, fmt.Errorf("keyset.Merge: key ID %d is used by different keys", key.KeyId)
+			}
+			key = proto.Clone(key).(*tinkpb.Keyset_Key)
+			key.KeyId = km.newKeyID()
+			ks.Key = append(ks.Key, key)
+		}
+	}
+	if err := Validate(ks); err != nil {
+		return nil, fmt.Errorf("keyset.Merge: invalid keyset: %s", err)
+	}
+	return &Handle{ks: ks, annotations: handles[0].annotations}, nil
+}
+
+// Subset returns a handle for a keyset with only the keys of h with the given
+// key IDs, e.g. to strip retired keys. Unless the remaining keys are all public
+// keys, the primary key must be one of them.
+func (h *Handle) Subset(keyIDs ...uint32) (*Handle, error) {
+	keep := make(map[uint32]bool, len(keyIDs))
+	for _, keyID := range keyIDs {
+		keep[keyID] = true
+	}
+	ks := &tinkpb.Keyset{}
+	for _, key := range h.ks.Key {
+		if !keep[key.KeyId] {
+			continue
+		}
+		delete(keep, key.KeyId)
+		ks.Key = append(ks.Key, proto.Clone(key).(*tinkpb.Keyset_Key))
+		if key.KeyId == h.ks.PrimaryKeyId {
+			ks.PrimaryKeyId = key.KeyId
+		}
+	}
+	for keyID := range keep {
+		return nil, fmt.Errorf("keyset.Handle: key %d not found", keyID)
+	}
+	if err := Validate(ks); err != nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid subset: %s", err)
+	}
+	return &Handle{ks: ks, annotations: h.annotations}, nil
+}
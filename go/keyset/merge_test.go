@@ -0,0 +1,166 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestMerge(t *testing.T) {
+	h1, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	h2, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	merged, err := keyset.Merge(h1, h2, h1)
+	if err != nil {
+		t.Fatalf("keyset.Merge() failed: %v", err)
+	}
+	info := merged.KeysetInfo()
+	if info.PrimaryKeyId != h1.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key ID = %d, want %d", info.PrimaryKeyId, h1.KeysetInfo().PrimaryKeyId)
+	}
+	if len(info.KeyInfo) != 2 {
+		t.Fatalf("number of keys = %d, want 2", len(info.KeyInfo))
+	}
+	if info.KeyInfo[1].KeyId != h2.KeysetInfo().PrimaryKeyId {
+		t.Errorf("second key ID = %d, want %d", info.KeyInfo[1].KeyId, h2.KeysetInfo().PrimaryKeyId)
+	}
+
+	// Tags of both keysets verify with the merged keyset.
+	m, err := mac.New(merged)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	for _, h := range []*keyset.Handle{h1, h2} {
+		p, err := mac.New(h)
+		if err != nil {
+			t.Fatalf("mac.New() failed: %v", err)
+		}
+		tag, err := p.ComputeMAC([]byte("data"))
+		if err != nil {
+			t.Fatalf("ComputeMAC() failed: %v", err)
+		}
+		if err := m.VerifyMAC(tag, []byte("data")); err != nil {
+			t.Errorf("VerifyMAC() with the merged keyset failed: %v", err)
+		}
+	}
+}
+
+func TestMergeKeyIDCollisions(t *testing.T) {
+	newKeyset := func(key *tinkpb.Keyset_Key) *keyset.Handle {
+		h, err := testkeyset.NewHandle(testutil.NewKeyset(key.KeyId, []*tinkpb.Keyset_Key{key}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle() failed: %v", err)
+		}
+		return h
+	}
+	raw1 := newKeyset(testutil.NewKey(testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16), tinkpb.KeyStatusType_ENABLED, 42, tinkpb.OutputPrefixType_RAW))
+	raw2 := newKeyset(testutil.NewKey(testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16), tinkpb.KeyStatusType_ENABLED, 42, tinkpb.OutputPrefixType_RAW))
+	merged, err := keyset.Merge(raw1, raw2)
+	if err != nil {
+		t.Fatalf("keyset.Merge() failed: %v", err)
+	}
+	info := merged.KeysetInfo()
+	if len(info.KeyInfo) != 2 || info.KeyInfo[0].KeyId != 42 || info.KeyInfo[1].KeyId == 42 {
+		t.Errorf("merged keyset = %v, want the second key with a fresh key ID", info)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(merged).Key[1].KeyData, testkeyset.KeysetMaterial(raw2).Key[0].KeyData) {
+		t.Error("key data of the second key changed")
+	}
+
+	tink1 := newKeyset(testutil.NewKey(testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16), tinkpb.KeyStatusType_ENABLED, 42, tinkpb.OutputPrefixType_TINK))
+	tink2 := newKeyset(testutil.NewKey(testutil.NewHMACKeyData(commonpb.HashType_SHA256, 16), tinkpb.KeyStatusType_ENABLED, 42, tinkpb.OutputPrefixType_TINK))
+	if _, err := keyset.Merge(tink1, tink2); err == nil {
+		t.Error("keyset.Merge() of different TINK keys with the same key ID succeeded, want error")
+	}
+	if _, err := keyset.Merge(); err == nil {
+		t.Error("keyset.Merge() without handles succeeded, want error")
+	}
+	if _, err := keyset.Merge(tink1, nil); err == nil {
+		t.Error("keyset.Merge() with a nil handle succeeded, want error")
+	}
+}
+
+func TestSubset(t *testing.T) {
+	m := keyset.NewManager()
+	var keyIDs []uint32
+	for i := 0; i < 3; i++ {
+		keyID, err := m.Add(mac.HMACSHA256Tag128KeyTemplate())
+		if err != nil {
+			t.Fatalf("m.Add() failed: %v", err)
+		}
+		keyIDs = append(keyIDs, keyID)
+	}
+	if err := m.SetPrimary(keyIDs[1]); err != nil {
+		t.Fatalf("m.SetPrimary() failed: %v", err)
+	}
+	h, _ := m.Handle()
+
+	subset, err := h.Subset(keyIDs[2], keyIDs[1])
+	if err != nil {
+		t.Fatalf("h.Subset() failed: %v", err)
+	}
+	info := subset.KeysetInfo()
+	if len(info.KeyInfo) != 2 || info.KeyInfo[0].KeyId != keyIDs[1] || info.KeyInfo[1].KeyId != keyIDs[2] {
+		t.Errorf("subset = %v, want keys %d and %d", info, keyIDs[1], keyIDs[2])
+	}
+	if info.PrimaryKeyId != keyIDs[1] {
+		t.Errorf("primary key ID = %d, want %d", info.PrimaryKeyId, keyIDs[1])
+	}
+	if len(h.KeysetInfo().KeyInfo) != 3 {
+		t.Error("h.Subset() modified the original keyset")
+	}
+
+	if _, err := h.Subset(keyIDs[0]); err == nil {
+		t.Error("h.Subset() without the primary key succeeded, want error")
+	}
+	if _, err := h.Subset(keyIDs[1], keyIDs[0]+keyIDs[1]+keyIDs[2]); err == nil {
+		t.Error("h.Subset() with an unknown key ID succeeded, want error")
+	}
+
+	// Public keysets don't need a primary key.
+	sh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	sm := keyset.NewManagerFromHandle(sh)
+	if err := sm.Rotate(signature.ED25519KeyTemplate()); err != nil {
+		t.Fatalf("sm.Rotate() failed: %v", err)
+	}
+	sh, _ = sm.Handle()
+	pub, err := sh.Public()
+	if err != nil {
+		t.Fatalf("sh.Public() failed: %v", err)
+	}
+	oldKeyID := pub.KeysetInfo().KeyInfo[0].KeyId
+	if _, err := pub.Subset(oldKeyID); err != nil {
+		t.Errorf("pub.Subset() of a non-primary public key failed: %v", err)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// Keysets encrypted with a password start with a header that holds the
+// parameters of the key derivation, followed by an AES-256-GCM ciphertext of
+// the serialized keyset. The header is authenticated as associated data.
+//
+// Header layout (integers are big endian):
+//   - version: 1 byte
+//   - key derivation function: 1 byte
+//   - Argon2id: time (4 bytes), memory in KiB (4 bytes), threads (1 byte)
+//   - scrypt: N (4 bytes), r (4 bytes), p (4 bytes)
+//   - salt: 16 bytes
+const (
+	passwordHeaderVersion = 1
+
+	passwordKDFArgon2id = 1
+	passwordKDFScrypt   = 2
+
+	passwordSaltSize = 16
+	passwordKeySize  = 32
+
+	// Limits on the parameters accepted when reading, so that a crafted header
+	// cannot make the key derivation use unbounded time or memory.
+	maxArgon2idTime   = 64
+	maxArgon2idMemory = 4 * 1024 * 1024        // KiB, i.e. 4 GiB
+	maxScryptMemory   = 4 * 1024 * 1024 * 1024 // bytes
+)
+
+// passwordParams are the parameters of the key derivation from the password.
+type passwordParams struct {
+	kdf byte
+	// Argon2id parameters.
+	time, memory uint32
+	threads      uint8
+	// scrypt parameters.
+	n, r, p uint32
+}
+
+// PasswordOption configures how WriteWithPassword derives the key which
+// encrypts the keyset.
+type PasswordOption func(*passwordParams)
+
+// WithArgon2id derives the key with Argon2id using the given number of passes,
+// memory in KiB and degree of parallelism. The default is Argon2id with
+// time=1, memory=64*1024 and threads=4, as recommended in RFC 9106.
+func WithArgon2id(time, memory uint32, threads uint8) PasswordOption {
+	return func(p *passwordParams) {
+		*p = passwordParams{kdf: passwordKDFArgon2id, time: time, memory: memory, threads: threads}
+	}
+}
+
+// WithScrypt derives the key with scrypt using the given CPU/memory cost N,
+// block size r and parallelization p.
+func WithScrypt(n, r, p uint32) PasswordOption {
+	return func(params *passwordParams) {
+		*params = passwordParams{kdf: passwordKDFScrypt, n: n, r: r, p: p}
+	}
+}
+
+func (p *passwordParams) validate() error {
+	switch p.kdf {
+	case passwordKDFArgon2id:
+		if p.time == 0 || p.time > maxArgon2idTime {
+			return fmt.Errorf("invalid Argon2id time %d", p.time)
+		}
+		if p.memory < 8*uint32(p.threads) || p.memory > maxArgon2idMemory {
+			return fmt.Errorf("invalid Argon2id memory %d", p.memory)
+		}
+		if p.threads == 0 {
+			return errors.New("invalid Argon2id threads 0")
+		}
+	case passwordKDFScrypt:
+		if p.n <= 1 || p.n&(p.n-1) != 0 {
+			return fmt.Errorf("invalid scrypt N %d, must be a power of two greater than 1", p.n)
+		}
+		if p.r == 0 || p.p == 0 || uint64(p.r)*uint64(p.p) >= 1<<30 {
+			return fmt.Errorf("invalid scrypt r %d and p %d", p.r, p.p)
+		}
+		if 128*uint64(p.n)*uint64(p.r) > maxScryptMemory {
+			return fmt.Errorf("scrypt N %d and r %d use too much memory", p.n, p.r)
+		}
+	default:
+		return fmt.Errorf("unknown key derivation function %d", p.kdf)
+	}
+	return nil
+}
+
+// marshal returns the header for the parameters and the given salt.
+func (p *passwordParams) marshal(salt []byte) []byte {
+	header := []byte{passwordHeaderVersion, p.kdf}
+	switch p.kdf {
+	case passwordKDFArgon2id:
+		header = appendUint32(header, p.time)
+		header = appendUint32(header, p.memory)
+		header = append(header, p.threads)
+	case passwordKDFScrypt:
+		header = appendUint32(header, p.n)
+		header = appendUint32(header, p.r)
+		header = appendUint32(header, p.p)
+	}
+	return append(header, salt...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// parsePasswordHeader parses the header at the start of b. It returns the
+// parameters, the salt and the length of the header.
+func parsePasswordHeader(b []byte) (*passwordParams, []byte, int, error) {
+	if len(b) < 2 {
+		return nil, nil, 0, errors.New("ciphertext too short")
+	}
+	if b[0] != passwordHeaderVersion {
+		return nil, nil, 0, fmt.Errorf("unsupported version %d", b[0])
+	}
+	p := &passwordParams{kdf: b[1]}
+	var paramsLen int
+	switch p.kdf {
+	case passwordKDFArgon2id:
+		paramsLen = 9
+	case passwordKDFScrypt:
+		paramsLen = 12
+	default:
+		return nil, nil, 0, fmt.Errorf("unknown key derivation function %d", p.kdf)
+	}
+	headerLen := 2 + paramsLen + passwordSaltSize
+	if len(b) < headerLen {
+		return nil, nil, 0, errors.New("ciphertext too short")
+	}
+	params := b[2 : 2+paramsLen]
+	switch p.kdf {
+	case passwordKDFArgon2id:
+		p.time = binary.BigEndian.Uint32(params[0:4])
+		p.memory = binary.BigEndian.Uint32(params[4:8])
+		p.threads = params[8]
+	case passwordKDFScrypt:
+		p.n = binary.BigEndian.Uint32(params[0:4])
+		p.r = binary.BigEndian.Uint32(params[4:8])
+		p.p = binary.BigEndian.Uint32(params[8:12])
+	}
+	if err := p.validate(); err != nil {
+		return nil, nil, 0, err
+	}
+	return p, b[2+paramsLen : headerLen], headerLen, nil
+}
+
+// deriveKey derives the keyset encryption key from the password.
+func (p *passwordParams) deriveKey(password, salt []byte) ([]byte, error) {
+	switch p.kdf {
+	case passwordKDFArgon2id:
+		return argon2.IDKey(password, salt, p.time, p.memory, p.threads, passwordKeySize), nil
+	case passwordKDFScrypt:
+		return scrypt.Key(password, salt, int(p.n), int(p.r), int(p.p), passwordKeySize)
+	}
+	return nil, fmt.Errorf("unknown key derivation function %d", p.kdf)
+}
+
+// ReadWithPassword tries to create a Handle from a keyset obtained via reader
+// which was encrypted with WriteWithPassword.
+func ReadWithPassword(reader Reader, password []byte) (*Handle, error) {
+	if len(password) == 0 {
+		return nil, errors.New("keyset.Handle: empty password")
+	}
+	encryptedKeyset, err := reader.ReadEncrypted()
+	if err != nil {
+		return nil, err
+	}
+	if encryptedKeyset == nil {
+		return nil, errors.New("keyset.Handle: invalid encrypted keyset")
+	}
+	ct := encryptedKeyset.EncryptedKeyset
+	params, salt, headerLen, err := parsePasswordHeader(ct)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: invalid password encrypted keyset: %s", err)
+	}
+	key, err := params.deriveKey(password, salt)
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: cannot derive key: %s", err)
+	}
+	a, err := subtle.NewAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := a.Decrypt(ct[headerLen:], ct[:headerLen])
+	if err != nil {
+		return nil, fmt.Errorf("keyset.Handle: decryption failed: %s", err)
+	}
+	ks := new(tinkpb.Keyset)
+	if err := proto.Unmarshal(decrypted, ks); err != nil {
+		return nil, errInvalidKeyset
+	}
+	annotations, err := readAnnotations(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Handle{ks: ks, annotations: annotations}, nil
+}
+
+// WriteWithPassword encrypts the enclosing keyset with a key derived from the
+// password and writes it. The key derivation parameters are stored with the
+// encrypted keyset, so ReadWithPassword only needs the password.
+func (h *Handle) WriteWithPassword(writer Writer, password []byte, opts ...PasswordOption) error {
+	if len(password) == 0 {
+		return errors.New("keyset.Handle: empty password")
+	}
+	params := &passwordParams{}
+	WithArgon2id(1, 64*1024, 4)(params)
+	for _, opt := range opts {
+		opt(params)
+	}
+	if err := params.validate(); err != nil {
+		return fmt.Errorf("keyset.Handle: %s", err)
+	}
+	salt := random.GetRandomBytes(passwordSaltSize)
+	key, err := params.deriveKey(password, salt)
+	if err != nil {
+		return fmt.Errorf("keyset.Handle: cannot derive key: %s", err)
+	}
+	a, err := subtle.NewAESGCM(key)
+	if err != nil {
+		return err
+	}
+	serializedKeyset, err := proto.Marshal(h.ks)
+	if err != nil {
+		return errInvalidKeyset
+	}
+	header := params.marshal(salt)
+	ct, err := a.Encrypt(serializedKeyset, header)
+	if err != nil {
+		return fmt.Errorf("keyset.Handle: encryption failed: %s", err)
+	}
+	if err := writer.WriteEncrypted(&tinkpb.EncryptedKeyset{
+		EncryptedKeyset: append(header, ct...),
+		KeysetInfo:      getKeysetInfo(h.ks),
+	}); err != nil {
+		return err
+	}
+	return writeAnnotations(writer, h.annotations)
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/testkeyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestWriteWithPasswordAndReadWithPassword(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	password := []byte("correct horse battery staple")
+	for _, tc := range []struct {
+		name string
+		opts []keyset.PasswordOption
+	}{
+		{"default", nil},
+		{"Argon2id", []keyset.PasswordOption{keyset.WithArgon2id(2, 1024, 2)}},
+		{"scrypt", []keyset.PasswordOption{keyset.WithScrypt(1024, 8, 1)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			memKeyset := &keyset.MemReaderWriter{}
+			if err := h.WriteWithPassword(memKeyset, password, tc.opts...); err != nil {
+				t.Fatalf("h.WriteWithPassword() failed: %v", err)
+			}
+			if !proto.Equal(memKeyset.EncryptedKeyset.KeysetInfo, h.KeysetInfo()) {
+				t.Errorf("KeysetInfo = %v, want %v", memKeyset.EncryptedKeyset.KeysetInfo, h.KeysetInfo())
+			}
+			h2, err := keyset.ReadWithPassword(memKeyset, password)
+			if err != nil {
+				t.Fatalf("keyset.ReadWithPassword() failed: %v", err)
+			}
+			if !proto.Equal(testkeyset.KeysetMaterial(h2), testkeyset.KeysetMaterial(h)) {
+				t.Error("keyset read with password doesn't match the written keyset")
+			}
+			if _, err := keyset.ReadWithPassword(memKeyset, []byte("wrong password")); err == nil {
+				t.Error("keyset.ReadWithPassword() succeeded with a wrong password, want error")
+			}
+		})
+	}
+}
+
+func TestReadWithPasswordModifiedHeader(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	password := []byte("password")
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.WriteWithPassword(memKeyset, password, keyset.WithArgon2id(1, 1024, 1)); err != nil {
+		t.Fatalf("h.WriteWithPassword() failed: %v", err)
+	}
+	ct := memKeyset.EncryptedKeyset.EncryptedKeyset
+	// Header: version, KDF, time (4 bytes), memory (4 bytes), threads, salt.
+	for i := 0; i < 2+9+16; i++ {
+		modified := append([]byte{}, ct...)
+		modified[i] ^= 1
+		r := &keyset.MemReaderWriter{EncryptedKeyset: &tinkpb.EncryptedKeyset{EncryptedKeyset: modified}}
+		if _, err := keyset.ReadWithPassword(r, password); err == nil {
+			t.Errorf("keyset.ReadWithPassword() succeeded with byte %d of the header modified, want error", i)
+		}
+	}
+	for _, n := range []int{0, 1, 10, 2 + 9 + 16} {
+		r := &keyset.MemReaderWriter{EncryptedKeyset: &tinkpb.EncryptedKeyset{EncryptedKeyset: ct[:n]}}
+		if _, err := keyset.ReadWithPassword(r, password); err == nil {
+			t.Errorf("keyset.ReadWithPassword() succeeded with ciphertext truncated to %d bytes, want error", n)
+		}
+	}
+}
+
+func TestPasswordInvalidInput(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	if err := h.WriteWithPassword(memKeyset, nil); err == nil {
+		t.Error("h.WriteWithPassword() succeeded with an empty password, want error")
+	}
+	for _, opt := range []keyset.PasswordOption{
+		keyset.WithArgon2id(0, 1024, 1),
+		keyset.WithArgon2id(1, 4, 1),
+		keyset.WithArgon2id(1, 1024, 0),
+		keyset.WithArgon2id(1, 1<<30, 1),
+		keyset.WithScrypt(1000, 8, 1),
+		keyset.WithScrypt(1024, 0, 1),
+		keyset.WithScrypt(1024, 8, 0),
+		keyset.WithScrypt(1<<30, 8, 1),
+	} {
+		if err := h.WriteWithPassword(memKeyset, []byte("password"), opt); err == nil {
+			t.Error("h.WriteWithPassword() succeeded with invalid parameters, want error")
+		}
+	}
+	if err := h.WriteWithPassword(memKeyset, []byte("password"), keyset.WithArgon2id(1, 1024, 1)); err != nil {
+		t.Fatalf("h.WriteWithPassword() failed: %v", err)
+	}
+	if _, err := keyset.ReadWithPassword(memKeyset, nil); err == nil {
+		t.Error("keyset.ReadWithPassword() succeeded with an empty password, want error")
+	}
+	if _, err := keyset.ReadWithPassword(&keyset.MemReaderWriter{}, []byte("password")); err == nil {
+		t.Error("keyset.ReadWithPassword() succeeded without an encrypted keyset, want error")
+	}
+}
@@ -0,0 +1,134 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+)
+
+// PrimitiveFor returns the primitive of the primary key of h as a T, e.g.
+// PrimitiveFor[tink.MAC](h). The primitives of all enabled keys in h must be
+// of type T.
+//
+// The primitive only uses the primary key. To also use the other keys, for
+// example to decrypt ciphertexts of older keys, use the factory of the
+// primitive such as aead.New, or NewPrimitive for custom primitives.
+func PrimitiveFor[T any](h *Handle) (T, error) {
+	var zero T
+	ps, err := primitivesFor[T](h)
+	if err != nil {
+		return zero, err
+	}
+	if ps.Primary == nil {
+		return zero, fmt.Errorf("keyset.Handle: keyset has no primary key")
+	}
+	return ps.Primary.Primitive.(T), nil
+}
+
+// NewPrimitive creates a primitive of type T for h. It checks that the
+// primitives of all enabled keys in h are of type T and passes them to wrap,
+// which combines them into one primitive, usually by selecting the primary key
+// for new outputs and keys by output prefix otherwise. This is what factories
+// such as mac.New do, so custom primitives don't need their own factory.
+func NewPrimitive[T any](h *Handle, wrap func(ps *primitiveset.PrimitiveSet) (T, error)) (T, error) {
+	ps, err := primitivesFor[T](h)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return wrap(ps)
+}
+
+// Wrap creates a primitive of type T for h, e.g. Wrap[tink.AEAD](h), with the
+// wrapper registered for T with registry.RegisterPrimitiveWrapper. Like the
+// factories such as aead.New, the primitive uses all keys of h. The packages
+// of the primitives must be imported so that their wrappers are registered.
+func Wrap[T any](h *Handle) (T, error) {
+	var zero T
+	w, err := registry.GetPrimitiveWrapper[T]()
+	if err != nil {
+		return zero, err
+	}
+	ps, err := h.Primitives()
+	if err != nil {
+		return zero, err
+	}
+	return w(ps)
+}
+
+// primitivesFor returns the primitive set of h, checking that all primitives
+// are of type T.
+func primitivesFor[T any](h *Handle) (*primitiveset.PrimitiveSet, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, err
+	}
+	for _, entries := range ps.Entries {
+		for _, entry := range entries {
+			if _, ok := entry.Primitive.(T); !ok {
+				var zero T
+				return nil, fmt.Errorf("keyset.Handle: primitive of key %d is %T, not %T", entry.KeyID, entry.Primitive, &zero)
+			}
+		}
+	}
+	return ps, nil
+}
+
+// ProvidedPrimitive is a primitive of type T that is derived from the keyset
+// of a ReloadingProvider and swapped together with its handle.
+type ProvidedPrimitive[T any] struct {
+	p         *ReloadingProvider
+	primitive T
+}
+
+// NewProvidedPrimitive derives a primitive from the current keyset of p with
+// newPrimitive, e.g. aead.New, and again whenever the keyset changes. If
+// newPrimitive fails for a new keyset, the reload fails and both the handle
+// and the primitive are kept.
+func NewProvidedPrimitive[T any](p *ReloadingProvider, newPrimitive func(*Handle) (T, error)) (*ProvidedPrimitive[T], error) {
+	pp := &ProvidedPrimitive[T]{p: p}
+	// Hold the reload lock so that no keyset is swapped in between deriving
+	// the first primitive and registering for updates.
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	primitive, err := newPrimitive(p.Handle())
+	if err != nil {
+		return nil, err
+	}
+	pp.primitive = primitive
+	p.derive(func(h *Handle) (func(), error) {
+		primitive, err := newPrimitive(h)
+		if err != nil {
+			return nil, err
+		}
+		return func() { pp.primitive = primitive }, nil
+	})
+	return pp, nil
+}
+
+// Primitive returns the primitive for the current keyset.
+func (pp *ProvidedPrimitive[T]) Primitive() T {
+	pp.p.mu.RLock()
+	defer pp.p.mu.RUnlock()
+	return pp.primitive
+}
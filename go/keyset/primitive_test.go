@@ -0,0 +1,223 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/hybrid"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/tink"
+)
+
+func TestPrimitiveFor(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	p, err := keyset.PrimitiveFor[tink.MAC](h)
+	if err != nil {
+		t.Fatalf("keyset.PrimitiveFor[tink.MAC]() failed: %v", err)
+	}
+	tag, err := p.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("p.ComputeMAC() failed: %v", err)
+	}
+	if err := p.VerifyMAC(tag, []byte("data")); err != nil {
+		t.Errorf("p.VerifyMAC() failed: %v", err)
+	}
+}
+
+func TestPrimitiveForWrongType(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	if _, err := keyset.PrimitiveFor[tink.AEAD](h); err == nil {
+		t.Error("keyset.PrimitiveFor[tink.AEAD]() of a MAC keyset succeeded, want error")
+	}
+	if _, err := keyset.NewPrimitive(h, func(ps *primitiveset.PrimitiveSet) (tink.AEAD, error) {
+		return aead.New(h)
+	}); err == nil {
+		t.Error("keyset.NewPrimitive() of a MAC keyset as tink.AEAD succeeded, want error")
+	}
+}
+
+func TestNewPrimitive(t *testing.T) {
+	manager := keyset.NewManager()
+	for i := 0; i < 2; i++ {
+		if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+			t.Fatalf("manager.Rotate() failed: %v", err)
+		}
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	var entries int
+	m, err := keyset.NewPrimitive(h, func(ps *primitiveset.PrimitiveSet) (tink.MAC, error) {
+		for _, e := range ps.Entries {
+			entries += len(e)
+		}
+		return mac.New(h)
+	})
+	if err != nil {
+		t.Fatalf("keyset.NewPrimitive() failed: %v", err)
+	}
+	if entries != 2 {
+		t.Errorf("wrap got %d entries, want 2", entries)
+	}
+	tag, err := m.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("m.ComputeMAC() failed: %v", err)
+	}
+	if err := m.VerifyMAC(tag, []byte("data")); err != nil {
+		t.Errorf("m.VerifyMAC() failed: %v", err)
+	}
+
+	wantErr := errors.New("wrap failed")
+	if _, err := keyset.NewPrimitive(h, func(ps *primitiveset.PrimitiveSet) (tink.MAC, error) {
+		return nil, wantErr
+	}); err != wantErr {
+		t.Errorf("keyset.NewPrimitive() err = %v, want %v", err, wantErr)
+	}
+}
+
+func TestProvidedPrimitive(t *testing.T) {
+	manager := keyset.NewManager()
+	if err := manager.Rotate(signature.ECDSAP256KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	publicKeyset := func() []byte {
+		t.Helper()
+		priv, err := manager.Handle()
+		if err != nil {
+			t.Fatalf("manager.Handle() failed: %v", err)
+		}
+		pub, err := priv.Public()
+		if err != nil {
+			t.Fatalf("priv.Public() failed: %v", err)
+		}
+		buf := &bytes.Buffer{}
+		if err := pub.WriteWithNoSecrets(keyset.NewBinaryWriter(buf)); err != nil {
+			t.Fatalf("pub.WriteWithNoSecrets() failed: %v", err)
+		}
+		return buf.Bytes()
+	}
+	serialized := publicKeyset()
+	p, err := keyset.NewReloadingProvider(func() ([]byte, error) { return serialized, nil })
+	if err != nil {
+		t.Fatalf("keyset.NewReloadingProvider() failed: %v", err)
+	}
+	verifier, err := keyset.NewProvidedPrimitive(p, signature.NewVerifier)
+	if err != nil {
+		t.Fatalf("keyset.NewProvidedPrimitive() failed: %v", err)
+	}
+	first := verifier.Primitive()
+
+	// A keyset for which no verifier can be created is not swapped in.
+	hybridPriv, err := keyset.NewHandle(hybrid.ECIESHKDFAES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	hybridPub, err := hybridPriv.Public()
+	if err != nil {
+		t.Fatalf("hybridPriv.Public() failed: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := hybridPub.WriteWithNoSecrets(keyset.NewBinaryWriter(buf)); err != nil {
+		t.Fatalf("hybridPub.WriteWithNoSecrets() failed: %v", err)
+	}
+	serialized = buf.Bytes()
+	if err := p.Reload(); err == nil {
+		t.Error("p.Reload() succeeded with a hybrid keyset, want error")
+	}
+	if p.Handle().KeysetInfo().GetPrimaryKeyId() == hybridPub.KeysetInfo().GetPrimaryKeyId() || verifier.Primitive() != first {
+		t.Error("p.Reload() swapped the keyset after a failure to derive the primitive")
+	}
+
+	if err := manager.Rotate(signature.ECDSAP256KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	serialized = publicKeyset()
+	if err := p.Reload(); err != nil {
+		t.Fatalf("p.Reload() failed: %v", err)
+	}
+	if verifier.Primitive() == first {
+		t.Error("verifier.Primitive() didn't change after the keyset changed")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	manager := keyset.NewManager()
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	oldHandle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	oldMAC, err := keyset.Wrap[tink.MAC](oldHandle)
+	if err != nil {
+		t.Fatalf("keyset.Wrap[tink.MAC]() failed: %v", err)
+	}
+	tag, err := oldMAC.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("oldMAC.ComputeMAC() failed: %v", err)
+	}
+
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	m, err := keyset.Wrap[tink.MAC](h)
+	if err != nil {
+		t.Fatalf("keyset.Wrap[tink.MAC]() failed: %v", err)
+	}
+	if err := m.VerifyMAC(tag, []byte("data")); err != nil {
+		t.Errorf("m.VerifyMAC() of a tag of the old primary failed: %v", err)
+	}
+	if _, err := keyset.Wrap[tink.AEAD](h); err == nil {
+		t.Error("keyset.Wrap[tink.AEAD]() of a MAC keyset succeeded, want error")
+	}
+}
+
+type unregisteredPrimitive interface {
+	Unregistered()
+}
+
+func TestWrapUnregisteredPrimitive(t *testing.T) {
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	if _, err := keyset.Wrap[unregisteredPrimitive](h); err == nil {
+		t.Error("keyset.Wrap[unregisteredPrimitive]() succeeded, want error")
+	}
+}
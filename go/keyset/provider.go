@@ -0,0 +1,257 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/google/tink/go/tink"
+)
+
+// Provider provides the current handle of a keyset that may change over
+// time, for example when the keyset is rotated by another process.
+type Provider interface {
+	// Handle returns the current handle.
+	Handle() *Handle
+}
+
+// Source fetches the serialized keyset of a ReloadingProvider. Keysets in
+// object stores such as GCS or S3 are read by a Source that downloads the
+// object with the respective client.
+type Source func() ([]byte, error)
+
+// FileSource returns a Source that reads the keyset from the file at path.
+func FileSource(path string) Source {
+	return func() ([]byte, error) {
+		return ioutil.ReadFile(path)
+	}
+}
+
+// ReloadingProvider is a Provider that reloads the keyset from a Source and
+// atomically swaps its handle when the serialized keyset changes.
+//
+// By default the keyset is read in binary format with ReadWithNoSecrets, so
+// only keysets without secret key material can be loaded. Keysets encrypted
+// with a master key, such as an AEAD of a KMS, are loaded with WithMasterKey.
+//
+// It is safe for concurrent use.
+type ReloadingProvider struct {
+	source         Source
+	masterKey      tink.AEAD
+	associatedData []byte
+	json           bool
+	interval       time.Duration
+	errorHandler   func(error)
+
+	// reloadMu serializes reloads; mu guards the fields below.
+	reloadMu   sync.Mutex
+	mu         sync.RWMutex
+	h          *Handle
+	serialized []byte
+	derived    []func(*Handle) (func(), error)
+	onChange   []func(old, new *Handle)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ Provider = (*ReloadingProvider)(nil)
+
+// ProviderOption configures a ReloadingProvider.
+type ProviderOption func(*ReloadingProvider)
+
+// WithMasterKey makes the ReloadingProvider decrypt the keyset with masterKey
+// and associatedData, as written by Handle.WriteWithAssociatedData.
+func WithMasterKey(masterKey tink.AEAD, associatedData []byte) ProviderOption {
+	return func(p *ReloadingProvider) {
+		p.masterKey = masterKey
+		p.associatedData = associatedData
+	}
+}
+
+// WithJSONFormat makes the ReloadingProvider read the keyset in JSON format.
+func WithJSONFormat() ProviderOption {
+	return func(p *ReloadingProvider) {
+		p.json = true
+	}
+}
+
+// WithReloadInterval makes the ReloadingProvider reload the keyset every d
+// until it is closed. Without it, the keyset is only reloaded by Reload.
+func WithReloadInterval(d time.Duration) ProviderOption {
+	return func(p *ReloadingProvider) {
+		p.interval = d
+	}
+}
+
+// WithReloadErrorHandler sets a function that is called with the errors of
+// the periodic reloads. The current handle is kept when a reload fails.
+func WithReloadErrorHandler(fn func(error)) ProviderOption {
+	return func(p *ReloadingProvider) {
+		p.errorHandler = fn
+	}
+}
+
+// NewReloadingProvider loads the keyset from source and returns a provider
+// for it. If WithReloadInterval is given, the keyset is reloaded in the
+// background until Close is called.
+func NewReloadingProvider(source Source, opts ...ProviderOption) (*ReloadingProvider, error) {
+	if source == nil {
+		return nil, errors.New("keyset.ReloadingProvider: source must not be nil")
+	}
+	p := &ReloadingProvider{source: source}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.interval < 0 {
+		return nil, errors.New("keyset.ReloadingProvider: reload interval must not be negative")
+	}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	if p.interval > 0 {
+		p.stop = make(chan struct{})
+		p.done = make(chan struct{})
+		go p.run()
+	}
+	return p, nil
+}
+
+// Handle returns the current handle.
+func (p *ReloadingProvider) Handle() *Handle {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.h
+}
+
+// OnChange registers fn to be called with the old and the new handle after
+// the keyset changed. Callbacks are called in the order they were registered,
+// from the goroutine that reloaded the keyset.
+func (p *ReloadingProvider) OnChange(fn func(old, new *Handle)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = append(p.onChange, fn)
+}
+
+// Reload fetches the keyset from the source and swaps the handle if the
+// keyset changed. If the keyset can't be fetched or parsed, or a primitive
+// can't be derived from it, the current handle is kept and an error is
+// returned.
+func (p *ReloadingProvider) Reload() error {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	serialized, err := p.source()
+	if err != nil {
+		return fmt.Errorf("keyset.ReloadingProvider: cannot fetch keyset: %v", err)
+	}
+	p.mu.RLock()
+	old := p.h
+	unchanged := old != nil && bytes.Equal(serialized, p.serialized)
+	derived := p.derived
+	p.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	h, err := p.parse(serialized)
+	if err != nil {
+		return fmt.Errorf("keyset.ReloadingProvider: cannot read keyset: %v", err)
+	}
+	commits := make([]func(), 0, len(derived))
+	for _, derive := range derived {
+		commit, err := derive(h)
+		if err != nil {
+			return fmt.Errorf("keyset.ReloadingProvider: cannot derive primitive: %v", err)
+		}
+		commits = append(commits, commit)
+	}
+
+	p.mu.Lock()
+	p.h = h
+	p.serialized = serialized
+	for _, commit := range commits {
+		commit()
+	}
+	onChange := p.onChange
+	p.mu.Unlock()
+
+	if old != nil {
+		for _, fn := range onChange {
+			fn(old, h)
+		}
+	}
+	return nil
+}
+
+// Close stops the background reloads. It is a no-op if the provider has no
+// reload interval.
+func (p *ReloadingProvider) Close() {
+	if p.stop == nil {
+		return
+	}
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+func (p *ReloadingProvider) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.Reload(); err != nil && p.errorHandler != nil {
+				p.errorHandler(err)
+			}
+		}
+	}
+}
+
+func (p *ReloadingProvider) parse(serialized []byte) (*Handle, error) {
+	var reader Reader
+	if p.json {
+		reader = NewJSONReader(bytes.NewReader(serialized))
+	} else {
+		reader = NewBinaryReader(bytes.NewReader(serialized))
+	}
+	if p.masterKey != nil {
+		return ReadWithAssociatedData(reader, p.masterKey, p.associatedData)
+	}
+	return ReadWithNoSecrets(reader)
+}
+
+// derive registers a function that is called with every new handle before it
+// is swapped in. The returned commit function is called while the swap holds
+// the lock, so that readers using p.mu see the handle and the derived
+// primitive change together.
+func (p *ReloadingProvider) derive(fn func(*Handle) (func(), error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.derived = append(p.derived, fn)
+}
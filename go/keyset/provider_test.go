@@ -0,0 +1,240 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/tink"
+)
+
+// memSource is a keyset.Source whose content is set by the test.
+type memSource struct {
+	mu   sync.Mutex
+	data []byte
+	err  error
+}
+
+func (s *memSource) set(data []byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data, s.err = data, err
+}
+
+func (s *memSource) source() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data, s.err
+}
+
+func encryptedKeyset(t *testing.T, h *keyset.Handle, masterKey tink.AEAD) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	if err := h.WriteWithAssociatedData(keyset.NewBinaryWriter(buf), masterKey, []byte("ad")); err != nil {
+		t.Fatalf("h.WriteWithAssociatedData() failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReloadingProviderEncryptedKeyset(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	manager := keyset.NewManager()
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	src := &memSource{}
+	src.set(encryptedKeyset(t, h, masterKey), nil)
+
+	p, err := keyset.NewReloadingProvider(src.source, keyset.WithMasterKey(masterKey, []byte("ad")))
+	if err != nil {
+		t.Fatalf("keyset.NewReloadingProvider() failed: %v", err)
+	}
+	first := p.Handle()
+	if first.KeysetInfo().GetPrimaryKeyId() != h.KeysetInfo().GetPrimaryKeyId() {
+		t.Errorf("primary key ID = %d, want %d", first.KeysetInfo().GetPrimaryKeyId(), h.KeysetInfo().GetPrimaryKeyId())
+	}
+	var changes int
+	p.OnChange(func(old, new *keyset.Handle) {
+		changes++
+		if old != first || new != p.Handle() {
+			t.Errorf("OnChange called with unexpected handles")
+		}
+	})
+
+	// An unchanged keyset keeps the handle.
+	if err := p.Reload(); err != nil {
+		t.Fatalf("p.Reload() failed: %v", err)
+	}
+	if p.Handle() != first || changes != 0 {
+		t.Errorf("p.Reload() of an unchanged keyset swapped the handle")
+	}
+
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	h, err = manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	src.set(encryptedKeyset(t, h, masterKey), nil)
+	if err := p.Reload(); err != nil {
+		t.Fatalf("p.Reload() failed: %v", err)
+	}
+	if changes != 1 {
+		t.Errorf("OnChange called %d times, want 1", changes)
+	}
+	if got, want := p.Handle().KeysetInfo().GetPrimaryKeyId(), h.KeysetInfo().GetPrimaryKeyId(); got != want {
+		t.Errorf("primary key ID = %d, want %d", got, want)
+	}
+}
+
+func TestReloadingProviderKeepsHandleOnError(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	src := &memSource{}
+	src.set(encryptedKeyset(t, h, masterKey), nil)
+	p, err := keyset.NewReloadingProvider(src.source, keyset.WithMasterKey(masterKey, []byte("ad")))
+	if err != nil {
+		t.Fatalf("keyset.NewReloadingProvider() failed: %v", err)
+	}
+	first := p.Handle()
+
+	src.set(nil, errors.New("unavailable"))
+	if err := p.Reload(); err == nil {
+		t.Error("p.Reload() succeeded with a failing source, want error")
+	}
+	src.set([]byte("invalid keyset"), nil)
+	if err := p.Reload(); err == nil {
+		t.Error("p.Reload() succeeded with an invalid keyset, want error")
+	}
+	if p.Handle() != first {
+		t.Error("p.Reload() swapped the handle after an error")
+	}
+}
+
+func TestReloadingProviderRequiresMasterKeyForSecrets(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	src := &memSource{}
+	src.set(encryptedKeyset(t, h, masterKey), nil)
+	if _, err := keyset.NewReloadingProvider(src.source); err == nil {
+		t.Error("keyset.NewReloadingProvider() without master key succeeded, want error")
+	}
+	if _, err := keyset.NewReloadingProvider(nil); err == nil {
+		t.Error("keyset.NewReloadingProvider(nil) succeeded, want error")
+	}
+}
+
+func TestReloadingProviderFileSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyset")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "public_keyset.json")
+	writePublicKeyset := func() *keyset.Handle {
+		t.Helper()
+		priv, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+		if err != nil {
+			t.Fatalf("keyset.NewHandle() failed: %v", err)
+		}
+		pub, err := priv.Public()
+		if err != nil {
+			t.Fatalf("priv.Public() failed: %v", err)
+		}
+		buf := &bytes.Buffer{}
+		if err := pub.WriteWithNoSecrets(keyset.NewJSONWriter(buf)); err != nil {
+			t.Fatalf("pub.WriteWithNoSecrets() failed: %v", err)
+		}
+		// Write and rename, so that the reload never sees a partial file.
+		tmp := path + ".tmp"
+		if err := ioutil.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+			t.Fatalf("ioutil.WriteFile() failed: %v", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatalf("os.Rename() failed: %v", err)
+		}
+		return pub
+	}
+	writePublicKeyset()
+
+	var errs []error
+	var mu sync.Mutex
+	p, err := keyset.NewReloadingProvider(keyset.FileSource(path),
+		keyset.WithJSONFormat(),
+		keyset.WithReloadInterval(10*time.Millisecond),
+		keyset.WithReloadErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}))
+	if err != nil {
+		t.Fatalf("keyset.NewReloadingProvider() failed: %v", err)
+	}
+	defer p.Close()
+	changed := make(chan *keyset.Handle, 1)
+	p.OnChange(func(old, new *keyset.Handle) {
+		changed <- new
+	})
+
+	want := writePublicKeyset()
+	select {
+	case got := <-changed:
+		if got.KeysetInfo().GetPrimaryKeyId() != want.KeysetInfo().GetPrimaryKeyId() {
+			t.Errorf("primary key ID = %d, want %d", got.KeysetInfo().GetPrimaryKeyId(), want.KeysetInfo().GetPrimaryKeyId())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("keyset was not reloaded")
+	}
+	p.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 0 {
+		t.Errorf("reload errors: %v", errs)
+	}
+}
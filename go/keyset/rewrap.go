@@ -0,0 +1,78 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/tink"
+)
+
+// Rewrap reads an encrypted keyset from reader, decrypts it with
+// oldMasterKey, and writes it to writer encrypted with newMasterKey, for
+// example to rotate the KMS key protecting a keyset or to migrate it to
+// another cloud. The keyset and its annotations are written unchanged.
+//
+// Before anything is written, Rewrap checks that the keyset can be decrypted
+// again with newMasterKey, so that a misconfigured new key is detected before
+// the old encrypted keyset is overwritten. ctx is checked between the calls to
+// the master keys, which do not take a context themselves.
+func Rewrap(ctx context.Context, reader Reader, oldMasterKey, newMasterKey tink.AEAD, writer Writer) error {
+	return RewrapWithAssociatedData(ctx, reader, oldMasterKey, newMasterKey, writer, []byte{})
+}
+
+// RewrapWithAssociatedData is like Rewrap, for keysets written with
+// WriteWithAssociatedData. The keyset is written with the same associated
+// data.
+func RewrapWithAssociatedData(ctx context.Context, reader Reader, oldMasterKey, newMasterKey tink.AEAD, writer Writer, associatedData []byte) error {
+	if oldMasterKey == nil || newMasterKey == nil {
+		return fmt.Errorf("keyset.Handle: cannot rewrap, need old and new master keys")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	h, err := ReadWithAssociatedData(reader, oldMasterKey, associatedData)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	encrypted, err := encrypt(h.ks, newMasterKey, associatedData)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	ks, err := decrypt(encrypted, newMasterKey, associatedData)
+	if err != nil {
+		return fmt.Errorf("keyset.Handle: cannot rewrap, new master key cannot decrypt: %v", err)
+	}
+	if !proto.Equal(ks, h.ks) {
+		return fmt.Errorf("keyset.Handle: cannot rewrap, new master key does not round-trip the keyset")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := writer.WriteEncrypted(encrypted); err != nil {
+		return err
+	}
+	return writeAnnotations(writer, h.annotations)
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/tink"
+)
+
+func newRewrapMasterKey(t *testing.T, c string) tink.AEAD {
+	t.Helper()
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat(c, 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	return masterKey
+}
+
+// brokenAEAD encrypts, but cannot decrypt.
+type brokenAEAD struct{ tink.AEAD }
+
+func (brokenAEAD) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	return nil, errors.New("broken")
+}
+
+func TestRewrap(t *testing.T) {
+	oldMasterKey, newMasterKey := newRewrapMasterKey(t, "A"), newRewrapMasterKey(t, "B")
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	h, err = h.WithAnnotations(map[string]string{"owner": "payments"})
+	if err != nil {
+		t.Fatalf("h.WithAnnotations() failed: %v", err)
+	}
+	ad := []byte("payments")
+	oldMem := &keyset.MemReaderWriter{}
+	if err := h.WriteWithAssociatedData(oldMem, oldMasterKey, ad); err != nil {
+		t.Fatalf("h.WriteWithAssociatedData() failed: %v", err)
+	}
+
+	newMem := &keyset.MemReaderWriter{}
+	if err := keyset.RewrapWithAssociatedData(context.Background(), oldMem, oldMasterKey, newMasterKey, newMem, ad); err != nil {
+		t.Fatalf("keyset.RewrapWithAssociatedData() failed: %v", err)
+	}
+	if _, err := keyset.ReadWithAssociatedData(newMem, oldMasterKey, ad); err == nil {
+		t.Error("reading the rewrapped keyset with the old master key succeeded, want error")
+	}
+	h2, err := keyset.ReadWithAssociatedData(newMem, newMasterKey, ad)
+	if err != nil {
+		t.Fatalf("keyset.ReadWithAssociatedData() failed: %v", err)
+	}
+	if !proto.Equal(testkeyset.KeysetMaterial(h), testkeyset.KeysetMaterial(h2)) {
+		t.Error("rewrapped keyset differs from the original")
+	}
+	if got := h2.Annotations()["owner"]; got != "payments" {
+		t.Errorf("rewrapped keyset has owner annotation %q, want %q", got, "payments")
+	}
+
+	if err := keyset.Rewrap(context.Background(), oldMem, oldMasterKey, newMasterKey, &keyset.MemReaderWriter{}); err == nil {
+		t.Error("keyset.Rewrap() without the associated data succeeded, want error")
+	}
+}
+
+func TestRewrapFailures(t *testing.T) {
+	oldMasterKey, newMasterKey := newRewrapMasterKey(t, "A"), newRewrapMasterKey(t, "B")
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	oldMem := &keyset.MemReaderWriter{}
+	if err := h.Write(oldMem, oldMasterKey); err != nil {
+		t.Fatalf("h.Write() failed: %v", err)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, tc := range []struct {
+		name         string
+		ctx          context.Context
+		oldMasterKey tink.AEAD
+		newMasterKey tink.AEAD
+	}{
+		{"wrong old master key", context.Background(), newMasterKey, newMasterKey},
+		{"new master key cannot decrypt", context.Background(), oldMasterKey, brokenAEAD{newMasterKey}},
+		{"missing new master key", context.Background(), oldMasterKey, nil},
+		{"canceled", canceled, oldMasterKey, newMasterKey},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			newMem := &keyset.MemReaderWriter{}
+			if err := keyset.Rewrap(tc.ctx, oldMem, tc.oldMasterKey, tc.newMasterKey, newMem); err == nil {
+				t.Error("keyset.Rewrap() succeeded, want error")
+			}
+			if newMem.EncryptedKeyset != nil {
+				t.Error("keyset.Rewrap() wrote a keyset despite failing")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// RotationPolicy configures when a Rotator generates a new primary key.
+type RotationPolicy struct {
+	// Template is the key template of the generated keys. It must be set.
+	Template *tinkpb.KeyTemplate
+
+	// MaxAge is how long a key stays primary before a new key is generated.
+	// Zero means no limit.
+	MaxAge time.Duration
+
+	// MaxOperations is the number of operations recorded with
+	// Rotator.RecordOperations after which a new key is generated. Zero means
+	// no limit.
+	MaxOperations int
+}
+
+// Rotator generates and promotes new primary keys of a keyset according to a
+// RotationPolicy. Old keys stay enabled, so that data protected with them can
+// still be decrypted or verified.
+//
+// Keys do not record when they were created, so the age of the primary key is
+// counted from when the Rotator was created, unless WithPrimarySince is given.
+//
+// It is safe for concurrent use.
+type Rotator struct {
+	policy    RotationPolicy
+	writer    Writer
+	masterKey tink.AEAD
+
+	mu          sync.Mutex
+	ks          *tinkpb.Keyset
+	annotations map[string]string
+	since       time.Time
+	operations  int
+}
+
+// RotatorOption configures a Rotator.
+type RotatorOption func(*Rotator)
+
+// WithKeysetWriter makes the Rotator write the keyset encrypted with
+// masterKey to w after every rotation.
+func WithKeysetWriter(w Writer, masterKey tink.AEAD) RotatorOption {
+	return func(r *Rotator) {
+		r.writer = w
+		r.masterKey = masterKey
+	}
+}
+
+// WithPrimarySince sets when the current primary key was promoted, for
+// example as stored by the application alongside the keyset.
+func WithPrimarySince(t time.Time) RotatorOption {
+	return func(r *Rotator) {
+		r.since = t
+	}
+}
+
+// NewRotator returns a Rotator for the keyset in h. The Rotator works on a
+// copy of the keyset, so h is not modified.
+func NewRotator(h *Handle, policy RotationPolicy, opts ...RotatorOption) (*Rotator, error) {
+	if h == nil {
+		return nil, errors.New("keyset.Rotator: nil keyset handle")
+	}
+	if policy.Template == nil {
+		return nil, errors.New("keyset.Rotator: need key template")
+	}
+	if policy.MaxAge < 0 || policy.MaxOperations < 0 {
+		return nil, errors.New("keyset.Rotator: invalid rotation policy")
+	}
+	r := &Rotator{
+		policy:      policy,
+		ks:          proto.Clone(h.ks).(*tinkpb.Keyset),
+		annotations: h.annotations,
+		since:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.writer != nil && r.masterKey == nil {
+		return nil, errors.New("keyset.Rotator: need master key to write keyset")
+	}
+	return r, nil
+}
+
+// Handle returns a handle for the current keyset, first rotating the keyset if
+// the policy requires it. The returned handle is not affected by later
+// rotations.
+func (r *Rotator) Handle() (*Handle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dueLocked() {
+		if err := r.rotateLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return &Handle{ks: r.ks, annotations: r.annotations}, nil
+}
+
+// RecordOperations records that n operations were performed with the current
+// primary key, and rotates the keyset if this reaches MaxOperations.
+func (r *Rotator) RecordOperations(n int) error {
+	if n < 0 {
+		return fmt.Errorf("keyset.Rotator: invalid number of operations %d", n)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations += n
+	if r.dueLocked() {
+		return r.rotateLocked()
+	}
+	return nil
+}
+
+// Rotate generates and promotes a new primary key, regardless of the policy.
+func (r *Rotator) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rotateLocked()
+}
+
+// dueLocked returns whether the policy requires a new primary key.
+func (r *Rotator) dueLocked() bool {
+	if r.policy.MaxAge > 0 && time.Since(r.since) >= r.policy.MaxAge {
+		return true
+	}
+	return r.policy.MaxOperations > 0 && r.operations >= r.policy.MaxOperations
+}
+
+// rotateLocked adds a new primary key to a copy of the keyset, persists it if
+// a writer is configured, and only then makes it the current keyset.
+func (r *Rotator) rotateLocked() error {
+	km := NewManagerFromHandle(&Handle{ks: r.ks})
+	if err := km.Rotate(r.policy.Template); err != nil {
+		return fmt.Errorf("keyset.Rotator: %s", err)
+	}
+	ks := km.ks
+	if r.writer != nil {
+		if err := (&Handle{ks: ks, annotations: r.annotations}).Write(r.writer, r.masterKey); err != nil {
+			return fmt.Errorf("keyset.Rotator: cannot write keyset: %s", err)
+		}
+	}
+	r.ks = ks
+	r.since = time.Now()
+	r.operations = 0
+	return nil
+}
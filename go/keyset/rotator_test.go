@@ -0,0 +1,220 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(*tinkpb.Keyset) error {
+	return errors.New("write failed")
+}
+
+func (failingWriter) WriteEncrypted(*tinkpb.EncryptedKeyset) error {
+	return errors.New("write failed")
+}
+
+func newRotatorTestHandle(t *testing.T) *keyset.Handle {
+	t.Helper()
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	return h
+}
+
+func rotatorHandle(t *testing.T, r *keyset.Rotator) *keyset.Handle {
+	t.Helper()
+	h, err := r.Handle()
+	if err != nil {
+		t.Fatalf("r.Handle() failed: %v", err)
+	}
+	return h
+}
+
+func TestRotatorMaxOperations(t *testing.T) {
+	h := newRotatorTestHandle(t)
+	r, err := keyset.NewRotator(h, keyset.RotationPolicy{
+		Template:      mac.HMACSHA256Tag128KeyTemplate(),
+		MaxOperations: 10,
+	})
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	first := rotatorHandle(t, r)
+	if first.KeysetInfo().PrimaryKeyId != h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key changed before reaching MaxOperations")
+	}
+	if err := r.RecordOperations(9); err != nil {
+		t.Fatalf("r.RecordOperations(9) failed: %v", err)
+	}
+	if rotatorHandle(t, r).KeysetInfo().PrimaryKeyId != h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key changed before reaching MaxOperations")
+	}
+	if err := r.RecordOperations(1); err != nil {
+		t.Fatalf("r.RecordOperations(1) failed: %v", err)
+	}
+	second := rotatorHandle(t, r)
+	if second.KeysetInfo().PrimaryKeyId == h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key didn't change after reaching MaxOperations")
+	}
+	if got := len(second.KeysetInfo().KeyInfo); got != 2 {
+		t.Errorf("number of keys = %d, want 2", got)
+	}
+	if got := len(first.KeysetInfo().KeyInfo); got != 1 {
+		t.Errorf("number of keys in handle returned before rotation = %d, want 1", got)
+	}
+	if got := len(h.KeysetInfo().KeyInfo); got != 1 {
+		t.Errorf("number of keys in original handle = %d, want 1", got)
+	}
+
+	// Data authenticated with the old key can still be verified.
+	oldMAC, err := mac.New(first)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	newMAC, err := mac.New(second)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	tag, err := oldMAC.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("ComputeMAC() failed: %v", err)
+	}
+	if err := newMAC.VerifyMAC(tag, []byte("data")); err != nil {
+		t.Errorf("VerifyMAC() of tag computed with the old key failed: %v", err)
+	}
+}
+
+func TestRotatorMaxAge(t *testing.T) {
+	h := newRotatorTestHandle(t)
+	r, err := keyset.NewRotator(h, keyset.RotationPolicy{
+		Template: mac.HMACSHA256Tag128KeyTemplate(),
+		MaxAge:   20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	if rotatorHandle(t, r).KeysetInfo().PrimaryKeyId != h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key changed before reaching MaxAge")
+	}
+	time.Sleep(40 * time.Millisecond)
+	rotated := rotatorHandle(t, r)
+	if rotated.KeysetInfo().PrimaryKeyId == h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key didn't change after reaching MaxAge")
+	}
+	if rotatorHandle(t, r).KeysetInfo().PrimaryKeyId != rotated.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key changed again right after rotation")
+	}
+}
+
+func TestRotatorWithPrimarySince(t *testing.T) {
+	h := newRotatorTestHandle(t)
+	r, err := keyset.NewRotator(h, keyset.RotationPolicy{
+		Template: mac.HMACSHA256Tag128KeyTemplate(),
+		MaxAge:   time.Hour,
+	}, keyset.WithPrimarySince(time.Now().Add(-2*time.Hour)))
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	if rotatorHandle(t, r).KeysetInfo().PrimaryKeyId == h.KeysetInfo().PrimaryKeyId {
+		t.Errorf("primary key older than MaxAge wasn't rotated")
+	}
+}
+
+func TestRotatorWithKeysetWriter(t *testing.T) {
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	memKeyset := &keyset.MemReaderWriter{}
+	r, err := keyset.NewRotator(newRotatorTestHandle(t), keyset.RotationPolicy{
+		Template: mac.HMACSHA256Tag128KeyTemplate(),
+	}, keyset.WithKeysetWriter(memKeyset, masterKey))
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	if memKeyset.EncryptedKeyset != nil {
+		t.Errorf("keyset was written before rotation")
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatalf("r.Rotate() failed: %v", err)
+	}
+	written, err := keyset.Read(memKeyset, masterKey)
+	if err != nil {
+		t.Fatalf("keyset.Read() failed: %v", err)
+	}
+	if written.String() != rotatorHandle(t, r).String() {
+		t.Errorf("written keyset %s, want %s", written, rotatorHandle(t, r))
+	}
+
+	// A failed write leaves the keyset unchanged.
+	r, err = keyset.NewRotator(newRotatorTestHandle(t), keyset.RotationPolicy{
+		Template: mac.HMACSHA256Tag128KeyTemplate(),
+	}, keyset.WithKeysetWriter(&failingWriter{}, masterKey))
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	before := rotatorHandle(t, r).String()
+	if err := r.Rotate(); err == nil {
+		t.Errorf("r.Rotate() succeeded with a failing writer, want error")
+	}
+	if after := rotatorHandle(t, r).String(); after != before {
+		t.Errorf("keyset changed after failed rotation: got %s, want %s", after, before)
+	}
+}
+
+func TestNewRotatorInvalidInput(t *testing.T) {
+	h := newRotatorTestHandle(t)
+	template := mac.HMACSHA256Tag128KeyTemplate()
+	for _, tc := range []struct {
+		name   string
+		h      *keyset.Handle
+		policy keyset.RotationPolicy
+	}{
+		{"nil handle", nil, keyset.RotationPolicy{Template: template}},
+		{"nil template", h, keyset.RotationPolicy{MaxAge: time.Hour}},
+		{"negative MaxAge", h, keyset.RotationPolicy{Template: template, MaxAge: -time.Hour}},
+		{"negative MaxOperations", h, keyset.RotationPolicy{Template: template, MaxOperations: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := keyset.NewRotator(tc.h, tc.policy); err == nil {
+				t.Error("keyset.NewRotator() succeeded, want error")
+			}
+		})
+	}
+	if _, err := keyset.NewRotator(h, keyset.RotationPolicy{Template: template}, keyset.WithKeysetWriter(&keyset.MemReaderWriter{}, nil)); err == nil {
+		t.Error("keyset.NewRotator() succeeded with a writer but no master key, want error")
+	}
+	r, err := keyset.NewRotator(h, keyset.RotationPolicy{Template: template})
+	if err != nil {
+		t.Fatalf("keyset.NewRotator() failed: %v", err)
+	}
+	if err := r.RecordOperations(-1); err == nil {
+		t.Error("r.RecordOperations(-1) succeeded, want error")
+	}
+}
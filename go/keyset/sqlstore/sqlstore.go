@@ -0,0 +1,181 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package sqlstore provides a keyset.Store backed by a SQL database.
+//
+// The keysets are stored in a table with the following columns, which the
+// application creates with the types of its database, for example:
+//
+//	CREATE TABLE keysets (
+//	  name    VARCHAR(255) NOT NULL,
+//	  version BIGINT       NOT NULL,
+//	  data    BLOB         NOT NULL,
+//	  PRIMARY KEY (name, version)
+//	);
+//
+// The primary key makes concurrent updates of the same version fail, which
+// Update reports as keyset.ErrVersionConflict.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/tink/go/keyset"
+)
+
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Store is a keyset.Store backed by a SQL table.
+type Store struct {
+	db     *sql.DB
+	table  string
+	dollar bool
+}
+
+var _ keyset.Store = (*Store)(nil)
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithDollarPlaceholders makes the Store use $1, $2, ... as query
+// placeholders, as PostgreSQL does, instead of ?.
+func WithDollarPlaceholders() Option {
+	return func(s *Store) {
+		s.dollar = true
+	}
+}
+
+// New returns a Store which keeps keysets in the given table of db.
+func New(db *sql.DB, table string, opts ...Option) (*Store, error) {
+	if db == nil {
+		return nil, errors.New("sqlstore: db must not be nil")
+	}
+	if !tableNameRegexp.MatchString(table) {
+		return nil, fmt.Errorf("sqlstore: invalid table name %q", table)
+	}
+	s := &Store{db: db, table: table}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// query inserts the table name into format and replaces the ? placeholders
+// if the Store uses dollar placeholders. The queries of Store contain no
+// other question marks.
+func (s *Store) query(format string) string {
+	q := fmt.Sprintf(format, s.table)
+	if !s.dollar {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Get returns the latest version of the keyset.
+func (s *Store) Get(ctx context.Context, name string) (*keyset.StoredKeyset, error) {
+	row := s.db.QueryRowContext(ctx, s.query("SELECT version, data FROM %s WHERE name = ? ORDER BY version DESC LIMIT 1"), name)
+	return scanKeyset(row, name)
+}
+
+// GetVersion returns the given version of the keyset.
+func (s *Store) GetVersion(ctx context.Context, name string, version int64) (*keyset.StoredKeyset, error) {
+	row := s.db.QueryRowContext(ctx, s.query("SELECT version, data FROM %s WHERE name = ? AND version = ?"), name, version)
+	return scanKeyset(row, name)
+}
+
+func scanKeyset(row *sql.Row, name string) (*keyset.StoredKeyset, error) {
+	stored := &keyset.StoredKeyset{}
+	if err := row.Scan(&stored.Version, &stored.Data); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %q", keyset.ErrKeysetNotFound, name)
+		}
+		return nil, fmt.Errorf("sqlstore: cannot read keyset %q: %v", name, err)
+	}
+	return stored, nil
+}
+
+// ListVersions returns the versions of the keyset in increasing order.
+func (s *Store) ListVersions(ctx context.Context, name string) ([]int64, error) {
+	rows, err := s.db.QueryContext(ctx, s.query("SELECT version FROM %s WHERE name = ? ORDER BY version"), name)
+	if err != nil {
+		return nil, fmt.Errorf("sqlstore: cannot list versions of keyset %q: %v", name, err)
+	}
+	defer rows.Close()
+	var versions []int64
+	for rows.Next() {
+		var v int64
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("sqlstore: cannot list versions of keyset %q: %v", name, err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlstore: cannot list versions of keyset %q: %v", name, err)
+	}
+	return versions, nil
+}
+
+// Update stores data as a new version of the keyset if its latest version is
+// expectedVersion.
+func (s *Store) Update(ctx context.Context, name string, data []byte, expectedVersion int64) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("sqlstore: cannot update keyset %q: %v", name, err)
+	}
+	defer tx.Rollback()
+
+	var latest sql.NullInt64
+	if err := tx.QueryRowContext(ctx, s.query("SELECT MAX(version) FROM %s WHERE name = ?"), name).Scan(&latest); err != nil {
+		return 0, fmt.Errorf("sqlstore: cannot update keyset %q: %v", name, err)
+	}
+	if latest.Int64 != expectedVersion {
+		return 0, fmt.Errorf("%w: %q is at version %d, not %d", keyset.ErrVersionConflict, name, latest.Int64, expectedVersion)
+	}
+	version := expectedVersion + 1
+	if _, err := tx.ExecContext(ctx, s.query("INSERT INTO %s (name, version, data) VALUES (?, ?, ?)"), name, version, data); err != nil {
+		// A concurrent update inserted the same version first.
+		if current, lerr := s.latestVersion(ctx, name); lerr == nil && current >= version {
+			return 0, fmt.Errorf("%w: %q was updated concurrently", keyset.ErrVersionConflict, name)
+		}
+		return 0, fmt.Errorf("sqlstore: cannot update keyset %q: %v", name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("sqlstore: cannot update keyset %q: %v", name, err)
+	}
+	return version, nil
+}
+
+func (s *Store) latestVersion(ctx context.Context, name string) (int64, error) {
+	var latest sql.NullInt64
+	err := s.db.QueryRowContext(ctx, s.query("SELECT MAX(version) FROM %s WHERE name = ?"), name).Scan(&latest)
+	return latest.Int64, err
+}
@@ -0,0 +1,229 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package sqlstore_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/keyset/sqlstore"
+)
+
+// fakeDriver is a database/sql driver which understands the queries of
+// sqlstore.Store on a single in-memory table.
+type fakeDriver struct {
+	mu      sync.Mutex
+	rows    map[string]map[int64][]byte
+	queries []string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+	if !strings.HasPrefix(s.query, "INSERT INTO keysets ") {
+		return nil, errors.New("unexpected statement")
+	}
+	name, version := args[0].(string), args[1].(int64)
+	if _, ok := d.rows[name][version]; ok {
+		return nil, errors.New("duplicate primary key")
+	}
+	if d.rows[name] == nil {
+		d.rows[name] = make(map[int64][]byte)
+	}
+	d.rows[name][version] = append([]byte{}, args[2].([]byte)...)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, s.query)
+	name := args[0].(string)
+	var latest int64
+	for v := range d.rows[name] {
+		if v > latest {
+			latest = v
+		}
+	}
+	switch {
+	case strings.HasPrefix(s.query, "SELECT MAX(version) "):
+		if latest == 0 {
+			return &fakeRows{cols: []string{"max"}, values: [][]driver.Value{{nil}}}, nil
+		}
+		return &fakeRows{cols: []string{"max"}, values: [][]driver.Value{{latest}}}, nil
+	case strings.Contains(s.query, "ORDER BY version DESC LIMIT 1"):
+		if latest == 0 {
+			return &fakeRows{cols: []string{"version", "data"}}, nil
+		}
+		return &fakeRows{cols: []string{"version", "data"}, values: [][]driver.Value{{latest, d.rows[name][latest]}}}, nil
+	case strings.HasPrefix(s.query, "SELECT version, data "):
+		version := args[1].(int64)
+		data, ok := d.rows[name][version]
+		if !ok {
+			return &fakeRows{cols: []string{"version", "data"}}, nil
+		}
+		return &fakeRows{cols: []string{"version", "data"}, values: [][]driver.Value{{version, data}}}, nil
+	case strings.HasPrefix(s.query, "SELECT version "):
+		rows := &fakeRows{cols: []string{"version"}}
+		for v := int64(1); v <= latest; v++ {
+			if _, ok := d.rows[name][v]; ok {
+				rows.values = append(rows.values, []driver.Value{v})
+			}
+		}
+		return rows, nil
+	}
+	return nil, errors.New("unexpected query")
+}
+
+type fakeRows struct {
+	cols   []string
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newTestStore(t *testing.T, opts ...sqlstore.Option) (*sqlstore.Store, *fakeDriver) {
+	t.Helper()
+	d := &fakeDriver{rows: make(map[string]map[int64][]byte)}
+	name := "sqlstore_fake_" + t.Name()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() failed: %v", err)
+	}
+	s, err := sqlstore.New(db, "keysets", opts...)
+	if err != nil {
+		t.Fatalf("sqlstore.New() failed: %v", err)
+	}
+	return s, d
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestStore(t)
+
+	if _, err := s.Get(ctx, "tenant-1"); !errors.Is(err, keyset.ErrKeysetNotFound) {
+		t.Errorf("s.Get() of a missing keyset err = %v, want ErrKeysetNotFound", err)
+	}
+	v1, err := s.Update(ctx, "tenant-1", []byte("first"), 0)
+	if err != nil || v1 != 1 {
+		t.Fatalf("s.Update() = %d, %v, want 1, nil", v1, err)
+	}
+	if _, err := s.Update(ctx, "tenant-1", []byte("conflicting"), 0); !errors.Is(err, keyset.ErrVersionConflict) {
+		t.Errorf("s.Update() with a stale version err = %v, want ErrVersionConflict", err)
+	}
+	v2, err := s.Update(ctx, "tenant-1", []byte("second"), v1)
+	if err != nil || v2 != 2 {
+		t.Fatalf("s.Update() = %d, %v, want 2, nil", v2, err)
+	}
+
+	latest, err := s.Get(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("s.Get() failed: %v", err)
+	}
+	if latest.Version != 2 || string(latest.Data) != "second" {
+		t.Errorf("s.Get() = %d, %q, want 2, %q", latest.Version, latest.Data, "second")
+	}
+	first, err := s.GetVersion(ctx, "tenant-1", 1)
+	if err != nil {
+		t.Fatalf("s.GetVersion() failed: %v", err)
+	}
+	if string(first.Data) != "first" {
+		t.Errorf("s.GetVersion(1).Data = %q, want %q", first.Data, "first")
+	}
+	if _, err := s.GetVersion(ctx, "tenant-1", 3); !errors.Is(err, keyset.ErrKeysetNotFound) {
+		t.Errorf("s.GetVersion(3) err = %v, want ErrKeysetNotFound", err)
+	}
+	versions, err := s.ListVersions(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("s.ListVersions() failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+		t.Errorf("s.ListVersions() = %v, want [1 2]", versions)
+	}
+}
+
+func TestStoreDollarPlaceholders(t *testing.T) {
+	s, d := newTestStore(t, sqlstore.WithDollarPlaceholders())
+	if _, err := s.Update(context.Background(), "tenant-1", []byte("first"), 0); err != nil {
+		t.Fatalf("s.Update() failed: %v", err)
+	}
+	for _, q := range d.queries {
+		if strings.Contains(q, "?") || !strings.Contains(q, "$1") {
+			t.Errorf("query %q doesn't use dollar placeholders", q)
+		}
+	}
+	if q := d.queries[len(d.queries)-1]; !strings.Contains(q, "($1, $2, $3)") {
+		t.Errorf("insert query = %q, want placeholders ($1, $2, $3)", q)
+	}
+}
+
+func TestNewInvalidTable(t *testing.T) {
+	for _, table := range []string{"", "keysets; DROP TABLE users", "1keysets", "a.b.c"} {
+		if _, err := sqlstore.New(&sql.DB{}, table); err == nil {
+			t.Errorf("sqlstore.New(%q) succeeded, want error", table)
+		}
+	}
+}
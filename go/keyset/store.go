@@ -0,0 +1,153 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/tink/go/tink"
+)
+
+var (
+	// ErrKeysetNotFound is returned by a Store when it has no version of the
+	// requested keyset. Use errors.Is to test for it.
+	ErrKeysetNotFound = errors.New("keyset.Store: keyset not found")
+
+	// ErrVersionConflict is returned by Store.Update when the latest version
+	// of the keyset is not the expected one, because another writer updated
+	// it in the meantime. Use errors.Is to test for it.
+	ErrVersionConflict = errors.New("keyset.Store: version conflict")
+)
+
+// StoredKeyset is a version of a serialized keyset in a Store.
+type StoredKeyset struct {
+	// Version numbers start at 1 and increase by one with every update.
+	Version int64
+	Data    []byte
+}
+
+// Store persists versions of serialized keysets by name. Each update adds a
+// new version, so that earlier versions remain available for rollback and
+// auditing. Implementations must be safe for concurrent use.
+//
+// Stores hold opaque bytes; ReadFromStore and Handle.WriteToStore encrypt and
+// decrypt keysets with a master key.
+type Store interface {
+	// Get returns the latest version of the keyset.
+	Get(ctx context.Context, name string) (*StoredKeyset, error)
+
+	// GetVersion returns the given version of the keyset.
+	GetVersion(ctx context.Context, name string, version int64) (*StoredKeyset, error)
+
+	// ListVersions returns the versions of the keyset in increasing order.
+	ListVersions(ctx context.Context, name string) ([]int64, error)
+
+	// Update stores data as a new version of the keyset if its latest version
+	// is expectedVersion, or if expectedVersion is 0 and the keyset doesn't
+	// exist yet. It returns the new version, or an error wrapping
+	// ErrVersionConflict.
+	Update(ctx context.Context, name string, data []byte, expectedVersion int64) (int64, error)
+}
+
+// ReadFromStore reads the latest version of the keyset with the given name
+// from s and decrypts it with masterKey. The name is the associated data of
+// the encryption, so a keyset can't be passed off under a different name. It
+// returns the handle and the version it was read from, which is passed to
+// WriteToStore to update the keyset.
+func ReadFromStore(ctx context.Context, s Store, name string, masterKey tink.AEAD) (*Handle, int64, error) {
+	stored, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	h, err := ReadWithAssociatedData(NewBinaryReader(bytes.NewReader(stored.Data)), masterKey, []byte(name))
+	if err != nil {
+		return nil, 0, err
+	}
+	return h, stored.Version, nil
+}
+
+// WriteToStore encrypts the keyset of h with masterKey and stores it in s as
+// a new version of the keyset with the given name, if its latest version is
+// expectedVersion. Use 0 to create a new keyset. It returns the new version.
+func (h *Handle) WriteToStore(ctx context.Context, s Store, name string, masterKey tink.AEAD, expectedVersion int64) (int64, error) {
+	buf := &bytes.Buffer{}
+	if err := h.WriteWithAssociatedData(NewBinaryWriter(buf), masterKey, []byte(name)); err != nil {
+		return 0, err
+	}
+	return s.Update(ctx, name, buf.Bytes(), expectedVersion)
+}
+
+// MemStore is a Store that holds keysets in memory, e.g. for tests.
+type MemStore struct {
+	mu       sync.Mutex
+	versions map[string][][]byte
+}
+
+var _ Store = &MemStore{}
+
+// Get returns the latest version of the keyset.
+func (m *MemStore) Get(ctx context.Context, name string) (*StoredKeyset, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	versions := m.versions[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrKeysetNotFound, name)
+	}
+	return &StoredKeyset{Version: int64(len(versions)), Data: versions[len(versions)-1]}, nil
+}
+
+// GetVersion returns the given version of the keyset.
+func (m *MemStore) GetVersion(ctx context.Context, name string, version int64) (*StoredKeyset, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	versions := m.versions[name]
+	if version < 1 || version > int64(len(versions)) {
+		return nil, fmt.Errorf("%w: %q version %d", ErrKeysetNotFound, name, version)
+	}
+	return &StoredKeyset{Version: version, Data: versions[version-1]}, nil
+}
+
+// ListVersions returns the versions of the keyset in increasing order.
+func (m *MemStore) ListVersions(ctx context.Context, name string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	versions := make([]int64, len(m.versions[name]))
+	for i := range versions {
+		versions[i] = int64(i + 1)
+	}
+	return versions, nil
+}
+
+// Update stores data as a new version of the keyset if its latest version is
+// expectedVersion.
+func (m *MemStore) Update(ctx context.Context, name string, data []byte, expectedVersion int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.versions == nil {
+		m.versions = make(map[string][][]byte)
+	}
+	latest := int64(len(m.versions[name]))
+	if latest != expectedVersion {
+		return 0, fmt.Errorf("%w: %q is at version %d, not %d", ErrVersionConflict, name, latest, expectedVersion)
+	}
+	m.versions[name] = append(m.versions[name], append([]byte{}, data...))
+	return latest + 1, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package keyset_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/tink/go/aead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+)
+
+func TestWriteToStoreAndReadFromStore(t *testing.T) {
+	ctx := context.Background()
+	masterKey, err := subtle.NewAESGCM([]byte(strings.Repeat("A", 32)))
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCM() failed: %v", err)
+	}
+	store := &keyset.MemStore{}
+	if _, _, err := keyset.ReadFromStore(ctx, store, "tenant-1", masterKey); !errors.Is(err, keyset.ErrKeysetNotFound) {
+		t.Errorf("keyset.ReadFromStore() of a missing keyset err = %v, want ErrKeysetNotFound", err)
+	}
+
+	manager := keyset.NewManager()
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	h, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	v1, err := h.WriteToStore(ctx, store, "tenant-1", masterKey, 0)
+	if err != nil {
+		t.Fatalf("h.WriteToStore() failed: %v", err)
+	}
+	got, version, err := keyset.ReadFromStore(ctx, store, "tenant-1", masterKey)
+	if err != nil {
+		t.Fatalf("keyset.ReadFromStore() failed: %v", err)
+	}
+	if version != v1 || got.KeysetInfo().GetPrimaryKeyId() != h.KeysetInfo().GetPrimaryKeyId() {
+		t.Errorf("keyset.ReadFromStore() = %d, primary %d, want %d, primary %d", version, got.KeysetInfo().GetPrimaryKeyId(), v1, h.KeysetInfo().GetPrimaryKeyId())
+	}
+
+	// A concurrent writer that read the same version loses.
+	if err := manager.Rotate(mac.HMACSHA256Tag128KeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate() failed: %v", err)
+	}
+	h, err = manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle() failed: %v", err)
+	}
+	if _, err := h.WriteToStore(ctx, store, "tenant-1", masterKey, version); err != nil {
+		t.Fatalf("h.WriteToStore() failed: %v", err)
+	}
+	if _, err := h.WriteToStore(ctx, store, "tenant-1", masterKey, version); !errors.Is(err, keyset.ErrVersionConflict) {
+		t.Errorf("h.WriteToStore() with a stale version err = %v, want ErrVersionConflict", err)
+	}
+	versions, err := store.ListVersions(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("store.ListVersions() failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("store.ListVersions() = %v, want 2 versions", versions)
+	}
+
+	// The keyset is bound to its name.
+	stored, err := store.Get(ctx, "tenant-1")
+	if err != nil {
+		t.Fatalf("store.Get() failed: %v", err)
+	}
+	if _, err := store.Update(ctx, "tenant-2", stored.Data, 0); err != nil {
+		t.Fatalf("store.Update() failed: %v", err)
+	}
+	if _, _, err := keyset.ReadFromStore(ctx, store, "tenant-2", masterKey); err == nil {
+		t.Error("keyset.ReadFromStore() of a keyset copied to another name succeeded, want error")
+	}
+}
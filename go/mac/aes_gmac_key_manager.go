@@ -0,0 +1,125 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	gmacpb "github.com/google/tink/go/proto/aes_gmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	gmacKeyVersion = 0
+	gmacTypeURL    = "type.googleapis.com/google.crypto.tink.AesGmacKey"
+)
+
+var errInvalidGMACKey = errors.New("aes_gmac_key_manager: invalid key")
+var errInvalidGMACKeyFormat = errors.New("aes_gmac_key_manager: invalid key format")
+
+// aesgmacKeyManager generates new AES-GMAC keys and produces new instances of AES-GMAC.
+type aesgmacKeyManager struct{}
+
+// newAESGMACKeyManager returns a new aesgmacKeyManager.
+func newAESGMACKeyManager() *aesgmacKeyManager {
+	return new(aesgmacKeyManager)
+}
+
+// Primitive constructs a AES-GMAC instance for the given serialized AesGmacKey.
+func (km *aesgmacKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidGMACKey
+	}
+	key := new(gmacpb.AesGmacKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidGMACKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	gmac, err := subtle.NewAESGMAC(key.KeyValue)
+	if err != nil {
+		return nil, err
+	}
+	return gmac, nil
+}
+
+// NewKey generates a new AesGmacKey according to specification in the given AesGmacKeyFormat.
+func (km *aesgmacKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidGMACKeyFormat
+	}
+	keyFormat := new(gmacpb.AesGmacKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidGMACKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_gmac_key_manager: invalid key format: %s", err)
+	}
+	return &gmacpb.AesGmacKey{
+		Version:  gmacKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+	}, nil
+}
+
+// NewKeyData generates a new KeyData according to specification in the given
+// serialized AesGmacKeyFormat. This should be used solely by the key management API.
+func (km *aesgmacKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidGMACKeyFormat
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         gmacTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport checks whether this KeyManager supports the given key type.
+func (km *aesgmacKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == gmacTypeURL
+}
+
+// TypeURL returns the type URL of keys managed by this KeyManager.
+func (km *aesgmacKeyManager) TypeURL() string {
+	return gmacTypeURL
+}
+
+// validateKey validates the given AesGmacKey.
+func (km *aesgmacKeyManager) validateKey(key *gmacpb.AesGmacKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, gmacKeyVersion)
+	if err != nil {
+		return fmt.Errorf("aes_gmac_key_manager: invalid version: %s", err)
+	}
+	return subtle.ValidateAESGMACParams(uint32(len(key.KeyValue)))
+}
+
+// validateKeyFormat validates the given AesGmacKeyFormat.
+func (km *aesgmacKeyManager) validateKeyFormat(format *gmacpb.AesGmacKeyFormat) error {
+	return subtle.ValidateAESGMACParams(format.KeySize)
+}
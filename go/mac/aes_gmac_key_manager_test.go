@@ -0,0 +1,152 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	subtleMac "github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	gmacpb "github.com/google/tink/go/proto/aes_gmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestGetPrimitiveAESGMACBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESGMACTypeURL)
+	if err != nil {
+		t.Fatalf("AES-GMAC key manager not found: %s", err)
+	}
+	for _, keySize := range []uint32{16, 32} {
+		key := &gmacpb.AesGmacKey{
+			Version:  testutil.AESGMACKeyVersion,
+			KeyValue: random.GetRandomBytes(keySize),
+		}
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Fatalf("km.Primitive failed: %s", err)
+		}
+		expected, err := subtleMac.NewAESGMAC(key.KeyValue)
+		if err != nil {
+			t.Fatalf("subtleMac.NewAESGMAC failed: %s", err)
+		}
+		data := random.GetRandomBytes(20)
+		tag, err := p.(*subtleMac.AESGMAC).ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		if err := expected.VerifyMAC(tag, data); err != nil {
+			t.Errorf("VerifyMAC failed: %s", err)
+		}
+	}
+}
+
+func TestGetPrimitiveAESGMACWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESGMACTypeURL)
+	if err != nil {
+		t.Fatalf("AES-GMAC key manager not found: %s", err)
+	}
+	testKeys := []*gmacpb.AesGmacKey{
+		// bad version
+		{Version: testutil.AESGMACKeyVersion + 1, KeyValue: random.GetRandomBytes(16)},
+		// bad key size
+		{Version: testutil.AESGMACKeyVersion, KeyValue: random.GetRandomBytes(24)},
+		{Version: testutil.AESGMACKeyVersion, KeyValue: random.GetRandomBytes(15)},
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestNewKeyDataAESGMAC(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESGMACTypeURL)
+	if err != nil {
+		t.Fatalf("AES-GMAC key manager not found: %s", err)
+	}
+	for _, keySize := range []uint32{16, 32} {
+		serializedFormat, _ := proto.Marshal(&gmacpb.AesGmacKeyFormat{KeySize: keySize})
+		keyData, err := km.NewKeyData(serializedFormat)
+		if err != nil {
+			t.Fatalf("km.NewKeyData failed: %s", err)
+		}
+		if keyData.TypeUrl != testutil.AESGMACTypeURL {
+			t.Errorf("incorrect type url")
+		}
+		if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+			t.Errorf("incorrect key material type")
+		}
+		key := new(gmacpb.AesGmacKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			t.Fatalf("invalid key value")
+		}
+		if uint32(len(key.KeyValue)) != keySize {
+			t.Errorf("key has size %d, want %d", len(key.KeyValue), keySize)
+		}
+	}
+	if !km.DoesSupport(testutil.AESGMACTypeURL) || km.TypeURL() != testutil.AESGMACTypeURL {
+		t.Errorf("AESGMACKeyManager must support %s", testutil.AESGMACTypeURL)
+	}
+}
+
+func TestNewKeyAESGMACWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESGMACTypeURL)
+	if err != nil {
+		t.Fatalf("AES-GMAC key manager not found: %s", err)
+	}
+	for _, keySize := range []uint32{0, 15, 24, 33} {
+		serializedFormat, _ := proto.Marshal(&gmacpb.AesGmacKeyFormat{KeySize: keySize})
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestAESGMACKeysetRoundtrip(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.AES256GMACKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("some data")
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("p.ComputeMAC failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Errorf("p.VerifyMAC failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, []byte("other data")); err == nil {
+		t.Errorf("p.VerifyMAC succeeded with modified data")
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+var errFinished = errors.New("mac_factory: computation already finished")
+
+// hashMAC is implemented by MAC primitives that can process data incrementally.
+type hashMAC interface {
+	NewHash() hash.Hash
+}
+
+// Computer computes a MAC over data that is written to it in chunks, using the
+// primary key of a keyset. The result is the same as ComputeMAC of the MAC
+// primitive obtained from the same keyset handle over the concatenated chunks.
+//
+// A Computer is not safe for concurrent use.
+type Computer struct {
+	h        hash.Hash
+	prefix   string
+	legacy   bool
+	finished bool
+}
+
+// NewComputer creates a Computer from the given keyset handle, configured with
+// the given options.
+func NewComputer(h *keyset.Handle, opts ...Option) (*Computer, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	return newComputer(ps, newOptions(opts))
+}
+
+func newComputer(ps *primitiveset.PrimitiveSet, opts options) (*Computer, error) {
+	if ps.Primary == nil {
+		return nil, fmt.Errorf("mac_factory: no primary key")
+	}
+	if err := checkPrimary(ps.Primary, opts); err != nil {
+		return nil, err
+	}
+	p, ok := (ps.Primary.Primitive).(hashMAC)
+	if !ok {
+		return nil, fmt.Errorf("mac_factory: primary primitive does not support chunked computation")
+	}
+	return &Computer{
+		h:      p.NewHash(),
+		prefix: ps.Primary.Prefix,
+		legacy: ps.Primary.PrefixType == tinkpb.OutputPrefixType_LEGACY,
+	}, nil
+}
+
+// Write adds more data to the computation. It never returns an error unless
+// Finish has already been called.
+func (c *Computer) Write(p []byte) (int, error) {
+	if c.finished {
+		return 0, errFinished
+	}
+	return c.h.Write(p)
+}
+
+// Finish returns the MAC of all data written so far. The Computer cannot be
+// used after Finish has been called.
+func (c *Computer) Finish() ([]byte, error) {
+	if c.finished {
+		return nil, errFinished
+	}
+	c.finished = true
+	if c.legacy {
+		c.h.Write([]byte{0})
+	}
+	return c.h.Sum([]byte(c.prefix)), nil
+}
+
+// verification is a candidate key for a chunked verification.
+type verification struct {
+	h      hash.Hash
+	tag    []byte
+	legacy bool
+}
+
+// Verifier verifies a MAC over data that is written to it in chunks, using all
+// enabled keys of a keyset. The result is the same as VerifyMAC of the MAC
+// primitive obtained from the same keyset handle over the concatenated chunks.
+//
+// A Verifier is not safe for concurrent use.
+type Verifier struct {
+	candidates []*verification
+	finished   bool
+}
+
+// NewVerifier creates a Verifier for the given mac from the given keyset
+// handle, configured with the given options.
+func NewVerifier(h *keyset.Handle, mac []byte, opts ...Option) (*Verifier, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	return newVerifier(ps, mac, newOptions(opts))
+}
+
+func newVerifier(ps *primitiveset.PrimitiveSet, mac []byte, opts options) (*Verifier, error) {
+	v := new(Verifier)
+	// This also rejects raw MAC with size of 4 bytes or fewer, see VerifyMAC.
+	prefixSize := cryptofmt.NonRawPrefixSize
+	if len(mac) <= prefixSize {
+		return v, nil
+	}
+	prefixed, err := ps.EntriesForPrefix(string(mac[:prefixSize]))
+	if err != nil {
+		return nil, err
+	}
+	if err := v.addCandidates(prefixed, mac[prefixSize:]); err != nil {
+		return nil, err
+	}
+	if opts.strictPrefixOnly {
+		return v, nil
+	}
+	raw, err := ps.RawEntries()
+	if err != nil {
+		return nil, err
+	}
+	if err := v.addCandidates(raw, mac); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *Verifier) addCandidates(entries []*primitiveset.Entry, tag []byte) error {
+	for _, e := range entries {
+		p, ok := (e.Primitive).(hashMAC)
+		if !ok {
+			return fmt.Errorf("mac_factory: primitive does not support chunked verification")
+		}
+		v.candidates = append(v.candidates, &verification{
+			h:      p.NewHash(),
+			tag:    tag,
+			legacy: e.PrefixType == tinkpb.OutputPrefixType_LEGACY,
+		})
+	}
+	return nil
+}
+
+// Write adds more data to the verification. It never returns an error unless
+// Finish has already been called.
+func (v *Verifier) Write(p []byte) (int, error) {
+	if v.finished {
+		return 0, errFinished
+	}
+	for _, c := range v.candidates {
+		c.h.Write(p)
+	}
+	return len(p), nil
+}
+
+// Finish returns nil if the MAC is valid for all data written so far. The
+// Verifier cannot be used after Finish has been called.
+func (v *Verifier) Finish() error {
+	if v.finished {
+		return errFinished
+	}
+	v.finished = true
+	for _, c := range v.candidates {
+		if c.legacy {
+			c.h.Write([]byte{0})
+		}
+		if subtle.ConstantTimeCompare(c.h.Sum(nil), c.tag) == 1 {
+			return nil
+		}
+	}
+	return ErrInvalidMAC
+}
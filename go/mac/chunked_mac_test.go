@@ -0,0 +1,155 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestChunkedMACMatchesComputeMAC(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	prefixTypes := []tinkpb.OutputPrefixType{
+		tinkpb.OutputPrefixType_TINK,
+		tinkpb.OutputPrefixType_LEGACY,
+		tinkpb.OutputPrefixType_CRUNCHY,
+		tinkpb.OutputPrefixType_RAW,
+	}
+	for _, prefixType := range prefixTypes {
+		kh, err := testkeyset.NewHandle(testutil.NewTestHMACKeyset(16, prefixType))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		p, err := mac.New(kh)
+		if err != nil {
+			t.Fatalf("mac.New failed: %s", err)
+		}
+		c, err := mac.NewComputer(kh)
+		if err != nil {
+			t.Fatalf("mac.NewComputer failed: %s", err)
+		}
+		for i := 0; i < len(data); i += 64 {
+			if _, err := c.Write(data[i:min(i+64, len(data))]); err != nil {
+				t.Fatalf("c.Write failed: %s", err)
+			}
+		}
+		tag, err := c.Finish()
+		if err != nil {
+			t.Fatalf("c.Finish failed: %s", err)
+		}
+		expected, err := p.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("p.ComputeMAC failed: %s", err)
+		}
+		if !bytes.Equal(tag, expected) {
+			t.Errorf("prefix type %s: chunked MAC = %x, want %x", prefixType, tag, expected)
+		}
+		if _, err := c.Write(data); err == nil {
+			t.Errorf("c.Write after Finish succeeded unexpectedly")
+		}
+
+		v, err := mac.NewVerifier(kh, expected)
+		if err != nil {
+			t.Fatalf("mac.NewVerifier failed: %s", err)
+		}
+		for i := 0; i < len(data); i += 100 {
+			v.Write(data[i:min(i+100, len(data))])
+		}
+		if err := v.Finish(); err != nil {
+			t.Errorf("prefix type %s: v.Finish failed: %s", prefixType, err)
+		}
+		if err := v.Finish(); err == nil {
+			t.Errorf("second v.Finish succeeded unexpectedly")
+		}
+	}
+}
+
+func TestChunkedMACAESCMAC(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.AESCMACTag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("some data to authenticate in more than one block")
+	c, err := mac.NewComputer(kh)
+	if err != nil {
+		t.Fatalf("mac.NewComputer failed: %s", err)
+	}
+	c.Write(data[:20])
+	c.Write(data[20:])
+	tag, err := c.Finish()
+	if err != nil {
+		t.Fatalf("c.Finish failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Errorf("p.VerifyMAC of chunked MAC failed: %s", err)
+	}
+}
+
+func TestChunkedMACVerifierRejectsInvalidMAC(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("hello")
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("p.ComputeMAC failed: %s", err)
+	}
+	for _, m := range testutil.GenerateMutations(tag) {
+		v, err := mac.NewVerifier(kh, m)
+		if err != nil {
+			t.Fatalf("mac.NewVerifier failed: %s", err)
+		}
+		v.Write(data)
+		if err := v.Finish(); err == nil {
+			t.Errorf("v.Finish succeeded with modified mac %x", m)
+		}
+	}
+	v, err := mac.NewVerifier(kh, tag)
+	if err != nil {
+		t.Fatalf("mac.NewVerifier failed: %s", err)
+	}
+	v.Write([]byte("hellO"))
+	if err := v.Finish(); err == nil {
+		t.Errorf("v.Finish succeeded with modified data")
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -19,6 +19,7 @@ package mac
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
@@ -85,6 +86,31 @@ func (km *hmacKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, err
 	}, nil
 }
 
+// DeriveKey derives a new key according to specification in the given
+// serialized HmacKeyFormat, using the bytes read from pseudorandomness as key
+// material.
+func (km *hmacKeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidHMACKeyFormat
+	}
+	keyFormat := new(hmacpb.HmacKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidHMACKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("hmac_key_manager: invalid key format: %s", err)
+	}
+	keyValue := make([]byte, keyFormat.KeySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("hmac_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &hmacpb.HmacKey{
+		Version:  hmacKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: keyValue,
+	}, nil
+}
+
 // NewKeyData generates a new KeyData according to specification in the given
 // serialized HMACKeyFormat. This should be used solely by the key management API.
 func (km *hmacKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
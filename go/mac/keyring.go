@@ -0,0 +1,111 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+)
+
+// KeyRingOption configures a KeyRingVerifier.
+type KeyRingOption func(*KeyRingVerifier)
+
+// WithKeyRingMonitor makes the KeyRingVerifier call monitor after every
+// verification, with the label of the keyset which verified the MAC, or with
+// "" and the error if none did.
+func WithKeyRingMonitor(monitor func(label string, err error)) KeyRingOption {
+	return func(v *KeyRingVerifier) {
+		v.monitor = monitor
+	}
+}
+
+// KeyRingVerifier is a MAC which verifies MACs with several keysets, for
+// example those shared with different upstream services. Each keyset has a
+// label, which identifies it in errors and monitoring. It only verifies MACs:
+// ComputeMAC always fails, since it would be ambiguous which keyset to use.
+type KeyRingVerifier struct {
+	labels  []string
+	macs    []tink.MAC
+	monitor func(label string, err error)
+}
+
+var _ tink.MAC = (*KeyRingVerifier)(nil)
+
+// NewKeyRingVerifier returns a KeyRingVerifier for the keysets in handles,
+// keyed by their labels. Keysets are tried in the order of their labels.
+func NewKeyRingVerifier(handles map[string]*keyset.Handle, opts ...KeyRingOption) (*KeyRingVerifier, error) {
+	if len(handles) == 0 {
+		return nil, fmt.Errorf("mac_factory: key ring has no keysets")
+	}
+	v := new(KeyRingVerifier)
+	for label := range handles {
+		v.labels = append(v.labels, label)
+	}
+	sort.Strings(v.labels)
+	for _, label := range v.labels {
+		h := handles[label]
+		if h == nil {
+			return nil, fmt.Errorf("mac_factory: key ring keyset %q is nil", label)
+		}
+		m, err := New(h)
+		if err != nil {
+			return nil, fmt.Errorf("mac_factory: key ring keyset %q: %s", label, err)
+		}
+		v.macs = append(v.macs, m)
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// ComputeMAC always returns an error.
+func (v *KeyRingVerifier) ComputeMAC(data []byte) ([]byte, error) {
+	return nil, errors.New("mac_factory: a key ring can only verify MACs")
+}
+
+// VerifyMAC returns nil if mac is a correct authentication code for data under
+// a key of any of the keysets. Otherwise the returned error wraps
+// ErrInvalidMAC.
+func (v *KeyRingVerifier) VerifyMAC(mac, data []byte) error {
+	_, err := v.VerifyMACWithLabel(mac, data)
+	return err
+}
+
+// VerifyMACWithLabel is like VerifyMAC, and also returns the label of the
+// keyset which verified the MAC.
+func (v *KeyRingVerifier) VerifyMACWithLabel(mac, data []byte) (string, error) {
+	label, err := v.verifyMAC(mac, data)
+	if v.monitor != nil {
+		v.monitor(label, err)
+	}
+	return label, err
+}
+
+func (v *KeyRingVerifier) verifyMAC(mac, data []byte) (string, error) {
+	for i, m := range v.macs {
+		if err := m.VerifyMAC(mac, data); err == nil {
+			return v.labels[i], nil
+		}
+	}
+	return "", fmt.Errorf("%w by any of the keysets %s", ErrInvalidMAC, strings.Join(v.labels, ", "))
+}
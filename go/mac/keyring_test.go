@@ -0,0 +1,96 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/tink"
+)
+
+func newKeyRingMAC(t *testing.T) (tink.MAC, *keyset.Handle) {
+	t.Helper()
+	h, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	m, err := mac.New(h)
+	if err != nil {
+		t.Fatalf("mac.New() failed: %v", err)
+	}
+	return m, h
+}
+
+func TestKeyRingVerifier(t *testing.T) {
+	billing, billingHandle := newKeyRingMAC(t)
+	payments, paymentsHandle := newKeyRingMAC(t)
+	other, _ := newKeyRingMAC(t)
+
+	var labels []string
+	v, err := mac.NewKeyRingVerifier(map[string]*keyset.Handle{
+		"billing":  billingHandle,
+		"payments": paymentsHandle,
+	}, mac.WithKeyRingMonitor(func(label string, err error) {
+		labels = append(labels, label)
+	}))
+	if err != nil {
+		t.Fatalf("mac.NewKeyRingVerifier() failed: %v", err)
+	}
+	data := []byte("data")
+	for _, tc := range []struct {
+		m     tink.MAC
+		label string
+	}{
+		{billing, "billing"},
+		{payments, "payments"},
+	} {
+		tag, err := tc.m.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC() failed: %v", err)
+		}
+		label, err := v.VerifyMACWithLabel(tag, data)
+		if err != nil || label != tc.label {
+			t.Errorf("v.VerifyMACWithLabel() = %q, %v, want %q, nil", label, err, tc.label)
+		}
+	}
+	tag, err := other.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC() failed: %v", err)
+	}
+	if err := v.VerifyMAC(tag, data); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("v.VerifyMAC() of an unknown MAC = %v, want ErrInvalidMAC", err)
+	}
+	if want := []string{"billing", "payments", ""}; len(labels) != len(want) || labels[0] != want[0] || labels[1] != want[1] || labels[2] != want[2] {
+		t.Errorf("monitor was called with labels %q, want %q", labels, want)
+	}
+	if _, err := v.ComputeMAC(data); err == nil {
+		t.Error("v.ComputeMAC() succeeded, want error")
+	}
+}
+
+func TestNewKeyRingVerifierFailures(t *testing.T) {
+	_, h := newKeyRingMAC(t)
+	if _, err := mac.NewKeyRingVerifier(nil); err == nil {
+		t.Error("mac.NewKeyRingVerifier() without keysets succeeded, want error")
+	}
+	if _, err := mac.NewKeyRingVerifier(map[string]*keyset.Handle{"a": h, "b": nil}); err == nil {
+		t.Error("mac.NewKeyRingVerifier() with a nil keyset succeeded, want error")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	kmacpb "github.com/google/tink/go/proto/kmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	kmacKeyVersion = 0
+	kmacTypeURL    = "type.googleapis.com/google.crypto.tink.KmacKey"
+)
+
+var errInvalidKMACKey = errors.New("kmac_key_manager: invalid key")
+var errInvalidKMACKeyFormat = errors.New("kmac_key_manager: invalid key format")
+
+// kmacKeyManager generates new KMAC keys and produces new instances of KMAC.
+type kmacKeyManager struct{}
+
+// newKMACKeyManager returns a new kmacKeyManager.
+func newKMACKeyManager() *kmacKeyManager {
+	return new(kmacKeyManager)
+}
+
+// Primitive constructs a KMAC instance for the given serialized KmacKey.
+func (km *kmacKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidKMACKey
+	}
+	key := new(kmacpb.KmacKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidKMACKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	kmac, err := subtle.NewKMAC(key.Params.Variant.String(), key.KeyValue, key.Params.Customization, key.Params.TagSize)
+	if err != nil {
+		return nil, err
+	}
+	return kmac, nil
+}
+
+// NewKey generates a new KmacKey according to specification in the given KmacKeyFormat.
+func (km *kmacKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidKMACKeyFormat
+	}
+	keyFormat := new(kmacpb.KmacKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidKMACKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("kmac_key_manager: invalid key format: %s", err)
+	}
+	keyValue := random.GetRandomBytes(keyFormat.KeySize)
+	return &kmacpb.KmacKey{
+		Version:  kmacKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: keyValue,
+	}, nil
+}
+
+// NewKeyData generates a new KeyData according to specification in the given
+// serialized KmacKeyFormat. This should be used solely by the key management API.
+func (km *kmacKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidKMACKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         kmacTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport checks whether this KeyManager supports the given key type.
+func (km *kmacKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == kmacTypeURL
+}
+
+// TypeURL returns the type URL of keys managed by this KeyManager.
+func (km *kmacKeyManager) TypeURL() string {
+	return kmacTypeURL
+}
+
+// validateKey validates the given KmacKey.
+func (km *kmacKeyManager) validateKey(key *kmacpb.KmacKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, kmacKeyVersion)
+	if err != nil {
+		return fmt.Errorf("kmac_key_manager: invalid version: %s", err)
+	}
+	if key.Params == nil {
+		return fmt.Errorf("kmac_key_manager: null KMAC params")
+	}
+	keySize := uint32(len(key.KeyValue))
+	return subtle.ValidateKMACParams(key.Params.Variant.String(), keySize, key.Params.TagSize)
+}
+
+// validateKeyFormat validates the given KmacKeyFormat.
+func (km *kmacKeyManager) validateKeyFormat(format *kmacpb.KmacKeyFormat) error {
+	if format.Params == nil {
+		return fmt.Errorf("null KMAC params")
+	}
+	return subtle.ValidateKMACParams(format.Params.Variant.String(), format.KeySize, format.Params.TagSize)
+}
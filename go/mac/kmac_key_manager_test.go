@@ -0,0 +1,237 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	subtleMac "github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	kmacpb "github.com/google/tink/go/proto/kmac_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestGetPrimitiveKMACBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMACTypeURL)
+	if err != nil {
+		t.Fatalf("KMAC key manager not found: %s", err)
+	}
+	for i, key := range genValidKMACKeys() {
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("unexpected error in test case %d: %s", i, err)
+			continue
+		}
+		if err := validateKMACPrimitive(p, key); err != nil {
+			t.Errorf("test case %d: %s", i, err)
+		}
+	}
+}
+
+func TestGetPrimitiveKMACWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMACTypeURL)
+	if err != nil {
+		t.Fatalf("KMAC key manager not found: %s", err)
+	}
+	for i, key := range genInvalidKMACKeys() {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestNewKeyDataKMACBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMACTypeURL)
+	if err != nil {
+		t.Fatalf("KMAC key manager not found: %s", err)
+	}
+	for i, format := range genValidKMACKeyFormats() {
+		serializedFormat, _ := proto.Marshal(format)
+		keyData, err := km.NewKeyData(serializedFormat)
+		if err != nil {
+			t.Errorf("unexpected error in test case %d: %s", i, err)
+			continue
+		}
+		if keyData.TypeUrl != testutil.KMACTypeURL {
+			t.Errorf("incorrect type url in test case %d", i)
+		}
+		if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+			t.Errorf("incorrect key material type in test case %d", i)
+		}
+		key := new(kmacpb.KmacKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			t.Errorf("invalid key value in test case %d", i)
+			continue
+		}
+		if format.KeySize != uint32(len(key.KeyValue)) || !proto.Equal(format.Params, key.Params) {
+			t.Errorf("key format and generated key do not match in test case %d", i)
+		}
+		p, err := km.Primitive(keyData.Value)
+		if err != nil {
+			t.Errorf("cannot create primitive from key in test case %d: %s", i, err)
+			continue
+		}
+		if err := validateKMACPrimitive(p, key); err != nil {
+			t.Errorf("test case %d: %s", i, err)
+		}
+	}
+}
+
+func TestNewKeyKMACWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMACTypeURL)
+	if err != nil {
+		t.Fatalf("KMAC key manager not found: %s", err)
+	}
+	for i, format := range genInvalidKMACKeyFormats() {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+		if _, err := km.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestDoesSupportKMAC(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.KMACTypeURL)
+	if err != nil {
+		t.Fatalf("KMAC key manager not found: %s", err)
+	}
+	if !km.DoesSupport(testutil.KMACTypeURL) {
+		t.Errorf("KMACKeyManager must support %s", testutil.KMACTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("KMACKeyManager must support only %s", testutil.KMACTypeURL)
+	}
+	if km.TypeURL() != testutil.KMACTypeURL {
+		t.Errorf("incorrect TypeURL()")
+	}
+}
+
+func newKMACParams(variant kmacpb.KmacVariant, tagSize uint32) *kmacpb.KmacParams {
+	return &kmacpb.KmacParams{
+		Variant:       variant,
+		TagSize:       tagSize,
+		Customization: []byte("customization"),
+	}
+}
+
+func newKMACKey(variant kmacpb.KmacVariant, keySize, tagSize uint32) *kmacpb.KmacKey {
+	return &kmacpb.KmacKey{
+		Version:  testutil.KMACKeyVersion,
+		Params:   newKMACParams(variant, tagSize),
+		KeyValue: random.GetRandomBytes(keySize),
+	}
+}
+
+func newKMACKeyFormat(variant kmacpb.KmacVariant, keySize, tagSize uint32) *kmacpb.KmacKeyFormat {
+	return &kmacpb.KmacKeyFormat{
+		Params:  newKMACParams(variant, tagSize),
+		KeySize: keySize,
+	}
+}
+
+func genValidKMACKeys() []*kmacpb.KmacKey {
+	return []*kmacpb.KmacKey{
+		newKMACKey(kmacpb.KmacVariant_KMAC128, 16, 10),
+		newKMACKey(kmacpb.KmacVariant_KMAC128, 32, 32),
+		newKMACKey(kmacpb.KmacVariant_KMAC256, 32, 32),
+		newKMACKey(kmacpb.KmacVariant_KMAC256, 64, 64),
+	}
+}
+
+func genInvalidKMACKeys() []proto.Message {
+	badVersionKey := newKMACKey(kmacpb.KmacVariant_KMAC128, 32, 32)
+	badVersionKey.Version++
+	noParamsKey := newKMACKey(kmacpb.KmacVariant_KMAC128, 32, 32)
+	noParamsKey.Params = nil
+	return []proto.Message{
+		// not a KmacKey
+		newKMACParams(kmacpb.KmacVariant_KMAC128, 32),
+		badVersionKey,
+		noParamsKey,
+		// unknown variant
+		newKMACKey(kmacpb.KmacVariant_UNKNOWN_KMAC, 32, 32),
+		// key too short
+		newKMACKey(kmacpb.KmacVariant_KMAC128, 15, 32),
+		newKMACKey(kmacpb.KmacVariant_KMAC256, 31, 32),
+		// tag size too small
+		newKMACKey(kmacpb.KmacVariant_KMAC128, 32, 9),
+		// tag size too big
+		newKMACKey(kmacpb.KmacVariant_KMAC256, 64, 65),
+	}
+}
+
+func genValidKMACKeyFormats() []*kmacpb.KmacKeyFormat {
+	return []*kmacpb.KmacKeyFormat{
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC128, 32, 32),
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC256, 64, 64),
+	}
+}
+
+func genInvalidKMACKeyFormats() []proto.Message {
+	noParamsFormat := newKMACKeyFormat(kmacpb.KmacVariant_KMAC128, 32, 32)
+	noParamsFormat.Params = nil
+	return []proto.Message{
+		noParamsFormat,
+		newKMACKeyFormat(kmacpb.KmacVariant_UNKNOWN_KMAC, 32, 32),
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC128, 15, 32),
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC256, 31, 32),
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC128, 32, 9),
+		newKMACKeyFormat(kmacpb.KmacVariant_KMAC256, 64, 65),
+	}
+}
+
+// validateKMACPrimitive checks whether the given primitive matches the given KmacKey.
+func validateKMACPrimitive(p interface{}, key *kmacpb.KmacKey) error {
+	kmacPrimitive, ok := p.(*subtleMac.KMAC)
+	if !ok {
+		return fmt.Errorf("primitive is not a KMAC")
+	}
+	keyPrimitive, err := subtleMac.NewKMAC(key.Params.Variant.String(), key.KeyValue, key.Params.Customization, key.Params.TagSize)
+	if err != nil {
+		return fmt.Errorf("cannot create KMAC from key: %s", err)
+	}
+	data := random.GetRandomBytes(20)
+	mac, err := kmacPrimitive.ComputeMAC(data)
+	if err != nil {
+		return fmt.Errorf("mac computation failed: %s", err)
+	}
+	if uint32(len(mac)) != key.Params.TagSize {
+		return fmt.Errorf("mac has size %d, want %d", len(mac), key.Params.TagSize)
+	}
+	if err := keyPrimitive.VerifyMAC(mac, data); err != nil {
+		return fmt.Errorf("mac could not be verified by primitive using the provided key: %s", err)
+	}
+	return nil
+}
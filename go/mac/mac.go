@@ -34,4 +34,21 @@ func init() {
 	if err := registry.RegisterKeyManager(newAESCMACKeyManager()); err != nil {
 		panic(fmt.Sprintf("mac.init() failed: %v", err))
 	}
+	if err := registry.RegisterKeyManager(newKMACKeyManager()); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newSipHashKeyManager()); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newPoly1305KeyManager()); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newAESGMACKeyManager()); err != nil {
+		panic(fmt.Sprintf("mac.init() failed: %v", err))
+	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("mac.init() failed: %v", err))
+		}
+	}
 }
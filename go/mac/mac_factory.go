@@ -17,7 +17,12 @@
 package mac
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"runtime"
+	"sync"
 
 	"github.com/google/tink/go/core/cryptofmt"
 	"github.com/google/tink/go/core/primitiveset"
@@ -29,11 +34,61 @@ import (
 
 const (
 	maxInt = int(^uint(0) >> 1)
+
+	// minBatchPerWorker is the smallest number of MACs that VerifyMACBatch hands
+	// to a goroutine, so that small batches are not dominated by scheduling.
+	minBatchPerWorker = 64
 )
 
-// New creates a MAC primitive from the given keyset handle.
-func New(h *keyset.Handle) (tink.MAC, error) {
-	return NewWithKeyManager(h, nil /*keyManager*/)
+// Option configures the MAC primitive returned by New.
+type Option func(*options)
+
+type options struct {
+	legacyComputeDisabled bool
+	strictPrefixOnly      bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLegacyComputeDisabled makes the MAC primitive refuse to compute MACs when
+// the primary key has output prefix type LEGACY. MACs produced by LEGACY keys
+// can still be verified.
+func WithLegacyComputeDisabled() Option {
+	return func(o *options) {
+		o.legacyComputeDisabled = true
+	}
+}
+
+// WithStrictPrefixOnly makes the MAC primitive only accept MACs that start with
+// the TINK, LEGACY or CRUNCHY prefix of a key in the keyset. Keys with output
+// prefix type RAW are never tried, so MACs without a recognized prefix fail
+// fast instead of being checked against every RAW key. Note that this also
+// rejects all MACs produced by RAW keys.
+func WithStrictPrefixOnly() Option {
+	return func(o *options) {
+		o.strictPrefixOnly = true
+	}
+}
+
+// New creates a MAC primitive from the given keyset handle, configured with the
+// given options.
+func New(h *keyset.Handle, opts ...Option) (tink.MAC, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	m, err := newWrappedMAC(ps)
+	if err != nil {
+		return nil, err
+	}
+	m.opts = newOptions(opts)
+	return m, nil
 }
 
 // NewWithKeyManager creates a MAC primitive from the given keyset handle and a custom key manager.
@@ -47,27 +102,156 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.MAC, erro
 	return newWrappedMAC(ps)
 }
 
+// NewWithRegistry creates a MAC primitive from the given keyset handle,
+// configured with the given options, using the key managers of r instead of
+// the global registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry, opts ...Option) (tink.MAC, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot obtain primitive set: %s", err)
+	}
+	m, err := newWrappedMAC(ps)
+	if err != nil {
+		return nil, err
+	}
+	m.opts = newOptions(opts)
+	return m, nil
+}
+
+// ReaderMAC is implemented by the MAC primitives returned by New. It computes and
+// verifies MACs over data read from an io.Reader, so that large inputs do not
+// have to be held in memory. The results are the same as those of ComputeMAC and
+// VerifyMAC over all data read until io.EOF.
+type ReaderMAC interface {
+	tink.MAC
+
+	// ComputeMACFromReader computes a MAC over the data read from r.
+	ComputeMACFromReader(r io.Reader) ([]byte, error)
+
+	// VerifyMACFromReader returns nil if mac is a correct authentication code
+	// for the data read from r, otherwise it returns an error.
+	VerifyMACFromReader(mac []byte, r io.Reader) error
+}
+
+// KeyInfo describes the key of a keyset that verified a MAC.
+type KeyInfo struct {
+	KeyID            uint32
+	OutputPrefixType tinkpb.OutputPrefixType
+}
+
+// KeyInfoMAC is implemented by the MAC primitives returned by New. It reports
+// which key of the keyset verified a MAC, so that MACs made with old keys can
+// be recomputed under the current primary key when they are read.
+type KeyInfoMAC interface {
+	tink.MAC
+
+	// VerifyMACWithKeyInfo returns information about the key that verified mac
+	// if mac is a correct authentication code for data, otherwise it returns an
+	// error.
+	VerifyMACWithKeyInfo(mac, data []byte) (KeyInfo, error)
+}
+
+// BatchMAC is implemented by the MAC primitives returned by New. It verifies
+// many MACs at once, which is faster than calling VerifyMAC in a loop.
+type BatchMAC interface {
+	tink.MAC
+
+	// VerifyMACBatch verifies each MAC against its data. The i-th returned error
+	// is the result of VerifyMAC(pairs[i].MAC, pairs[i].Data). Large batches are
+	// verified concurrently.
+	VerifyMACBatch(pairs []struct{ MAC, Data []byte }) []error
+}
+
+// ContextMAC is implemented by the MAC primitives returned by New. It computes
+// and verifies MACs while honoring the deadline and cancellation of a context.
+//
+// Primitives created by key managers may implement ContextMAC as well, for
+// example if they call a remote service. The primitives returned by New then
+// pass the context on to them. Other primitives run to completion once started,
+// but are not started if the context is already done.
+type ContextMAC interface {
+	tink.MAC
+
+	// ComputeMACWithContext computes a MAC for data. It returns ctx.Err() if ctx
+	// is done before the MAC is computed.
+	ComputeMACWithContext(ctx context.Context, data []byte) ([]byte, error)
+
+	// VerifyMACWithContext returns nil if mac is a correct authentication code
+	// for data, otherwise it returns an error. It returns ctx.Err() if ctx is
+	// done before the MAC is verified.
+	VerifyMACWithContext(ctx context.Context, mac, data []byte) error
+}
+
 // wrappedMAC is a MAC implementation that uses the underlying primitive set to compute and
 // verify MACs.
 type wrappedMAC struct {
-	ps *primitiveset.PrimitiveSet
+	ps   *primitiveset.PrimitiveSet
+	opts options
+
+	// The entries of ps with their primitives asserted to tink.MAC once, so
+	// that computing and verifying MACs does not repeat the assertions.
+	primary macEntry
+	entries map[string][]macEntry
+	raw     []macEntry
+}
+
+// macEntry is an entry of a primitive set together with its MAC primitive.
+type macEntry struct {
+	*primitiveset.Entry
+	mac tink.MAC
+	// ctxMAC is mac if it implements ContextMAC, and nil otherwise.
+	ctxMAC ContextMAC
+}
+
+func newMACEntry(e *primitiveset.Entry, mac tink.MAC) macEntry {
+	ctxMAC, _ := mac.(ContextMAC)
+	return macEntry{Entry: e, mac: mac, ctxMAC: ctxMAC}
+}
+
+func (e macEntry) computeMAC(ctx context.Context, data []byte) ([]byte, error) {
+	if e.ctxMAC != nil {
+		return e.ctxMAC.ComputeMACWithContext(ctx, data)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return e.mac.ComputeMAC(data)
+}
+
+func (e macEntry) verifyMAC(ctx context.Context, mac, data []byte) error {
+	if e.ctxMAC != nil {
+		return e.ctxMAC.VerifyMACWithContext(ctx, mac, data)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return e.mac.VerifyMAC(mac, data)
 }
 
 func newWrappedMAC(ps *primitiveset.PrimitiveSet) (*wrappedMAC, error) {
-	if _, ok := (ps.Primary.Primitive).(tink.MAC); !ok {
+	primary, ok := (ps.Primary.Primitive).(tink.MAC)
+	if !ok {
 		return nil, fmt.Errorf("mac_factory: not a MAC primitive")
 	}
 
-	for _, primitives := range ps.Entries {
+	entries := make(map[string][]macEntry, len(ps.Entries))
+	for prefix, primitives := range ps.Entries {
+		typed := make([]macEntry, 0, len(primitives))
 		for _, p := range primitives {
-			if _, ok := (p.Primitive).(tink.MAC); !ok {
+			mac, ok := (p.Primitive).(tink.MAC)
+			if !ok {
 				return nil, fmt.Errorf("mac_factory: not an MAC primitive")
 			}
+			typed = append(typed, newMACEntry(p, mac))
 		}
+		entries[prefix] = typed
 	}
 
 	ret := new(wrappedMAC)
 	ret.ps = ps
+	ret.primary = newMACEntry(ps.Primary, primary)
+	ret.entries = entries
+	ret.raw = entries[cryptofmt.RawPrefix]
 
 	return ret, nil
 }
@@ -75,12 +259,16 @@ func newWrappedMAC(ps *primitiveset.PrimitiveSet) (*wrappedMAC, error) {
 // ComputeMAC calculates a MAC over the given data using the primary primitive
 // and returns the concatenation of the primary's identifier and the calculated mac.
 func (m *wrappedMAC) ComputeMAC(data []byte) ([]byte, error) {
-	primary := m.ps.Primary
-	primitive, ok := (primary.Primitive).(tink.MAC)
-	if !ok {
-		return nil, fmt.Errorf("mac_factory: not a MAC primitive")
+	return m.ComputeMACWithContext(context.Background(), data)
+}
+
+// ComputeMACWithContext is like ComputeMAC, but stops early if ctx is done.
+func (m *wrappedMAC) ComputeMACWithContext(ctx context.Context, data []byte) ([]byte, error) {
+	primary := m.primary
+	if err := checkPrimary(primary.Entry, m.opts); err != nil {
+		return nil, err
 	}
-	if m.ps.Primary.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+	if primary.PrefixType == tinkpb.OutputPrefixType_LEGACY {
 		d := data
 		if len(d) == maxInt {
 			return nil, fmt.Errorf("mac_factory: data too long")
@@ -89,66 +277,163 @@ func (m *wrappedMAC) ComputeMAC(data []byte) ([]byte, error) {
 		data = append(data, d...)
 		data = append(data, byte(0))
 	}
-	mac, err := primitive.ComputeMAC(data)
+	mac, err := primary.computeMAC(ctx, data)
 	if err != nil {
 		return nil, err
 	}
 	return append([]byte(primary.Prefix), mac...), nil
 }
 
-var errInvalidMAC = fmt.Errorf("mac_factory: invalid mac")
+// checkPrimary returns an error if the primary key has expired or the options
+// forbid computing MACs with it.
+func checkPrimary(primary *primitiveset.Entry, opts options) error {
+	if err := primary.CheckNotExpired(); err != nil {
+		return err
+	}
+	if opts.legacyComputeDisabled && primary.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+		return fmt.Errorf("mac_factory: computing MACs with LEGACY keys is disabled")
+	}
+	return nil
+}
+
+// ErrInvalidMAC is returned when a MAC does not verify, as opposed to errors
+// caused by an invalid keyset or configuration. Use errors.Is to test for it.
+var ErrInvalidMAC = errors.New("mac_factory: invalid mac")
 
 // VerifyMAC verifies whether the given mac is a correct authentication code
 // for the given data.
 func (m *wrappedMAC) VerifyMAC(mac, data []byte) error {
+	_, err := m.verify(context.Background(), mac, data)
+	return err
+}
+
+// VerifyMACWithContext is like VerifyMAC, but stops early if ctx is done. In
+// that case it returns ctx.Err() instead of ErrInvalidMAC.
+func (m *wrappedMAC) VerifyMACWithContext(ctx context.Context, mac, data []byte) error {
+	_, err := m.verify(ctx, mac, data)
+	return err
+}
+
+// VerifyMACWithKeyInfo verifies whether the given mac is a correct
+// authentication code for the given data, and returns information about the
+// key that verified it.
+func (m *wrappedMAC) VerifyMACWithKeyInfo(mac, data []byte) (KeyInfo, error) {
+	entry, err := m.verify(context.Background(), mac, data)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	return KeyInfo{
+		KeyID:            entry.KeyID,
+		OutputPrefixType: entry.PrefixType,
+	}, nil
+}
+
+// verify returns the entry of the primitive set that verified the given mac.
+// It returns ctx.Err() if ctx is done before a key verified the mac.
+func (m *wrappedMAC) verify(ctx context.Context, mac, data []byte) (*primitiveset.Entry, error) {
 	// This also rejects raw MAC with size of 4 bytes or fewer. Those MACs are
 	// clearly insecure, thus should be discouraged.
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(mac) <= prefixSize {
-		return errInvalidMAC
+		return nil, ErrInvalidMAC
 	}
 
 	// try non raw keys
-	prefix := mac[:prefixSize]
 	macNoPrefix := mac[prefixSize:]
-	entries, err := m.ps.EntriesForPrefix(string(prefix))
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			entry := entries[i]
-			p, ok := (entry.Primitive).(tink.MAC)
-			if !ok {
-				return fmt.Errorf("mac_factory: not an MAC primitive")
-			}
-			if entry.PrefixType == tinkpb.OutputPrefixType_LEGACY {
-				d := data
-				if len(d) == maxInt {
-					return fmt.Errorf("mac_factory: data too long")
-				}
-				data = make([]byte, 0, len(d)+1)
-				data = append(data, d...)
-				data = append(data, byte(0))
-			}
-			if err = p.VerifyMAC(macNoPrefix, data); err == nil {
-				return nil
+	for _, entry := range m.entries[string(mac[:prefixSize])] {
+		d := data
+		if entry.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+			if len(data) == maxInt {
+				return nil, fmt.Errorf("mac_factory: data too long")
 			}
+			d = make([]byte, 0, len(data)+1)
+			d = append(d, data...)
+			d = append(d, byte(0))
+		}
+		if err := entry.verifyMAC(ctx, macNoPrefix, d); err == nil {
+			return entry.Entry, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 	}
 
-	// try raw keys
-	entries, err = m.ps.RawEntries()
-	if err == nil {
-		for i := 0; i < len(entries); i++ {
-			p, ok := (entries[i].Primitive).(tink.MAC)
-			if !ok {
-				return fmt.Errorf("mac_factory: not an MAC primitive")
-			}
+	if m.opts.strictPrefixOnly {
+		return nil, ErrInvalidMAC
+	}
 
-			if err = p.VerifyMAC(mac, data); err == nil {
-				return nil
-			}
+	// try raw keys
+	for _, entry := range m.raw {
+		if err := entry.verifyMAC(ctx, mac, data); err == nil {
+			return entry.Entry, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 	}
 
 	// nothing worked
-	return errInvalidMAC
+	return nil, ErrInvalidMAC
+}
+
+// VerifyMACBatch verifies each of the given MACs against its data. Large
+// batches are split across GOMAXPROCS goroutines.
+func (m *wrappedMAC) VerifyMACBatch(pairs []struct{ MAC, Data []byte }) []error {
+	errs := make([]error, len(pairs))
+	verifyRange := func(start, end int) {
+		for i := start; i < end; i++ {
+			_, errs[i] = m.verify(context.Background(), pairs[i].MAC, pairs[i].Data)
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if n := (len(pairs) + minBatchPerWorker - 1) / minBatchPerWorker; n < workers {
+		workers = n
+	}
+	if workers <= 1 {
+		verifyRange(0, len(pairs))
+		return errs
+	}
+	size := (len(pairs) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(pairs); start += size {
+		end := start + size
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			verifyRange(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+	return errs
+}
+
+// ComputeMACFromReader calculates a MAC over the data read from r using the
+// primary primitive. The primary primitive must support chunked computation.
+func (m *wrappedMAC) ComputeMACFromReader(r io.Reader) ([]byte, error) {
+	c, err := newComputer(m.ps, m.opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(c, r); err != nil {
+		return nil, fmt.Errorf("mac_factory: cannot read data: %s", err)
+	}
+	return c.Finish()
+}
+
+// VerifyMACFromReader verifies whether the given mac is a correct
+// authentication code for the data read from r. All primitives that may have
+// produced mac must support chunked verification.
+func (m *wrappedMAC) VerifyMACFromReader(mac []byte, r io.Reader) error {
+	v, err := newVerifier(m.ps, mac, m.opts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(v, r); err != nil {
+		return fmt.Errorf("mac_factory: cannot read data: %s", err)
+	}
+	return v.Finish()
 }
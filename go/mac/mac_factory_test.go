@@ -17,12 +17,17 @@
 package mac_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/signature"
@@ -242,3 +247,353 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Fatalf("calling New() with good *keyset.Handle failed: %s", err)
 	}
 }
+
+func TestFactoryReaderMAC(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	rm, ok := p.(mac.ReaderMAC)
+	if !ok {
+		t.Fatalf("primitive returned by mac.New is not a mac.ReaderMAC")
+	}
+	data := bytes.Repeat([]byte("some data "), 1000)
+	tag, err := rm.ComputeMACFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ComputeMACFromReader failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Errorf("VerifyMAC failed: %s", err)
+	}
+	if err := rm.VerifyMACFromReader(tag, bytes.NewReader(data)); err != nil {
+		t.Errorf("VerifyMACFromReader failed: %s", err)
+	}
+	if err := rm.VerifyMACFromReader(tag, bytes.NewReader(data[1:])); err == nil {
+		t.Errorf("VerifyMACFromReader succeeded with modified data")
+	}
+	if _, err := rm.ComputeMACFromReader(iotest.TimeoutReader(bytes.NewReader(data))); err == nil {
+		t.Errorf("ComputeMACFromReader succeeded with a failing reader")
+	}
+	if err := rm.VerifyMACFromReader(tag, iotest.TimeoutReader(bytes.NewReader(data))); err == nil {
+		t.Errorf("VerifyMACFromReader succeeded with a failing reader")
+	}
+}
+
+func TestFactoryWithLegacyComputeDisabled(t *testing.T) {
+	legacyKH, err := testkeyset.NewHandle(testutil.NewTestHMACKeyset(16, tinkpb.OutputPrefixType_LEGACY))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	data := []byte("some data")
+	p, err := mac.New(legacyKH)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("mac computation failed: %s", err)
+	}
+
+	restricted, err := mac.New(legacyKH, mac.WithLegacyComputeDisabled())
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	if _, err := restricted.ComputeMAC(data); err == nil {
+		t.Errorf("ComputeMAC with a LEGACY primary key succeeded unexpectedly")
+	}
+	if _, err := restricted.(mac.ReaderMAC).ComputeMACFromReader(bytes.NewReader(data)); err == nil {
+		t.Errorf("ComputeMACFromReader with a LEGACY primary key succeeded unexpectedly")
+	}
+	if err := restricted.VerifyMAC(tag, data); err != nil {
+		t.Errorf("VerifyMAC failed: %s", err)
+	}
+
+	tinkKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	restricted, err = mac.New(tinkKH, mac.WithLegacyComputeDisabled())
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	if _, err := restricted.ComputeMAC(data); err != nil {
+		t.Errorf("ComputeMAC with a TINK primary key failed: %s", err)
+	}
+}
+
+func TestFactoryVerifyMACWithKeyInfo(t *testing.T) {
+	ks := testutil.NewTestHMACKeyset(16, tinkpb.OutputPrefixType_TINK)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("some data")
+	for _, key := range ks.Key {
+		single, err := testkeyset.NewHandle(testutil.NewKeyset(key.KeyId, []*tinkpb.Keyset_Key{key}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		sp, err := mac.New(single)
+		if err != nil {
+			t.Fatalf("mac.New failed: %s", err)
+		}
+		tag, err := sp.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("mac computation failed: %s", err)
+		}
+		info, err := p.(mac.KeyInfoMAC).VerifyMACWithKeyInfo(tag, data)
+		if err != nil {
+			t.Fatalf("VerifyMACWithKeyInfo failed: %s", err)
+		}
+		if info.KeyID != key.KeyId || info.OutputPrefixType != key.OutputPrefixType {
+			t.Errorf("VerifyMACWithKeyInfo = %v, want key ID %d and prefix type %s", info, key.KeyId, key.OutputPrefixType)
+		}
+		if _, err := p.(mac.KeyInfoMAC).VerifyMACWithKeyInfo(tag, []byte("other data")); err == nil {
+			t.Errorf("VerifyMACWithKeyInfo succeeded with modified data")
+		}
+	}
+}
+
+func TestFactoryWithStrictPrefixOnly(t *testing.T) {
+	ks := testutil.NewTestHMACKeyset(16, tinkpb.OutputPrefixType_TINK)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh, mac.WithStrictPrefixOnly())
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("some data")
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("mac computation failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Errorf("VerifyMAC failed: %s", err)
+	}
+
+	rawKey := ks.Key[1]
+	if rawKey.OutputPrefixType != tinkpb.OutputPrefixType_RAW {
+		t.Fatalf("expect a raw key")
+	}
+	rawKH, err := testkeyset.NewHandle(testutil.NewKeyset(rawKey.KeyId, []*tinkpb.Keyset_Key{rawKey}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	rawP, err := mac.New(rawKH)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	rawTag, err := rawP.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("mac computation failed: %s", err)
+	}
+	if err := p.VerifyMAC(rawTag, data); err == nil {
+		t.Errorf("VerifyMAC of a RAW MAC succeeded with WithStrictPrefixOnly")
+	}
+	if err := p.(mac.ReaderMAC).VerifyMACFromReader(rawTag, bytes.NewReader(data)); err == nil {
+		t.Errorf("VerifyMACFromReader of a RAW MAC succeeded with WithStrictPrefixOnly")
+	}
+	lenient, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	if err := lenient.VerifyMAC(rawTag, data); err != nil {
+		t.Errorf("VerifyMAC of a RAW MAC failed: %s", err)
+	}
+}
+
+func TestFactoryVerifyMACBatch(t *testing.T) {
+	ks := testutil.NewTestHMACKeyset(16, tinkpb.OutputPrefixType_TINK)
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	var signers []tink.MAC
+	for _, key := range ks.Key {
+		single, err := testkeyset.NewHandle(testutil.NewKeyset(key.KeyId, []*tinkpb.Keyset_Key{key}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		sp, err := mac.New(single)
+		if err != nil {
+			t.Fatalf("mac.New failed: %s", err)
+		}
+		signers = append(signers, sp)
+	}
+	// Enough entries to be verified by several goroutines.
+	var pairs []struct{ MAC, Data []byte }
+	for i := 0; i < 500; i++ {
+		data := []byte(fmt.Sprintf("record %d", i))
+		tag, err := signers[i%len(signers)].ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("mac computation failed: %s", err)
+		}
+		switch i % 7 {
+		case 3:
+			data = []byte("other data")
+		case 5:
+			tag = tag[:4]
+		}
+		pairs = append(pairs, struct{ MAC, Data []byte }{tag, data})
+	}
+	errs := p.(mac.BatchMAC).VerifyMACBatch(pairs)
+	if len(errs) != len(pairs) {
+		t.Fatalf("VerifyMACBatch returned %d errors, want %d", len(errs), len(pairs))
+	}
+	for i, pair := range pairs {
+		want := p.VerifyMAC(pair.MAC, pair.Data)
+		if (errs[i] == nil) != (want == nil) {
+			t.Errorf("VerifyMACBatch()[%d] = %v, want %v", i, errs[i], want)
+		}
+		if i%7 == 3 || i%7 == 5 {
+			if errs[i] == nil {
+				t.Errorf("VerifyMACBatch()[%d] succeeded with invalid input", i)
+			}
+		} else if errs[i] != nil {
+			t.Errorf("VerifyMACBatch()[%d] failed: %s", i, errs[i])
+		}
+	}
+	if errs := p.(mac.BatchMAC).VerifyMACBatch(nil); len(errs) != 0 {
+		t.Errorf("VerifyMACBatch(nil) = %v, want no errors", errs)
+	}
+}
+
+func TestFactoryInvalidMACIsErrInvalidMAC(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	tag, err := p.ComputeMAC([]byte("data"))
+	if err != nil {
+		t.Fatalf("p.ComputeMAC failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, []byte("other data")); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("p.VerifyMAC with wrong data = %v, want mac.ErrInvalidMAC", err)
+	}
+	if err := p.VerifyMAC(tag[:3], []byte("data")); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("p.VerifyMAC with short mac = %v, want mac.ErrInvalidMAC", err)
+	}
+	v, err := mac.NewVerifier(kh, tag)
+	if err != nil {
+		t.Fatalf("mac.NewVerifier failed: %s", err)
+	}
+	v.Write([]byte("other data"))
+	if err := v.Finish(); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("v.Finish with wrong data = %v, want mac.ErrInvalidMAC", err)
+	}
+}
+
+func TestFactoryContextMACWithDoneContext(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	cp, ok := p.(mac.ContextMAC)
+	if !ok {
+		t.Fatalf("primitive does not implement mac.ContextMAC")
+	}
+	data := []byte("data")
+	tag, err := cp.ComputeMACWithContext(context.Background(), data)
+	if err != nil {
+		t.Fatalf("cp.ComputeMACWithContext failed: %s", err)
+	}
+	if err := cp.VerifyMACWithContext(context.Background(), tag, data); err != nil {
+		t.Errorf("cp.VerifyMACWithContext failed: %s", err)
+	}
+	if err := cp.VerifyMACWithContext(context.Background(), tag, []byte("other data")); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("cp.VerifyMACWithContext with wrong data = %v, want mac.ErrInvalidMAC", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cp.ComputeMACWithContext(ctx, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("cp.ComputeMACWithContext with canceled context = %v, want context.Canceled", err)
+	}
+	if err := cp.VerifyMACWithContext(ctx, tag, data); !errors.Is(err, context.Canceled) {
+		t.Errorf("cp.VerifyMACWithContext with canceled context = %v, want context.Canceled", err)
+	}
+}
+
+type contextKey struct{}
+
+// recordingMAC is a ContextMAC that records the value of contextKey in the
+// contexts it is called with.
+type recordingMAC struct {
+	tink.MAC
+	seen *[]interface{}
+}
+
+func (r *recordingMAC) ComputeMACWithContext(ctx context.Context, data []byte) ([]byte, error) {
+	*r.seen = append(*r.seen, ctx.Value(contextKey{}))
+	return r.ComputeMAC(data)
+}
+
+func (r *recordingMAC) VerifyMACWithContext(ctx context.Context, mac, data []byte) error {
+	*r.seen = append(*r.seen, ctx.Value(contextKey{}))
+	return r.VerifyMAC(mac, data)
+}
+
+// recordingKeyManager wraps the primitives of a key manager in recordingMAC.
+type recordingKeyManager struct {
+	registry.KeyManager
+	seen *[]interface{}
+}
+
+func (km *recordingKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	p, err := km.KeyManager.Primitive(serializedKey)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingMAC{MAC: p.(tink.MAC), seen: km.seen}, nil
+}
+
+func TestFactoryContextMACPassesContext(t *testing.T) {
+	hmacKM, err := registry.GetKeyManager(testutil.HMACTypeURL)
+	if err != nil {
+		t.Fatalf("registry.GetKeyManager failed: %s", err)
+	}
+	var seen []interface{}
+	kh, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.NewWithKeyManager(kh, &recordingKeyManager{KeyManager: hmacKM, seen: &seen})
+	if err != nil {
+		t.Fatalf("mac.NewWithKeyManager failed: %s", err)
+	}
+	cp := p.(mac.ContextMAC)
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	data := []byte("data")
+	tag, err := cp.ComputeMACWithContext(ctx, data)
+	if err != nil {
+		t.Fatalf("cp.ComputeMACWithContext failed: %s", err)
+	}
+	if err := cp.VerifyMACWithContext(ctx, tag, data); err != nil {
+		t.Errorf("cp.VerifyMACWithContext failed: %s", err)
+	}
+	if len(seen) != 2 || seen[0] != "value" || seen[1] != "value" {
+		t.Errorf("primitive saw context values %v, want [value value]", seen)
+	}
+}
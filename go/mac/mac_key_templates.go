@@ -19,8 +19,11 @@ package mac
 import (
 	"github.com/golang/protobuf/proto"
 	cmacpb "github.com/google/tink/go/proto/aes_cmac_go_proto"
+	gmacpb "github.com/google/tink/go/proto/aes_gmac_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	hmacpb "github.com/google/tink/go/proto/hmac_go_proto"
+	kmacpb "github.com/google/tink/go/proto/kmac_go_proto"
+	sippb "github.com/google/tink/go/proto/siphash_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
@@ -58,6 +61,22 @@ func HMACSHA512Tag512KeyTemplate() *tinkpb.KeyTemplate {
 	return createHMACKeyTemplate(64, 64, commonpb.HashType_SHA512)
 }
 
+// HMACSHA3_256Tag256KeyTemplate is a KeyTemplate that generates a HMAC key with the following parameters:
+//   - Key size: 32 bytes
+//   - Tag size: 32 bytes
+//   - Hash function: SHA3-256
+func HMACSHA3_256Tag256KeyTemplate() *tinkpb.KeyTemplate {
+	return createHMACKeyTemplate(32, 32, commonpb.HashType_SHA3_256)
+}
+
+// HMACSHA3_512Tag512KeyTemplate is a KeyTemplate that generates a HMAC key with the following parameters:
+//   - Key size: 64 bytes
+//   - Tag size: 64 bytes
+//   - Hash function: SHA3-512
+func HMACSHA3_512Tag512KeyTemplate() *tinkpb.KeyTemplate {
+	return createHMACKeyTemplate(64, 64, commonpb.HashType_SHA3_512)
+}
+
 // AESCMACTag128KeyTemplate is a KeyTemplate that generates a AES-CMAC key with the following parameters:
 //   - Key size: 32 bytes
 //   - Tag size: 16 bytes
@@ -65,6 +84,123 @@ func AESCMACTag128KeyTemplate() *tinkpb.KeyTemplate {
 	return createCMACKeyTemplate(32, 16)
 }
 
+// KMAC128Tag256KeyTemplate is a KeyTemplate that generates a KMAC key with the following parameters:
+//   - Variant: KMAC128
+//   - Key size: 32 bytes
+//   - Tag size: 32 bytes
+//   - Customization string: empty
+func KMAC128Tag256KeyTemplate() *tinkpb.KeyTemplate {
+	return createKMACKeyTemplate(kmacpb.KmacVariant_KMAC128, 32, 32)
+}
+
+// KMAC256Tag512KeyTemplate is a KeyTemplate that generates a KMAC key with the following parameters:
+//   - Variant: KMAC256
+//   - Key size: 64 bytes
+//   - Tag size: 64 bytes
+//   - Customization string: empty
+func KMAC256Tag512KeyTemplate() *tinkpb.KeyTemplate {
+	return createKMACKeyTemplate(kmacpb.KmacVariant_KMAC256, 64, 64)
+}
+
+// SipHash24Tag64KeyTemplate is a KeyTemplate that generates a SipHash-2-4 key with the following parameters:
+//   - Key size: 16 bytes
+//   - Tag size: 8 bytes
+//
+// A 64-bit tag only provides 64-bit security against forgeries.
+func SipHash24Tag64KeyTemplate() *tinkpb.KeyTemplate {
+	return createSipHashKeyTemplate(8)
+}
+
+// SipHash24Tag128KeyTemplate is a KeyTemplate that generates a SipHash-2-4 key with the following parameters:
+//   - Key size: 16 bytes
+//   - Tag size: 16 bytes
+func SipHash24Tag128KeyTemplate() *tinkpb.KeyTemplate {
+	return createSipHashKeyTemplate(16)
+}
+
+// Poly1305KeyTemplate is a KeyTemplate that generates a Poly1305 key with the following parameters:
+//   - Key size: 32 bytes
+//   - MAC size: 40 bytes (24-byte nonce and 16-byte tag)
+//
+// Every MAC uses a fresh one-time key, see subtle.Poly1305.
+func Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		// Don't set value because KeyFormat is not required.
+		TypeUrl:          poly1305TypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// AES128GMACKeyTemplate is a KeyTemplate that generates a AES-GMAC key with the following parameters:
+//   - Key size: 16 bytes
+//   - MAC size: 28 bytes (12-byte IV and 16-byte tag)
+//
+// MACs are randomized, see subtle.AESGMAC.
+func AES128GMACKeyTemplate() *tinkpb.KeyTemplate {
+	return createGMACKeyTemplate(16)
+}
+
+// AES256GMACKeyTemplate is a KeyTemplate that generates a AES-GMAC key with the following parameters:
+//   - Key size: 32 bytes
+//   - MAC size: 28 bytes (12-byte IV and 16-byte tag)
+//
+// MACs are randomized, see subtle.AESGMAC.
+func AES256GMACKeyTemplate() *tinkpb.KeyTemplate {
+	return createGMACKeyTemplate(32)
+}
+
+// TemplateOption customizes the KeyTemplate returned by HMACSHA256Template,
+// HMACSHA512Template and AESCMACTemplate.
+type TemplateOption func(*templateOptions)
+
+type templateOptions struct {
+	tagSize uint32
+}
+
+// WithTagSize sets the size in bytes of the tags produced by keys of the
+// template. Tags are truncated to this size. The size is validated when a key
+// is generated from the template: it must be at least 10 bytes and at most the
+// size of the untruncated tag.
+func WithTagSize(tagSize uint32) TemplateOption {
+	return func(o *templateOptions) {
+		o.tagSize = tagSize
+	}
+}
+
+func newTemplateOptions(defaultTagSize uint32, opts []TemplateOption) templateOptions {
+	o := templateOptions{tagSize: defaultTagSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// HMACSHA256Template returns a KeyTemplate that generates a HMAC key with the following parameters:
+//   - Key size: 32 bytes
+//   - Tag size: 32 bytes, unless changed with WithTagSize
+//   - Hash function: SHA256
+func HMACSHA256Template(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(32, opts)
+	return createHMACKeyTemplate(32, o.tagSize, commonpb.HashType_SHA256)
+}
+
+// HMACSHA512Template returns a KeyTemplate that generates a HMAC key with the following parameters:
+//   - Key size: 64 bytes
+//   - Tag size: 64 bytes, unless changed with WithTagSize
+//   - Hash function: SHA512
+func HMACSHA512Template(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(64, opts)
+	return createHMACKeyTemplate(64, o.tagSize, commonpb.HashType_SHA512)
+}
+
+// AESCMACTemplate returns a KeyTemplate that generates a AES-CMAC key with the following parameters:
+//   - Key size: 32 bytes
+//   - Tag size: 16 bytes, unless changed with WithTagSize
+func AESCMACTemplate(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(16, opts)
+	return createCMACKeyTemplate(32, o.tagSize)
+}
+
 // createHMACKeyTemplate creates a new KeyTemplate for HMAC using the given parameters.
 func createHMACKeyTemplate(keySize uint32,
 	tagSize uint32,
@@ -101,3 +237,69 @@ func createCMACKeyTemplate(keySize uint32, tagSize uint32) *tinkpb.KeyTemplate {
 		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
 	}
 }
+
+// createKMACKeyTemplate creates a new KeyTemplate for KMAC using the given parameters.
+func createKMACKeyTemplate(variant kmacpb.KmacVariant, keySize uint32, tagSize uint32) *tinkpb.KeyTemplate {
+	params := kmacpb.KmacParams{
+		Variant: variant,
+		TagSize: tagSize,
+	}
+	format := kmacpb.KmacKeyFormat{
+		Params:  &params,
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(&format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          kmacTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// createSipHashKeyTemplate creates a new KeyTemplate for SipHash using the given parameters.
+func createSipHashKeyTemplate(tagSize uint32) *tinkpb.KeyTemplate {
+	format := sippb.SipHashKeyFormat{
+		Params: &sippb.SipHashParams{
+			TagSize: tagSize,
+		},
+	}
+	serializedFormat, _ := proto.Marshal(&format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          sipHashTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// createGMACKeyTemplate creates a new KeyTemplate for AES-GMAC using the given key size.
+func createGMACKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
+	format := gmacpb.AesGmacKeyFormat{
+		KeySize: keySize,
+	}
+	serializedFormat, _ := proto.Marshal(&format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          gmacTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"HMAC_SHA256_128BITTAG":   HMACSHA256Tag128KeyTemplate,
+	"HMAC_SHA256_256BITTAG":   HMACSHA256Tag256KeyTemplate,
+	"HMAC_SHA512_256BITTAG":   HMACSHA512Tag256KeyTemplate,
+	"HMAC_SHA512_512BITTAG":   HMACSHA512Tag512KeyTemplate,
+	"HMAC_SHA3_256_256BITTAG": HMACSHA3_256Tag256KeyTemplate,
+	"HMAC_SHA3_512_512BITTAG": HMACSHA3_512Tag512KeyTemplate,
+	"AES_CMAC":                AESCMACTag128KeyTemplate,
+	"KMAC128_256BITTAG":       KMAC128Tag256KeyTemplate,
+	"KMAC256_512BITTAG":       KMAC256Tag512KeyTemplate,
+	"SIPHASH24_64BITTAG":      SipHash24Tag64KeyTemplate,
+	"SIPHASH24_128BITTAG":     SipHash24Tag128KeyTemplate,
+	"POLY1305":                Poly1305KeyTemplate,
+	"AES128_GMAC":             AES128GMACKeyTemplate,
+	"AES256_GMAC":             AES256GMACKeyTemplate,
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/cryptofmt"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/testutil"
@@ -40,8 +41,26 @@ func TestKeyTemplates(t *testing.T) {
 			template: mac.HMACSHA512Tag256KeyTemplate()},
 		{name: "HMAC_SHA512_512BITTAG",
 			template: mac.HMACSHA512Tag512KeyTemplate()},
+		{name: "HMAC_SHA3_256_256BITTAG",
+			template: mac.HMACSHA3_256Tag256KeyTemplate()},
+		{name: "HMAC_SHA3_512_512BITTAG",
+			template: mac.HMACSHA3_512Tag512KeyTemplate()},
 		{name: "AES_CMAC",
 			template: mac.AESCMACTag128KeyTemplate()},
+		{name: "KMAC128_256BITTAG",
+			template: mac.KMAC128Tag256KeyTemplate()},
+		{name: "KMAC256_512BITTAG",
+			template: mac.KMAC256Tag512KeyTemplate()},
+		{name: "SIPHASH_2_4_64BITTAG",
+			template: mac.SipHash24Tag64KeyTemplate()},
+		{name: "SIPHASH_2_4_128BITTAG",
+			template: mac.SipHash24Tag128KeyTemplate()},
+		{name: "POLY1305",
+			template: mac.Poly1305KeyTemplate()},
+		{name: "AES128_GMAC",
+			template: mac.AES128GMACKeyTemplate()},
+		{name: "AES256_GMAC",
+			template: mac.AES256GMACKeyTemplate()},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -95,3 +114,62 @@ func TestKeyTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateBuilders(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		want     *tinkpb.KeyTemplate
+	}{
+		{"HMACSHA256Template()", mac.HMACSHA256Template(), mac.HMACSHA256Tag256KeyTemplate()},
+		{"HMACSHA256Template(WithTagSize(16))", mac.HMACSHA256Template(mac.WithTagSize(16)), mac.HMACSHA256Tag128KeyTemplate()},
+		{"HMACSHA512Template()", mac.HMACSHA512Template(), mac.HMACSHA512Tag512KeyTemplate()},
+		{"HMACSHA512Template(WithTagSize(32))", mac.HMACSHA512Template(mac.WithTagSize(32)), mac.HMACSHA512Tag256KeyTemplate()},
+		{"AESCMACTemplate()", mac.AESCMACTemplate(), mac.AESCMACTag128KeyTemplate()},
+	}
+	for _, tc := range testCases {
+		if !proto.Equal(tc.template, tc.want) {
+			t.Errorf("%s = %s, want %s", tc.name, tc.template, tc.want)
+		}
+	}
+}
+
+func TestTemplateBuildersWithTagSize(t *testing.T) {
+	var testCases = []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		tagSize  int
+	}{
+		{"HMACSHA256", mac.HMACSHA256Template(mac.WithTagSize(20)), 20},
+		{"HMACSHA512", mac.HMACSHA512Template(mac.WithTagSize(48)), 48},
+		{"AESCMAC", mac.AESCMACTemplate(mac.WithTagSize(12)), 12},
+	}
+	for _, tc := range testCases {
+		handle, err := keyset.NewHandle(tc.template)
+		if err != nil {
+			t.Fatalf("%s: keyset.NewHandle failed: %s", tc.name, err)
+		}
+		p, err := mac.New(handle)
+		if err != nil {
+			t.Fatalf("%s: mac.New failed: %s", tc.name, err)
+		}
+		tag, err := p.ComputeMAC([]byte("data"))
+		if err != nil {
+			t.Fatalf("%s: p.ComputeMAC failed: %s", tc.name, err)
+		}
+		if len(tag) != cryptofmt.NonRawPrefixSize+tc.tagSize {
+			t.Errorf("%s: tag has size %d, want %d", tc.name, len(tag), cryptofmt.NonRawPrefixSize+tc.tagSize)
+		}
+	}
+
+	invalid := []*tinkpb.KeyTemplate{
+		mac.HMACSHA256Template(mac.WithTagSize(9)),
+		mac.HMACSHA256Template(mac.WithTagSize(33)),
+		mac.AESCMACTemplate(mac.WithTagSize(17)),
+	}
+	for i, template := range invalid {
+		if _, err := keyset.NewHandle(template); err == nil {
+			t.Errorf("keyset.NewHandle succeeded with invalid template %d", i)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+)
+
+// Migrator re-tags data during key rotation: it verifies a stored MAC with an
+// old keyset and computes a new MAC with the primary key of a new keyset.
+//
+// The old and new keysets may share keys, e.g. when the new keyset is the old
+// one with a new primary key added.
+type Migrator struct {
+	from tink.MAC
+	to   tink.MAC
+}
+
+// NewMigrator creates a Migrator that verifies MACs with the keyset of
+// oldHandle and computes MACs with the keyset of newHandle. The options apply
+// to both keysets.
+func NewMigrator(oldHandle, newHandle *keyset.Handle, opts ...Option) (*Migrator, error) {
+	from, err := New(oldHandle, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("mac_migrator: cannot create MAC for old keyset: %s", err)
+	}
+	to, err := New(newHandle, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("mac_migrator: cannot create MAC for new keyset: %s", err)
+	}
+	return &Migrator{from: from, to: to}, nil
+}
+
+// Migrate verifies mac over data with the old keyset and, if it is valid,
+// returns a MAC over data computed with the primary key of the new keyset.
+// If mac does not verify, the returned error is ErrInvalidMAC.
+func (m *Migrator) Migrate(mac, data []byte) ([]byte, error) {
+	if err := m.from.VerifyMAC(mac, data); err != nil {
+		return nil, err
+	}
+	return m.to.ComputeMAC(data)
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+)
+
+func TestMigrator(t *testing.T) {
+	oldKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	newKH, err := keyset.NewHandle(mac.AESCMACTag128KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	oldMAC, err := mac.New(oldKH)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	newMAC, err := mac.New(newKH)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	m, err := mac.NewMigrator(oldKH, newKH)
+	if err != nil {
+		t.Fatalf("mac.NewMigrator failed: %s", err)
+	}
+
+	data := []byte("stored record")
+	oldTag, err := oldMAC.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("oldMAC.ComputeMAC failed: %s", err)
+	}
+	newTag, err := m.Migrate(oldTag, data)
+	if err != nil {
+		t.Fatalf("m.Migrate failed: %s", err)
+	}
+	if err := newMAC.VerifyMAC(newTag, data); err != nil {
+		t.Errorf("newMAC.VerifyMAC of migrated tag failed: %s", err)
+	}
+	if err := oldMAC.VerifyMAC(newTag, data); err == nil {
+		t.Errorf("oldMAC.VerifyMAC of migrated tag succeeded unexpectedly")
+	}
+
+	if _, err := m.Migrate(oldTag, []byte("modified record")); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("m.Migrate with modified data = %v, want mac.ErrInvalidMAC", err)
+	}
+	if _, err := m.Migrate(newTag, data); !errors.Is(err, mac.ErrInvalidMAC) {
+		t.Errorf("m.Migrate of a tag of the new keyset = %v, want mac.ErrInvalidMAC", err)
+	}
+}
+
+func TestNewMigratorWithInvalidKeyset(t *testing.T) {
+	macKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	sigKH, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	if _, err := mac.NewMigrator(sigKH, macKH); err == nil {
+		t.Errorf("mac.NewMigrator with a signature keyset as old keyset succeeded")
+	}
+	if _, err := mac.NewMigrator(macKH, sigKH); err == nil {
+		t.Errorf("mac.NewMigrator with a signature keyset as new keyset succeeded")
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	poly1305pb "github.com/google/tink/go/proto/poly1305_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	poly1305KeyVersion = 0
+	poly1305TypeURL    = "type.googleapis.com/google.crypto.tink.Poly1305Key"
+)
+
+var errInvalidPoly1305Key = errors.New("poly1305_key_manager: invalid key")
+
+// poly1305KeyManager generates new Poly1305 keys and produces new instances of Poly1305.
+type poly1305KeyManager struct{}
+
+// newPoly1305KeyManager returns a new poly1305KeyManager.
+func newPoly1305KeyManager() *poly1305KeyManager {
+	return new(poly1305KeyManager)
+}
+
+// Primitive constructs a Poly1305 instance for the given serialized Poly1305Key.
+func (km *poly1305KeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidPoly1305Key
+	}
+	key := new(poly1305pb.Poly1305Key)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidPoly1305Key
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	p, err := subtle.NewPoly1305(key.KeyValue)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewKey generates a new Poly1305Key, ignoring the specification in the given
+// serialized key format because the key size is fixed.
+func (km *poly1305KeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return km.newPoly1305Key(), nil
+}
+
+// NewKeyData generates a new KeyData, ignoring the specification in the given
+// serialized key format because the key size is fixed.
+// This should be used solely by the key management API.
+func (km *poly1305KeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key := km.newPoly1305Key()
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         poly1305TypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport checks whether this KeyManager supports the given key type.
+func (km *poly1305KeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == poly1305TypeURL
+}
+
+// TypeURL returns the type URL of keys managed by this KeyManager.
+func (km *poly1305KeyManager) TypeURL() string {
+	return poly1305TypeURL
+}
+
+// validateKey validates the given Poly1305Key.
+func (km *poly1305KeyManager) validateKey(key *poly1305pb.Poly1305Key) error {
+	err := keyset.ValidateKeyVersion(key.Version, poly1305KeyVersion)
+	if err != nil {
+		return fmt.Errorf("poly1305_key_manager: invalid version: %s", err)
+	}
+	return subtle.ValidatePoly1305Params(uint32(len(key.KeyValue)))
+}
+
+func (km *poly1305KeyManager) newPoly1305Key() *poly1305pb.Poly1305Key {
+	return &poly1305pb.Poly1305Key{
+		Version:  poly1305KeyVersion,
+		KeyValue: random.GetRandomBytes(subtle.Poly1305KeySize),
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	subtleMac "github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	poly1305pb "github.com/google/tink/go/proto/poly1305_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestGetPrimitivePoly1305Basic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Poly1305TypeURL)
+	if err != nil {
+		t.Fatalf("Poly1305 key manager not found: %s", err)
+	}
+	key := &poly1305pb.Poly1305Key{
+		Version:  testutil.Poly1305KeyVersion,
+		KeyValue: random.GetRandomBytes(subtleMac.Poly1305KeySize),
+	}
+	serializedKey, _ := proto.Marshal(key)
+	p, err := km.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("km.Primitive failed: %s", err)
+	}
+	expected, err := subtleMac.NewPoly1305(key.KeyValue)
+	if err != nil {
+		t.Fatalf("subtleMac.NewPoly1305 failed: %s", err)
+	}
+	data := random.GetRandomBytes(20)
+	tag, err := p.(*subtleMac.Poly1305).ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC failed: %s", err)
+	}
+	if err := expected.VerifyMAC(tag, data); err != nil {
+		t.Errorf("VerifyMAC failed: %s", err)
+	}
+}
+
+func TestGetPrimitivePoly1305WithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Poly1305TypeURL)
+	if err != nil {
+		t.Fatalf("Poly1305 key manager not found: %s", err)
+	}
+	testKeys := []*poly1305pb.Poly1305Key{
+		// bad version
+		{Version: testutil.Poly1305KeyVersion + 1, KeyValue: random.GetRandomBytes(32)},
+		// bad key size
+		{Version: testutil.Poly1305KeyVersion, KeyValue: random.GetRandomBytes(16)},
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestNewKeyDataPoly1305(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.Poly1305TypeURL)
+	if err != nil {
+		t.Fatalf("Poly1305 key manager not found: %s", err)
+	}
+	keyData, err := km.NewKeyData(nil)
+	if err != nil {
+		t.Fatalf("km.NewKeyData failed: %s", err)
+	}
+	if keyData.TypeUrl != testutil.Poly1305TypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(poly1305pb.Poly1305Key)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("invalid key value")
+	}
+	if len(key.KeyValue) != subtleMac.Poly1305KeySize {
+		t.Errorf("key has size %d, want %d", len(key.KeyValue), subtleMac.Poly1305KeySize)
+	}
+	if !km.DoesSupport(testutil.Poly1305TypeURL) || km.TypeURL() != testutil.Poly1305TypeURL {
+		t.Errorf("Poly1305KeyManager must support %s", testutil.Poly1305TypeURL)
+	}
+}
+
+func TestPoly1305KeysetRoundtrip(t *testing.T) {
+	kh, err := keyset.NewHandle(mac.Poly1305KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	p, err := mac.New(kh)
+	if err != nil {
+		t.Fatalf("mac.New failed: %s", err)
+	}
+	data := []byte("some data")
+	tag, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("p.ComputeMAC failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, data); err != nil {
+		t.Errorf("p.VerifyMAC failed: %s", err)
+	}
+	if err := p.VerifyMAC(tag, []byte("other data")); err == nil {
+		t.Errorf("p.VerifyMAC succeeded with modified data")
+	}
+}
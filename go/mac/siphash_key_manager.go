@@ -0,0 +1,131 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	sippb "github.com/google/tink/go/proto/siphash_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	sipHashKeyVersion = 0
+	sipHashTypeURL    = "type.googleapis.com/google.crypto.tink.SipHashKey"
+)
+
+var errInvalidSipHashKey = errors.New("siphash_key_manager: invalid key")
+var errInvalidSipHashKeyFormat = errors.New("siphash_key_manager: invalid key format")
+
+// sipHashKeyManager generates new SipHash keys and produces new instances of SipHash.
+type sipHashKeyManager struct{}
+
+// newSipHashKeyManager returns a new sipHashKeyManager.
+func newSipHashKeyManager() *sipHashKeyManager {
+	return new(sipHashKeyManager)
+}
+
+// Primitive constructs a SipHash instance for the given serialized SipHashKey.
+func (km *sipHashKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidSipHashKey
+	}
+	key := new(sippb.SipHashKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidSipHashKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	s, err := subtle.NewSipHash(key.KeyValue, key.Params.TagSize)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewKey generates a new SipHashKey according to specification in the given SipHashKeyFormat.
+func (km *sipHashKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidSipHashKeyFormat
+	}
+	keyFormat := new(sippb.SipHashKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidSipHashKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("siphash_key_manager: invalid key format: %s", err)
+	}
+	return &sippb.SipHashKey{
+		Version:  sipHashKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: random.GetRandomBytes(subtle.SipHashKeySize),
+	}, nil
+}
+
+// NewKeyData generates a new KeyData according to specification in the given
+// serialized SipHashKeyFormat. This should be used solely by the key management API.
+func (km *sipHashKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidSipHashKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         sipHashTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport checks whether this KeyManager supports the given key type.
+func (km *sipHashKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == sipHashTypeURL
+}
+
+// TypeURL returns the type URL of keys managed by this KeyManager.
+func (km *sipHashKeyManager) TypeURL() string {
+	return sipHashTypeURL
+}
+
+// validateKey validates the given SipHashKey.
+func (km *sipHashKeyManager) validateKey(key *sippb.SipHashKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, sipHashKeyVersion)
+	if err != nil {
+		return fmt.Errorf("siphash_key_manager: invalid version: %s", err)
+	}
+	if key.Params == nil {
+		return fmt.Errorf("siphash_key_manager: null SipHash params")
+	}
+	return subtle.ValidateSipHashParams(uint32(len(key.KeyValue)), key.Params.TagSize)
+}
+
+// validateKeyFormat validates the given SipHashKeyFormat.
+func (km *sipHashKeyManager) validateKeyFormat(format *sippb.SipHashKeyFormat) error {
+	if format.Params == nil {
+		return fmt.Errorf("null SipHash params")
+	}
+	return subtle.ValidateSipHashParams(subtle.SipHashKeySize, format.Params.TagSize)
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package mac_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	subtleMac "github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	sippb "github.com/google/tink/go/proto/siphash_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestGetPrimitiveSipHashBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.SipHashTypeURL)
+	if err != nil {
+		t.Fatalf("SipHash key manager not found: %s", err)
+	}
+	for _, tagSize := range []uint32{8, 16} {
+		key := newSipHashKey(tagSize)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Fatalf("km.Primitive failed for tag size %d: %s", tagSize, err)
+		}
+		expected, err := subtleMac.NewSipHash(key.KeyValue, tagSize)
+		if err != nil {
+			t.Fatalf("subtleMac.NewSipHash failed: %s", err)
+		}
+		data := random.GetRandomBytes(20)
+		tag, err := p.(*subtleMac.SipHash).ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		if uint32(len(tag)) != tagSize {
+			t.Errorf("tag has size %d, want %d", len(tag), tagSize)
+		}
+		if err := expected.VerifyMAC(tag, data); err != nil {
+			t.Errorf("VerifyMAC failed: %s", err)
+		}
+	}
+}
+
+func TestGetPrimitiveSipHashWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.SipHashTypeURL)
+	if err != nil {
+		t.Fatalf("SipHash key manager not found: %s", err)
+	}
+	badVersionKey := newSipHashKey(8)
+	badVersionKey.Version++
+	shortKey := newSipHashKey(8)
+	shortKey.KeyValue = shortKey.KeyValue[:15]
+	noParamsKey := newSipHashKey(8)
+	noParamsKey.Params = nil
+	testKeys := []proto.Message{
+		badVersionKey,
+		shortKey,
+		noParamsKey,
+		newSipHashKey(10),
+		newSipHashKey(32),
+	}
+	for i, key := range testKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+}
+
+func TestNewKeyDataSipHash(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.SipHashTypeURL)
+	if err != nil {
+		t.Fatalf("SipHash key manager not found: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(&sippb.SipHashKeyFormat{Params: &sippb.SipHashParams{TagSize: 16}})
+	keyData, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyData failed: %s", err)
+	}
+	if keyData.TypeUrl != testutil.SipHashTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := new(sippb.SipHashKey)
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("invalid key value")
+	}
+	if len(key.KeyValue) != subtleMac.SipHashKeySize || key.Params.TagSize != 16 {
+		t.Errorf("key format and generated key do not match")
+	}
+
+	invalidFormats := []*sippb.SipHashKeyFormat{
+		{},
+		{Params: &sippb.SipHashParams{TagSize: 12}},
+	}
+	for i, format := range invalidFormats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+}
+
+func TestDoesSupportSipHash(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.SipHashTypeURL)
+	if err != nil {
+		t.Fatalf("SipHash key manager not found: %s", err)
+	}
+	if !km.DoesSupport(testutil.SipHashTypeURL) {
+		t.Errorf("SipHashKeyManager must support %s", testutil.SipHashTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("SipHashKeyManager must support only %s", testutil.SipHashTypeURL)
+	}
+	if km.TypeURL() != testutil.SipHashTypeURL {
+		t.Errorf("incorrect TypeURL()")
+	}
+}
+
+func TestSipHashKeysetRoundtrip(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{mac.SipHash24Tag64KeyTemplate(), mac.SipHash24Tag128KeyTemplate()} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		p, err := mac.New(kh)
+		if err != nil {
+			t.Fatalf("mac.New failed: %s", err)
+		}
+		data := []byte("short key")
+		tag, err := p.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("p.ComputeMAC failed: %s", err)
+		}
+		if err := p.VerifyMAC(tag, data); err != nil {
+			t.Errorf("p.VerifyMAC failed: %s", err)
+		}
+	}
+}
+
+func newSipHashKey(tagSize uint32) *sippb.SipHashKey {
+	return &sippb.SipHashKey{
+		Version:  testutil.SipHashKeyVersion,
+		Params:   &sippb.SipHashParams{TagSize: tagSize},
+		KeyValue: random.GetRandomBytes(subtleMac.SipHashKeySize),
+	}
+}
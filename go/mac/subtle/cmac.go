@@ -19,6 +19,7 @@ package subtle
 import (
 	"crypto/subtle"
 	"fmt"
+	"hash"
 
 	subtleprf "github.com/google/tink/go/prf/subtle"
 
@@ -77,6 +78,12 @@ func (a AESCMAC) VerifyMAC(mac, data []byte) error {
 	return nil
 }
 
+// NewHash returns a hash.Hash that computes the same MAC as ComputeMAC over the
+// data written to it. It can be used when the data is not available all at once.
+func (a AESCMAC) NewHash() hash.Hash {
+	return &truncatedHash{Hash: a.prf.NewHash(), size: int(a.tagLength)}
+}
+
 // ValidateCMACParams validates the parameters for an AES-CMAC against the recommended parameters.
 func ValidateCMACParams(keySize, tagSize uint32) error {
 	if keySize != recommendedCMACKeySizeInBytes {
@@ -263,3 +263,23 @@ func TestCMACSmallerTagSize(t *testing.T) {
 		}
 	}
 }
+
+func TestCMACNewHash(t *testing.T) {
+	a, err := subtle.NewAESCMAC(keyRFC4493, 12)
+	if err != nil {
+		t.Fatalf("Could not create subtle.CMAC object: %v", err)
+	}
+	for l, e := range expected {
+		h := a.NewHash()
+		for i := 0; i < l; i += 5 {
+			end := i + 5
+			if end > l {
+				end = l
+			}
+			h.Write(dataRFC4493[i:end])
+		}
+		if got := hex.EncodeToString(h.Sum(nil)); got != e[:24] {
+			t.Errorf("Streaming computation and test vector differ. Computation: %q, Test Vector %q", got, e[:24])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// GMACIVSize is the size of the random IV that prefixes every GMAC.
+	GMACIVSize = 12
+	// GMACTagSize is the size of the GMAC tag that follows the IV.
+	GMACTagSize = 16
+)
+
+var errGMACInvalidMAC = errors.New("GMAC: invalid MAC")
+
+// AESGMAC is an implementation of GMAC, i.e. AES-GCM with empty plaintext and
+// the data as additional authenticated data. It implements the interface
+// tink.MAC.
+//
+// Like AES-GCM, GMAC fails catastrophically if an IV is ever reused with the
+// same key. Every MAC therefore uses a fresh random 12-byte IV, which is
+// prepended to the tag; as a consequence, MACs are randomized. Because of the
+// birthday bound on random IVs, a key should not be used for more than 2^32
+// MACs.
+type AESGMAC struct {
+	gcm cipher.AEAD
+}
+
+// NewAESGMAC creates a new instance of AES-GMAC with the specified key.
+func NewAESGMAC(key []byte) (*AESGMAC, error) {
+	if err := ValidateAESGMACParams(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("aes_gmac: %s", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gmac: %s", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("aes_gmac: %s", err)
+	}
+	return &AESGMAC{gcm: gcm}, nil
+}
+
+// ValidateAESGMACParams validates parameters of AES-GMAC constructor.
+func ValidateAESGMACParams(keySize uint32) error {
+	if keySize != 16 && keySize != 32 {
+		return fmt.Errorf("invalid key size %d, want 16 or 32", keySize)
+	}
+	return nil
+}
+
+// ComputeMAC computes message authentication code (MAC) for the given data.
+func (g *AESGMAC) ComputeMAC(data []byte) ([]byte, error) {
+	iv := random.GetRandomBytes(GMACIVSize)
+	return g.gcm.Seal(iv, iv, nil, data), nil
+}
+
+// VerifyMAC verifies whether the given MAC is a correct message authentication
+// code (MAC) the given data.
+func (g *AESGMAC) VerifyMAC(mac []byte, data []byte) error {
+	if len(mac) != GMACIVSize+GMACTagSize {
+		return errGMACInvalidMAC
+	}
+	if _, err := g.gcm.Open(nil, mac[:GMACIVSize], mac[GMACIVSize:], data); err != nil {
+		return errGMACInvalidMAC
+	}
+	return nil
+}
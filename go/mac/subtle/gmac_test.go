@@ -0,0 +1,91 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestAESGMACTestVector(t *testing.T) {
+	// NIST CAVP gcmEncryptExtIV128.rsp, PTlen = 0, AADlen = 128, Count = 0.
+	key, _ := hex.DecodeString("77be63708971c4e240d1cb79e8d77feb")
+	iv, _ := hex.DecodeString("e0e00f19fed7ba0136a797f3")
+	aad, _ := hex.DecodeString("7a43ec1d9c0a5a78a0b16533a6213cab")
+	tag, _ := hex.DecodeString("209fcc8d3675ed938e9c7166709dd946")
+	g, err := subtle.NewAESGMAC(key)
+	if err != nil {
+		t.Fatalf("NewAESGMAC failed: %s", err)
+	}
+	if err := g.VerifyMAC(append(iv, tag...), aad); err != nil {
+		t.Errorf("VerifyMAC failed: %s", err)
+	}
+}
+
+func TestAESGMACBasic(t *testing.T) {
+	for _, keySize := range []uint32{16, 32} {
+		g, err := subtle.NewAESGMAC(random.GetRandomBytes(keySize))
+		if err != nil {
+			t.Fatalf("NewAESGMAC failed: %s", err)
+		}
+		data := random.GetRandomBytes(100)
+		mac1, err := g.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		mac2, err := g.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		if len(mac1) != subtle.GMACIVSize+subtle.GMACTagSize {
+			t.Errorf("MAC has size %d, want %d", len(mac1), subtle.GMACIVSize+subtle.GMACTagSize)
+		}
+		if bytes.Equal(mac1[:subtle.GMACIVSize], mac2[:subtle.GMACIVSize]) {
+			t.Errorf("two MACs use the same IV")
+		}
+		for _, mac := range [][]byte{mac1, mac2} {
+			if err := g.VerifyMAC(mac, data); err != nil {
+				t.Errorf("VerifyMAC failed: %s", err)
+			}
+		}
+		if err := g.VerifyMAC(mac1, data[1:]); err == nil {
+			t.Errorf("VerifyMAC succeeded with modified data")
+		}
+		for i := range mac1 {
+			modified := append([]byte(nil), mac1...)
+			modified[i] ^= 1
+			if err := g.VerifyMAC(modified, data); err == nil {
+				t.Errorf("VerifyMAC succeeded with MAC modified at byte %d", i)
+			}
+		}
+		if err := g.VerifyMAC(mac1[:len(mac1)-1], data); err == nil {
+			t.Errorf("VerifyMAC succeeded with truncated MAC")
+		}
+	}
+}
+
+func TestAESGMACWithInvalidKey(t *testing.T) {
+	for _, keySize := range []uint32{0, 15, 24, 33} {
+		if _, err := subtle.NewAESGMAC(random.GetRandomBytes(keySize)); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+}
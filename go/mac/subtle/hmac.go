@@ -19,6 +19,7 @@ package subtle
 
 import (
 	"crypto/hmac"
+	"encoding"
 	"errors"
 	"fmt"
 	"hash"
@@ -41,6 +42,14 @@ type HMAC struct {
 	HashFunc func() hash.Hash
 	Key      []byte
 	TagSize  uint32
+
+	// innerState and outerState are the marshaled states of HashFunc after
+	// absorbing the key XORed with ipad and opad respectively. Restoring them is
+	// cheaper than hashing the padded key for every MAC. They are nil if the
+	// hash function does not support marshaling its state, or if the HMAC was not
+	// created by NewHMAC.
+	innerState []byte
+	outerState []byte
 }
 
 // NewHMAC creates a new instance of HMAC with the specified key and tag size.
@@ -53,13 +62,60 @@ func NewHMAC(hashAlg string, key []byte, tagSize uint32) (*HMAC, error) {
 	if hashFunc == nil {
 		return nil, fmt.Errorf("hmac: invalid hash algorithm")
 	}
+	innerState, outerState := precomputeHMACStates(hashFunc, key)
 	return &HMAC{
-		HashFunc: hashFunc,
-		Key:      key,
-		TagSize:  tagSize,
+		HashFunc:   hashFunc,
+		Key:        key,
+		TagSize:    tagSize,
+		innerState: innerState,
+		outerState: outerState,
 	}, nil
 }
 
+// precomputeHMACStates returns the marshaled hash states after absorbing the
+// inner and outer padded key, as defined in RFC 2104. It returns nil states if
+// the hash function does not implement encoding.BinaryMarshaler.
+func precomputeHMACStates(hashFunc func() hash.Hash, key []byte) ([]byte, []byte) {
+	inner := hashFunc()
+	outer := hashFunc()
+	innerMarshaler, ok := inner.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, nil
+	}
+	outerMarshaler, ok := outer.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := inner.(encoding.BinaryUnmarshaler); !ok {
+		return nil, nil
+	}
+	blockSize := inner.BlockSize()
+	if len(key) > blockSize {
+		inner.Write(key)
+		key = inner.Sum(nil)
+		inner.Reset()
+	}
+	ipad := make([]byte, blockSize)
+	opad := make([]byte, blockSize)
+	copy(ipad, key)
+	copy(opad, key)
+	for i := range ipad {
+		ipad[i] ^= 0x36
+		opad[i] ^= 0x5c
+	}
+	inner.Write(ipad)
+	outer.Write(opad)
+	innerState, err := innerMarshaler.MarshalBinary()
+	if err != nil {
+		return nil, nil
+	}
+	outerState, err := outerMarshaler.MarshalBinary()
+	if err != nil {
+		return nil, nil
+	}
+	return innerState, outerState
+}
+
 // ValidateHMACParams validates parameters of HMAC constructor.
 func ValidateHMACParams(hash string, keySize uint32, tagSize uint32) error {
 	// validate tag size
@@ -82,7 +138,7 @@ func ValidateHMACParams(hash string, keySize uint32, tagSize uint32) error {
 
 // ComputeMAC computes message authentication code (MAC) for the given data.
 func (h *HMAC) ComputeMAC(data []byte) ([]byte, error) {
-	mac := hmac.New(h.HashFunc, h.Key)
+	mac := h.newHMAC()
 	if _, err := mac.Write(data); err != nil {
 		return nil, err
 	}
@@ -102,3 +158,65 @@ func (h *HMAC) VerifyMAC(mac []byte, data []byte) error {
 	}
 	return errors.New("HMAC: invalid MAC")
 }
+
+// NewHash returns a hash.Hash that computes the same MAC as ComputeMAC over the
+// data written to it. It can be used when the data is not available all at once.
+func (h *HMAC) NewHash() hash.Hash {
+	return &truncatedHash{Hash: h.newHMAC(), size: int(h.TagSize)}
+}
+
+// newHMAC returns an untruncated HMAC hash.Hash. It restores the precomputed
+// states if available, and falls back to crypto/hmac otherwise.
+func (h *HMAC) newHMAC() hash.Hash {
+	if h.innerState == nil {
+		return hmac.New(h.HashFunc, h.Key)
+	}
+	p := &precomputedHMAC{h: h, inner: h.HashFunc()}
+	p.Reset()
+	return p
+}
+
+// precomputedHMAC computes HMAC starting from the precomputed states of an HMAC.
+type precomputedHMAC struct {
+	h     *HMAC
+	inner hash.Hash
+}
+
+func (p *precomputedHMAC) Write(b []byte) (int, error) {
+	return p.inner.Write(b)
+}
+
+func (p *precomputedHMAC) Sum(b []byte) []byte {
+	outer := p.h.HashFunc()
+	// The states were produced by the same hash function, so restoring them
+	// cannot fail.
+	outer.(encoding.BinaryUnmarshaler).UnmarshalBinary(p.h.outerState)
+	outer.Write(p.inner.Sum(nil))
+	return outer.Sum(b)
+}
+
+func (p *precomputedHMAC) Reset() {
+	p.inner.(encoding.BinaryUnmarshaler).UnmarshalBinary(p.h.innerState)
+}
+
+func (p *precomputedHMAC) Size() int {
+	return p.inner.Size()
+}
+
+func (p *precomputedHMAC) BlockSize() int {
+	return p.inner.BlockSize()
+}
+
+// truncatedHash is a hash.Hash whose output is truncated to size bytes.
+type truncatedHash struct {
+	hash.Hash
+	size int
+}
+
+func (t *truncatedHash) Sum(b []byte) []byte {
+	return append(b, t.Hash.Sum(nil)[:t.size]...)
+}
+
+func (t *truncatedHash) Size() int {
+	return t.size
+}
@@ -17,10 +17,17 @@
 package subtle_test
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
+	"hash"
 	"strings"
 	"testing"
 
+	"golang.org/x/crypto/sha3"
 	"github.com/google/tink/go/mac/subtle"
 	"github.com/google/tink/go/subtle/random"
 )
@@ -49,6 +56,21 @@ var hmacTests = []struct {
 		expectedMac: "481e10d823ba64c15b94537a3de3f253c16642451ac45124dd4dde120bf1e5c15" +
 			"e55487d55ba72b43039f235226e7954cd5854b30abc4b5b53171a4177047c9b",
 	},
+	{
+		hashAlg:     "SHA3_256",
+		tagSize:     32,
+		data:        data,
+		key:         key,
+		expectedMac: "bd2261de0740466f4138a41beefe9004aa868e11aabba945ef98f64f70032840",
+	},
+	{
+		hashAlg: "SHA3_512",
+		tagSize: 64,
+		data:    data,
+		key:     key,
+		expectedMac: "faa49c7046556384d74f85e3454dd7355990218180747d1c5dab0e8f76dd8911" +
+			"dbaaa8106d10dc8a069369c1a55d59a498d5ff1757a87455b39a729b242a99fd",
+	},
 	// empty data
 	{
 		hashAlg:     "SHA256",
@@ -177,3 +199,82 @@ func TestHMACTruncation(t *testing.T) {
 		}
 	}
 }
+
+func TestHMACNewHash(t *testing.T) {
+	for i, test := range hmacTests {
+		for _, tagSize := range []uint32{16, test.tagSize} {
+			cipher, err := subtle.NewHMAC(test.hashAlg, test.key, tagSize)
+			if err != nil {
+				t.Fatalf("cannot create new mac in test case %d: %s", i, err)
+			}
+			expected, err := cipher.ComputeMAC(test.data)
+			if err != nil {
+				t.Fatalf("mac computation failed in test case %d: %s", i, err)
+			}
+			h := cipher.NewHash()
+			for j := range test.data {
+				h.Write(test.data[j : j+1])
+			}
+			if h.Size() != int(tagSize) {
+				t.Errorf("test case %d: Size() = %d, want %d", i, h.Size(), tagSize)
+			}
+			if mac := h.Sum(nil); hex.EncodeToString(mac) != hex.EncodeToString(expected) {
+				t.Errorf("incorrect mac in test case %d: expect %x, got %x", i, expected, mac)
+			}
+		}
+	}
+}
+
+func TestHMACMatchesCryptoHMAC(t *testing.T) {
+	hashFuncs := map[string]func() hash.Hash{
+		"SHA1":     sha1.New,
+		"SHA256":   sha256.New,
+		"SHA384":   sha512.New384,
+		"SHA512":   sha512.New,
+		"SHA3_256": sha3.New256,
+		"SHA3_512": sha3.New512,
+	}
+	for hashAlg, hashFunc := range hashFuncs {
+		// Include keys shorter than, equal to and longer than the block size.
+		for _, keySize := range []uint32{16, 64, 128, 200} {
+			key := random.GetRandomBytes(keySize)
+			data := random.GetRandomBytes(100)
+			cipher, err := subtle.NewHMAC(hashAlg, key, 16)
+			if err != nil {
+				t.Fatalf("NewHMAC(%s) failed: %s", hashAlg, err)
+			}
+			want := hmac.New(hashFunc, key)
+			want.Write(data)
+			expected := want.Sum(nil)[:16]
+			for i := 0; i < 2; i++ {
+				mac, err := cipher.ComputeMAC(data)
+				if err != nil {
+					t.Fatalf("ComputeMAC failed: %s", err)
+				}
+				if !bytes.Equal(mac, expected) {
+					t.Errorf("%s with key size %d: got %x, want %x", hashAlg, keySize, mac, expected)
+				}
+			}
+			h := cipher.NewHash()
+			h.Write([]byte("discarded"))
+			h.Reset()
+			h.Write(data)
+			if mac := h.Sum(nil); !bytes.Equal(mac, expected) {
+				t.Errorf("%s with key size %d after Reset: got %x, want %x", hashAlg, keySize, mac, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkHMACSHA256(b *testing.B) {
+	cipher, err := subtle.NewHMAC("SHA256", random.GetRandomBytes(32), 32)
+	if err != nil {
+		b.Fatalf("NewHMAC failed: %s", err)
+	}
+	data := random.GetRandomBytes(64)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cipher.ComputeMAC(data)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Maximum KMAC tag size in bytes.
+	maxKMACTagSizeInBytes = uint32(64)
+)
+
+// KMAC is an implementation of KMAC128 and KMAC256 as defined in NIST SP
+// 800-185. It implements the interface tink.MAC.
+type KMAC struct {
+	// keyed is the cSHAKE state after absorbing the padded key. It is cloned
+	// for every computation.
+	keyed   sha3.ShakeHash
+	rate    int
+	tagSize uint32
+}
+
+// NewKMAC creates a new instance of KMAC with the specified variant ("KMAC128"
+// or "KMAC256"), key, customization string and tag size.
+func NewKMAC(variant string, key, customization []byte, tagSize uint32) (*KMAC, error) {
+	if err := ValidateKMACParams(variant, uint32(len(key)), tagSize); err != nil {
+		return nil, fmt.Errorf("kmac: %s", err)
+	}
+	var h sha3.ShakeHash
+	var rate int
+	switch variant {
+	case "KMAC128":
+		h = sha3.NewCShake128([]byte("KMAC"), customization)
+		rate = 168
+	case "KMAC256":
+		h = sha3.NewCShake256([]byte("KMAC"), customization)
+		rate = 136
+	}
+	h.Write(bytepad(encodeString(key), rate))
+	return &KMAC{
+		keyed:   h,
+		rate:    rate,
+		tagSize: tagSize,
+	}, nil
+}
+
+// ValidateKMACParams validates parameters of KMAC constructor. The key must be
+// at least as long as the security strength of the variant.
+func ValidateKMACParams(variant string, keySize uint32, tagSize uint32) error {
+	var minKeySize uint32
+	switch variant {
+	case "KMAC128":
+		minKeySize = 16
+	case "KMAC256":
+		minKeySize = 32
+	default:
+		return fmt.Errorf("unsupported variant: %s", variant)
+	}
+	if keySize < minKeySize {
+		return fmt.Errorf("key too short")
+	}
+	if tagSize < minTagSizeInBytes {
+		return fmt.Errorf("tag size too small")
+	}
+	if tagSize > maxKMACTagSizeInBytes {
+		return fmt.Errorf("tag size too big")
+	}
+	return nil
+}
+
+// ComputeMAC computes message authentication code (MAC) for the given data.
+func (k *KMAC) ComputeMAC(data []byte) ([]byte, error) {
+	h := k.NewHash()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// VerifyMAC verifies whether the given MAC is a correct message authentication
+// code (MAC) the given data.
+func (k *KMAC) VerifyMAC(mac []byte, data []byte) error {
+	expectedMAC, err := k.ComputeMAC(data)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expectedMAC, mac) == 1 {
+		return nil
+	}
+	return errors.New("KMAC: invalid MAC")
+}
+
+// NewHash returns a hash.Hash that computes the same MAC as ComputeMAC over the
+// data written to it. It can be used when the data is not available all at once.
+func (k *KMAC) NewHash() hash.Hash {
+	return &kmacHash{k: k, h: k.keyed.Clone()}
+}
+
+// kmacHash computes KMAC incrementally.
+type kmacHash struct {
+	k *KMAC
+	h sha3.ShakeHash
+}
+
+func (d *kmacHash) Write(p []byte) (int, error) {
+	return d.h.Write(p)
+}
+
+func (d *kmacHash) Sum(b []byte) []byte {
+	h := d.h.Clone()
+	h.Write(rightEncode(uint64(d.k.tagSize) * 8))
+	tag := make([]byte, d.k.tagSize)
+	h.Read(tag)
+	return append(b, tag...)
+}
+
+func (d *kmacHash) Reset() {
+	d.h = d.k.keyed.Clone()
+}
+
+func (d *kmacHash) Size() int {
+	return int(d.k.tagSize)
+}
+
+func (d *kmacHash) BlockSize() int {
+	return d.k.rate
+}
+
+// leftEncode, rightEncode, encodeString and bytepad are the encoding functions
+// of NIST SP 800-185, section 2.3.
+func leftEncode(x uint64) []byte {
+	b := encodeUint(x)
+	return append([]byte{byte(len(b))}, b...)
+}
+
+func rightEncode(x uint64) []byte {
+	b := encodeUint(x)
+	return append(b, byte(len(b)))
+}
+
+// encodeUint returns the big-endian encoding of x with no leading zero bytes,
+// but at least one byte.
+func encodeUint(x uint64) []byte {
+	var b []byte
+	for x > 0 {
+		b = append([]byte{byte(x)}, b...)
+		x >>= 8
+	}
+	if len(b) == 0 {
+		b = []byte{0}
+	}
+	return b
+}
+
+func encodeString(s []byte) []byte {
+	return append(leftEncode(uint64(len(s))*8), s...)
+}
+
+func bytepad(x []byte, w int) []byte {
+	b := append(leftEncode(uint64(w)), x...)
+	if r := len(b) % w; r != 0 {
+		b = append(b, make([]byte, w-r)...)
+	}
+	return b
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+// Samples from NIST SP 800-185, KMAC_samples.pdf.
+var kmacTests = []struct {
+	variant       string
+	key           string
+	data          string
+	customization string
+	tag           string
+}{
+	{
+		variant: "KMAC128",
+		key:     "404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f",
+		data:    "00010203",
+		tag:     "e5780b0d3ea6f7d3a429c5706aa43a00fadbd7d49628839e3187243f456ee14e",
+	},
+	{
+		variant:       "KMAC128",
+		key:           "404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f",
+		data:          "00010203",
+		customization: "My Tagged Application",
+		tag:           "3b1fba963cd8b0b59e8c1a6d71888b7143651af8ba0a7070c0979e2811324aa5",
+	},
+	{
+		variant:       "KMAC256",
+		key:           "404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f",
+		data:          "00010203",
+		customization: "My Tagged Application",
+		tag:           "20c570c31346f703c9ac36c61c03cb64c3970d0cfc787e9b79599d273a68d2f7f69d4cc3de9d104a351689f27cf6f5951f0103f33f4f24871024d9c27773a8dd",
+	},
+}
+
+func TestKMACTestVectors(t *testing.T) {
+	for i, tc := range kmacTests {
+		key, _ := hex.DecodeString(tc.key)
+		data, _ := hex.DecodeString(tc.data)
+		k, err := subtle.NewKMAC(tc.variant, key, []byte(tc.customization), uint32(len(tc.tag)/2))
+		if err != nil {
+			t.Fatalf("test %d: NewKMAC failed: %s", i, err)
+		}
+		tag, err := k.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("test %d: ComputeMAC failed: %s", i, err)
+		}
+		if hex.EncodeToString(tag) != tc.tag {
+			t.Errorf("test %d: tag = %x, want %s", i, tag, tc.tag)
+		}
+		if err := k.VerifyMAC(tag, data); err != nil {
+			t.Errorf("test %d: VerifyMAC failed: %s", i, err)
+		}
+	}
+}
+
+func TestKMACNewHash(t *testing.T) {
+	for _, variant := range []string{"KMAC128", "KMAC256"} {
+		k, err := subtle.NewKMAC(variant, random.GetRandomBytes(32), []byte("app"), 32)
+		if err != nil {
+			t.Fatalf("NewKMAC failed: %s", err)
+		}
+		data := random.GetRandomBytes(300)
+		expected, err := k.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		h := k.NewHash()
+		h.Write(data[:100])
+		h.Write(data[100:])
+		if tag := h.Sum(nil); !bytes.Equal(tag, expected) {
+			t.Errorf("%s: NewHash tag = %x, want %x", variant, tag, expected)
+		}
+		h.Reset()
+		h.Write(data)
+		if tag := h.Sum(nil); !bytes.Equal(tag, expected) {
+			t.Errorf("%s: NewHash tag after Reset = %x, want %x", variant, tag, expected)
+		}
+	}
+}
+
+func TestKMACCustomizationSeparatesTags(t *testing.T) {
+	key := random.GetRandomBytes(32)
+	k1, err := subtle.NewKMAC("KMAC128", key, []byte("a"), 32)
+	if err != nil {
+		t.Fatalf("NewKMAC failed: %s", err)
+	}
+	k2, err := subtle.NewKMAC("KMAC128", key, []byte("b"), 32)
+	if err != nil {
+		t.Fatalf("NewKMAC failed: %s", err)
+	}
+	tag, _ := k1.ComputeMAC([]byte("data"))
+	if err := k2.VerifyMAC(tag, []byte("data")); err == nil {
+		t.Errorf("VerifyMAC with different customization succeeded unexpectedly")
+	}
+}
+
+func TestKMACWithInvalidInput(t *testing.T) {
+	var testCases = []struct {
+		variant string
+		keySize int
+		tagSize uint32
+	}{
+		{"KMAC128", 15, 32},
+		{"KMAC256", 31, 32},
+		{"KMAC128", 32, 9},
+		{"KMAC256", 64, 65},
+		{"KMAC512", 64, 32},
+		{"", 32, 32},
+	}
+	for i, tc := range testCases {
+		if _, err := subtle.NewKMAC(tc.variant, random.GetRandomBytes(uint32(tc.keySize)), nil, tc.tagSize); err == nil {
+			t.Errorf("test %d: expect an error", i)
+		}
+	}
+}
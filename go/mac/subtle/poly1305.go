@@ -0,0 +1,111 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/poly1305"
+	"github.com/google/tink/go/subtle/random"
+)
+
+const (
+	// Poly1305KeySize is the size of Poly1305 keys in bytes.
+	Poly1305KeySize = 32
+	// Poly1305NonceSize is the size of the nonce that prefixes every MAC.
+	Poly1305NonceSize = chacha20.NonceSizeX
+	// Poly1305TagSize is the size of the Poly1305 tag that follows the nonce.
+	Poly1305TagSize = poly1305.TagSize
+)
+
+var errPoly1305InvalidMAC = errors.New("Poly1305: invalid MAC")
+
+// Poly1305 is a MAC based on the Poly1305 one-time authenticator of RFC 8439.
+// It implements the interface tink.MAC.
+//
+// A Poly1305 key must never be used to authenticate more than one message:
+// two tags under the same key reveal enough to forge tags for any message.
+// Poly1305 therefore never uses its key directly. For every MAC it picks a
+// random 192-bit nonce and derives a fresh one-time key as the first 32 bytes
+// of the XChaCha20 keystream for that nonce, like the Poly1305 key generation
+// of RFC 8439, section 2.6. The MAC is the nonce followed by the RFC 8439
+// Poly1305 tag of the data under the one-time key.
+//
+// As a consequence, MACs are randomized: computing the MAC of the same data
+// twice gives different results, and both verify.
+type Poly1305 struct {
+	key []byte
+}
+
+// NewPoly1305 creates a new instance of Poly1305 with the specified key.
+func NewPoly1305(key []byte) (*Poly1305, error) {
+	if err := ValidatePoly1305Params(uint32(len(key))); err != nil {
+		return nil, fmt.Errorf("poly1305: %s", err)
+	}
+	return &Poly1305{key: key}, nil
+}
+
+// ValidatePoly1305Params validates parameters of Poly1305 constructor.
+func ValidatePoly1305Params(keySize uint32) error {
+	if keySize != Poly1305KeySize {
+		return fmt.Errorf("invalid key size %d, want %d", keySize, Poly1305KeySize)
+	}
+	return nil
+}
+
+// ComputeMAC computes message authentication code (MAC) for the given data.
+func (p *Poly1305) ComputeMAC(data []byte) ([]byte, error) {
+	nonce := random.GetRandomBytes(Poly1305NonceSize)
+	otk, err := p.oneTimeKey(nonce)
+	if err != nil {
+		return nil, err
+	}
+	var tag [Poly1305TagSize]byte
+	poly1305.Sum(&tag, data, otk)
+	return append(nonce, tag[:]...), nil
+}
+
+// VerifyMAC verifies whether the given MAC is a correct message authentication
+// code (MAC) the given data.
+func (p *Poly1305) VerifyMAC(mac []byte, data []byte) error {
+	if len(mac) != Poly1305NonceSize+Poly1305TagSize {
+		return errPoly1305InvalidMAC
+	}
+	otk, err := p.oneTimeKey(mac[:Poly1305NonceSize])
+	if err != nil {
+		return err
+	}
+	var tag [Poly1305TagSize]byte
+	copy(tag[:], mac[Poly1305NonceSize:])
+	if !poly1305.Verify(&tag, data, otk) {
+		return errPoly1305InvalidMAC
+	}
+	return nil
+}
+
+// oneTimeKey derives the Poly1305 key for the given nonce.
+func (p *Poly1305) oneTimeKey(nonce []byte) (*[32]byte, error) {
+	c, err := chacha20.NewUnauthenticatedCipher(p.key, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("poly1305: %s", err)
+	}
+	otk := new([32]byte)
+	c.XORKeyStream(otk[:], otk[:])
+	return otk, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/poly1305"
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestPoly1305Basic(t *testing.T) {
+	p, err := subtle.NewPoly1305(random.GetRandomBytes(subtle.Poly1305KeySize))
+	if err != nil {
+		t.Fatalf("NewPoly1305 failed: %s", err)
+	}
+	data := []byte("Hello")
+	mac1, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC failed: %s", err)
+	}
+	mac2, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC failed: %s", err)
+	}
+	if len(mac1) != subtle.Poly1305NonceSize+subtle.Poly1305TagSize {
+		t.Errorf("MAC has size %d, want %d", len(mac1), subtle.Poly1305NonceSize+subtle.Poly1305TagSize)
+	}
+	if bytes.Equal(mac1, mac2) {
+		t.Errorf("two MACs of the same data are equal, the one-time key was reused")
+	}
+	for _, mac := range [][]byte{mac1, mac2} {
+		if err := p.VerifyMAC(mac, data); err != nil {
+			t.Errorf("VerifyMAC failed: %s", err)
+		}
+	}
+	if err := p.VerifyMAC(mac1, []byte("hello")); err == nil {
+		t.Errorf("VerifyMAC succeeded with modified data")
+	}
+	for i := range mac1 {
+		modified := append([]byte(nil), mac1...)
+		modified[i] ^= 1
+		if err := p.VerifyMAC(modified, data); err == nil {
+			t.Errorf("VerifyMAC succeeded with MAC modified at byte %d", i)
+		}
+	}
+	if err := p.VerifyMAC(mac1[:len(mac1)-1], data); err == nil {
+		t.Errorf("VerifyMAC succeeded with truncated MAC")
+	}
+}
+
+func TestPoly1305OneTimeKeyDerivation(t *testing.T) {
+	key := random.GetRandomBytes(subtle.Poly1305KeySize)
+	p, err := subtle.NewPoly1305(key)
+	if err != nil {
+		t.Fatalf("NewPoly1305 failed: %s", err)
+	}
+	data := random.GetRandomBytes(100)
+	mac, err := p.ComputeMAC(data)
+	if err != nil {
+		t.Fatalf("ComputeMAC failed: %s", err)
+	}
+	// The one-time key is the first block of XChaCha20 keystream, which is
+	// ChaCha20 under the HChaCha20 subkey (RFC 8439, section 2.6).
+	nonce := mac[:subtle.Poly1305NonceSize]
+	subkey, err := chacha20.HChaCha20(key, nonce[:16])
+	if err != nil {
+		t.Fatalf("HChaCha20 failed: %s", err)
+	}
+	c, err := chacha20.NewUnauthenticatedCipher(subkey, append(make([]byte, 4), nonce[16:]...))
+	if err != nil {
+		t.Fatalf("NewUnauthenticatedCipher failed: %s", err)
+	}
+	var otk [32]byte
+	c.XORKeyStream(otk[:], otk[:])
+	var tag [16]byte
+	poly1305.Sum(&tag, data, &otk)
+	if !bytes.Equal(tag[:], mac[subtle.Poly1305NonceSize:]) {
+		t.Errorf("tag = %x, want %x", mac[subtle.Poly1305NonceSize:], tag)
+	}
+}
+
+func TestPoly1305WithInvalidKey(t *testing.T) {
+	for _, keySize := range []uint32{0, 16, 31, 33, 64} {
+		if _, err := subtle.NewPoly1305(random.GetRandomBytes(keySize)); err == nil {
+			t.Errorf("expect an error when key size is %d", keySize)
+		}
+	}
+}
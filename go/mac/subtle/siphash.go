@@ -0,0 +1,197 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// SipHashKeySize is the size of SipHash keys in bytes.
+	SipHashKeySize = 16
+
+	sipHashBlockSize = 8
+)
+
+// SipHash is an implementation of SipHash-2-4 with either a 64-bit or a
+// 128-bit tag. It implements the interface tink.MAC.
+//
+// SipHash is a fast MAC for short inputs. A 64-bit tag only provides 64-bit
+// security against forgeries, so it should only be used where this suffices,
+// e.g. for keying hash tables.
+type SipHash struct {
+	k0, k1  uint64
+	tagSize uint32
+}
+
+// NewSipHash creates a new instance of SipHash-2-4 with the specified key and
+// tag size (8 or 16 bytes).
+func NewSipHash(key []byte, tagSize uint32) (*SipHash, error) {
+	if err := ValidateSipHashParams(uint32(len(key)), tagSize); err != nil {
+		return nil, fmt.Errorf("siphash: %s", err)
+	}
+	return &SipHash{
+		k0:      binary.LittleEndian.Uint64(key[:8]),
+		k1:      binary.LittleEndian.Uint64(key[8:]),
+		tagSize: tagSize,
+	}, nil
+}
+
+// ValidateSipHashParams validates parameters of SipHash constructor.
+func ValidateSipHashParams(keySize uint32, tagSize uint32) error {
+	if keySize != SipHashKeySize {
+		return fmt.Errorf("invalid key size %d, want %d", keySize, SipHashKeySize)
+	}
+	if tagSize != 8 && tagSize != 16 {
+		return fmt.Errorf("invalid tag size %d, want 8 or 16", tagSize)
+	}
+	return nil
+}
+
+// ComputeMAC computes message authentication code (MAC) for the given data.
+func (s *SipHash) ComputeMAC(data []byte) ([]byte, error) {
+	h := s.NewHash()
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// VerifyMAC verifies whether the given MAC is a correct message authentication
+// code (MAC) the given data.
+func (s *SipHash) VerifyMAC(mac []byte, data []byte) error {
+	expectedMAC, err := s.ComputeMAC(data)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(expectedMAC, mac) == 1 {
+		return nil
+	}
+	return errors.New("SipHash: invalid MAC")
+}
+
+// NewHash returns a hash.Hash that computes the same MAC as ComputeMAC over the
+// data written to it. It can be used when the data is not available all at once.
+func (s *SipHash) NewHash() hash.Hash {
+	d := &sipHashDigest{s: s}
+	d.Reset()
+	return d
+}
+
+// sipHashDigest computes SipHash-2-4 incrementally.
+type sipHashDigest struct {
+	s              *SipHash
+	v0, v1, v2, v3 uint64
+	buf            [sipHashBlockSize]byte
+	nbuf           int
+	length         uint64
+}
+
+func (d *sipHashDigest) Reset() {
+	d.v0 = d.s.k0 ^ 0x736f6d6570736575
+	d.v1 = d.s.k1 ^ 0x646f72616e646f6d
+	d.v2 = d.s.k0 ^ 0x6c7967656e657261
+	d.v3 = d.s.k1 ^ 0x7465646279746573
+	if d.s.tagSize == 16 {
+		d.v1 ^= 0xee
+	}
+	d.nbuf = 0
+	d.length = 0
+}
+
+func (d *sipHashDigest) Size() int {
+	return int(d.s.tagSize)
+}
+
+func (d *sipHashDigest) BlockSize() int {
+	return sipHashBlockSize
+}
+
+func (d *sipHashDigest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.length += uint64(n)
+	if d.nbuf > 0 {
+		c := copy(d.buf[d.nbuf:], p)
+		d.nbuf += c
+		p = p[c:]
+		if d.nbuf < sipHashBlockSize {
+			return n, nil
+		}
+		d.compress(binary.LittleEndian.Uint64(d.buf[:]))
+		d.nbuf = 0
+	}
+	for len(p) >= sipHashBlockSize {
+		d.compress(binary.LittleEndian.Uint64(p))
+		p = p[sipHashBlockSize:]
+	}
+	d.nbuf = copy(d.buf[:], p)
+	return n, nil
+}
+
+func (d *sipHashDigest) Sum(b []byte) []byte {
+	// Work on a copy so that the caller can keep writing.
+	c := *d
+	var last [sipHashBlockSize]byte
+	copy(last[:], c.buf[:c.nbuf])
+	last[7] = byte(c.length)
+	c.compress(binary.LittleEndian.Uint64(last[:]))
+
+	if c.s.tagSize == 16 {
+		c.v2 ^= 0xee
+	} else {
+		c.v2 ^= 0xff
+	}
+	c.rounds(4)
+	var tag [16]byte
+	binary.LittleEndian.PutUint64(tag[:8], c.v0^c.v1^c.v2^c.v3)
+	if c.s.tagSize == 16 {
+		c.v1 ^= 0xdd
+		c.rounds(4)
+		binary.LittleEndian.PutUint64(tag[8:], c.v0^c.v1^c.v2^c.v3)
+	}
+	return append(b, tag[:c.s.tagSize]...)
+}
+
+func (d *sipHashDigest) compress(m uint64) {
+	d.v3 ^= m
+	d.rounds(2)
+	d.v0 ^= m
+}
+
+func (d *sipHashDigest) rounds(n int) {
+	v0, v1, v2, v3 := d.v0, d.v1, d.v2, d.v3
+	for i := 0; i < n; i++ {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+	d.v0, d.v1, d.v2, d.v3 = v0, v1, v2, v3
+}
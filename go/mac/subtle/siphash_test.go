@@ -0,0 +1,106 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/tink/go/mac/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+// Test vectors from the SipHash reference implementation, with key
+// 000102...0f and message 000102...(n-1).
+var sipHashTests = []struct {
+	tagSize uint32
+	dataLen int
+	tag     string
+}{
+	{8, 0, "310e0edd47db6f72"},
+	{8, 1, "fd67dc93c539f874"},
+	{8, 15, "e545be4961ca29a1"},
+	{16, 0, "a3817f04ba25a8e66df67214c7550293"},
+	{16, 1, "da87c1d86b99af44347659119b22fc45"},
+}
+
+func TestSipHashTestVectors(t *testing.T) {
+	key := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	for i, tc := range sipHashTests {
+		data := make([]byte, tc.dataLen)
+		for j := range data {
+			data[j] = byte(j)
+		}
+		s, err := subtle.NewSipHash(key, tc.tagSize)
+		if err != nil {
+			t.Fatalf("test %d: NewSipHash failed: %s", i, err)
+		}
+		tag, err := s.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("test %d: ComputeMAC failed: %s", i, err)
+		}
+		if hex.EncodeToString(tag) != tc.tag {
+			t.Errorf("test %d: tag = %x, want %s", i, tag, tc.tag)
+		}
+		if err := s.VerifyMAC(tag, data); err != nil {
+			t.Errorf("test %d: VerifyMAC failed: %s", i, err)
+		}
+	}
+}
+
+func TestSipHashNewHash(t *testing.T) {
+	for _, tagSize := range []uint32{8, 16} {
+		s, err := subtle.NewSipHash(random.GetRandomBytes(16), tagSize)
+		if err != nil {
+			t.Fatalf("NewSipHash failed: %s", err)
+		}
+		data := random.GetRandomBytes(100)
+		expected, err := s.ComputeMAC(data)
+		if err != nil {
+			t.Fatalf("ComputeMAC failed: %s", err)
+		}
+		h := s.NewHash()
+		for i := 0; i < len(data); i += 3 {
+			end := i + 3
+			if end > len(data) {
+				end = len(data)
+			}
+			h.Write(data[i:end])
+		}
+		if tag := h.Sum(nil); !bytes.Equal(tag, expected) {
+			t.Errorf("tag size %d: NewHash tag = %x, want %x", tagSize, tag, expected)
+		}
+	}
+}
+
+func TestSipHashWithInvalidInput(t *testing.T) {
+	if _, err := subtle.NewSipHash(random.GetRandomBytes(15), 8); err == nil {
+		t.Errorf("expect an error when key is too short")
+	}
+	if _, err := subtle.NewSipHash(random.GetRandomBytes(32), 8); err == nil {
+		t.Errorf("expect an error when key is too long")
+	}
+	for _, tagSize := range []uint32{0, 4, 10, 32} {
+		if _, err := subtle.NewSipHash(random.GetRandomBytes(16), tagSize); err == nil {
+			t.Errorf("expect an error when tag size is %d", tagSize)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package prf
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
@@ -85,6 +86,31 @@ func (km *hkdfprfKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message,
 	}, nil
 }
 
+// DeriveKey derives a new key according to specification in the given
+// serialized HkdfPrfKeyFormat, using the bytes read from pseudorandomness as key
+// material.
+func (km *hkdfprfKeyManager) DeriveKey(serializedKeyFormat []byte, pseudorandomness io.Reader) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidHKDFPRFKeyFormat
+	}
+	keyFormat := new(hkdfpb.HkdfPrfKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidHKDFPRFKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("hkdf_prf_key_manager: invalid key format: %s", err)
+	}
+	keyValue := make([]byte, keyFormat.KeySize)
+	if _, err := io.ReadFull(pseudorandomness, keyValue); err != nil {
+		return nil, fmt.Errorf("hkdf_prf_key_manager: not enough pseudorandomness: %s", err)
+	}
+	return &hkdfpb.HkdfPrfKey{
+		Version:  hkdfprfKeyVersion,
+		Params:   keyFormat.Params,
+		KeyValue: keyValue,
+	}, nil
+}
+
 // NewKeyData generates a new KeyData according to specification in the given
 // serialized HKDFPRFKeyFormat. This should be used solely by the key management API.
 func (km *hkdfprfKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
@@ -102,3 +102,13 @@ func createAESCMACPRFKeyTemplate(keySize uint32) *tinkpb.KeyTemplate {
 		Value:            serializedFormat,
 	}
 }
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"HMAC_SHA256_PRF": HMACSHA256PRFKeyTemplate,
+	"HMAC_SHA512_PRF": HMACSHA512PRFKeyTemplate,
+	"HKDF_SHA256":     HKDFSHA256PRFKeyTemplate,
+	"AES_CMAC_PRF":    AESCMACPRFKeyTemplate,
+}
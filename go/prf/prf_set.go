@@ -18,6 +18,7 @@
 package prf
 
 import (
+	"encoding/binary"
 	"fmt"
 
 	"github.com/google/tink/go/core/registry"
@@ -75,6 +76,32 @@ func (s Set) ComputePrimaryPRF(input []byte, outputLength uint32) ([]byte, error
 	return prf.ComputePRF(input, outputLength)
 }
 
+// ComputePRFWithLabel computes the primary PRF on input, domain separated by
+// label, and returns outputLength bytes. Outputs for different labels or
+// different output lengths are independent of each other, so unlike
+// ComputePrimaryPRF a shorter output is not a prefix of a longer one.
+//
+// The PRF is computed on
+//
+//	len(label) || label || outputLength || input
+//
+// where the lengths are encoded as 4 byte big endian integers.
+func (s Set) ComputePRFWithLabel(label string, input []byte, outputLength uint32) ([]byte, error) {
+	return s.ComputePrimaryPRF(labeledInput(label, input, outputLength), outputLength)
+}
+
+// labeledInput returns the input for ComputePRFWithLabel.
+func labeledInput(label string, input []byte, outputLength uint32) []byte {
+	b := make([]byte, 0, 8+len(label)+len(input))
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(label)))
+	b = append(b, buf[:]...)
+	b = append(b, label...)
+	binary.BigEndian.PutUint32(buf[:], outputLength)
+	b = append(b, buf[:]...)
+	return append(b, input...)
+}
+
 func init() {
 	if err := registry.RegisterKeyManager(newHMACPRFKeyManager()); err != nil {
 		panic(fmt.Sprintf("prf.init() failed: %v", err))
@@ -85,4 +112,9 @@ func init() {
 	if err := registry.RegisterKeyManager(newAESCMACPRFKeyManager()); err != nil {
 		panic(fmt.Sprintf("prf.init() failed: %v", err))
 	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("prf.init() failed: %v", err))
+		}
+	}
 }
@@ -40,6 +40,16 @@ func NewPRFSetWithKeyManager(h *keyset.Handle, km registry.KeyManager) (*Set, er
 	return wrapPRFset(ps)
 }
 
+// NewPRFSetWithRegistry creates a prf.Set primitive from the given keyset
+// handle, using the key managers of r instead of the global registry.
+func NewPRFSetWithRegistry(h *keyset.Handle, r *registry.Registry) (*Set, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("prf_set_factory: cannot obtain primitive set: %s", err)
+	}
+	return wrapPRFset(ps)
+}
+
 func wrapPRFset(ps *primitiveset.PrimitiveSet) (*Set, error) {
 	set := &Set{}
 	if _, ok := (ps.Primary.Primitive).(PRF); !ok {
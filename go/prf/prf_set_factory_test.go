@@ -17,6 +17,7 @@
 package prf_test
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"testing"
@@ -223,3 +224,57 @@ func runZTests(results [][]byte, t *testing.T) {
 		}
 	}
 }
+
+func TestComputePRFWithLabel(t *testing.T) {
+	h, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	ps, err := prf.NewPRFSet(h)
+	if err != nil {
+		t.Fatalf("prf.NewPRFSet() failed: %v", err)
+	}
+	input := []byte("input")
+	out, err := ps.ComputePRFWithLabel("label", input, 16)
+	if err != nil {
+		t.Fatalf("ps.ComputePRFWithLabel() failed: %v", err)
+	}
+	if len(out) != 16 {
+		t.Errorf("len(ps.ComputePRFWithLabel()) = %d, want 16", len(out))
+	}
+	again, err := ps.ComputePRFWithLabel("label", input, 16)
+	if err != nil {
+		t.Fatalf("ps.ComputePRFWithLabel() failed: %v", err)
+	}
+	if !bytes.Equal(out, again) {
+		t.Error("ps.ComputePRFWithLabel() is not deterministic")
+	}
+	want, err := ps.ComputePrimaryPRF([]byte("\x00\x00\x00\x05label\x00\x00\x00\x10input"), 16)
+	if err != nil {
+		t.Fatalf("ps.ComputePrimaryPRF() failed: %v", err)
+	}
+	if !bytes.Equal(out, want) {
+		t.Errorf("ps.ComputePRFWithLabel() = %x, want %x", out, want)
+	}
+	for _, tc := range []struct {
+		label        string
+		input        []byte
+		outputLength uint32
+	}{
+		{"other label", input, 16},
+		{"", input, 16},
+		{"labe", []byte("linput"), 16},
+		{"label", input, 32},
+	} {
+		other, err := ps.ComputePRFWithLabel(tc.label, tc.input, tc.outputLength)
+		if err != nil {
+			t.Fatalf("ps.ComputePRFWithLabel(%q, %q, %d) failed: %v", tc.label, tc.input, tc.outputLength, err)
+		}
+		if bytes.Equal(out, other[:16]) {
+			t.Errorf("ps.ComputePRFWithLabel(%q, %q, %d) is not independent of the output for label %q", tc.label, tc.input, tc.outputLength, "label")
+		}
+	}
+	if _, err := ps.ComputePRFWithLabel("label", input, 33); err == nil {
+		t.Error("ps.ComputePRFWithLabel() succeeded with output length 33 for HMAC-SHA256, want error")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package prf
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf/subtle"
+)
+
+// StreamingPRF is a PRF whose output is a stream rather than a fixed number of
+// bytes. It has the same properties as PRF, and is meant for uses such as key
+// hierarchies where the amount of output needed is not known up front.
+type StreamingPRF interface {
+	// ComputePRF returns a reader of the output of the PRF selected by the
+	// underlying key on input. The output only depends on the key and input,
+	// so the first n bytes read are always the same. The output may be
+	// limited, in which case the reader returns io.EOF once it is exhausted.
+	ComputePRF(input []byte) io.Reader
+}
+
+// NewStreamingPRF creates a StreamingPRF from the primary key of the given
+// keyset handle. Only HKDF PRF keys are supported.
+func NewStreamingPRF(h *keyset.Handle) (StreamingPRF, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("streaming_prf: cannot obtain primitive set: %s", err)
+	}
+	p, ok := (ps.Primary.Primitive).(*subtle.HKDFPRF)
+	if !ok {
+		return nil, fmt.Errorf("streaming_prf: primary key is not an HKDF PRF key")
+	}
+	return p.StreamingPRF(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package prf_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/prf"
+)
+
+func TestStreamingPRF(t *testing.T) {
+	h, err := keyset.NewHandle(prf.HKDFSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	s, err := prf.NewStreamingPRF(h)
+	if err != nil {
+		t.Fatalf("prf.NewStreamingPRF() failed: %v", err)
+	}
+	ps, err := prf.NewPRFSet(h)
+	if err != nil {
+		t.Fatalf("prf.NewPRFSet() failed: %v", err)
+	}
+	input := []byte("input")
+	want, err := ps.ComputePrimaryPRF(input, 100)
+	if err != nil {
+		t.Fatalf("ps.ComputePrimaryPRF() failed: %v", err)
+	}
+	got := make([]byte, 100)
+	if _, err := io.ReadFull(s.ComputePRF(input), got); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("streaming PRF output = %x, want %x", got, want)
+	}
+}
+
+func TestStreamingPRFUnsupportedKey(t *testing.T) {
+	h, err := keyset.NewHandle(prf.HMACSHA256PRFKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	if _, err := prf.NewStreamingPRF(h); err == nil {
+		t.Error("prf.NewStreamingPRF() succeeded with an HMAC PRF key, want error")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"crypto/cipher"
 	"crypto/subtle"
 	"fmt"
+	"hash"
 
 	// Placeholder for internal crypto/subtle allowlist, please ignore.
 )
@@ -116,6 +117,78 @@ func (a AESCMACPRF) ComputePRF(data []byte, outputLength uint32) ([]byte, error)
 	return output[:outputLength], nil
 }
 
+// NewHash returns a hash.Hash that computes the full-length AES-CMAC of the
+// data written to it. It can be used when the data is not available all at
+// once.
+func (a AESCMACPRF) NewHash() hash.Hash {
+	bs := a.bc.BlockSize()
+	return &aesCMACHash{
+		bc:      a.bc,
+		subkey1: a.subkey1,
+		subkey2: a.subkey2,
+		x:       make([]byte, bs),
+		buf:     make([]byte, 0, bs),
+	}
+}
+
+// aesCMACHash computes AES-CMAC incrementally. The last block of input is kept
+// in buf until more data arrives, since it needs to be masked with a subkey
+// once it is known to be the final block.
+type aesCMACHash struct {
+	bc               cipher.Block
+	subkey1, subkey2 []byte
+	x                []byte
+	buf              []byte
+}
+
+func (h *aesCMACHash) Write(p []byte) (int, error) {
+	n := len(p)
+	bs := h.bc.BlockSize()
+	for len(p) > 0 {
+		if len(h.buf) == bs {
+			for i := 0; i < bs; i++ {
+				h.x[i] ^= h.buf[i]
+			}
+			h.bc.Encrypt(h.x, h.x)
+			h.buf = h.buf[:0]
+		}
+		m := bs - len(h.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		h.buf = append(h.buf, p[:m]...)
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (h *aesCMACHash) Sum(b []byte) []byte {
+	bs := h.bc.BlockSize()
+	last := make([]byte, bs)
+	copy(last, h.buf)
+	subkey := h.subkey1
+	if len(h.buf) < bs {
+		last[len(h.buf)] = pad
+		subkey = h.subkey2
+	}
+	for i := 0; i < bs; i++ {
+		last[i] ^= subkey[i] ^ h.x[i]
+	}
+	h.bc.Encrypt(last, last)
+	return append(b, last...)
+}
+
+func (h *aesCMACHash) Reset() {
+	for i := range h.x {
+		h.x[i] = 0
+	}
+	h.buf = h.buf[:0]
+}
+
+func (h *aesCMACHash) Size() int { return h.bc.BlockSize() }
+
+func (h *aesCMACHash) BlockSize() int { return h.bc.BlockSize() }
+
 func mulByX(block []byte) {
 	bs := len(block)
 	v := int(block[0] >> 7)
@@ -163,3 +163,37 @@ func TestAESCMACPRFOutputLength(t *testing.T) {
 		}
 	}
 }
+
+func TestAESCMACPRFNewHashMatchesComputePRF(t *testing.T) {
+	key, err := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	if err != nil {
+		t.Fatalf("Could not decode key: %v", err)
+	}
+	a, err := subtle.NewAESCMACPRF(key)
+	if err != nil {
+		t.Fatalf("Could not create cmac.AES object: %v", err)
+	}
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for l := 0; l <= len(data); l++ {
+		want, err := a.ComputePRF(data[:l], 16)
+		if err != nil {
+			t.Fatalf("Error computing AES-CMAC: %v", err)
+		}
+		for _, chunkSize := range []int{1, 7, 16, 33} {
+			h := a.NewHash()
+			for i := 0; i < l; i += chunkSize {
+				end := i + chunkSize
+				if end > l {
+					end = l
+				}
+				h.Write(data[i:end])
+			}
+			if got := h.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("NewHash() with length %d and chunk size %d = %x, want %x", l, chunkSize, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"github.com/google/tink/go/subtle"
+)
+
+// HKDFStreamingPRF is a PRF based on HKDF whose output is a stream, so that
+// callers can read as many bytes as they need. HKDF limits the output to 255
+// times the size of the hash, after which reading returns io.EOF.
+type HKDFStreamingPRF struct {
+	h    func() hash.Hash
+	key  []byte
+	salt []byte
+}
+
+// NewHKDFStreamingPRF creates a new HKDFStreamingPRF object and initializes it
+// with the correct key material.
+func NewHKDFStreamingPRF(hashAlg string, key []byte, salt []byte) (*HKDFStreamingPRF, error) {
+	if err := ValidateHKDFPRFParams(hashAlg, uint32(len(key)), salt); err != nil {
+		return nil, err
+	}
+	return &HKDFStreamingPRF{
+		h:    subtle.GetHashFunc(hashAlg),
+		key:  key,
+		salt: salt,
+	}, nil
+}
+
+// ComputePRF returns a reader of the HKDF output for the given data. The
+// first n bytes read are the same as HKDFPRF.ComputePRF(data, n) returns for
+// the same key material.
+func (h *HKDFStreamingPRF) ComputePRF(data []byte) io.Reader {
+	return &hkdfReader{
+		r:         hkdf.New(h.h, h.key, h.salt, data),
+		remaining: 255 * h.h().Size(),
+	}
+}
+
+// hkdfReader returns the output of HKDF up to its limit. The HKDF reader
+// itself fails reads which would go past the limit without returning the
+// remaining bytes.
+type hkdfReader struct {
+	r         io.Reader
+	remaining int
+}
+
+func (r *hkdfReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if len(p) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= n
+	return n, err
+}
+
+// StreamingPRF returns an HKDFStreamingPRF with the key material of h.
+func (h HKDFPRF) StreamingPRF() *HKDFStreamingPRF {
+	return &HKDFStreamingPRF{
+		h:    h.h,
+		key:  h.key,
+		salt: h.salt,
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/prf/subtle"
+)
+
+func TestHKDFStreamingPRFMatchesHKDFPRF(t *testing.T) {
+	key := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	salt := []byte{0xaf, 0xfe, 0xc0, 0xff, 0xee}
+	for hash, length := range map[string]int{"SHA256": 32, "SHA512": 64} {
+		prf, err := subtle.NewHKDFPRF(hash, key, salt)
+		if err != nil {
+			t.Fatalf("subtle.NewHKDFPRF(%s) failed: %v", hash, err)
+		}
+		streamingPRF, err := subtle.NewHKDFStreamingPRF(hash, key, salt)
+		if err != nil {
+			t.Fatalf("subtle.NewHKDFStreamingPRF(%s) failed: %v", hash, err)
+		}
+		want, err := prf.ComputePRF([]byte{0x01, 0x02}, uint32(length*255))
+		if err != nil {
+			t.Fatalf("prf.ComputePRF() failed: %v", err)
+		}
+		for _, p := range []*subtle.HKDFStreamingPRF{streamingPRF, prf.StreamingPRF()} {
+			got, err := ioutil.ReadAll(p.ComputePRF([]byte{0x01, 0x02}))
+			if err != nil {
+				t.Errorf("Expected HKDF %s streaming PRF to return %d bytes: %v", hash, length*255, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("HKDF %s streaming PRF output doesn't match HKDF PRF output", hash)
+			}
+		}
+	}
+}
+
+func TestHKDFStreamingPRFDeterministic(t *testing.T) {
+	key := []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10}
+	prf, err := subtle.NewHKDFStreamingPRF("SHA256", key, nil)
+	if err != nil {
+		t.Fatalf("subtle.NewHKDFStreamingPRF() failed: %v", err)
+	}
+	r := prf.ComputePRF([]byte("input"))
+	first := make([]byte, 10)
+	second := make([]byte, 90)
+	if _, err := io.ReadFull(r, first); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if _, err := io.ReadFull(r, second); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	all := make([]byte, 100)
+	if _, err := io.ReadFull(prf.ComputePRF([]byte("input")), all); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if !bytes.Equal(all, append(first, second...)) {
+		t.Errorf("Expected the same output regardless of read sizes")
+	}
+	other := make([]byte, 100)
+	if _, err := io.ReadFull(prf.ComputePRF([]byte("other input")), other); err != nil {
+		t.Fatalf("io.ReadFull() failed: %v", err)
+	}
+	if bytes.Equal(all, other) {
+		t.Errorf("Expected different output for different inputs")
+	}
+}
+
+func TestHKDFStreamingPRFInvalidParams(t *testing.T) {
+	if _, err := subtle.NewHKDFStreamingPRF("SHA256", make([]byte, 4), nil); err == nil {
+		t.Errorf("Expected NewHKDFStreamingPRF to fail on short key")
+	}
+	if _, err := subtle.NewHKDFStreamingPRF("md5", make([]byte, 32), nil); err == nil {
+		t.Errorf("Expected NewHKDFStreamingPRF to fail on weak hash")
+	}
+}
@@ -43,9 +43,15 @@ type AesCtrHmacStreamingParams struct {
 	DerivedKeySize        uint32                    `protobuf:"varint,2,opt,name=derived_key_size,json=derivedKeySize,proto3" json:"derived_key_size,omitempty"`
 	HkdfHashType          common_go_proto.HashType  `protobuf:"varint,3,opt,name=hkdf_hash_type,json=hkdfHashType,proto3,enum=google.crypto.tink.HashType" json:"hkdf_hash_type,omitempty"`
 	HmacParams            *hmac_go_proto.HmacParams `protobuf:"bytes,4,opt,name=hmac_params,json=hmacParams,proto3" json:"hmac_params,omitempty"`
-	XXX_NoUnkeyedLiteral  struct{}                  `json:"-"`
-	XXX_unrecognized      []byte                    `json:"-"`
-	XXX_sizecache         int32                     `json:"-"`
+	// Number of bytes by which the first ciphertext segment is shortened, to
+	// leave room for data written before the ciphertext. Only supported by Go.
+	FirstSegmentOffset uint32 `protobuf:"varint,5,opt,name=first_segment_offset,json=firstSegmentOffset,proto3" json:"first_segment_offset,omitempty"`
+	// If non-zero, fresh AES-CTR and HMAC keys are derived for every
+	// segments_per_key segments of a ciphertext. Only supported by Go.
+	SegmentsPerKey       uint32   `protobuf:"varint,6,opt,name=segments_per_key,json=segmentsPerKey,proto3" json:"segments_per_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *AesCtrHmacStreamingParams) Reset()         { *m = AesCtrHmacStreamingParams{} }
@@ -101,7 +107,22 @@ func (m *AesCtrHmacStreamingParams) GetHmacParams() *hmac_go_proto.HmacParams {
 	return nil
 }
 
+func (m *AesCtrHmacStreamingParams) GetFirstSegmentOffset() uint32 {
+	if m != nil {
+		return m.FirstSegmentOffset
+	}
+	return 0
+}
+
+func (m *AesCtrHmacStreamingParams) GetSegmentsPerKey() uint32 {
+	if m != nil {
+		return m.SegmentsPerKey
+	}
+	return 0
+}
+
 type AesCtrHmacStreamingKeyFormat struct {
+	Version              uint32                     `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 	Params               *AesCtrHmacStreamingParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
 	KeySize              uint32                     `protobuf:"varint,2,opt,name=key_size,json=keySize,proto3" json:"key_size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
@@ -134,6 +155,13 @@ func (m *AesCtrHmacStreamingKeyFormat) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AesCtrHmacStreamingKeyFormat proto.InternalMessageInfo
 
+func (m *AesCtrHmacStreamingKeyFormat) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 func (m *AesCtrHmacStreamingKeyFormat) GetParams() *AesCtrHmacStreamingParams {
 	if m != nil {
 		return m.Params
@@ -215,30 +243,34 @@ func init() {
 }
 
 var fileDescriptor_eaa520d3eb9ab35e = []byte{
-	// 393 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0xcf, 0xcb, 0xd3, 0x30,
-	0x18, 0x26, 0x53, 0xbe, 0x4f, 0xf3, 0x7d, 0x0e, 0x09, 0xa8, 0xfd, 0xe6, 0x90, 0x39, 0x2f, 0xbd,
-	0xd8, 0xc2, 0x06, 0x9e, 0x04, 0x71, 0xa2, 0x0c, 0x76, 0x19, 0x9d, 0x78, 0xf0, 0x60, 0xc8, 0xd2,
-	0xd7, 0x26, 0x74, 0x69, 0x4a, 0x92, 0x0d, 0xbb, 0x93, 0xf8, 0x47, 0xf8, 0xf7, 0x4a, 0x93, 0x4e,
-	0x45, 0x3b, 0x3c, 0x78, 0x6a, 0xdf, 0x3c, 0x3f, 0xf2, 0xe4, 0xe1, 0xc5, 0x73, 0x27, 0xa4, 0xc9,
-	0x69, 0xcd, 0x8c, 0x6b, 0x52, 0x27, 0xab, 0x32, 0xad, 0x8d, 0x76, 0x3a, 0x65, 0x60, 0x29, 0x77,
-	0x86, 0x0a, 0xc5, 0x38, 0xb5, 0xce, 0x00, 0x53, 0xb2, 0x2a, 0x12, 0x0f, 0x12, 0x52, 0x68, 0x5d,
-	0xec, 0x20, 0xe1, 0xa6, 0xa9, 0x9d, 0x4e, 0x5a, 0xd9, 0xe8, 0xd9, 0x19, 0x23, 0xae, 0x95, 0xd2,
-	0x55, 0x10, 0x8e, 0x9e, 0x9e, 0x21, 0xb5, 0xb7, 0x04, 0xca, 0xf4, 0xdb, 0x00, 0xdf, 0xbc, 0x06,
-	0xfb, 0xc6, 0x99, 0xa5, 0x62, 0x7c, 0x73, 0xba, 0x79, 0xcd, 0x0c, 0x53, 0x96, 0xbc, 0xc0, 0x8f,
-	0xb8, 0xac, 0x05, 0x18, 0x07, 0x5f, 0x1c, 0xb5, 0x50, 0x28, 0xa8, 0x1c, 0xb5, 0xf2, 0x08, 0x11,
-	0x9a, 0xa0, 0xf8, 0x5e, 0xf6, 0xe0, 0x17, 0xbc, 0x09, 0xe8, 0x46, 0x1e, 0x81, 0xc4, 0xf8, 0x7e,
-	0x0e, 0x46, 0x1e, 0x20, 0xa7, 0x25, 0x34, 0x41, 0x30, 0xf0, 0x82, 0x61, 0x77, 0xbe, 0x82, 0xc6,
-	0x33, 0x17, 0x78, 0x28, 0xca, 0xfc, 0x33, 0x15, 0xcc, 0x0a, 0xea, 0x9a, 0x1a, 0xa2, 0x5b, 0x13,
-	0x14, 0x0f, 0x67, 0xe3, 0xe4, 0xef, 0x47, 0x27, 0x4b, 0x66, 0xc5, 0xfb, 0xa6, 0x86, 0xec, 0xba,
-	0xd5, 0x9c, 0x26, 0xf2, 0x0a, 0x5f, 0xf9, 0xde, 0x6a, 0x1f, 0x3a, 0xba, 0x3d, 0x41, 0xf1, 0xd5,
-	0xec, 0x49, 0xaf, 0x81, 0x62, 0x3c, 0x3c, 0x2d, 0xc3, 0xe2, 0xe7, 0xff, 0xf4, 0x2b, 0xc2, 0xe3,
-	0x9e, 0x12, 0x56, 0xd0, 0xbc, 0xd3, 0x46, 0x31, 0x47, 0xde, 0xe2, 0x8b, 0xce, 0x1c, 0x79, 0xf3,
-	0xe7, 0x7d, 0xe6, 0x67, 0x6b, 0xcc, 0x3a, 0x31, 0xb9, 0xc1, 0x77, 0xfe, 0xa8, 0xe3, 0xb2, 0x0c,
-	0x3d, 0x4c, 0xbf, 0x23, 0xfc, 0xb0, 0x3f, 0x02, 0x89, 0xf0, 0xe5, 0x01, 0x8c, 0x95, 0xba, 0xea,
-	0x4a, 0x3f, 0x8d, 0xbf, 0xc5, 0x1a, 0xfc, 0x4f, 0xac, 0xc7, 0xf8, 0x6e, 0x1b, 0xeb, 0xc0, 0x76,
-	0xfb, 0x50, 0xff, 0x75, 0xd6, 0xe6, 0xfc, 0xd0, 0xce, 0x8b, 0x4f, 0x78, 0xcc, 0xb5, 0xea, 0x33,
-	0xf6, 0x0b, 0xb4, 0x46, 0x1f, 0x5f, 0x16, 0xd2, 0x89, 0xfd, 0x36, 0xe1, 0x5a, 0xa5, 0x81, 0xf6,
-	0xef, 0xcd, 0xa6, 0x85, 0xa6, 0x1e, 0xdf, 0x5e, 0xf8, 0xcf, 0xfc, 0x47, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x9b, 0x67, 0x84, 0x6f, 0x1a, 0x03, 0x00, 0x00,
+	// 451 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x93, 0xcf, 0x6e, 0x13, 0x31,
+	0x10, 0xc6, 0xe5, 0x00, 0x29, 0xb8, 0x6d, 0x84, 0x2c, 0xfe, 0x6c, 0x4b, 0x84, 0x42, 0xb8, 0xe4,
+	0xc2, 0x06, 0xb5, 0x12, 0x27, 0x24, 0x44, 0x10, 0xa8, 0x28, 0x12, 0x44, 0x9b, 0x8a, 0x03, 0x17,
+	0xcb, 0x75, 0x26, 0x6b, 0x2b, 0xf5, 0x7a, 0x65, 0xbb, 0x11, 0xee, 0x83, 0x70, 0xe0, 0xc8, 0x83,
+	0xf1, 0x2c, 0xc8, 0xf6, 0x86, 0x20, 0xd8, 0x88, 0x03, 0xb7, 0x8c, 0xbf, 0xf9, 0xec, 0xef, 0x37,
+	0x93, 0xc5, 0xa7, 0x4e, 0x48, 0xb3, 0xa0, 0x35, 0x33, 0xce, 0x8f, 0x9d, 0xac, 0x56, 0xe3, 0xda,
+	0x68, 0xa7, 0xc7, 0x0c, 0x2c, 0xe5, 0xce, 0x50, 0xa1, 0x18, 0xa7, 0xd6, 0x19, 0x60, 0x4a, 0x56,
+	0x65, 0x1e, 0x45, 0x42, 0x4a, 0xad, 0xcb, 0x4b, 0xc8, 0xb9, 0xf1, 0xb5, 0xd3, 0x79, 0xb0, 0x1d,
+	0x3f, 0xdd, 0x71, 0x11, 0xd7, 0x4a, 0xe9, 0x2a, 0x19, 0x8f, 0x9f, 0xec, 0x68, 0x0a, 0xaf, 0xa4,
+	0x96, 0xe1, 0x8f, 0x0e, 0x3e, 0x7a, 0x0d, 0xf6, 0x8d, 0x33, 0x67, 0x8a, 0xf1, 0xf9, 0xe6, 0xe5,
+	0x19, 0x33, 0x4c, 0x59, 0xf2, 0x02, 0x3f, 0xe4, 0xb2, 0x16, 0x60, 0x1c, 0x7c, 0x71, 0xd4, 0x42,
+	0xa9, 0xa0, 0x72, 0xd4, 0xca, 0x6b, 0xc8, 0xd0, 0x00, 0x8d, 0x0e, 0x8b, 0xfb, 0x5b, 0x79, 0x9e,
+	0xd4, 0xb9, 0xbc, 0x06, 0x32, 0xc2, 0x77, 0x17, 0x60, 0xe4, 0x1a, 0x16, 0x74, 0x05, 0x3e, 0x19,
+	0x3a, 0xd1, 0xd0, 0x6b, 0xce, 0xa7, 0xe0, 0x63, 0xe7, 0x04, 0xf7, 0xc4, 0x6a, 0xb1, 0xa4, 0x82,
+	0x59, 0x41, 0x9d, 0xaf, 0x21, 0xbb, 0x31, 0x40, 0xa3, 0xde, 0x49, 0x3f, 0xff, 0x1b, 0x3a, 0x3f,
+	0x63, 0x56, 0x9c, 0xfb, 0x1a, 0x8a, 0x83, 0xe0, 0xd9, 0x54, 0xe4, 0x15, 0xde, 0x8f, 0x73, 0xab,
+	0x63, 0xe8, 0xec, 0xe6, 0x00, 0x8d, 0xf6, 0x4f, 0x1e, 0xb7, 0x5e, 0xa0, 0x18, 0x4f, 0x68, 0x05,
+	0x16, 0xbf, 0x7e, 0x93, 0xe7, 0xf8, 0xde, 0x52, 0x1a, 0xbb, 0x25, 0xd4, 0xcb, 0xa5, 0x05, 0x97,
+	0xdd, 0x8a, 0x91, 0x49, 0xd4, 0x1a, 0xbc, 0x8f, 0x51, 0x09, 0x80, 0x4d, 0xaf, 0xa5, 0x35, 0x98,
+	0x40, 0x99, 0x75, 0x13, 0xe0, 0xe6, 0x7c, 0x06, 0x66, 0x0a, 0x7e, 0xf8, 0x0d, 0xe1, 0x7e, 0xcb,
+	0x80, 0xa7, 0xe0, 0xdf, 0x69, 0xa3, 0x98, 0x23, 0x19, 0xde, 0x5b, 0x83, 0xb1, 0x52, 0x57, 0x11,
+	0xfd, 0xb0, 0xd8, 0x94, 0xe4, 0x2d, 0xee, 0x36, 0x48, 0x28, 0x22, 0x3d, 0x6b, 0x43, 0xda, 0xb9,
+	0xbc, 0xa2, 0x31, 0x93, 0x23, 0x7c, 0xfb, 0x8f, 0x25, 0xec, 0xad, 0xd2, 0xf4, 0x87, 0x5f, 0x11,
+	0x7e, 0xd0, 0x1e, 0xee, 0xf7, 0x58, 0x68, 0x57, 0xac, 0xce, 0xff, 0xc4, 0x7a, 0x84, 0xef, 0x84,
+	0x58, 0x6b, 0x76, 0x79, 0x95, 0x96, 0x7e, 0x50, 0x84, 0x9c, 0x9f, 0x42, 0x3d, 0x29, 0x71, 0x9f,
+	0x6b, 0xd5, 0x76, 0x71, 0xfc, 0xdb, 0xce, 0xd0, 0xe7, 0x97, 0xa5, 0x74, 0xe2, 0xea, 0x22, 0xe7,
+	0x5a, 0x8d, 0x53, 0xdb, 0xbf, 0xbf, 0x27, 0x5a, 0x6a, 0x1a, 0xf5, 0xef, 0x9d, 0xee, 0xf9, 0xfb,
+	0x0f, 0xd3, 0xd9, 0xe4, 0xa2, 0x1b, 0xeb, 0xd3, 0x9f, 0x03, 0x00, 0x90, 0xb1, 0x62, 0x63, 0x99,
+	0x03, 0x00, 0x00,
 }
@@ -41,9 +41,15 @@ type AesGcmHkdfStreamingParams struct {
 	CiphertextSegmentSize uint32                   `protobuf:"varint,1,opt,name=ciphertext_segment_size,json=ciphertextSegmentSize,proto3" json:"ciphertext_segment_size,omitempty"`
 	DerivedKeySize        uint32                   `protobuf:"varint,2,opt,name=derived_key_size,json=derivedKeySize,proto3" json:"derived_key_size,omitempty"`
 	HkdfHashType          common_go_proto.HashType `protobuf:"varint,3,opt,name=hkdf_hash_type,json=hkdfHashType,proto3,enum=google.crypto.tink.HashType" json:"hkdf_hash_type,omitempty"`
-	XXX_NoUnkeyedLiteral  struct{}                 `json:"-"`
-	XXX_unrecognized      []byte                   `json:"-"`
-	XXX_sizecache         int32                    `json:"-"`
+	// Number of bytes by which the first ciphertext segment is shortened, to
+	// leave room for data written before the ciphertext. Only supported by Go.
+	FirstSegmentOffset uint32 `protobuf:"varint,4,opt,name=first_segment_offset,json=firstSegmentOffset,proto3" json:"first_segment_offset,omitempty"`
+	// If non-zero, a fresh AES-GCM key is derived for every segments_per_key
+	// segments of a ciphertext. Only supported by Go.
+	SegmentsPerKey       uint32   `protobuf:"varint,5,opt,name=segments_per_key,json=segmentsPerKey,proto3" json:"segments_per_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *AesGcmHkdfStreamingParams) Reset()         { *m = AesGcmHkdfStreamingParams{} }
@@ -92,6 +98,20 @@ func (m *AesGcmHkdfStreamingParams) GetHkdfHashType() common_go_proto.HashType {
 	return common_go_proto.HashType_UNKNOWN_HASH
 }
 
+func (m *AesGcmHkdfStreamingParams) GetFirstSegmentOffset() uint32 {
+	if m != nil {
+		return m.FirstSegmentOffset
+	}
+	return 0
+}
+
+func (m *AesGcmHkdfStreamingParams) GetSegmentsPerKey() uint32 {
+	if m != nil {
+		return m.SegmentsPerKey
+	}
+	return 0
+}
+
 type AesGcmHkdfStreamingKeyFormat struct {
 	Version              uint32                     `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
 	Params               *AesGcmHkdfStreamingParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
@@ -214,29 +234,31 @@ func init() {
 }
 
 var fileDescriptor_1dba2d882aaf5933 = []byte{
-	// 375 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x52, 0x41, 0x6b, 0xe2, 0x40,
-	0x18, 0x65, 0x5c, 0xd0, 0xdd, 0x59, 0x95, 0x25, 0xb0, 0xbb, 0xda, 0x7a, 0x10, 0x7b, 0xf1, 0xd2,
-	0x04, 0x14, 0x7a, 0xea, 0xa5, 0x42, 0x5b, 0x8b, 0x50, 0x24, 0x4a, 0x0f, 0xbd, 0x0c, 0x63, 0xf2,
-	0x39, 0x19, 0xe2, 0x64, 0xc2, 0xcc, 0x28, 0x8d, 0x3f, 0xa4, 0x87, 0x1e, 0xfb, 0x4f, 0xfa, 0xcf,
-	0x4a, 0x26, 0x4a, 0x4b, 0x1b, 0xe9, 0xa1, 0xc7, 0x37, 0xef, 0x7b, 0x6f, 0xde, 0xfb, 0xf8, 0xf0,
-	0xd0, 0x44, 0x5c, 0x85, 0x24, 0xa5, 0xca, 0x64, 0x9e, 0xe1, 0x49, 0xec, 0xa5, 0x4a, 0x1a, 0xe9,
-	0x51, 0xd0, 0x84, 0x05, 0x82, 0x44, 0x71, 0xb8, 0x24, 0xda, 0x28, 0xa0, 0x82, 0x27, 0xcc, 0xb5,
-	0xa4, 0xe3, 0x30, 0x29, 0xd9, 0x0a, 0xdc, 0x40, 0x65, 0xa9, 0x91, 0x6e, 0x2e, 0x3b, 0x3a, 0x39,
-	0x60, 0x14, 0x48, 0x21, 0x64, 0x52, 0x08, 0x7b, 0x2f, 0x08, 0xb7, 0x2f, 0x40, 0x5f, 0x07, 0x62,
-	0x1c, 0x87, 0xcb, 0xd9, 0xde, 0x76, 0x4a, 0x15, 0x15, 0xda, 0x39, 0xc3, 0xff, 0x03, 0x9e, 0x46,
-	0xa0, 0x0c, 0x3c, 0x18, 0xa2, 0x81, 0x09, 0x48, 0x0c, 0xd1, 0x7c, 0x0b, 0x2d, 0xd4, 0x45, 0xfd,
-	0x86, 0xff, 0xf7, 0x8d, 0x9e, 0x15, 0xec, 0x8c, 0x6f, 0xc1, 0xe9, 0xe3, 0x3f, 0x21, 0x28, 0xbe,
-	0x81, 0x90, 0xc4, 0x90, 0x15, 0x82, 0x8a, 0x15, 0x34, 0x77, 0xef, 0x13, 0xc8, 0xec, 0xe4, 0x08,
-	0x37, 0x6d, 0xa1, 0x88, 0xea, 0x88, 0x98, 0x2c, 0x85, 0xd6, 0x8f, 0x2e, 0xea, 0x37, 0x07, 0x1d,
-	0xf7, 0x73, 0x23, 0x77, 0x4c, 0x75, 0x34, 0xcf, 0x52, 0xf0, 0xeb, 0xb9, 0x66, 0x8f, 0x7a, 0x4f,
-	0x08, 0x77, 0x4a, 0x3a, 0x4c, 0x20, 0xbb, 0x92, 0x4a, 0x50, 0xe3, 0xb4, 0x70, 0x6d, 0x03, 0x4a,
-	0x73, 0x99, 0x58, 0xf7, 0x86, 0xbf, 0x87, 0xce, 0x25, 0xae, 0xa6, 0xb6, 0xaa, 0xed, 0xf3, 0x7b,
-	0x70, 0x5a, 0xf6, 0xed, 0xc1, 0xfd, 0xf8, 0x3b, 0xb1, 0xd3, 0xc6, 0x3f, 0x3f, 0xf4, 0xac, 0xc5,
-	0x45, 0xc1, 0xde, 0x23, 0xc2, 0xff, 0xca, 0xc3, 0xbd, 0x8f, 0x85, 0x0e, 0xc5, 0xaa, 0x7c, 0x27,
-	0xd6, 0x31, 0xfe, 0x95, 0xc7, 0xda, 0xd0, 0xd5, 0xba, 0xd8, 0x6b, 0xdd, 0xcf, 0x73, 0xde, 0xe5,
-	0x78, 0xc4, 0x70, 0x27, 0x90, 0xa2, 0xcc, 0xd8, 0x5e, 0xc6, 0x14, 0xdd, 0x9f, 0x33, 0x6e, 0xa2,
-	0xf5, 0xc2, 0x0d, 0xa4, 0xf0, 0x8a, 0xb1, 0xaf, 0xef, 0x91, 0x30, 0x49, 0x2c, 0xff, 0x5c, 0xa9,
-	0xce, 0x6f, 0x6e, 0x27, 0xd3, 0xd1, 0xa2, 0x6a, 0xf1, 0xf0, 0x35, 0x00, 0x00, 0xff, 0xff, 0x8b,
-	0xd0, 0x92, 0x6b, 0xd9, 0x02, 0x00, 0x00,
+	// 415 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0x4f, 0x6f, 0xd4, 0x30,
+	0x10, 0xc5, 0x95, 0x00, 0x5b, 0x30, 0xed, 0x0a, 0x59, 0xfc, 0x49, 0x61, 0x0f, 0xd5, 0x72, 0xd9,
+	0x0b, 0x09, 0x6a, 0x25, 0x4e, 0x5c, 0x58, 0x09, 0x28, 0x8a, 0x04, 0x51, 0xb6, 0xe2, 0xc0, 0xc5,
+	0x72, 0x93, 0x89, 0x63, 0xa5, 0x8e, 0x2d, 0xdb, 0x5d, 0xe1, 0x7e, 0x10, 0x0e, 0x48, 0x5c, 0xf8,
+	0xa4, 0x28, 0x4e, 0xa2, 0x45, 0x90, 0x15, 0x07, 0x8e, 0xe3, 0x37, 0x6f, 0xf2, 0x9b, 0x97, 0x41,
+	0x67, 0xb6, 0xe6, 0xba, 0x24, 0x8a, 0x6a, 0xeb, 0x12, 0xcb, 0xdb, 0x26, 0x51, 0x5a, 0x5a, 0x99,
+	0x50, 0x30, 0x84, 0x15, 0x82, 0xd4, 0x4d, 0x59, 0x11, 0x63, 0x35, 0x50, 0xc1, 0x5b, 0x16, 0x7b,
+	0x11, 0x63, 0x26, 0x25, 0xbb, 0x82, 0xb8, 0xd0, 0x4e, 0x59, 0x19, 0x77, 0xb6, 0xa7, 0xcf, 0xf7,
+	0x0c, 0x2a, 0xa4, 0x10, 0xb2, 0xed, 0x8d, 0xcb, 0x1f, 0x21, 0x3a, 0x7e, 0x03, 0xe6, 0x7d, 0x21,
+	0xce, 0x9b, 0xb2, 0xda, 0x8c, 0x63, 0x33, 0xaa, 0xa9, 0x30, 0xf8, 0x15, 0x7a, 0x52, 0x70, 0x55,
+	0x83, 0xb6, 0xf0, 0xd5, 0x12, 0x03, 0x4c, 0x40, 0x6b, 0x89, 0xe1, 0x37, 0x10, 0x05, 0x27, 0xc1,
+	0xea, 0x28, 0x7f, 0xb4, 0x93, 0x37, 0xbd, 0xba, 0xe1, 0x37, 0x80, 0x57, 0xe8, 0x41, 0x09, 0x9a,
+	0x6f, 0xa1, 0x24, 0x0d, 0xb8, 0xde, 0x10, 0x7a, 0xc3, 0x7c, 0x78, 0x4f, 0xc1, 0xf9, 0xce, 0x35,
+	0x9a, 0xfb, 0x85, 0x6a, 0x6a, 0x6a, 0x62, 0x9d, 0x82, 0xe8, 0xd6, 0x49, 0xb0, 0x9a, 0x9f, 0x2e,
+	0xe2, 0xbf, 0x37, 0x8a, 0xcf, 0xa9, 0xa9, 0x2f, 0x9c, 0x82, 0xfc, 0xb0, 0xf3, 0x8c, 0x15, 0x7e,
+	0x89, 0x1e, 0x56, 0x5c, 0x9b, 0x1d, 0xa0, 0xac, 0x2a, 0x03, 0x36, 0xba, 0xed, 0xbf, 0x88, 0xbd,
+	0x36, 0xd0, 0x7d, 0xf2, 0x4a, 0xc7, 0x37, 0xf4, 0x1a, 0xa2, 0x40, 0x77, 0x90, 0xd1, 0x9d, 0x9e,
+	0x6f, 0x7c, 0xcf, 0x40, 0xa7, 0xe0, 0x96, 0xdf, 0x03, 0xb4, 0x98, 0xc8, 0x27, 0x05, 0xf7, 0x4e,
+	0x6a, 0x41, 0x2d, 0x8e, 0xd0, 0xc1, 0x16, 0xb4, 0xe1, 0xb2, 0xf5, 0xe4, 0x47, 0xf9, 0x58, 0xe2,
+	0xb7, 0x68, 0xa6, 0x7c, 0x8c, 0x3e, 0xab, 0xfb, 0xa7, 0x2f, 0xa6, 0x56, 0xda, 0x9b, 0x7d, 0x3e,
+	0x98, 0xf1, 0x31, 0xba, 0xfb, 0x47, 0x86, 0x07, 0x4d, 0x1f, 0xde, 0xf2, 0x5b, 0x80, 0x1e, 0x4f,
+	0xc3, 0xfd, 0x8e, 0x15, 0xec, 0xc3, 0x0a, 0xff, 0x07, 0xeb, 0x19, 0xba, 0xd7, 0x61, 0x6d, 0xe9,
+	0xd5, 0x75, 0xff, 0xcf, 0x0e, 0xf3, 0x8e, 0xf3, 0x73, 0x57, 0xaf, 0x19, 0x5a, 0x14, 0x52, 0x4c,
+	0x0d, 0xf6, 0x57, 0x97, 0x05, 0x5f, 0x5e, 0x33, 0x6e, 0xeb, 0xeb, 0xcb, 0xb8, 0x90, 0x22, 0xe9,
+	0xdb, 0xfe, 0x7d, 0xeb, 0x84, 0x49, 0xe2, 0xf5, 0x9f, 0xe1, 0xec, 0xe2, 0xc3, 0xc7, 0x34, 0x5b,
+	0x5f, 0xce, 0x7c, 0x7d, 0xf6, 0x6b, 0x00, 0xc3, 0x5d, 0xa1, 0xff, 0x35, 0x03, 0x00, 0x00,
 }
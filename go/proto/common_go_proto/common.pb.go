@@ -44,6 +44,8 @@ const (
 	EllipticCurveType_NIST_P384     EllipticCurveType = 3
 	EllipticCurveType_NIST_P521     EllipticCurveType = 4
 	EllipticCurveType_CURVE25519    EllipticCurveType = 5
+	// Only supported for ECDSA signatures, after an explicit opt-in.
+	EllipticCurveType_SECP256K1 EllipticCurveType = 6
 )
 
 var EllipticCurveType_name = map[int32]string{
@@ -52,6 +54,7 @@ var EllipticCurveType_name = map[int32]string{
 	3: "NIST_P384",
 	4: "NIST_P521",
 	5: "CURVE25519",
+	6: "SECP256K1",
 }
 
 var EllipticCurveType_value = map[string]int32{
@@ -60,6 +63,7 @@ var EllipticCurveType_value = map[string]int32{
 	"NIST_P384":     3,
 	"NIST_P521":     4,
 	"CURVE25519":    5,
+	"SECP256K1":     6,
 }
 
 func (x EllipticCurveType) String() string {
@@ -109,10 +113,12 @@ const (
 	HashType_UNKNOWN_HASH HashType = 0
 	HashType_SHA1         HashType = 1
 	// fine.
-	HashType_SHA384 HashType = 2
-	HashType_SHA256 HashType = 3
-	HashType_SHA512 HashType = 4
-	HashType_SHA224 HashType = 5
+	HashType_SHA384   HashType = 2
+	HashType_SHA256   HashType = 3
+	HashType_SHA512   HashType = 4
+	HashType_SHA224   HashType = 5
+	HashType_SHA3_256 HashType = 6
+	HashType_SHA3_512 HashType = 7
 )
 
 var HashType_name = map[int32]string{
@@ -122,6 +128,8 @@ var HashType_name = map[int32]string{
 	3: "SHA256",
 	4: "SHA512",
 	5: "SHA224",
+	6: "SHA3_256",
+	7: "SHA3_512",
 }
 
 var HashType_value = map[string]int32{
@@ -131,6 +139,8 @@ var HashType_value = map[string]int32{
 	"SHA256":       3,
 	"SHA512":       4,
 	"SHA224":       5,
+	"SHA3_256":     6,
+	"SHA3_512":     7,
 }
 
 func (x HashType) String() string {
@@ -152,26 +162,27 @@ func init() {
 }
 
 var fileDescriptor_51c37496ff2054f5 = []byte{
-	// 321 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xdf, 0x6b, 0xfa, 0x30,
-	0x14, 0xc5, 0xfd, 0x8d, 0xdf, 0xcb, 0x57, 0x89, 0x79, 0x1e, 0xec, 0xc1, 0x37, 0x19, 0x2d, 0xad,
-	0x76, 0x6c, 0x8f, 0x5d, 0xad, 0x74, 0x0c, 0xd3, 0xd2, 0xb4, 0x8e, 0xed, 0x25, 0x68, 0x26, 0xb5,
-	0x9b, 0x35, 0x25, 0x8b, 0x03, 0xff, 0xfb, 0x61, 0x54, 0xca, 0xf0, 0x29, 0xf7, 0x13, 0xee, 0xbd,
-	0x27, 0xe7, 0x04, 0x86, 0x6a, 0x93, 0xcb, 0x0f, 0x56, 0x2e, 0xa5, 0x3a, 0x98, 0x2a, 0xdf, 0x7d,
-	0x99, 0xa5, 0x14, 0x4a, 0x98, 0x5c, 0x14, 0x85, 0xd8, 0x19, 0x1a, 0x30, 0xce, 0x84, 0xc8, 0xb6,
-	0x6b, 0x83, 0xcb, 0x43, 0xa9, 0x84, 0x71, 0x6c, 0x1b, 0x71, 0x18, 0xf8, 0xdb, 0x6d, 0x5e, 0xaa,
-	0x9c, 0x7b, 0x7b, 0xf9, 0xb3, 0x4e, 0x0e, 0xe5, 0x1a, 0x0f, 0xa0, 0x97, 0x92, 0x17, 0x12, 0xbe,
-	0x12, 0xe6, 0xa5, 0xf1, 0xc2, 0x47, 0x35, 0xdc, 0x83, 0x7f, 0xe4, 0x99, 0x26, 0x2c, 0xb2, 0x9d,
-	0x7b, 0xd4, 0xa8, 0x70, 0xfc, 0x30, 0x41, 0xcd, 0x0a, 0x1d, 0xdb, 0x42, 0x2d, 0xdc, 0x07, 0xd0,
-	0x73, 0xb6, 0xe3, 0x58, 0x8f, 0xa8, 0x3d, 0xfa, 0x84, 0x9e, 0xcf, 0x23, 0x91, 0xef, 0xd4, 0x4c,
-	0xc8, 0x62, 0xa9, 0x30, 0x86, 0xfe, 0x45, 0x60, 0x16, 0xc6, 0x73, 0x37, 0x41, 0x35, 0x8c, 0xe0,
-	0x7f, 0x4a, 0xbc, 0x70, 0x1e, 0xc5, 0x3e, 0xa5, 0xfe, 0x14, 0xd5, 0xf5, 0x9a, 0x8a, 0x1b, 0x78,
-	0x08, 0xb7, 0xd3, 0x90, 0x91, 0x30, 0x61, 0x29, 0xf5, 0x99, 0x17, 0xa7, 0xc4, 0x0b, 0xde, 0xd8,
-	0x9f, 0xa1, 0xe6, 0x68, 0x01, 0xdd, 0x60, 0xf9, 0xbd, 0xd1, 0x3e, 0xf4, 0xca, 0x93, 0x4c, 0xe0,
-	0xd2, 0x00, 0xd5, 0x70, 0x17, 0x5a, 0x34, 0x70, 0x2d, 0x54, 0xc7, 0x00, 0x1d, 0x1a, 0xb8, 0xc7,
-	0xe7, 0x37, 0xce, 0xf5, 0xd1, 0x59, 0xf3, 0x5c, 0x3b, 0x96, 0x8d, 0x5a, 0x97, 0x7b, 0x7b, 0x82,
-	0xda, 0x4f, 0x04, 0x6e, 0xb8, 0x28, 0x8c, 0xeb, 0x08, 0x4f, 0xe1, 0x46, 0xf5, 0xf7, 0xbb, 0x2c,
-	0x57, 0x9b, 0xfd, 0xca, 0xe0, 0xa2, 0x30, 0x4f, 0x6d, 0xd7, 0x3f, 0xc1, 0x32, 0xc1, 0x34, 0xaf,
-	0x3a, 0xfa, 0x18, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0x6c, 0x14, 0x46, 0x3d, 0xba, 0x01, 0x00,
-	0x00,
+	// 349 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xc1, 0xaf, 0x9a, 0x40,
+	0x10, 0xc6, 0x15, 0x95, 0xda, 0x89, 0x9a, 0x75, 0xcf, 0x4d, 0x7a, 0xf0, 0x66, 0x1a, 0x08, 0x28,
+	0x4d, 0x7b, 0x44, 0xc4, 0x60, 0x8c, 0x0b, 0x61, 0xa1, 0xa6, 0xbd, 0x6c, 0x94, 0x1a, 0xa4, 0x15,
+	0x97, 0x6c, 0xd7, 0x26, 0xfe, 0x3b, 0xef, 0x2f, 0x7d, 0x59, 0xd4, 0x67, 0x5e, 0xbc, 0xcd, 0x6f,
+	0xf7, 0xfb, 0x66, 0xf2, 0xcd, 0xc0, 0x48, 0x1e, 0x0a, 0xf1, 0x9b, 0x55, 0x5b, 0x21, 0x2f, 0xa6,
+	0x2c, 0x4e, 0x7f, 0xcd, 0x4a, 0x70, 0xc9, 0xcd, 0x8c, 0x97, 0x25, 0x3f, 0x19, 0x35, 0x60, 0x9c,
+	0x73, 0x9e, 0x1f, 0xf7, 0x46, 0x26, 0x2e, 0x95, 0xe4, 0x86, 0x92, 0x8d, 0x05, 0x0c, 0xfd, 0xe3,
+	0xb1, 0xa8, 0x64, 0x91, 0x79, 0x67, 0xf1, 0x7f, 0x9f, 0x5c, 0xaa, 0x3d, 0x1e, 0x42, 0x3f, 0x25,
+	0x2b, 0x12, 0x6e, 0x08, 0xf3, 0xd2, 0xf8, 0x87, 0x8f, 0x1a, 0xb8, 0x0f, 0x1f, 0xc9, 0x92, 0x26,
+	0x2c, 0xb2, 0x9d, 0xaf, 0x48, 0x7b, 0xe0, 0xe4, 0xdb, 0x14, 0xb5, 0x1e, 0xe8, 0xd8, 0x16, 0x6a,
+	0xe3, 0x01, 0x40, 0xed, 0xb3, 0x1d, 0xc7, 0xfa, 0x8e, 0x3a, 0xea, 0x9b, 0xfa, 0x9e, 0xb2, 0xae,
+	0x2c, 0xa4, 0x8f, 0xff, 0x40, 0xdf, 0xcf, 0x22, 0x5e, 0x9c, 0xe4, 0x82, 0x8b, 0x72, 0x2b, 0x31,
+	0x86, 0xc1, 0x7d, 0xde, 0x22, 0x8c, 0xd7, 0x6e, 0x82, 0x1a, 0x18, 0x41, 0x2f, 0x25, 0x5e, 0xb8,
+	0x8e, 0x62, 0x9f, 0x52, 0x7f, 0x8e, 0x9a, 0x75, 0xd7, 0x07, 0x6b, 0x78, 0x04, 0x9f, 0xe7, 0x21,
+	0x23, 0x61, 0xc2, 0x52, 0xea, 0x33, 0x2f, 0x4e, 0x89, 0x17, 0xfc, 0x64, 0xef, 0x4c, 0xad, 0xb1,
+	0x80, 0x6e, 0xb0, 0xfd, 0x77, 0xa8, 0x63, 0xd5, 0x2d, 0xaf, 0x63, 0x02, 0x97, 0x06, 0xa8, 0x81,
+	0xbb, 0xd0, 0xa6, 0x81, 0x6b, 0xa1, 0x26, 0x06, 0xd0, 0x69, 0xe0, 0xaa, 0x34, 0xda, 0xad, 0x56,
+	0x41, 0x5b, 0xb7, 0xda, 0xb1, 0x6c, 0xd4, 0xbe, 0xbf, 0xdb, 0x53, 0xd4, 0xc1, 0x3d, 0xe8, 0x2a,
+	0x3d, 0x53, 0x2a, 0xfd, 0x8d, 0x94, 0xee, 0xc3, 0x6c, 0x03, 0x9f, 0x32, 0x5e, 0x1a, 0xcf, 0xdb,
+	0xbe, 0xde, 0x21, 0x6a, 0xfe, 0xfa, 0x92, 0x17, 0xf2, 0x70, 0xde, 0x19, 0x19, 0x2f, 0xcd, 0xab,
+	0xec, 0xf9, 0x68, 0x2c, 0xe7, 0xac, 0xe6, 0x17, 0x4d, 0x4f, 0x96, 0x64, 0x15, 0xcd, 0x76, 0x7a,
+	0xcd, 0x93, 0xd7, 0x01, 0x00, 0xf7, 0xdf, 0x0f, 0xd6, 0xee, 0x01, 0x00, 0x00,
 }
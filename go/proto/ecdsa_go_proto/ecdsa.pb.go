@@ -41,9 +41,9 @@ type EcdsaSignatureEncoding int32
 
 const (
 	EcdsaSignatureEncoding_UNKNOWN_ENCODING EcdsaSignatureEncoding = 0
-	// The signature's format is r || s, where r and s are zero-padded and have the same size in
-	// bytes as the order of the curve. For example, for NIST P-256 curve, r and s are zero-padded to
-	// 32 bytes.
+	// The signature's format is r || s, where r and s are zero-padded and have
+	// the same size in bytes as the order of the curve. For example, for NIST
+	// P-256 curve, r and s are zero-padded to 32 bytes.
 	EcdsaSignatureEncoding_IEEE_P1363 EcdsaSignatureEncoding = 1
 	// The signature is encoded using ASN.1
 	// (https://tools.ietf.org/html/rfc5480#appendix-A):
@@ -81,10 +81,15 @@ type EcdsaParams struct {
 	// Required.
 	Curve common_go_proto.EllipticCurveType `protobuf:"varint,2,opt,name=curve,proto3,enum=google.crypto.tink.EllipticCurveType" json:"curve,omitempty"`
 	// Required.
-	Encoding             EcdsaSignatureEncoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=google.crypto.tink.EcdsaSignatureEncoding" json:"encoding,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Encoding EcdsaSignatureEncoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=google.crypto.tink.EcdsaSignatureEncoding" json:"encoding,omitempty"`
+	// Optional. If set, signers derive the per-signature nonce from the private
+	// key and the message digest as described in RFC 6979, instead of drawing it
+	// from the random number generator. The resulting signatures are ordinary
+	// ECDSA signatures and can be verified by any ECDSA verifier.
+	DeterministicNonce   bool     `protobuf:"varint,4,opt,name=deterministic_nonce,json=deterministicNonce,proto3" json:"deterministic_nonce,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *EcdsaParams) Reset()         { *m = EcdsaParams{} }
@@ -133,6 +138,13 @@ func (m *EcdsaParams) GetEncoding() EcdsaSignatureEncoding {
 	return EcdsaSignatureEncoding_UNKNOWN_ENCODING
 }
 
+func (m *EcdsaParams) GetDeterministicNonce() bool {
+	if m != nil {
+		return m.DeterministicNonce
+	}
+	return false
+}
+
 // key_type: type.googleapis.com/google.crypto.tink.EcdsaPublicKey
 type EcdsaPublicKey struct {
 	// Required.
@@ -318,33 +330,34 @@ func init() {
 }
 
 var fileDescriptor_8eef580f8138be98 = []byte{
-	// 435 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xd1, 0x6a, 0xdb, 0x30,
-	0x14, 0x86, 0xe7, 0x74, 0x4b, 0x93, 0xd3, 0x2e, 0x0b, 0x62, 0x0c, 0xb3, 0x15, 0x36, 0x3c, 0x06,
-	0xa3, 0x03, 0x87, 0x35, 0xb0, 0x31, 0x76, 0xb5, 0xb6, 0x6a, 0x17, 0x02, 0x6e, 0xf0, 0xba, 0x0e,
-	0x76, 0x23, 0x14, 0x45, 0xd8, 0x22, 0xb6, 0x25, 0x64, 0x39, 0x54, 0x57, 0x7b, 0x80, 0xbd, 0xc5,
-	0xde, 0x67, 0xef, 0x34, 0x2c, 0xbb, 0xbd, 0xa9, 0xb3, 0x8b, 0xde, 0xe9, 0x87, 0xf3, 0x49, 0xdf,
-	0x7f, 0x10, 0x04, 0x26, 0x15, 0x7a, 0x45, 0x14, 0xd5, 0xc6, 0x4e, 0x8c, 0x28, 0xd6, 0x13, 0xa5,
-	0xa5, 0x91, 0x13, 0xce, 0x56, 0x25, 0x0d, 0xdd, 0x19, 0xa1, 0x44, 0xca, 0x24, 0xe3, 0x21, 0xd3,
-	0x56, 0x19, 0x19, 0xd6, 0x53, 0xcf, 0x5f, 0x6f, 0xe1, 0x98, 0xcc, 0x73, 0x59, 0x34, 0x60, 0xf0,
-	0xd7, 0x83, 0x3d, 0x5c, 0x5f, 0xb4, 0xa0, 0x9a, 0xe6, 0x25, 0xfa, 0x04, 0xc3, 0x94, 0x96, 0x29,
-	0x31, 0x56, 0x71, 0xdf, 0x7b, 0xe5, 0xbd, 0x1d, 0x1d, 0x1d, 0x84, 0x77, 0x2f, 0x0f, 0xbf, 0xd2,
-	0x32, 0xbd, 0xb4, 0x8a, 0xc7, 0x83, 0xb4, 0x3d, 0xa1, 0xcf, 0xf0, 0x88, 0x55, 0x7a, 0xc3, 0xfd,
-	0x9e, 0xc3, 0xde, 0x74, 0x61, 0x38, 0xcb, 0x84, 0x32, 0x82, 0x9d, 0xd4, 0x83, 0x8e, 0x6f, 0x18,
-	0x74, 0x06, 0x03, 0x5e, 0x30, 0xb9, 0x12, 0x45, 0xe2, 0xef, 0x38, 0xfe, 0xb0, 0x93, 0xaf, 0x55,
-	0xbf, 0x89, 0xa4, 0xa0, 0xa6, 0xd2, 0x1c, 0xb7, 0x44, 0x7c, 0xcb, 0x06, 0xbf, 0x60, 0xd4, 0xd4,
-	0xa9, 0x96, 0x99, 0x60, 0x73, 0x6e, 0x91, 0x0f, 0xbb, 0x1b, 0xae, 0x4b, 0x21, 0x0b, 0xd7, 0xe7,
-	0x71, 0x7c, 0x13, 0xd1, 0x47, 0xe8, 0x2b, 0xd7, 0xda, 0x19, 0xef, 0x1d, 0xbd, 0xdc, 0xfa, 0x62,
-	0xb3, 0x9c, 0xb8, 0x1d, 0x47, 0xfb, 0xe0, 0x5d, 0x3b, 0xcb, 0xfd, 0xd8, 0xbb, 0xae, 0x93, 0xf5,
-	0x1f, 0x36, 0xc9, 0x06, 0xbf, 0x3d, 0x78, 0xd2, 0x30, 0x5a, 0x6c, 0xa8, 0xe1, 0xff, 0x57, 0xf8,
-	0x02, 0xa0, 0x9c, 0x29, 0x59, 0x73, 0xdb, 0x6a, 0x04, 0xdb, 0x35, 0x6e, 0x4a, 0xc5, 0x43, 0x75,
-	0xdb, 0xef, 0x05, 0x0c, 0xd7, 0xdc, 0x92, 0x0d, 0xcd, 0x2a, 0xde, 0x4a, 0x0d, 0xd6, 0xdc, 0x5e,
-	0xd5, 0x39, 0x98, 0xb5, 0xeb, 0x98, 0x73, 0x7b, 0x26, 0x75, 0x4e, 0xcd, 0xbd, 0x4b, 0x1f, 0x9e,
-	0xc3, 0xb3, 0xee, 0xed, 0xa3, 0xa7, 0x30, 0xfe, 0x1e, 0xcd, 0xa3, 0x8b, 0x1f, 0x11, 0xc1, 0xd1,
-	0xc9, 0xc5, 0xe9, 0x2c, 0x3a, 0x1f, 0x3f, 0x40, 0x23, 0x80, 0x19, 0xc6, 0x98, 0x2c, 0xde, 0x4f,
-	0x3f, 0x4c, 0xc7, 0x1e, 0xda, 0x85, 0x9d, 0x53, 0x1c, 0x8f, 0x7b, 0xc7, 0x57, 0x70, 0xc0, 0x64,
-	0xde, 0xf5, 0xac, 0xfb, 0x92, 0x0b, 0xef, 0xe7, 0xbb, 0x44, 0x98, 0xb4, 0x5a, 0x86, 0x4c, 0xe6,
-	0x93, 0x66, 0xec, 0xce, 0xbf, 0x27, 0x89, 0x24, 0x2e, 0xfe, 0xe9, 0xf5, 0x2f, 0x67, 0xd1, 0x7c,
-	0x71, 0xbc, 0xec, 0xbb, 0x3c, 0xfd, 0x17, 0x00, 0x00, 0xff, 0xff, 0x40, 0x46, 0x00, 0xdd, 0x30,
-	0x03, 0x00, 0x00,
+	// 461 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x61, 0x6b, 0xd3, 0x40,
+	0x1c, 0xc6, 0xbd, 0x4e, 0xbb, 0xf6, 0xbf, 0x5a, 0xcb, 0x29, 0x12, 0x74, 0xe0, 0x88, 0x08, 0x63,
+	0x42, 0x82, 0x2b, 0x28, 0xe2, 0x2b, 0xb7, 0x65, 0xb3, 0x14, 0xb2, 0x12, 0xe7, 0x04, 0xdf, 0x84,
+	0xeb, 0xf5, 0x48, 0x8e, 0x26, 0xb9, 0xe3, 0x72, 0x29, 0xbb, 0x57, 0x7e, 0x00, 0xbf, 0x85, 0x1f,
+	0x54, 0x24, 0x97, 0x6c, 0x20, 0x6d, 0x7d, 0xb1, 0x77, 0xf7, 0x70, 0xff, 0xdf, 0x3f, 0xcf, 0xf3,
+	0x24, 0x01, 0x57, 0xa7, 0x5c, 0x2d, 0x62, 0x49, 0x94, 0x36, 0xbe, 0xe6, 0xc5, 0xd2, 0x97, 0x4a,
+	0x68, 0xe1, 0x33, 0xba, 0x28, 0x89, 0x67, 0xcf, 0x18, 0x27, 0x42, 0x24, 0x19, 0xf3, 0xa8, 0x32,
+	0x52, 0x0b, 0xaf, 0x9e, 0x7a, 0xf1, 0x7a, 0x0b, 0x47, 0x45, 0x9e, 0x8b, 0xa2, 0x01, 0xdd, 0x3f,
+	0x08, 0xf6, 0x82, 0x7a, 0xd1, 0x8c, 0x28, 0x92, 0x97, 0xf8, 0x23, 0xf4, 0x53, 0x52, 0xa6, 0xb1,
+	0x36, 0x92, 0x39, 0xe8, 0x00, 0x1d, 0x0e, 0x8f, 0xf7, 0xbd, 0xf5, 0xe5, 0xde, 0x17, 0x52, 0xa6,
+	0x57, 0x46, 0xb2, 0xa8, 0x97, 0xb6, 0x27, 0xfc, 0x09, 0x1e, 0xd1, 0x4a, 0xad, 0x98, 0xd3, 0xb1,
+	0xd8, 0x9b, 0x4d, 0x58, 0x90, 0x65, 0x5c, 0x6a, 0x4e, 0x4f, 0xeb, 0x41, 0xcb, 0x37, 0x0c, 0x3e,
+	0x87, 0x1e, 0x2b, 0xa8, 0x58, 0xf0, 0x22, 0x71, 0x76, 0x2c, 0x7f, 0xb4, 0x91, 0xaf, 0xad, 0x7e,
+	0xe5, 0x49, 0x41, 0x74, 0xa5, 0x58, 0xd0, 0x12, 0xd1, 0x1d, 0x8b, 0x7d, 0x78, 0xba, 0x60, 0x9a,
+	0xa9, 0x9c, 0x17, 0xbc, 0xd4, 0x9c, 0xc6, 0x85, 0x28, 0x28, 0x73, 0x1e, 0x1e, 0xa0, 0xc3, 0x5e,
+	0x84, 0xff, 0xb9, 0x0a, 0xeb, 0x1b, 0xf7, 0x27, 0x0c, 0x9b, 0xfc, 0xd5, 0x3c, 0xe3, 0x74, 0xca,
+	0x0c, 0x76, 0x60, 0x77, 0xc5, 0x54, 0xc9, 0x45, 0x61, 0x0b, 0x78, 0x1c, 0xdd, 0x4a, 0xfc, 0x01,
+	0xba, 0xd2, 0xd6, 0x64, 0x23, 0xee, 0x1d, 0xbf, 0xda, 0x6a, 0xb1, 0x69, 0x33, 0x6a, 0xc7, 0xf1,
+	0x00, 0xd0, 0x8d, 0x8d, 0x35, 0x88, 0xd0, 0x4d, 0xad, 0x8c, 0x75, 0x34, 0x88, 0x90, 0x71, 0x7f,
+	0x21, 0x78, 0xd2, 0x30, 0x8a, 0xaf, 0x88, 0x66, 0xff, 0xb7, 0xf0, 0x19, 0x40, 0x5a, 0xa7, 0xf1,
+	0x92, 0x99, 0xd6, 0x86, 0xbb, 0xdd, 0xc6, 0x6d, 0xa8, 0xa8, 0x2f, 0xef, 0xf2, 0xbd, 0x84, 0xfe,
+	0x92, 0x99, 0x78, 0x45, 0xb2, 0x8a, 0xb5, 0xa6, 0x7a, 0x4b, 0x66, 0xae, 0x6b, 0xed, 0x4e, 0xda,
+	0x3a, 0xa6, 0xcc, 0x9c, 0x0b, 0x95, 0x13, 0x7d, 0xef, 0xd0, 0x47, 0x17, 0xf0, 0x7c, 0xf3, 0xeb,
+	0xc2, 0xcf, 0x60, 0xf4, 0x2d, 0x9c, 0x86, 0x97, 0xdf, 0xc3, 0x38, 0x08, 0x4f, 0x2f, 0xcf, 0x26,
+	0xe1, 0xc5, 0xe8, 0x01, 0x1e, 0x02, 0x4c, 0x82, 0x20, 0x88, 0x67, 0xef, 0xc6, 0xef, 0xc7, 0x23,
+	0x84, 0x77, 0x61, 0xe7, 0x2c, 0x88, 0x46, 0x9d, 0x93, 0x6b, 0xd8, 0xa7, 0x22, 0xdf, 0xf4, 0x58,
+	0xfb, 0x0d, 0xcf, 0xd0, 0x8f, 0xb7, 0x09, 0xd7, 0x69, 0x35, 0xf7, 0xa8, 0xc8, 0xfd, 0x66, 0x6c,
+	0xed, 0x47, 0x89, 0x13, 0x11, 0x5b, 0xf9, 0xbb, 0xd3, 0xbd, 0x9a, 0x84, 0xd3, 0xd9, 0xc9, 0xbc,
+	0x6b, 0xf5, 0xf8, 0xef, 0x00, 0x75, 0xeb, 0xcf, 0xcb, 0x61, 0x03, 0x00, 0x00,
 }
@@ -36,17 +36,102 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-type Ed25519KeyFormat struct {
+type Ed25519Variant int32
+
+const (
+	// Pure Ed25519, see https://tools.ietf.org/html/rfc8032#section-5.1.
+	Ed25519Variant_ED25519 Ed25519Variant = 0
+	// Ed25519ph: the message is prehashed with SHA-512 before signing, which
+	// allows signing streams of data.
+	Ed25519Variant_ED25519_PH Ed25519Variant = 1
+	// Ed25519ctx: Ed25519 with a non-empty context string for domain
+	// separation.
+	Ed25519Variant_ED25519_CTX Ed25519Variant = 2
+)
+
+var Ed25519Variant_name = map[int32]string{
+	0: "ED25519",
+	1: "ED25519_PH",
+	2: "ED25519_CTX",
+}
+
+var Ed25519Variant_value = map[string]int32{
+	"ED25519":     0,
+	"ED25519_PH":  1,
+	"ED25519_CTX": 2,
+}
+
+func (x Ed25519Variant) String() string {
+	return proto.EnumName(Ed25519Variant_name, int32(x))
+}
+
+func (Ed25519Variant) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_677c38422e9f421e, []int{0}
+}
+
+type Ed25519Params struct {
+	Variant Ed25519Variant `protobuf:"varint,1,opt,name=variant,proto3,enum=google.crypto.tink.Ed25519Variant" json:"variant,omitempty"`
+	// The context string, at most 255 bytes long. Must be empty for ED25519
+	// and non-empty for ED25519_CTX. Optional for ED25519_PH.
+	Context              []byte   `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *Ed25519Params) Reset()         { *m = Ed25519Params{} }
+func (m *Ed25519Params) String() string { return proto.CompactTextString(m) }
+func (*Ed25519Params) ProtoMessage()    {}
+func (*Ed25519Params) Descriptor() ([]byte, []int) {
+	return fileDescriptor_677c38422e9f421e, []int{0}
+}
+
+func (m *Ed25519Params) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ed25519Params.Unmarshal(m, b)
+}
+func (m *Ed25519Params) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ed25519Params.Marshal(b, m, deterministic)
+}
+func (m *Ed25519Params) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ed25519Params.Merge(m, src)
+}
+func (m *Ed25519Params) XXX_Size() int {
+	return xxx_messageInfo_Ed25519Params.Size(m)
+}
+func (m *Ed25519Params) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ed25519Params.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ed25519Params proto.InternalMessageInfo
+
+func (m *Ed25519Params) GetVariant() Ed25519Variant {
+	if m != nil {
+		return m.Variant
+	}
+	return Ed25519Variant_ED25519
+}
+
+func (m *Ed25519Params) GetContext() []byte {
+	if m != nil {
+		return m.Context
+	}
+	return nil
+}
+
+type Ed25519KeyFormat struct {
+	Version uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// Optional. If missing, keys use pure Ed25519.
+	Params               *Ed25519Params `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
 func (m *Ed25519KeyFormat) Reset()         { *m = Ed25519KeyFormat{} }
 func (m *Ed25519KeyFormat) String() string { return proto.CompactTextString(m) }
 func (*Ed25519KeyFormat) ProtoMessage()    {}
 func (*Ed25519KeyFormat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_677c38422e9f421e, []int{0}
+	return fileDescriptor_677c38422e9f421e, []int{1}
 }
 
 func (m *Ed25519KeyFormat) XXX_Unmarshal(b []byte) error {
@@ -67,6 +152,20 @@ func (m *Ed25519KeyFormat) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Ed25519KeyFormat proto.InternalMessageInfo
 
+func (m *Ed25519KeyFormat) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Ed25519KeyFormat) GetParams() *Ed25519Params {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
 // key_type: type.googleapis.com/google.crypto.tink.Ed25519PublicKey
 type Ed25519PublicKey struct {
 	// Required.
@@ -74,17 +173,19 @@ type Ed25519PublicKey struct {
 	// The public key is 32 bytes, encoded according to
 	// https://tools.ietf.org/html/rfc8032#section-5.1.2.
 	// Required.
-	KeyValue             []byte   `protobuf:"bytes,2,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	KeyValue []byte `protobuf:"bytes,2,opt,name=key_value,json=keyValue,proto3" json:"key_value,omitempty"`
+	// Optional. If missing, the key uses pure Ed25519.
+	Params               *Ed25519Params `protobuf:"bytes,3,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *Ed25519PublicKey) Reset()         { *m = Ed25519PublicKey{} }
 func (m *Ed25519PublicKey) String() string { return proto.CompactTextString(m) }
 func (*Ed25519PublicKey) ProtoMessage()    {}
 func (*Ed25519PublicKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_677c38422e9f421e, []int{1}
+	return fileDescriptor_677c38422e9f421e, []int{2}
 }
 
 func (m *Ed25519PublicKey) XXX_Unmarshal(b []byte) error {
@@ -119,6 +220,13 @@ func (m *Ed25519PublicKey) GetKeyValue() []byte {
 	return nil
 }
 
+func (m *Ed25519PublicKey) GetParams() *Ed25519Params {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
 // key_type: type.googleapis.com/google.crypto.tink.Ed25519PrivateKey
 type Ed25519PrivateKey struct {
 	// Required.
@@ -138,7 +246,7 @@ func (m *Ed25519PrivateKey) Reset()         { *m = Ed25519PrivateKey{} }
 func (m *Ed25519PrivateKey) String() string { return proto.CompactTextString(m) }
 func (*Ed25519PrivateKey) ProtoMessage()    {}
 func (*Ed25519PrivateKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_677c38422e9f421e, []int{2}
+	return fileDescriptor_677c38422e9f421e, []int{3}
 }
 
 func (m *Ed25519PrivateKey) XXX_Unmarshal(b []byte) error {
@@ -181,6 +289,8 @@ func (m *Ed25519PrivateKey) GetPublicKey() *Ed25519PublicKey {
 }
 
 func init() {
+	proto.RegisterEnum("google.crypto.tink.Ed25519Variant", Ed25519Variant_name, Ed25519Variant_value)
+	proto.RegisterType((*Ed25519Params)(nil), "google.crypto.tink.Ed25519Params")
 	proto.RegisterType((*Ed25519KeyFormat)(nil), "google.crypto.tink.Ed25519KeyFormat")
 	proto.RegisterType((*Ed25519PublicKey)(nil), "google.crypto.tink.Ed25519PublicKey")
 	proto.RegisterType((*Ed25519PrivateKey)(nil), "google.crypto.tink.Ed25519PrivateKey")
@@ -191,21 +301,27 @@ func init() {
 }
 
 var fileDescriptor_677c38422e9f421e = []byte{
-	// 250 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x29, 0xc9, 0xc8, 0x2c,
-	0x4a, 0x89, 0x2f, 0x48, 0x2c, 0x2a, 0xa9, 0xd4, 0x2f, 0xc9, 0xcc, 0xcb, 0xd6, 0x2f, 0x28, 0xca,
-	0x2f, 0xc9, 0xd7, 0x4f, 0x4d, 0x31, 0x32, 0x35, 0x35, 0xb4, 0xd4, 0x03, 0xf3, 0x84, 0x84, 0xd2,
-	0xf3, 0xf3, 0xd3, 0x73, 0x52, 0xf5, 0x92, 0x8b, 0x2a, 0x0b, 0x4a, 0xf2, 0xf5, 0x40, 0xea, 0x94,
-	0x84, 0xb8, 0x04, 0x5c, 0x21, 0x8a, 0xbc, 0x53, 0x2b, 0xdd, 0xf2, 0x8b, 0x72, 0x13, 0x4b, 0x94,
-	0x3c, 0xe1, 0x62, 0x01, 0xa5, 0x49, 0x39, 0x99, 0xc9, 0xde, 0xa9, 0x95, 0x42, 0x12, 0x5c, 0xec,
-	0x65, 0xa9, 0x45, 0xc5, 0x99, 0xf9, 0x79, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0xbc, 0x41, 0x30, 0xae,
-	0x90, 0x34, 0x17, 0x67, 0x76, 0x6a, 0x65, 0x7c, 0x59, 0x62, 0x4e, 0x69, 0xaa, 0x04, 0x93, 0x02,
-	0xa3, 0x06, 0x4f, 0x10, 0x47, 0x76, 0x6a, 0x65, 0x18, 0x88, 0xaf, 0xd4, 0xcf, 0xc8, 0x25, 0x08,
-	0x33, 0xab, 0x28, 0xb3, 0x2c, 0xb1, 0x24, 0x95, 0x7c, 0xc3, 0x84, 0x9c, 0xb9, 0xb8, 0x0a, 0xc0,
-	0x0e, 0x8a, 0xcf, 0x4e, 0xad, 0x94, 0x60, 0x56, 0x60, 0xd4, 0xe0, 0x36, 0x52, 0xd1, 0xc3, 0xf4,
-	0x94, 0x1e, 0xba, 0xeb, 0x83, 0x38, 0x0b, 0x60, 0x4c, 0xa7, 0x08, 0x2e, 0x99, 0xe4, 0xfc, 0x5c,
-	0x6c, 0xba, 0xc0, 0x81, 0x14, 0xc0, 0x18, 0xa5, 0x9b, 0x9e, 0x59, 0x92, 0x51, 0x9a, 0xa4, 0x97,
-	0x9c, 0x9f, 0xab, 0x0f, 0x51, 0x86, 0x25, 0x48, 0xe3, 0xd3, 0xf3, 0xe3, 0xc1, 0x02, 0x8b, 0x98,
-	0xd8, 0x42, 0x3c, 0xfd, 0xbc, 0x03, 0x9c, 0x92, 0xd8, 0xc0, 0x7c, 0x63, 0x40, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xe2, 0x32, 0x0c, 0x2b, 0x8d, 0x01, 0x00, 0x00,
+	// 347 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0xd1, 0x4b, 0xc2, 0x50,
+	0x14, 0xc6, 0x9b, 0x81, 0xe6, 0x31, 0x6d, 0xdd, 0x27, 0xa1, 0x1e, 0x6c, 0xf8, 0x20, 0x41, 0x1b,
+	0x19, 0x3e, 0x08, 0xd1, 0x83, 0x66, 0x14, 0x42, 0x8c, 0x21, 0x22, 0xbd, 0x8c, 0xeb, 0xbc, 0xcc,
+	0xcb, 0xdc, 0xee, 0xb8, 0x5e, 0x47, 0xf7, 0xbd, 0xf7, 0xfe, 0x87, 0xfe, 0xd2, 0xf0, 0x6e, 0x37,
+	0x8a, 0x44, 0x88, 0xde, 0xf6, 0x9d, 0x7d, 0xdf, 0xbe, 0xdf, 0x61, 0x07, 0xda, 0x62, 0x49, 0xf9,
+	0xc2, 0x4f, 0x31, 0x17, 0xd2, 0x11, 0x34, 0x89, 0x9c, 0x94, 0x33, 0xc1, 0x1c, 0xb2, 0xe8, 0xf6,
+	0x7a, 0xd7, 0x7d, 0x5b, 0x29, 0x84, 0x42, 0xc6, 0xc2, 0x15, 0xb1, 0x03, 0x2e, 0x53, 0xc1, 0xec,
+	0xad, 0xcf, 0x0a, 0xa1, 0x3e, 0xca, 0x4d, 0x2e, 0xe6, 0x38, 0x5e, 0xa3, 0x5b, 0xa8, 0x64, 0x98,
+	0x53, 0x9c, 0x88, 0xa6, 0xd1, 0x32, 0x3a, 0x8d, 0xae, 0x65, 0xff, 0x8e, 0xd9, 0x45, 0x66, 0x9a,
+	0x3b, 0x3d, 0x1d, 0x41, 0x4d, 0xa8, 0x04, 0x2c, 0x11, 0xe4, 0x55, 0x34, 0x4b, 0x2d, 0xa3, 0x73,
+	0xec, 0x69, 0x69, 0x85, 0x60, 0x16, 0xa1, 0x31, 0x91, 0x0f, 0x8c, 0xc7, 0x58, 0xb9, 0x33, 0xc2,
+	0xd7, 0x94, 0x25, 0xaa, 0xab, 0xee, 0x69, 0x89, 0xfa, 0x50, 0x4e, 0x15, 0x8f, 0xfa, 0x4c, 0xad,
+	0x7b, 0xb1, 0x07, 0x22, 0x07, 0xf7, 0x8a, 0x80, 0xf5, 0x66, 0x7c, 0x35, 0xb9, 0x9b, 0xf9, 0x8a,
+	0x06, 0x63, 0x22, 0xf7, 0x34, 0x9d, 0x41, 0x35, 0x22, 0xd2, 0xcf, 0xf0, 0x6a, 0x43, 0x0a, 0xe6,
+	0xa3, 0x88, 0xc8, 0xe9, 0x56, 0x7f, 0xc3, 0x38, 0xfc, 0x2b, 0xc6, 0xbb, 0x01, 0xa7, 0xfa, 0x0d,
+	0xa7, 0x19, 0x16, 0xe4, 0x1f, 0x1c, 0x43, 0x80, 0x54, 0xed, 0xe2, 0x47, 0x44, 0x16, 0x2c, 0xed,
+	0x7d, 0x2c, 0x7a, 0x71, 0xaf, 0x9a, 0xea, 0xc7, 0xcb, 0x3b, 0x68, 0xfc, 0xfc, 0x6d, 0xa8, 0x06,
+	0x95, 0xd1, 0xbd, 0x9a, 0x98, 0x07, 0xa8, 0x01, 0x50, 0x08, 0xdf, 0x7d, 0x34, 0x0d, 0x74, 0x02,
+	0x35, 0xad, 0x87, 0x93, 0x99, 0x59, 0x1a, 0xcc, 0xe0, 0x3c, 0x60, 0xf1, 0xae, 0x56, 0x75, 0x5e,
+	0xae, 0xf1, 0x72, 0x15, 0x52, 0xb1, 0xdc, 0xcc, 0xed, 0x80, 0xc5, 0x4e, 0x6e, 0xdb, 0x71, 0x8c,
+	0x7e, 0xc8, 0x7c, 0x35, 0xf8, 0x28, 0x95, 0x27, 0x4f, 0xcf, 0x63, 0x77, 0x30, 0x2f, 0x2b, 0x7d,
+	0xf3, 0x39, 0x00, 0xc9, 0x94, 0x7e, 0x88, 0xc7, 0x02, 0x00, 0x00,
 }
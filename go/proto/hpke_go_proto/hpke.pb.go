@@ -0,0 +1,379 @@
+// Copyright 2021 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/hpke.proto
+
+package hpke_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type HpkeKem int32
+
+const (
+	HpkeKem_KEM_UNKNOWN              HpkeKem = 0
+	HpkeKem_DHKEM_X25519_HKDF_SHA256 HpkeKem = 1
+)
+
+var HpkeKem_name = map[int32]string{
+	0: "KEM_UNKNOWN",
+	1: "DHKEM_X25519_HKDF_SHA256",
+}
+
+var HpkeKem_value = map[string]int32{
+	"KEM_UNKNOWN":              0,
+	"DHKEM_X25519_HKDF_SHA256": 1,
+}
+
+func (x HpkeKem) String() string {
+	return proto.EnumName(HpkeKem_name, int32(x))
+}
+
+func (HpkeKem) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{0}
+}
+
+type HpkeKdf int32
+
+const (
+	HpkeKdf_KDF_UNKNOWN HpkeKdf = 0
+	HpkeKdf_HKDF_SHA256 HpkeKdf = 1
+	HpkeKdf_HKDF_SHA384 HpkeKdf = 2
+	HpkeKdf_HKDF_SHA512 HpkeKdf = 3
+)
+
+var HpkeKdf_name = map[int32]string{
+	0: "KDF_UNKNOWN",
+	1: "HKDF_SHA256",
+	2: "HKDF_SHA384",
+	3: "HKDF_SHA512",
+}
+
+var HpkeKdf_value = map[string]int32{
+	"KDF_UNKNOWN": 0,
+	"HKDF_SHA256": 1,
+	"HKDF_SHA384": 2,
+	"HKDF_SHA512": 3,
+}
+
+func (x HpkeKdf) String() string {
+	return proto.EnumName(HpkeKdf_name, int32(x))
+}
+
+func (HpkeKdf) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{1}
+}
+
+type HpkeAead int32
+
+const (
+	HpkeAead_AEAD_UNKNOWN      HpkeAead = 0
+	HpkeAead_AES_128_GCM       HpkeAead = 1
+	HpkeAead_AES_256_GCM       HpkeAead = 2
+	HpkeAead_CHACHA20_POLY1305 HpkeAead = 3
+)
+
+var HpkeAead_name = map[int32]string{
+	0: "AEAD_UNKNOWN",
+	1: "AES_128_GCM",
+	2: "AES_256_GCM",
+	3: "CHACHA20_POLY1305",
+}
+
+var HpkeAead_value = map[string]int32{
+	"AEAD_UNKNOWN":      0,
+	"AES_128_GCM":       1,
+	"AES_256_GCM":       2,
+	"CHACHA20_POLY1305": 3,
+}
+
+func (x HpkeAead) String() string {
+	return proto.EnumName(HpkeAead_name, int32(x))
+}
+
+func (HpkeAead) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{2}
+}
+
+type HpkeParams struct {
+	Kem                  HpkeKem  `protobuf:"varint,1,opt,name=kem,proto3,enum=google.crypto.tink.HpkeKem" json:"kem,omitempty"`
+	Kdf                  HpkeKdf  `protobuf:"varint,2,opt,name=kdf,proto3,enum=google.crypto.tink.HpkeKdf" json:"kdf,omitempty"`
+	Aead                 HpkeAead `protobuf:"varint,3,opt,name=aead,proto3,enum=google.crypto.tink.HpkeAead" json:"aead,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HpkeParams) Reset()         { *m = HpkeParams{} }
+func (m *HpkeParams) String() string { return proto.CompactTextString(m) }
+func (*HpkeParams) ProtoMessage()    {}
+func (*HpkeParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{0}
+}
+
+func (m *HpkeParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HpkeParams.Unmarshal(m, b)
+}
+func (m *HpkeParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HpkeParams.Marshal(b, m, deterministic)
+}
+func (m *HpkeParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HpkeParams.Merge(m, src)
+}
+func (m *HpkeParams) XXX_Size() int {
+	return xxx_messageInfo_HpkeParams.Size(m)
+}
+func (m *HpkeParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_HpkeParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HpkeParams proto.InternalMessageInfo
+
+func (m *HpkeParams) GetKem() HpkeKem {
+	if m != nil {
+		return m.Kem
+	}
+	return HpkeKem_KEM_UNKNOWN
+}
+
+func (m *HpkeParams) GetKdf() HpkeKdf {
+	if m != nil {
+		return m.Kdf
+	}
+	return HpkeKdf_KDF_UNKNOWN
+}
+
+func (m *HpkeParams) GetAead() HpkeAead {
+	if m != nil {
+		return m.Aead
+	}
+	return HpkeAead_AEAD_UNKNOWN
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.HpkePublicKey
+type HpkePublicKey struct {
+	Version uint32      `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Params  *HpkeParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	// The serialized public key, as in SerializePublicKey() of RFC 9180.
+	PublicKey            []byte   `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HpkePublicKey) Reset()         { *m = HpkePublicKey{} }
+func (m *HpkePublicKey) String() string { return proto.CompactTextString(m) }
+func (*HpkePublicKey) ProtoMessage()    {}
+func (*HpkePublicKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{1}
+}
+
+func (m *HpkePublicKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HpkePublicKey.Unmarshal(m, b)
+}
+func (m *HpkePublicKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HpkePublicKey.Marshal(b, m, deterministic)
+}
+func (m *HpkePublicKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HpkePublicKey.Merge(m, src)
+}
+func (m *HpkePublicKey) XXX_Size() int {
+	return xxx_messageInfo_HpkePublicKey.Size(m)
+}
+func (m *HpkePublicKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_HpkePublicKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HpkePublicKey proto.InternalMessageInfo
+
+func (m *HpkePublicKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HpkePublicKey) GetParams() *HpkeParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *HpkePublicKey) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.HpkePrivateKey
+type HpkePrivateKey struct {
+	Version   uint32         `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PublicKey *HpkePublicKey `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// The serialized private key, as in SerializePrivateKey() of RFC 9180.
+	PrivateKey           []byte   `protobuf:"bytes,3,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HpkePrivateKey) Reset()         { *m = HpkePrivateKey{} }
+func (m *HpkePrivateKey) String() string { return proto.CompactTextString(m) }
+func (*HpkePrivateKey) ProtoMessage()    {}
+func (*HpkePrivateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{2}
+}
+
+func (m *HpkePrivateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HpkePrivateKey.Unmarshal(m, b)
+}
+func (m *HpkePrivateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HpkePrivateKey.Marshal(b, m, deterministic)
+}
+func (m *HpkePrivateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HpkePrivateKey.Merge(m, src)
+}
+func (m *HpkePrivateKey) XXX_Size() int {
+	return xxx_messageInfo_HpkePrivateKey.Size(m)
+}
+func (m *HpkePrivateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_HpkePrivateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HpkePrivateKey proto.InternalMessageInfo
+
+func (m *HpkePrivateKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *HpkePrivateKey) GetPublicKey() *HpkePublicKey {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *HpkePrivateKey) GetPrivateKey() []byte {
+	if m != nil {
+		return m.PrivateKey
+	}
+	return nil
+}
+
+type HpkeKeyFormat struct {
+	Params               *HpkeParams `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *HpkeKeyFormat) Reset()         { *m = HpkeKeyFormat{} }
+func (m *HpkeKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*HpkeKeyFormat) ProtoMessage()    {}
+func (*HpkeKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1f0cddb96f8353e7, []int{3}
+}
+
+func (m *HpkeKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HpkeKeyFormat.Unmarshal(m, b)
+}
+func (m *HpkeKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HpkeKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *HpkeKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HpkeKeyFormat.Merge(m, src)
+}
+func (m *HpkeKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_HpkeKeyFormat.Size(m)
+}
+func (m *HpkeKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_HpkeKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HpkeKeyFormat proto.InternalMessageInfo
+
+func (m *HpkeKeyFormat) GetParams() *HpkeParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("google.crypto.tink.HpkeKem", HpkeKem_name, HpkeKem_value)
+	proto.RegisterEnum("google.crypto.tink.HpkeKdf", HpkeKdf_name, HpkeKdf_value)
+	proto.RegisterEnum("google.crypto.tink.HpkeAead", HpkeAead_name, HpkeAead_value)
+	proto.RegisterType((*HpkeParams)(nil), "google.crypto.tink.HpkeParams")
+	proto.RegisterType((*HpkePublicKey)(nil), "google.crypto.tink.HpkePublicKey")
+	proto.RegisterType((*HpkePrivateKey)(nil), "google.crypto.tink.HpkePrivateKey")
+	proto.RegisterType((*HpkeKeyFormat)(nil), "google.crypto.tink.HpkeKeyFormat")
+}
+
+func init() {
+	proto.RegisterFile("proto/hpke.proto", fileDescriptor_1f0cddb96f8353e7)
+}
+
+var fileDescriptor_1f0cddb96f8353e7 = []byte{
+	// 460 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0x5b, 0x8f, 0xd2, 0x40,
+	0x14, 0xc7, 0x1d, 0x30, 0xac, 0x1e, 0xf6, 0x52, 0x27, 0x31, 0x69, 0x22, 0x5e, 0x96, 0x27, 0x43,
+	0x62, 0x81, 0x62, 0x09, 0xbe, 0xd9, 0xe5, 0xb2, 0x35, 0x15, 0x96, 0x74, 0x25, 0x5e, 0x5e, 0x26,
+	0x85, 0x0e, 0xd0, 0x74, 0xbb, 0x33, 0xe9, 0x76, 0x49, 0xfa, 0xe6, 0x17, 0xf0, 0x1b, 0xf8, 0xe4,
+	0x27, 0x35, 0x3d, 0xa5, 0xa6, 0xa8, 0x68, 0xf6, 0x6d, 0xce, 0xc9, 0xef, 0x7f, 0xfe, 0xff, 0x33,
+	0x93, 0x81, 0xd3, 0x78, 0xed, 0x47, 0x1e, 0x93, 0x6e, 0x14, 0x27, 0xcd, 0xd8, 0xbf, 0x0e, 0x9a,
+	0x32, 0x12, 0xb1, 0x68, 0xae, 0x65, 0xc0, 0x35, 0x3c, 0x52, 0xba, 0x12, 0x62, 0x75, 0xc5, 0xb5,
+	0x45, 0x94, 0xc8, 0x58, 0x68, 0x29, 0x54, 0xff, 0x4e, 0x00, 0x2c, 0x19, 0xf0, 0xa9, 0x1b, 0xb9,
+	0xe1, 0x0d, 0x7d, 0x05, 0xe5, 0x80, 0x87, 0x2a, 0x79, 0x41, 0x5e, 0x1e, 0xeb, 0x4f, 0xb4, 0x3f,
+	0x05, 0x5a, 0x0a, 0xdb, 0x3c, 0x74, 0x52, 0x0e, 0x71, 0x6f, 0xa9, 0x96, 0xfe, 0x83, 0x7b, 0x4b,
+	0x27, 0xe5, 0x68, 0x0b, 0xee, 0xbb, 0xdc, 0xf5, 0xd4, 0x32, 0xf2, 0xb5, 0x7d, 0xbc, 0xc9, 0x5d,
+	0xcf, 0x41, 0xb2, 0xfe, 0x95, 0xc0, 0x11, 0xc6, 0xbb, 0x9d, 0x5f, 0xf9, 0x0b, 0x9b, 0x27, 0x54,
+	0x85, 0x83, 0x0d, 0x8f, 0x6e, 0x7c, 0x71, 0x8d, 0x29, 0x8f, 0x9c, 0xbc, 0xa4, 0x5d, 0xa8, 0x48,
+	0xdc, 0x02, 0xf3, 0x54, 0xf5, 0x67, 0xfb, 0xe6, 0x67, 0xbb, 0x3a, 0x5b, 0x9a, 0x3e, 0x05, 0x90,
+	0x38, 0x9e, 0x05, 0x3c, 0xc1, 0x6c, 0x87, 0xce, 0x43, 0x99, 0x1b, 0xd6, 0xbf, 0x11, 0x38, 0x46,
+	0x55, 0xe4, 0x6f, 0xdc, 0x98, 0xff, 0x3b, 0xc3, 0xdb, 0x9d, 0x59, 0x59, 0x8e, 0xd3, 0xbd, 0x39,
+	0x72, 0x8f, 0x82, 0x1d, 0x7d, 0x0e, 0x55, 0x99, 0x39, 0x15, 0xe2, 0x80, 0xfc, 0x65, 0x5e, 0x3f,
+	0xcf, 0x6e, 0xc4, 0xe6, 0xc9, 0x48, 0x44, 0xa1, 0x1b, 0x17, 0xf6, 0x26, 0x77, 0xd9, 0xbb, 0xd1,
+	0x83, 0x83, 0xed, 0x63, 0xd2, 0x13, 0xa8, 0xda, 0xc3, 0x31, 0x9b, 0x4d, 0xec, 0xc9, 0xc5, 0xc7,
+	0x89, 0x72, 0x8f, 0xd6, 0x40, 0x1d, 0x58, 0x69, 0xeb, 0x93, 0x6e, 0x18, 0xed, 0x37, 0xcc, 0xb2,
+	0x07, 0x23, 0x76, 0x69, 0x99, 0xba, 0xd1, 0x55, 0x48, 0x63, 0xbc, 0x55, 0x7a, 0x4b, 0x54, 0x0e,
+	0x46, 0x05, 0xe5, 0x09, 0x54, 0x77, 0xe0, 0x62, 0xa3, 0xd3, 0x7b, 0xad, 0x94, 0x8a, 0x0d, 0xa3,
+	0xad, 0x2b, 0xe5, 0xc6, 0x0c, 0x1e, 0xe4, 0xcf, 0x4e, 0x15, 0x38, 0x34, 0x87, 0xe6, 0x60, 0x77,
+	0xa0, 0x39, 0xbc, 0x64, 0x6d, 0xbd, 0xc7, 0xce, 0xfb, 0x63, 0x85, 0xe4, 0x0d, 0xdd, 0xe8, 0x62,
+	0xa3, 0x44, 0x1f, 0xc3, 0xa3, 0xbe, 0x65, 0xf6, 0x2d, 0x53, 0x6f, 0xb1, 0xe9, 0xc5, 0xfb, 0xcf,
+	0xed, 0x4e, 0xcb, 0x50, 0xca, 0x67, 0x33, 0xa8, 0x2d, 0x44, 0xf8, 0xb7, 0xcb, 0xc0, 0xef, 0x30,
+	0x25, 0x5f, 0x1a, 0x2b, 0x3f, 0x5e, 0xdf, 0xce, 0xb5, 0x85, 0x08, 0x9b, 0x19, 0xf6, 0xfb, 0xcf,
+	0x61, 0x2b, 0xc1, 0xb0, 0xfa, 0x51, 0xaa, 0x7c, 0x78, 0x37, 0xb1, 0xa7, 0x67, 0xf3, 0x0a, 0xd6,
+	0x9d, 0x9f, 0x03, 0x00, 0x06, 0xec, 0x43, 0xc8, 0x71, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: third_party/tink/proto/prf_based_deriver.proto
+
+package prf_based_deriver_go_proto
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	tink_go_proto "github.com/google/tink/go/proto/tink_go_proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type PrfBasedDeriverParams struct {
+	DerivedKeyTemplate   *tink_go_proto.KeyTemplate `protobuf:"bytes,1,opt,name=derived_key_template,json=derivedKeyTemplate,proto3" json:"derived_key_template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *PrfBasedDeriverParams) Reset()         { *m = PrfBasedDeriverParams{} }
+func (m *PrfBasedDeriverParams) String() string { return proto.CompactTextString(m) }
+func (*PrfBasedDeriverParams) ProtoMessage()    {}
+func (*PrfBasedDeriverParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ade283b46af0cbf, []int{0}
+}
+
+func (m *PrfBasedDeriverParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrfBasedDeriverParams.Unmarshal(m, b)
+}
+func (m *PrfBasedDeriverParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrfBasedDeriverParams.Marshal(b, m, deterministic)
+}
+func (m *PrfBasedDeriverParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrfBasedDeriverParams.Merge(m, src)
+}
+func (m *PrfBasedDeriverParams) XXX_Size() int {
+	return xxx_messageInfo_PrfBasedDeriverParams.Size(m)
+}
+func (m *PrfBasedDeriverParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrfBasedDeriverParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrfBasedDeriverParams proto.InternalMessageInfo
+
+func (m *PrfBasedDeriverParams) GetDerivedKeyTemplate() *tink_go_proto.KeyTemplate {
+	if m != nil {
+		return m.DerivedKeyTemplate
+	}
+	return nil
+}
+
+type PrfBasedDeriverKeyFormat struct {
+	PrfKeyTemplate       *tink_go_proto.KeyTemplate `protobuf:"bytes,1,opt,name=prf_key_template,json=prfKeyTemplate,proto3" json:"prf_key_template,omitempty"`
+	Params               *PrfBasedDeriverParams     `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *PrfBasedDeriverKeyFormat) Reset()         { *m = PrfBasedDeriverKeyFormat{} }
+func (m *PrfBasedDeriverKeyFormat) String() string { return proto.CompactTextString(m) }
+func (*PrfBasedDeriverKeyFormat) ProtoMessage()    {}
+func (*PrfBasedDeriverKeyFormat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ade283b46af0cbf, []int{1}
+}
+
+func (m *PrfBasedDeriverKeyFormat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrfBasedDeriverKeyFormat.Unmarshal(m, b)
+}
+func (m *PrfBasedDeriverKeyFormat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrfBasedDeriverKeyFormat.Marshal(b, m, deterministic)
+}
+func (m *PrfBasedDeriverKeyFormat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrfBasedDeriverKeyFormat.Merge(m, src)
+}
+func (m *PrfBasedDeriverKeyFormat) XXX_Size() int {
+	return xxx_messageInfo_PrfBasedDeriverKeyFormat.Size(m)
+}
+func (m *PrfBasedDeriverKeyFormat) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrfBasedDeriverKeyFormat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrfBasedDeriverKeyFormat proto.InternalMessageInfo
+
+func (m *PrfBasedDeriverKeyFormat) GetPrfKeyTemplate() *tink_go_proto.KeyTemplate {
+	if m != nil {
+		return m.PrfKeyTemplate
+	}
+	return nil
+}
+
+func (m *PrfBasedDeriverKeyFormat) GetParams() *PrfBasedDeriverParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+// key_type: type.googleapis.com/google.crypto.tink.PrfBasedDeriverKey
+type PrfBasedDeriverKey struct {
+	Version              uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	PrfKey               *tink_go_proto.KeyData `protobuf:"bytes,2,opt,name=prf_key,json=prfKey,proto3" json:"prf_key,omitempty"`
+	Params               *PrfBasedDeriverParams `protobuf:"bytes,3,opt,name=params,proto3" json:"params,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *PrfBasedDeriverKey) Reset()         { *m = PrfBasedDeriverKey{} }
+func (m *PrfBasedDeriverKey) String() string { return proto.CompactTextString(m) }
+func (*PrfBasedDeriverKey) ProtoMessage()    {}
+func (*PrfBasedDeriverKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ade283b46af0cbf, []int{2}
+}
+
+func (m *PrfBasedDeriverKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrfBasedDeriverKey.Unmarshal(m, b)
+}
+func (m *PrfBasedDeriverKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrfBasedDeriverKey.Marshal(b, m, deterministic)
+}
+func (m *PrfBasedDeriverKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrfBasedDeriverKey.Merge(m, src)
+}
+func (m *PrfBasedDeriverKey) XXX_Size() int {
+	return xxx_messageInfo_PrfBasedDeriverKey.Size(m)
+}
+func (m *PrfBasedDeriverKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrfBasedDeriverKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrfBasedDeriverKey proto.InternalMessageInfo
+
+func (m *PrfBasedDeriverKey) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PrfBasedDeriverKey) GetPrfKey() *tink_go_proto.KeyData {
+	if m != nil {
+		return m.PrfKey
+	}
+	return nil
+}
+
+func (m *PrfBasedDeriverKey) GetParams() *PrfBasedDeriverParams {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PrfBasedDeriverParams)(nil), "google.crypto.tink.PrfBasedDeriverParams")
+	proto.RegisterType((*PrfBasedDeriverKeyFormat)(nil), "google.crypto.tink.PrfBasedDeriverKeyFormat")
+	proto.RegisterType((*PrfBasedDeriverKey)(nil), "google.crypto.tink.PrfBasedDeriverKey")
+}
+
+func init() {
+	proto.RegisterFile("proto/prf_based_deriver.proto", fileDescriptor_2ade283b46af0cbf)
+}
+
+var fileDescriptor_2ade283b46af0cbf = []byte{
+	// 301 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x4b, 0xc3, 0x30,
+	0x14, 0xc6, 0xe9, 0x84, 0x0e, 0x22, 0x8a, 0x04, 0x85, 0xa2, 0x82, 0xda, 0x93, 0x5e, 0x52, 0x50,
+	0xc1, 0xb3, 0x65, 0x08, 0xa3, 0x20, 0xb5, 0xec, 0xe4, 0x25, 0xa4, 0x6d, 0xda, 0xd5, 0xad, 0x4b,
+	0x78, 0x7d, 0x0e, 0xfa, 0xef, 0x78, 0xd1, 0x3f, 0x53, 0x9a, 0x56, 0x28, 0xae, 0xdb, 0x61, 0xb7,
+	0xbc, 0x97, 0xf7, 0x7d, 0xdf, 0xef, 0x91, 0x10, 0x86, 0xf3, 0x02, 0x52, 0xae, 0x05, 0x60, 0xed,
+	0x61, 0xb1, 0x5a, 0x78, 0x1a, 0x14, 0x2a, 0x4f, 0x43, 0xc6, 0x63, 0x51, 0xc9, 0x94, 0xa7, 0x12,
+	0x8a, 0xb5, 0x04, 0x66, 0xfa, 0x94, 0xe6, 0x4a, 0xe5, 0x4b, 0xc9, 0x12, 0xa8, 0x35, 0x2a, 0xd6,
+	0x28, 0xce, 0x6f, 0xb6, 0x78, 0x34, 0xc7, 0x56, 0xe6, 0x7e, 0x90, 0xb3, 0x10, 0x32, 0xbf, 0x31,
+	0x9c, 0xb4, 0x7e, 0xa1, 0x00, 0x51, 0x56, 0xf4, 0x8d, 0x9c, 0xb6, 0x01, 0x29, 0x5f, 0xc8, 0x9a,
+	0xa3, 0x2c, 0xf5, 0x52, 0xa0, 0x74, 0xac, 0x6b, 0xeb, 0xf6, 0xf0, 0xfe, 0x8a, 0x6d, 0xc6, 0xb1,
+	0x40, 0xd6, 0xb3, 0x6e, 0x2c, 0xa2, 0x9d, 0xb8, 0xd7, 0x73, 0x7f, 0x2c, 0xe2, 0xfc, 0x0b, 0x0b,
+	0x64, 0xfd, 0xa2, 0xa0, 0x14, 0x48, 0xa7, 0xe4, 0xa4, 0x59, 0x6d, 0x9f, 0xac, 0x63, 0x0d, 0x59,
+	0xaf, 0xa6, 0xcf, 0xc4, 0xd6, 0x66, 0x09, 0x67, 0x64, 0x0c, 0xee, 0x86, 0x0c, 0x06, 0xb7, 0x8e,
+	0x3a, 0xa1, 0xfb, 0x6d, 0x11, 0xba, 0x89, 0x4a, 0x1d, 0x32, 0x5e, 0x4b, 0xa8, 0x0a, 0xb5, 0x32,
+	0x6c, 0x47, 0xd1, 0x5f, 0x49, 0x1f, 0xc9, 0xb8, 0xc3, 0xef, 0x42, 0x2f, 0xb6, 0x50, 0x4f, 0x04,
+	0x8a, 0xc8, 0x6e, 0x89, 0x7b, 0xa4, 0x07, 0x7b, 0x92, 0xfa, 0x31, 0xb9, 0x4c, 0x54, 0x39, 0xa4,
+	0x33, 0x0f, 0x1c, 0x5a, 0xef, 0x4f, 0x79, 0x81, 0xf3, 0xcf, 0x98, 0x25, 0xaa, 0xf4, 0xda, 0xb1,
+	0x9d, 0xff, 0x89, 0xe7, 0x8a, 0x9b, 0xab, 0xaf, 0x91, 0x3d, 0x9b, 0xbe, 0x06, 0xa1, 0x1f, 0xdb,
+	0xa6, 0x7e, 0xf8, 0x1d, 0x00, 0x90, 0x02, 0x5c, 0xbc, 0x94, 0x02, 0x00, 0x00,
+}
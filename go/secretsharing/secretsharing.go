@@ -0,0 +1,239 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package secretsharing splits secrets, such as cleartext keysets or the keys
+// that encrypt them, into shares with Shamir's secret sharing over GF(2^8), so
+// that any threshold of the shares reconstructs the secret and fewer reveal
+// nothing about it. This enables offline root key ceremonies, in which the
+// shares are handed to different custodians.
+//
+// Shares of a keyset are as sensitive as the keyset itself while enough of
+// them are held together. Like insecurecleartextkeyset, this package handles
+// cleartext key material and its usage should be restricted and audited.
+package secretsharing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/subtle/random"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// A share consists of a header followed by one byte per byte of the secret:
+//
+//	version (1 byte) || split ID (4 bytes) || threshold (1 byte) || x (1 byte) || y
+//
+// The split ID is random and the same for all shares of one Split call, so
+// that shares of different splits are not combined by mistake.
+const (
+	shareVersion    = 1
+	shareHeaderSize = 7
+
+	// MaxShares is the maximal number of shares of a secret.
+	MaxShares = 255
+)
+
+// Split splits secret into n shares, any threshold of which reconstruct it
+// with Combine. It requires 2 <= threshold <= n <= MaxShares.
+func Split(secret []byte, n, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secretsharing: secret must not be empty")
+	}
+	if threshold < 2 || threshold > n || n > MaxShares {
+		return nil, fmt.Errorf("secretsharing: invalid threshold %d of %d shares", threshold, n)
+	}
+	id := random.GetRandomBytes(4)
+	shares := make([][]byte, n)
+	for i := range shares {
+		share := make([]byte, shareHeaderSize, shareHeaderSize+len(secret))
+		share[0] = shareVersion
+		copy(share[1:5], id)
+		share[5] = byte(threshold)
+		share[6] = byte(i + 1)
+		shares[i] = share
+	}
+	// Each byte of the secret is the constant term of a random polynomial of
+	// degree threshold-1, whose value at x is share x's byte.
+	coefficients := make([]byte, threshold)
+	for _, s := range secret {
+		coefficients[0] = s
+		copy(coefficients[1:], random.GetRandomBytes(uint32(threshold-1)))
+		for i := range shares {
+			x := byte(i + 1)
+			// Horner's method.
+			var y byte
+			for j := threshold - 1; j >= 0; j-- {
+				y = gfMul(y, x) ^ coefficients[j]
+			}
+			shares[i] = append(shares[i], y)
+		}
+	}
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+	return shares, nil
+}
+
+// Combine reconstructs the secret from shares created by Split. It needs at
+// least the threshold of shares from the same split; additional shares are
+// ignored.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("secretsharing: no shares")
+	}
+	first := shares[0]
+	if len(first) <= shareHeaderSize || first[0] != shareVersion {
+		return nil, errors.New("secretsharing: invalid share")
+	}
+	threshold := int(first[5])
+	if threshold < 2 {
+		return nil, fmt.Errorf("secretsharing: invalid threshold %d", threshold)
+	}
+	if len(shares) < threshold {
+		return nil, fmt.Errorf("secretsharing: need %d shares, got %d", threshold, len(shares))
+	}
+	xs := make([]byte, threshold)
+	seen := make(map[byte]bool)
+	for i, share := range shares[:threshold] {
+		if len(share) != len(first) || !bytes.Equal(share[:5], first[:5]) {
+			return nil, errors.New("secretsharing: shares are not from the same split")
+		}
+		if share[5] != first[5] {
+			return nil, errors.New("secretsharing: shares have different thresholds")
+		}
+		x := share[6]
+		if x == 0 || seen[x] {
+			return nil, errors.New("secretsharing: invalid or duplicate share")
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+	// Lagrange interpolation at 0. In GF(2^8), subtraction is XOR.
+	basis := make([]byte, threshold)
+	for i := range basis {
+		num, den := byte(1), byte(1)
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			num = gfMul(num, xs[j])
+			den = gfMul(den, xs[j]^xs[i])
+		}
+		basis[i] = gfMul(num, gfInv(den))
+	}
+	secret := make([]byte, len(first)-shareHeaderSize)
+	for k := range secret {
+		var s byte
+		for i, share := range shares[:threshold] {
+			s ^= gfMul(share[shareHeaderSize+k], basis[i])
+		}
+		secret[k] = s
+	}
+	return secret, nil
+}
+
+// ShareInfo describes a share without revealing anything about the secret.
+type ShareInfo struct {
+	// SplitID is the same for all shares created by one Split call.
+	SplitID uint32
+	// Threshold is the number of shares needed to reconstruct the secret.
+	Threshold int
+	// Index is the position of the share among the shares of its split,
+	// starting at 1.
+	Index int
+}
+
+// Info returns information about a share, e.g. to label it during a key
+// ceremony.
+func Info(share []byte) (*ShareInfo, error) {
+	if len(share) <= shareHeaderSize || share[0] != shareVersion {
+		return nil, errors.New("secretsharing: invalid share")
+	}
+	return &ShareInfo{
+		SplitID:   binary.BigEndian.Uint32(share[1:5]),
+		Threshold: int(share[5]),
+		Index:     int(share[6]),
+	}, nil
+}
+
+// SplitKeyset splits the cleartext keyset of h into n shares, any threshold
+// of which reconstruct it with CombineKeyset.
+func SplitKeyset(h *keyset.Handle, n, threshold int) ([][]byte, error) {
+	if h == nil {
+		return nil, errors.New("secretsharing: invalid handle")
+	}
+	serialized, err := proto.Marshal(insecurecleartextkeyset.KeysetMaterial(h))
+	if err != nil {
+		return nil, fmt.Errorf("secretsharing: cannot serialize keyset: %v", err)
+	}
+	defer zero(serialized)
+	return Split(serialized, n, threshold)
+}
+
+// CombineKeyset reconstructs a keyset handle from shares created by
+// SplitKeyset.
+func CombineKeyset(shares [][]byte) (*keyset.Handle, error) {
+	serialized, err := Combine(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(serialized)
+	ks := &tinkpb.Keyset{}
+	if err := proto.Unmarshal(serialized, ks); err != nil {
+		return nil, errors.New("secretsharing: shares don't reconstruct a keyset")
+	}
+	if err := keyset.Validate(ks); err != nil {
+		return nil, errors.New("secretsharing: shares don't reconstruct a valid keyset")
+	}
+	return insecurecleartextkeyset.KeysetHandle(ks), nil
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// gfMul multiplies in GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1,
+// in constant time.
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		carry := -(a >> 7)
+		a = a<<1 ^ 0x1b&carry
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a != 0 as a^254, in constant
+// time.
+func gfInv(a byte) byte {
+	// 254 = 0b11111110
+	r := a
+	for i := 0; i < 6; i++ {
+		r = gfMul(r, r)
+		r = gfMul(r, a)
+	}
+	return gfMul(r, r)
+}
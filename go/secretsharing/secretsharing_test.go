@@ -0,0 +1,178 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package secretsharing_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/secretsharing"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := random.GetRandomBytes(32)
+	shares, err := secretsharing.Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("len(shares) = %d, want 5", len(shares))
+	}
+	// Every subset of 3 shares, in any order, reconstructs the secret.
+	for i := range shares {
+		for j := range shares {
+			for k := range shares {
+				if i == j || j == k || i == k {
+					continue
+				}
+				got, err := secretsharing.Combine([][]byte{shares[i], shares[j], shares[k]})
+				if err != nil {
+					t.Fatalf("secretsharing.Combine(%d, %d, %d) failed: %v", i, j, k, err)
+				}
+				if !bytes.Equal(got, secret) {
+					t.Errorf("secretsharing.Combine(%d, %d, %d) = %x, want %x", i, j, k, got, secret)
+				}
+			}
+		}
+	}
+	// Additional shares are ignored.
+	if got, err := secretsharing.Combine(shares); err != nil || !bytes.Equal(got, secret) {
+		t.Errorf("secretsharing.Combine(all shares) = %x, %v, want %x, nil", got, err, secret)
+	}
+}
+
+func TestSplitMaxShares(t *testing.T) {
+	secret := []byte("secret")
+	shares, err := secretsharing.Split(secret, secretsharing.MaxShares, secretsharing.MaxShares)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	got, err := secretsharing.Combine(shares)
+	if err != nil || !bytes.Equal(got, secret) {
+		t.Errorf("secretsharing.Combine() = %q, %v, want %q, nil", got, err, secret)
+	}
+}
+
+func TestSplitInvalidParameters(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		secret       []byte
+		n, threshold int
+	}{
+		{"empty secret", nil, 3, 2},
+		{"threshold 1", []byte("secret"), 3, 1},
+		{"threshold above n", []byte("secret"), 3, 4},
+		{"too many shares", []byte("secret"), 256, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := secretsharing.Split(tc.secret, tc.n, tc.threshold); err == nil {
+				t.Error("secretsharing.Split() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestCombineInvalidShares(t *testing.T) {
+	shares, err := secretsharing.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	other, err := secretsharing.Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		shares [][]byte
+	}{
+		{"no shares", nil},
+		{"below threshold", shares[:1]},
+		{"duplicate share", [][]byte{shares[0], shares[0]}},
+		{"different splits", [][]byte{shares[0], other[1]}},
+		{"truncated share", [][]byte{shares[0], shares[1][:len(shares[1])-1]}},
+		{"invalid share", [][]byte{[]byte("invalid"), shares[1]}},
+		{"threshold 0", [][]byte{withThreshold(shares[0], 0), withThreshold(shares[1], 0)}},
+		{"threshold 1", [][]byte{withThreshold(shares[0], 1)}},
+		{"different thresholds", [][]byte{shares[0], withThreshold(shares[1], 3), shares[2]}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := secretsharing.Combine(tc.shares); err == nil {
+				t.Error("secretsharing.Combine() succeeded, want error")
+			}
+		})
+	}
+}
+
+// withThreshold returns a copy of share with the given threshold, as if it was
+// tampered with.
+func withThreshold(share []byte, threshold byte) []byte {
+	c := append([]byte{}, share...)
+	c[5] = threshold
+	return c
+}
+
+func TestInfo(t *testing.T) {
+	shares, err := secretsharing.Split([]byte("secret"), 4, 3)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	first, err := secretsharing.Info(shares[0])
+	if err != nil {
+		t.Fatalf("secretsharing.Info() failed: %v", err)
+	}
+	for i, share := range shares {
+		info, err := secretsharing.Info(share)
+		if err != nil {
+			t.Fatalf("secretsharing.Info() failed: %v", err)
+		}
+		if info.SplitID != first.SplitID || info.Threshold != 3 || info.Index != i+1 {
+			t.Errorf("secretsharing.Info(shares[%d]) = %+v, want split ID %d, threshold 3, index %d", i, info, first.SplitID, i+1)
+		}
+	}
+}
+
+func TestSplitCombineKeyset(t *testing.T) {
+	h, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	shares, err := secretsharing.SplitKeyset(h, 3, 2)
+	if err != nil {
+		t.Fatalf("secretsharing.SplitKeyset() failed: %v", err)
+	}
+	got, err := secretsharing.CombineKeyset(shares[1:])
+	if err != nil {
+		t.Fatalf("secretsharing.CombineKeyset() failed: %v", err)
+	}
+	if !proto.Equal(insecurecleartextkeyset.KeysetMaterial(got), insecurecleartextkeyset.KeysetMaterial(h)) {
+		t.Error("secretsharing.CombineKeyset() doesn't reconstruct the keyset")
+	}
+
+	// Shares of a secret that isn't a keyset are rejected.
+	notKeyset, err := secretsharing.Split([]byte{0xff, 0xff, 0xff}, 3, 2)
+	if err != nil {
+		t.Fatalf("secretsharing.Split() failed: %v", err)
+	}
+	if _, err := secretsharing.CombineKeyset(notKeyset); err == nil {
+		t.Error("secretsharing.CombineKeyset() of a non-keyset succeeded, want error")
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package dsse signs and verifies Dead Simple Signing Envelopes (DSSE) with
+// Tink signature keysets, as used by in-toto and other supply chain tools.
+//
+// An envelope binds a payload to its type: signatures are computed over the
+// pre-authentication encoding (PAE) of both, see
+// https://github.com/secure-systems-lab/dsse/blob/master/protocol.md.
+// Envelopes are serialized with encoding/json.
+//
+// Signatures of keys with a TINK or LEGACY output prefix carry the Tink
+// prefix. Use keys with output prefix RAW if envelopes must be verified by
+// implementations other than Tink.
+package dsse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/tink"
+)
+
+// Envelope is a DSSE envelope. Payload and the signatures are base64 encoded
+// by encoding/json.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     []byte      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	// KeyID is an unauthenticated hint of the key that created Sig. Envelopes
+	// created by this package use the decimal Tink key ID.
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+var errNoValidSignature = errors.New("dsse: no valid signature")
+
+// PAE returns the pre-authentication encoding of payloadType and payload,
+// which is the data that is actually signed.
+func PAE(payloadType string, payload []byte) []byte {
+	ret := []byte("DSSEv1 ")
+	ret = strconv.AppendInt(ret, int64(len(payloadType)), 10)
+	ret = append(ret, ' ')
+	ret = append(ret, payloadType...)
+	ret = append(ret, ' ')
+	ret = strconv.AppendInt(ret, int64(len(payload)), 10)
+	ret = append(ret, ' ')
+	return append(ret, payload...)
+}
+
+// Signer creates DSSE envelopes.
+type Signer struct {
+	signer signature.KeyInfoSigner
+}
+
+// NewSigner returns a Signer that signs with the primary key of the given
+// keyset handle.
+func NewSigner(h *keyset.Handle) (*Signer, error) {
+	s, err := signature.NewKeyInfoSigner(h)
+	if err != nil {
+		return nil, fmt.Errorf("dsse: %s", err)
+	}
+	return &Signer{signer: s}, nil
+}
+
+// Sign returns an envelope containing payload and a single signature.
+func (s *Signer) Sign(payloadType string, payload []byte) (*Envelope, error) {
+	sig, info, err := s.signer.SignWithInfo(PAE(payloadType, payload))
+	if err != nil {
+		return nil, fmt.Errorf("dsse: %s", err)
+	}
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     payload,
+		Signatures: []Signature{{
+			KeyID: strconv.FormatUint(uint64(info.KeyID), 10),
+			Sig:   sig,
+		}},
+	}, nil
+}
+
+// Verifier verifies DSSE envelopes.
+type Verifier struct {
+	verifier tink.Verifier
+}
+
+// NewVerifier returns a Verifier that accepts signatures of the keys in the
+// given keyset handle.
+func NewVerifier(h *keyset.Handle) (*Verifier, error) {
+	v, err := signature.NewVerifier(h)
+	if err != nil {
+		return nil, fmt.Errorf("dsse: %s", err)
+	}
+	return &Verifier{verifier: v}, nil
+}
+
+// Verify returns the payload of env if it has type payloadType and at least
+// one signature of env is valid. Key IDs in env are ignored, since they are
+// not authenticated.
+func (v *Verifier) Verify(env *Envelope, payloadType string) ([]byte, error) {
+	if env == nil {
+		return nil, errors.New("dsse: envelope must not be nil")
+	}
+	if env.PayloadType != payloadType {
+		return nil, fmt.Errorf("dsse: payload type is %q, want %q", env.PayloadType, payloadType)
+	}
+	pae := PAE(env.PayloadType, env.Payload)
+	for _, sig := range env.Signatures {
+		if err := v.verifier.Verify(sig.Sig, pae); err == nil {
+			return env.Payload, nil
+		}
+	}
+	return nil, errNoValidSignature
+}
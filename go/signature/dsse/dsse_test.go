@@ -0,0 +1,135 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package dsse_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/signature/dsse"
+)
+
+const payloadType = "application/vnd.in-toto+json"
+
+func TestPAE(t *testing.T) {
+	// Example from the DSSE protocol specification.
+	got := string(dsse.PAE("http://example.com/HelloWorld", []byte("hello world")))
+	want := "DSSEv1 29 http://example.com/HelloWorld 11 hello world"
+	if got != want {
+		t.Errorf("PAE() = %q, want %q", got, want)
+	}
+}
+
+func newSignerVerifier(t *testing.T) (*dsse.Signer, *dsse.Verifier, *keyset.Handle) {
+	t.Helper()
+	kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	s, err := dsse.NewSigner(kh)
+	if err != nil {
+		t.Fatalf("dsse.NewSigner() err = %v", err)
+	}
+	pub, err := kh.Public()
+	if err != nil {
+		t.Fatalf("kh.Public() err = %v", err)
+	}
+	v, err := dsse.NewVerifier(pub)
+	if err != nil {
+		t.Fatalf("dsse.NewVerifier() err = %v", err)
+	}
+	return s, v, kh
+}
+
+func TestSignVerify(t *testing.T) {
+	s, v, kh := newSignerVerifier(t)
+	payload := []byte(`{"_type": "https://in-toto.io/Statement/v0.1"}`)
+	env, err := s.Sign(payloadType, payload)
+	if err != nil {
+		t.Fatalf("s.Sign() err = %v", err)
+	}
+	if len(env.Signatures) != 1 {
+		t.Fatalf("len(env.Signatures) = %d, want 1", len(env.Signatures))
+	}
+	if got, want := env.Signatures[0].KeyID, strconv.FormatUint(uint64(kh.KeysetInfo().GetPrimaryKeyId()), 10); got != want {
+		t.Errorf("env.Signatures[0].KeyID = %q, want %q", got, want)
+	}
+
+	// Round trip through JSON, as envelopes are exchanged.
+	serialized, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("json.Marshal() err = %v", err)
+	}
+	parsed := new(dsse.Envelope)
+	if err := json.Unmarshal(serialized, parsed); err != nil {
+		t.Fatalf("json.Unmarshal() err = %v", err)
+	}
+	got, err := v.Verify(parsed, payloadType)
+	if err != nil {
+		t.Fatalf("v.Verify() err = %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Errorf("v.Verify() = %q, want %q", got, payload)
+	}
+}
+
+func TestVerifyFailures(t *testing.T) {
+	s, v, _ := newSignerVerifier(t)
+	otherSigner, _, _ := newSignerVerifier(t)
+	payload := []byte("payload")
+	env, err := s.Sign(payloadType, payload)
+	if err != nil {
+		t.Fatalf("s.Sign() err = %v", err)
+	}
+	otherEnv, err := otherSigner.Sign(payloadType, payload)
+	if err != nil {
+		t.Fatalf("otherSigner.Sign() err = %v", err)
+	}
+
+	modifiedPayload := *env
+	modifiedPayload.Payload = []byte("other payload")
+	// The signature binds the payload type, so changing it in the envelope
+	// and the expectation alike must fail as well.
+	modifiedType := *env
+	modifiedType.PayloadType = "text/plain"
+	for _, tc := range []struct {
+		name        string
+		env         *dsse.Envelope
+		payloadType string
+	}{
+		{"nil envelope", nil, payloadType},
+		{"wrong payload type", env, "text/plain"},
+		{"modified payload", &modifiedPayload, payloadType},
+		{"modified payload type", &modifiedType, "text/plain"},
+		{"no signatures", &dsse.Envelope{PayloadType: payloadType, Payload: payload}, payloadType},
+		{"other key", otherEnv, payloadType},
+	} {
+		if _, err := v.Verify(tc.env, tc.payloadType); err == nil {
+			t.Errorf("%s: v.Verify() err = nil, want error", tc.name)
+		}
+	}
+
+	// One valid signature among others is sufficient.
+	multi := *env
+	multi.Signatures = append(append([]dsse.Signature{}, otherEnv.Signatures...), env.Signatures...)
+	if _, err := v.Verify(&multi, payloadType); err != nil {
+		t.Errorf("v.Verify() with multiple signatures err = %v", err)
+	}
+}
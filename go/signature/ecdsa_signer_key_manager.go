@@ -25,7 +25,6 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
 	subtleSignature "github.com/google/tink/go/signature/subtle"
-	"github.com/google/tink/go/subtle"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
@@ -62,7 +61,11 @@ func (km *ecdsaSignerKeyManager) Primitive(serializedKey []byte) (interface{}, e
 		return nil, err
 	}
 	hash, curve, encoding := getECDSAParamNames(key.PublicKey.Params)
-	ret, err := subtleSignature.NewECDSASigner(hash, curve, encoding, key.KeyValue)
+	newSigner := subtleSignature.NewECDSASigner
+	if key.PublicKey.Params.DeterministicNonce {
+		newSigner = subtleSignature.NewDeterministicECDSASigner
+	}
+	ret, err := newSigner(hash, curve, encoding, key.KeyValue)
 	if err != nil {
 		return nil, fmt.Errorf("ecdsa_signer_key_manager: %s", err)
 	}
@@ -84,7 +87,7 @@ func (km *ecdsaSignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Messa
 	// generate key
 	params := keyFormat.Params
 	curve := commonpb.EllipticCurveType_name[int32(params.Curve)]
-	tmpKey, err := ecdsa.GenerateKey(subtle.GetCurve(curve), rand.Reader)
+	tmpKey, err := ecdsa.GenerateKey(subtleSignature.GetECDSACurve(curve), rand.Reader)
 	if err != nil {
 		return nil, fmt.Errorf("ecdsa_signer_key_manager: cannot generate ECDSA key: %s", err)
 	}
@@ -146,11 +149,21 @@ func (km *ecdsaSignerKeyManager) validateKey(key *ecdsapb.EcdsaPrivateKey) error
 		return fmt.Errorf("ecdsa_signer_key_manager: invalid key: %s", err)
 	}
 	hash, curve, encoding := getECDSAParamNames(key.PublicKey.Params)
-	return subtleSignature.ValidateECDSAParams(hash, curve, encoding)
+	return validateECDSASignerParams(hash, curve, encoding)
 }
 
 // validateKeyFormat validates the given ECDSAKeyFormat.
 func (km *ecdsaSignerKeyManager) validateKeyFormat(format *ecdsapb.EcdsaKeyFormat) error {
 	hash, curve, encoding := getECDSAParamNames(format.Params)
+	return validateECDSASignerParams(hash, curve, encoding)
+}
+
+// validateECDSASignerParams validates the parameters of a private key. Unlike
+// public keys, private keys on secp256k1 are rejected, since arithmetic on
+// that curve is not constant time.
+func validateECDSASignerParams(hash, curve, encoding string) error {
+	if curve == "SECP256K1" {
+		return errors.New("curve SECP256K1 is only supported for verification")
+	}
 	return subtleSignature.ValidateECDSAParams(hash, curve, encoding)
 }
@@ -17,12 +17,15 @@
 package signature_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
 	"github.com/google/tink/go/signature/subtle"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/google/tink/go/testutil"
@@ -279,6 +282,87 @@ func TestPublicKeyDataWithInvalidInput(t *testing.T) {
 
 var errSmallKey = fmt.Errorf("private key doesn't have adequate size")
 
+func TestECDSASignDeterministicNonce(t *testing.T) {
+	testCases := []struct {
+		name          string
+		template      *tinkpb.KeyTemplate
+		deterministic bool
+	}{
+		{"P256 deterministic", signature.ECDSAP256DeterministicKeyTemplate(), true},
+		{"P384 deterministic", signature.ECDSAP384DeterministicKeyTemplate(), true},
+		{"P521 deterministic", signature.ECDSAP521DeterministicKeyTemplate(), true},
+		{"P256 randomized", signature.ECDSAP256KeyTemplate(), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			signer, err := signature.NewSigner(kh)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v", err)
+			}
+			pub, err := kh.Public()
+			if err != nil {
+				t.Fatalf("kh.Public() err = %v", err)
+			}
+			verifier, err := signature.NewVerifier(pub)
+			if err != nil {
+				t.Fatalf("signature.NewVerifier() err = %v", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig1, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			sig2, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if got := bytes.Equal(sig1, sig2); got != tc.deterministic {
+				t.Errorf("signatures of the same data equal = %v, want %v", got, tc.deterministic)
+			}
+			if err := verifier.Verify(sig1, data); err != nil {
+				t.Errorf("verifier.Verify() err = %v", err)
+			}
+		})
+	}
+}
+
+func TestECDSASECP256K1IsVerifyOnly(t *testing.T) {
+	signature.EnableSECP256K1()
+	signerKM, err := registry.GetKeyManager(testutil.ECDSASignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ECDSASigner key manager: %s", err)
+	}
+	params := testutil.NewECDSAParams(commonpb.HashType_SHA256, commonpb.EllipticCurveType_SECP256K1,
+		ecdsapb.EcdsaSignatureEncoding_DER)
+	serializedFormat, _ := proto.Marshal(testutil.NewECDSAKeyFormat(params))
+	if _, err := signerKM.NewKey(serializedFormat); err == nil {
+		t.Errorf("signerKM.NewKey() with secp256k1 succeeded, want error")
+	}
+
+	// A secp256k1 public key from SEC 2, the generator of the curve.
+	c := subtle.SECP256K1()
+	pub := testutil.NewECDSAPublicKey(testutil.ECDSAVerifierKeyVersion, params,
+		c.Params().Gx.Bytes(), c.Params().Gy.Bytes())
+	priv := testutil.NewECDSAPrivateKey(testutil.ECDSASignerKeyVersion, pub, []byte{1})
+	serializedPriv, _ := proto.Marshal(priv)
+	if _, err := signerKM.Primitive(serializedPriv); err == nil {
+		t.Errorf("signerKM.Primitive() with secp256k1 succeeded, want error")
+	}
+
+	verifierKM, err := registry.GetKeyManager(testutil.ECDSAVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ECDSAVerifier key manager: %s", err)
+	}
+	serializedPub, _ := proto.Marshal(pub)
+	if _, err := verifierKM.Primitive(serializedPub); err != nil {
+		t.Errorf("verifierKM.Primitive() with secp256k1 err = %v", err)
+	}
+}
+
 func validateECDSAPrivateKey(key *ecdsapb.EcdsaPrivateKey, params *ecdsapb.EcdsaParams) error {
 	if key.Version != testutil.ECDSASignerKeyVersion {
 		return fmt.Errorf("incorrect private key's version: expect %d, got %d",
@@ -25,7 +25,6 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
-	"github.com/google/tink/go/signature/subtle"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
@@ -62,7 +61,7 @@ func (km *ed25519SignerKeyManager) Primitive(serializedKey []byte) (interface{},
 		return nil, err
 	}
 
-	ret, err := subtle.NewED25519Signer(key.KeyValue)
+	ret, err := newED25519Signer(key.PublicKey.GetParams(), key.KeyValue)
 	if err != nil {
 		return nil, fmt.Errorf("ed25519_signer_key_manager: %s", err)
 	}
@@ -70,7 +69,15 @@ func (km *ed25519SignerKeyManager) Primitive(serializedKey []byte) (interface{},
 }
 
 // NewKey creates a new ED25519PrivateKey according to specification the given serialized ED25519KeyFormat.
-func (km *ed25519SignerKeyManager) NewKey(serializedKey []byte) (proto.Message, error) {
+// An empty key format generates a pure Ed25519 key.
+func (km *ed25519SignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	keyFormat := new(ed25519pb.Ed25519KeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidED25519SignKeyFormat
+	}
+	if err := validateED25519Params(keyFormat.Params); err != nil {
+		return nil, fmt.Errorf("ed25519_signer_key_manager: invalid key format: %s", err)
+	}
 	public, private, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
 		return nil, fmt.Errorf("ed25519_signer_key_manager: cannot generate ED25519 key: %s", err)
@@ -79,6 +86,7 @@ func (km *ed25519SignerKeyManager) NewKey(serializedKey []byte) (proto.Message,
 	publicProto := &ed25519pb.Ed25519PublicKey{
 		Version:  ed25519SignerKeyVersion,
 		KeyValue: public,
+		Params:   keyFormat.Params,
 	}
 	privateProto := &ed25519pb.Ed25519PrivateKey{
 		Version:   ed25519SignerKeyVersion,
@@ -141,5 +149,8 @@ func (km *ed25519SignerKeyManager) validateKey(key *ed25519pb.Ed25519PrivateKey)
 	if len(key.KeyValue) != ed25519.SeedSize {
 		return fmt.Errorf("ed2219_signer_key_manager: invalid key length, got %d", len(key.KeyValue))
 	}
+	if err := validateED25519Params(key.PublicKey.GetParams()); err != nil {
+		return fmt.Errorf("ed25519_signer_key_manager: invalid key: %s", err)
+	}
 	return nil
 }
@@ -22,9 +22,12 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
 	"github.com/google/tink/go/signature/subtle"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/google/tink/go/testutil"
+	"github.com/google/tink/go/tink"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
@@ -94,7 +97,7 @@ func TestED25519SignNewKeyBasic(t *testing.T) {
 	if err != nil {
 		t.Errorf("cannot obtain ED25519Signer key manager: %s", err)
 	}
-	serializedFormat, _ := proto.Marshal(testutil.NewED25519PrivateKey())
+	serializedFormat, _ := proto.Marshal(&ed25519pb.Ed25519KeyFormat{})
 	tmp, err := km.NewKey(serializedFormat)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
@@ -105,6 +108,124 @@ func TestED25519SignNewKeyBasic(t *testing.T) {
 	}
 }
 
+func TestED25519SignVerifyVariants(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.ED25519SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ED25519Signer key manager: %s", err)
+	}
+	pkm := km.(registry.PrivateKeyManager)
+	kmPub, err := registry.GetKeyManager(testutil.ED25519VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ED25519Verifier key manager: %s", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		params *ed25519pb.Ed25519Params
+	}{
+		{"Ed25519", nil},
+		{"Ed25519ph", &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_PH}},
+		{"Ed25519ph with context", &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_PH, Context: []byte("firmware")}},
+		{"Ed25519ctx", &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_CTX, Context: []byte("firmware")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(&ed25519pb.Ed25519KeyFormat{Params: tc.params})
+			keyData, err := km.NewKeyData(serializedFormat)
+			if err != nil {
+				t.Fatalf("km.NewKeyData() err = %v", err)
+			}
+			pubKeyData, err := pkm.PublicKeyData(keyData.Value)
+			if err != nil {
+				t.Fatalf("pkm.PublicKeyData() err = %v", err)
+			}
+			pubKey := new(ed25519pb.Ed25519PublicKey)
+			if err := proto.Unmarshal(pubKeyData.Value, pubKey); err != nil {
+				t.Fatalf("proto.Unmarshal() err = %v", err)
+			}
+			if !proto.Equal(pubKey.Params, tc.params) {
+				t.Errorf("pubKey.Params = %v, want %v", pubKey.Params, tc.params)
+			}
+			s, err := km.Primitive(keyData.Value)
+			if err != nil {
+				t.Fatalf("km.Primitive() err = %v", err)
+			}
+			v, err := kmPub.Primitive(pubKeyData.Value)
+			if err != nil {
+				t.Fatalf("kmPub.Primitive() err = %v", err)
+			}
+			data := random.GetRandomBytes(1281)
+			sig, err := s.(tink.Signer).Sign(data)
+			if err != nil {
+				t.Fatalf("Sign() err = %v", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+				t.Errorf("Verify() err = %v", err)
+			}
+
+			// Signatures do not verify under the same key with other params.
+			otherKey := proto.Clone(pubKey).(*ed25519pb.Ed25519PublicKey)
+			otherKey.Params = &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_CTX, Context: []byte("other")}
+			serializedOtherKey, _ := proto.Marshal(otherKey)
+			otherV, err := kmPub.Primitive(serializedOtherKey)
+			if err != nil {
+				t.Fatalf("kmPub.Primitive() err = %v", err)
+			}
+			if err := otherV.(tink.Verifier).Verify(sig, data); err == nil {
+				t.Errorf("Verify() with other params succeeded")
+			}
+		})
+	}
+}
+
+func TestED25519NewKeyWithInvalidParams(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.ED25519SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ED25519Signer key manager: %s", err)
+	}
+	for _, tc := range []struct {
+		name   string
+		params *ed25519pb.Ed25519Params
+	}{
+		{"Ed25519 with context", &ed25519pb.Ed25519Params{Context: []byte("ctx")}},
+		{"Ed25519ctx without context", &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_CTX}},
+		{"Ed25519ph with long context", &ed25519pb.Ed25519Params{Variant: ed25519pb.Ed25519Variant_ED25519_PH, Context: make([]byte, 256)}},
+		{"unknown variant", &ed25519pb.Ed25519Params{Variant: 42}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(&ed25519pb.Ed25519KeyFormat{Params: tc.params})
+			if _, err := km.NewKey(serializedFormat); err == nil {
+				t.Errorf("km.NewKey() succeeded, want error")
+			}
+
+			// Keys with invalid params are rejected as well.
+			key := testutil.NewED25519PrivateKey()
+			key.PublicKey.Params = tc.params
+			serializedKey, _ := proto.Marshal(key)
+			if _, err := km.Primitive(serializedKey); err == nil {
+				t.Errorf("km.Primitive() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestFactoryWithED25519VariantTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"Ed25519ph", signature.ED25519PhKeyTemplate()},
+		{"Ed25519ctx", signature.ED25519CtxKeyTemplate([]byte("firmware"))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := testSignVerify(tc.template); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	if _, err := keyset.NewHandle(signature.ED25519CtxKeyTemplate(nil)); err == nil {
+		t.Errorf("keyset.NewHandle() with empty Ed25519ctx context succeeded, want error")
+	}
+}
+
 func TestED25519PublicKeyDataBasic(t *testing.T) {
 	km, err := registry.GetKeyManager(testutil.ED25519SignerTypeURL)
 	if err != nil {
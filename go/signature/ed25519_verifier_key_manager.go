@@ -22,7 +22,6 @@ import (
 	"golang.org/x/crypto/ed25519"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
-	"github.com/google/tink/go/signature/subtle"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
@@ -57,7 +56,7 @@ func (km *ed25519VerifierKeyManager) Primitive(serializedKey []byte) (interface{
 	if err := km.validateKey(key); err != nil {
 		return nil, fmt.Errorf("ed25519_verifier_key_manager: %s", err)
 	}
-	ret, err := subtle.NewED25519Verifier(key.KeyValue)
+	ret, err := newED25519Verifier(key.Params, key.KeyValue)
 	if err != nil {
 		return nil, fmt.Errorf("ed25519_verifier_key_manager: invalid key: %s", err)
 	}
@@ -93,5 +92,5 @@ func (km *ed25519VerifierKeyManager) validateKey(key *ed25519pb.Ed25519PublicKey
 	if len(key.KeyValue) != ed25519.PublicKeySize {
 		return fmt.Errorf("ed25519_verifier_key_manager: invalid key length, required :%d", ed25519.PublicKeySize)
 	}
-	return nil
+	return validateED25519Params(key.Params)
 }
@@ -0,0 +1,144 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	ed448pb "github.com/google/tink/go/proto/ed448_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	ed448SignerKeyVersion = 0
+	ed448SignerTypeURL    = "type.googleapis.com/google.crypto.tink.Ed448PrivateKey"
+)
+
+// common errors
+var errInvalidED448SignKey = errors.New("ed448_signer_key_manager: invalid key")
+var errInvalidED448SignKeyFormat = errors.New("ed448_signer_key_manager: invalid key format")
+
+// ed448SignerKeyManager is an implementation of KeyManager interface.
+// It generates new Ed448PrivateKeys and produces new instances of ED448Signer subtle.
+type ed448SignerKeyManager struct{}
+
+// newED448SignerKeyManager creates a new ed448SignerKeyManager.
+func newED448SignerKeyManager() *ed448SignerKeyManager {
+	return new(ed448SignerKeyManager)
+}
+
+// Primitive creates an ED448Signer subtle for the given serialized Ed448PrivateKey proto.
+func (km *ed448SignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidED448SignKey
+	}
+	key := new(ed448pb.Ed448PrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidED448SignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewED448Signer(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("ed448_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new Ed448PrivateKey according to specification the given serialized Ed448KeyFormat.
+func (km *ed448SignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	keyFormat := new(ed448pb.Ed448KeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidED448SignKeyFormat
+	}
+	seed, public, err := subtle.GenerateED448Key()
+	if err != nil {
+		return nil, fmt.Errorf("ed448_signer_key_manager: cannot generate Ed448 key: %s", err)
+	}
+	return &ed448pb.Ed448PrivateKey{
+		Version: ed448SignerKeyVersion,
+		PublicKey: &ed448pb.Ed448PublicKey{
+			Version:  ed448SignerKeyVersion,
+			KeyValue: public,
+		},
+		KeyValue: seed,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized Ed448KeyFormat. It should be used solely by the key management API.
+func (km *ed448SignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidED448SignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         ed448SignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *ed448SignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ed448pb.Ed448PrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidED448SignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidED448SignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidED448SignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         ed448VerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *ed448SignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == ed448SignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *ed448SignerKeyManager) TypeURL() string {
+	return ed448SignerTypeURL
+}
+
+// validateKey validates the given Ed448PrivateKey.
+func (km *ed448SignerKeyManager) validateKey(key *ed448pb.Ed448PrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, ed448SignerKeyVersion); err != nil {
+		return fmt.Errorf("ed448_signer_key_manager: invalid key: %s", err)
+	}
+	if len(key.KeyValue) != subtle.ED448SeedSize {
+		return fmt.Errorf("ed448_signer_key_manager: invalid key length, got %d", len(key.KeyValue))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+	ed448pb "github.com/google/tink/go/proto/ed448_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	ed448SignerTypeURL   = "type.googleapis.com/google.crypto.tink.Ed448PrivateKey"
+	ed448VerifierTypeURL = "type.googleapis.com/google.crypto.tink.Ed448PublicKey"
+)
+
+func TestED448SignVerify(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(ed448SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain Ed448 signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(ed448VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain Ed448 verifier key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(&ed448pb.Ed448KeyFormat{})
+	m, err := signerKM.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	serializedKey, _ := proto.Marshal(m)
+	p, err := signerKM.Primitive(serializedKey)
+	if err != nil {
+		t.Fatalf("signerKM.Primitive() failed: %s", err)
+	}
+	pubKeyData, err := signerKM.(registry.PrivateKeyManager).PublicKeyData(serializedKey)
+	if err != nil {
+		t.Fatalf("PublicKeyData() failed: %s", err)
+	}
+	if pubKeyData.TypeUrl != ed448VerifierTypeURL {
+		t.Errorf("pubKeyData.TypeUrl = %q, want %q", pubKeyData.TypeUrl, ed448VerifierTypeURL)
+	}
+	v, err := verifierKM.Primitive(pubKeyData.Value)
+	if err != nil {
+		t.Fatalf("verifierKM.Primitive() failed: %s", err)
+	}
+	data := random.GetRandomBytes(20)
+	sig, err := p.(tink.Signer).Sign(data)
+	if err != nil {
+		t.Fatalf("Sign() failed: %s", err)
+	}
+	if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+		t.Errorf("Verify() failed: %s", err)
+	}
+	if err := v.(tink.Verifier).Verify(sig, append(data, 0)); err == nil {
+		t.Errorf("Verify() succeeded with modified data")
+	}
+}
+
+func TestED448WithInvalidInput(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(ed448SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain Ed448 signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(ed448VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain Ed448 verifier key manager: %s", err)
+	}
+	if _, err := signerKM.NewKey([]byte{0xff}); err == nil {
+		t.Errorf("signerKM.NewKey() with invalid format succeeded, want error")
+	}
+
+	m, err := signerKM.NewKey(nil)
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	validKey := m.(*ed448pb.Ed448PrivateKey)
+	badVersion := proto.Clone(validKey).(*ed448pb.Ed448PrivateKey)
+	badVersion.Version = 1
+	badSeed := proto.Clone(validKey).(*ed448pb.Ed448PrivateKey)
+	badSeed.KeyValue = badSeed.KeyValue[1:]
+	for _, tc := range []struct {
+		name string
+		key  *ed448pb.Ed448PrivateKey
+	}{
+		{"bad version", badVersion},
+		{"bad seed", badSeed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := signerKM.Primitive(serializedKey); err == nil {
+				t.Errorf("signerKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+
+	badPublicVersion := proto.Clone(validKey.PublicKey).(*ed448pb.Ed448PublicKey)
+	badPublicVersion.Version = 1
+	badPublicKey := proto.Clone(validKey.PublicKey).(*ed448pb.Ed448PublicKey)
+	badPublicKey.KeyValue = badPublicKey.KeyValue[1:]
+	for _, tc := range []struct {
+		name string
+		key  *ed448pb.Ed448PublicKey
+	}{
+		{"bad version", badPublicVersion},
+		{"bad key", badPublicKey},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := verifierKM.Primitive(serializedKey); err == nil {
+				t.Errorf("verifierKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+	if _, err := verifierKM.Primitive(nil); err == nil {
+		t.Errorf("verifierKM.Primitive(nil) succeeded, want error")
+	}
+}
+
+func TestFactoryWithED448KeyTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"Ed448", signature.ED448KeyTemplate()},
+		{"Ed448 without prefix", signature.ED448KeyWithoutPrefixTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := testSignVerify(tc.template); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	ed448pb "github.com/google/tink/go/proto/ed448_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	ed448VerifierKeyVersion = 0
+	ed448VerifierTypeURL    = "type.googleapis.com/google.crypto.tink.Ed448PublicKey"
+)
+
+// common errors
+var errInvalidED448VerifierKey = fmt.Errorf("ed448_verifier_key_manager: invalid key")
+var errED448VerifierNotImplemented = fmt.Errorf("ed448_verifier_key_manager: not implemented")
+
+// ed448VerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type ed448VerifierKeyManager struct{}
+
+// newED448VerifierKeyManager creates a new ed448VerifierKeyManager.
+func newED448VerifierKeyManager() *ed448VerifierKeyManager {
+	return new(ed448VerifierKeyManager)
+}
+
+// Primitive creates an ED448Verifier subtle for the given serialized Ed448PublicKey proto.
+func (km *ed448VerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidED448VerifierKey
+	}
+	key := new(ed448pb.Ed448PublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidED448VerifierKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("ed448_verifier_key_manager: %s", err)
+	}
+	ret, err := subtle.NewED448Verifier(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("ed448_verifier_key_manager: invalid key: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *ed448VerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errED448VerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *ed448VerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errED448VerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *ed448VerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == ed448VerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *ed448VerifierKeyManager) TypeURL() string {
+	return ed448VerifierTypeURL
+}
+
+// validateKey validates the given Ed448PublicKey.
+func (km *ed448VerifierKeyManager) validateKey(key *ed448pb.Ed448PublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, ed448VerifierKeyVersion); err != nil {
+		return err
+	}
+	if len(key.KeyValue) != subtle.ED448PublicKeySize {
+		return fmt.Errorf("invalid key length, got %d", len(key.KeyValue))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package insecureimport provides functions to import externally generated
+// private signing keys into keysets, e.g. to migrate existing keys onto Tink.
+//
+// Importing keys is insecure in the sense that Tink cannot vouch for how the
+// key material was generated or how it has been handled so far. Prefer
+// generating new keys with keyset.NewHandle and rotating to them whenever
+// possible.
+package insecureimport
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	// Registers the signature key managers, which are used to validate
+	// imported keys.
+	_ "github.com/google/tink/go/signature"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	ecdsaSignerKeyVersion   = 0
+	ecdsaSignerTypeURL      = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
+	ed25519SignerKeyVersion = 0
+	ed25519SignerTypeURL    = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+)
+
+var (
+	errInvalidPEM    = errors.New("insecureimport: invalid PEM block")
+	errInvalidPrefix = errors.New("insecureimport: invalid output prefix type")
+)
+
+// ImportECDSAPrivateKeyPEM returns a keyset handle containing the ECDSA
+// private key encoded in pemBytes as its only and primary key, with the given
+// key ID and output prefix type. Both SEC 1 ("EC PRIVATE KEY") and PKCS #8
+// ("PRIVATE KEY") encodings are accepted, for keys on the NIST curves P-256,
+// P-384 and P-521. Signatures are DER encoded and computed over hashType
+// digests of the data.
+func ImportECDSAPrivateKeyPEM(pemBytes []byte, hashType commonpb.HashType, keyID uint32, prefixType tinkpb.OutputPrefixType) (*keyset.Handle, error) {
+	block, rest := pem.Decode(pemBytes)
+	if block == nil || len(rest) != 0 {
+		return nil, errInvalidPEM
+	}
+	var priv *ecdsa.PrivateKey
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		k, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("insecureimport: %s", err)
+		}
+		priv = k
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("insecureimport: %s", err)
+		}
+		ecdsaKey, ok := k.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("insecureimport: not an ECDSA private key: %T", k)
+		}
+		priv = ecdsaKey
+	default:
+		return nil, fmt.Errorf("insecureimport: unsupported PEM block type %q", block.Type)
+	}
+	var curve commonpb.EllipticCurveType
+	switch priv.Curve.Params().Name {
+	case "P-256":
+		curve = commonpb.EllipticCurveType_NIST_P256
+	case "P-384":
+		curve = commonpb.EllipticCurveType_NIST_P384
+	case "P-521":
+		curve = commonpb.EllipticCurveType_NIST_P521
+	default:
+		return nil, fmt.Errorf("insecureimport: unsupported curve %s", priv.Curve.Params().Name)
+	}
+	key := &ecdsapb.EcdsaPrivateKey{
+		Version: ecdsaSignerKeyVersion,
+		PublicKey: &ecdsapb.EcdsaPublicKey{
+			Version: ecdsaSignerKeyVersion,
+			Params: &ecdsapb.EcdsaParams{
+				HashType: hashType,
+				Curve:    curve,
+				Encoding: ecdsapb.EcdsaSignatureEncoding_DER,
+			},
+			X: priv.X.Bytes(),
+			Y: priv.Y.Bytes(),
+		},
+		KeyValue: priv.D.Bytes(),
+	}
+	return newHandle(ecdsaSignerTypeURL, key, keyID, prefixType)
+}
+
+// ImportED25519Seed returns a keyset handle containing the ED25519 private
+// key derived from the 32-byte seed as its only and primary key, with the
+// given key ID and output prefix type. The seed is the private key format of
+// RFC 8032, also used by e.g. OpenSSH and the Go standard library.
+func ImportED25519Seed(seed []byte, keyID uint32, prefixType tinkpb.OutputPrefixType) (*keyset.Handle, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("insecureimport: invalid seed length %d, want %d", len(seed), ed25519.SeedSize)
+	}
+	pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	key := &ed25519pb.Ed25519PrivateKey{
+		Version: ed25519SignerKeyVersion,
+		PublicKey: &ed25519pb.Ed25519PublicKey{
+			Version:  ed25519SignerKeyVersion,
+			KeyValue: pub,
+		},
+		KeyValue: append([]byte{}, seed...),
+	}
+	return newHandle(ed25519SignerTypeURL, key, keyID, prefixType)
+}
+
+// newHandle returns a keyset handle which contains key as its only and
+// primary key. The key is validated by the registered key manager first.
+func newHandle(typeURL string, key proto.Message, keyID uint32, prefixType tinkpb.OutputPrefixType) (*keyset.Handle, error) {
+	if prefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
+		return nil, errInvalidPrefix
+	}
+	if _, ok := tinkpb.OutputPrefixType_name[int32(prefixType)]; !ok {
+		return nil, errInvalidPrefix
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("insecureimport: %s", err)
+	}
+	keyData := &tinkpb.KeyData{
+		TypeUrl:         typeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}
+	if _, err := registry.PrimitiveFromKeyData(keyData); err != nil {
+		return nil, fmt.Errorf("insecureimport: invalid key: %s", err)
+	}
+	ks := &tinkpb.Keyset{
+		PrimaryKeyId: keyID,
+		Key: []*tinkpb.Keyset_Key{{
+			KeyData:          keyData,
+			Status:           tinkpb.KeyStatusType_ENABLED,
+			KeyId:            keyID,
+			OutputPrefixType: prefixType,
+		}},
+	}
+	return insecurecleartextkeyset.KeysetHandle(ks), nil
+}
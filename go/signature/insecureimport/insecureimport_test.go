@@ -0,0 +1,186 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package insecureimport_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/signature/insecureimport"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestImportECDSAPrivateKeyPEM(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	sec1, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey() err = %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey() err = %v", err)
+	}
+	data := []byte("data to be signed")
+	digest := sha256.Sum256(data)
+	for _, block := range []*pem.Block{
+		{Type: "EC PRIVATE KEY", Bytes: sec1},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		t.Run(block.Type, func(t *testing.T) {
+			h, err := insecureimport.ImportECDSAPrivateKeyPEM(pem.EncodeToMemory(block), commonpb.HashType_SHA256, 42, tinkpb.OutputPrefixType_RAW)
+			if err != nil {
+				t.Fatalf("insecureimport.ImportECDSAPrivateKeyPEM() err = %v", err)
+			}
+			if got := h.KeysetInfo().GetPrimaryKeyId(); got != 42 {
+				t.Errorf("primary key ID = %d, want 42", got)
+			}
+			signer, err := signature.NewSigner(h)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v", err)
+			}
+			sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if !ecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig) {
+				t.Error("signature does not verify with the original public key")
+			}
+		})
+	}
+}
+
+func TestImportED25519Seed(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		t.Fatalf("rand.Read() err = %v", err)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+	data := []byte("data to be signed")
+
+	h, err := insecureimport.ImportED25519Seed(seed, 7, tinkpb.OutputPrefixType_RAW)
+	if err != nil {
+		t.Fatalf("insecureimport.ImportED25519Seed() err = %v", err)
+	}
+	signer, err := signature.NewSigner(h)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v", err)
+	}
+	sig, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	// ED25519 signatures are deterministic.
+	if want := ed25519.Sign(priv, data); !bytes.Equal(sig, want) {
+		t.Errorf("signer.Sign() = %x, want %x", sig, want)
+	}
+}
+
+func TestImportedKeyWithTinkPrefix(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	h, err := insecureimport.ImportED25519Seed(seed, 0x01020304, tinkpb.OutputPrefixType_TINK)
+	if err != nil {
+		t.Fatalf("insecureimport.ImportED25519Seed() err = %v", err)
+	}
+	signer, err := signature.NewSigner(h)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v", err)
+	}
+	sig, err := signer.Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	if want := []byte{1, 1, 2, 3, 4}; !bytes.HasPrefix(sig, want) {
+		t.Errorf("signature prefix = %x, want %x", sig[:len(want)], want)
+	}
+	pub, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() err = %v", err)
+	}
+	verifier, err := signature.NewVerifier(pub)
+	if err != nil {
+		t.Fatalf("signature.NewVerifier() err = %v", err)
+	}
+	if err := verifier.Verify(sig, []byte("data")); err != nil {
+		t.Errorf("verifier.Verify() err = %v", err)
+	}
+}
+
+func TestImportFailures(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+	}
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey() err = %v", err)
+	}
+	ecPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1})
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() err = %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalPKCS8PrivateKey() err = %v", err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+
+	for _, tc := range []struct {
+		name      string
+		importKey func() (*keyset.Handle, error)
+	}{
+		{"not PEM", func() (*keyset.Handle, error) {
+			return insecureimport.ImportECDSAPrivateKeyPEM(sec1, commonpb.HashType_SHA256, 1, tinkpb.OutputPrefixType_TINK)
+		}},
+		{"trailing data", func() (*keyset.Handle, error) {
+			return insecureimport.ImportECDSAPrivateKeyPEM(append(ecPEM, ecPEM...), commonpb.HashType_SHA256, 1, tinkpb.OutputPrefixType_TINK)
+		}},
+		{"RSA key", func() (*keyset.Handle, error) {
+			return insecureimport.ImportECDSAPrivateKeyPEM(rsaPEM, commonpb.HashType_SHA256, 1, tinkpb.OutputPrefixType_TINK)
+		}},
+		{"invalid hash", func() (*keyset.Handle, error) {
+			return insecureimport.ImportECDSAPrivateKeyPEM(ecPEM, commonpb.HashType_SHA1, 1, tinkpb.OutputPrefixType_TINK)
+		}},
+		{"unknown prefix", func() (*keyset.Handle, error) {
+			return insecureimport.ImportECDSAPrivateKeyPEM(ecPEM, commonpb.HashType_SHA256, 1, tinkpb.OutputPrefixType_UNKNOWN_PREFIX)
+		}},
+		{"short seed", func() (*keyset.Handle, error) {
+			return insecureimport.ImportED25519Seed(make([]byte, 31), 1, tinkpb.OutputPrefixType_TINK)
+		}},
+		{"invalid prefix", func() (*keyset.Handle, error) {
+			return insecureimport.ImportED25519Seed(make([]byte, 32), 1, tinkpb.OutputPrefixType(42))
+		}},
+	} {
+		if _, err := tc.importKey(); err == nil {
+			t.Errorf("%s: import err = nil, want error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// KeyRingOption configures a KeyRingVerifier.
+type KeyRingOption func(*KeyRingVerifier)
+
+// WithKeyRingMonitor makes the KeyRingVerifier call monitor after every
+// verification, with the label of the keyset which verified the signature, or
+// with "" and the error if none did. It can be used to count verifications
+// per upstream source.
+func WithKeyRingMonitor(monitor func(label string, err error)) KeyRingOption {
+	return func(v *KeyRingVerifier) {
+		v.monitor = monitor
+	}
+}
+
+// KeyRingVerifier is a Verifier which verifies signatures with several
+// public keysets, for example those of the upstream services whose signatures
+// a service accepts. Each keyset has a label, which identifies it in errors
+// and monitoring.
+type KeyRingVerifier struct {
+	labels    []string
+	verifiers []tink.Verifier
+	monitor   func(label string, err error)
+}
+
+var _ tink.Verifier = (*KeyRingVerifier)(nil)
+
+// NewKeyRingVerifier returns a KeyRingVerifier for the public keysets in
+// handles, keyed by their labels. Keysets are tried in the order of their
+// labels. It returns an error if a keyset contains private keys, so that
+// signing keys are not distributed by accident.
+func NewKeyRingVerifier(handles map[string]*keyset.Handle, opts ...KeyRingOption) (*KeyRingVerifier, error) {
+	if len(handles) == 0 {
+		return nil, fmt.Errorf("verifier_factory: key ring has no keysets")
+	}
+	v := new(KeyRingVerifier)
+	for label := range handles {
+		v.labels = append(v.labels, label)
+	}
+	sort.Strings(v.labels)
+	for _, label := range v.labels {
+		h := handles[label]
+		if h == nil {
+			return nil, fmt.Errorf("verifier_factory: key ring keyset %q is nil", label)
+		}
+		for _, k := range keyset.Inspect(h).Keys {
+			if k.KeyMaterialType != tinkpb.KeyData_ASYMMETRIC_PUBLIC && k.KeyMaterialType != tinkpb.KeyData_REMOTE {
+				return nil, fmt.Errorf("verifier_factory: key ring keyset %q contains key %d which is not a public key", label, k.KeyID)
+			}
+		}
+		verifier, err := NewVerifier(h)
+		if err != nil {
+			return nil, fmt.Errorf("verifier_factory: key ring keyset %q: %s", label, err)
+		}
+		v.verifiers = append(v.verifiers, verifier)
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// Verify checks whether signature is a valid signature of data by a key of
+// any of the keysets. If it is not, the returned error wraps
+// ErrInvalidSignature.
+func (v *KeyRingVerifier) Verify(signature, data []byte) error {
+	_, err := v.VerifyWithLabel(signature, data)
+	return err
+}
+
+// VerifyWithLabel is like Verify, and also returns the label of the keyset
+// which verified the signature.
+func (v *KeyRingVerifier) VerifyWithLabel(signature, data []byte) (string, error) {
+	label, err := v.verify(signature, data)
+	if v.monitor != nil {
+		v.monitor(label, err)
+	}
+	return label, err
+}
+
+func (v *KeyRingVerifier) verify(signature, data []byte) (string, error) {
+	for i, verifier := range v.verifiers {
+		if err := verifier.Verify(signature, data); err == nil {
+			return v.labels[i], nil
+		}
+	}
+	return "", fmt.Errorf("%w by any of the keysets %s", ErrInvalidSignature, strings.Join(v.labels, ", "))
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/tink"
+)
+
+func newKeyRingSigner(t *testing.T) (tink.Signer, *keyset.Handle) {
+	t.Helper()
+	h, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	signer, err := signature.NewSigner(h)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() failed: %v", err)
+	}
+	pub, err := h.Public()
+	if err != nil {
+		t.Fatalf("h.Public() failed: %v", err)
+	}
+	return signer, pub
+}
+
+func TestKeyRingVerifier(t *testing.T) {
+	billing, billingPub := newKeyRingSigner(t)
+	payments, paymentsPub := newKeyRingSigner(t)
+	other, _ := newKeyRingSigner(t)
+
+	type result struct {
+		label string
+		err   error
+	}
+	var monitored []result
+	v, err := signature.NewKeyRingVerifier(map[string]*keyset.Handle{
+		"billing":  billingPub,
+		"payments": paymentsPub,
+	}, signature.WithKeyRingMonitor(func(label string, err error) {
+		monitored = append(monitored, result{label, err})
+	}))
+	if err != nil {
+		t.Fatalf("signature.NewKeyRingVerifier() failed: %v", err)
+	}
+	data := []byte("data")
+	for _, tc := range []struct {
+		signer tink.Signer
+		label  string
+	}{
+		{billing, "billing"},
+		{payments, "payments"},
+	} {
+		sig, err := tc.signer.Sign(data)
+		if err != nil {
+			t.Fatalf("Sign() failed: %v", err)
+		}
+		if err := v.Verify(sig, data); err != nil {
+			t.Errorf("v.Verify() of a %s signature failed: %v", tc.label, err)
+		}
+		label, err := v.VerifyWithLabel(sig, data)
+		if err != nil || label != tc.label {
+			t.Errorf("v.VerifyWithLabel() = %q, %v, want %q, nil", label, err, tc.label)
+		}
+	}
+
+	sig, err := other.Sign(data)
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	err = v.Verify(sig, data)
+	if !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("v.Verify() of an unknown signature = %v, want ErrInvalidSignature", err)
+	}
+	if len(monitored) != 5 || monitored[0].label != "billing" || monitored[3].label != "payments" || monitored[4].label != "" || monitored[4].err == nil {
+		t.Errorf("monitor was called with %v", monitored)
+	}
+}
+
+func TestNewKeyRingVerifierFailures(t *testing.T) {
+	_, pub := newKeyRingSigner(t)
+	priv, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name    string
+		handles map[string]*keyset.Handle
+	}{
+		{"no keysets", nil},
+		{"nil keyset", map[string]*keyset.Handle{"a": pub, "b": nil}},
+		{"private keys", map[string]*keyset.Handle{"a": pub, "b": priv}},
+	} {
+		if _, err := signature.NewKeyRingVerifier(tc.handles); err == nil {
+			t.Errorf("%s: signature.NewKeyRingVerifier() succeeded, want error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	mldsapb "github.com/google/tink/go/proto/ml_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	mlDSASignerKeyVersion = 0
+	mlDSASignerTypeURL    = "type.googleapis.com/google.crypto.tink.MlDsaPrivateKey"
+)
+
+// common errors
+var errInvalidMLDSASignKey = errors.New("ml_dsa_signer_key_manager: invalid key")
+var errInvalidMLDSASignKeyFormat = errors.New("ml_dsa_signer_key_manager: invalid key format")
+
+// mlDSASignerKeyManager is an implementation of KeyManager interface.
+// It generates new MlDsaPrivateKeys and produces new instances of MLDSASigner subtle.
+type mlDSASignerKeyManager struct{}
+
+// newMLDSASignerKeyManager creates a new mlDSASignerKeyManager.
+func newMLDSASignerKeyManager() *mlDSASignerKeyManager {
+	return new(mlDSASignerKeyManager)
+}
+
+// Primitive creates an MLDSASigner subtle for the given serialized MlDsaPrivateKey proto.
+func (km *mlDSASignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidMLDSASignKey
+	}
+	key := new(mldsapb.MlDsaPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidMLDSASignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	instance := mldsapb.MlDsaInstance_name[int32(key.PublicKey.Params.MlDsaInstance)]
+	ret, err := subtle.NewMLDSASigner(instance, key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new MlDsaPrivateKey according to specification the given serialized MlDsaKeyFormat.
+func (km *mlDSASignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidMLDSASignKeyFormat
+	}
+	keyFormat := new(mldsapb.MlDsaKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer_key_manager: invalid proto: %s", err)
+	}
+	if err := validateMLDSAParams(keyFormat.Params); err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer_key_manager: invalid key format: %s", err)
+	}
+	instance := mldsapb.MlDsaInstance_name[int32(keyFormat.Params.MlDsaInstance)]
+	seed, public, err := subtle.GenerateMLDSAKey(instance)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer_key_manager: cannot generate ML-DSA key: %s", err)
+	}
+	return &mldsapb.MlDsaPrivateKey{
+		Version: mlDSASignerKeyVersion,
+		PublicKey: &mldsapb.MlDsaPublicKey{
+			Version:  mlDSASignerKeyVersion,
+			Params:   keyFormat.Params,
+			KeyValue: public,
+		},
+		KeyValue: seed,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized MlDsaKeyFormat. It should be used solely by the key management API.
+func (km *mlDSASignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidMLDSASignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         mlDSASignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *mlDSASignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(mldsapb.MlDsaPrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidMLDSASignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidMLDSASignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidMLDSASignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         mlDSAVerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *mlDSASignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == mlDSASignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *mlDSASignerKeyManager) TypeURL() string {
+	return mlDSASignerTypeURL
+}
+
+// validateKey validates the given MlDsaPrivateKey.
+func (km *mlDSASignerKeyManager) validateKey(key *mldsapb.MlDsaPrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, mlDSASignerKeyVersion); err != nil {
+		return fmt.Errorf("ml_dsa_signer_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil {
+		return errInvalidMLDSASignKey
+	}
+	if err := validateMLDSAParams(key.PublicKey.Params); err != nil {
+		return fmt.Errorf("ml_dsa_signer_key_manager: invalid key: %s", err)
+	}
+	if len(key.KeyValue) != subtle.MLDSASeedSize {
+		return fmt.Errorf("ml_dsa_signer_key_manager: invalid key length, got %d", len(key.KeyValue))
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+//go:build go1.27
+// +build go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	"github.com/google/tink/go/tink"
+	mldsapb "github.com/google/tink/go/proto/ml_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	mlDSASignerTypeURL   = "type.googleapis.com/google.crypto.tink.MlDsaPrivateKey"
+	mlDSAVerifierTypeURL = "type.googleapis.com/google.crypto.tink.MlDsaPublicKey"
+)
+
+func newMLDSAKeyFormat(instance mldsapb.MlDsaInstance) []byte {
+	serializedFormat, _ := proto.Marshal(&mldsapb.MlDsaKeyFormat{
+		Params: &mldsapb.MlDsaParams{MlDsaInstance: instance},
+	})
+	return serializedFormat
+}
+
+func TestMLDSASignVerify(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(mlDSASignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ML-DSA signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(mlDSAVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ML-DSA verifier key manager: %s", err)
+	}
+	for _, instance := range []mldsapb.MlDsaInstance{
+		mldsapb.MlDsaInstance_ML_DSA_44,
+		mldsapb.MlDsaInstance_ML_DSA_65,
+		mldsapb.MlDsaInstance_ML_DSA_87,
+	} {
+		t.Run(instance.String(), func(t *testing.T) {
+			m, err := signerKM.NewKey(newMLDSAKeyFormat(instance))
+			if err != nil {
+				t.Fatalf("signerKM.NewKey() failed: %s", err)
+			}
+			key := m.(*mldsapb.MlDsaPrivateKey)
+			if got := key.PublicKey.Params.MlDsaInstance; got != instance {
+				t.Errorf("key.PublicKey.Params.MlDsaInstance = %s, want %s", got, instance)
+			}
+			serializedKey, _ := proto.Marshal(key)
+			p, err := signerKM.Primitive(serializedKey)
+			if err != nil {
+				t.Fatalf("signerKM.Primitive() failed: %s", err)
+			}
+			pubKeyData, err := signerKM.(registry.PrivateKeyManager).PublicKeyData(serializedKey)
+			if err != nil {
+				t.Fatalf("PublicKeyData() failed: %s", err)
+			}
+			if pubKeyData.TypeUrl != mlDSAVerifierTypeURL {
+				t.Errorf("pubKeyData.TypeUrl = %q, want %q", pubKeyData.TypeUrl, mlDSAVerifierTypeURL)
+			}
+			v, err := verifierKM.Primitive(pubKeyData.Value)
+			if err != nil {
+				t.Fatalf("verifierKM.Primitive() failed: %s", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := p.(tink.Signer).Sign(data)
+			if err != nil {
+				t.Fatalf("Sign() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+				t.Errorf("Verify() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("Verify() succeeded with modified data")
+			}
+		})
+	}
+}
+
+func TestMLDSAWithInvalidInput(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(mlDSASignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ML-DSA signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(mlDSAVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ML-DSA verifier key manager: %s", err)
+	}
+	if _, err := signerKM.NewKey(newMLDSAKeyFormat(mldsapb.MlDsaInstance_ML_DSA_UNKNOWN_INSTANCE)); err == nil {
+		t.Errorf("signerKM.NewKey() with unknown instance succeeded, want error")
+	}
+	if _, err := signerKM.NewKey(nil); err == nil {
+		t.Errorf("signerKM.NewKey(nil) succeeded, want error")
+	}
+
+	m, err := signerKM.NewKey(newMLDSAKeyFormat(mldsapb.MlDsaInstance_ML_DSA_65))
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	validKey := m.(*mldsapb.MlDsaPrivateKey)
+	badVersion := proto.Clone(validKey).(*mldsapb.MlDsaPrivateKey)
+	badVersion.Version = 1
+	badSeed := proto.Clone(validKey).(*mldsapb.MlDsaPrivateKey)
+	badSeed.KeyValue = badSeed.KeyValue[1:]
+	badInstance := proto.Clone(validKey).(*mldsapb.MlDsaPrivateKey)
+	badInstance.PublicKey.Params.MlDsaInstance = mldsapb.MlDsaInstance_ML_DSA_UNKNOWN_INSTANCE
+	for _, tc := range []struct {
+		name string
+		key  *mldsapb.MlDsaPrivateKey
+	}{
+		{"bad version", badVersion},
+		{"bad seed", badSeed},
+		{"bad instance", badInstance},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := signerKM.Primitive(serializedKey); err == nil {
+				t.Errorf("signerKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+
+	wrongInstance := proto.Clone(validKey.PublicKey).(*mldsapb.MlDsaPublicKey)
+	wrongInstance.Params.MlDsaInstance = mldsapb.MlDsaInstance_ML_DSA_87
+	badPublicVersion := proto.Clone(validKey.PublicKey).(*mldsapb.MlDsaPublicKey)
+	badPublicVersion.Version = 1
+	for _, tc := range []struct {
+		name string
+		key  *mldsapb.MlDsaPublicKey
+	}{
+		{"bad version", badPublicVersion},
+		{"wrong instance", wrongInstance},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := verifierKM.Primitive(serializedKey); err == nil {
+				t.Errorf("verifierKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+	if _, err := verifierKM.Primitive(nil); err == nil {
+		t.Errorf("verifierKM.Primitive(nil) succeeded, want error")
+	}
+}
+
+func TestFactoryWithMLDSAKeyTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"ML-DSA-44", signature.MLDSA44KeyTemplate()},
+		{"ML-DSA-65", signature.MLDSA65KeyTemplate()},
+		{"ML-DSA-87 without prefix", signature.MLDSA87KeyWithoutPrefixTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := testSignVerify(tc.template); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMLDSAKeyTemplates(t *testing.T) {
+	testutil.SkipTestIfTestSrcDirIsNotSet(t)
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		raw      bool
+	}{
+		{name: "ML_DSA_44", template: signature.MLDSA44KeyTemplate()},
+		{name: "ML_DSA_65", template: signature.MLDSA65KeyTemplate()},
+		{name: "ML_DSA_87", template: signature.MLDSA87KeyTemplate()},
+		{name: "ML_DSA_44", template: signature.MLDSA44KeyWithoutPrefixTemplate(), raw: true},
+		{name: "ML_DSA_65", template: signature.MLDSA65KeyWithoutPrefixTemplate(), raw: true},
+		{name: "ML_DSA_87", template: signature.MLDSA87KeyWithoutPrefixTemplate(), raw: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := testutil.KeyTemplateProto("signature", tc.name)
+			if err != nil {
+				t.Fatalf("testutil.KeyTemplateProto('signature', tc.name) failed: %s", err)
+			}
+			if tc.raw {
+				want.OutputPrefixType = tinkpb.OutputPrefixType_RAW
+			}
+			if !proto.Equal(want, tc.template) {
+				t.Errorf("template %s is not equal to '%s'", tc.name, tc.template)
+			}
+			h, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() failed: %v", err)
+			}
+			got, err := h.TemplateFor(h.KeysetInfo().GetPrimaryKeyId())
+			if err != nil {
+				t.Fatalf("h.TemplateFor() failed: %v", err)
+			}
+			if !proto.Equal(got, tc.template) {
+				t.Errorf("h.TemplateFor() = %v, want %v", got, tc.template)
+			}
+		})
+	}
+}
+
+func TestExportPublicKeyDERWithMLDSA(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.MLDSA65KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	if _, err := signature.ExportPublicKeyDER(kh, kh.KeysetInfo().GetPrimaryKeyId()); err == nil {
+		t.Error("signature.ExportPublicKeyDER() with ML-DSA keyset err = nil, want error")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !go1.27
+// +build !go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+)
+
+func TestMLDSAUnsupported(t *testing.T) {
+	for _, typeURL := range []string{
+		"type.googleapis.com/google.crypto.tink.MlDsaPrivateKey",
+		"type.googleapis.com/google.crypto.tink.MlDsaPublicKey",
+	} {
+		if _, err := registry.GetKeyManager(typeURL); err == nil {
+			t.Errorf("registry.GetKeyManager(%q) succeeded, want error", typeURL)
+		}
+	}
+	if _, err := keyset.NewHandle(signature.MLDSA65KeyTemplate()); err == nil {
+		t.Error("keyset.NewHandle() with an ML-DSA template succeeded, want error")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	mldsapb "github.com/google/tink/go/proto/ml_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	mlDSAVerifierKeyVersion = 0
+	mlDSAVerifierTypeURL    = "type.googleapis.com/google.crypto.tink.MlDsaPublicKey"
+)
+
+// common errors
+var errInvalidMLDSAVerifierKey = fmt.Errorf("ml_dsa_verifier_key_manager: invalid key")
+var errMLDSAVerifierNotImplemented = fmt.Errorf("ml_dsa_verifier_key_manager: not implemented")
+
+// mlDSAVerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type mlDSAVerifierKeyManager struct{}
+
+// newMLDSAVerifierKeyManager creates a new mlDSAVerifierKeyManager.
+func newMLDSAVerifierKeyManager() *mlDSAVerifierKeyManager {
+	return new(mlDSAVerifierKeyManager)
+}
+
+// Primitive creates an MLDSAVerifier subtle for the given serialized MlDsaPublicKey proto.
+func (km *mlDSAVerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidMLDSAVerifierKey
+	}
+	key := new(mldsapb.MlDsaPublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidMLDSAVerifierKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("ml_dsa_verifier_key_manager: %s", err)
+	}
+	instance := mldsapb.MlDsaInstance_name[int32(key.Params.MlDsaInstance)]
+	ret, err := subtle.NewMLDSAVerifier(instance, key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_verifier_key_manager: invalid key: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *mlDSAVerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errMLDSAVerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *mlDSAVerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errMLDSAVerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *mlDSAVerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == mlDSAVerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *mlDSAVerifierKeyManager) TypeURL() string {
+	return mlDSAVerifierTypeURL
+}
+
+// validateKey validates the given MlDsaPublicKey.
+func (km *mlDSAVerifierKeyManager) validateKey(key *mldsapb.MlDsaPublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, mlDSAVerifierKeyVersion); err != nil {
+		return err
+	}
+	return validateMLDSAParams(key.Params)
+}
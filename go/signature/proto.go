@@ -17,8 +17,19 @@
 package signature
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/google/tink/go/signature/subtle"
+	"github.com/google/tink/go/tink"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	mldsapb "github.com/google/tink/go/proto/ml_dsa_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+	slhdsapb "github.com/google/tink/go/proto/slh_dsa_go_proto"
 )
 
 // getECDSAParamNames returns the string representations of each parameter in
@@ -52,3 +63,150 @@ func newECDSAPublicKey(version uint32,
 		Y:       y,
 	}
 }
+
+// validateRSAHashType checks that the given hash type can be used with RSA
+// signatures.
+func validateRSAHashType(hashType commonpb.HashType) error {
+	switch hashType {
+	case commonpb.HashType_SHA256, commonpb.HashType_SHA384, commonpb.HashType_SHA512:
+		return nil
+	default:
+		return fmt.Errorf("unsupported hash type: %s", hashType)
+	}
+}
+
+// validateRSASSAPSSParams checks that the given RsaSsaPssParams are supported.
+// crypto/rsa uses the signature hash for MGF1, so both hashes must match.
+func validateRSASSAPSSParams(params *rsassapsspb.RsaSsaPssParams) error {
+	if params == nil {
+		return errors.New("missing params")
+	}
+	if err := validateRSAHashType(params.SigHash); err != nil {
+		return err
+	}
+	if params.SigHash != params.Mgf1Hash {
+		return errors.New("signature hash and MGF1 hash must be the same")
+	}
+	if params.SaltLength <= 0 {
+		return errors.New("salt length must be positive")
+	}
+	return nil
+}
+
+// rsaPublicExponent decodes the given big-endian public exponent.
+func rsaPublicExponent(e []byte) (int, error) {
+	v := new(big.Int).SetBytes(e)
+	if !v.IsInt64() || v.Int64() > math.MaxInt32 {
+		return 0, errors.New("public exponent too large")
+	}
+	return int(v.Int64()), nil
+}
+
+// newRSAPublicKeyData creates a RSAPublicKeyData from the big-endian encoded
+// modulus and public exponent.
+func newRSAPublicKeyData(n, e []byte) (*subtle.RSAPublicKeyData, error) {
+	exponent, err := rsaPublicExponent(e)
+	if err != nil {
+		return nil, err
+	}
+	ret := &subtle.RSAPublicKeyData{
+		N: new(big.Int).SetBytes(n),
+		E: exponent,
+	}
+	if err := ret.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// newRSAPrivateKeyData creates a RSAPrivateKeyData from the big-endian
+// encoded private key components.
+func newRSAPrivateKeyData(pub *subtle.RSAPublicKeyData, d, p, q, dp, dq, crt []byte) *subtle.RSAPrivateKeyData {
+	return &subtle.RSAPrivateKeyData{
+		D:             new(big.Int).SetBytes(d),
+		P:             new(big.Int).SetBytes(p),
+		Q:             new(big.Int).SetBytes(q),
+		Dp:            new(big.Int).SetBytes(dp),
+		Dq:            new(big.Int).SetBytes(dq),
+		Qinv:          new(big.Int).SetBytes(crt),
+		PublicKeyData: pub,
+	}
+}
+
+// validateMLDSAParams checks that the given MlDsaParams name a supported
+// parameter set.
+func validateMLDSAParams(params *mldsapb.MlDsaParams) error {
+	if params == nil {
+		return errors.New("missing params")
+	}
+	switch params.MlDsaInstance {
+	case mldsapb.MlDsaInstance_ML_DSA_44, mldsapb.MlDsaInstance_ML_DSA_65, mldsapb.MlDsaInstance_ML_DSA_87:
+		return nil
+	default:
+		return fmt.Errorf("unsupported ML-DSA instance: %s", params.MlDsaInstance)
+	}
+}
+
+// validateED25519Params validates Ed25519 params. Missing params denote pure
+// Ed25519.
+func validateED25519Params(params *ed25519pb.Ed25519Params) error {
+	if params == nil {
+		return nil
+	}
+	if len(params.Context) > subtle.MaxED25519ContextSize {
+		return fmt.Errorf("context is longer than %d bytes", subtle.MaxED25519ContextSize)
+	}
+	switch params.Variant {
+	case ed25519pb.Ed25519Variant_ED25519:
+		if len(params.Context) != 0 {
+			return errors.New("pure Ed25519 does not support a context")
+		}
+	case ed25519pb.Ed25519Variant_ED25519_CTX:
+		if len(params.Context) == 0 {
+			return errors.New("Ed25519ctx requires a non-empty context")
+		}
+	case ed25519pb.Ed25519Variant_ED25519_PH:
+	default:
+		return fmt.Errorf("unsupported Ed25519 variant: %s", params.Variant)
+	}
+	return nil
+}
+
+// newED25519Signer returns the Signer for the Ed25519 variant given by params.
+func newED25519Signer(params *ed25519pb.Ed25519Params, seed []byte) (tink.Signer, error) {
+	switch params.GetVariant() {
+	case ed25519pb.Ed25519Variant_ED25519_PH:
+		return subtle.NewED25519PhSigner(seed, params.Context)
+	case ed25519pb.Ed25519Variant_ED25519_CTX:
+		return subtle.NewED25519CtxSigner(seed, params.Context)
+	default:
+		return subtle.NewED25519Signer(seed)
+	}
+}
+
+// newED25519Verifier returns the Verifier for the Ed25519 variant given by
+// params.
+func newED25519Verifier(params *ed25519pb.Ed25519Params, publicKey []byte) (tink.Verifier, error) {
+	switch params.GetVariant() {
+	case ed25519pb.Ed25519Variant_ED25519_PH:
+		return subtle.NewED25519PhVerifier(publicKey, params.Context)
+	case ed25519pb.Ed25519Variant_ED25519_CTX:
+		return subtle.NewED25519CtxVerifier(publicKey, params.Context)
+	default:
+		return subtle.NewED25519Verifier(publicKey)
+	}
+}
+
+// validateSLHDSAParams validates SLH-DSA params.
+func validateSLHDSAParams(params *slhdsapb.SlhDsaParams) error {
+	if params == nil {
+		return errors.New("missing params")
+	}
+	switch params.SlhDsaInstance {
+	case slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128S, slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128S,
+		slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128F, slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128F:
+		return nil
+	default:
+		return fmt.Errorf("unsupported SLH-DSA instance: %s", params.SlhDsaInstance)
+	}
+}
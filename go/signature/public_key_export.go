@@ -0,0 +1,162 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	rsassapkcs1pb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// ExportPublicKeyDER returns the public key with the given ID in h as a DER
+// encoded X.509 SubjectPublicKeyInfo, so that it can be consumed by verifiers
+// outside of Tink. h may contain either public or private keys. Only ECDSA on
+// NIST curves, ED25519 and RSA public keys are supported.
+//
+// Note that the encoding carries the key only: parameters such as the hash
+// function, the signature encoding or the output prefix of the key are not
+// part of it. In particular RSA-SSA-PSS keys are exported as plain RSA keys,
+// and signatures of keys whose output prefix is not RAW cannot be verified by
+// other libraries without stripping the prefix first.
+func ExportPublicKeyDER(h *keyset.Handle, keyID uint32) ([]byte, error) {
+	key, err := exportPublicKey(h, keyID)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("signature: cannot export key %d: %s", keyID, err)
+	}
+	return der, nil
+}
+
+// ExportPublicKeyPEM is like ExportPublicKeyDER, but returns the key as a PEM
+// block of type "PUBLIC KEY".
+func ExportPublicKeyPEM(h *keyset.Handle, keyID uint32) ([]byte, error) {
+	der, err := ExportPublicKeyDER(h, keyID)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// exportPublicKey returns the public key with the given ID in h as a
+// crypto.PublicKey of the standard library.
+func exportPublicKey(h *keyset.Handle, keyID uint32) (interface{}, error) {
+	if h == nil {
+		return nil, fmt.Errorf("signature: keyset handle must not be nil")
+	}
+	ks, err := publicKeyset(h)
+	if err != nil {
+		return nil, fmt.Errorf("signature: cannot obtain public keyset: %s", err)
+	}
+	var keyData *tinkpb.KeyData
+	for _, k := range ks.Key {
+		if k.GetKeyId() == keyID {
+			keyData = k.GetKeyData()
+			break
+		}
+	}
+	if keyData == nil {
+		return nil, fmt.Errorf("signature: key %d not found", keyID)
+	}
+	switch keyData.TypeUrl {
+	case ecdsaVerifierTypeURL:
+		key := new(ecdsapb.EcdsaPublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errInvalidECDSAVerifierKey
+		}
+		if err := newECDSAVerifierKeyManager().validateKey(key); err != nil {
+			return nil, err
+		}
+		_, curve, _ := getECDSAParamNames(key.Params)
+		return &ecdsa.PublicKey{
+			Curve: subtle.GetECDSACurve(curve),
+			X:     new(big.Int).SetBytes(key.X),
+			Y:     new(big.Int).SetBytes(key.Y),
+		}, nil
+	case ed25519VerifierTypeURL:
+		key := new(ed25519pb.Ed25519PublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errInvalidED25519VerifierKey
+		}
+		if err := newED25519VerifierKeyManager().validateKey(key); err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(key.KeyValue), nil
+	case rsaSSAPKCS1VerifierTypeURL:
+		key := new(rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errInvalidRSASSAPKCS1VerifierKey
+		}
+		if err := newRSASSAPKCS1VerifierKeyManager().validateKey(key); err != nil {
+			return nil, err
+		}
+		return newRSAPublicKey(key.N, key.E)
+	case rsaSSAPSSVerifierTypeURL:
+		key := new(rsassapsspb.RsaSsaPssPublicKey)
+		if err := proto.Unmarshal(keyData.Value, key); err != nil {
+			return nil, errInvalidRSASSAPSSVerifierKey
+		}
+		if err := newRSASSAPSSVerifierKeyManager().validateKey(key); err != nil {
+			return nil, err
+		}
+		return newRSAPublicKey(key.N, key.E)
+	default:
+		return nil, fmt.Errorf("signature: cannot export key %d of type %s", keyID, keyData.TypeUrl)
+	}
+}
+
+// publicKeyset returns the keyset of h, converted to public keys if h
+// contains private keys.
+func publicKeyset(h *keyset.Handle) (*tinkpb.Keyset, error) {
+	w := &keyset.MemReaderWriter{}
+	if err := h.WriteWithNoSecrets(w); err == nil {
+		return w.Keyset, nil
+	}
+	pub, err := h.Public()
+	if err != nil {
+		return nil, err
+	}
+	if err := pub.WriteWithNoSecrets(w); err != nil {
+		return nil, err
+	}
+	return w.Keyset, nil
+}
+
+// newRSAPublicKey creates a rsa.PublicKey from the big-endian encoded modulus
+// and public exponent.
+func newRSAPublicKey(n, e []byte) (*rsa.PublicKey, error) {
+	pub, err := newRSAPublicKeyData(n, e)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{N: pub.N, E: pub.E}, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestExportPublicKeyPEM(t *testing.T) {
+	data := []byte("data to be signed")
+	digest := sha256.Sum256(data)
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+		verify   func(pub interface{}, sig []byte) bool
+	}{
+		{
+			name:     "ECDSA_P256",
+			template: signature.ECDSAP256KeyWithoutPrefixTemplate(),
+			verify: func(pub interface{}, sig []byte) bool {
+				k, ok := pub.(*ecdsa.PublicKey)
+				return ok && ecdsa.VerifyASN1(k, digest[:], sig)
+			},
+		},
+		{
+			name:     "ED25519",
+			template: signature.ED25519KeyWithoutPrefixTemplate(),
+			verify: func(pub interface{}, sig []byte) bool {
+				k, ok := pub.(ed25519.PublicKey)
+				return ok && ed25519.Verify(k, data, sig)
+			},
+		},
+		{
+			name:     "RSA_SSA_PKCS1",
+			template: signature.RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate(),
+			verify: func(pub interface{}, sig []byte) bool {
+				k, ok := pub.(*rsa.PublicKey)
+				return ok && rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+			},
+		},
+		{
+			name:     "RSA_SSA_PSS",
+			template: signature.RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate(),
+			verify: func(pub interface{}, sig []byte) bool {
+				k, ok := pub.(*rsa.PublicKey)
+				opts := &rsa.PSSOptions{SaltLength: 32, Hash: crypto.SHA256}
+				return ok && rsa.VerifyPSS(k, crypto.SHA256, digest[:], sig, opts) == nil
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kh, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle() err = %v", err)
+			}
+			keyID := kh.KeysetInfo().GetPrimaryKeyId()
+			signer, err := signature.NewSigner(kh)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v", err)
+			}
+			sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			pubKH, err := kh.Public()
+			if err != nil {
+				t.Fatalf("kh.Public() err = %v", err)
+			}
+			// Both the private and the public handle export the same key.
+			for _, h := range []*keyset.Handle{kh, pubKH} {
+				pemBytes, err := signature.ExportPublicKeyPEM(h, keyID)
+				if err != nil {
+					t.Fatalf("signature.ExportPublicKeyPEM() err = %v", err)
+				}
+				block, rest := pem.Decode(pemBytes)
+				if block == nil || len(rest) != 0 || block.Type != "PUBLIC KEY" {
+					t.Fatalf("pem.Decode() = %v, %q, want a single PUBLIC KEY block", block, rest)
+				}
+				pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+				if err != nil {
+					t.Fatalf("x509.ParsePKIXPublicKey() err = %v", err)
+				}
+				if !tc.verify(pub, sig) {
+					t.Errorf("signature does not verify with the exported key")
+				}
+			}
+		})
+	}
+}
+
+func TestExportPublicKeyDERFailures(t *testing.T) {
+	kh, err := keyset.NewHandle(signature.ECDSAP256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	keyID := kh.KeysetInfo().GetPrimaryKeyId()
+	if _, err := signature.ExportPublicKeyDER(kh, keyID+1); err == nil {
+		t.Error("signature.ExportPublicKeyDER() with unknown key ID err = nil, want error")
+	}
+	if _, err := signature.ExportPublicKeyDER(nil, keyID); err == nil {
+		t.Error("signature.ExportPublicKeyDER() with nil handle err = nil, want error")
+	}
+
+	macKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	if _, err := signature.ExportPublicKeyDER(macKH, macKH.KeysetInfo().GetPrimaryKeyId()); err == nil {
+		t.Error("signature.ExportPublicKeyDER() with MAC keyset err = nil, want error")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsassapkcs1pb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	rsaSSAPKCS1SignerKeyVersion = 0
+	rsaSSAPKCS1SignerTypeURL    = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey"
+)
+
+// common errors
+var errInvalidRSASSAPKCS1SignKey = errors.New("rsa_ssa_pkcs1_signer_key_manager: invalid key")
+var errInvalidRSASSAPKCS1SignKeyFormat = errors.New("rsa_ssa_pkcs1_signer_key_manager: invalid key format")
+
+// rsaSSAPKCS1SignerKeyManager is an implementation of KeyManager interface.
+// It generates new RsaSsaPkcs1PrivateKeys and produces new instances of
+// RSASSAPKCS1Signer subtle.
+type rsaSSAPKCS1SignerKeyManager struct{}
+
+// newRSASSAPKCS1SignerKeyManager creates a new rsaSSAPKCS1SignerKeyManager.
+func newRSASSAPKCS1SignerKeyManager() *rsaSSAPKCS1SignerKeyManager {
+	return new(rsaSSAPKCS1SignerKeyManager)
+}
+
+// Primitive creates an RSASSAPKCS1Signer subtle for the given serialized
+// RsaSsaPkcs1PrivateKey proto.
+func (km *rsaSSAPKCS1SignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidRSASSAPKCS1SignKey
+	}
+	key := new(rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidRSASSAPKCS1SignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	pub, err := newRSAPublicKeyData(key.PublicKey.N, key.PublicKey.E)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key: %s", err)
+	}
+	privKey, err := newRSAPrivateKeyData(pub, key.D, key.P, key.Q, key.Dp, key.Dq, key.Crt).CreateKey()
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key: %s", err)
+	}
+	hash := commonpb.HashType_name[int32(key.PublicKey.Params.HashType)]
+	ret, err := subtle.NewRSASSAPKCS1Signer(hash, privKey)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new RsaSsaPkcs1PrivateKey according to specification the
+// given serialized RsaSsaPkcs1KeyFormat.
+func (km *rsaSSAPKCS1SignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidRSASSAPKCS1SignKeyFormat
+	}
+	keyFormat := new(rsassapkcs1pb.RsaSsaPkcs1KeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid proto: %s", err)
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key format: %s", err)
+	}
+	e, err := rsaPublicExponent(keyFormat.PublicExponent)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key format: %s", err)
+	}
+	privKey, err := subtle.GenerateRSAKey(int(keyFormat.ModulusSizeInBits), e)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: cannot generate RSA key: %s", err)
+	}
+	return &rsassapkcs1pb.RsaSsaPkcs1PrivateKey{
+		Version: rsaSSAPKCS1SignerKeyVersion,
+		PublicKey: &rsassapkcs1pb.RsaSsaPkcs1PublicKey{
+			Version: rsaSSAPKCS1SignerKeyVersion,
+			Params:  keyFormat.Params,
+			N:       privKey.N.Bytes(),
+			E:       big.NewInt(int64(privKey.E)).Bytes(),
+		},
+		D:   privKey.D.Bytes(),
+		P:   privKey.Primes[0].Bytes(),
+		Q:   privKey.Primes[1].Bytes(),
+		Dp:  privKey.Precomputed.Dp.Bytes(),
+		Dq:  privKey.Precomputed.Dq.Bytes(),
+		Crt: privKey.Precomputed.Qinv.Bytes(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized RsaSsaPkcs1KeyFormat. It should be used solely by the key
+// management API.
+func (km *rsaSSAPKCS1SignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidRSASSAPKCS1SignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPKCS1SignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *rsaSSAPKCS1SignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidRSASSAPKCS1SignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidRSASSAPKCS1SignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidRSASSAPKCS1SignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPKCS1VerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *rsaSSAPKCS1SignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == rsaSSAPKCS1SignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *rsaSSAPKCS1SignerKeyManager) TypeURL() string {
+	return rsaSSAPKCS1SignerTypeURL
+}
+
+// validateKey validates the given RsaSsaPkcs1PrivateKey.
+func (km *rsaSSAPKCS1SignerKeyManager) validateKey(key *rsassapkcs1pb.RsaSsaPkcs1PrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, rsaSSAPKCS1SignerKeyVersion); err != nil {
+		return fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil || key.PublicKey.Params == nil {
+		return errInvalidRSASSAPKCS1SignKey
+	}
+	if err := validateRSAHashType(key.PublicKey.Params.HashType); err != nil {
+		return fmt.Errorf("rsa_ssa_pkcs1_signer_key_manager: invalid key: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given RsaSsaPkcs1KeyFormat.
+func (km *rsaSSAPKCS1SignerKeyManager) validateKeyFormat(format *rsassapkcs1pb.RsaSsaPkcs1KeyFormat) error {
+	if format.Params == nil {
+		return errors.New("missing params")
+	}
+	return validateRSAHashType(format.Params.HashType)
+}
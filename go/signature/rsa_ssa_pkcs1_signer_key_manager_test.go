@@ -0,0 +1,185 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsassapkcs1pb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+)
+
+const (
+	rsaSSAPKCS1SignerTypeURL   = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PrivateKey"
+	rsaSSAPKCS1VerifierTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPkcs1PublicKey"
+)
+
+func newRSASSAPKCS1KeyFormat(modulusSize uint32, hashType commonpb.HashType) *rsassapkcs1pb.RsaSsaPkcs1KeyFormat {
+	return &rsassapkcs1pb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: hashType},
+		ModulusSizeInBits: modulusSize,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+}
+
+func TestRSASSAPKCS1SignVerify(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(rsaSSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PKCS1 signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(rsaSSAPKCS1VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PKCS1 verifier key manager: %s", err)
+	}
+	for _, hashType := range []commonpb.HashType{commonpb.HashType_SHA256, commonpb.HashType_SHA384, commonpb.HashType_SHA512} {
+		t.Run(hashType.String(), func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(newRSASSAPKCS1KeyFormat(2048, hashType))
+			m, err := signerKM.NewKey(serializedFormat)
+			if err != nil {
+				t.Fatalf("signerKM.NewKey() failed: %s", err)
+			}
+			key := m.(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+			if got := key.PublicKey.Params.HashType; got != hashType {
+				t.Errorf("key.PublicKey.Params.HashType = %s, want %s", got, hashType)
+			}
+			serializedKey, _ := proto.Marshal(key)
+			p, err := signerKM.Primitive(serializedKey)
+			if err != nil {
+				t.Fatalf("signerKM.Primitive() failed: %s", err)
+			}
+			pubKeyData, err := signerKM.(registry.PrivateKeyManager).PublicKeyData(serializedKey)
+			if err != nil {
+				t.Fatalf("PublicKeyData() failed: %s", err)
+			}
+			if pubKeyData.TypeUrl != rsaSSAPKCS1VerifierTypeURL {
+				t.Errorf("pubKeyData.TypeUrl = %q, want %q", pubKeyData.TypeUrl, rsaSSAPKCS1VerifierTypeURL)
+			}
+			v, err := verifierKM.Primitive(pubKeyData.Value)
+			if err != nil {
+				t.Fatalf("verifierKM.Primitive() failed: %s", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := p.(tink.Signer).Sign(data)
+			if err != nil {
+				t.Fatalf("Sign() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+				t.Errorf("Verify() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("Verify() succeeded with modified data")
+			}
+		})
+	}
+}
+
+func TestRSASSAPKCS1NewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(rsaSSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PKCS1 signer key manager: %s", err)
+	}
+	badExponent := newRSASSAPKCS1KeyFormat(2048, commonpb.HashType_SHA256)
+	badExponent.PublicExponent = []byte{0x03}
+	for _, tc := range []struct {
+		name   string
+		format *rsassapkcs1pb.RsaSsaPkcs1KeyFormat
+	}{
+		{"small modulus", newRSASSAPKCS1KeyFormat(1024, commonpb.HashType_SHA256)},
+		{"SHA1", newRSASSAPKCS1KeyFormat(2048, commonpb.HashType_SHA1)},
+		{"unknown hash", newRSASSAPKCS1KeyFormat(2048, commonpb.HashType_UNKNOWN_HASH)},
+		{"bad exponent", badExponent},
+		{"no params", &rsassapkcs1pb.RsaSsaPkcs1KeyFormat{ModulusSizeInBits: 2048}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(tc.format)
+			if _, err := km.NewKey(serializedFormat); err == nil {
+				t.Errorf("km.NewKey() succeeded, want error")
+			}
+		})
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("km.NewKey(nil) succeeded, want error")
+	}
+}
+
+func TestRSASSAPKCS1PrimitiveWithInvalidInput(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(rsaSSAPKCS1SignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PKCS1 signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(rsaSSAPKCS1VerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PKCS1 verifier key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPKCS1KeyFormat(2048, commonpb.HashType_SHA256))
+	m, err := signerKM.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	validKey := m.(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+
+	badVersion := proto.Clone(validKey).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badVersion.Version = 1
+	badHash := proto.Clone(validKey).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badHash.PublicKey.Params.HashType = commonpb.HashType_SHA1
+	badD := proto.Clone(validKey).(*rsassapkcs1pb.RsaSsaPkcs1PrivateKey)
+	badD.D = []byte{0x02}
+	for _, tc := range []struct {
+		name string
+		key  *rsassapkcs1pb.RsaSsaPkcs1PrivateKey
+	}{
+		{"bad version", badVersion},
+		{"bad hash", badHash},
+		{"bad private exponent", badD},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := signerKM.Primitive(serializedKey); err == nil {
+				t.Errorf("signerKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+
+	badPublicVersion := proto.Clone(validKey.PublicKey).(*rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+	badPublicVersion.Version = 1
+	badModulus := proto.Clone(validKey.PublicKey).(*rsassapkcs1pb.RsaSsaPkcs1PublicKey)
+	badModulus.N = []byte{0x01, 0x00}
+	for _, tc := range []struct {
+		name string
+		key  *rsassapkcs1pb.RsaSsaPkcs1PublicKey
+	}{
+		{"bad version", badPublicVersion},
+		{"bad modulus", badModulus},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := verifierKM.Primitive(serializedKey); err == nil {
+				t.Errorf("verifierKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+	if _, err := signerKM.Primitive(nil); err == nil {
+		t.Errorf("signerKM.Primitive(nil) succeeded, want error")
+	}
+	if _, err := verifierKM.Primitive(nil); err == nil {
+		t.Errorf("verifierKM.Primitive(nil) succeeded, want error")
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	rsaSSAPSSSignerKeyVersion = 0
+	rsaSSAPSSSignerTypeURL    = "type.googleapis.com/google.crypto.tink.RsaSsaPssPrivateKey"
+)
+
+// common errors
+var errInvalidRSASSAPSSSignKey = errors.New("rsa_ssa_pss_signer_key_manager: invalid key")
+var errInvalidRSASSAPSSSignKeyFormat = errors.New("rsa_ssa_pss_signer_key_manager: invalid key format")
+
+// rsaSSAPSSSignerKeyManager is an implementation of KeyManager interface.
+// It generates new RsaSsaPssPrivateKeys and produces new instances of
+// RSASSAPSSSigner subtle.
+type rsaSSAPSSSignerKeyManager struct{}
+
+// newRSASSAPSSSignerKeyManager creates a new rsaSSAPSSSignerKeyManager.
+func newRSASSAPSSSignerKeyManager() *rsaSSAPSSSignerKeyManager {
+	return new(rsaSSAPSSSignerKeyManager)
+}
+
+// Primitive creates an RSASSAPSSSigner subtle for the given serialized
+// RsaSsaPssPrivateKey proto.
+func (km *rsaSSAPSSSignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	key := new(rsassapsspb.RsaSsaPssPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	pub, err := newRSAPublicKeyData(key.PublicKey.N, key.PublicKey.E)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	privKey, err := newRSAPrivateKeyData(pub, key.D, key.P, key.Q, key.Dp, key.Dq, key.Crt).CreateKey()
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	params := key.PublicKey.Params
+	hash := commonpb.HashType_name[int32(params.SigHash)]
+	ret, err := subtle.NewRSASSAPSSSigner(hash, int(params.SaltLength), privKey)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new RsaSsaPssPrivateKey according to specification the
+// given serialized RsaSsaPssKeyFormat.
+func (km *rsaSSAPSSSignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidRSASSAPSSSignKeyFormat
+	}
+	keyFormat := new(rsassapsspb.RsaSsaPssKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid proto: %s", err)
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key format: %s", err)
+	}
+	e, err := rsaPublicExponent(keyFormat.PublicExponent)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key format: %s", err)
+	}
+	privKey, err := subtle.GenerateRSAKey(int(keyFormat.ModulusSizeInBits), e)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer_key_manager: cannot generate RSA key: %s", err)
+	}
+	return &rsassapsspb.RsaSsaPssPrivateKey{
+		Version: rsaSSAPSSSignerKeyVersion,
+		PublicKey: &rsassapsspb.RsaSsaPssPublicKey{
+			Version: rsaSSAPSSSignerKeyVersion,
+			Params:  keyFormat.Params,
+			N:       privKey.N.Bytes(),
+			E:       big.NewInt(int64(privKey.E)).Bytes(),
+		},
+		D:   privKey.D.Bytes(),
+		P:   privKey.Primes[0].Bytes(),
+		Q:   privKey.Primes[1].Bytes(),
+		Dp:  privKey.Precomputed.Dp.Bytes(),
+		Dq:  privKey.Precomputed.Dq.Bytes(),
+		Crt: privKey.Precomputed.Qinv.Bytes(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized RsaSsaPssKeyFormat. It should be used solely by the key
+// management API.
+func (km *rsaSSAPSSSignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidRSASSAPSSSignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPSSSignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *rsaSSAPSSSignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(rsassapsspb.RsaSsaPssPrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidRSASSAPSSSignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         rsaSSAPSSVerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *rsaSSAPSSSignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == rsaSSAPSSSignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *rsaSSAPSSSignerKeyManager) TypeURL() string {
+	return rsaSSAPSSSignerTypeURL
+}
+
+// validateKey validates the given RsaSsaPssPrivateKey.
+func (km *rsaSSAPSSSignerKeyManager) validateKey(key *rsassapsspb.RsaSsaPssPrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, rsaSSAPSSSignerKeyVersion); err != nil {
+		return fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil {
+		return errInvalidRSASSAPSSSignKey
+	}
+	if err := validateRSASSAPSSParams(key.PublicKey.Params); err != nil {
+		return fmt.Errorf("rsa_ssa_pss_signer_key_manager: invalid key: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given RsaSsaPssKeyFormat.
+func (km *rsaSSAPSSSignerKeyManager) validateKeyFormat(format *rsassapsspb.RsaSsaPssKeyFormat) error {
+	return validateRSASSAPSSParams(format.Params)
+}
@@ -0,0 +1,202 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+)
+
+const (
+	rsaSSAPSSSignerTypeURL   = "type.googleapis.com/google.crypto.tink.RsaSsaPssPrivateKey"
+	rsaSSAPSSVerifierTypeURL = "type.googleapis.com/google.crypto.tink.RsaSsaPssPublicKey"
+)
+
+func newRSASSAPSSKeyFormat(modulusSize uint32, hashType commonpb.HashType) *rsassapsspb.RsaSsaPssKeyFormat {
+	return &rsassapsspb.RsaSsaPssKeyFormat{
+		Params: &rsassapsspb.RsaSsaPssParams{
+			SigHash:    hashType,
+			Mgf1Hash:   hashType,
+			SaltLength: 32,
+		},
+		ModulusSizeInBits: modulusSize,
+		PublicExponent:    []byte{0x01, 0x00, 0x01},
+	}
+}
+
+func TestRSASSAPSSSignVerify(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(rsaSSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PSS signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(rsaSSAPSSVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PSS verifier key manager: %s", err)
+	}
+	for _, hashType := range []commonpb.HashType{commonpb.HashType_SHA256, commonpb.HashType_SHA384, commonpb.HashType_SHA512} {
+		t.Run(hashType.String(), func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(newRSASSAPSSKeyFormat(2048, hashType))
+			m, err := signerKM.NewKey(serializedFormat)
+			if err != nil {
+				t.Fatalf("signerKM.NewKey() failed: %s", err)
+			}
+			key := m.(*rsassapsspb.RsaSsaPssPrivateKey)
+			if got := key.PublicKey.Params.SigHash; got != hashType {
+				t.Errorf("key.PublicKey.Params.SigHash = %s, want %s", got, hashType)
+			}
+			serializedKey, _ := proto.Marshal(key)
+			p, err := signerKM.Primitive(serializedKey)
+			if err != nil {
+				t.Fatalf("signerKM.Primitive() failed: %s", err)
+			}
+			pubKeyData, err := signerKM.(registry.PrivateKeyManager).PublicKeyData(serializedKey)
+			if err != nil {
+				t.Fatalf("PublicKeyData() failed: %s", err)
+			}
+			if pubKeyData.TypeUrl != rsaSSAPSSVerifierTypeURL {
+				t.Errorf("pubKeyData.TypeUrl = %q, want %q", pubKeyData.TypeUrl, rsaSSAPSSVerifierTypeURL)
+			}
+			v, err := verifierKM.Primitive(pubKeyData.Value)
+			if err != nil {
+				t.Fatalf("verifierKM.Primitive() failed: %s", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := p.(tink.Signer).Sign(data)
+			if err != nil {
+				t.Fatalf("Sign() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+				t.Errorf("Verify() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("Verify() succeeded with modified data")
+			}
+		})
+	}
+}
+
+func TestRSASSAPSSNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(rsaSSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PSS signer key manager: %s", err)
+	}
+	badExponent := newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA256)
+	badExponent.PublicExponent = []byte{0x03}
+	mismatchedMGF1 := newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA256)
+	mismatchedMGF1.Params.Mgf1Hash = commonpb.HashType_SHA512
+	zeroSalt := newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA256)
+	zeroSalt.Params.SaltLength = 0
+	negativeSalt := newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA256)
+	negativeSalt.Params.SaltLength = -1
+	for _, tc := range []struct {
+		name   string
+		format *rsassapsspb.RsaSsaPssKeyFormat
+	}{
+		{"small modulus", newRSASSAPSSKeyFormat(1024, commonpb.HashType_SHA256)},
+		{"SHA1", newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA1)},
+		{"unknown hash", newRSASSAPSSKeyFormat(2048, commonpb.HashType_UNKNOWN_HASH)},
+		{"bad exponent", badExponent},
+		{"mismatched MGF1 hash", mismatchedMGF1},
+		{"zero salt length", zeroSalt},
+		{"negative salt length", negativeSalt},
+		{"no params", &rsassapsspb.RsaSsaPssKeyFormat{ModulusSizeInBits: 2048}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedFormat, _ := proto.Marshal(tc.format)
+			if _, err := km.NewKey(serializedFormat); err == nil {
+				t.Errorf("km.NewKey() succeeded, want error")
+			}
+		})
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("km.NewKey(nil) succeeded, want error")
+	}
+}
+
+func TestRSASSAPSSPrimitiveWithInvalidInput(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(rsaSSAPSSSignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PSS signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(rsaSSAPSSVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain RSA-SSA-PSS verifier key manager: %s", err)
+	}
+	serializedFormat, _ := proto.Marshal(newRSASSAPSSKeyFormat(2048, commonpb.HashType_SHA256))
+	m, err := signerKM.NewKey(serializedFormat)
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	validKey := m.(*rsassapsspb.RsaSsaPssPrivateKey)
+
+	badVersion := proto.Clone(validKey).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badVersion.Version = 1
+	badHash := proto.Clone(validKey).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badHash.PublicKey.Params.SigHash = commonpb.HashType_SHA1
+	badHash.PublicKey.Params.Mgf1Hash = commonpb.HashType_SHA1
+	badD := proto.Clone(validKey).(*rsassapsspb.RsaSsaPssPrivateKey)
+	badD.D = []byte{0x02}
+	for _, tc := range []struct {
+		name string
+		key  *rsassapsspb.RsaSsaPssPrivateKey
+	}{
+		{"bad version", badVersion},
+		{"bad hash", badHash},
+		{"bad private exponent", badD},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := signerKM.Primitive(serializedKey); err == nil {
+				t.Errorf("signerKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+
+	badPublicVersion := proto.Clone(validKey.PublicKey).(*rsassapsspb.RsaSsaPssPublicKey)
+	badPublicVersion.Version = 1
+	badModulus := proto.Clone(validKey.PublicKey).(*rsassapsspb.RsaSsaPssPublicKey)
+	badModulus.N = []byte{0x01, 0x00}
+	badSalt := proto.Clone(validKey.PublicKey).(*rsassapsspb.RsaSsaPssPublicKey)
+	badSalt.Params.SaltLength = 0
+	for _, tc := range []struct {
+		name string
+		key  *rsassapsspb.RsaSsaPssPublicKey
+	}{
+		{"bad version", badPublicVersion},
+		{"bad modulus", badModulus},
+		{"bad salt length", badSalt},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := verifierKM.Primitive(serializedKey); err == nil {
+				t.Errorf("verifierKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+	if _, err := signerKM.Primitive(nil); err == nil {
+		t.Errorf("signerKM.Primitive(nil) succeeded, want error")
+	}
+	if _, err := verifierKM.Primitive(nil); err == nil {
+		t.Errorf("verifierKM.Primitive(nil) succeeded, want error")
+	}
+}
@@ -17,13 +17,16 @@
 // Package signature provides implementations of the Signer and Verifier
 // primitives.
 //
-// To sign data using Tink you can use ECDSA or ED25519 key templates.
+// To sign data using Tink you can use ECDSA, ED25519, ED448, RSA-SSA-PKCS1,
+// RSA-SSA-PSS, ML-DSA or SLH-DSA key templates. ML-DSA keys are only supported
+// in binaries built with Go 1.27 or later, which provides crypto/mldsa.
 package signature
 
 import (
 	"fmt"
 
 	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/signature/subtle"
 )
 
 func init() {
@@ -42,4 +45,50 @@ func init() {
 	if err := registry.RegisterKeyManager(newED25519VerifierKeyManager()); err != nil {
 		panic(fmt.Sprintf("signature.init() failed: %v", err))
 	}
+
+	// ED448
+	if err := registry.RegisterKeyManager(newED448SignerKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newED448VerifierKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	// RSA-SSA-PKCS1
+	if err := registry.RegisterKeyManager(newRSASSAPKCS1SignerKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newRSASSAPKCS1VerifierKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	// RSA-SSA-PSS
+	if err := registry.RegisterKeyManager(newRSASSAPSSSignerKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newRSASSAPSSVerifierKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+
+	// SLH-DSA
+	if err := registry.RegisterKeyManager(newSLHDSASignerKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	if err := registry.RegisterKeyManager(newSLHDSAVerifierKeyManager()); err != nil {
+		panic(fmt.Sprintf("signature.init() failed: %v", err))
+	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("signature.init() failed: %v", err))
+		}
+	}
+}
+
+// EnableSECP256K1 allows ECDSA public keys on the secp256k1 curve. Such keys
+// are rejected by default, since the curve is only meant to verify signatures
+// from blockchain ecosystems; see subtle.EnableSECP256K1 for details. Private
+// keys on the curve are always rejected. The opt-in is process-wide and cannot
+// be undone.
+func EnableSECP256K1() {
+	subtle.EnableSECP256K1()
 }
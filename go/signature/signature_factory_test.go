@@ -17,6 +17,7 @@
 package signature_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -146,3 +147,233 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Errorf("calling NewVerifier() with good *keyset.Handle failed: %s", err)
 	}
 }
+
+func TestFactoryInvalidSignatureIsErrInvalidSignature(t *testing.T) {
+	privKH, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	pubKH, err := privKH.Public()
+	if err != nil {
+		t.Fatalf("privKH.Public failed: %s", err)
+	}
+	signer, err := signature.NewSigner(privKH)
+	if err != nil {
+		t.Fatalf("signature.NewSigner failed: %s", err)
+	}
+	verifier, err := signature.NewVerifier(pubKH)
+	if err != nil {
+		t.Fatalf("signature.NewVerifier failed: %s", err)
+	}
+	sig, err := signer.Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("signer.Sign failed: %s", err)
+	}
+	if err := verifier.Verify(sig, []byte("other data")); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("verifier.Verify with wrong data = %v, want signature.ErrInvalidSignature", err)
+	}
+}
+
+func TestFactoryWithRSAKeyTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"RSA-SSA-PKCS1", signature.RSASSAPKCS13072SHA256F4KeyTemplate()},
+		{"RSA-SSA-PKCS1 without prefix", signature.RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate()},
+		{"RSA-SSA-PSS", signature.RSASSAPSS3072SHA256SHA25632F4KeyTemplate()},
+		{"RSA-SSA-PSS without prefix", signature.RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := testSignVerify(tc.template); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestFactoryVerifyBatch(t *testing.T) {
+	privKH, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	oldSigner, err := signature.NewSigner(privKH)
+	if err != nil {
+		t.Fatalf("signature.NewSigner failed: %s", err)
+	}
+	// Rotate to a RAW ECDSA key, so that signatures of several key types and
+	// prefixes are mixed in the batch.
+	manager := keyset.NewManagerFromHandle(privKH)
+	if err := manager.Rotate(signature.ECDSAP256KeyWithoutPrefixTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	rotatedPrivKH, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+	newSigner, err := signature.NewSigner(rotatedPrivKH)
+	if err != nil {
+		t.Fatalf("signature.NewSigner failed: %s", err)
+	}
+	pubKH, err := rotatedPrivKH.Public()
+	if err != nil {
+		t.Fatalf("rotatedPrivKH.Public failed: %s", err)
+	}
+	verifier, err := signature.NewBatchVerifier(pubKH)
+	if err != nil {
+		t.Fatalf("signature.NewBatchVerifier failed: %s", err)
+	}
+
+	const n = 100
+	signatures := make([][]byte, n)
+	data := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		data[i] = random.GetRandomBytes(20)
+		signer := oldSigner
+		if i%2 == 1 {
+			signer = newSigner
+		}
+		if signatures[i], err = signer.Sign(data[i]); err != nil {
+			t.Fatalf("signer.Sign failed: %s", err)
+		}
+	}
+	// Invalidate every third entry.
+	for i := 0; i < n; i += 3 {
+		data[i] = append([]byte{}, data[i]...)
+		data[i][0] ^= 1
+	}
+
+	results, err := verifier.VerifyBatch(signatures, data)
+	if err != nil {
+		t.Fatalf("verifier.VerifyBatch failed: %s", err)
+	}
+	if len(results) != n {
+		t.Fatalf("len(results) = %d, want %d", len(results), n)
+	}
+	for i, err := range results {
+		if i%3 == 0 {
+			if !errors.Is(err, signature.ErrInvalidSignature) {
+				t.Errorf("results[%d] = %v, want signature.ErrInvalidSignature", i, err)
+			}
+		} else if err != nil {
+			t.Errorf("results[%d] = %v, want nil", i, err)
+		}
+		if got := verifier.Verify(signatures[i], data[i]); (got == nil) != (err == nil) {
+			t.Errorf("verifier.Verify(signatures[%d], data[%d]) = %v, inconsistent with VerifyBatch result %v", i, i, got, err)
+		}
+	}
+
+	if results, err := verifier.VerifyBatch(nil, nil); err != nil || len(results) != 0 {
+		t.Errorf("verifier.VerifyBatch(nil, nil) = %v, %v, want empty results", results, err)
+	}
+	if _, err := verifier.VerifyBatch(signatures, data[1:]); err == nil {
+		t.Errorf("verifier.VerifyBatch with mismatched lengths succeeded, want error")
+	}
+}
+
+func TestFactorySignWithInfo(t *testing.T) {
+	for _, tc := range []struct {
+		template *tinkpb.KeyTemplate
+		typeURL  string
+	}{
+		{signature.ECDSAP256KeyTemplate(), testutil.ECDSASignerTypeURL},
+		{signature.ED25519KeyWithoutPrefixTemplate(), testutil.ED25519SignerTypeURL},
+	} {
+		kh, err := keyset.NewHandle(tc.template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		signer, err := signature.NewKeyInfoSigner(kh)
+		if err != nil {
+			t.Fatalf("signature.NewKeyInfoSigner failed: %s", err)
+		}
+		data := random.GetRandomBytes(20)
+		sig, info, err := signer.SignWithInfo(data)
+		if err != nil {
+			t.Fatalf("signer.SignWithInfo failed: %s", err)
+		}
+		want := signature.KeyInfo{
+			KeyID:            kh.KeysetInfo().GetPrimaryKeyId(),
+			OutputPrefixType: tc.template.OutputPrefixType,
+			TypeURL:          tc.typeURL,
+		}
+		if info != want {
+			t.Errorf("signer.SignWithInfo info = %v, want %v", info, want)
+		}
+		pubKH, err := kh.Public()
+		if err != nil {
+			t.Fatalf("kh.Public failed: %s", err)
+		}
+		verifier, err := signature.NewVerifier(pubKH)
+		if err != nil {
+			t.Fatalf("signature.NewVerifier failed: %s", err)
+		}
+		if err := verifier.Verify(sig, data); err != nil {
+			t.Errorf("verifier.Verify failed: %s", err)
+		}
+	}
+}
+
+func TestFactoryVerifierWithKeyIDs(t *testing.T) {
+	tinkPriv, tinkPub := newECDSAKeysetKeypair(commonpb.HashType_SHA256,
+		commonpb.EllipticCurveType_NIST_P256,
+		tinkpb.OutputPrefixType_TINK,
+		1)
+	rawPriv, rawPub := newECDSAKeysetKeypair(commonpb.HashType_SHA256,
+		commonpb.EllipticCurveType_NIST_P256,
+		tinkpb.OutputPrefixType_RAW,
+		2)
+	pubKeys := []*tinkpb.Keyset_Key{tinkPub, rawPub}
+	pubKH, err := testkeyset.NewHandle(testutil.NewKeyset(1, pubKeys))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	data := random.GetRandomBytes(20)
+	sigs := make(map[uint32][]byte)
+	for _, priv := range []*tinkpb.Keyset_Key{tinkPriv, rawPriv} {
+		kh, err := testkeyset.NewHandle(testutil.NewKeyset(priv.KeyId, []*tinkpb.Keyset_Key{priv}))
+		if err != nil {
+			t.Fatalf("testkeyset.NewHandle failed: %s", err)
+		}
+		signer, err := signature.NewSigner(kh)
+		if err != nil {
+			t.Fatalf("signature.NewSigner failed: %s", err)
+		}
+		if sigs[priv.KeyId], err = signer.Sign(data); err != nil {
+			t.Fatalf("signer.Sign failed: %s", err)
+		}
+	}
+
+	for _, allowed := range []uint32{1, 2} {
+		verifier, err := signature.NewVerifierWithKeyIDs(pubKH, allowed)
+		if err != nil {
+			t.Fatalf("signature.NewVerifierWithKeyIDs(%d) failed: %s", allowed, err)
+		}
+		for keyID, sig := range sigs {
+			err := verifier.Verify(sig, data)
+			if keyID == allowed && err != nil {
+				t.Errorf("allowed key %d: Verify(signature of key %d) failed: %s", allowed, keyID, err)
+			}
+			if keyID != allowed && !errors.Is(err, signature.ErrInvalidSignature) {
+				t.Errorf("allowed key %d: Verify(signature of key %d) = %v, want ErrInvalidSignature", allowed, keyID, err)
+			}
+		}
+	}
+
+	verifier, err := signature.NewVerifierWithKeyIDs(pubKH, 1, 2)
+	if err != nil {
+		t.Fatalf("signature.NewVerifierWithKeyIDs(1, 2) failed: %s", err)
+	}
+	for keyID, sig := range sigs {
+		if err := verifier.Verify(sig, data); err != nil {
+			t.Errorf("Verify(signature of key %d) failed: %s", keyID, err)
+		}
+	}
+
+	if _, err := signature.NewVerifierWithKeyIDs(pubKH); err == nil {
+		t.Error("signature.NewVerifierWithKeyIDs without key IDs succeeded")
+	}
+	if _, err := signature.NewVerifierWithKeyIDs(pubKH, 3); err == nil {
+		t.Error("signature.NewVerifierWithKeyIDs with unknown key ID succeeded")
+	}
+}
@@ -20,6 +20,11 @@ import (
 	"github.com/golang/protobuf/proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	mldsapb "github.com/google/tink/go/proto/ml_dsa_go_proto"
+	rsassapkcs1pb "github.com/google/tink/go/proto/rsa_ssa_pkcs1_go_proto"
+	rsassapsspb "github.com/google/tink/go/proto/rsa_ssa_pss_go_proto"
+	slhdsapb "github.com/google/tink/go/proto/slh_dsa_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
@@ -101,6 +106,48 @@ func ECDSAP521KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
 		tinkpb.OutputPrefixType_RAW)
 }
 
+// ECDSAP256DeterministicKeyTemplate is a KeyTemplate that generates a new ECDSA private key with the following
+// parameters:
+//   - Hash function: SHA256
+//   - Curve: NIST P-256
+//   - Signature encoding: DER
+//   - Nonce: deterministic (RFC 6979)
+//   - Output prefix type: TINK
+func ECDSAP256DeterministicKeyTemplate() *tinkpb.KeyTemplate {
+	return createDeterministicECDSAKeyTemplate(commonpb.HashType_SHA256,
+		commonpb.EllipticCurveType_NIST_P256,
+		ecdsapb.EcdsaSignatureEncoding_DER,
+		tinkpb.OutputPrefixType_TINK)
+}
+
+// ECDSAP384DeterministicKeyTemplate is a KeyTemplate that generates a new ECDSA private key with the following
+// parameters:
+//   - Hash function: SHA512
+//   - Curve: NIST P-384
+//   - Signature encoding: DER
+//   - Nonce: deterministic (RFC 6979)
+//   - Output prefix type: TINK
+func ECDSAP384DeterministicKeyTemplate() *tinkpb.KeyTemplate {
+	return createDeterministicECDSAKeyTemplate(commonpb.HashType_SHA512,
+		commonpb.EllipticCurveType_NIST_P384,
+		ecdsapb.EcdsaSignatureEncoding_DER,
+		tinkpb.OutputPrefixType_TINK)
+}
+
+// ECDSAP521DeterministicKeyTemplate is a KeyTemplate that generates a new ECDSA private key with the following
+// parameters:
+//   - Hash function: SHA512
+//   - Curve: NIST P-521
+//   - Signature encoding: DER
+//   - Nonce: deterministic (RFC 6979)
+//   - Output prefix type: TINK
+func ECDSAP521DeterministicKeyTemplate() *tinkpb.KeyTemplate {
+	return createDeterministicECDSAKeyTemplate(commonpb.HashType_SHA512,
+		commonpb.EllipticCurveType_NIST_P521,
+		ecdsapb.EcdsaSignatureEncoding_DER,
+		tinkpb.OutputPrefixType_TINK)
+}
+
 // createECDSAKeyTemplate creates a KeyTemplate containing a EcdasKeyFormat
 // with the given parameters.
 func createECDSAKeyTemplate(hashType commonpb.HashType, curve commonpb.EllipticCurveType,
@@ -110,6 +157,25 @@ func createECDSAKeyTemplate(hashType commonpb.HashType, curve commonpb.EllipticC
 		Curve:    curve,
 		Encoding: encoding,
 	}
+	return newECDSAKeyTemplate(params, prefixType)
+}
+
+// createDeterministicECDSAKeyTemplate is like createECDSAKeyTemplate, but the
+// generated keys sign with RFC 6979 nonces.
+func createDeterministicECDSAKeyTemplate(hashType commonpb.HashType, curve commonpb.EllipticCurveType,
+	encoding ecdsapb.EcdsaSignatureEncoding, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	params := &ecdsapb.EcdsaParams{
+		HashType:           hashType,
+		Curve:              curve,
+		Encoding:           encoding,
+		DeterministicNonce: true,
+	}
+	return newECDSAKeyTemplate(params, prefixType)
+}
+
+// newECDSAKeyTemplate creates a KeyTemplate containing a EcdsaKeyFormat with
+// the given params.
+func newECDSAKeyTemplate(params *ecdsapb.EcdsaParams, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
 	format := &ecdsapb.EcdsaKeyFormat{Params: params}
 	serializedFormat, _ := proto.Marshal(format)
 	return &tinkpb.KeyTemplate{
@@ -134,3 +200,321 @@ func ED25519KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
 		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
 	}
 }
+
+// ED25519PhKeyTemplate is a KeyTemplate that generates a new Ed25519ph private key
+// with an empty context. Ed25519ph keys sign a SHA-512 digest of the data and
+// thus support SignReader and VerifyReader.
+func ED25519PhKeyTemplate() *tinkpb.KeyTemplate {
+	return createED25519KeyTemplate(&ed25519pb.Ed25519Params{
+		Variant: ed25519pb.Ed25519Variant_ED25519_PH,
+	}, tinkpb.OutputPrefixType_TINK)
+}
+
+// ED25519CtxKeyTemplate is a KeyTemplate that generates a new Ed25519ctx private key
+// with the given context, which must be between 1 and 255 bytes long. Signatures
+// only verify with keys using the same context.
+func ED25519CtxKeyTemplate(context []byte) *tinkpb.KeyTemplate {
+	return createED25519KeyTemplate(&ed25519pb.Ed25519Params{
+		Variant: ed25519pb.Ed25519Variant_ED25519_CTX,
+		Context: context,
+	}, tinkpb.OutputPrefixType_TINK)
+}
+
+// createED25519KeyTemplate creates a KeyTemplate containing a Ed25519KeyFormat
+// with the given parameters.
+func createED25519KeyTemplate(params *ed25519pb.Ed25519Params, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &ed25519pb.Ed25519KeyFormat{
+		Params: params,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          ed25519SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// ED448KeyTemplate is a KeyTemplate that generates a new Ed448 private key.
+func ED448KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          ed448SignerTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_TINK,
+	}
+}
+
+// ED448KeyWithoutPrefixTemplate is a KeyTemplate that generates a new Ed448 private key.
+func ED448KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          ed448SignerTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// RSASSAPKCS13072SHA256F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key with the
+// following parameters:
+//   - Modulus size in bits: 3072
+//   - Hash function: SHA256
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: TINK
+func RSASSAPKCS13072SHA256F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(3072, commonpb.HashType_SHA256, tinkpb.OutputPrefixType_TINK)
+}
+
+// RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key
+// with the following parameters:
+//   - Modulus size in bits: 3072
+//   - Hash function: SHA256
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: RAW
+func RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(3072, commonpb.HashType_SHA256, tinkpb.OutputPrefixType_RAW)
+}
+
+// RSASSAPKCS14096SHA512F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key with the
+// following parameters:
+//   - Modulus size in bits: 4096
+//   - Hash function: SHA512
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: TINK
+func RSASSAPKCS14096SHA512F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(4096, commonpb.HashType_SHA512, tinkpb.OutputPrefixType_TINK)
+}
+
+// RSASSAPKCS14096SHA512F4KeyWithoutPrefixTemplate is a KeyTemplate that generates a new RSA-SSA-PKCS1 private key
+// with the following parameters:
+//   - Modulus size in bits: 4096
+//   - Hash function: SHA512
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: RAW
+func RSASSAPKCS14096SHA512F4KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPKCS1KeyTemplate(4096, commonpb.HashType_SHA512, tinkpb.OutputPrefixType_RAW)
+}
+
+// RSASSAPSS3072SHA256SHA25632F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private key with the
+// following parameters:
+//   - Modulus size in bits: 3072
+//   - Signature hash: SHA256
+//   - MGF1 hash: SHA256
+//   - Salt length: 32
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: TINK
+func RSASSAPSS3072SHA256SHA25632F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(3072, commonpb.HashType_SHA256, 32, tinkpb.OutputPrefixType_TINK)
+}
+
+// RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private
+// key with the following parameters:
+//   - Modulus size in bits: 3072
+//   - Signature hash: SHA256
+//   - MGF1 hash: SHA256
+//   - Salt length: 32
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: RAW
+func RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(3072, commonpb.HashType_SHA256, 32, tinkpb.OutputPrefixType_RAW)
+}
+
+// RSASSAPSS4096SHA512SHA51264F4KeyTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private key with the
+// following parameters:
+//   - Modulus size in bits: 4096
+//   - Signature hash: SHA512
+//   - MGF1 hash: SHA512
+//   - Salt length: 64
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: TINK
+func RSASSAPSS4096SHA512SHA51264F4KeyTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(4096, commonpb.HashType_SHA512, 64, tinkpb.OutputPrefixType_TINK)
+}
+
+// RSASSAPSS4096SHA512SHA51264F4KeyWithoutPrefixTemplate is a KeyTemplate that generates a new RSA-SSA-PSS private
+// key with the following parameters:
+//   - Modulus size in bits: 4096
+//   - Signature hash: SHA512
+//   - MGF1 hash: SHA512
+//   - Salt length: 64
+//   - Public exponent: 65537 (aka F4)
+//   - Output prefix type: RAW
+func RSASSAPSS4096SHA512SHA51264F4KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createRSASSAPSSKeyTemplate(4096, commonpb.HashType_SHA512, 64, tinkpb.OutputPrefixType_RAW)
+}
+
+// rsaF4 is the big-endian encoding of the public exponent 65537.
+var rsaF4 = []byte{0x01, 0x00, 0x01}
+
+// createRSASSAPKCS1KeyTemplate creates a KeyTemplate containing a RsaSsaPkcs1KeyFormat
+// with the given parameters.
+func createRSASSAPKCS1KeyTemplate(modulusSize uint32, hashType commonpb.HashType,
+	prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &rsassapkcs1pb.RsaSsaPkcs1KeyFormat{
+		Params:            &rsassapkcs1pb.RsaSsaPkcs1Params{HashType: hashType},
+		ModulusSizeInBits: modulusSize,
+		PublicExponent:    rsaF4,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          rsaSSAPKCS1SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// createRSASSAPSSKeyTemplate creates a KeyTemplate containing a RsaSsaPssKeyFormat
+// with the given parameters. The same hash is used for signing and for MGF1.
+func createRSASSAPSSKeyTemplate(modulusSize uint32, hashType commonpb.HashType, saltLength int32,
+	prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &rsassapsspb.RsaSsaPssKeyFormat{
+		Params: &rsassapsspb.RsaSsaPssParams{
+			SigHash:    hashType,
+			Mgf1Hash:   hashType,
+			SaltLength: saltLength,
+		},
+		ModulusSizeInBits: modulusSize,
+		PublicExponent:    rsaF4,
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          rsaSSAPSSSignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// MLDSA44KeyTemplate is a KeyTemplate that generates a new ML-DSA-44 private key.
+func MLDSA44KeyTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_44, tinkpb.OutputPrefixType_TINK)
+}
+
+// MLDSA44KeyWithoutPrefixTemplate is a KeyTemplate that generates a new ML-DSA-44 private key.
+func MLDSA44KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_44, tinkpb.OutputPrefixType_RAW)
+}
+
+// MLDSA65KeyTemplate is a KeyTemplate that generates a new ML-DSA-65 private key.
+func MLDSA65KeyTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_65, tinkpb.OutputPrefixType_TINK)
+}
+
+// MLDSA65KeyWithoutPrefixTemplate is a KeyTemplate that generates a new ML-DSA-65 private key.
+func MLDSA65KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_65, tinkpb.OutputPrefixType_RAW)
+}
+
+// MLDSA87KeyTemplate is a KeyTemplate that generates a new ML-DSA-87 private key.
+func MLDSA87KeyTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_87, tinkpb.OutputPrefixType_TINK)
+}
+
+// MLDSA87KeyWithoutPrefixTemplate is a KeyTemplate that generates a new ML-DSA-87 private key.
+func MLDSA87KeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createMLDSAKeyTemplate(mldsapb.MlDsaInstance_ML_DSA_87, tinkpb.OutputPrefixType_RAW)
+}
+
+// createMLDSAKeyTemplate creates a KeyTemplate containing a MlDsaKeyFormat
+// for the given parameter set.
+func createMLDSAKeyTemplate(instance mldsapb.MlDsaInstance, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &mldsapb.MlDsaKeyFormat{
+		Params: &mldsapb.MlDsaParams{MlDsaInstance: instance},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          mlDSASignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// SLHDSASHA2128SKeyTemplate is a KeyTemplate that generates a new SLH-DSA-SHA2-128s private key.
+func SLHDSASHA2128SKeyTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128S, tinkpb.OutputPrefixType_TINK)
+}
+
+// SLHDSASHA2128SKeyWithoutPrefixTemplate is a KeyTemplate that generates a new SLH-DSA-SHA2-128s private key.
+func SLHDSASHA2128SKeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128S, tinkpb.OutputPrefixType_RAW)
+}
+
+// SLHDSASHAKE128SKeyTemplate is a KeyTemplate that generates a new SLH-DSA-SHAKE-128s private key.
+func SLHDSASHAKE128SKeyTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128S, tinkpb.OutputPrefixType_TINK)
+}
+
+// SLHDSASHAKE128SKeyWithoutPrefixTemplate is a KeyTemplate that generates a new SLH-DSA-SHAKE-128s private key.
+func SLHDSASHAKE128SKeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128S, tinkpb.OutputPrefixType_RAW)
+}
+
+// SLHDSASHA2128FKeyTemplate is a KeyTemplate that generates a new SLH-DSA-SHA2-128f private key.
+func SLHDSASHA2128FKeyTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128F, tinkpb.OutputPrefixType_TINK)
+}
+
+// SLHDSASHA2128FKeyWithoutPrefixTemplate is a KeyTemplate that generates a new SLH-DSA-SHA2-128f private key.
+func SLHDSASHA2128FKeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128F, tinkpb.OutputPrefixType_RAW)
+}
+
+// SLHDSASHAKE128FKeyTemplate is a KeyTemplate that generates a new SLH-DSA-SHAKE-128f private key.
+func SLHDSASHAKE128FKeyTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128F, tinkpb.OutputPrefixType_TINK)
+}
+
+// SLHDSASHAKE128FKeyWithoutPrefixTemplate is a KeyTemplate that generates a new SLH-DSA-SHAKE-128f private key.
+func SLHDSASHAKE128FKeyWithoutPrefixTemplate() *tinkpb.KeyTemplate {
+	return createSLHDSAKeyTemplate(slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128F, tinkpb.OutputPrefixType_RAW)
+}
+
+// createSLHDSAKeyTemplate creates a KeyTemplate containing a SlhDsaKeyFormat
+// for the given parameter set.
+func createSLHDSAKeyTemplate(instance slhdsapb.SlhDsaInstance, prefixType tinkpb.OutputPrefixType) *tinkpb.KeyTemplate {
+	format := &slhdsapb.SlhDsaKeyFormat{
+		Params: &slhdsapb.SlhDsaParams{SlhDsaInstance: instance},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          slhDSASignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: prefixType,
+	}
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"ECDSA_P256":                               ECDSAP256KeyTemplate,
+	"ECDSA_P256_RAW":                           ECDSAP256KeyWithoutPrefixTemplate,
+	"ECDSA_P384":                               ECDSAP384KeyTemplate,
+	"ECDSA_P384_RAW":                           ECDSAP384KeyWithoutPrefixTemplate,
+	"ECDSA_P521":                               ECDSAP521KeyTemplate,
+	"ECDSA_P521_RAW":                           ECDSAP521KeyWithoutPrefixTemplate,
+	"ECDSA_P256_DETERMINISTIC":                 ECDSAP256DeterministicKeyTemplate,
+	"ECDSA_P384_DETERMINISTIC":                 ECDSAP384DeterministicKeyTemplate,
+	"ECDSA_P521_DETERMINISTIC":                 ECDSAP521DeterministicKeyTemplate,
+	"ED25519":                                  ED25519KeyTemplate,
+	"ED25519_RAW":                              ED25519KeyWithoutPrefixTemplate,
+	"ED25519PH":                                ED25519PhKeyTemplate,
+	"ED448":                                    ED448KeyTemplate,
+	"ED448_RAW":                                ED448KeyWithoutPrefixTemplate,
+	"RSA_SSA_PKCS1_3072_SHA256_F4":             RSASSAPKCS13072SHA256F4KeyTemplate,
+	"RSA_SSA_PKCS1_3072_SHA256_F4_RAW":         RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate,
+	"RSA_SSA_PKCS1_4096_SHA512_F4":             RSASSAPKCS14096SHA512F4KeyTemplate,
+	"RSA_SSA_PKCS1_4096_SHA512_F4_RAW":         RSASSAPKCS14096SHA512F4KeyWithoutPrefixTemplate,
+	"RSA_SSA_PSS_3072_SHA256_SHA256_32_F4":     RSASSAPSS3072SHA256SHA25632F4KeyTemplate,
+	"RSA_SSA_PSS_3072_SHA256_SHA256_32_F4_RAW": RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate,
+	"RSA_SSA_PSS_4096_SHA512_SHA512_64_F4":     RSASSAPSS4096SHA512SHA51264F4KeyTemplate,
+	"RSA_SSA_PSS_4096_SHA512_SHA512_64_F4_RAW": RSASSAPSS4096SHA512SHA51264F4KeyWithoutPrefixTemplate,
+	"ML_DSA_44":                                MLDSA44KeyTemplate,
+	"ML_DSA_44_RAW":                            MLDSA44KeyWithoutPrefixTemplate,
+	"ML_DSA_65":                                MLDSA65KeyTemplate,
+	"ML_DSA_65_RAW":                            MLDSA65KeyWithoutPrefixTemplate,
+	"ML_DSA_87":                                MLDSA87KeyTemplate,
+	"ML_DSA_87_RAW":                            MLDSA87KeyWithoutPrefixTemplate,
+	"SLH_DSA_SHA2_128S":                        SLHDSASHA2128SKeyTemplate,
+	"SLH_DSA_SHA2_128S_RAW":                    SLHDSASHA2128SKeyWithoutPrefixTemplate,
+	"SLH_DSA_SHAKE_128S":                       SLHDSASHAKE128SKeyTemplate,
+	"SLH_DSA_SHAKE_128S_RAW":                   SLHDSASHAKE128SKeyWithoutPrefixTemplate,
+	"SLH_DSA_SHA2_128F":                        SLHDSASHA2128FKeyTemplate,
+	"SLH_DSA_SHA2_128F_RAW":                    SLHDSASHA2128FKeyWithoutPrefixTemplate,
+	"SLH_DSA_SHAKE_128F":                       SLHDSASHAKE128FKeyTemplate,
+	"SLH_DSA_SHAKE_128F_RAW":                   SLHDSASHAKE128FKeyWithoutPrefixTemplate,
+}
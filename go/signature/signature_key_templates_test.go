@@ -39,6 +39,32 @@ func TestKeyTemplates(t *testing.T) {
 			template: signature.ECDSAP384KeyTemplate()},
 		{name: "ECDSA_P521",
 			template: signature.ECDSAP521KeyTemplate()},
+		{name: "ECDSA_P256_DETERMINISTIC",
+			template: signature.ECDSAP256DeterministicKeyTemplate()},
+		{name: "ECDSA_P384_DETERMINISTIC",
+			template: signature.ECDSAP384DeterministicKeyTemplate()},
+		{name: "ECDSA_P521_DETERMINISTIC",
+			template: signature.ECDSAP521DeterministicKeyTemplate()},
+		{name: "ED25519_PH",
+			template: signature.ED25519PhKeyTemplate()},
+		{name: "ED448",
+			template: signature.ED448KeyTemplate()},
+		{name: "RSA_SSA_PKCS1_3072_SHA256_F4",
+			template: signature.RSASSAPKCS13072SHA256F4KeyTemplate()},
+		{name: "RSA_SSA_PKCS1_4096_SHA512_F4",
+			template: signature.RSASSAPKCS14096SHA512F4KeyTemplate()},
+		{name: "RSA_SSA_PSS_3072_SHA256_SHA256_32_F4",
+			template: signature.RSASSAPSS3072SHA256SHA25632F4KeyTemplate()},
+		{name: "RSA_SSA_PSS_4096_SHA512_SHA512_64_F4",
+			template: signature.RSASSAPSS4096SHA512SHA51264F4KeyTemplate()},
+		{name: "SLH_DSA_SHA2_128S",
+			template: signature.SLHDSASHA2128SKeyTemplate()},
+		{name: "SLH_DSA_SHAKE_128S",
+			template: signature.SLHDSASHAKE128SKeyTemplate()},
+		{name: "SLH_DSA_SHA2_128F",
+			template: signature.SLHDSASHA2128FKeyTemplate()},
+		{name: "SLH_DSA_SHAKE_128F",
+			template: signature.SLHDSASHAKE128FKeyTemplate()},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -68,6 +94,24 @@ func TestKeyWithoutPrefixTemplates(t *testing.T) {
 			template: signature.ECDSAP384KeyWithoutPrefixTemplate()},
 		{name: "ECDSA_P521",
 			template: signature.ECDSAP521KeyWithoutPrefixTemplate()},
+		{name: "ED448",
+			template: signature.ED448KeyWithoutPrefixTemplate()},
+		{name: "RSA_SSA_PKCS1_3072_SHA256_F4",
+			template: signature.RSASSAPKCS13072SHA256F4KeyWithoutPrefixTemplate()},
+		{name: "RSA_SSA_PKCS1_4096_SHA512_F4",
+			template: signature.RSASSAPKCS14096SHA512F4KeyWithoutPrefixTemplate()},
+		{name: "RSA_SSA_PSS_3072_SHA256_SHA256_32_F4",
+			template: signature.RSASSAPSS3072SHA256SHA25632F4KeyWithoutPrefixTemplate()},
+		{name: "RSA_SSA_PSS_4096_SHA512_SHA512_64_F4",
+			template: signature.RSASSAPSS4096SHA512SHA51264F4KeyWithoutPrefixTemplate()},
+		{name: "SLH_DSA_SHA2_128S",
+			template: signature.SLHDSASHA2128SKeyWithoutPrefixTemplate()},
+		{name: "SLH_DSA_SHAKE_128S",
+			template: signature.SLHDSASHAKE128SKeyWithoutPrefixTemplate()},
+		{name: "SLH_DSA_SHA2_128F",
+			template: signature.SLHDSASHA2128FKeyWithoutPrefixTemplate()},
+		{name: "SLH_DSA_SHAKE_128F",
+			template: signature.SLHDSASHAKE128FKeyWithoutPrefixTemplate()},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
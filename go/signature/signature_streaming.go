@@ -0,0 +1,151 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/google/tink/go/core/cryptofmt"
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// digestSigner is implemented by Signer primitives that sign a digest of the
+// data, which allows the data to be hashed incrementally.
+type digestSigner interface {
+	NewHash() hash.Hash
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// digestVerifier is implemented by Verifier primitives that verify a digest
+// of the data, which allows the data to be hashed incrementally.
+type digestVerifier interface {
+	NewHash() hash.Hash
+	VerifyDigest(signature, digest []byte) error
+}
+
+var errStreamingNotSupported = errors.New("signature_streaming: key type does not support streaming")
+
+// SignReader signs all data read from r with the primary key of h, hashing it
+// incrementally so that the data never has to be held in memory. The result
+// is identical to signing the same data with the Signer returned by
+// NewSigner.
+//
+// Only key types that sign a digest of the data (ECDSA, RSA-SSA-PKCS1,
+// RSA-SSA-PSS and Ed25519ph) support streaming; other ED25519 keys are
+// rejected.
+func SignReader(h *keyset.Handle, r io.Reader) ([]byte, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("signature_streaming: cannot obtain primitive set: %s", err)
+	}
+	primary := ps.Primary
+	signer, ok := (primary.Primitive).(digestSigner)
+	if !ok {
+		return nil, errStreamingNotSupported
+	}
+	hasher := signer.NewHash()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return nil, fmt.Errorf("signature_streaming: reading data failed: %s", err)
+	}
+	if primary.PrefixType == tinkpb.OutputPrefixType_LEGACY {
+		hasher.Write([]byte{0})
+	}
+	signature, err := signer.SignDigest(hasher.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(primary.Prefix), signature...), nil
+}
+
+// streamingCandidate is a key that may have produced a signature, together
+// with the hash state of the data it would have signed.
+type streamingCandidate struct {
+	verifier  digestVerifier
+	hasher    hash.Hash
+	signature []byte
+	legacy    bool
+}
+
+// VerifyReader checks whether signature is a valid signature of all data read
+// from r under one of the keys in h. The data is hashed incrementally, once
+// for each key the signature may belong to. It returns ErrInvalidSignature if
+// the signature does not verify.
+//
+// Keys whose type does not support streaming (pure ED25519 and Ed25519ctx)
+// are ignored.
+func VerifyReader(h *keyset.Handle, signature []byte, r io.Reader) error {
+	ps, err := h.Primitives()
+	if err != nil {
+		return fmt.Errorf("signature_streaming: cannot obtain primitive set: %s", err)
+	}
+	if len(signature) < cryptofmt.NonRawPrefixSize {
+		return ErrInvalidSignature
+	}
+
+	var candidates []*streamingCandidate
+	matched := false
+	addCandidates := func(entries []*primitiveset.Entry, sig []byte) {
+		for _, e := range entries {
+			matched = true
+			verifier, ok := (e.Primitive).(digestVerifier)
+			if !ok {
+				continue
+			}
+			candidates = append(candidates, &streamingCandidate{
+				verifier:  verifier,
+				hasher:    verifier.NewHash(),
+				signature: sig,
+				legacy:    e.PrefixType == tinkpb.OutputPrefixType_LEGACY,
+			})
+		}
+	}
+	prefix := signature[:cryptofmt.NonRawPrefixSize]
+	if entries, err := ps.EntriesForPrefix(string(prefix)); err == nil {
+		addCandidates(entries, signature[cryptofmt.NonRawPrefixSize:])
+	}
+	if entries, err := ps.RawEntries(); err == nil {
+		addCandidates(entries, signature)
+	}
+	if !matched {
+		return ErrInvalidSignature
+	}
+	if len(candidates) == 0 {
+		return errStreamingNotSupported
+	}
+
+	writers := make([]io.Writer, len(candidates))
+	for i, c := range candidates {
+		writers[i] = c.hasher
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return fmt.Errorf("signature_streaming: reading data failed: %s", err)
+	}
+	for _, c := range candidates {
+		if c.legacy {
+			c.hasher.Write([]byte{0})
+		}
+		if err := c.verifier.VerifyDigest(c.signature, c.hasher.Sum(nil)); err == nil {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testkeyset"
+	"github.com/google/tink/go/testutil"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func streamingKeyHandles(t *testing.T, template *tinkpb.KeyTemplate) (*keyset.Handle, *keyset.Handle) {
+	t.Helper()
+	priv, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle() err = %v", err)
+	}
+	pub, err := priv.Public()
+	if err != nil {
+		t.Fatalf("priv.Public() err = %v", err)
+	}
+	return priv, pub
+}
+
+func legacyECDSAKeyHandles(t *testing.T) (*keyset.Handle, *keyset.Handle) {
+	t.Helper()
+	privKey, pubKey := newECDSAKeysetKeypair(commonpb.HashType_SHA256,
+		commonpb.EllipticCurveType_NIST_P256, tinkpb.OutputPrefixType_LEGACY, 42)
+	priv, err := testkeyset.NewHandle(testutil.NewKeyset(42, []*tinkpb.Keyset_Key{privKey}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v", err)
+	}
+	pub, err := testkeyset.NewHandle(testutil.NewKeyset(42, []*tinkpb.Keyset_Key{pubKey}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle() err = %v", err)
+	}
+	return priv, pub
+}
+
+func TestStreamingSignVerify(t *testing.T) {
+	templateKeys := func(template *tinkpb.KeyTemplate) func(*testing.T) (*keyset.Handle, *keyset.Handle) {
+		return func(t *testing.T) (*keyset.Handle, *keyset.Handle) {
+			return streamingKeyHandles(t, template)
+		}
+	}
+	for _, tc := range []struct {
+		name string
+		keys func(*testing.T) (*keyset.Handle, *keyset.Handle)
+	}{
+		{"ECDSA", templateKeys(signature.ECDSAP256KeyTemplate())},
+		{"ECDSA without prefix", templateKeys(signature.ECDSAP384KeyWithoutPrefixTemplate())},
+		{"ECDSA legacy", legacyECDSAKeyHandles},
+		{"RSA-SSA-PKCS1", templateKeys(signature.RSASSAPKCS13072SHA256F4KeyTemplate())},
+		{"RSA-SSA-PSS", templateKeys(signature.RSASSAPSS3072SHA256SHA25632F4KeyTemplate())},
+		{"Ed25519ph", templateKeys(signature.ED25519PhKeyTemplate())},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			priv, pub := tc.keys(t)
+			signer, err := signature.NewSigner(priv)
+			if err != nil {
+				t.Fatalf("signature.NewSigner() err = %v", err)
+			}
+			verifier, err := signature.NewVerifier(pub)
+			if err != nil {
+				t.Fatalf("signature.NewVerifier() err = %v", err)
+			}
+			data := random.GetRandomBytes(1 << 20)
+
+			sig, err := signature.SignReader(priv, bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("signature.SignReader() err = %v", err)
+			}
+			if err := verifier.Verify(sig, data); err != nil {
+				t.Errorf("verifier.Verify() of streamed signature err = %v", err)
+			}
+			if err := signature.VerifyReader(pub, sig, bytes.NewReader(data)); err != nil {
+				t.Errorf("signature.VerifyReader() err = %v", err)
+			}
+
+			sig, err = signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if err := signature.VerifyReader(pub, sig, bytes.NewReader(data)); err != nil {
+				t.Errorf("signature.VerifyReader() of one-shot signature err = %v", err)
+			}
+
+			modified := append([]byte{}, data...)
+			modified[len(modified)/2] ^= 1
+			if err := signature.VerifyReader(pub, sig, bytes.NewReader(modified)); !errors.Is(err, signature.ErrInvalidSignature) {
+				t.Errorf("signature.VerifyReader() with modified data err = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestStreamingSignVerifyWithED25519(t *testing.T) {
+	priv, pub := streamingKeyHandles(t, signature.ED25519KeyTemplate())
+	if _, err := signature.SignReader(priv, bytes.NewReader([]byte("data"))); err == nil {
+		t.Errorf("signature.SignReader() with ED25519 key succeeded, want error")
+	}
+	signer, err := signature.NewSigner(priv)
+	if err != nil {
+		t.Fatalf("signature.NewSigner() err = %v", err)
+	}
+	sig, err := signer.Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	err = signature.VerifyReader(pub, sig, bytes.NewReader([]byte("data")))
+	if err == nil || errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("signature.VerifyReader() with ED25519 key err = %v, want unsupported error", err)
+	}
+}
+
+func TestStreamingVerifyWithUnknownKey(t *testing.T) {
+	priv, _ := streamingKeyHandles(t, signature.ECDSAP256KeyTemplate())
+	_, otherPub := streamingKeyHandles(t, signature.ECDSAP256KeyTemplate())
+	sig, err := signature.SignReader(priv, bytes.NewReader([]byte("data")))
+	if err != nil {
+		t.Fatalf("signature.SignReader() err = %v", err)
+	}
+	if err := signature.VerifyReader(otherPub, sig, bytes.NewReader([]byte("data"))); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("signature.VerifyReader() with other key err = %v, want ErrInvalidSignature", err)
+	}
+	if err := signature.VerifyReader(otherPub, []byte{1}, bytes.NewReader([]byte("data"))); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("signature.VerifyReader() with short signature err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, io.ErrUnexpectedEOF }
+
+func TestStreamingSignVerifyReadError(t *testing.T) {
+	priv, pub := streamingKeyHandles(t, signature.ECDSAP256KeyTemplate())
+	if _, err := signature.SignReader(priv, errReader{}); err == nil {
+		t.Errorf("signature.SignReader() with failing reader succeeded, want error")
+	}
+	sig, err := signature.SignReader(priv, bytes.NewReader(nil))
+	if err != nil {
+		t.Fatalf("signature.SignReader() err = %v", err)
+	}
+	err = signature.VerifyReader(pub, sig, errReader{})
+	if err == nil || errors.Is(err, signature.ErrInvalidSignature) {
+		t.Errorf("signature.VerifyReader() with failing reader err = %v, want read error", err)
+	}
+}
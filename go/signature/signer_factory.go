@@ -42,13 +42,53 @@ func NewSignerWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Sig
 	return newWrappedSigner(ps)
 }
 
+// NewSignerWithRegistry returns a Signer primitive from the given keyset
+// handle, using the key managers of r instead of the global registry.
+func NewSignerWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.Signer, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("public_key_sign_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedSigner(ps)
+}
+
+// KeyInfo describes the key of a keyset that created a signature.
+type KeyInfo struct {
+	KeyID            uint32
+	OutputPrefixType tinkpb.OutputPrefixType
+	// TypeURL identifies the signature algorithm of the key, e.g.
+	// "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey".
+	TypeURL string
+}
+
+// KeyInfoSigner is a Signer that can also report which key created a
+// signature, so that callers building detached signature envelopes can embed
+// the key ID without parsing the output prefix of the signature.
+type KeyInfoSigner interface {
+	tink.Signer
+
+	// SignWithInfo computes a signature for data like Sign, and returns it
+	// together with information about the key that created it.
+	SignWithInfo(data []byte) ([]byte, KeyInfo, error)
+}
+
+// NewKeyInfoSigner returns a KeyInfoSigner primitive from the given keyset
+// handle.
+func NewKeyInfoSigner(h *keyset.Handle) (KeyInfoSigner, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("public_key_sign_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedSigner(ps)
+}
+
 // wrappedSigner is an Signer implementation that uses the underlying primitive set for signing.
 type wrappedSigner struct {
 	ps *primitiveset.PrimitiveSet
 }
 
-// Asserts that wrappedSigner implements the Signer interface.
-var _ tink.Signer = (*wrappedSigner)(nil)
+// Asserts that wrappedSigner implements the KeyInfoSigner interface.
+var _ KeyInfoSigner = (*wrappedSigner)(nil)
 
 func newWrappedSigner(ps *primitiveset.PrimitiveSet) (*wrappedSigner, error) {
 	if _, ok := (ps.Primary.Primitive).(tink.Signer); !ok {
@@ -72,10 +112,20 @@ func newWrappedSigner(ps *primitiveset.PrimitiveSet) (*wrappedSigner, error) {
 // Sign signs the given data and returns the signature concatenated with the identifier of the
 // primary primitive.
 func (s *wrappedSigner) Sign(data []byte) ([]byte, error) {
+	signature, _, err := s.SignWithInfo(data)
+	return signature, err
+}
+
+// SignWithInfo is like Sign, but also returns information about the primary
+// key that created the signature.
+func (s *wrappedSigner) SignWithInfo(data []byte) ([]byte, KeyInfo, error) {
 	primary := s.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, KeyInfo{}, err
+	}
 	signer, ok := (primary.Primitive).(tink.Signer)
 	if !ok {
-		return nil, fmt.Errorf("public_key_sign_factory: not a Signer primitive")
+		return nil, KeyInfo{}, fmt.Errorf("public_key_sign_factory: not a Signer primitive")
 	}
 
 	var signedData []byte
@@ -88,7 +138,12 @@ func (s *wrappedSigner) Sign(data []byte) ([]byte, error) {
 
 	signature, err := signer.Sign(signedData)
 	if err != nil {
-		return nil, err
+		return nil, KeyInfo{}, err
+	}
+	info := KeyInfo{
+		KeyID:            primary.KeyID,
+		OutputPrefixType: primary.PrefixType,
+		TypeURL:          primary.TypeURL,
 	}
-	return append([]byte(primary.Prefix), signature...), nil
+	return append([]byte(primary.Prefix), signature...), info, nil
 }
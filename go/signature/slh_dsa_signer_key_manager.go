@@ -0,0 +1,164 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	slhdsapb "github.com/google/tink/go/proto/slh_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	slhDSASignerKeyVersion = 0
+	slhDSASignerTypeURL    = "type.googleapis.com/google.crypto.tink.SlhDsaPrivateKey"
+)
+
+// common errors
+var errInvalidSLHDSASignKey = errors.New("slh_dsa_signer_key_manager: invalid key")
+var errInvalidSLHDSASignKeyFormat = errors.New("slh_dsa_signer_key_manager: invalid key format")
+
+// slhDSASignerKeyManager is an implementation of KeyManager interface.
+// It generates new SlhDsaPrivateKeys and produces new instances of SLHDSASigner subtle.
+type slhDSASignerKeyManager struct{}
+
+// newSLHDSASignerKeyManager creates a new slhDSASignerKeyManager.
+func newSLHDSASignerKeyManager() *slhDSASignerKeyManager {
+	return new(slhDSASignerKeyManager)
+}
+
+// Primitive creates an SLHDSASigner subtle for the given serialized SlhDsaPrivateKey proto.
+func (km *slhDSASignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidSLHDSASignKey
+	}
+	key := new(slhdsapb.SlhDsaPrivateKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidSLHDSASignKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	instance := slhdsapb.SlhDsaInstance_name[int32(key.PublicKey.Params.SlhDsaInstance)]
+	ret, err := subtle.NewSLHDSASigner(instance, key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("slh_dsa_signer_key_manager: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new SlhDsaPrivateKey according to specification the given serialized SlhDsaKeyFormat.
+func (km *slhDSASignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidSLHDSASignKeyFormat
+	}
+	keyFormat := new(slhdsapb.SlhDsaKeyFormat)
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, fmt.Errorf("slh_dsa_signer_key_manager: invalid proto: %s", err)
+	}
+	if err := validateSLHDSAParams(keyFormat.Params); err != nil {
+		return nil, fmt.Errorf("slh_dsa_signer_key_manager: invalid key format: %s", err)
+	}
+	instance := slhdsapb.SlhDsaInstance_name[int32(keyFormat.Params.SlhDsaInstance)]
+	private, public, err := subtle.GenerateSLHDSAKey(instance)
+	if err != nil {
+		return nil, fmt.Errorf("slh_dsa_signer_key_manager: cannot generate SLH-DSA key: %s", err)
+	}
+	return &slhdsapb.SlhDsaPrivateKey{
+		Version: slhDSASignerKeyVersion,
+		PublicKey: &slhdsapb.SlhDsaPublicKey{
+			Version:  slhDSASignerKeyVersion,
+			Params:   keyFormat.Params,
+			KeyValue: public,
+		},
+		KeyValue: private,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized SlhDsaKeyFormat. It should be used solely by the key management API.
+func (km *slhDSASignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidSLHDSASignKeyFormat
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         slhDSASignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *slhDSASignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(slhdsapb.SlhDsaPrivateKey)
+	if err := proto.Unmarshal(serializedPrivKey, privKey); err != nil {
+		return nil, errInvalidSLHDSASignKey
+	}
+	if privKey.PublicKey == nil {
+		return nil, errInvalidSLHDSASignKey
+	}
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidSLHDSASignKey
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         slhDSAVerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *slhDSASignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == slhDSASignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *slhDSASignerKeyManager) TypeURL() string {
+	return slhDSASignerTypeURL
+}
+
+// validateKey validates the given SlhDsaPrivateKey.
+func (km *slhDSASignerKeyManager) validateKey(key *slhdsapb.SlhDsaPrivateKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, slhDSASignerKeyVersion); err != nil {
+		return fmt.Errorf("slh_dsa_signer_key_manager: invalid key: %s", err)
+	}
+	if key.PublicKey == nil {
+		return errInvalidSLHDSASignKey
+	}
+	if err := validateSLHDSAParams(key.PublicKey.Params); err != nil {
+		return fmt.Errorf("slh_dsa_signer_key_manager: invalid key: %s", err)
+	}
+	if len(key.KeyValue) != subtle.SLHDSAPrivateKeySize {
+		return fmt.Errorf("slh_dsa_signer_key_manager: invalid key length, got %d", len(key.KeyValue))
+	}
+	// The encoded private key ends with the encoded public key.
+	if !bytes.HasSuffix(key.KeyValue, key.PublicKey.KeyValue) || len(key.PublicKey.KeyValue) != subtle.SLHDSAPublicKeySize {
+		return fmt.Errorf("slh_dsa_signer_key_manager: public key does not match private key")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+	slhdsapb "github.com/google/tink/go/proto/slh_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	slhDSASignerTypeURL   = "type.googleapis.com/google.crypto.tink.SlhDsaPrivateKey"
+	slhDSAVerifierTypeURL = "type.googleapis.com/google.crypto.tink.SlhDsaPublicKey"
+)
+
+func newSLHDSAKeyFormat(instance slhdsapb.SlhDsaInstance) []byte {
+	serializedFormat, _ := proto.Marshal(&slhdsapb.SlhDsaKeyFormat{
+		Params: &slhdsapb.SlhDsaParams{SlhDsaInstance: instance},
+	})
+	return serializedFormat
+}
+
+func TestSLHDSASignVerify(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(slhDSASignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain SLH-DSA signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(slhDSAVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain SLH-DSA verifier key manager: %s", err)
+	}
+	for _, instance := range []slhdsapb.SlhDsaInstance{
+		slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128S,
+		slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128S,
+		slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128F,
+		slhdsapb.SlhDsaInstance_SLH_DSA_SHAKE_128F,
+	} {
+		t.Run(instance.String(), func(t *testing.T) {
+			m, err := signerKM.NewKey(newSLHDSAKeyFormat(instance))
+			if err != nil {
+				t.Fatalf("signerKM.NewKey() failed: %s", err)
+			}
+			key := m.(*slhdsapb.SlhDsaPrivateKey)
+			if got := key.PublicKey.Params.SlhDsaInstance; got != instance {
+				t.Errorf("key.PublicKey.Params.SlhDsaInstance = %s, want %s", got, instance)
+			}
+			serializedKey, _ := proto.Marshal(key)
+			p, err := signerKM.Primitive(serializedKey)
+			if err != nil {
+				t.Fatalf("signerKM.Primitive() failed: %s", err)
+			}
+			pubKeyData, err := signerKM.(registry.PrivateKeyManager).PublicKeyData(serializedKey)
+			if err != nil {
+				t.Fatalf("PublicKeyData() failed: %s", err)
+			}
+			if pubKeyData.TypeUrl != slhDSAVerifierTypeURL {
+				t.Errorf("pubKeyData.TypeUrl = %q, want %q", pubKeyData.TypeUrl, slhDSAVerifierTypeURL)
+			}
+			v, err := verifierKM.Primitive(pubKeyData.Value)
+			if err != nil {
+				t.Fatalf("verifierKM.Primitive() failed: %s", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := p.(tink.Signer).Sign(data)
+			if err != nil {
+				t.Fatalf("Sign() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, data); err != nil {
+				t.Errorf("Verify() failed: %s", err)
+			}
+			if err := v.(tink.Verifier).Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("Verify() succeeded with modified data")
+			}
+		})
+	}
+}
+
+func TestSLHDSAWithInvalidInput(t *testing.T) {
+	signerKM, err := registry.GetKeyManager(slhDSASignerTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain SLH-DSA signer key manager: %s", err)
+	}
+	verifierKM, err := registry.GetKeyManager(slhDSAVerifierTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain SLH-DSA verifier key manager: %s", err)
+	}
+	if _, err := signerKM.NewKey(newSLHDSAKeyFormat(slhdsapb.SlhDsaInstance_SLH_DSA_UNKNOWN_INSTANCE)); err == nil {
+		t.Errorf("signerKM.NewKey() with unknown instance succeeded, want error")
+	}
+	if _, err := signerKM.NewKey(nil); err == nil {
+		t.Errorf("signerKM.NewKey(nil) succeeded, want error")
+	}
+
+	m, err := signerKM.NewKey(newSLHDSAKeyFormat(slhdsapb.SlhDsaInstance_SLH_DSA_SHA2_128F))
+	if err != nil {
+		t.Fatalf("signerKM.NewKey() failed: %s", err)
+	}
+	validKey := m.(*slhdsapb.SlhDsaPrivateKey)
+	badVersion := proto.Clone(validKey).(*slhdsapb.SlhDsaPrivateKey)
+	badVersion.Version = 1
+	badKeyValue := proto.Clone(validKey).(*slhdsapb.SlhDsaPrivateKey)
+	badKeyValue.KeyValue = badKeyValue.KeyValue[1:]
+	mismatchedPublicKey := proto.Clone(validKey).(*slhdsapb.SlhDsaPrivateKey)
+	mismatchedPublicKey.PublicKey.KeyValue = random.GetRandomBytes(32)
+	badInstance := proto.Clone(validKey).(*slhdsapb.SlhDsaPrivateKey)
+	badInstance.PublicKey.Params.SlhDsaInstance = slhdsapb.SlhDsaInstance_SLH_DSA_UNKNOWN_INSTANCE
+	for _, tc := range []struct {
+		name string
+		key  *slhdsapb.SlhDsaPrivateKey
+	}{
+		{"bad version", badVersion},
+		{"bad key value", badKeyValue},
+		{"mismatched public key", mismatchedPublicKey},
+		{"bad instance", badInstance},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := signerKM.Primitive(serializedKey); err == nil {
+				t.Errorf("signerKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+
+	badPublicKeyValue := proto.Clone(validKey.PublicKey).(*slhdsapb.SlhDsaPublicKey)
+	badPublicKeyValue.KeyValue = badPublicKeyValue.KeyValue[1:]
+	badPublicInstance := proto.Clone(validKey.PublicKey).(*slhdsapb.SlhDsaPublicKey)
+	badPublicInstance.Params.SlhDsaInstance = slhdsapb.SlhDsaInstance_SLH_DSA_UNKNOWN_INSTANCE
+	badPublicVersion := proto.Clone(validKey.PublicKey).(*slhdsapb.SlhDsaPublicKey)
+	badPublicVersion.Version = 1
+	for _, tc := range []struct {
+		name string
+		key  *slhdsapb.SlhDsaPublicKey
+	}{
+		{"bad version", badPublicVersion},
+		{"bad key value", badPublicKeyValue},
+		{"bad instance", badPublicInstance},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			serializedKey, _ := proto.Marshal(tc.key)
+			if _, err := verifierKM.Primitive(serializedKey); err == nil {
+				t.Errorf("verifierKM.Primitive() succeeded, want error")
+			}
+		})
+	}
+	if _, err := verifierKM.Primitive(nil); err == nil {
+		t.Errorf("verifierKM.Primitive(nil) succeeded, want error")
+	}
+}
+
+func TestFactoryWithSLHDSAKeyTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"SLH-DSA-SHA2-128f", signature.SLHDSASHA2128FKeyTemplate()},
+		{"SLH-DSA-SHAKE-128f without prefix", signature.SLHDSASHAKE128FKeyWithoutPrefixTemplate()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := testSignVerify(tc.template); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package signature
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature/subtle"
+	slhdsapb "github.com/google/tink/go/proto/slh_dsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	slhDSAVerifierKeyVersion = 0
+	slhDSAVerifierTypeURL    = "type.googleapis.com/google.crypto.tink.SlhDsaPublicKey"
+)
+
+// common errors
+var errInvalidSLHDSAVerifierKey = fmt.Errorf("slh_dsa_verifier_key_manager: invalid key")
+var errSLHDSAVerifierNotImplemented = fmt.Errorf("slh_dsa_verifier_key_manager: not implemented")
+
+// slhDSAVerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type slhDSAVerifierKeyManager struct{}
+
+// newSLHDSAVerifierKeyManager creates a new slhDSAVerifierKeyManager.
+func newSLHDSAVerifierKeyManager() *slhDSAVerifierKeyManager {
+	return new(slhDSAVerifierKeyManager)
+}
+
+// Primitive creates an SLHDSAVerifier subtle for the given serialized SlhDsaPublicKey proto.
+func (km *slhDSAVerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidSLHDSAVerifierKey
+	}
+	key := new(slhdsapb.SlhDsaPublicKey)
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidSLHDSAVerifierKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, fmt.Errorf("slh_dsa_verifier_key_manager: %s", err)
+	}
+	instance := slhdsapb.SlhDsaInstance_name[int32(key.Params.SlhDsaInstance)]
+	ret, err := subtle.NewSLHDSAVerifier(instance, key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("slh_dsa_verifier_key_manager: invalid key: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *slhDSAVerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errSLHDSAVerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *slhDSAVerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errSLHDSAVerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *slhDSAVerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == slhDSAVerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *slhDSAVerifierKeyManager) TypeURL() string {
+	return slhDSAVerifierTypeURL
+}
+
+// validateKey validates the given SlhDsaPublicKey.
+func (km *slhDSAVerifierKeyManager) validateKey(key *slhdsapb.SlhDsaPublicKey) error {
+	if err := keyset.ValidateKeyVersion(key.Version, slhDSAVerifierKeyVersion); err != nil {
+		return err
+	}
+	return validateSLHDSAParams(key.Params)
+}
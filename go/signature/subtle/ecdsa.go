@@ -95,6 +95,13 @@ func ValidateECDSAParams(hashAlg string, curve string, encoding string) error {
 		if hashAlg != "SHA512" {
 			return errors.New("invalid hash type, expect SHA-512")
 		}
+	case "SECP256K1":
+		if !secp256k1IsEnabled() {
+			return errors.New("curve SECP256K1 is disabled, see EnableSECP256K1")
+		}
+		if hashAlg != "SHA256" {
+			return errors.New("invalid hash type, expect SHA-256")
+		}
 	default:
 		return fmt.Errorf("unsupported curve: %s", curve)
 	}
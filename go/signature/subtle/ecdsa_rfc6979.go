@@ -0,0 +1,163 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// rfc6979 generates ECDSA nonces deterministically from the private key and
+// the message digest, as described in RFC 6979, section 3.2.
+type rfc6979 struct {
+	hashFunc func() hash.Hash
+	q        *big.Int
+	field    *ecdsaScalarField
+	k, v     []byte
+}
+
+// newRFC6979 initializes the HMAC_DRBG of RFC 6979 for the given private key
+// and digest (steps a. to g.).
+func newRFC6979(hashFunc func() hash.Hash, priv *ecdsa.PrivateKey, field *ecdsaScalarField, digest []byte) *rfc6979 {
+	q := priv.Curve.Params().N
+	rlen := field.byteLen
+	hlen := hashFunc().Size()
+	g := &rfc6979{
+		hashFunc: hashFunc,
+		q:        q,
+		field:    field,
+		k:        make([]byte, hlen),
+		v:        make([]byte, hlen),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	seed := append(int2octets(priv.D, rlen), bits2octets(digest, q, rlen)...)
+	g.k = g.mac(g.k, g.v, []byte{0x00}, seed)
+	g.v = g.mac(g.k, g.v)
+	g.k = g.mac(g.k, g.v, []byte{0x01}, seed)
+	g.v = g.mac(g.k, g.v)
+	return g
+}
+
+// next returns the next nonce candidate in [1, q-1] (step h.), encoded in
+// the byte length of q. Successive calls return fresh candidates, for the case
+// where a nonce yields r = 0 or s = 0. Only the rejection of a candidate
+// depends on its value.
+func (g *rfc6979) next() []byte {
+	qlen := g.q.BitLen()
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			g.v = g.mac(g.k, g.v)
+			t = append(t, g.v...)
+		}
+		k := shiftRightBytes(t, len(t)*8-qlen, g.field.byteLen)
+		g.k = g.mac(g.k, g.v, []byte{0x00})
+		g.v = g.mac(g.k, g.v)
+		if g.field.isValid(k) == 1 {
+			return k
+		}
+	}
+}
+
+// shiftRightBytes returns the big-endian integer b shifted right by shift
+// bits, encoded in outLen bytes. The memory accesses only depend on the
+// lengths and on shift.
+func shiftRightBytes(b []byte, shift, outLen int) []byte {
+	out := make([]byte, outLen)
+	byteShift, bitShift := shift/8, uint(shift%8)
+	for i := 0; i < outLen; i++ {
+		j := len(b) - 1 - byteShift - i
+		var lo, hi byte
+		if j >= 0 {
+			lo = b[j]
+		}
+		if j >= 1 {
+			hi = b[j-1]
+		}
+		out[outLen-1-i] = lo>>bitShift | hi<<(8-bitShift)
+	}
+	return out
+}
+
+func (g *rfc6979) mac(key []byte, data ...[]byte) []byte {
+	m := hmac.New(g.hashFunc, key)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+// bits2int converts b to an integer keeping only its qlen leftmost bits.
+func bits2int(b []byte, qlen int) *big.Int {
+	x := new(big.Int).SetBytes(b)
+	if blen := len(b) * 8; blen > qlen {
+		x.Rsh(x, uint(blen-qlen))
+	}
+	return x
+}
+
+// int2octets returns the big-endian encoding of x, left-padded to rlen bytes.
+func int2octets(x *big.Int, rlen int) []byte {
+	return x.FillBytes(make([]byte, rlen))
+}
+
+// bits2octets converts the digest b to an integer reduced modulo q, encoded
+// in rlen bytes.
+func bits2octets(b []byte, q *big.Int, rlen int) []byte {
+	z := bits2int(b, q.BitLen())
+	if z.Cmp(q) >= 0 {
+		z.Sub(z, q)
+	}
+	return int2octets(z, rlen)
+}
+
+// signDeterministic computes an ECDSA signature of digest with a nonce
+// derived as described in RFC 6979.
+//
+// The nonce and the private key are handled as fixed-width integers: the
+// nonce is passed to the scalar multiplication padded to the size of the
+// group order, and the scalar arithmetic, including the inversion of the
+// nonce as k^(n-2) mod n, uses ecdsaScalarField, whose sequence of operations
+// does not depend on the values of the nonce or the private key. The digest
+// and r are public.
+func signDeterministic(priv *ecdsa.PrivateKey, hashFunc func() hash.Hash, digest []byte) (*big.Int, *big.Int) {
+	curve := priv.Curve
+	n := curve.Params().N
+	f := newECDSAScalarField(n)
+	d := f.setBytes(priv.D.FillBytes(make([]byte, f.byteLen)))
+	e := f.setBytes(bits2int(digest, n.BitLen()).FillBytes(make([]byte, f.byteLen)))
+	g := newRFC6979(hashFunc, priv, f, digest)
+	for {
+		kBytes := g.next()
+		x, _ := curve.ScalarBaseMult(kBytes)
+		r := new(big.Int).Mod(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		k := f.setBytes(kBytes)
+		rd := f.mul(f.setBytes(r.FillBytes(make([]byte, f.byteLen))), d)
+		s := new(big.Int).SetBytes(f.bytes(f.mul(f.inverse(k), f.add(e, rd))))
+		if s.Sign() == 0 {
+			continue
+		}
+		return r, s
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+)
+
+// Test vectors from RFC 6979, appendix A.2.5 (P-256).
+func TestDeterministicECDSASignerRFC6979Vectors(t *testing.T) {
+	x, _ := new(big.Int).SetString("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", 16)
+	testCases := []struct {
+		hash    string
+		message string
+		r, s    string
+	}{
+		{
+			hash:    "SHA256",
+			message: "sample",
+			r:       "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			s:       "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+		},
+		{
+			hash:    "SHA256",
+			message: "test",
+			r:       "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			s:       "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.hash+"/"+tc.message, func(t *testing.T) {
+			signer, err := subtleSignature.NewDeterministicECDSASigner(tc.hash, "NIST_P256", "IEEE_P1363", x.Bytes())
+			if err != nil {
+				t.Fatalf("NewDeterministicECDSASigner() err = %v", err)
+			}
+			sig, err := signer.Sign([]byte(tc.message))
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			want, _ := hex.DecodeString(tc.r + tc.s)
+			if !bytes.Equal(sig, want) {
+				t.Errorf("signer.Sign() = %x, want %x", sig, want)
+			}
+		})
+	}
+}
+
+func TestDeterministicECDSASignerIsDeterministic(t *testing.T) {
+	for _, tc := range []struct {
+		hash  string
+		curve elliptic.Curve
+	}{
+		{"SHA256", elliptic.P256()},
+		{"SHA512", elliptic.P384()},
+		{"SHA512", elliptic.P521()},
+	} {
+		priv, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+		}
+		signer, err := subtleSignature.NewDeterministicECDSASignerFromPrivateKey(tc.hash, "DER", priv)
+		if err != nil {
+			t.Fatalf("NewDeterministicECDSASignerFromPrivateKey() err = %v", err)
+		}
+		verifier, err := subtleSignature.NewECDSAVerifierFromPublicKey(tc.hash, "DER", &priv.PublicKey)
+		if err != nil {
+			t.Fatalf("NewECDSAVerifierFromPublicKey() err = %v", err)
+		}
+		data := []byte("some data to sign")
+		sig1, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("signer.Sign() err = %v", err)
+		}
+		sig2, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("signer.Sign() err = %v", err)
+		}
+		if !bytes.Equal(sig1, sig2) {
+			t.Errorf("%s: signatures of the same data differ", tc.curve.Params().Name)
+		}
+		if err := verifier.Verify(sig1, data); err != nil {
+			t.Errorf("%s: verifier.Verify() err = %v", tc.curve.Params().Name, err)
+		}
+		sig3, err := signer.Sign([]byte("other data"))
+		if err != nil {
+			t.Fatalf("signer.Sign() err = %v", err)
+		}
+		if bytes.Equal(sig1, sig3) {
+			t.Errorf("%s: signatures of different data are equal", tc.curve.Params().Name)
+		}
+	}
+}
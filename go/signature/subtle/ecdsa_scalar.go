@@ -0,0 +1,189 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// ecdsaScalarField implements arithmetic modulo the order n of an elliptic
+// curve group on fixed-width integers, without branches or memory accesses
+// that depend on the values of the operands. Scalars are little-endian slices
+// of 64-bit limbs, with as many limbs as n, and are reduced modulo n.
+type ecdsaScalarField struct {
+	n []uint64
+	// nInv is -n^-1 mod 2^64.
+	nInv uint64
+	// rr is R^2 mod n, where R = 2^(64*len(n)) is the Montgomery constant.
+	rr []uint64
+	// nMinus2 is the big-endian encoding of n-2.
+	nMinus2 []byte
+	byteLen int
+}
+
+// newECDSAScalarField returns the scalar field of the group of order n. n must
+// be odd, which holds for prime group orders.
+func newECDSAScalarField(n *big.Int) *ecdsaScalarField {
+	f := &ecdsaScalarField{
+		n:       make([]uint64, (n.BitLen()+63)/64),
+		byteLen: (n.BitLen() + 7) / 8,
+	}
+	f.n = f.fromBig(n)
+	r := new(big.Int).Lsh(big.NewInt(1), 64)
+	inv := new(big.Int).ModInverse(new(big.Int).Mod(n, r), r)
+	f.nInv = -inv.Uint64()
+	rr := new(big.Int).Lsh(big.NewInt(1), uint(128*len(f.n)))
+	f.rr = f.fromBig(rr.Mod(rr, n))
+	f.nMinus2 = new(big.Int).Sub(n, big.NewInt(2)).Bytes()
+	return f
+}
+
+// fromBig returns the limbs of x, which is public and fits in len(f.n) limbs.
+func (f *ecdsaScalarField) fromBig(x *big.Int) []uint64 {
+	b := make([]byte, 8*len(f.n))
+	return bytesToLimbs(x.FillBytes(b), len(f.n))
+}
+
+// bytesToLimbs converts the big-endian encoding b to n limbs.
+func bytesToLimbs(b []byte, n int) []uint64 {
+	x := make([]uint64, n)
+	for i := range b {
+		j := len(b) - 1 - i
+		x[j/8] |= uint64(b[i]) << (8 * uint(j%8))
+	}
+	return x
+}
+
+// setBytes returns the scalar of the big-endian encoding b of f.byteLen
+// bytes, which must be less than 2n. Every integer of the bit length of n is
+// less than 2n.
+func (f *ecdsaScalarField) setBytes(b []byte) []uint64 {
+	return f.reduce(bytesToLimbs(b, len(f.n)), 0)
+}
+
+// bytes returns the big-endian encoding of a in f.byteLen bytes.
+func (f *ecdsaScalarField) bytes(a []uint64) []byte {
+	b := make([]byte, f.byteLen)
+	for i := range b {
+		j := len(b) - 1 - i
+		b[i] = byte(a[j/8] >> (8 * uint(j%8)))
+	}
+	return b
+}
+
+// isValid returns 1 if the big-endian encoding b of f.byteLen bytes is in
+// [1, n-1], and 0 otherwise.
+func (f *ecdsaScalarField) isValid(b []byte) int {
+	x := bytesToLimbs(b, len(f.n))
+	var borrow, acc uint64
+	for i := range x {
+		_, borrow = bits.Sub64(x[i], f.n[i], borrow)
+		acc |= x[i]
+	}
+	// The top bit of acc | -acc is set iff acc is not zero.
+	nonZero := (acc | -acc) >> 63
+	return int(borrow & nonZero)
+}
+
+// reduce returns hi*2^(64*len(x)) + x mod n, which must be less than 2n.
+func (f *ecdsaScalarField) reduce(x []uint64, hi uint64) []uint64 {
+	d := make([]uint64, len(x))
+	var borrow uint64
+	for i := range x {
+		d[i], borrow = bits.Sub64(x[i], f.n[i], borrow)
+	}
+	// Keep x if x < n, i.e. if the subtraction borrowed and hi is zero.
+	_, borrow = bits.Sub64(hi, 0, borrow)
+	mask := -borrow
+	for i := range d {
+		d[i] = d[i]&^mask | x[i]&mask
+	}
+	return d
+}
+
+// add returns a + b mod n.
+func (f *ecdsaScalarField) add(a, b []uint64) []uint64 {
+	s := make([]uint64, len(a))
+	var carry uint64
+	for i := range a {
+		s[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	return f.reduce(s, carry)
+}
+
+// montMul returns a * b * R^-1 mod n, computed with the coarsely integrated
+// operand scanning method.
+func (f *ecdsaScalarField) montMul(a, b []uint64) []uint64 {
+	l := len(f.n)
+	t := make([]uint64, l+2)
+	for i := 0; i < l; i++ {
+		// t += a * b[i]
+		var c uint64
+		for j := 0; j < l; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		t[l], c = bits.Add64(t[l], c, 0)
+		t[l+1] = c
+		// t = (t + m * n) / 2^64, where m makes the low limb zero.
+		m := t[0] * f.nInv
+		hi, lo := bits.Mul64(m, f.n[0])
+		_, c = bits.Add64(lo, t[0], 0)
+		c += hi
+		for j := 1; j < l; j++ {
+			hi, lo := bits.Mul64(m, f.n[j])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[l-1], c = bits.Add64(t[l], c, 0)
+		t[l] = t[l+1] + c
+	}
+	return f.reduce(t[:l], t[l])
+}
+
+// mul returns a * b mod n.
+func (f *ecdsaScalarField) mul(a, b []uint64) []uint64 {
+	return f.montMul(f.montMul(a, b), f.rr)
+}
+
+// inverse returns a^-1 mod n, computed as a^(n-2) mod n. The exponent is
+// public, so the sequence of operations only depends on n.
+func (f *ecdsaScalarField) inverse(a []uint64) []uint64 {
+	one := make([]uint64, len(f.n))
+	one[0] = 1
+	aMont := f.montMul(a, f.rr)
+	acc := f.montMul(one, f.rr)
+	for _, b := range f.nMinus2 {
+		for i := 7; i >= 0; i-- {
+			acc = f.montMul(acc, acc)
+			if b>>uint(i)&1 == 1 {
+				acc = f.montMul(acc, aMont)
+			}
+		}
+	}
+	return f.montMul(acc, one)
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+)
+
+func TestECDSAScalarField(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P224(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			n := curve.Params().N
+			f := newECDSAScalarField(n)
+			toBig := func(a []uint64) *big.Int { return new(big.Int).SetBytes(f.bytes(a)) }
+			for i := 0; i < 200; i++ {
+				x, err := rand.Int(rand.Reader, n)
+				if err != nil {
+					t.Fatalf("rand.Int() err = %v", err)
+				}
+				y, err := rand.Int(rand.Reader, n)
+				if err != nil {
+					t.Fatalf("rand.Int() err = %v", err)
+				}
+				// Edge cases: 0, 1 and n-1.
+				switch i {
+				case 0:
+					x.SetInt64(0)
+				case 1:
+					x.SetInt64(1)
+				case 2:
+					x.Sub(n, big.NewInt(1))
+					y.Sub(n, big.NewInt(1))
+				}
+				a := f.setBytes(x.FillBytes(make([]byte, f.byteLen)))
+				b := f.setBytes(y.FillBytes(make([]byte, f.byteLen)))
+				if got := toBig(a); got.Cmp(x) != 0 {
+					t.Fatalf("setBytes(%x) = %x", x, got)
+				}
+				want := new(big.Int).Add(x, y)
+				if got := toBig(f.add(a, b)); got.Cmp(want.Mod(want, n)) != 0 {
+					t.Errorf("add(%x, %x) = %x, want %x", x, y, got, want)
+				}
+				want = new(big.Int).Mul(x, y)
+				if got := toBig(f.mul(a, b)); got.Cmp(want.Mod(want, n)) != 0 {
+					t.Errorf("mul(%x, %x) = %x, want %x", x, y, got, want)
+				}
+				if x.Sign() != 0 {
+					want = new(big.Int).ModInverse(x, n)
+					if got := toBig(f.inverse(a)); got.Cmp(want) != 0 {
+						t.Errorf("inverse(%x) = %x, want %x", x, got, want)
+					}
+				}
+				// Integers of the bit length of n up to 2n are reduced.
+				z := new(big.Int).Add(x, n)
+				if z.BitLen() <= n.BitLen() {
+					if got := toBig(f.setBytes(z.FillBytes(make([]byte, f.byteLen)))); got.Cmp(x) != 0 {
+						t.Errorf("setBytes(%x) = %x, want %x", z, got, x)
+					}
+				}
+			}
+			for _, tc := range []struct {
+				x    *big.Int
+				want int
+			}{
+				{big.NewInt(0), 0},
+				{big.NewInt(1), 1},
+				{new(big.Int).Sub(n, big.NewInt(1)), 1},
+				{n, 0},
+			} {
+				if got := f.isValid(tc.x.FillBytes(make([]byte, f.byteLen))); got != tc.want {
+					t.Errorf("isValid(%x) = %d, want %d", tc.x, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSignDeterministicVerifies(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				t.Fatalf("ecdsa.GenerateKey() err = %v", err)
+			}
+			digest := sha512.Sum512([]byte("message"))
+			r, s := signDeterministic(priv, sha512.New, digest[:])
+			if !ecdsa.Verify(&priv.PublicKey, digest[:], r, s) {
+				t.Errorf("ecdsa.Verify() of signDeterministic() failed")
+			}
+		})
+	}
+}
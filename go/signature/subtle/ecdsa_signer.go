@@ -30,9 +30,10 @@ import (
 // ECDSASigner is an implementation of Signer for ECDSA.
 // At the moment, the implementation only accepts DER encoding.
 type ECDSASigner struct {
-	privateKey *ecdsa.PrivateKey
-	hashFunc   func() hash.Hash
-	encoding   string
+	privateKey    *ecdsa.PrivateKey
+	hashFunc      func() hash.Hash
+	encoding      string
+	deterministic bool
 }
 
 // NewECDSASigner creates a new instance of ECDSASigner.
@@ -40,8 +41,11 @@ func NewECDSASigner(hashAlg string,
 	curve string,
 	encoding string,
 	keyValue []byte) (*ECDSASigner, error) {
+	c := GetECDSACurve(curve)
+	if c == nil {
+		return nil, fmt.Errorf("ecdsa_signer: unsupported curve: %s", curve)
+	}
 	privKey := new(ecdsa.PrivateKey)
-	c := subtle.GetCurve(curve)
 	privKey.PublicKey.Curve = c
 	privKey.D = new(big.Int).SetBytes(keyValue)
 	privKey.PublicKey.X, privKey.PublicKey.Y = c.ScalarBaseMult(keyValue)
@@ -56,6 +60,9 @@ func NewECDSASignerFromPrivateKey(hashAlg string,
 		return nil, errors.New("ecdsa_signer: privateKey.Curve can't be nil")
 	}
 	curve := subtle.ConvertCurveName(privateKey.Curve.Params().Name)
+	if curve == "SECP256K1" {
+		return nil, errors.New("ecdsa_signer: curve SECP256K1 is only supported for verification")
+	}
 	if err := ValidateECDSAParams(hashAlg, curve, encoding); err != nil {
 		return nil, fmt.Errorf("ecdsa_signer: %s", err)
 	}
@@ -67,15 +74,61 @@ func NewECDSASignerFromPrivateKey(hashAlg string,
 	}, nil
 }
 
+// NewDeterministicECDSASigner creates a new instance of ECDSASigner that
+// derives nonces from the private key and the message as described in
+// RFC 6979, instead of using the random number generator. Signing the same
+// data twice yields the same signature.
+func NewDeterministicECDSASigner(hashAlg string,
+	curve string,
+	encoding string,
+	keyValue []byte) (*ECDSASigner, error) {
+	ret, err := NewECDSASigner(hashAlg, curve, encoding, keyValue)
+	if err != nil {
+		return nil, err
+	}
+	ret.deterministic = true
+	return ret, nil
+}
+
+// NewDeterministicECDSASignerFromPrivateKey creates a new instance of
+// ECDSASigner that uses RFC 6979 nonces.
+func NewDeterministicECDSASignerFromPrivateKey(hashAlg string,
+	encoding string,
+	privateKey *ecdsa.PrivateKey) (*ECDSASigner, error) {
+	ret, err := NewECDSASignerFromPrivateKey(hashAlg, encoding, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	ret.deterministic = true
+	return ret, nil
+}
+
 // Sign computes a signature for the given data.
 func (e *ECDSASigner) Sign(data []byte) ([]byte, error) {
 	hashed, err := subtle.ComputeHash(e.hashFunc, data)
 	if err != nil {
 		return nil, err
 	}
-	r, s, err := ecdsa.Sign(rand.Reader, e.privateKey, hashed)
-	if err != nil {
-		return nil, fmt.Errorf("ecdsa_signer: signing failed: %s", err)
+	return e.SignDigest(hashed)
+}
+
+// NewHash returns a new instance of the hash function used by the signer.
+func (e *ECDSASigner) NewHash() hash.Hash {
+	return e.hashFunc()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash function returned by NewHash.
+func (e *ECDSASigner) SignDigest(hashed []byte) ([]byte, error) {
+	var r, s *big.Int
+	if e.deterministic {
+		r, s = signDeterministic(e.privateKey, e.hashFunc, hashed)
+	} else {
+		var err error
+		r, s, err = ecdsa.Sign(rand.Reader, e.privateKey, hashed)
+		if err != nil {
+			return nil, fmt.Errorf("ecdsa_signer: signing failed: %s", err)
+		}
 	}
 	// format the signature
 	sig := NewECDSASignature(r, s)
@@ -39,7 +39,7 @@ type ECDSAVerifier struct {
 // NewECDSAVerifier creates a new instance of ECDSAVerifier.
 func NewECDSAVerifier(hashAlg string, curve string, encoding string, x []byte, y []byte) (*ECDSAVerifier, error) {
 	publicKey := &ecdsa.PublicKey{
-		Curve: subtle.GetCurve(curve),
+		Curve: GetECDSACurve(curve),
 		X:     new(big.Int).SetBytes(x),
 		Y:     new(big.Int).SetBytes(y),
 	}
@@ -55,6 +55,11 @@ func NewECDSAVerifierFromPublicKey(hashAlg string, encoding string, publicKey *e
 	if err := ValidateECDSAParams(hashAlg, curve, encoding); err != nil {
 		return nil, fmt.Errorf("ecdsa_verifier: %s", err)
 	}
+	// crypto/ecdsa does not validate points on curves it does not implement
+	// itself.
+	if curve == "SECP256K1" && !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, errors.New("ecdsa_verifier: public key is not on the curve")
+	}
 	hashFunc := subtle.GetHashFunc(hashAlg)
 	return &ECDSAVerifier{
 		publicKey: publicKey,
@@ -66,14 +71,26 @@ func NewECDSAVerifierFromPublicKey(hashAlg string, encoding string, publicKey *e
 // Verify verifies whether the given signature is valid for the given data.
 // It returns an error if the signature is not valid; nil otherwise.
 func (e *ECDSAVerifier) Verify(signatureBytes, data []byte) error {
-	signature, err := DecodeECDSASignature(signatureBytes, e.encoding)
-	if err != nil {
-		return fmt.Errorf("ecdsa_verifier: %s", err)
-	}
 	hashed, err := subtle.ComputeHash(e.hashFunc, data)
 	if err != nil {
 		return err
 	}
+	return e.VerifyDigest(signatureBytes, hashed)
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (e *ECDSAVerifier) NewHash() hash.Hash {
+	return e.hashFunc()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash function returned by
+// NewHash.
+func (e *ECDSAVerifier) VerifyDigest(signatureBytes, hashed []byte) error {
+	signature, err := DecodeECDSASignature(signatureBytes, e.encoding)
+	if err != nil {
+		return fmt.Errorf("ecdsa_verifier: %s", err)
+	}
 	valid := ecdsa.Verify(e.publicKey, hashed, signature.R, signature.S)
 	if !valid {
 		return errInvalidECDSASignature
@@ -17,12 +17,23 @@
 package subtle
 
 import (
-	"golang.org/x/crypto/ed25519"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
 )
 
-// ED25519Signer is an implementation of Signer for ED25519.
+// MaxED25519ContextSize is the maximal size of an Ed25519ctx or Ed25519ph
+// context string, see RFC 8032.
+const MaxED25519ContextSize = 255
+
+// ED25519Signer is an implementation of Signer for ED25519. If it was created
+// with a context string, it computes Ed25519ctx signatures.
 type ED25519Signer struct {
 	privateKey *ed25519.PrivateKey
+	context    []byte
 }
 
 // NewED25519Signer creates a new instance of ED25519Signer.
@@ -38,11 +49,73 @@ func NewED25519SignerFromPrivateKey(privateKey *ed25519.PrivateKey) (*ED25519Sig
 	}, nil
 }
 
+// NewED25519CtxSigner creates a new instance of ED25519Signer that computes
+// Ed25519ctx signatures with the given non-empty context string.
+func NewED25519CtxSigner(keyValue, context []byte) (*ED25519Signer, error) {
+	if err := validateED25519Context(context); err != nil {
+		return nil, err
+	}
+	if len(context) == 0 {
+		return nil, errors.New("ed25519: Ed25519ctx requires a non-empty context")
+	}
+	p := ed25519.NewKeyFromSeed(keyValue)
+	return &ED25519Signer{
+		privateKey: &p,
+		context:    context,
+	}, nil
+}
+
 // Sign computes a signature for the given data.
 func (e *ED25519Signer) Sign(data []byte) ([]byte, error) {
+	if len(e.context) > 0 {
+		return e.privateKey.Sign(nil, data, &ed25519.Options{Context: string(e.context)})
+	}
 	r := ed25519.Sign(*e.privateKey, data)
 	if len(r) != ed25519.SignatureSize {
 		return nil, errInvalidED25519Signature
 	}
 	return r, nil
 }
+
+// ED25519PhSigner is an implementation of Signer for Ed25519ph, which signs a
+// SHA-512 digest of the data.
+type ED25519PhSigner struct {
+	privateKey ed25519.PrivateKey
+	opts       *ed25519.Options
+}
+
+// NewED25519PhSigner creates a new instance of ED25519PhSigner with the given
+// context string, which may be empty.
+func NewED25519PhSigner(keyValue, context []byte) (*ED25519PhSigner, error) {
+	if err := validateED25519Context(context); err != nil {
+		return nil, err
+	}
+	return &ED25519PhSigner{
+		privateKey: ed25519.NewKeyFromSeed(keyValue),
+		opts:       &ed25519.Options{Hash: crypto.SHA512, Context: string(context)},
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (e *ED25519PhSigner) Sign(data []byte) ([]byte, error) {
+	digest := sha512.Sum512(data)
+	return e.SignDigest(digest[:])
+}
+
+// NewHash returns the hash function used to prehash the data.
+func (e *ED25519PhSigner) NewHash() hash.Hash {
+	return sha512.New()
+}
+
+// SignDigest computes a signature for the given SHA-512 digest of the data.
+func (e *ED25519PhSigner) SignDigest(digest []byte) ([]byte, error) {
+	return e.privateKey.Sign(nil, digest, e.opts)
+}
+
+// validateED25519Context checks the length of an Ed25519 context string.
+func validateED25519Context(context []byte) error {
+	if len(context) > MaxED25519ContextSize {
+		return fmt.Errorf("ed25519: context is longer than %d bytes", MaxED25519ContextSize)
+	}
+	return nil
+}
@@ -216,6 +216,165 @@ func TestED25519WycheproofCases(t *testing.T) {
 	}
 }
 
+func TestED25519CtxAndPhRFC8032Vectors(t *testing.T) {
+	// From RFC 8032, sections 7.2 and 7.3.
+	for _, tc := range []struct {
+		name    string
+		seed    string
+		public  string
+		message string
+		context string
+		sig     string
+		prehash bool
+	}{
+		{
+			name:    "Ed25519ctx",
+			seed:    "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+			public:  "dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+			message: "f726936d19c800494e3fdaff20b276a8",
+			context: "666f6f",
+			sig:     "55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+		},
+		{
+			name:    "Ed25519ph",
+			seed:    "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+			public:  "ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+			message: "616263",
+			sig:     "98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+			prehash: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			seed, _ := hex.DecodeString(tc.seed)
+			public, _ := hex.DecodeString(tc.public)
+			message, _ := hex.DecodeString(tc.message)
+			context, _ := hex.DecodeString(tc.context)
+			want, _ := hex.DecodeString(tc.sig)
+			var signer interface{ Sign([]byte) ([]byte, error) }
+			var verifier interface{ Verify([]byte, []byte) error }
+			var err error
+			if tc.prehash {
+				signer, err = subtleSignature.NewED25519PhSigner(seed, context)
+				if err != nil {
+					t.Fatalf("NewED25519PhSigner() err = %v", err)
+				}
+				verifier, err = subtleSignature.NewED25519PhVerifier(public, context)
+				if err != nil {
+					t.Fatalf("NewED25519PhVerifier() err = %v", err)
+				}
+			} else {
+				signer, err = subtleSignature.NewED25519CtxSigner(seed, context)
+				if err != nil {
+					t.Fatalf("NewED25519CtxSigner() err = %v", err)
+				}
+				verifier, err = subtleSignature.NewED25519CtxVerifier(public, context)
+				if err != nil {
+					t.Fatalf("NewED25519CtxVerifier() err = %v", err)
+				}
+			}
+			sig, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if !bytes.Equal(sig, want) {
+				t.Errorf("signer.Sign() = %x, want %x", sig, want)
+			}
+			if err := verifier.Verify(want, message); err != nil {
+				t.Errorf("verifier.Verify() err = %v", err)
+			}
+			if err := verifier.Verify(want, append(message, 0)); err == nil {
+				t.Errorf("verifier.Verify() succeeded with modified message")
+			}
+		})
+	}
+}
+
+func TestED25519VariantsAreDomainSeparated(t *testing.T) {
+	public, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("key generation error: %s", err)
+	}
+	seed := priv.Seed()
+	data := random.GetRandomBytes(20)
+
+	pureSigner, err := subtleSignature.NewED25519Signer(seed)
+	if err != nil {
+		t.Fatalf("NewED25519Signer() err = %v", err)
+	}
+	ctxSigner, err := subtleSignature.NewED25519CtxSigner(seed, []byte("ctx"))
+	if err != nil {
+		t.Fatalf("NewED25519CtxSigner() err = %v", err)
+	}
+	phSigner, err := subtleSignature.NewED25519PhSigner(seed, nil)
+	if err != nil {
+		t.Fatalf("NewED25519PhSigner() err = %v", err)
+	}
+	pureVerifier, err := subtleSignature.NewED25519Verifier(public)
+	if err != nil {
+		t.Fatalf("NewED25519Verifier() err = %v", err)
+	}
+	ctxVerifier, err := subtleSignature.NewED25519CtxVerifier(public, []byte("ctx"))
+	if err != nil {
+		t.Fatalf("NewED25519CtxVerifier() err = %v", err)
+	}
+	otherCtxVerifier, err := subtleSignature.NewED25519CtxVerifier(public, []byte("other ctx"))
+	if err != nil {
+		t.Fatalf("NewED25519CtxVerifier() err = %v", err)
+	}
+	phVerifier, err := subtleSignature.NewED25519PhVerifier(public, nil)
+	if err != nil {
+		t.Fatalf("NewED25519PhVerifier() err = %v", err)
+	}
+
+	signers := map[string]interface{ Sign([]byte) ([]byte, error) }{
+		"pure": pureSigner,
+		"ctx":  ctxSigner,
+		"ph":   phSigner,
+	}
+	verifiers := map[string]interface{ Verify([]byte, []byte) error }{
+		"pure":      pureVerifier,
+		"ctx":       ctxVerifier,
+		"other ctx": otherCtxVerifier,
+		"ph":        phVerifier,
+	}
+	for signerName, signer := range signers {
+		sig, err := signer.Sign(data)
+		if err != nil {
+			t.Fatalf("%s: Sign() err = %v", signerName, err)
+		}
+		for verifierName, verifier := range verifiers {
+			err := verifier.Verify(sig, data)
+			if signerName == verifierName && err != nil {
+				t.Errorf("%s signature: %s verifier failed: %v", signerName, verifierName, err)
+			}
+			if signerName != verifierName && err == nil {
+				t.Errorf("%s signature: %s verifier succeeded, want error", signerName, verifierName)
+			}
+		}
+	}
+}
+
+func TestED25519InvalidContext(t *testing.T) {
+	seed := random.GetRandomBytes(ed25519.SeedSize)
+	public := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	longContext := make([]byte, subtleSignature.MaxED25519ContextSize+1)
+	if _, err := subtleSignature.NewED25519CtxSigner(seed, nil); err == nil {
+		t.Errorf("NewED25519CtxSigner() with empty context succeeded, want error")
+	}
+	if _, err := subtleSignature.NewED25519CtxSigner(seed, longContext); err == nil {
+		t.Errorf("NewED25519CtxSigner() with long context succeeded, want error")
+	}
+	if _, err := subtleSignature.NewED25519CtxVerifier(public, nil); err == nil {
+		t.Errorf("NewED25519CtxVerifier() with empty context succeeded, want error")
+	}
+	if _, err := subtleSignature.NewED25519PhSigner(seed, longContext); err == nil {
+		t.Errorf("NewED25519PhSigner() with long context succeeded, want error")
+	}
+	if _, err := subtleSignature.NewED25519PhVerifier(public, longContext); err == nil {
+		t.Errorf("NewED25519PhVerifier() with long context succeeded, want error")
+	}
+}
+
 func newSignerVerifier(t *testing.T, pvtKey *ed25519.PrivateKey, pubKey *ed25519.PublicKey) (*subtleSignature.ED25519Signer, *subtleSignature.ED25519Verifier, error) {
 	t.Helper()
 	signer, err := subtleSignature.NewED25519SignerFromPrivateKey(pvtKey)
@@ -17,18 +17,22 @@
 package subtle
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
 	"errors"
 	"fmt"
-
-	"golang.org/x/crypto/ed25519"
+	"hash"
 )
 
 var errInvalidED25519Signature = errors.New("ed25519: invalid signature")
 
 // ED25519Verifier is an implementation of Verifier for ED25519.
 // At the moment, the implementation only accepts signatures with strict DER encoding.
+// If it was created with a context string, it verifies Ed25519ctx signatures.
 type ED25519Verifier struct {
 	publicKey *ed25519.PublicKey
+	context   []byte
 }
 
 // NewED25519Verifier creates a new instance of ED25519Verifier.
@@ -44,14 +48,78 @@ func NewED25519VerifierFromPublicKey(publicKey *ed25519.PublicKey) (*ED25519Veri
 	}, nil
 }
 
+// NewED25519CtxVerifier creates a new instance of ED25519Verifier that
+// verifies Ed25519ctx signatures with the given non-empty context string.
+func NewED25519CtxVerifier(pub, context []byte) (*ED25519Verifier, error) {
+	if err := validateED25519Context(context); err != nil {
+		return nil, err
+	}
+	if len(context) == 0 {
+		return nil, errors.New("ed25519: Ed25519ctx requires a non-empty context")
+	}
+	publicKey := ed25519.PublicKey(pub)
+	return &ED25519Verifier{
+		publicKey: &publicKey,
+		context:   context,
+	}, nil
+}
+
 // Verify verifies whether the given signature is valid for the given data.
 // It returns an error if the signature is not valid; nil otherwise.
 func (e *ED25519Verifier) Verify(signature, data []byte) error {
 	if len(signature) != ed25519.SignatureSize {
 		return fmt.Errorf("the length of the signature is not %d", ed25519.SignatureSize)
 	}
+	if len(e.context) > 0 {
+		if err := ed25519.VerifyWithOptions(*e.publicKey, data, signature, &ed25519.Options{Context: string(e.context)}); err != nil {
+			return errInvalidED25519Signature
+		}
+		return nil
+	}
 	if !ed25519.Verify(*e.publicKey, data, signature) {
 		return errInvalidED25519Signature
 	}
 	return nil
 }
+
+// ED25519PhVerifier is an implementation of Verifier for Ed25519ph.
+type ED25519PhVerifier struct {
+	publicKey ed25519.PublicKey
+	opts      *ed25519.Options
+}
+
+// NewED25519PhVerifier creates a new instance of ED25519PhVerifier with the
+// given context string, which may be empty.
+func NewED25519PhVerifier(pub, context []byte) (*ED25519PhVerifier, error) {
+	if err := validateED25519Context(context); err != nil {
+		return nil, err
+	}
+	return &ED25519PhVerifier{
+		publicKey: ed25519.PublicKey(pub),
+		opts:      &ed25519.Options{Hash: crypto.SHA512, Context: string(context)},
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (e *ED25519PhVerifier) Verify(signature, data []byte) error {
+	digest := sha512.Sum512(data)
+	return e.VerifyDigest(signature, digest[:])
+}
+
+// NewHash returns the hash function used to prehash the data.
+func (e *ED25519PhVerifier) NewHash() hash.Hash {
+	return sha512.New()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// SHA-512 digest of the data.
+func (e *ED25519PhVerifier) VerifyDigest(signature, digest []byte) error {
+	if len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf("the length of the signature is not %d", ed25519.SignatureSize)
+	}
+	if err := ed25519.VerifyWithOptions(e.publicKey, digest, signature, e.opts); err != nil {
+		return errInvalidED25519Signature
+	}
+	return nil
+}
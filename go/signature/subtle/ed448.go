@@ -0,0 +1,495 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"math/bits"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// ED448SeedSize is the size in bytes of an Ed448 private key seed.
+	ED448SeedSize = 57
+	// ED448PublicKeySize is the size in bytes of an Ed448 public key.
+	ED448PublicKeySize = 57
+	// ED448SignatureSize is the size in bytes of an Ed448 signature.
+	ED448SignatureSize = 114
+)
+
+var errInvalidED448Signature = errors.New("ed448: invalid signature")
+
+// GenerateED448Key generates a new Ed448 key and returns its private key seed
+// and encoded public key.
+func GenerateED448Key() (seed, publicKey []byte, err error) {
+	seed = make([]byte, ED448SeedSize)
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, nil, err
+	}
+	return seed, ed448PublicKey(seed), nil
+}
+
+// ED448PublicKey returns the encoded public key of the Ed448 private key with
+// the given seed.
+func ED448PublicKey(seed []byte) ([]byte, error) {
+	if len(seed) != ED448SeedSize {
+		return nil, errors.New("ed448: invalid seed length")
+	}
+	return ed448PublicKey(seed), nil
+}
+
+// The functions below implement pure Ed448 as specified in RFC 8032,
+// section 5.2. The field arithmetic, the point arithmetic and the arithmetic
+// on scalars modulo the group order run in constant time, so that signing does
+// not leak the private key or the nonce through timing. Decoding points, which
+// is only done for public keys and signatures, is not constant time.
+
+const (
+	ed448LimbBits = 28
+	ed448LimbMask = 1<<ed448LimbBits - 1
+)
+
+// ed448Element is an element of GF(p), p = 2^448 - 2^224 - 1, as 16
+// little-endian limbs of 28 bits. The operations below return limbs of at most
+// 2^28, which is not necessarily the canonical representation; use bytes for
+// that.
+type ed448Element [16]uint64
+
+// ed448P holds the limbs of p.
+var ed448P = ed448Element{
+	ed448LimbMask, ed448LimbMask, ed448LimbMask, ed448LimbMask,
+	ed448LimbMask, ed448LimbMask, ed448LimbMask, ed448LimbMask,
+	ed448LimbMask - 1, ed448LimbMask, ed448LimbMask, ed448LimbMask,
+	ed448LimbMask, ed448LimbMask, ed448LimbMask, ed448LimbMask,
+}
+
+// carry propagates the carries of v, so that its limbs are at most 2^28, given
+// limbs below 2^63. Since 2^448 = 2^224 + 1 (mod p), the carry out of the top
+// limb is added to limbs 0 and 8. The second pass absorbs what the first one
+// adds to them.
+func (v *ed448Element) carry() *ed448Element {
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < 15; i++ {
+			v[i+1] += v[i] >> ed448LimbBits
+			v[i] &= ed448LimbMask
+		}
+		c := v[15] >> ed448LimbBits
+		v[15] &= ed448LimbMask
+		v[0] += c
+		v[8] += c
+	}
+	return v
+}
+
+// add sets v = a + b and returns v.
+func (v *ed448Element) add(a, b *ed448Element) *ed448Element {
+	for i := range v {
+		v[i] = a[i] + b[i]
+	}
+	return v.carry()
+}
+
+// sub sets v = a - b and returns v. It adds 2p, whose limbs exceed those of b,
+// so that no limb underflows.
+func (v *ed448Element) sub(a, b *ed448Element) *ed448Element {
+	for i := range v {
+		v[i] = a[i] + 2*ed448P[i] - b[i]
+	}
+	return v.carry()
+}
+
+// neg sets v = -a and returns v.
+func (v *ed448Element) neg(a *ed448Element) *ed448Element {
+	return v.sub(&ed448Element{}, a)
+}
+
+// mul sets v = a * b and returns v.
+func (v *ed448Element) mul(a, b *ed448Element) *ed448Element {
+	// With limbs of at most 2^28, each column sums at most 16 products of at
+	// most 2^56, and the reduction adds at most three columns to each other.
+	var c [31]uint64
+	for i := 0; i < 16; i++ {
+		for j := 0; j < 16; j++ {
+			c[i+j] += a[i] * b[j]
+		}
+	}
+	// The weight of column k >= 16 is 2^(28k) = 2^(28(k-8)) + 2^(28(k-16))
+	// (mod p). Columns are folded from the top, so that columns 16 to 22,
+	// which receive the top ones, are folded again.
+	for k := 30; k >= 16; k-- {
+		c[k-8] += c[k]
+		c[k-16] += c[k]
+	}
+	copy(v[:], c[:16])
+	return v.carry()
+}
+
+// pow sets v = a^e and returns v, for a public big-endian exponent e.
+func (v *ed448Element) pow(a *ed448Element, e []byte) *ed448Element {
+	base := *a
+	r := ed448Element{1}
+	for _, b := range e {
+		for i := 7; i >= 0; i-- {
+			r.mul(&r, &r)
+			if b>>uint(i)&1 == 1 {
+				r.mul(&r, &base)
+			}
+		}
+	}
+	*v = r
+	return v
+}
+
+// invert sets v = 1/a, or 0 if a is 0, by Fermat's little theorem, and
+// returns v.
+func (v *ed448Element) invert(a *ed448Element) *ed448Element {
+	return v.pow(a, ed448Curve().pMinus2)
+}
+
+// setBytes sets v to the 56-byte little-endian integer b, which may not be
+// reduced modulo p, and returns v.
+func (v *ed448Element) setBytes(b []byte) *ed448Element {
+	for j := 0; j < 8; j++ {
+		var w uint64
+		for i := 6; i >= 0; i-- {
+			w = w<<8 | uint64(b[7*j+i])
+		}
+		v[2*j] = w & ed448LimbMask
+		v[2*j+1] = w >> ed448LimbBits
+	}
+	return v
+}
+
+// bytes returns the canonical 56-byte little-endian encoding of v.
+func (v *ed448Element) bytes() []byte {
+	t := *v
+	t.carry()
+	// t < 2p, so subtracting p once and adding it back if the result is
+	// negative reduces t.
+	var borrow int64
+	for i := range t {
+		borrow += int64(t[i]) - int64(ed448P[i])
+		t[i] = uint64(borrow) & ed448LimbMask
+		borrow >>= ed448LimbBits
+	}
+	mask := uint64(borrow)
+	var c uint64
+	for i := range t {
+		c += t[i] + ed448P[i]&mask
+		t[i] = c & ed448LimbMask
+		c >>= ed448LimbBits
+	}
+	out := make([]byte, 56)
+	for j := 0; j < 8; j++ {
+		w := t[2*j] | t[2*j+1]<<ed448LimbBits
+		for i := 0; i < 7; i++ {
+			out[7*j+i] = byte(w >> uint(8*i))
+		}
+	}
+	return out
+}
+
+// ed448Params holds the constants of edwards448.
+type ed448Params struct {
+	d ed448Element
+	b ed448Point
+	// l is the order of the base point, as little-endian 32-bit limbs.
+	l [14]uint32
+	// pMinus2 and sqrtExp are the big-endian exponents used to invert and to
+	// compute square roots in GF(p).
+	pMinus2, sqrtExp []byte
+}
+
+var (
+	initED448   sync.Once
+	ed448Consts *ed448Params
+)
+
+func ed448Curve() *ed448Params {
+	initED448.Do(func() {
+		// The constants are public, so they are computed with math/big.
+		p := new(big.Int).Lsh(big.NewInt(1), 448)
+		p.Sub(p, new(big.Int).Lsh(big.NewInt(1), 224))
+		p.Sub(p, big.NewInt(1))
+		l, _ := new(big.Int).SetString("3fffffffffffffffffffffffffffffffffffffffffffffffffffffff7cca23e9c44edb49aed63690216cc2728dc58f552378c292ab5844f3", 16)
+		bx, _ := new(big.Int).SetString("224580040295924300187604334099896036246789641632564134246125461686950415467406032909029192869357953282578032075146446173674602635247710", 10)
+		by, _ := new(big.Int).SetString("298819210078481492676017930443930673437544040154080242095928241372331506189835876003536878655418784733982303233503462500531545062832660", 10)
+		c := &ed448Params{
+			pMinus2: new(big.Int).Sub(p, big.NewInt(2)).Bytes(),
+			// p = 3 (mod 4), so a square root of x is x^((p+1)/4).
+			sqrtExp: new(big.Int).Rsh(new(big.Int).Add(p, big.NewInt(1)), 2).Bytes(),
+		}
+		c.d.neg(&ed448Element{39081})
+		c.b.x.setBytes(ed448LE(bx, 56))
+		c.b.y.setBytes(ed448LE(by, 56))
+		c.b.z = ed448Element{1}
+		lb := ed448LE(l, 56)
+		for i := range c.l {
+			c.l[i] = binary.LittleEndian.Uint32(lb[4*i:])
+		}
+		ed448Consts = c
+	})
+	return ed448Consts
+}
+
+// ed448LE returns the size-byte little-endian encoding of v.
+func ed448LE(v *big.Int, size int) []byte {
+	b := v.FillBytes(make([]byte, size))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// ed448Point is a point on edwards448 in projective coordinates.
+type ed448Point struct {
+	x, y, z ed448Element
+}
+
+func ed448Identity() *ed448Point {
+	return &ed448Point{y: ed448Element{1}, z: ed448Element{1}}
+}
+
+// add returns p1 + p2, using the complete addition formulas of RFC 8032,
+// section 5.2.4.
+func (c *ed448Params) add(p1, p2 *ed448Point) *ed448Point {
+	var a, b, cc, d, e, f, g, h, t ed448Element
+	a.mul(&p1.z, &p2.z)
+	b.mul(&a, &a)
+	cc.mul(&p1.x, &p2.x)
+	d.mul(&p1.y, &p2.y)
+	e.mul(&c.d, e.mul(&cc, &d))
+	f.sub(&b, &e)
+	g.add(&b, &e)
+	h.mul(h.add(&p1.x, &p1.y), t.add(&p2.x, &p2.y))
+	h.sub(h.sub(&h, &cc), &d)
+	r := new(ed448Point)
+	r.x.mul(r.x.mul(&a, &f), &h)
+	r.y.mul(r.y.mul(&a, &g), t.sub(&d, &cc))
+	r.z.mul(&f, &g)
+	return r
+}
+
+// scalarMult returns [k]q for the little-endian scalar k. It uses fixed 4-bit
+// windows, and reads the table of multiples of q in constant time.
+func (c *ed448Params) scalarMult(k []byte, q *ed448Point) *ed448Point {
+	var table [16]ed448Point
+	table[0] = *ed448Identity()
+	for i := 1; i < len(table); i++ {
+		table[i] = *c.add(&table[i-1], q)
+	}
+	r := ed448Identity()
+	for i := 2*len(k) - 1; i >= 0; i-- {
+		for j := 0; j < 4; j++ {
+			r = c.add(r, r)
+		}
+		w := uint64(k[i/2]>>uint(4*(i%2))) & 0xf
+		var t ed448Point
+		for j := range table {
+			// mask is all ones if j == w, and 0 otherwise.
+			x := uint64(j) ^ w
+			mask := ((x | -x) >> 63) - 1
+			for n := range t.x {
+				t.x[n] |= table[j].x[n] & mask
+				t.y[n] |= table[j].y[n] & mask
+				t.z[n] |= table[j].z[n] & mask
+			}
+		}
+		r = c.add(r, &t)
+	}
+	return r
+}
+
+// encode returns the 57-byte encoding of q.
+func (c *ed448Params) encode(q *ed448Point) []byte {
+	var zInv, x, y ed448Element
+	zInv.invert(&q.z)
+	x.mul(&q.x, &zInv)
+	y.mul(&q.y, &zInv)
+	out := make([]byte, ED448PublicKeySize)
+	copy(out, y.bytes())
+	out[56] |= (x.bytes()[0] & 1) << 7
+	return out
+}
+
+// decode parses the 57-byte encoding of a point.
+func (c *ed448Params) decode(in []byte) (*ed448Point, bool) {
+	if len(in) != ED448PublicKeySize || in[56]&0x7f != 0 {
+		return nil, false
+	}
+	sign := in[56] >> 7
+	var y ed448Element
+	if !bytes.Equal(y.setBytes(in[:56]).bytes(), in[:56]) {
+		// y >= p.
+		return nil, false
+	}
+	// x² = (y² - 1) / (d·y² - 1). The denominator is never 0, as d is not a
+	// square.
+	var y2, u, v, x2, x, check ed448Element
+	one := ed448Element{1}
+	y2.mul(&y, &y)
+	u.sub(&y2, &one)
+	v.sub(v.mul(&c.d, &y2), &one)
+	x2.mul(&u, v.invert(&v))
+	x.pow(&x2, c.sqrtExp)
+	if !bytes.Equal(check.mul(&x, &x).bytes(), x2.bytes()) {
+		return nil, false
+	}
+	xb := x.bytes()
+	if bytes.Equal(xb, make([]byte, 56)) && sign == 1 {
+		return nil, false
+	}
+	if xb[0]&1 != sign {
+		x.neg(&x)
+	}
+	return &ed448Point{x: x, y: y, z: one}, true
+}
+
+// ed448ReduceScalar returns the 56-byte little-endian encoding of the
+// little-endian integer b modulo l. It shifts in the bits of b one at a time
+// and conditionally subtracts l after each, which takes the same time for all
+// values of b of a given length.
+func ed448ReduceScalar(b []byte) []byte {
+	l := &ed448Curve().l
+	var acc, t [14]uint32
+	for i := 8*len(b) - 1; i >= 0; i-- {
+		// acc < l < 2^446, so 2·acc + 1 < 2l fits in 14 limbs, and needs at
+		// most one subtraction of l.
+		for j := len(acc) - 1; j > 0; j-- {
+			acc[j] = acc[j]<<1 | acc[j-1]>>31
+		}
+		acc[0] = acc[0]<<1 | uint32(b[i/8]>>uint(i%8))&1
+		var borrow uint32
+		for j := range acc {
+			t[j], borrow = bits.Sub32(acc[j], l[j], borrow)
+		}
+		// mask is all ones if acc >= l.
+		mask := borrow - 1
+		for j := range acc {
+			acc[j] = t[j]&mask | acc[j]&^mask
+		}
+	}
+	out := make([]byte, 56)
+	for j := range acc {
+		binary.LittleEndian.PutUint32(out[4*j:], acc[j])
+	}
+	return out
+}
+
+// ed448MulAddScalars returns the 56-byte little-endian encoding of
+// k·s + r mod l, for 56-byte little-endian k, s and r.
+func ed448MulAddScalars(k, s, r []byte) []byte {
+	var prod [29]uint32
+	for i := 0; i < 14; i++ {
+		ki := uint64(binary.LittleEndian.Uint32(k[4*i:]))
+		var c uint64
+		for j := 0; j < 14; j++ {
+			c += ki*uint64(binary.LittleEndian.Uint32(s[4*j:])) + uint64(prod[i+j])
+			prod[i+j] = uint32(c)
+			c >>= 32
+		}
+		prod[i+14] = uint32(c)
+	}
+	var c uint32
+	for j := range prod {
+		var rj uint32
+		if j < 14 {
+			rj = binary.LittleEndian.Uint32(r[4*j:])
+		}
+		prod[j], c = bits.Add32(prod[j], rj, c)
+	}
+	b := make([]byte, 4*len(prod))
+	for j := range prod {
+		binary.LittleEndian.PutUint32(b[4*j:], prod[j])
+	}
+	return ed448ReduceScalar(b)
+}
+
+// ed448Hash returns the 114-byte SHAKE256(dom4(0, context) || parts...).
+func ed448Hash(context []byte, parts ...[]byte) []byte {
+	h := sha3.NewShake256()
+	h.Write([]byte("SigEd448"))
+	h.Write([]byte{0, byte(len(context))})
+	h.Write(context)
+	for _, p := range parts {
+		h.Write(p)
+	}
+	out := make([]byte, 114)
+	h.Read(out)
+	return out
+}
+
+// ed448ExpandSeed returns the 56-byte little-endian secret scalar and the
+// prefix derived from seed.
+func ed448ExpandSeed(seed []byte) ([]byte, []byte) {
+	h := make([]byte, 114)
+	sha3.ShakeSum256(h, seed)
+	s := append([]byte{}, h[:56]...)
+	s[0] &= 0xfc
+	s[55] |= 0x80
+	return s, h[57:]
+}
+
+func ed448PublicKey(seed []byte) []byte {
+	c := ed448Curve()
+	s, _ := ed448ExpandSeed(seed)
+	return c.encode(c.scalarMult(s, &c.b))
+}
+
+func ed448Sign(seed, publicKey, context, message []byte) []byte {
+	c := ed448Curve()
+	s, prefix := ed448ExpandSeed(seed)
+	r := ed448ReduceScalar(ed448Hash(context, prefix, message))
+	rEnc := c.encode(c.scalarMult(r, &c.b))
+	k := ed448ReduceScalar(ed448Hash(context, rEnc, publicKey, message))
+	sig := make([]byte, ED448SignatureSize)
+	copy(sig, rEnc)
+	copy(sig[57:], ed448MulAddScalars(k, s, r))
+	return sig
+}
+
+func ed448Verify(publicKey, context, message, sig []byte) bool {
+	if len(sig) != ED448SignatureSize {
+		return false
+	}
+	c := ed448Curve()
+	a, ok := c.decode(publicKey)
+	if !ok {
+		return false
+	}
+	if _, ok := c.decode(sig[:57]); !ok {
+		return false
+	}
+	// S must be reduced modulo l.
+	s := sig[57:]
+	if s[56] != 0 || !bytes.Equal(ed448ReduceScalar(s[:56]), s[:56]) {
+		return false
+	}
+	k := ed448ReduceScalar(ed448Hash(context, sig[:57], publicKey, message))
+	// Check [S]B = R + [k]A by comparing encodings, i.e. check that
+	// R = [S]B - [k]A.
+	a.x.neg(&a.x)
+	got := c.encode(c.add(c.scalarMult(s[:56], &c.b), c.scalarMult(k, a)))
+	return bytes.Equal(got, sig[:57])
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+)
+
+// ED448Signer is an implementation of Signer for pure Ed448.
+type ED448Signer struct {
+	seed      []byte
+	publicKey []byte
+}
+
+// NewED448Signer creates a new instance of ED448Signer for the private key
+// with the given seed.
+func NewED448Signer(seed []byte) (*ED448Signer, error) {
+	if len(seed) != ED448SeedSize {
+		return nil, errors.New("ed448: invalid seed length")
+	}
+	return &ED448Signer{
+		seed:      append([]byte{}, seed...),
+		publicKey: ed448PublicKey(seed),
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (e *ED448Signer) Sign(data []byte) ([]byte, error) {
+	return ed448Sign(e.seed, e.publicKey, nil, data), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestED448Vectors(t *testing.T) {
+	for _, tc := range []struct {
+		name, seed, pub, msg, sig string
+	}{
+		{
+			// RFC 8032, section 7.4, "-----Blank".
+			name: "RFC 8032 blank",
+			seed: "6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b",
+			pub:  "5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180",
+			msg:  "",
+			sig:  "533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980ff0d2028d4b18a9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4dbb61149f05a7363268c71d95808ff2e652600",
+		},
+		{
+			// Generated with OpenSSL 3.0.
+			name: "OpenSSL",
+			seed: "246ba62c9cffc8e4a9a87c7dc66e46a137c345f3b01e1443a4801a0b290fd5d14d3c60dd57e6933a6581a6897b3540ff0d37592a72e64804b1",
+			pub:  "a2e9a14ec3827e53095857b6caa6f2f2161cfa4b2ea8d130e6c706296e3fd811e35672a3cde95fc1d83506104027884ebf68fc7729c6ad7900",
+			msg:  hex.EncodeToString([]byte("hello ed448")),
+			sig:  "2c0cee9de00f3ed16f4f0d86e069ef544c60ad809d72d833694a86c1eb9458b4dbe0729aee99134580bbeb4cb72a59364d49a3ed4e56ddb980328803cbe2f3afa97254fa4ae3bf34f2f7297648c8d03b70bee93a43680e379b931bcc64ca97cb65817da617887317ba28baf80252b77b3100",
+		},
+	} {
+		seed, _ := hex.DecodeString(tc.seed)
+		wantPub, _ := hex.DecodeString(tc.pub)
+		msg, _ := hex.DecodeString(tc.msg)
+		wantSig, _ := hex.DecodeString(tc.sig)
+
+		pub, err := subtleSignature.ED448PublicKey(seed)
+		if err != nil {
+			t.Fatalf("%s: ED448PublicKey() err = %v", tc.name, err)
+		}
+		if !bytes.Equal(pub, wantPub) {
+			t.Errorf("%s: ED448PublicKey() = %x, want %x", tc.name, pub, wantPub)
+		}
+		signer, err := subtleSignature.NewED448Signer(seed)
+		if err != nil {
+			t.Fatalf("%s: NewED448Signer() err = %v", tc.name, err)
+		}
+		sig, err := signer.Sign(msg)
+		if err != nil {
+			t.Fatalf("%s: Sign() err = %v", tc.name, err)
+		}
+		if !bytes.Equal(sig, wantSig) {
+			t.Errorf("%s: Sign() = %x, want %x", tc.name, sig, wantSig)
+		}
+		verifier, err := subtleSignature.NewED448Verifier(wantPub)
+		if err != nil {
+			t.Fatalf("%s: NewED448Verifier() err = %v", tc.name, err)
+		}
+		if err := verifier.Verify(wantSig, msg); err != nil {
+			t.Errorf("%s: Verify() err = %v", tc.name, err)
+		}
+	}
+}
+
+func TestED448SignVerify(t *testing.T) {
+	seed, pub, err := subtleSignature.GenerateED448Key()
+	if err != nil {
+		t.Fatalf("GenerateED448Key() err = %v", err)
+	}
+	signer, err := subtleSignature.NewED448Signer(seed)
+	if err != nil {
+		t.Fatalf("NewED448Signer() err = %v", err)
+	}
+	verifier, err := subtleSignature.NewED448Verifier(pub)
+	if err != nil {
+		t.Fatalf("NewED448Verifier() err = %v", err)
+	}
+	data := random.GetRandomBytes(20)
+	sig, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("Sign() err = %v", err)
+	}
+	if err := verifier.Verify(sig, data); err != nil {
+		t.Errorf("Verify() err = %v", err)
+	}
+	if err := verifier.Verify(sig, []byte("other data")); err == nil {
+		t.Error("Verify() with modified data succeeded")
+	}
+	for i := range sig {
+		modified := append([]byte{}, sig...)
+		modified[i] ^= 0x01
+		if err := verifier.Verify(modified, data); err == nil {
+			t.Errorf("Verify() with modified byte %d succeeded", i)
+		}
+	}
+	if err := verifier.Verify(sig[:len(sig)-1], data); err == nil {
+		t.Error("Verify() with truncated signature succeeded")
+	}
+}
+
+func TestED448InvalidKeys(t *testing.T) {
+	if _, err := subtleSignature.NewED448Signer(make([]byte, 32)); err == nil {
+		t.Error("NewED448Signer() with a 32-byte seed succeeded")
+	}
+	if _, err := subtleSignature.NewED448Verifier(make([]byte, 32)); err == nil {
+		t.Error("NewED448Verifier() with a 32-byte key succeeded")
+	}
+	invalid := bytes.Repeat([]byte{0xff}, subtleSignature.ED448PublicKeySize)
+	if _, err := subtleSignature.NewED448Verifier(invalid); err == nil {
+		t.Error("NewED448Verifier() with a non-canonical key succeeded")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ED448Verifier is an implementation of Verifier for pure Ed448.
+type ED448Verifier struct {
+	publicKey []byte
+}
+
+// NewED448Verifier creates a new instance of ED448Verifier.
+func NewED448Verifier(pub []byte) (*ED448Verifier, error) {
+	if _, ok := ed448Curve().decode(pub); !ok {
+		return nil, errors.New("ed448: invalid public key")
+	}
+	return &ED448Verifier{
+		publicKey: append([]byte{}, pub...),
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (e *ED448Verifier) Verify(signature, data []byte) error {
+	if len(signature) != ED448SignatureSize {
+		return fmt.Errorf("the length of the signature is not %d", ED448SignatureSize)
+	}
+	if !ed448Verify(e.publicKey, nil, data, signature) {
+		return errInvalidED448Signature
+	}
+	return nil
+}
@@ -67,6 +67,8 @@ func ieeeSignatureSize(curveName string) (int, error) {
 		return 96, nil
 	case elliptic.P521().Params().Name:
 		return 132, nil
+	case SECP256K1().Params().Name:
+		return 64, nil
 	default:
 		return 0, fmt.Errorf("ieeeP1363 unsupported curve name: %q", curveName)
 	}
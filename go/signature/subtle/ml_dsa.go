@@ -0,0 +1,71 @@
+//go:build go1.27
+// +build go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/mldsa"
+	"fmt"
+)
+
+// MLDSASeedSize is the size in bytes of an ML-DSA private key seed.
+const MLDSASeedSize = mldsa.PrivateKeySize
+
+// mldsaParameters returns the ML-DSA parameter set with the given name, which
+// is one of "ML_DSA_44", "ML_DSA_65" or "ML_DSA_87".
+func mldsaParameters(instance string) (mldsa.Parameters, error) {
+	switch instance {
+	case "ML_DSA_44":
+		return mldsa.MLDSA44(), nil
+	case "ML_DSA_65":
+		return mldsa.MLDSA65(), nil
+	case "ML_DSA_87":
+		return mldsa.MLDSA87(), nil
+	default:
+		return mldsa.Parameters{}, fmt.Errorf("unsupported ML-DSA instance: %s", instance)
+	}
+}
+
+// GenerateMLDSAKey generates a new ML-DSA key for the given parameter set and
+// returns its private key seed and encoded public key.
+func GenerateMLDSAKey(instance string) (seed, publicKey []byte, err error) {
+	params, err := mldsaParameters(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv, err := mldsa.GenerateKey(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv.Bytes(), priv.PublicKey().Bytes(), nil
+}
+
+// MLDSAPublicKey returns the encoded public key of the ML-DSA private key
+// with the given seed.
+func MLDSAPublicKey(instance string, seed []byte) ([]byte, error) {
+	params, err := mldsaParameters(instance)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := mldsa.NewPrivateKey(params, seed)
+	if err != nil {
+		return nil, err
+	}
+	return priv.PublicKey().Bytes(), nil
+}
@@ -0,0 +1,54 @@
+//go:build go1.27
+// +build go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/mldsa"
+	"fmt"
+)
+
+// MLDSASigner is an implementation of Signer for ML-DSA. Signatures are
+// hedged, i.e. they mix fresh randomness into the signing nonce.
+type MLDSASigner struct {
+	privateKey *mldsa.PrivateKey
+}
+
+// NewMLDSASigner creates a new instance of MLDSASigner for the given
+// parameter set and private key seed.
+func NewMLDSASigner(instance string, seed []byte) (*MLDSASigner, error) {
+	params, err := mldsaParameters(instance)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer: %s", err)
+	}
+	priv, err := mldsa.NewPrivateKey(params, seed)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer: %s", err)
+	}
+	return &MLDSASigner{privateKey: priv}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *MLDSASigner) Sign(data []byte) ([]byte, error) {
+	sig, err := s.privateKey.Sign(nil, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_signer: signing failed: %s", err)
+	}
+	return sig, nil
+}
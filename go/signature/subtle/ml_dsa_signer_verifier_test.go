@@ -0,0 +1,113 @@
+//go:build go1.27
+// +build go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestMLDSASignVerify(t *testing.T) {
+	for _, tc := range []struct {
+		instance      string
+		publicKeySize int
+	}{
+		{"ML_DSA_44", 1312},
+		{"ML_DSA_65", 1952},
+		{"ML_DSA_87", 2592},
+	} {
+		t.Run(tc.instance, func(t *testing.T) {
+			seed, pub, err := subtleSignature.GenerateMLDSAKey(tc.instance)
+			if err != nil {
+				t.Fatalf("GenerateMLDSAKey() err = %v", err)
+			}
+			if len(seed) != subtleSignature.MLDSASeedSize {
+				t.Errorf("len(seed) = %d, want %d", len(seed), subtleSignature.MLDSASeedSize)
+			}
+			if len(pub) != tc.publicKeySize {
+				t.Errorf("len(pub) = %d, want %d", len(pub), tc.publicKeySize)
+			}
+			signer, err := subtleSignature.NewMLDSASigner(tc.instance, seed)
+			if err != nil {
+				t.Fatalf("NewMLDSASigner() err = %v", err)
+			}
+			verifier, err := subtleSignature.NewMLDSAVerifier(tc.instance, pub)
+			if err != nil {
+				t.Fatalf("NewMLDSAVerifier() err = %v", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if err := verifier.Verify(sig, data); err != nil {
+				t.Errorf("verifier.Verify() err = %v", err)
+			}
+			if err := verifier.Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("verifier.Verify() succeeded with modified data")
+			}
+			sig[0] ^= 1
+			if err := verifier.Verify(sig, data); err == nil {
+				t.Errorf("verifier.Verify() succeeded with modified signature")
+			}
+
+			// The public key is fully determined by the seed.
+			derived, err := subtleSignature.MLDSAPublicKey(tc.instance, seed)
+			if err != nil {
+				t.Fatalf("MLDSAPublicKey() err = %v", err)
+			}
+			otherVerifier, err := subtleSignature.NewMLDSAVerifier(tc.instance, derived)
+			if err != nil {
+				t.Fatalf("NewMLDSAVerifier() err = %v", err)
+			}
+			sig, err = signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if err := otherVerifier.Verify(sig, data); err != nil {
+				t.Errorf("otherVerifier.Verify() err = %v", err)
+			}
+		})
+	}
+}
+
+func TestMLDSAInvalidParameters(t *testing.T) {
+	seed, pub, err := subtleSignature.GenerateMLDSAKey("ML_DSA_65")
+	if err != nil {
+		t.Fatalf("GenerateMLDSAKey() err = %v", err)
+	}
+	if _, _, err := subtleSignature.GenerateMLDSAKey("ML_DSA_UNKNOWN_INSTANCE"); err == nil {
+		t.Errorf("GenerateMLDSAKey() with unknown instance succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSASigner("ML_DSA_65", seed[1:]); err == nil {
+		t.Errorf("NewMLDSASigner() with short seed succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSASigner("", seed); err == nil {
+		t.Errorf("NewMLDSASigner() with unknown instance succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSAVerifier("ML_DSA_44", pub); err == nil {
+		t.Errorf("NewMLDSAVerifier() with public key of another instance succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSAVerifier("ML_DSA_65", pub[1:]); err == nil {
+		t.Errorf("NewMLDSAVerifier() with short public key succeeded, want error")
+	}
+}
@@ -0,0 +1,71 @@
+//go:build !go1.27
+// +build !go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+)
+
+// MLDSASeedSize is the size in bytes of an ML-DSA private key seed.
+const MLDSASeedSize = 32
+
+// errMLDSAUnsupported is returned by the ML-DSA functions when the binary is
+// built with a Go version that lacks crypto/mldsa.
+var errMLDSAUnsupported = errors.New("ml_dsa: ML-DSA requires Go 1.27 or later")
+
+// GenerateMLDSAKey is not supported before Go 1.27 and always returns an
+// error.
+func GenerateMLDSAKey(instance string) (seed, publicKey []byte, err error) {
+	return nil, nil, errMLDSAUnsupported
+}
+
+// MLDSAPublicKey is not supported before Go 1.27 and always returns an error.
+func MLDSAPublicKey(instance string, seed []byte) ([]byte, error) {
+	return nil, errMLDSAUnsupported
+}
+
+// MLDSASigner is an implementation of Signer for ML-DSA. It is not supported
+// before Go 1.27.
+type MLDSASigner struct{}
+
+// NewMLDSASigner is not supported before Go 1.27 and always returns an error.
+func NewMLDSASigner(instance string, seed []byte) (*MLDSASigner, error) {
+	return nil, errMLDSAUnsupported
+}
+
+// Sign always returns an error.
+func (s *MLDSASigner) Sign(data []byte) ([]byte, error) {
+	return nil, errMLDSAUnsupported
+}
+
+// MLDSAVerifier is an implementation of Verifier for ML-DSA. It is not
+// supported before Go 1.27.
+type MLDSAVerifier struct{}
+
+// NewMLDSAVerifier is not supported before Go 1.27 and always returns an
+// error.
+func NewMLDSAVerifier(instance string, publicKey []byte) (*MLDSAVerifier, error) {
+	return nil, errMLDSAUnsupported
+}
+
+// Verify always returns an error.
+func (v *MLDSAVerifier) Verify(signature, data []byte) error {
+	return errMLDSAUnsupported
+}
@@ -0,0 +1,41 @@
+//go:build !go1.27
+// +build !go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+)
+
+func TestMLDSAUnsupported(t *testing.T) {
+	if _, _, err := subtleSignature.GenerateMLDSAKey("ML_DSA_65"); err == nil {
+		t.Error("GenerateMLDSAKey() succeeded, want error")
+	}
+	if _, err := subtleSignature.MLDSAPublicKey("ML_DSA_65", make([]byte, subtleSignature.MLDSASeedSize)); err == nil {
+		t.Error("MLDSAPublicKey() succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSASigner("ML_DSA_65", make([]byte, subtleSignature.MLDSASeedSize)); err == nil {
+		t.Error("NewMLDSASigner() succeeded, want error")
+	}
+	if _, err := subtleSignature.NewMLDSAVerifier("ML_DSA_65", make([]byte, 1952)); err == nil {
+		t.Error("NewMLDSAVerifier() succeeded, want error")
+	}
+}
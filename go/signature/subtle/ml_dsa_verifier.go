@@ -0,0 +1,56 @@
+//go:build go1.27
+// +build go1.27
+
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/mldsa"
+	"errors"
+	"fmt"
+)
+
+var errInvalidMLDSASignature = errors.New("ml_dsa_verifier: invalid signature")
+
+// MLDSAVerifier is an implementation of Verifier for ML-DSA.
+type MLDSAVerifier struct {
+	publicKey *mldsa.PublicKey
+}
+
+// NewMLDSAVerifier creates a new instance of MLDSAVerifier for the given
+// parameter set and encoded public key.
+func NewMLDSAVerifier(instance string, publicKey []byte) (*MLDSAVerifier, error) {
+	params, err := mldsaParameters(instance)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_verifier: %s", err)
+	}
+	pub, err := mldsa.NewPublicKey(params, publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("ml_dsa_verifier: %s", err)
+	}
+	return &MLDSAVerifier{publicKey: pub}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (v *MLDSAVerifier) Verify(signature, data []byte) error {
+	if err := mldsa.Verify(v.publicKey, data, signature, nil); err != nil {
+		return errInvalidMLDSASignature
+	}
+	return nil
+}
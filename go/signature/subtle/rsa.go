@@ -17,6 +17,7 @@
 package subtle
 
 import (
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"errors"
@@ -127,6 +128,28 @@ func (r *RSAPrivateKeyData) CreateKey() (*rsa.PrivateKey, error) {
 	return privKey, nil
 }
 
+// rsaHash returns the crypto.Hash for the given hash algorithm name. Only
+// SHA256, SHA384 and SHA512 are accepted for RSA signatures.
+func rsaHash(hashAlg string) (crypto.Hash, error) {
+	switch hashAlg {
+	case "SHA256":
+		return crypto.SHA256, nil
+	case "SHA384":
+		return crypto.SHA384, nil
+	case "SHA512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported hash algorithm: %s", hashAlg)
+	}
+}
+
+// computeRSAHash returns the digest of data computed with h.
+func computeRSAHash(h crypto.Hash, data []byte) []byte {
+	hasher := h.New()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
+
 func validModulusSize(m int) error {
 	if m < 2048 {
 		return errors.New("modulus size too small, must be >= 2048")
@@ -0,0 +1,69 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+)
+
+// RSASSAPKCS1Signer is an implementation of Signer for RSA-SSA-PKCS1 v1.5.
+type RSASSAPKCS1Signer struct {
+	privateKey *rsa.PrivateKey
+	hash       crypto.Hash
+}
+
+// NewRSASSAPKCS1Signer creates a new instance of RSASSAPKCS1Signer.
+func NewRSASSAPKCS1Signer(hashAlg string, privateKey *rsa.PrivateKey) (*RSASSAPKCS1Signer, error) {
+	h, err := rsaHash(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: %s", err)
+	}
+	if err := validModulusSize(privateKey.N.BitLen()); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: %s", err)
+	}
+	if err := validPublicExponent(privateKey.E); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: %s", err)
+	}
+	return &RSASSAPKCS1Signer{
+		privateKey: privateKey,
+		hash:       h,
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *RSASSAPKCS1Signer) Sign(data []byte) ([]byte, error) {
+	return s.SignDigest(computeRSAHash(s.hash, data))
+}
+
+// NewHash returns a new instance of the hash function used by the signer.
+func (s *RSASSAPKCS1Signer) NewHash() hash.Hash {
+	return s.hash.New()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash function returned by NewHash.
+func (s *RSASSAPKCS1Signer) SignDigest(digest []byte) ([]byte, error) {
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, s.hash, digest)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_signer: signing failed: %s", err)
+	}
+	return sig, nil
+}
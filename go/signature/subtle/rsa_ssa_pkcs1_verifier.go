@@ -0,0 +1,69 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+var errInvalidRSASSAPKCS1Signature = errors.New("rsa_ssa_pkcs1_verifier: invalid signature")
+
+// RSASSAPKCS1Verifier is an implementation of Verifier for RSA-SSA-PKCS1 v1.5.
+type RSASSAPKCS1Verifier struct {
+	publicKey *rsa.PublicKey
+	hash      crypto.Hash
+}
+
+// NewRSASSAPKCS1Verifier creates a new instance of RSASSAPKCS1Verifier.
+func NewRSASSAPKCS1Verifier(hashAlg string, publicKey *rsa.PublicKey) (*RSASSAPKCS1Verifier, error) {
+	h, err := rsaHash(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier: %s", err)
+	}
+	if err := (&RSAPublicKeyData{E: publicKey.E, N: publicKey.N}).Validate(); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pkcs1_verifier: %s", err)
+	}
+	return &RSASSAPKCS1Verifier{
+		publicKey: publicKey,
+		hash:      h,
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (v *RSASSAPKCS1Verifier) Verify(signature, data []byte) error {
+	return v.VerifyDigest(signature, computeRSAHash(v.hash, data))
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (v *RSASSAPKCS1Verifier) NewHash() hash.Hash {
+	return v.hash.New()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash function returned by
+// NewHash.
+func (v *RSASSAPKCS1Verifier) VerifyDigest(signature, digest []byte) error {
+	if err := rsa.VerifyPKCS1v15(v.publicKey, v.hash, digest, signature); err != nil {
+		return errInvalidRSASSAPKCS1Signature
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// RSASSAPSSSigner is an implementation of Signer for RSA-SSA-PSS. The same
+// hash function is used for the message digest and for MGF1.
+type RSASSAPSSSigner struct {
+	privateKey *rsa.PrivateKey
+	hash       crypto.Hash
+	saltLength int
+}
+
+// NewRSASSAPSSSigner creates a new instance of RSASSAPSSSigner.
+func NewRSASSAPSSSigner(hashAlg string, saltLength int, privateKey *rsa.PrivateKey) (*RSASSAPSSSigner, error) {
+	h, err := rsaHash(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: %s", err)
+	}
+	// crypto/rsa interprets a zero salt length as "auto", so it cannot be
+	// used to request an empty salt.
+	if saltLength <= 0 {
+		return nil, errors.New("rsa_ssa_pss_signer: invalid salt length")
+	}
+	if err := validModulusSize(privateKey.N.BitLen()); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: %s", err)
+	}
+	if err := validPublicExponent(privateKey.E); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: %s", err)
+	}
+	return &RSASSAPSSSigner{
+		privateKey: privateKey,
+		hash:       h,
+		saltLength: saltLength,
+	}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *RSASSAPSSSigner) Sign(data []byte) ([]byte, error) {
+	return s.SignDigest(computeRSAHash(s.hash, data))
+}
+
+// NewHash returns a new instance of the hash function used by the signer.
+func (s *RSASSAPSSSigner) NewHash() hash.Hash {
+	return s.hash.New()
+}
+
+// SignDigest computes a signature for the given digest, which must have been
+// computed with the hash function returned by NewHash.
+func (s *RSASSAPSSSigner) SignDigest(digest []byte) ([]byte, error) {
+	opts := &rsa.PSSOptions{SaltLength: s.saltLength, Hash: s.hash}
+	sig, err := rsa.SignPSS(rand.Reader, s.privateKey, s.hash, digest, opts)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_signer: signing failed: %s", err)
+	}
+	return sig, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+var errInvalidRSASSAPSSSignature = errors.New("rsa_ssa_pss_verifier: invalid signature")
+
+// RSASSAPSSVerifier is an implementation of Verifier for RSA-SSA-PSS. The
+// same hash function is used for the message digest and for MGF1.
+type RSASSAPSSVerifier struct {
+	publicKey  *rsa.PublicKey
+	hash       crypto.Hash
+	saltLength int
+}
+
+// NewRSASSAPSSVerifier creates a new instance of RSASSAPSSVerifier.
+func NewRSASSAPSSVerifier(hashAlg string, saltLength int, publicKey *rsa.PublicKey) (*RSASSAPSSVerifier, error) {
+	h, err := rsaHash(hashAlg)
+	if err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier: %s", err)
+	}
+	// crypto/rsa interprets a zero salt length as "auto", so it cannot be
+	// used to request an empty salt.
+	if saltLength <= 0 {
+		return nil, errors.New("rsa_ssa_pss_verifier: invalid salt length")
+	}
+	if err := (&RSAPublicKeyData{E: publicKey.E, N: publicKey.N}).Validate(); err != nil {
+		return nil, fmt.Errorf("rsa_ssa_pss_verifier: %s", err)
+	}
+	return &RSASSAPSSVerifier{
+		publicKey:  publicKey,
+		hash:       h,
+		saltLength: saltLength,
+	}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+// It returns an error if the signature is not valid; nil otherwise.
+func (v *RSASSAPSSVerifier) Verify(signature, data []byte) error {
+	return v.VerifyDigest(signature, computeRSAHash(v.hash, data))
+}
+
+// NewHash returns a new instance of the hash function used by the verifier.
+func (v *RSASSAPSSVerifier) NewHash() hash.Hash {
+	return v.hash.New()
+}
+
+// VerifyDigest verifies whether the given signature is valid for the given
+// digest, which must have been computed with the hash function returned by
+// NewHash.
+func (v *RSASSAPSSVerifier) VerifyDigest(signature, digest []byte) error {
+	opts := &rsa.PSSOptions{SaltLength: v.saltLength, Hash: v.hash}
+	if err := rsa.VerifyPSS(v.publicKey, v.hash, digest, signature, opts); err != nil {
+		return errInvalidRSASSAPSSSignature
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestRSASSASignVerify(t *testing.T) {
+	priv, err := subtleSignature.GenerateRSAKey(2048, 65537)
+	if err != nil {
+		t.Fatalf("GenerateRSAKey() failed: %v", err)
+	}
+	for _, hash := range []string{"SHA256", "SHA384", "SHA512"} {
+		pkcs1Signer, err := subtleSignature.NewRSASSAPKCS1Signer(hash, priv)
+		if err != nil {
+			t.Fatalf("NewRSASSAPKCS1Signer(%s) failed: %v", hash, err)
+		}
+		pkcs1Verifier, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, &priv.PublicKey)
+		if err != nil {
+			t.Fatalf("NewRSASSAPKCS1Verifier(%s) failed: %v", hash, err)
+		}
+		pssSigner, err := subtleSignature.NewRSASSAPSSSigner(hash, 32, priv)
+		if err != nil {
+			t.Fatalf("NewRSASSAPSSSigner(%s) failed: %v", hash, err)
+		}
+		pssVerifier, err := subtleSignature.NewRSASSAPSSVerifier(hash, 32, &priv.PublicKey)
+		if err != nil {
+			t.Fatalf("NewRSASSAPSSVerifier(%s) failed: %v", hash, err)
+		}
+		otherSaltVerifier, err := subtleSignature.NewRSASSAPSSVerifier(hash, 20, &priv.PublicKey)
+		if err != nil {
+			t.Fatalf("NewRSASSAPSSVerifier(%s) failed: %v", hash, err)
+		}
+
+		data := random.GetRandomBytes(20)
+		sig, err := pkcs1Signer.Sign(data)
+		if err != nil {
+			t.Fatalf("pkcs1Signer.Sign() failed: %v", err)
+		}
+		if err := pkcs1Verifier.Verify(sig, data); err != nil {
+			t.Errorf("pkcs1Verifier.Verify() failed: %v", err)
+		}
+		if err := pkcs1Verifier.Verify(sig, append(data, 0)); err == nil {
+			t.Errorf("pkcs1Verifier.Verify() succeeded with modified data")
+		}
+		if err := pssVerifier.Verify(sig, data); err == nil {
+			t.Errorf("pssVerifier.Verify() succeeded with a PKCS1 signature")
+		}
+
+		sig, err = pssSigner.Sign(data)
+		if err != nil {
+			t.Fatalf("pssSigner.Sign() failed: %v", err)
+		}
+		if err := pssVerifier.Verify(sig, data); err != nil {
+			t.Errorf("pssVerifier.Verify() failed: %v", err)
+		}
+		if err := pssVerifier.Verify(sig, append(data, 0)); err == nil {
+			t.Errorf("pssVerifier.Verify() succeeded with modified data")
+		}
+		if err := otherSaltVerifier.Verify(sig, data); err == nil {
+			t.Errorf("otherSaltVerifier.Verify() succeeded with a different salt length")
+		}
+		if err := pkcs1Verifier.Verify(sig, data); err == nil {
+			t.Errorf("pkcs1Verifier.Verify() succeeded with a PSS signature")
+		}
+	}
+}
+
+func TestRSASSAInvalidParams(t *testing.T) {
+	priv, err := subtleSignature.GenerateRSAKey(2048, 65537)
+	if err != nil {
+		t.Fatalf("GenerateRSAKey() failed: %v", err)
+	}
+	for _, hash := range []string{"SHA1", "SHA224", "SHA3_256", ""} {
+		if _, err := subtleSignature.NewRSASSAPKCS1Signer(hash, priv); err == nil {
+			t.Errorf("NewRSASSAPKCS1Signer(%q) succeeded, want error", hash)
+		}
+		if _, err := subtleSignature.NewRSASSAPKCS1Verifier(hash, &priv.PublicKey); err == nil {
+			t.Errorf("NewRSASSAPKCS1Verifier(%q) succeeded, want error", hash)
+		}
+		if _, err := subtleSignature.NewRSASSAPSSSigner(hash, 32, priv); err == nil {
+			t.Errorf("NewRSASSAPSSSigner(%q) succeeded, want error", hash)
+		}
+		if _, err := subtleSignature.NewRSASSAPSSVerifier(hash, 32, &priv.PublicKey); err == nil {
+			t.Errorf("NewRSASSAPSSVerifier(%q) succeeded, want error", hash)
+		}
+	}
+	for _, saltLength := range []int{-1, 0} {
+		if _, err := subtleSignature.NewRSASSAPSSSigner("SHA256", saltLength, priv); err == nil {
+			t.Errorf("NewRSASSAPSSSigner(saltLength = %d) succeeded, want error", saltLength)
+		}
+		if _, err := subtleSignature.NewRSASSAPSSVerifier("SHA256", saltLength, &priv.PublicKey); err == nil {
+			t.Errorf("NewRSASSAPSSVerifier(saltLength = %d) succeeded, want error", saltLength)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/tink/go/subtle"
+)
+
+// secp256k1Enabled is non-zero once EnableSECP256K1 has been called.
+var secp256k1Enabled int32
+
+// EnableSECP256K1 allows ECDSA public keys on the secp256k1 curve, which are
+// rejected by default. The curve is mainly used in blockchain ecosystems, and
+// is only supported with SHA-256.
+//
+// Arithmetic on secp256k1 is not constant time, so the curve can only be used
+// to verify signatures; signers and key generation reject it even once it is
+// enabled. Once enabled, the curve cannot be disabled again.
+func EnableSECP256K1() {
+	atomic.StoreInt32(&secp256k1Enabled, 1)
+}
+
+func secp256k1IsEnabled() bool {
+	return atomic.LoadInt32(&secp256k1Enabled) != 0
+}
+
+// GetECDSACurve returns the curve with the given name, e.g. "NIST_P256" or
+// "SECP256K1". It returns nil if the curve is not supported.
+func GetECDSACurve(curve string) elliptic.Curve {
+	if curve == "SECP256K1" {
+		return SECP256K1()
+	}
+	return subtle.GetCurve(curve)
+}
+
+var (
+	initSECP256K1 sync.Once
+	secp256k1     *secp256k1Curve
+)
+
+// SECP256K1 returns a Curve which implements secp256k1 as specified in SEC 2,
+// section 2.4.1.
+func SECP256K1() elliptic.Curve {
+	initSECP256K1.Do(func() {
+		params := &elliptic.CurveParams{Name: "secp256k1", BitSize: 256}
+		params.P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+		params.N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+		params.B = big.NewInt(7)
+		params.Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+		params.Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+		secp256k1 = &secp256k1Curve{params: params}
+	})
+	return secp256k1
+}
+
+// secp256k1Curve implements elliptic.Curve for y² = x³ + 7. The generic
+// implementation in crypto/elliptic assumes a = -3 and cannot be used.
+// Points are handled in Jacobian coordinates; the point at infinity has
+// z = 0, and (0, 0) in affine coordinates.
+type secp256k1Curve struct {
+	params *elliptic.CurveParams
+}
+
+func (c *secp256k1Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+func (c *secp256k1Curve) IsOnCurve(x, y *big.Int) bool {
+	p := c.params.P
+	if x.Sign() < 0 || x.Cmp(p) >= 0 || y.Sign() < 0 || y.Cmp(p) >= 0 {
+		return false
+	}
+	// y² = x³ + 7
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, p)
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, c.params.B)
+	x3.Mod(x3, p)
+	return x3.Cmp(y2) == 0
+}
+
+// jacobianPoint is a point (x/z², y/z³).
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func (c *secp256k1Curve) toJacobian(x, y *big.Int) *jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return &jacobianPoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+func (c *secp256k1Curve) toAffine(p *jacobianPoint) (*big.Int, *big.Int) {
+	if p.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	prime := c.params.P
+	zInv := new(big.Int).ModInverse(p.z, prime)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x := new(big.Int).Mul(p.x, zInv2)
+	x.Mod(x, prime)
+	zInv2.Mul(zInv2, zInv)
+	y := new(big.Int).Mul(p.y, zInv2)
+	y.Mod(y, prime)
+	return x, y
+}
+
+// double uses the "dbl-2009-l" formulas for a = 0.
+func (c *secp256k1Curve) double(p *jacobianPoint) *jacobianPoint {
+	if p.z.Sign() == 0 || p.y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	prime := c.params.P
+	a := new(big.Int).Mul(p.x, p.x)
+	a.Mod(a, prime)
+	b := new(big.Int).Mul(p.y, p.y)
+	b.Mod(b, prime)
+	cc := new(big.Int).Mul(b, b)
+	cc.Mod(cc, prime)
+	// d = 2 * ((x + b)² - a - cc)
+	d := new(big.Int).Add(p.x, b)
+	d.Mul(d, d)
+	d.Sub(d, a)
+	d.Sub(d, cc)
+	d.Lsh(d, 1)
+	d.Mod(d, prime)
+	e := new(big.Int).Lsh(a, 1)
+	e.Add(e, a)
+	f := new(big.Int).Mul(e, e)
+
+	x3 := new(big.Int).Sub(f, new(big.Int).Lsh(d, 1))
+	x3.Mod(x3, prime)
+	y3 := new(big.Int).Sub(d, x3)
+	y3.Mul(y3, e)
+	y3.Sub(y3, new(big.Int).Lsh(cc, 3))
+	y3.Mod(y3, prime)
+	z3 := new(big.Int).Mul(p.y, p.z)
+	z3.Lsh(z3, 1)
+	z3.Mod(z3, prime)
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// add uses the "add-2007-bl" formulas.
+func (c *secp256k1Curve) add(p, q *jacobianPoint) *jacobianPoint {
+	if p.z.Sign() == 0 {
+		return q
+	}
+	if q.z.Sign() == 0 {
+		return p
+	}
+	prime := c.params.P
+	z1z1 := new(big.Int).Mul(p.z, p.z)
+	z1z1.Mod(z1z1, prime)
+	z2z2 := new(big.Int).Mul(q.z, q.z)
+	z2z2.Mod(z2z2, prime)
+	u1 := new(big.Int).Mul(p.x, z2z2)
+	u1.Mod(u1, prime)
+	u2 := new(big.Int).Mul(q.x, z1z1)
+	u2.Mod(u2, prime)
+	s1 := new(big.Int).Mul(p.y, q.z)
+	s1.Mul(s1, z2z2)
+	s1.Mod(s1, prime)
+	s2 := new(big.Int).Mul(q.y, p.z)
+	s2.Mul(s2, z1z1)
+	s2.Mod(s2, prime)
+	h := new(big.Int).Sub(u2, u1)
+	h.Mod(h, prime)
+	r := new(big.Int).Sub(s2, s1)
+	r.Lsh(r, 1)
+	r.Mod(r, prime)
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.double(p)
+		}
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	i := new(big.Int).Lsh(h, 1)
+	i.Mul(i, i)
+	j := new(big.Int).Mul(h, i)
+	v := new(big.Int).Mul(u1, i)
+
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	x3.Mod(x3, prime)
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	s1.Mul(s1, j)
+	s1.Lsh(s1, 1)
+	y3.Sub(y3, s1)
+	y3.Mod(y3, prime)
+	z3 := new(big.Int).Add(p.z, q.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	z3.Mod(z3, prime)
+	return &jacobianPoint{x3, y3, z3}
+}
+
+func (c *secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.add(c.toJacobian(x1, y1), c.toJacobian(x2, y2)))
+}
+
+func (c *secp256k1Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
+	return c.toAffine(c.double(c.toJacobian(x1, y1)))
+}
+
+func (c *secp256k1Curve) ScalarMult(x1, y1 *big.Int, k []byte) (*big.Int, *big.Int) {
+	base := c.toJacobian(x1, y1)
+	acc := &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	for _, b := range k {
+		for bit := 7; bit >= 0; bit-- {
+			acc = c.double(acc)
+			if (b>>uint(bit))&1 == 1 {
+				acc = c.add(acc, base)
+			}
+		}
+	}
+	return c.toAffine(acc)
+}
+
+func (c *secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	return c.ScalarMult(c.params.Gx, c.params.Gy, k)
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+)
+
+// A key pair and signature of secp256k1Message generated with OpenSSL.
+const (
+	secp256k1PrivateKey = "0acd117a54b41ac1a8470537d5fd38a6507de7ee90763ea566313ba1d90b485b"
+	secp256k1PublicX    = "d8a799176153d17ceca361f4bf47a23dd39dc8318275c352787c3b17248de83d"
+	secp256k1PublicY    = "f588594b33a6fe236d3f9d8359a7628a9f126f75445c9ecbdc47e51674fc45c3"
+	secp256k1Signature  = "30460221009c7c29a13a18ca397aae7a7ef272b3e44f3c95db6cd4cdb6bd7381a3b50ba72202210089d2587de335b9c6b82f00a619992bf2281902014bc3e7e45cf5891d3ffce1f9"
+	secp256k1Message    = "Hello, blockchain!"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) err = %v", s, err)
+	}
+	return b
+}
+
+// This test must run before any other test enables the curve.
+func TestSECP256K1RequiresOptIn(t *testing.T) {
+	x, y := mustDecodeHex(t, secp256k1PublicX), mustDecodeHex(t, secp256k1PublicY)
+	if _, err := subtleSignature.NewECDSAVerifier("SHA256", "SECP256K1", "DER", x, y); err == nil {
+		t.Errorf("NewECDSAVerifier() with SECP256K1 succeeded before EnableSECP256K1()")
+	}
+	if err := subtleSignature.ValidateECDSAParams("SHA256", "SECP256K1", "DER"); err == nil {
+		t.Errorf("ValidateECDSAParams() with SECP256K1 succeeded before EnableSECP256K1()")
+	}
+	subtleSignature.EnableSECP256K1()
+	if err := subtleSignature.ValidateECDSAParams("SHA256", "SECP256K1", "DER"); err != nil {
+		t.Errorf("ValidateECDSAParams() with SECP256K1 err = %v", err)
+	}
+	if err := subtleSignature.ValidateECDSAParams("SHA512", "SECP256K1", "DER"); err == nil {
+		t.Errorf("ValidateECDSAParams() with SECP256K1 and SHA512 succeeded, want error")
+	}
+}
+
+func TestSECP256K1Arithmetic(t *testing.T) {
+	c := subtleSignature.SECP256K1()
+	params := c.Params()
+	if !c.IsOnCurve(params.Gx, params.Gy) {
+		t.Errorf("generator is not on the curve")
+	}
+	// 2G, from SEC 2 test data.
+	wantX, _ := new(big.Int).SetString("c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", 16)
+	wantY, _ := new(big.Int).SetString("1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a", 16)
+	for name, f := range map[string]func() (*big.Int, *big.Int){
+		"Double":         func() (*big.Int, *big.Int) { return c.Double(params.Gx, params.Gy) },
+		"Add":            func() (*big.Int, *big.Int) { return c.Add(params.Gx, params.Gy, params.Gx, params.Gy) },
+		"ScalarBaseMult": func() (*big.Int, *big.Int) { return c.ScalarBaseMult([]byte{2}) },
+	} {
+		x, y := f()
+		if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Errorf("%s() = (%x, %x), want (%x, %x)", name, x, y, wantX, wantY)
+		}
+	}
+	// G + (-G) and N*G are the point at infinity.
+	negY := new(big.Int).Sub(params.P, params.Gy)
+	if x, y := c.Add(params.Gx, params.Gy, params.Gx, negY); x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("G + (-G) = (%x, %x), want (0, 0)", x, y)
+	}
+	if x, y := c.ScalarBaseMult(params.N.Bytes()); x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("N * G = (%x, %x), want (0, 0)", x, y)
+	}
+	x, y := c.ScalarBaseMult(mustDecodeHex(t, secp256k1PrivateKey))
+	if hex.EncodeToString(x.Bytes()) != secp256k1PublicX || hex.EncodeToString(y.Bytes()) != secp256k1PublicY {
+		t.Errorf("ScalarBaseMult(privateKey) = (%x, %x), want (%s, %s)", x, y, secp256k1PublicX, secp256k1PublicY)
+	}
+	if c.IsOnCurve(x, new(big.Int).Add(y, big.NewInt(1))) {
+		t.Errorf("IsOnCurve() of modified point = true, want false")
+	}
+}
+
+func TestSECP256K1VerifyExternalSignature(t *testing.T) {
+	subtleSignature.EnableSECP256K1()
+	x, y := mustDecodeHex(t, secp256k1PublicX), mustDecodeHex(t, secp256k1PublicY)
+	verifier, err := subtleSignature.NewECDSAVerifier("SHA256", "SECP256K1", "DER", x, y)
+	if err != nil {
+		t.Fatalf("NewECDSAVerifier() err = %v", err)
+	}
+	sig := mustDecodeHex(t, secp256k1Signature)
+	if err := verifier.Verify(sig, []byte(secp256k1Message)); err != nil {
+		t.Errorf("verifier.Verify() err = %v", err)
+	}
+	if err := verifier.Verify(sig, []byte("other message")); err == nil {
+		t.Errorf("verifier.Verify() with other message succeeded")
+	}
+
+	// Points that are not on the curve are rejected.
+	y[len(y)-1] ^= 1
+	if _, err := subtleSignature.NewECDSAVerifier("SHA256", "SECP256K1", "DER", x, y); err == nil {
+		t.Errorf("NewECDSAVerifier() with invalid point succeeded")
+	}
+}
+
+func TestSECP256K1SignerIsRejected(t *testing.T) {
+	subtleSignature.EnableSECP256K1()
+	priv := mustDecodeHex(t, secp256k1PrivateKey)
+	if _, err := subtleSignature.NewECDSASigner("SHA256", "SECP256K1", "DER", priv); err == nil {
+		t.Errorf("NewECDSASigner() with SECP256K1 succeeded, want error")
+	}
+	if _, err := subtleSignature.NewDeterministicECDSASigner("SHA256", "SECP256K1", "DER", priv); err == nil {
+		t.Errorf("NewDeterministicECDSASigner() with SECP256K1 succeeded, want error")
+	}
+	privKey := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(priv)}
+	privKey.Curve = subtleSignature.SECP256K1()
+	privKey.X, privKey.Y = privKey.Curve.ScalarBaseMult(priv)
+	if _, err := subtleSignature.NewECDSASignerFromPrivateKey("SHA256", "DER", privKey); err == nil {
+		t.Errorf("NewECDSASignerFromPrivateKey() with SECP256K1 succeeded, want error")
+	}
+}
@@ -0,0 +1,618 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/sha3"
+)
+
+// This file implements the stateless hash-based signature scheme SLH-DSA as
+// specified in FIPS 205, for the parameter sets of security category 1
+// (n = 16). Section and algorithm numbers refer to FIPS 205.
+
+const (
+	// SLHDSAPrivateKeySize is the size in bytes of an SLH-DSA private key:
+	// SK.seed || SK.prf || PK.seed || PK.root.
+	SLHDSAPrivateKeySize = 4 * slhdsaN
+	// SLHDSAPublicKeySize is the size in bytes of an SLH-DSA public key:
+	// PK.seed || PK.root.
+	SLHDSAPublicKeySize = 2 * slhdsaN
+
+	slhdsaN    = 16
+	slhdsaLgW  = 4
+	slhdsaW    = 1 << slhdsaLgW
+	slhdsaLen1 = 2 * slhdsaN
+	slhdsaLen2 = 3
+	slhdsaLen  = slhdsaLen1 + slhdsaLen2
+)
+
+// Address types (section 4.2).
+const (
+	slhdsaWOTSHash  = 0
+	slhdsaWOTSPK    = 1
+	slhdsaTree      = 2
+	slhdsaFORSTree  = 3
+	slhdsaFORSRoots = 4
+	slhdsaWOTSPRF   = 5
+	slhdsaFORSPRF   = 6
+)
+
+// slhdsaParams is an SLH-DSA parameter set (section 11).
+type slhdsaParams struct {
+	name  string
+	shake bool
+	h     int // total tree height
+	d     int // number of layers
+	hp    int // height of each XMSS tree
+	a     int // height of each FORS tree
+	k     int // number of FORS trees
+	m     int // message digest size in bytes
+}
+
+var slhdsaParamSets = map[string]*slhdsaParams{
+	"SLH_DSA_SHA2_128S":  {name: "SLH-DSA-SHA2-128s", h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30},
+	"SLH_DSA_SHA2_128F":  {name: "SLH-DSA-SHA2-128f", h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34},
+	"SLH_DSA_SHAKE_128S": {name: "SLH-DSA-SHAKE-128s", shake: true, h: 63, d: 7, hp: 9, a: 12, k: 14, m: 30},
+	"SLH_DSA_SHAKE_128F": {name: "SLH-DSA-SHAKE-128f", shake: true, h: 66, d: 22, hp: 3, a: 6, k: 33, m: 34},
+}
+
+// slhdsaParameters returns the parameter set with the given name, e.g.
+// "SLH_DSA_SHA2_128S".
+func slhdsaParameters(instance string) (*slhdsaParams, error) {
+	p, ok := slhdsaParamSets[instance]
+	if !ok {
+		return nil, fmt.Errorf("unsupported SLH-DSA instance: %s", instance)
+	}
+	return p, nil
+}
+
+// SLHDSASignatureSize returns the size in bytes of signatures of the given
+// SLH-DSA parameter set.
+func SLHDSASignatureSize(instance string) (int, error) {
+	p, err := slhdsaParameters(instance)
+	if err != nil {
+		return 0, err
+	}
+	return p.signatureSize(), nil
+}
+
+func (p *slhdsaParams) signatureSize() int {
+	return (1 + p.k*(1+p.a) + p.h + p.d*slhdsaLen) * slhdsaN
+}
+
+func (p *slhdsaParams) forsSignatureSize() int {
+	return p.k * (1 + p.a) * slhdsaN
+}
+
+func (p *slhdsaParams) xmssSignatureSize() int {
+	return (slhdsaLen + p.hp) * slhdsaN
+}
+
+// slhdsaAddress is the 32-byte ADRS structure (section 4.2).
+type slhdsaAddress [32]byte
+
+func (a *slhdsaAddress) setLayerAddress(l uint32) {
+	binary.BigEndian.PutUint32(a[0:4], l)
+}
+
+func (a *slhdsaAddress) setTreeAddress(t uint64) {
+	// The tree address is 12 bytes long, but never exceeds 64 bits for the
+	// supported parameter sets.
+	binary.BigEndian.PutUint32(a[4:8], 0)
+	binary.BigEndian.PutUint64(a[8:16], t)
+}
+
+func (a *slhdsaAddress) setTypeAndClear(t uint32) {
+	binary.BigEndian.PutUint32(a[16:20], t)
+	for i := 20; i < 32; i++ {
+		a[i] = 0
+	}
+}
+
+func (a *slhdsaAddress) setKeyPairAddress(i uint32) {
+	binary.BigEndian.PutUint32(a[20:24], i)
+}
+
+func (a *slhdsaAddress) keyPairAddress() uint32 {
+	return binary.BigEndian.Uint32(a[20:24])
+}
+
+func (a *slhdsaAddress) setChainAddress(i uint32) {
+	binary.BigEndian.PutUint32(a[24:28], i)
+}
+
+func (a *slhdsaAddress) setTreeHeight(z uint32) {
+	binary.BigEndian.PutUint32(a[24:28], z)
+}
+
+func (a *slhdsaAddress) setHashAddress(i uint32) {
+	binary.BigEndian.PutUint32(a[28:32], i)
+}
+
+func (a *slhdsaAddress) setTreeIndex(i uint32) {
+	binary.BigEndian.PutUint32(a[28:32], i)
+}
+
+func (a *slhdsaAddress) treeIndex() uint32 {
+	return binary.BigEndian.Uint32(a[28:32])
+}
+
+// compressed returns the 22-byte compressed address used by the SHA2
+// parameter sets (section 11.2).
+func (a *slhdsaAddress) compressed() []byte {
+	c := make([]byte, 0, 22)
+	c = append(c, a[3])
+	c = append(c, a[8:16]...)
+	c = append(c, a[19])
+	return append(c, a[20:32]...)
+}
+
+// slhdsaContext holds the public seed and the hash functions of a parameter
+// set (sections 10 and 11).
+type slhdsaContext struct {
+	*slhdsaParams
+	pkSeed []byte
+	skSeed []byte
+	sha    hash.Hash
+}
+
+func newSLHDSAContext(p *slhdsaParams, pkSeed, skSeed []byte) *slhdsaContext {
+	return &slhdsaContext{slhdsaParams: p, pkSeed: pkSeed, skSeed: skSeed, sha: sha256.New()}
+}
+
+// thash implements F, H and T_l, which only differ in the length of their
+// input.
+func (c *slhdsaContext) thash(adrs *slhdsaAddress, in ...[]byte) []byte {
+	if c.shake {
+		h := sha3.NewShake256()
+		h.Write(c.pkSeed)
+		h.Write(adrs[:])
+		for _, b := range in {
+			h.Write(b)
+		}
+		out := make([]byte, slhdsaN)
+		h.Read(out)
+		return out
+	}
+	var zeros [64 - slhdsaN]byte
+	c.sha.Reset()
+	c.sha.Write(c.pkSeed)
+	c.sha.Write(zeros[:])
+	c.sha.Write(adrs.compressed())
+	for _, b := range in {
+		c.sha.Write(b)
+	}
+	return c.sha.Sum(nil)[:slhdsaN]
+}
+
+// prf derives the secret values of WOTS+ and FORS keys.
+func (c *slhdsaContext) prf(adrs *slhdsaAddress) []byte {
+	return c.thash(adrs, c.skSeed)
+}
+
+// prfMsg derives the randomizer R of a signature.
+func (c *slhdsaContext) prfMsg(skPRF, optRand, msg []byte) []byte {
+	if c.shake {
+		h := sha3.NewShake256()
+		h.Write(skPRF)
+		h.Write(optRand)
+		h.Write(msg)
+		out := make([]byte, slhdsaN)
+		h.Read(out)
+		return out
+	}
+	mac := hmac.New(sha256.New, skPRF)
+	mac.Write(optRand)
+	mac.Write(msg)
+	return mac.Sum(nil)[:slhdsaN]
+}
+
+// hashMsg computes the m-byte message digest H_msg.
+func (c *slhdsaContext) hashMsg(r, pkRoot, msg []byte) []byte {
+	out := make([]byte, c.m)
+	if c.shake {
+		h := sha3.NewShake256()
+		h.Write(r)
+		h.Write(c.pkSeed)
+		h.Write(pkRoot)
+		h.Write(msg)
+		h.Read(out)
+		return out
+	}
+	h := sha256.New()
+	h.Write(r)
+	h.Write(c.pkSeed)
+	h.Write(pkRoot)
+	h.Write(msg)
+	seed := append(append(append([]byte{}, r...), c.pkSeed...), h.Sum(nil)...)
+	// MGF1-SHA-256
+	var counter [4]byte
+	for i, off := uint32(0), 0; off < len(out); i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter[:])
+		off += copy(out[off:], h.Sum(nil))
+	}
+	return out
+}
+
+// base2b splits x into outLen integers of b bits each (algorithm 4).
+func base2b(x []byte, b, outLen int) []uint32 {
+	out := make([]uint32, outLen)
+	in, bits, total := 0, 0, uint64(0)
+	for i := range out {
+		for bits < b {
+			total = total<<8 | uint64(x[in])
+			in++
+			bits += 8
+		}
+		bits -= b
+		out[i] = uint32(total>>uint(bits)) & (1<<uint(b) - 1)
+	}
+	return out
+}
+
+// chain computes s iterations of F on x starting at index i (algorithm 5).
+func (c *slhdsaContext) chain(x []byte, i, s uint32, adrs *slhdsaAddress) []byte {
+	tmp := x
+	for j := i; j < i+s; j++ {
+		adrs.setHashAddress(j)
+		tmp = c.thash(adrs, tmp)
+	}
+	return tmp
+}
+
+// wotsMessage returns the base-w message and checksum digits signed by WOTS+.
+func wotsMessage(m []byte) []uint32 {
+	msg := base2b(m, slhdsaLgW, slhdsaLen1)
+	csum := uint32(0)
+	for _, v := range msg {
+		csum += slhdsaW - 1 - v
+	}
+	csum <<= (8 - (slhdsaLen2*slhdsaLgW)%8) % 8
+	csumBytes := []byte{byte(csum >> 8), byte(csum)}
+	return append(msg, base2b(csumBytes, slhdsaLgW, slhdsaLen2)...)
+}
+
+// wotsSecret derives the secret value of chain i of the WOTS+ key at adrs.
+func (c *slhdsaContext) wotsSecret(adrs *slhdsaAddress, i uint32) []byte {
+	skADRS := *adrs
+	skADRS.setTypeAndClear(slhdsaWOTSPRF)
+	skADRS.setKeyPairAddress(adrs.keyPairAddress())
+	skADRS.setChainAddress(i)
+	return c.prf(&skADRS)
+}
+
+// wotsCompress compresses the chain ends into a WOTS+ public key.
+func (c *slhdsaContext) wotsCompress(adrs *slhdsaAddress, tmp [][]byte) []byte {
+	pkADRS := *adrs
+	pkADRS.setTypeAndClear(slhdsaWOTSPK)
+	pkADRS.setKeyPairAddress(adrs.keyPairAddress())
+	return c.thash(&pkADRS, tmp...)
+}
+
+// wotsPKGen generates a WOTS+ public key (algorithm 6).
+func (c *slhdsaContext) wotsPKGen(adrs *slhdsaAddress) []byte {
+	tmp := make([][]byte, slhdsaLen)
+	for i := range tmp {
+		sk := c.wotsSecret(adrs, uint32(i))
+		adrs.setChainAddress(uint32(i))
+		tmp[i] = c.chain(sk, 0, slhdsaW-1, adrs)
+	}
+	return c.wotsCompress(adrs, tmp)
+}
+
+// wotsSign signs the n-byte message m with WOTS+ (algorithm 7).
+func (c *slhdsaContext) wotsSign(m []byte, adrs *slhdsaAddress) []byte {
+	msg := wotsMessage(m)
+	sig := make([]byte, 0, slhdsaLen*slhdsaN)
+	for i, v := range msg {
+		sk := c.wotsSecret(adrs, uint32(i))
+		adrs.setChainAddress(uint32(i))
+		sig = append(sig, c.chain(sk, 0, v, adrs)...)
+	}
+	return sig
+}
+
+// wotsPKFromSig computes a WOTS+ public key from a signature (algorithm 8).
+func (c *slhdsaContext) wotsPKFromSig(sig, m []byte, adrs *slhdsaAddress) []byte {
+	msg := wotsMessage(m)
+	tmp := make([][]byte, slhdsaLen)
+	for i, v := range msg {
+		adrs.setChainAddress(uint32(i))
+		tmp[i] = c.chain(sig[i*slhdsaN:(i+1)*slhdsaN], v, slhdsaW-1-v, adrs)
+	}
+	return c.wotsCompress(adrs, tmp)
+}
+
+// xmssNode computes the node at height z and index i of an XMSS tree
+// (algorithm 9).
+func (c *slhdsaContext) xmssNode(i, z uint32, adrs *slhdsaAddress) []byte {
+	if z == 0 {
+		adrs.setTypeAndClear(slhdsaWOTSHash)
+		adrs.setKeyPairAddress(i)
+		return c.wotsPKGen(adrs)
+	}
+	lnode := c.xmssNode(2*i, z-1, adrs)
+	rnode := c.xmssNode(2*i+1, z-1, adrs)
+	adrs.setTypeAndClear(slhdsaTree)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	return c.thash(adrs, lnode, rnode)
+}
+
+// xmssSign signs the n-byte message m with leaf idx of an XMSS tree
+// (algorithm 10).
+func (c *slhdsaContext) xmssSign(m []byte, idx uint32, adrs *slhdsaAddress) []byte {
+	var auth []byte
+	for j := 0; j < c.hp; j++ {
+		k := (idx >> uint(j)) ^ 1
+		auth = append(auth, c.xmssNode(k, uint32(j), adrs)...)
+	}
+	adrs.setTypeAndClear(slhdsaWOTSHash)
+	adrs.setKeyPairAddress(idx)
+	return append(c.wotsSign(m, adrs), auth...)
+}
+
+// xmssPKFromSig computes the root of an XMSS tree from a signature
+// (algorithm 11).
+func (c *slhdsaContext) xmssPKFromSig(idx uint32, sig, m []byte, adrs *slhdsaAddress) []byte {
+	adrs.setTypeAndClear(slhdsaWOTSHash)
+	adrs.setKeyPairAddress(idx)
+	wotsSig, auth := sig[:slhdsaLen*slhdsaN], sig[slhdsaLen*slhdsaN:]
+	node := c.wotsPKFromSig(wotsSig, m, adrs)
+	adrs.setTypeAndClear(slhdsaTree)
+	adrs.setTreeIndex(idx)
+	for k := 0; k < c.hp; k++ {
+		adrs.setTreeHeight(uint32(k + 1))
+		authK := auth[k*slhdsaN : (k+1)*slhdsaN]
+		if (idx>>uint(k))&1 == 0 {
+			adrs.setTreeIndex(adrs.treeIndex() / 2)
+			node = c.thash(adrs, node, authK)
+		} else {
+			adrs.setTreeIndex((adrs.treeIndex() - 1) / 2)
+			node = c.thash(adrs, authK, node)
+		}
+	}
+	return node
+}
+
+// htSign signs the n-byte message m with the hypertree (algorithm 12).
+func (c *slhdsaContext) htSign(m []byte, idxTree uint64, idxLeaf uint32) []byte {
+	var adrs slhdsaAddress
+	adrs.setTreeAddress(idxTree)
+	sigTmp := c.xmssSign(m, idxLeaf, &adrs)
+	sig := sigTmp
+	root := c.xmssPKFromSig(idxLeaf, sigTmp, m, &adrs)
+	for j := 1; j < c.d; j++ {
+		idxLeaf = uint32(idxTree & (1<<uint(c.hp) - 1))
+		idxTree >>= uint(c.hp)
+		adrs.setLayerAddress(uint32(j))
+		adrs.setTreeAddress(idxTree)
+		sigTmp = c.xmssSign(root, idxLeaf, &adrs)
+		sig = append(sig, sigTmp...)
+		if j < c.d-1 {
+			root = c.xmssPKFromSig(idxLeaf, sigTmp, root, &adrs)
+		}
+	}
+	return sig
+}
+
+// htVerify verifies a hypertree signature of the n-byte message m
+// (algorithm 13).
+func (c *slhdsaContext) htVerify(m, sig []byte, idxTree uint64, idxLeaf uint32, pkRoot []byte) bool {
+	var adrs slhdsaAddress
+	adrs.setTreeAddress(idxTree)
+	size := c.xmssSignatureSize()
+	node := c.xmssPKFromSig(idxLeaf, sig[:size], m, &adrs)
+	for j := 1; j < c.d; j++ {
+		idxLeaf = uint32(idxTree & (1<<uint(c.hp) - 1))
+		idxTree >>= uint(c.hp)
+		adrs.setLayerAddress(uint32(j))
+		adrs.setTreeAddress(idxTree)
+		node = c.xmssPKFromSig(idxLeaf, sig[j*size:(j+1)*size], node, &adrs)
+	}
+	return subtle.ConstantTimeCompare(node, pkRoot) == 1
+}
+
+// forsSKGen derives the FORS secret value with index idx (algorithm 14).
+func (c *slhdsaContext) forsSKGen(adrs *slhdsaAddress, idx uint32) []byte {
+	skADRS := *adrs
+	skADRS.setTypeAndClear(slhdsaFORSPRF)
+	skADRS.setKeyPairAddress(adrs.keyPairAddress())
+	skADRS.setTreeIndex(idx)
+	return c.prf(&skADRS)
+}
+
+// forsNode computes the node at height z and index i of the FORS trees
+// (algorithm 15).
+func (c *slhdsaContext) forsNode(i, z uint32, adrs *slhdsaAddress) []byte {
+	if z == 0 {
+		sk := c.forsSKGen(adrs, i)
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(i)
+		return c.thash(adrs, sk)
+	}
+	lnode := c.forsNode(2*i, z-1, adrs)
+	rnode := c.forsNode(2*i+1, z-1, adrs)
+	adrs.setTreeHeight(z)
+	adrs.setTreeIndex(i)
+	return c.thash(adrs, lnode, rnode)
+}
+
+// forsSign signs the message digest md with FORS (algorithm 16).
+func (c *slhdsaContext) forsSign(md []byte, adrs *slhdsaAddress) []byte {
+	indices := base2b(md, c.a, c.k)
+	sig := make([]byte, 0, c.forsSignatureSize())
+	for i, idx := range indices {
+		base := uint32(i) << uint(c.a)
+		sig = append(sig, c.forsSKGen(adrs, base+idx)...)
+		for j := 0; j < c.a; j++ {
+			s := (idx >> uint(j)) ^ 1
+			sig = append(sig, c.forsNode(uint32(i)<<uint(c.a-j)+s, uint32(j), adrs)...)
+		}
+	}
+	return sig
+}
+
+// forsPKFromSig computes a FORS public key from a signature (algorithm 17).
+func (c *slhdsaContext) forsPKFromSig(sig, md []byte, adrs *slhdsaAddress) []byte {
+	indices := base2b(md, c.a, c.k)
+	roots := make([][]byte, c.k)
+	for i, idx := range indices {
+		off := i * (1 + c.a) * slhdsaN
+		sk := sig[off : off+slhdsaN]
+		auth := sig[off+slhdsaN : off+(1+c.a)*slhdsaN]
+		adrs.setTreeHeight(0)
+		adrs.setTreeIndex(uint32(i)<<uint(c.a) + idx)
+		node := c.thash(adrs, sk)
+		for j := 0; j < c.a; j++ {
+			adrs.setTreeHeight(uint32(j + 1))
+			authJ := auth[j*slhdsaN : (j+1)*slhdsaN]
+			if (idx>>uint(j))&1 == 0 {
+				adrs.setTreeIndex(adrs.treeIndex() / 2)
+				node = c.thash(adrs, node, authJ)
+			} else {
+				adrs.setTreeIndex((adrs.treeIndex() - 1) / 2)
+				node = c.thash(adrs, authJ, node)
+			}
+		}
+		roots[i] = node
+	}
+	pkADRS := *adrs
+	pkADRS.setTypeAndClear(slhdsaFORSRoots)
+	pkADRS.setKeyPairAddress(adrs.keyPairAddress())
+	return c.thash(&pkADRS, roots...)
+}
+
+// splitDigest splits the message digest into the FORS message and the
+// hypertree indices.
+func (c *slhdsaContext) splitDigest(digest []byte) ([]byte, uint64, uint32) {
+	mdLen := (c.k*c.a + 7) / 8
+	treeBits := c.h - c.h/c.d
+	treeLen := (treeBits + 7) / 8
+	leafBits := c.h / c.d
+	leafLen := (leafBits + 7) / 8
+	md := digest[:mdLen]
+	var idxTree uint64
+	for _, b := range digest[mdLen : mdLen+treeLen] {
+		idxTree = idxTree<<8 | uint64(b)
+	}
+	if treeBits < 64 {
+		idxTree &= 1<<uint(treeBits) - 1
+	}
+	var idxLeaf uint32
+	for _, b := range digest[mdLen+treeLen : mdLen+treeLen+leafLen] {
+		idxLeaf = idxLeaf<<8 | uint32(b)
+	}
+	idxLeaf &= 1<<uint(leafBits) - 1
+	return md, idxTree, idxLeaf
+}
+
+// slhdsaEncodeMessage prepends the domain separator and the empty context
+// string of pure SLH-DSA to msg (algorithm 22).
+func slhdsaEncodeMessage(msg []byte) []byte {
+	return append([]byte{0, 0}, msg...)
+}
+
+// slhdsaKeyGen computes an SLH-DSA key pair from its seeds (algorithm 18).
+func slhdsaKeyGen(p *slhdsaParams, skSeed, skPRF, pkSeed []byte) (privateKey, publicKey []byte) {
+	c := newSLHDSAContext(p, pkSeed, skSeed)
+	var adrs slhdsaAddress
+	adrs.setLayerAddress(uint32(p.d - 1))
+	pkRoot := c.xmssNode(0, uint32(p.hp), &adrs)
+	privateKey = make([]byte, 0, SLHDSAPrivateKeySize)
+	privateKey = append(privateKey, skSeed...)
+	privateKey = append(privateKey, skPRF...)
+	privateKey = append(privateKey, pkSeed...)
+	privateKey = append(privateKey, pkRoot...)
+	return privateKey, privateKey[2*slhdsaN:]
+}
+
+// GenerateSLHDSAKey generates a new SLH-DSA key pair for the given parameter
+// set.
+func GenerateSLHDSAKey(instance string) (privateKey, publicKey []byte, err error) {
+	p, err := slhdsaParameters(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, publicKey = slhdsaKeyGen(p,
+		random.GetRandomBytes(slhdsaN),
+		random.GetRandomBytes(slhdsaN),
+		random.GetRandomBytes(slhdsaN))
+	return privateKey, publicKey, nil
+}
+
+// slhdsaSign signs msg (algorithm 19). optRand is the additional randomness
+// of the hedged variant.
+func slhdsaSign(p *slhdsaParams, privateKey, msg, optRand []byte) []byte {
+	skSeed := privateKey[:slhdsaN]
+	skPRF := privateKey[slhdsaN : 2*slhdsaN]
+	pkSeed := privateKey[2*slhdsaN : 3*slhdsaN]
+	pkRoot := privateKey[3*slhdsaN:]
+	c := newSLHDSAContext(p, pkSeed, skSeed)
+
+	r := c.prfMsg(skPRF, optRand, msg)
+	md, idxTree, idxLeaf := c.splitDigest(c.hashMsg(r, pkRoot, msg))
+	var adrs slhdsaAddress
+	adrs.setTreeAddress(idxTree)
+	adrs.setTypeAndClear(slhdsaFORSTree)
+	adrs.setKeyPairAddress(idxLeaf)
+	forsSig := c.forsSign(md, &adrs)
+	forsPK := c.forsPKFromSig(forsSig, md, &adrs)
+
+	sig := make([]byte, 0, p.signatureSize())
+	sig = append(sig, r...)
+	sig = append(sig, forsSig...)
+	return append(sig, c.htSign(forsPK, idxTree, idxLeaf)...)
+}
+
+// slhdsaVerify verifies a signature of msg (algorithm 20).
+func slhdsaVerify(p *slhdsaParams, publicKey, msg, sig []byte) error {
+	if len(publicKey) != SLHDSAPublicKeySize {
+		return errors.New("invalid public key")
+	}
+	if len(sig) != p.signatureSize() {
+		return errors.New("invalid signature length")
+	}
+	pkSeed, pkRoot := publicKey[:slhdsaN], publicKey[slhdsaN:]
+	c := newSLHDSAContext(p, pkSeed, nil)
+
+	r := sig[:slhdsaN]
+	forsSig := sig[slhdsaN : slhdsaN+p.forsSignatureSize()]
+	htSig := sig[slhdsaN+p.forsSignatureSize():]
+	md, idxTree, idxLeaf := c.splitDigest(c.hashMsg(r, pkRoot, msg))
+	var adrs slhdsaAddress
+	adrs.setTreeAddress(idxTree)
+	adrs.setTypeAndClear(slhdsaFORSTree)
+	adrs.setKeyPairAddress(idxLeaf)
+	forsPK := c.forsPKFromSig(forsSig, md, &adrs)
+	if !c.htVerify(forsPK, htSig, idxTree, idxLeaf, pkRoot) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+)
+
+// SLHDSASigner is an implementation of Signer for SLH-DSA. Signatures are
+// hedged, i.e. they mix fresh randomness into the message randomizer.
+type SLHDSASigner struct {
+	params     *slhdsaParams
+	privateKey []byte
+}
+
+// NewSLHDSASigner creates a new instance of SLHDSASigner for the given
+// parameter set and encoded private key.
+func NewSLHDSASigner(instance string, privateKey []byte) (*SLHDSASigner, error) {
+	params, err := slhdsaParameters(instance)
+	if err != nil {
+		return nil, fmt.Errorf("slh_dsa_signer: %s", err)
+	}
+	if len(privateKey) != SLHDSAPrivateKeySize {
+		return nil, fmt.Errorf("slh_dsa_signer: invalid private key length, got %d", len(privateKey))
+	}
+	return &SLHDSASigner{params: params, privateKey: privateKey}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *SLHDSASigner) Sign(data []byte) ([]byte, error) {
+	optRand := random.GetRandomBytes(slhdsaN)
+	return slhdsaSign(s.params, s.privateKey, slhdsaEncodeMessage(data), optRand), nil
+}
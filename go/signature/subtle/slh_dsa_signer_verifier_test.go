@@ -0,0 +1,151 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"testing"
+
+	subtleSignature "github.com/google/tink/go/signature/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestSLHDSASignVerify(t *testing.T) {
+	for _, tc := range []struct {
+		instance      string
+		signatureSize int
+	}{
+		{"SLH_DSA_SHA2_128S", 7856},
+		{"SLH_DSA_SHAKE_128S", 7856},
+		{"SLH_DSA_SHA2_128F", 17088},
+		{"SLH_DSA_SHAKE_128F", 17088},
+	} {
+		t.Run(tc.instance, func(t *testing.T) {
+			priv, pub, err := subtleSignature.GenerateSLHDSAKey(tc.instance)
+			if err != nil {
+				t.Fatalf("GenerateSLHDSAKey() err = %v", err)
+			}
+			if len(priv) != subtleSignature.SLHDSAPrivateKeySize {
+				t.Errorf("len(priv) = %d, want %d", len(priv), subtleSignature.SLHDSAPrivateKeySize)
+			}
+			if len(pub) != subtleSignature.SLHDSAPublicKeySize {
+				t.Errorf("len(pub) = %d, want %d", len(pub), subtleSignature.SLHDSAPublicKeySize)
+			}
+			// The private key ends with the public key.
+			if !bytes.HasSuffix(priv, pub) {
+				t.Errorf("private key does not contain the public key")
+			}
+			signer, err := subtleSignature.NewSLHDSASigner(tc.instance, priv)
+			if err != nil {
+				t.Fatalf("NewSLHDSASigner() err = %v", err)
+			}
+			verifier, err := subtleSignature.NewSLHDSAVerifier(tc.instance, pub)
+			if err != nil {
+				t.Fatalf("NewSLHDSAVerifier() err = %v", err)
+			}
+			data := random.GetRandomBytes(20)
+			sig, err := signer.Sign(data)
+			if err != nil {
+				t.Fatalf("signer.Sign() err = %v", err)
+			}
+			if len(sig) != tc.signatureSize {
+				t.Errorf("len(sig) = %d, want %d", len(sig), tc.signatureSize)
+			}
+			if size, err := subtleSignature.SLHDSASignatureSize(tc.instance); err != nil || size != tc.signatureSize {
+				t.Errorf("SLHDSASignatureSize() = %d, %v, want %d, nil", size, err, tc.signatureSize)
+			}
+			if err := verifier.Verify(sig, data); err != nil {
+				t.Errorf("verifier.Verify() err = %v", err)
+			}
+			if err := verifier.Verify(sig, append(data, 0)); err == nil {
+				t.Errorf("verifier.Verify() succeeded with modified data")
+			}
+			if err := verifier.Verify(sig[:len(sig)-1], data); err == nil {
+				t.Errorf("verifier.Verify() succeeded with truncated signature")
+			}
+			// Flip bits in the randomizer, the FORS signature and the
+			// hypertree signature.
+			for _, i := range []int{0, 16, len(sig) - 1} {
+				modified := append([]byte{}, sig...)
+				modified[i] ^= 1
+				if err := verifier.Verify(modified, data); err == nil {
+					t.Errorf("verifier.Verify() succeeded with signature modified at byte %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSLHDSASignIsHedged(t *testing.T) {
+	priv, _, err := subtleSignature.GenerateSLHDSAKey("SLH_DSA_SHA2_128F")
+	if err != nil {
+		t.Fatalf("GenerateSLHDSAKey() err = %v", err)
+	}
+	signer, err := subtleSignature.NewSLHDSASigner("SLH_DSA_SHA2_128F", priv)
+	if err != nil {
+		t.Fatalf("NewSLHDSASigner() err = %v", err)
+	}
+	data := []byte("firmware image")
+	sig1, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	sig2, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	if bytes.Equal(sig1, sig2) {
+		t.Errorf("signer.Sign() produced the same signature twice")
+	}
+}
+
+func TestSLHDSAInvalidParameters(t *testing.T) {
+	priv, pub, err := subtleSignature.GenerateSLHDSAKey("SLH_DSA_SHAKE_128F")
+	if err != nil {
+		t.Fatalf("GenerateSLHDSAKey() err = %v", err)
+	}
+	if _, _, err := subtleSignature.GenerateSLHDSAKey("SLH_DSA_UNKNOWN_INSTANCE"); err == nil {
+		t.Errorf("GenerateSLHDSAKey() with unknown instance succeeded, want error")
+	}
+	if _, err := subtleSignature.NewSLHDSASigner("SLH_DSA_SHAKE_128F", priv[1:]); err == nil {
+		t.Errorf("NewSLHDSASigner() with short private key succeeded, want error")
+	}
+	if _, err := subtleSignature.NewSLHDSASigner("", priv); err == nil {
+		t.Errorf("NewSLHDSASigner() with unknown instance succeeded, want error")
+	}
+	if _, err := subtleSignature.NewSLHDSAVerifier("SLH_DSA_SHAKE_128F", pub[1:]); err == nil {
+		t.Errorf("NewSLHDSAVerifier() with short public key succeeded, want error")
+	}
+
+	// A signature does not verify under another parameter set with the same
+	// signature size.
+	signer, err := subtleSignature.NewSLHDSASigner("SLH_DSA_SHAKE_128F", priv)
+	if err != nil {
+		t.Fatalf("NewSLHDSASigner() err = %v", err)
+	}
+	sig, err := signer.Sign([]byte("data"))
+	if err != nil {
+		t.Fatalf("signer.Sign() err = %v", err)
+	}
+	verifier, err := subtleSignature.NewSLHDSAVerifier("SLH_DSA_SHA2_128F", pub)
+	if err != nil {
+		t.Fatalf("NewSLHDSAVerifier() err = %v", err)
+	}
+	if err := verifier.Verify(sig, []byte("data")); err == nil {
+		t.Errorf("verifier.Verify() with another parameter set succeeded")
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// slhdsaVectors were generated with the SLH-DSA implementation of OpenSSL 3.5
+// from random seeds, messages and additional randomness. Signatures use the
+// pure variant with an empty context string; they are given by their SHA-256
+// digest to keep the file small.
+var slhdsaVectors = []struct {
+	instance            string
+	skSeed              string
+	skPRF               string
+	pkSeed              string
+	pkRoot              string
+	msg                 string
+	deterministicSHA256 string
+	addRand             string
+	hedgedSHA256        string
+}{
+	{
+		instance:            "SLH_DSA_SHA2_128S",
+		skSeed:              "29bc930869ab651d65fa56f42f6b192f",
+		skPRF:               "e1e8d5af196315e04376ccca58d5df4b",
+		pkSeed:              "cbdbb151df98e32ee17b278937477dfc",
+		pkRoot:              "c1bd8ef70a104bdbb8f6155ea0af0ea5",
+		msg:                 "4bb6182b3a1ed8c5bcba97b2a49408e2922ca0e967b82d154f23fbec30b4211b6d",
+		deterministicSHA256: "75d139ebcdbaf200224209a8f6de4a27b09aec963e5a0ed4a6b6c6f07b815fd7",
+		addRand:             "d9c579f2c15ef51e409213a723d9a71c",
+		hedgedSHA256:        "03c250a0b110003d358536d36f76c016a94c958316cff4adacb82ad1a7e59d54",
+	},
+	{
+		instance:            "SLH_DSA_SHA2_128F",
+		skSeed:              "bdf5ddf86c482f56be4e02ce3a72a3c9",
+		skPRF:               "e9ce4fd6309e344be786fb4026716dae",
+		pkSeed:              "3446996fd3cb6f9af8eaad2ea041356f",
+		pkRoot:              "90940e8fd2327547975baae6d0ab5e56",
+		msg:                 "97b1cc2c53ccd47a90f07897423023ef8dfec24007997e7acf4b155933219923fa",
+		deterministicSHA256: "f248a877d4cae506c65ac9308c659a0f68ea3169a69c1717c4a13cd428d21caa",
+		addRand:             "e27315b3ebfc9e331c43d91f42e601b6",
+		hedgedSHA256:        "791491711d414e212594504f590de1b41fbd0b4f3827e52ffa63c72a614802af",
+	},
+	{
+		instance:            "SLH_DSA_SHAKE_128S",
+		skSeed:              "a75869c586c10e1568ea0a43566ee71c",
+		skPRF:               "74ca7c86863db852719200b69ab2c3a7",
+		pkSeed:              "41a8e7dbfae840a6fd6287b61643c2b6",
+		pkRoot:              "4ad2fa4123775236d273e3d554ecf02c",
+		msg:                 "4a3862c5cc1eed52ab649a5766273d66b1cf3d157820688c95aa5abd545b3a58a2",
+		deterministicSHA256: "7399c441afd87e265b2c19fb200837a39e68cc9aacb2b11db80052d3e4578402",
+		addRand:             "d62676df6ec758563825bceef7acc4d4",
+		hedgedSHA256:        "ecca7f18b4b2d1a5863767867ff74b44bac098695b86a49152704d41bb569e22",
+	},
+	{
+		instance:            "SLH_DSA_SHAKE_128F",
+		skSeed:              "4ec0d51bd8ade8644948fb00b8c760c6",
+		skPRF:               "fcb9406f199ebc57a165075161215d5e",
+		pkSeed:              "64a2517a6e2342a5760472837756605f",
+		pkRoot:              "babbf663af6ef8b34f4ed61028b19ca9",
+		msg:                 "7684dac0fe11bd868ff41e803e4ee8080785b473349cfd3b377ccbe48caadd3deb",
+		deterministicSHA256: "f05298f5749b197cf660098ce5b2b3d2291a5557c07323c5e5042c5330207d63",
+		addRand:             "4db66c9baa5a449afefe0fffd4f20bcf",
+		hedgedSHA256:        "ad47c0b6f0508e3d5910b410024737889b3c455d3f35de6fcc224d14f4362401",
+	},
+}
+
+func slhdsaHexDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("hex.DecodeString(%q) failed: %v", s, err)
+	}
+	return b
+}
+
+func TestSLHDSAVectors(t *testing.T) {
+	for _, v := range slhdsaVectors {
+		t.Run(v.instance, func(t *testing.T) {
+			p, err := slhdsaParameters(v.instance)
+			if err != nil {
+				t.Fatalf("slhdsaParameters() err = %v", err)
+			}
+			skSeed := slhdsaHexDecode(t, v.skSeed)
+			skPRF := slhdsaHexDecode(t, v.skPRF)
+			pkSeed := slhdsaHexDecode(t, v.pkSeed)
+			pkRoot := slhdsaHexDecode(t, v.pkRoot)
+			msg := slhdsaEncodeMessage(slhdsaHexDecode(t, v.msg))
+
+			// Key generation.
+			priv, pub := slhdsaKeyGen(p, skSeed, skPRF, pkSeed)
+			if want := append(append([]byte{}, pkSeed...), pkRoot...); !bytes.Equal(pub, want) {
+				t.Fatalf("slhdsaKeyGen() public key = %x, want %x", pub, want)
+			}
+
+			for _, tc := range []struct {
+				name       string
+				optRand    []byte
+				wantSHA256 string
+			}{
+				// The deterministic variant uses pkSeed as randomness.
+				{"deterministic", pkSeed, v.deterministicSHA256},
+				{"hedged", slhdsaHexDecode(t, v.addRand), v.hedgedSHA256},
+			} {
+				// Signature generation.
+				sig := slhdsaSign(p, priv, msg, tc.optRand)
+				if got := sha256.Sum256(sig); hex.EncodeToString(got[:]) != tc.wantSHA256 {
+					t.Errorf("%s: SHA-256(slhdsaSign()) = %x, want %s", tc.name, got, tc.wantSHA256)
+					continue
+				}
+				// Signature verification.
+				if err := slhdsaVerify(p, pub, msg, sig); err != nil {
+					t.Errorf("%s: slhdsaVerify() err = %v", tc.name, err)
+				}
+				sig[len(sig)-1] ^= 1
+				if err := slhdsaVerify(p, pub, msg, sig); err == nil {
+					t.Errorf("%s: slhdsaVerify() succeeded with a modified signature", tc.name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errInvalidSLHDSASignature = errors.New("slh_dsa_verifier: invalid signature")
+
+// SLHDSAVerifier is an implementation of Verifier for SLH-DSA.
+type SLHDSAVerifier struct {
+	params    *slhdsaParams
+	publicKey []byte
+}
+
+// NewSLHDSAVerifier creates a new instance of SLHDSAVerifier for the given
+// parameter set and encoded public key.
+func NewSLHDSAVerifier(instance string, publicKey []byte) (*SLHDSAVerifier, error) {
+	params, err := slhdsaParameters(instance)
+	if err != nil {
+		return nil, fmt.Errorf("slh_dsa_verifier: %s", err)
+	}
+	if len(publicKey) != SLHDSAPublicKeySize {
+		return nil, fmt.Errorf("slh_dsa_verifier: invalid public key length, got %d", len(publicKey))
+	}
+	return &SLHDSAVerifier{params: params, publicKey: publicKey}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+func (v *SLHDSAVerifier) Verify(signature, data []byte) error {
+	if err := slhdsaVerify(v.params, v.publicKey, slhdsaEncodeMessage(data), signature); err != nil {
+		return errInvalidSLHDSASignature
+	}
+	return nil
+}
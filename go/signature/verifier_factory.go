@@ -19,6 +19,9 @@ package signature
 import (
 	"errors"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/google/tink/go/core/cryptofmt"
 	"github.com/google/tink/go/core/primitiveset"
@@ -43,14 +46,84 @@ func NewVerifierWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.V
 	return newWrappedVerifier(ps)
 }
 
+// NewVerifierWithRegistry returns a Verifier primitive from the given keyset
+// handle, using the key managers of r instead of the global registry.
+func NewVerifierWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.Verifier, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedVerifier(ps)
+}
+
+// BatchVerifier is a Verifier that can also verify many signatures at once,
+// e.g. to audit large logs of signed entries.
+type BatchVerifier interface {
+	tink.Verifier
+
+	// VerifyBatch checks whether signatures[i] is a valid signature of data[i]
+	// for every i. The i-th result is nil if the signature is valid and an
+	// error, usually ErrInvalidSignature, otherwise. An error is only returned
+	// if signatures and data have different lengths.
+	VerifyBatch(signatures, data [][]byte) ([]error, error)
+}
+
+// NewBatchVerifier returns a BatchVerifier primitive from the given keyset
+// handle.
+func NewBatchVerifier(h *keyset.Handle) (BatchVerifier, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedVerifier(ps)
+}
+
+// NewVerifierWithKeyIDs returns a Verifier primitive from the given keyset
+// handle that only accepts signatures made with one of the keys with the given
+// IDs. Signatures of the other keys in the keyset are rejected with
+// ErrInvalidSignature. This allows distrusting compromised keys right away,
+// before a keyset without them has been distributed.
+//
+// It returns an error if no IDs are given or if an ID is not in the keyset.
+func NewVerifierWithKeyIDs(h *keyset.Handle, keyIDs ...uint32) (tink.Verifier, error) {
+	if len(keyIDs) == 0 {
+		return nil, fmt.Errorf("verifier_factory: no key IDs given")
+	}
+	inKeyset := make(map[uint32]bool)
+	for _, k := range h.KeysetInfo().GetKeyInfo() {
+		inKeyset[k.GetKeyId()] = true
+	}
+	allowed := make(map[uint32]bool)
+	for _, id := range keyIDs {
+		if !inKeyset[id] {
+			return nil, fmt.Errorf("verifier_factory: key ID %d not in keyset", id)
+		}
+		allowed[id] = true
+	}
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("verifier_factory: cannot obtain primitive set: %s", err)
+	}
+	v, err := newWrappedVerifier(ps)
+	if err != nil {
+		return nil, err
+	}
+	v.allowed = allowed
+	return v, nil
+}
+
 // verifierSet is a Verifier implementation that uses the
 // underlying primitive set for verifying.
 type wrappedVerifier struct {
 	ps *primitiveset.PrimitiveSet
+	// allowed contains the IDs of the keys that may verify signatures. All
+	// keys may if it is nil.
+	allowed map[uint32]bool
 }
 
 // Asserts that verifierSet implements the Verifier interface.
 var _ tink.Verifier = (*wrappedVerifier)(nil)
+var _ BatchVerifier = (*wrappedVerifier)(nil)
 
 func newWrappedVerifier(ps *primitiveset.PrimitiveSet) (*wrappedVerifier, error) {
 	if _, ok := (ps.Primary.Primitive).(tink.Verifier); !ok {
@@ -71,13 +144,16 @@ func newWrappedVerifier(ps *primitiveset.PrimitiveSet) (*wrappedVerifier, error)
 	return ret, nil
 }
 
-var errInvalidSignature = errors.New("verifier_factory: invalid signature")
+// ErrInvalidSignature is returned when a signature does not verify, as opposed
+// to errors caused by an invalid keyset or configuration. Use errors.Is to test
+// for it.
+var ErrInvalidSignature = errors.New("verifier_factory: invalid signature")
 
 // Verify checks whether the given signature is a valid signature of the given data.
 func (v *wrappedVerifier) Verify(signature, data []byte) error {
 	prefixSize := cryptofmt.NonRawPrefixSize
 	if len(signature) < prefixSize {
-		return errInvalidSignature
+		return ErrInvalidSignature
 	}
 
 	// try non-raw keys
@@ -86,6 +162,9 @@ func (v *wrappedVerifier) Verify(signature, data []byte) error {
 	entries, err := v.ps.EntriesForPrefix(string(prefix))
 	if err == nil {
 		for i := 0; i < len(entries); i++ {
+			if !v.isAllowed(entries[i]) {
+				continue
+			}
 			var signedData []byte
 			if entries[i].PrefixType == tinkpb.OutputPrefixType_LEGACY {
 				signedData = append(data, byte(0))
@@ -108,6 +187,9 @@ func (v *wrappedVerifier) Verify(signature, data []byte) error {
 	entries, err = v.ps.RawEntries()
 	if err == nil {
 		for i := 0; i < len(entries); i++ {
+			if !v.isAllowed(entries[i]) {
+				continue
+			}
 			verifier, ok := (entries[i].Primitive).(tink.Verifier)
 			if !ok {
 				return fmt.Errorf("verifier_factory: not an Verifier primitive")
@@ -119,5 +201,45 @@ func (v *wrappedVerifier) Verify(signature, data []byte) error {
 		}
 	}
 
-	return errInvalidSignature
+	return ErrInvalidSignature
+}
+
+// isAllowed returns true if the key of e may verify signatures.
+func (v *wrappedVerifier) isAllowed(e *primitiveset.Entry) bool {
+	return v.allowed == nil || v.allowed[e.KeyID]
+}
+
+// VerifyBatch checks every (signature, data) pair, spreading the work over
+// GOMAXPROCS goroutines.
+//
+// Each signature is verified on its own, including ED25519 signatures: the
+// Ed25519 batch verification equation is cofactored and accepts some
+// signatures that Verify rejects, so it is not used to keep both methods
+// consistent.
+func (v *wrappedVerifier) VerifyBatch(signatures, data [][]byte) ([]error, error) {
+	if len(signatures) != len(data) {
+		return nil, fmt.Errorf("verifier_factory: got %d signatures for %d messages", len(signatures), len(data))
+	}
+	results := make([]error, len(signatures))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(signatures) {
+		workers = len(signatures)
+	}
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(signatures) {
+					return
+				}
+				results[i] = v.Verify(signatures[i], data[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results, nil
 }
@@ -60,15 +60,15 @@ func (km *aesCTRHMACKeyManager) Primitive(serializedKey []byte) (interface{}, er
 	if err := km.validateKey(key); err != nil {
 		return nil, err
 	}
-	p, err := subtle.NewAESCTRHMAC(
+	p, err := subtle.NewAESCTRHMACWithSegmentsPerKey(
 		key.KeyValue,
 		key.Params.HkdfHashType.String(),
 		int(key.Params.DerivedKeySize),
 		key.Params.HmacParams.Hash.String(),
 		int(key.Params.HmacParams.TagSize),
 		int(key.Params.CiphertextSegmentSize),
-		// No first segment offset.
-		0)
+		int(key.Params.FirstSegmentOffset),
+		key.Params.SegmentsPerKey)
 	if err != nil {
 		return nil, fmt.Errorf("aes_ctr_hmac_key_manager: cannot create new primitive: %s", err)
 	}
@@ -170,5 +170,8 @@ func (km *aesCTRHMACKeyManager) validateParams(params *chpb.AesCtrHmacStreamingP
 	if params.CiphertextSegmentSize < minSegmentSize {
 		return fmt.Errorf("ciphertext segment size must be at least (derivedKeySize + noncePrefixInBytes + tagSizeInBytes + 2)")
 	}
+	if params.FirstSegmentOffset > params.CiphertextSegmentSize-minSegmentSize {
+		return fmt.Errorf("ciphertext segment size must be at least (firstSegmentOffset + derivedKeySize + noncePrefixInBytes + tagSizeInBytes + 2)")
+	}
 	return nil
 }
@@ -56,13 +56,13 @@ func (km *aesGCMHKDFKeyManager) Primitive(serializedKey []byte) (interface{}, er
 	if err := km.validateKey(key); err != nil {
 		return nil, err
 	}
-	ret, err := subtle.NewAESGCMHKDF(
+	ret, err := subtle.NewAESGCMHKDFWithSegmentsPerKey(
 		key.KeyValue,
 		key.Params.HkdfHashType.String(),
 		int(key.Params.DerivedKeySize),
 		int(key.Params.CiphertextSegmentSize),
-		// no first segment offset
-		0)
+		int(key.Params.FirstSegmentOffset),
+		key.Params.SegmentsPerKey)
 	if err != nil {
 		return nil, fmt.Errorf("aes_gcm_hkdf_key_manager: cannot create new primitive: %s", err)
 	}
@@ -156,5 +156,8 @@ func (km *aesGCMHKDFKeyManager) validateParams(params *ghpb.AesGcmHkdfStreamingP
 	if params.CiphertextSegmentSize < minSegmentSize {
 		return fmt.Errorf("ciphertext segment_size must be at least (derivedKeySize + noncePrefixInBytes + tagSizeInBytes + 2)")
 	}
+	if params.FirstSegmentOffset > params.CiphertextSegmentSize-minSegmentSize {
+		return fmt.Errorf("ciphertext segment_size must be at least (firstSegmentOffset + derivedKeySize + noncePrefixInBytes + tagSizeInBytes + 2)")
+	}
 	return nil
 }
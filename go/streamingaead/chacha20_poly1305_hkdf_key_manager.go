@@ -0,0 +1,172 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	chpb "github.com/google/tink/go/proto/chacha20_poly1305_hkdf_streaming_go_proto"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	chaCha20Poly1305HKDFKeyVersion = 0
+	chaCha20Poly1305HKDFTypeURL    = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305HkdfStreamingKey"
+)
+
+var (
+	errInvalidChaCha20Poly1305HKDFKey       = errors.New("chacha20_poly1305_hkdf_key_manager: invalid key")
+	errInvalidChaCha20Poly1305HKDFKeyFormat = errors.New("chacha20_poly1305_hkdf_key_manager: invalid key format")
+)
+
+// chaCha20Poly1305HKDFKeyManager is an implementation of KeyManager interface.
+// It generates new ChaCha20Poly1305HkdfStreamingKey keys and produces new
+// instances of ChaCha20Poly1305HKDF subtle.
+type chaCha20Poly1305HKDFKeyManager struct{}
+
+// Primitive creates a ChaCha20Poly1305HKDF subtle for the given serialized
+// ChaCha20Poly1305HkdfStreamingKey proto.
+func (km *chaCha20Poly1305HKDFKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidChaCha20Poly1305HKDFKey
+	}
+	key := &chpb.ChaCha20Poly1305HkdfStreamingKey{}
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidChaCha20Poly1305HKDFKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewChaCha20Poly1305HKDF(
+		key.KeyValue,
+		key.Params.HkdfHashType.String(),
+		int(key.Params.CiphertextSegmentSize),
+		int(key.Params.FirstSegmentOffset),
+		key.Params.SegmentsPerKey)
+	if err != nil {
+		return nil, fmt.Errorf("chacha20_poly1305_hkdf_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification in the given serialized
+// ChaCha20Poly1305HkdfStreamingKeyFormat.
+func (km *chaCha20Poly1305HKDFKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidChaCha20Poly1305HKDFKeyFormat
+	}
+	keyFormat := &chpb.ChaCha20Poly1305HkdfStreamingKeyFormat{}
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidChaCha20Poly1305HKDFKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("chacha20_poly1305_hkdf_key_manager: invalid key format: %s", err)
+	}
+	return &chpb.ChaCha20Poly1305HkdfStreamingKey{
+		Version:  chaCha20Poly1305HKDFKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+		Params:   keyFormat.Params,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given
+// serialized ChaCha20Poly1305HkdfStreamingKeyFormat.
+// It should be used solely by the key management API.
+func (km *chaCha20Poly1305HKDFKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         km.TypeURL(),
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *chaCha20Poly1305HKDFKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == chaCha20Poly1305HKDFTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *chaCha20Poly1305HKDFKeyManager) TypeURL() string {
+	return chaCha20Poly1305HKDFTypeURL
+}
+
+// validateKey validates the given ChaCha20Poly1305HkdfStreamingKey.
+func (km *chaCha20Poly1305HKDFKeyManager) validateKey(key *chpb.ChaCha20Poly1305HkdfStreamingKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, chaCha20Poly1305HKDFKeyVersion)
+	if err != nil {
+		return fmt.Errorf("chacha20_poly1305_hkdf_key_manager: %s", err)
+	}
+	if err := validateChaCha20Poly1305HKDFKeySize(uint32(len(key.KeyValue))); err != nil {
+		return fmt.Errorf("chacha20_poly1305_hkdf_key_manager: %s", err)
+	}
+	if err := km.validateParams(key.Params); err != nil {
+		return fmt.Errorf("chacha20_poly1305_hkdf_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given ChaCha20Poly1305HkdfStreamingKeyFormat.
+func (km *chaCha20Poly1305HKDFKeyManager) validateKeyFormat(format *chpb.ChaCha20Poly1305HkdfStreamingKeyFormat) error {
+	if err := validateChaCha20Poly1305HKDFKeySize(format.KeySize); err != nil {
+		return fmt.Errorf("chacha20_poly1305_hkdf_key_manager: %s", err)
+	}
+	if err := km.validateParams(format.Params); err != nil {
+		return fmt.Errorf("chacha20_poly1305_hkdf_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateParams validates the given ChaCha20Poly1305HkdfStreamingParams.
+func (km *chaCha20Poly1305HKDFKeyManager) validateParams(params *chpb.ChaCha20Poly1305HkdfStreamingParams) error {
+	if params == nil {
+		return errors.New("missing params")
+	}
+	if params.HkdfHashType == commonpb.HashType_UNKNOWN_HASH {
+		return errors.New("unknown HKDF hash type")
+	}
+	minSegmentSize := uint32(subtle.ChaCha20Poly1305HKDFKeySizeInBytes + subtle.ChaCha20Poly1305HKDFNoncePrefixSizeInBytes + subtle.ChaCha20Poly1305HKDFTagSizeInBytes + 2)
+	if params.CiphertextSegmentSize < minSegmentSize {
+		return fmt.Errorf("ciphertext segment_size must be at least (keySize + noncePrefixInBytes + tagSizeInBytes + 2)")
+	}
+	if params.FirstSegmentOffset > params.CiphertextSegmentSize-minSegmentSize {
+		return fmt.Errorf("ciphertext segment_size must be at least (firstSegmentOffset + keySize + noncePrefixInBytes + tagSizeInBytes + 2)")
+	}
+	return nil
+}
+
+// validateChaCha20Poly1305HKDFKeySize checks that the main key has the size of
+// a ChaCha20-Poly1305 key.
+func validateChaCha20Poly1305HKDFKeySize(keySize uint32) error {
+	if keySize != subtle.ChaCha20Poly1305HKDFKeySizeInBytes {
+		return fmt.Errorf("invalid key size; want %d, got %d", subtle.ChaCha20Poly1305HKDFKeySizeInBytes, keySize)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	chpb "github.com/google/tink/go/proto/chacha20_poly1305_hkdf_streaming_go_proto"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const chaCha20Poly1305HKDFTypeURL = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305HkdfStreamingKey"
+
+func newChaCha20Poly1305HKDFKeyFormat(keySize uint32, hashType commonpb.HashType, segmentSize, offset uint32) *chpb.ChaCha20Poly1305HkdfStreamingKeyFormat {
+	return &chpb.ChaCha20Poly1305HkdfStreamingKeyFormat{
+		KeySize: keySize,
+		Params: &chpb.ChaCha20Poly1305HkdfStreamingParams{
+			CiphertextSegmentSize: segmentSize,
+			HkdfHashType:          hashType,
+			FirstSegmentOffset:    offset,
+		},
+	}
+}
+
+func newChaCha20Poly1305HKDFKey(version, keySize uint32, hashType commonpb.HashType, segmentSize, offset uint32) *chpb.ChaCha20Poly1305HkdfStreamingKey {
+	return &chpb.ChaCha20Poly1305HkdfStreamingKey{
+		Version:  version,
+		KeyValue: random.GetRandomBytes(keySize),
+		Params:   newChaCha20Poly1305HKDFKeyFormat(keySize, hashType, segmentSize, offset).Params,
+	}
+}
+
+func TestChaCha20Poly1305HKDFGetPrimitiveBasic(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(chaCha20Poly1305HKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ChaCha20-Poly1305-HKDF key manager: %s", err)
+	}
+	for _, offset := range []uint32{0, 100} {
+		key := newChaCha20Poly1305HKDFKey(0, 32, commonpb.HashType_SHA256, 4096, offset)
+		serializedKey, err := proto.Marshal(key)
+		if err != nil {
+			t.Fatalf("failed to marshal key: %s", err)
+		}
+		p, err := keyManager.Primitive(serializedKey)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		cipher := p.(*subtle.ChaCha20Poly1305HKDF)
+		if !bytes.Equal(cipher.MainKey, key.KeyValue) {
+			t.Errorf("main key and primitive don't match")
+		}
+		if err := encryptDecrypt(cipher, cipher, 32, 32); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestChaCha20Poly1305HKDFGetPrimitiveWithInvalidInput(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(chaCha20Poly1305HKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ChaCha20-Poly1305-HKDF key manager: %s", err)
+	}
+	testKeys := []proto.Message{
+		// bad key size
+		newChaCha20Poly1305HKDFKey(0, 16, commonpb.HashType_SHA256, 4096, 0),
+		newChaCha20Poly1305HKDFKey(0, 33, commonpb.HashType_SHA256, 4096, 0),
+		// bad version
+		newChaCha20Poly1305HKDFKey(1, 32, commonpb.HashType_SHA256, 4096, 0),
+		// bad hash
+		newChaCha20Poly1305HKDFKey(0, 32, commonpb.HashType_UNKNOWN_HASH, 4096, 0),
+		// segment size too small
+		newChaCha20Poly1305HKDFKey(0, 32, commonpb.HashType_SHA256, 56, 0),
+		newChaCha20Poly1305HKDFKey(0, 32, commonpb.HashType_SHA256, 4096, 4096),
+	}
+	for i, key := range testKeys {
+		serializedKey, err := proto.Marshal(key)
+		if err != nil {
+			t.Fatalf("failed to marshal key: %s", err)
+		}
+		if _, err := keyManager.Primitive(serializedKey); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+	if _, err := keyManager.Primitive(nil); err == nil {
+		t.Errorf("expect an error when input is nil")
+	}
+	if _, err := keyManager.Primitive([]byte{}); err == nil {
+		t.Errorf("expect an error when input is empty")
+	}
+}
+
+func TestChaCha20Poly1305HKDFNewKeyData(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(chaCha20Poly1305HKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ChaCha20-Poly1305-HKDF key manager: %s", err)
+	}
+	format := newChaCha20Poly1305HKDFKeyFormat(32, commonpb.HashType_SHA256, 4096, 0)
+	serializedFormat, err := proto.Marshal(format)
+	if err != nil {
+		t.Fatalf("failed to marshal key format: %s", err)
+	}
+	keyData, err := keyManager.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if keyData.TypeUrl != chaCha20Poly1305HKDFTypeURL {
+		t.Errorf("incorrect type url")
+	}
+	if keyData.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("incorrect key material type")
+	}
+	key := &chpb.ChaCha20Poly1305HkdfStreamingKey{}
+	if err := proto.Unmarshal(keyData.Value, key); err != nil {
+		t.Fatalf("incorrect key value")
+	}
+	if len(key.KeyValue) != 32 || !proto.Equal(key.Params, format.Params) {
+		t.Errorf("key = %v, want key size 32 and params %v", key, format.Params)
+	}
+	if _, err := keyManager.Primitive(keyData.Value); err != nil {
+		t.Errorf("Primitive() err = %v", err)
+	}
+
+	for i, format := range []*chpb.ChaCha20Poly1305HkdfStreamingKeyFormat{
+		newChaCha20Poly1305HKDFKeyFormat(16, commonpb.HashType_SHA256, 4096, 0),
+		newChaCha20Poly1305HKDFKeyFormat(32, commonpb.HashType_UNKNOWN_HASH, 4096, 0),
+		newChaCha20Poly1305HKDFKeyFormat(32, commonpb.HashType_SHA256, 56, 0),
+		{KeySize: 32},
+	} {
+		serializedFormat, err := proto.Marshal(format)
+		if err != nil {
+			t.Fatalf("failed to marshal key format: %s", err)
+		}
+		if _, err := keyManager.NewKeyData(serializedFormat); err == nil {
+			t.Errorf("expect an error in test case %d", i)
+		}
+	}
+}
+
+func TestChaCha20Poly1305HKDFDoesSupport(t *testing.T) {
+	keyManager, err := registry.GetKeyManager(chaCha20Poly1305HKDFTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain ChaCha20-Poly1305-HKDF key manager: %s", err)
+	}
+	if !keyManager.DoesSupport(chaCha20Poly1305HKDFTypeURL) {
+		t.Errorf("ChaCha20Poly1305HKDFKeyManager must support %s", chaCha20Poly1305HKDFTypeURL)
+	}
+	if keyManager.DoesSupport("some bad type") {
+		t.Errorf("ChaCha20Poly1305HKDFKeyManager must support only %s", chaCha20Poly1305HKDFTypeURL)
+	}
+	if keyManager.TypeURL() != chaCha20Poly1305HKDFTypeURL {
+		t.Errorf("incorrect key type")
+	}
+}
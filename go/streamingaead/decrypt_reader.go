@@ -67,8 +67,25 @@ func (dr *decryptReader) Read(p []byte) (n int, err error) {
 		var buf bytes.Buffer
 		tee := io.TeeReader(cr, &buf)
 
+		// Segments decrypted while trying the key are only reported once the
+		// key is known to match.
+		var (
+			matched bool
+			events  []SegmentEvent
+		)
+		var onSegment func(SegmentEvent) error
+		if dr.wrapped.onSegment != nil {
+			onSegment = func(ev SegmentEvent) error {
+				if matched {
+					return dr.wrapped.onSegment(ev)
+				}
+				events = append(events, ev)
+				return nil
+			}
+		}
+
 		read := func() (io.Reader, int, error) {
-			r, err := sa.NewDecryptingReader(tee, dr.aad)
+			r, err := dr.wrapped.newDecryptingReader(sa, tee, dr.aad, onSegment)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -82,10 +99,29 @@ func (dr *decryptReader) Read(p []byte) (n int, err error) {
 		r, n, err := read()
 		if err == nil {
 			dr.mr = r
+			matched = true
+			for _, ev := range events {
+				if err := dr.wrapped.onSegment(ev); err != nil && ev.Err == nil {
+					dr.mr = &errReader{err: err}
+					return 0, err
+				}
+			}
 			return n, nil
 		}
 
 		cr = io.MultiReader(&buf, cr)
 	}
+	if dr.wrapped.onSegment != nil {
+		dr.wrapped.onSegment(SegmentEvent{Index: 0, Err: errKeyNotFound})
+	}
 	return 0, errKeyNotFound
 }
+
+// errReader is a reader that always returns err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
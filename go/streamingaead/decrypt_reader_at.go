@@ -0,0 +1,62 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+)
+
+// readerAtDecrypter is implemented by streaming AEAD primitives that support
+// random access decryption.
+type readerAtDecrypter interface {
+	NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error)
+}
+
+// NewSeekableDecryptingReaderAt returns a reader that decrypts the ciphertext
+// of the given size in src on demand, using aad as associated authenticated
+// data. Only the segments of the ciphertext which contain the requested range
+// of the plaintext are read and decrypted, so that e.g. HTTP range requests
+// can be served from an encrypted blob.
+//
+// The returned reader implements io.ReaderAt and io.ReadSeeker over the
+// plaintext, and its Size method returns the size of the plaintext. The first
+// segment of the ciphertext is authenticated before it is returned; the other
+// segments are authenticated when they are read.
+func NewSeekableDecryptingReaderAt(h *keyset.Handle, src io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	entries, err := ps.RawEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		d, ok := e.Primitive.(readerAtDecrypter)
+		if !ok {
+			continue
+		}
+		r, err := d.NewDecryptingReaderAt(src, size, aad)
+		if err == nil {
+			return r, nil
+		}
+	}
+	return nil, errKeyNotFound
+}
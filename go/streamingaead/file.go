@@ -0,0 +1,187 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/tink/go/keyset"
+)
+
+// fileChunkSize is the size of the chunks in which EncryptFile and
+// DecryptFile copy data.
+const fileChunkSize = 1 << 16
+
+// FileOption configures EncryptFile and DecryptFile.
+type FileOption func(*fileOptions)
+
+type fileOptions struct {
+	progress func(n int64)
+	opts     []Option
+}
+
+// WithProgress makes EncryptFile and DecryptFile call f with the total number
+// of plaintext bytes processed so far, each time a chunk has been processed.
+func WithProgress(f func(n int64)) FileOption {
+	return func(o *fileOptions) {
+		o.progress = f
+	}
+}
+
+// WithPrimitiveOptions makes EncryptFile and DecryptFile create the
+// StreamingAEAD primitive with the given options, e.g. WithWorkers.
+func WithPrimitiveOptions(opts ...Option) FileOption {
+	return func(o *fileOptions) {
+		o.opts = append(o.opts, opts...)
+	}
+}
+
+// EncryptFile encrypts the file src with the primary key of h, using aad as
+// associated authenticated data, and writes the ciphertext to the file dst.
+//
+// The ciphertext is written to a temporary file in the directory of dst, which
+// is synced and then renamed to dst. dst is thus either left untouched or
+// replaced by the complete ciphertext, even if the process crashes. The
+// created file is only accessible by its owner.
+func EncryptFile(h *keyset.Handle, src, dst string, aad []byte, opts ...FileOption) error {
+	o := newFileOptions(opts)
+	a, err := New(h, o.opts...)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return writeFileAtomically(dst, func(out io.Writer) error {
+		w, err := a.NewEncryptingWriter(out, aad)
+		if err != nil {
+			return err
+		}
+		if err := copyWithProgress(w, in, o.progress); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// DecryptFile decrypts the file src with h, using aad as associated
+// authenticated data, and writes the plaintext to the file dst.
+//
+// Like EncryptFile, it writes to a temporary file which is renamed to dst once
+// the whole ciphertext has been decrypted and authenticated, so that dst never
+// contains the plaintext of a partial or modified ciphertext.
+func DecryptFile(h *keyset.Handle, src, dst string, aad []byte, opts ...FileOption) error {
+	o := newFileOptions(opts)
+	a, err := New(h, o.opts...)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return writeFileAtomically(dst, func(out io.Writer) error {
+		r, err := a.NewDecryptingReader(in, aad)
+		if err != nil {
+			return err
+		}
+		return copyWithProgress(out, r, o.progress)
+	})
+}
+
+func newFileOptions(opts []FileOption) fileOptions {
+	var o fileOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// copyWithProgress copies r to w in chunks, and reports the number of bytes
+// copied so far to progress, if it is not nil.
+func copyWithProgress(w io.Writer, r io.Reader, progress func(n int64)) error {
+	buf := make([]byte, fileChunkSize)
+	var total int64
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			total += int64(n)
+			if progress != nil {
+				progress(total)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFileAtomically calls write with a temporary file in the directory of
+// dst, and renames the file to dst if write succeeds. The temporary file is
+// removed otherwise.
+func writeFileAtomically(dst string, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(dst)
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(dst)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("streamingaead: cannot replace %s: %s", dst, err)
+	}
+	return syncDir(dir)
+}
+
+// syncDir syncs the directory dir, so that a rename in it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil && !os.IsPermission(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestEncryptDecryptFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "streamingaead")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		src = filepath.Join(dir, "plaintext.src")
+		ct  = filepath.Join(dir, "ciphertext.bin")
+		dst = filepath.Join(dir, "plaintext.dst")
+	)
+	pt := random.GetRandomBytes(300000)
+	if err := ioutil.WriteFile(src, pt, 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile failed: %s", err)
+	}
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	aad := []byte("aad")
+
+	var progress []int64
+	if err := streamingaead.EncryptFile(kh, src, ct, aad, streamingaead.WithProgress(func(n int64) {
+		progress = append(progress, n)
+	})); err != nil {
+		t.Fatalf("streamingaead.EncryptFile failed: %s", err)
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != int64(len(pt)) {
+		t.Errorf("progress = %v, want to end with %d", progress, len(pt))
+	}
+	if err := streamingaead.DecryptFile(kh, ct, dst, aad, streamingaead.WithPrimitiveOptions(streamingaead.WithWorkers(4))); err != nil {
+		t.Fatalf("streamingaead.DecryptFile failed: %s", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed: %s", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Error("decrypted file does not match the plaintext")
+	}
+
+	// A failed decryption leaves dst and its directory untouched.
+	if err := streamingaead.DecryptFile(kh, ct, dst, []byte("other aad")); err == nil {
+		t.Fatal("streamingaead.DecryptFile with wrong aad succeeded")
+	}
+	got, err = ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile failed: %s", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Error("failed decryption modified dst")
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ioutil.ReadDir failed: %s", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("directory contains %d files, want 3", len(files))
+	}
+
+	if err := streamingaead.EncryptFile(kh, filepath.Join(dir, "missing"), ct, aad); err == nil {
+		t.Error("streamingaead.EncryptFile with missing src succeeded")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead/subtle/noncebased"
+	"github.com/google/tink/go/tink"
+)
+
+// resumingEncrypter is implemented by streaming AEAD primitives that can
+// resume the encryption of unfinished ciphertexts.
+type resumingEncrypter interface {
+	NewResumingEncryptingWriter(w io.Writer, r io.ReaderAt, size int64, aad []byte) (io.WriteCloser, int64, int64, error)
+}
+
+// ResumePoint describes where the encryption of an unfinished ciphertext, e.g.
+// an interrupted upload, can be resumed. It is returned by FindResumePoint.
+type ResumePoint struct {
+	// CiphertextSize is the size of the part of the ciphertext that is kept.
+	// The ciphertext must be truncated to this size before encryption resumes.
+	CiphertextSize int64
+
+	// PlaintextOffset is the offset in the plaintext at which encryption
+	// resumes: the plaintext after it must be written to the writer returned by
+	// NewEncryptingWriter.
+	PlaintextOffset int64
+
+	aad []byte
+	// fresh encrypts from the start if no part of the ciphertext is kept.
+	fresh tink.StreamingAEAD
+	// out forwards the writes of resumed to the writer passed to
+	// NewEncryptingWriter.
+	out     *forwardingWriter
+	resumed io.WriteCloser
+	used    bool
+}
+
+// forwardingWriter writes to w, which is set once it is known.
+type forwardingWriter struct {
+	w io.Writer
+}
+
+func (f *forwardingWriter) Write(p []byte) (int, error) {
+	if f.w == nil {
+		return 0, errors.New("streamingaead: write before NewEncryptingWriter")
+	}
+	return f.w.Write(p)
+}
+
+// FindResumePoint finds where the encryption of the unfinished ciphertext of
+// the given size in r can be resumed with the keys of h, using aad as
+// associated authenticated data. The complete segments of the ciphertext are
+// kept, their first and last one are authenticated. Finished ciphertexts can
+// be resumed too, which appends plaintext to them.
+//
+// To resume, truncate the ciphertext to the returned CiphertextSize, then
+// write the plaintext starting at PlaintextOffset to the writer returned by
+// NewEncryptingWriter of the returned ResumePoint. If the ciphertext has no
+// complete segment, both are zero and encryption starts over with the primary
+// key of h.
+func FindResumePoint(h *keyset.Handle, r io.ReaderAt, size int64, aad []byte) (*ResumePoint, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	fresh, err := newWrappedStreamingAEAD(ps, newOptions(nil))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ps.RawEntries()
+	if err != nil {
+		return nil, err
+	}
+	out := &forwardingWriter{}
+	noCompleteSegment := true
+	for _, e := range entries {
+		re, ok := e.Primitive.(resumingEncrypter)
+		if !ok {
+			continue
+		}
+		w, ciphertextSize, plaintextOffset, err := re.NewResumingEncryptingWriter(out, r, size, aad)
+		if err == nil {
+			return &ResumePoint{
+				CiphertextSize:  ciphertextSize,
+				PlaintextOffset: plaintextOffset,
+				aad:             aad,
+				out:             out,
+				resumed:         w,
+			}, nil
+		}
+		if err != noncebased.ErrNoCompleteSegment {
+			noCompleteSegment = false
+		}
+	}
+	if !noCompleteSegment {
+		return nil, errKeyNotFound
+	}
+	return &ResumePoint{aad: aad, fresh: fresh}, nil
+}
+
+// NewEncryptingWriter returns a writer that resumes the encryption, writing
+// the rest of the ciphertext to w. w must append to the ciphertext truncated
+// to p.CiphertextSize. It can only be called once.
+func (p *ResumePoint) NewEncryptingWriter(w io.Writer) (io.WriteCloser, error) {
+	if p.used {
+		return nil, errors.New("streamingaead: ResumePoint already used")
+	}
+	p.used = true
+	if p.fresh != nil {
+		return p.fresh.NewEncryptingWriter(w, p.aad)
+	}
+	p.out.w = w
+	return p.resumed, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingaead_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func TestFindResumePoint(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	pt := random.GetRandomBytes(50000)
+	aad := []byte("aad")
+
+	// Simulate an encryption that was interrupted after 30000 bytes.
+	buf := &bytes.Buffer{}
+	w, err := a.NewEncryptingWriter(buf, aad)
+	if err != nil {
+		t.Fatalf("a.NewEncryptingWriter failed: %s", err)
+	}
+	if _, err := w.Write(pt[:30000]); err != nil {
+		t.Fatalf("w.Write failed: %s", err)
+	}
+	unfinished := buf.Bytes()
+
+	// Rotate the keyset: the ciphertext is resumed with the key it was started
+	// with.
+	manager := keyset.NewManagerFromHandle(kh)
+	if err := manager.Rotate(streamingaead.AES256GCMHKDF4KBKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	rotated, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+
+	rp, err := streamingaead.FindResumePoint(rotated, bytes.NewReader(unfinished), int64(len(unfinished)), aad)
+	if err != nil {
+		t.Fatalf("streamingaead.FindResumePoint failed: %s", err)
+	}
+	if rp.CiphertextSize == 0 || rp.CiphertextSize > int64(len(unfinished)) || rp.PlaintextOffset == 0 || rp.PlaintextOffset > 30000 {
+		t.Fatalf("streamingaead.FindResumePoint = %d, %d, out of range", rp.CiphertextSize, rp.PlaintextOffset)
+	}
+	resumed := bytes.NewBuffer(append([]byte{}, unfinished[:rp.CiphertextSize]...))
+	rw, err := rp.NewEncryptingWriter(resumed)
+	if err != nil {
+		t.Fatalf("rp.NewEncryptingWriter failed: %s", err)
+	}
+	if _, err := rw.Write(pt[rp.PlaintextOffset:]); err != nil {
+		t.Fatalf("rw.Write failed: %s", err)
+	}
+	if err := rw.Close(); err != nil {
+		t.Fatalf("rw.Close failed: %s", err)
+	}
+	if _, err := rp.NewEncryptingWriter(&bytes.Buffer{}); err == nil {
+		t.Error("second rp.NewEncryptingWriter succeeded")
+	}
+
+	ra, err := streamingaead.New(rotated)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	r, err := ra.NewDecryptingReader(resumed, aad)
+	if err != nil {
+		t.Fatalf("ra.NewDecryptingReader failed: %s", err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ioutil.ReadAll failed: %s", err)
+	}
+	if !bytes.Equal(got, pt) {
+		t.Error("resumed ciphertext does not decrypt to the plaintext")
+	}
+
+	if _, err := streamingaead.FindResumePoint(rotated, bytes.NewReader(unfinished), int64(len(unfinished)), []byte("other aad")); err == nil {
+		t.Error("streamingaead.FindResumePoint with wrong aad succeeded")
+	}
+}
+
+func TestFindResumePointWithoutCompleteSegment(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	unfinished := random.GetRandomBytes(100)
+	rp, err := streamingaead.FindResumePoint(kh, bytes.NewReader(unfinished), int64(len(unfinished)), nil)
+	if err != nil {
+		t.Fatalf("streamingaead.FindResumePoint failed: %s", err)
+	}
+	if rp.CiphertextSize != 0 || rp.PlaintextOffset != 0 {
+		t.Errorf("streamingaead.FindResumePoint = %d, %d, want 0, 0", rp.CiphertextSize, rp.PlaintextOffset)
+	}
+	buf := &bytes.Buffer{}
+	w, err := rp.NewEncryptingWriter(buf)
+	if err != nil {
+		t.Fatalf("rp.NewEncryptingWriter failed: %s", err)
+	}
+	if _, err := w.Write([]byte("plaintext")); err != nil {
+		t.Fatalf("w.Write failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close failed: %s", err)
+	}
+	a, err := streamingaead.New(kh)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	r, err := a.NewDecryptingReader(buf, nil)
+	if err != nil {
+		t.Fatalf("a.NewDecryptingReader failed: %s", err)
+	}
+	if got, err := ioutil.ReadAll(r); err != nil || string(got) != "plaintext" {
+		t.Errorf("decrypting = %q, %v, want %q", got, err, "plaintext")
+	}
+
+	garbage := random.GetRandomBytes(10000)
+	if _, err := streamingaead.FindResumePoint(kh, bytes.NewReader(garbage), int64(len(garbage)), nil); err == nil {
+		t.Error("streamingaead.FindResumePoint with random ciphertext succeeded")
+	}
+}
@@ -34,4 +34,13 @@ func init() {
 	if err := registry.RegisterKeyManager(&aesCTRHMACKeyManager{}); err != nil {
 		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
 	}
+
+	if err := registry.RegisterKeyManager(&chaCha20Poly1305HKDFKeyManager{}); err != nil {
+		panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("streamingaead.init() failed: %v", err))
+		}
+	}
 }
@@ -19,16 +19,89 @@ package streamingaead
 import (
 	"fmt"
 	"io"
+	"runtime"
 
 	"github.com/google/tink/go/core/primitiveset"
 	"github.com/google/tink/go/core/registry"
 	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/streamingaead/subtle/noncebased"
 	"github.com/google/tink/go/tink"
 )
 
-// New returns a StreamingAEAD primitive from the given keyset handle.
-func New(h *keyset.Handle) (tink.StreamingAEAD, error) {
-	return NewWithKeyManager(h, nil /*keyManager*/)
+// Option configures the StreamingAEAD primitive returned by New.
+type Option func(*options)
+
+type options struct {
+	// workers is the number of segments that are encrypted or decrypted
+	// concurrently.
+	workers int
+	// onSegment is called for each segment that is encrypted or decrypted.
+	onSegment func(SegmentEvent) error
+}
+
+func newOptions(opts []Option) options {
+	o := options{workers: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithWorkers makes the StreamingAEAD primitive encrypt and decrypt up to n
+// segments concurrently, so that large streams are processed on several cores.
+// Writers buffer and readers read ahead up to n segments, which uses n times
+// the memory of a single segment. If n is zero or less, runtime.GOMAXPROCS(0)
+// workers are used. By default, segments are processed one at a time.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		o.workers = n
+	}
+}
+
+// SegmentEvent describes a ciphertext segment that was encrypted or decrypted
+// by a StreamingAEAD primitive.
+type SegmentEvent struct {
+	// Index is the index of the segment in the ciphertext, starting at 0.
+	Index uint64
+
+	// PlaintextBytes is the number of plaintext bytes encrypted or decrypted
+	// up to and including this segment.
+	PlaintextBytes int64
+
+	// Err is the error encrypting or authenticating the segment, or nil if
+	// the segment was processed successfully.
+	Err error
+}
+
+// WithSegmentCallback makes the writers and readers of the StreamingAEAD
+// primitive call f after each segment is encrypted or decrypted, in the order
+// of the segments, e.g. to report progress. A segment that fails to be
+// authenticated is reported with a non-nil Err before the reader returns the
+// error; if no key can decrypt the first segment, segment 0 is reported with
+// Err set. If f returns an error, the writer or reader stops and returns that
+// error, which allows aborting a stream early.
+//
+// f is shared by all the writers and readers of the primitive and must be safe
+// for concurrent use if they are used concurrently. It is only called for
+// keys of the key types in this package.
+func WithSegmentCallback(f func(SegmentEvent) error) Option {
+	return func(o *options) {
+		o.onSegment = f
+	}
+}
+
+// New returns a StreamingAEAD primitive from the given keyset handle,
+// configured with the given options.
+func New(h *keyset.Handle, opts ...Option) (tink.StreamingAEAD, error) {
+	ps, err := h.Primitives()
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingAEAD(ps, newOptions(opts))
 }
 
 // NewWithKeyManager returns a StreamingAEAD primitive from the given keyset handle and custom key manager.
@@ -38,7 +111,21 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Streaming
 	if err != nil {
 		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
 	}
+	return newWrappedStreamingAEAD(ps, newOptions(nil))
+}
+
+// NewWithRegistry returns a StreamingAEAD primitive from the given keyset
+// handle, configured with the given options, using the key managers of r
+// instead of the global registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry, opts ...Option) (tink.StreamingAEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("streamingaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingAEAD(ps, newOptions(opts))
+}
 
+func newWrappedStreamingAEAD(ps *primitiveset.PrimitiveSet, o options) (tink.StreamingAEAD, error) {
 	_, ok := (ps.Primary.Primitive).(tink.StreamingAEAD)
 	if !ok {
 		return nil, fmt.Errorf("streamingaead_factory: not a StreamingAEAD primitive")
@@ -54,13 +141,25 @@ func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.Streaming
 
 	ret := new(wrappedStreamingAEAD)
 	ret.ps = ps
-	return tink.StreamingAEAD(ret), nil
+	ret.workers = o.workers
+	ret.onSegment = o.onSegment
+	return ret, nil
+}
+
+// configurableStreamingAEAD is implemented by streaming AEAD primitives whose
+// writers and readers can encrypt and decrypt several segments concurrently
+// and report the segments they process.
+type configurableStreamingAEAD interface {
+	NewEncryptingWriterWithParams(w io.Writer, aad []byte, params subtle.StreamParams) (io.WriteCloser, error)
+	NewDecryptingReaderWithParams(r io.Reader, aad []byte, params subtle.StreamParams) (io.Reader, error)
 }
 
 // wrappedStreamingAEAD is an StreamingAEAD implementation that uses the underlying primitive set
 // for deterministic encryption and decryption.
 type wrappedStreamingAEAD struct {
-	ps *primitiveset.PrimitiveSet
+	ps        *primitiveset.PrimitiveSet
+	workers   int
+	onSegment func(SegmentEvent) error
 }
 
 // Asserts that primitiveSet implements the StreamingAEAD interface.
@@ -72,11 +171,17 @@ var _ tink.StreamingAEAD = (*wrappedStreamingAEAD)(nil)
 // and has to be passed in as parameter for decryption.
 func (s *wrappedStreamingAEAD) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
 	primary := s.ps.Primary
+	if err := primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
 	p, ok := (primary.Primitive).(tink.StreamingAEAD)
 	if !ok {
 		return nil, fmt.Errorf("streamingaead_factory: not a StreamingAEAD primitive")
 	}
 
+	if cp, ok := p.(configurableStreamingAEAD); ok && (s.workers > 1 || s.onSegment != nil) {
+		return cp.NewEncryptingWriterWithParams(w, aad, s.streamParams(s.onSegment))
+	}
 	return p.NewEncryptingWriter(w, aad)
 }
 
@@ -90,3 +195,25 @@ func (s *wrappedStreamingAEAD) NewDecryptingReader(r io.Reader, aad []byte) (io.
 		aad:     aad,
 	}, nil
 }
+
+// newDecryptingReader returns a decrypting reader of p, which decrypts up to
+// s.workers segments concurrently and reports them to onSegment if p supports
+// it.
+func (s *wrappedStreamingAEAD) newDecryptingReader(p tink.StreamingAEAD, r io.Reader, aad []byte, onSegment func(SegmentEvent) error) (io.Reader, error) {
+	if cp, ok := p.(configurableStreamingAEAD); ok && (s.workers > 1 || onSegment != nil) {
+		return cp.NewDecryptingReaderWithParams(r, aad, s.streamParams(onSegment))
+	}
+	return p.NewDecryptingReader(r, aad)
+}
+
+// streamParams returns the subtle.StreamParams of the primitive, reporting
+// segments to onSegment.
+func (s *wrappedStreamingAEAD) streamParams(onSegment func(SegmentEvent) error) subtle.StreamParams {
+	params := subtle.StreamParams{Workers: s.workers}
+	if onSegment != nil {
+		params.OnSegment = func(ev noncebased.SegmentEvent) error {
+			return onSegment(SegmentEvent(ev))
+		}
+	}
+	return params
+}
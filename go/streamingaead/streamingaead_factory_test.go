@@ -150,3 +150,256 @@ func TestFactoryWithValidPrimitiveSetType(t *testing.T) {
 		t.Fatalf("New() failed with good *keyset.Handle: %s", err)
 	}
 }
+
+func TestNewSeekableDecryptingReaderAt(t *testing.T) {
+	ks := testutil.NewTestAESGCMHKDFKeyset()
+	kh, err := testkeyset.NewHandle(ks)
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	// Encrypt with a non-primary key, so that the right key has to be found.
+	rawKey := ks.Key[1]
+	encKH, err := testkeyset.NewHandle(testutil.NewKeyset(rawKey.KeyId, []*tinkpb.Keyset_Key{rawKey}))
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	a, err := streamingaead.New(encKH)
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	pt := random.GetRandomBytes(10000)
+	aad := []byte("aad")
+	buf := &bytes.Buffer{}
+	w, err := a.NewEncryptingWriter(buf, aad)
+	if err != nil {
+		t.Fatalf("a.NewEncryptingWriter failed: %s", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("w.Write failed: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close failed: %s", err)
+	}
+	ct := buf.Bytes()
+
+	r, err := streamingaead.NewSeekableDecryptingReaderAt(kh, bytes.NewReader(ct), int64(len(ct)), aad)
+	if err != nil {
+		t.Fatalf("streamingaead.NewSeekableDecryptingReaderAt failed: %s", err)
+	}
+	if r.Size() != int64(len(pt)) {
+		t.Errorf("r.Size() = %d, want %d", r.Size(), len(pt))
+	}
+	got := make([]byte, 100)
+	if _, err := r.ReadAt(got, 5000); err != nil {
+		t.Fatalf("r.ReadAt failed: %s", err)
+	}
+	if !bytes.Equal(got, pt[5000:5100]) {
+		t.Errorf("r.ReadAt returned wrong plaintext")
+	}
+	if _, err := r.Seek(9000, io.SeekStart); err != nil {
+		t.Fatalf("r.Seek failed: %s", err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("io.ReadAll failed: %s", err)
+	}
+	if !bytes.Equal(rest, pt[9000:]) {
+		t.Errorf("reading after r.Seek returned wrong plaintext")
+	}
+
+	if _, err := streamingaead.NewSeekableDecryptingReaderAt(kh, bytes.NewReader(ct), int64(len(ct)), []byte("other aad")); err == nil {
+		t.Error("streamingaead.NewSeekableDecryptingReaderAt with wrong aad succeeded")
+	}
+	otherKH, err := testkeyset.NewHandle(testutil.NewTestAESGCMHKDFKeyset())
+	if err != nil {
+		t.Fatalf("testkeyset.NewHandle failed: %s", err)
+	}
+	if _, err := streamingaead.NewSeekableDecryptingReaderAt(otherKH, bytes.NewReader(ct), int64(len(ct)), aad); err == nil {
+		t.Error("streamingaead.NewSeekableDecryptingReaderAt with other keyset succeeded")
+	}
+}
+
+func TestFactoryWithWorkers(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		streamingaead.AES128GCMHKDF4KBKeyTemplate(),
+		streamingaead.AES128CTRHMACSHA256Segment4KBKeyTemplate(),
+	} {
+		kh, err := keyset.NewHandle(template)
+		if err != nil {
+			t.Fatalf("keyset.NewHandle failed: %s", err)
+		}
+		sequential, err := streamingaead.New(kh)
+		if err != nil {
+			t.Fatalf("streamingaead.New failed: %s", err)
+		}
+		parallel, err := streamingaead.New(kh, streamingaead.WithWorkers(4))
+		if err != nil {
+			t.Fatalf("streamingaead.New with WithWorkers failed: %s", err)
+		}
+		for _, size := range []int{0, 100, 4096, 50000} {
+			pt := random.GetRandomBytes(uint32(size))
+			aad := []byte("aad")
+			for _, tc := range []struct {
+				name     string
+				enc, dec tink.StreamingAEAD
+			}{
+				{"parallel encryption", parallel, sequential},
+				{"parallel decryption", sequential, parallel},
+				{"parallel encryption and decryption", parallel, parallel},
+			} {
+				buf := &bytes.Buffer{}
+				w, err := tc.enc.NewEncryptingWriter(buf, aad)
+				if err != nil {
+					t.Fatalf("%s: NewEncryptingWriter failed: %s", tc.name, err)
+				}
+				if _, err := w.Write(pt); err != nil {
+					t.Fatalf("%s: w.Write failed: %s", tc.name, err)
+				}
+				if err := w.Close(); err != nil {
+					t.Fatalf("%s: w.Close failed: %s", tc.name, err)
+				}
+				r, err := tc.dec.NewDecryptingReader(buf, aad)
+				if err != nil {
+					t.Fatalf("%s: NewDecryptingReader failed: %s", tc.name, err)
+				}
+				got, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("%s: io.ReadAll failed: %s", tc.name, err)
+				}
+				if !bytes.Equal(got, pt) {
+					t.Errorf("%s: decrypted %d bytes, want %d bytes of plaintext", tc.name, len(got), size)
+				}
+			}
+		}
+	}
+}
+
+func TestFactoryWithSegmentCallback(t *testing.T) {
+	template := streamingaead.AES128GCMHKDF(streamingaead.WithSegmentSize(256))
+	oldHandle, err := keyset.NewHandle(template)
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	manager := keyset.NewManagerFromHandle(oldHandle)
+	if err := manager.Rotate(template); err != nil {
+		t.Fatalf("manager.Rotate failed: %s", err)
+	}
+	kh, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %s", err)
+	}
+	aad := []byte("aad")
+	pt := random.GetRandomBytes(1000)
+
+	for _, workers := range []int{1, 4} {
+		// Encrypt with the old key, so that decryption first tries the
+		// primary key in some order and must not report its failures.
+		var encEvents []streamingaead.SegmentEvent
+		enc, err := streamingaead.New(oldHandle, streamingaead.WithWorkers(workers), streamingaead.WithSegmentCallback(func(ev streamingaead.SegmentEvent) error {
+			encEvents = append(encEvents, ev)
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("streamingaead.New failed: %s", err)
+		}
+		buf := &bytes.Buffer{}
+		w, err := enc.NewEncryptingWriter(buf, aad)
+		if err != nil {
+			t.Fatalf("NewEncryptingWriter failed: %s", err)
+		}
+		if _, err := w.Write(pt); err != nil {
+			t.Fatalf("w.Write failed: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("w.Close failed: %s", err)
+		}
+		ct := buf.Bytes()
+		if len(encEvents) == 0 || encEvents[len(encEvents)-1].PlaintextBytes != int64(len(pt)) {
+			t.Fatalf("workers=%d: encryption events = %+v, want %d plaintext bytes in total", workers, encEvents, len(pt))
+		}
+
+		var decEvents []streamingaead.SegmentEvent
+		dec, err := streamingaead.New(kh, streamingaead.WithWorkers(workers), streamingaead.WithSegmentCallback(func(ev streamingaead.SegmentEvent) error {
+			decEvents = append(decEvents, ev)
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("streamingaead.New failed: %s", err)
+		}
+		r, err := dec.NewDecryptingReader(bytes.NewReader(ct), aad)
+		if err != nil {
+			t.Fatalf("NewDecryptingReader failed: %s", err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, pt) {
+			t.Fatalf("workers=%d: io.ReadAll = %d bytes, %v, want %d bytes", workers, len(got), err, len(pt))
+		}
+		if len(decEvents) != len(encEvents) {
+			t.Fatalf("workers=%d: got %d decryption events, want %d", workers, len(decEvents), len(encEvents))
+		}
+		for i, ev := range decEvents {
+			if ev != encEvents[i] {
+				t.Errorf("workers=%d: decryption event %d = %+v, want %+v", workers, i, ev, encEvents[i])
+			}
+		}
+
+		// A modified segment is reported before the error is returned.
+		modified := append([]byte{}, ct...)
+		modified[len(modified)-1] ^= 1
+		decEvents = nil
+		r, err = dec.NewDecryptingReader(bytes.NewReader(modified), aad)
+		if err != nil {
+			t.Fatalf("NewDecryptingReader failed: %s", err)
+		}
+		if _, err := io.ReadAll(r); err == nil {
+			t.Fatalf("workers=%d: decrypting modified ciphertext succeeded", workers)
+		}
+		last := decEvents[len(decEvents)-1]
+		if last.Index != uint64(len(encEvents)-1) || last.Err == nil {
+			t.Errorf("workers=%d: last event = %+v, want failure of segment %d", workers, last, len(encEvents)-1)
+		}
+
+		// Returning an error from the callback aborts decryption.
+		abort, err := streamingaead.New(kh, streamingaead.WithWorkers(workers), streamingaead.WithSegmentCallback(func(ev streamingaead.SegmentEvent) error {
+			if ev.Index == 1 {
+				return fmt.Errorf("abort")
+			}
+			return nil
+		}))
+		if err != nil {
+			t.Fatalf("streamingaead.New failed: %s", err)
+		}
+		r, err = abort.NewDecryptingReader(bytes.NewReader(ct), aad)
+		if err != nil {
+			t.Fatalf("NewDecryptingReader failed: %s", err)
+		}
+		if got, err := io.ReadAll(r); err == nil || err.Error() != "abort" || len(got) > 2*256 {
+			t.Errorf("workers=%d: io.ReadAll with aborting callback = %d bytes, %v, want abort", workers, len(got), err)
+		}
+	}
+}
+
+func TestFactoryWithSegmentCallbackKeyNotFound(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %s", err)
+	}
+	var events []streamingaead.SegmentEvent
+	a, err := streamingaead.New(kh, streamingaead.WithSegmentCallback(func(ev streamingaead.SegmentEvent) error {
+		events = append(events, ev)
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("streamingaead.New failed: %s", err)
+	}
+	r, err := a.NewDecryptingReader(bytes.NewReader(random.GetRandomBytes(100)), nil)
+	if err != nil {
+		t.Fatalf("NewDecryptingReader failed: %s", err)
+	}
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("decrypting random data succeeded")
+	}
+	if len(events) != 1 || events[0].Index != 0 || events[0].Err == nil {
+		t.Errorf("events = %+v, want a single failure of segment 0", events)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	chpb "github.com/google/tink/go/proto/chacha20_poly1305_hkdf_streaming_go_proto"
 	ctrhmacpb "github.com/google/tink/go/proto/aes_ctr_hmac_streaming_go_proto"
 	gcmhkdfpb "github.com/google/tink/go/proto/aes_gcm_hkdf_streaming_go_proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
@@ -36,7 +37,7 @@ import (
 //   - Size of AES-GCM derived keys: 16 bytes
 //   - Ciphertext segment size: 4096 bytes
 func AES128GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESGCMHKDFKeyTemplate(16, commonpb.HashType_SHA256, 16, 4096)
+	return newAESGCMHKDFKeyTemplate(16, commonpb.HashType_SHA256, 16, 4096, 0, 0)
 }
 
 // AES128GCMHKDF1MBKeyTemplate is a KeyTemplate that generates an AES-GCM key with the following parameters:
@@ -45,7 +46,7 @@ func AES128GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
 //   - Size of AES-GCM derived keys: 16 bytes
 //   - Ciphertext segment size: 1048576 bytes (1 MB)
 func AES128GCMHKDF1MBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESGCMHKDFKeyTemplate(16, commonpb.HashType_SHA256, 16, 1048576)
+	return newAESGCMHKDFKeyTemplate(16, commonpb.HashType_SHA256, 16, 1048576, 0, 0)
 }
 
 // AES256GCMHKDF4KBKeyTemplate is a KeyTemplate that generates an AES-GCM key with the following parameters:
@@ -54,7 +55,7 @@ func AES128GCMHKDF1MBKeyTemplate() *tinkpb.KeyTemplate {
 //   - Size of AES-GCM derived keys: 32 bytes
 //   - Ciphertext segment size: 4096 bytes
 func AES256GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESGCMHKDFKeyTemplate(32, commonpb.HashType_SHA256, 32, 4096)
+	return newAESGCMHKDFKeyTemplate(32, commonpb.HashType_SHA256, 32, 4096, 0, 0)
 }
 
 // AES256GCMHKDF1MBKeyTemplate is a KeyTemplate that generates an AES-GCM key with the following parameters:
@@ -63,7 +64,7 @@ func AES256GCMHKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
 //   - Size of AES-GCM derived keys: 32 bytes
 //   - Ciphertext segment size: 1048576 bytes (1 MB)
 func AES256GCMHKDF1MBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESGCMHKDFKeyTemplate(32, commonpb.HashType_SHA256, 32, 1048576)
+	return newAESGCMHKDFKeyTemplate(32, commonpb.HashType_SHA256, 32, 1048576, 0, 0)
 }
 
 // AES128CTRHMACSHA256Segment4KBKeyTemplate is a KeyTemplate that generates an
@@ -75,7 +76,7 @@ func AES256GCMHKDF1MBKeyTemplate() *tinkpb.KeyTemplate {
 //		- Tag size: 32 bytes
 //		- Ciphertext segment size: 4096 bytes (4 KB)
 func AES128CTRHMACSHA256Segment4KBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESCTRHMACKeyTemplate(16, commonpb.HashType_SHA256, 16, commonpb.HashType_SHA256, 32, 4096)
+	return newAESCTRHMACKeyTemplate(16, commonpb.HashType_SHA256, 16, commonpb.HashType_SHA256, 32, 4096, 0, 0)
 }
 
 // AES128CTRHMACSHA256Segment1MBKeyTemplate is a KeyTemplate that generates an
@@ -87,7 +88,7 @@ func AES128CTRHMACSHA256Segment4KBKeyTemplate() *tinkpb.KeyTemplate {
 //		- Tag size: 32 bytes
 //		- Ciphertext segment size: 1048576 bytes (1 MB)
 func AES128CTRHMACSHA256Segment1MBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESCTRHMACKeyTemplate(16, commonpb.HashType_SHA256, 16, commonpb.HashType_SHA256, 32, 1048576)
+	return newAESCTRHMACKeyTemplate(16, commonpb.HashType_SHA256, 16, commonpb.HashType_SHA256, 32, 1048576, 0, 0)
 }
 
 // AES256CTRHMACSHA256Segment4KBKeyTemplate is a KeyTemplate that generates an
@@ -99,7 +100,7 @@ func AES128CTRHMACSHA256Segment1MBKeyTemplate() *tinkpb.KeyTemplate {
 //		- Tag size: 32 bytes
 //		- Ciphertext segment size: 4096 bytes (4 KB)
 func AES256CTRHMACSHA256Segment4KBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, 4096)
+	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, 4096, 0, 0)
 }
 
 // AES256CTRHMACSHA256Segment1MBKeyTemplate is a KeyTemplate that generates an
@@ -111,7 +112,146 @@ func AES256CTRHMACSHA256Segment4KBKeyTemplate() *tinkpb.KeyTemplate {
 //		- Tag size: 32 bytes
 //		- Ciphertext segment size: 1048576 bytes (1 MB)
 func AES256CTRHMACSHA256Segment1MBKeyTemplate() *tinkpb.KeyTemplate {
-	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, 1048576)
+	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, 1048576, 0, 0)
+}
+
+// ChaCha20Poly1305HKDF4KBKeyTemplate is a KeyTemplate that generates a
+// ChaCha20-Poly1305 key with the following parameters:
+//   - Main key size: 32 bytes
+//   - HKDF algo: HMAC-SHA256
+//   - Ciphertext segment size: 4096 bytes
+func ChaCha20Poly1305HKDF4KBKeyTemplate() *tinkpb.KeyTemplate {
+	return newChaCha20Poly1305HKDFKeyTemplate(commonpb.HashType_SHA256, 4096, 0, 0)
+}
+
+// ChaCha20Poly1305HKDF1MBKeyTemplate is a KeyTemplate that generates a
+// ChaCha20-Poly1305 key with the following parameters:
+//   - Main key size: 32 bytes
+//   - HKDF algo: HMAC-SHA256
+//   - Ciphertext segment size: 1048576 bytes (1 MB)
+func ChaCha20Poly1305HKDF1MBKeyTemplate() *tinkpb.KeyTemplate {
+	return newChaCha20Poly1305HKDFKeyTemplate(commonpb.HashType_SHA256, 1048576, 0, 0)
+}
+
+// TemplateOption customizes the KeyTemplate returned by AES128GCMHKDF,
+// AES256GCMHKDF, AES128CTRHMACSHA256, AES256CTRHMACSHA256 and
+// ChaCha20Poly1305HKDF.
+type TemplateOption func(*templateOptions)
+
+type templateOptions struct {
+	segmentSize        uint32
+	firstSegmentOffset uint32
+	segmentsPerKey     uint32
+}
+
+// WithSegmentSize sets the size in bytes of the ciphertext segments of keys of
+// the template. Larger segments have less overhead, smaller segments allow
+// finer-grained random access; matching the part size of an object store
+// allows uploading each part as soon as it is encrypted. The size is validated
+// when a key is generated from the template.
+func WithSegmentSize(size uint32) TemplateOption {
+	return func(o *templateOptions) {
+		o.segmentSize = size
+	}
+}
+
+// WithFirstSegmentOffset shortens the first ciphertext segment of keys of the
+// template by offset bytes. Callers can then write offset bytes, e.g. their own
+// file header, before the ciphertext and keep the segments aligned with the
+// segment size. The offset is not written to or read from the ciphertext
+// stream: the reader passed for decryption must start after these bytes.
+func WithFirstSegmentOffset(offset uint32) TemplateOption {
+	return func(o *templateOptions) {
+		o.firstSegmentOffset = offset
+	}
+}
+
+// WithSegmentsPerKey makes keys of the template derive a fresh segment key for
+// every n segments of a ciphertext, i.e. every n times the segment size bytes.
+// This raises the amount of data that can safely be encrypted in a single
+// ciphertext, e.g. a long-lived append-only log. Keys using this option can
+// only be used by Tink Go. A value of zero disables rekeying.
+func WithSegmentsPerKey(n uint32) TemplateOption {
+	return func(o *templateOptions) {
+		o.segmentsPerKey = n
+	}
+}
+
+func newTemplateOptions(opts []TemplateOption) templateOptions {
+	o := templateOptions{segmentSize: 4096}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// AES128GCMHKDF returns a KeyTemplate that generates an AES-GCM key with the following parameters:
+//   - Main key size: 16 bytes
+//   - HKDF algo: HMAC-SHA256
+//   - Size of AES-GCM derived keys: 16 bytes
+//   - Ciphertext segment size: 4096 bytes, unless changed with WithSegmentSize
+//   - First segment offset: 0 bytes, unless changed with WithFirstSegmentOffset
+//   - Segments per key: unlimited, unless changed with WithSegmentsPerKey
+func AES128GCMHKDF(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(opts)
+	return newAESGCMHKDFKeyTemplate(16, commonpb.HashType_SHA256, 16, o.segmentSize, o.firstSegmentOffset, o.segmentsPerKey)
+}
+
+// AES256GCMHKDF returns a KeyTemplate that generates an AES-GCM key with the following parameters:
+//   - Main key size: 32 bytes
+//   - HKDF algo: HMAC-SHA256
+//   - Size of AES-GCM derived keys: 32 bytes
+//   - Ciphertext segment size: 4096 bytes, unless changed with WithSegmentSize
+//   - First segment offset: 0 bytes, unless changed with WithFirstSegmentOffset
+//   - Segments per key: unlimited, unless changed with WithSegmentsPerKey
+func AES256GCMHKDF(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(opts)
+	return newAESGCMHKDFKeyTemplate(32, commonpb.HashType_SHA256, 32, o.segmentSize, o.firstSegmentOffset, o.segmentsPerKey)
+}
+
+// AES128CTRHMACSHA256 returns a KeyTemplate that generates an AES-CTR-HMAC key
+// with the following parameters:
+//   - Main key size: 16 bytes
+//   - HKDF algorthim: HMAC-SHA256
+//   - AES-CTR derived key size: 16 bytes
+//   - Tag algorithm: HMAC-SHA256
+//   - Tag size: 32 bytes
+//   - Ciphertext segment size: 4096 bytes, unless changed with WithSegmentSize
+//   - First segment offset: 0 bytes, unless changed with WithFirstSegmentOffset
+//   - Segments per key: unlimited, unless changed with WithSegmentsPerKey
+func AES128CTRHMACSHA256(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(opts)
+	return newAESCTRHMACKeyTemplate(16, commonpb.HashType_SHA256, 16, commonpb.HashType_SHA256, 32, o.segmentSize, o.firstSegmentOffset, o.segmentsPerKey)
+}
+
+// AES256CTRHMACSHA256 returns a KeyTemplate that generates an AES-CTR-HMAC key
+// with the following parameters:
+//   - Main key size: 32 bytes
+//   - HKDF algorthim: HMAC-SHA256
+//   - AES-CTR derived key size: 32 bytes
+//   - Tag algorithm: HMAC-SHA256
+//   - Tag size: 32 bytes
+//   - Ciphertext segment size: 4096 bytes, unless changed with WithSegmentSize
+//   - First segment offset: 0 bytes, unless changed with WithFirstSegmentOffset
+//   - Segments per key: unlimited, unless changed with WithSegmentsPerKey
+func AES256CTRHMACSHA256(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(opts)
+	return newAESCTRHMACKeyTemplate(32, commonpb.HashType_SHA256, 32, commonpb.HashType_SHA256, 32, o.segmentSize, o.firstSegmentOffset, o.segmentsPerKey)
+}
+
+// ChaCha20Poly1305HKDF returns a KeyTemplate that generates a
+// ChaCha20-Poly1305 key with the following parameters:
+//   - Main key size: 32 bytes
+//   - HKDF algo: HMAC-SHA256
+//   - Ciphertext segment size: 4096 bytes, unless changed with WithSegmentSize
+//   - First segment offset: 0 bytes, unless changed with WithFirstSegmentOffset
+//   - Segments per key: unlimited, unless changed with WithSegmentsPerKey
+//
+// Unlike the AES based key types, it is fast on platforms without hardware
+// support for AES. Keys of this type can only be used by Tink Go.
+func ChaCha20Poly1305HKDF(opts ...TemplateOption) *tinkpb.KeyTemplate {
+	o := newTemplateOptions(opts)
+	return newChaCha20Poly1305HKDFKeyTemplate(commonpb.HashType_SHA256, o.segmentSize, o.firstSegmentOffset, o.segmentsPerKey)
 }
 
 // newAESGCMHKDFKeyTemplate creates a KeyTemplate containing a AesGcmHkdfStreamingKeyFormat with
@@ -121,6 +261,8 @@ func newAESGCMHKDFKeyTemplate(
 	hkdfHashType commonpb.HashType,
 	derivedKeySize uint32,
 	ciphertextSegmentSize uint32,
+	firstSegmentOffset uint32,
+	segmentsPerKey uint32,
 ) *tinkpb.KeyTemplate {
 	serializedFormat, err := proto.Marshal(&gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{
 		KeySize: mainKeySize,
@@ -128,6 +270,8 @@ func newAESGCMHKDFKeyTemplate(
 			CiphertextSegmentSize: ciphertextSegmentSize,
 			DerivedKeySize:        derivedKeySize,
 			HkdfHashType:          hkdfHashType,
+			FirstSegmentOffset:    firstSegmentOffset,
+			SegmentsPerKey:        segmentsPerKey,
 		},
 	})
 	if err != nil {
@@ -149,6 +293,8 @@ func newAESCTRHMACKeyTemplate(
 	tagAlg commonpb.HashType,
 	tagSize uint32,
 	ciphertextSegmentSize uint32,
+	firstSegmentOffset uint32,
+	segmentsPerKey uint32,
 ) *tinkpb.KeyTemplate {
 	serializedFormat, err := proto.Marshal(&ctrhmacpb.AesCtrHmacStreamingKeyFormat{
 		KeySize: mainKeySize,
@@ -160,6 +306,8 @@ func newAESCTRHMACKeyTemplate(
 				Hash:    tagAlg,
 				TagSize: tagSize,
 			},
+			FirstSegmentOffset: firstSegmentOffset,
+			SegmentsPerKey:     segmentsPerKey,
 		},
 	})
 	if err != nil {
@@ -171,3 +319,46 @@ func newAESCTRHMACKeyTemplate(
 		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
 	}
 }
+
+// newChaCha20Poly1305HKDFKeyTemplate creates a KeyTemplate containing a
+// ChaCha20Poly1305HkdfStreamingKeyFormat with the specified parameters.
+func newChaCha20Poly1305HKDFKeyTemplate(
+	hkdfHashType commonpb.HashType,
+	ciphertextSegmentSize uint32,
+	firstSegmentOffset uint32,
+	segmentsPerKey uint32,
+) *tinkpb.KeyTemplate {
+	serializedFormat, err := proto.Marshal(&chpb.ChaCha20Poly1305HkdfStreamingKeyFormat{
+		KeySize: 32,
+		Params: &chpb.ChaCha20Poly1305HkdfStreamingParams{
+			CiphertextSegmentSize: ciphertextSegmentSize,
+			HkdfHashType:          hkdfHashType,
+			FirstSegmentOffset:    firstSegmentOffset,
+			SegmentsPerKey:        segmentsPerKey,
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal key: %s", err))
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          chaCha20Poly1305HKDFTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"AES128_GCM_HKDF_4KB":        AES128GCMHKDF4KBKeyTemplate,
+	"AES128_GCM_HKDF_1MB":        AES128GCMHKDF1MBKeyTemplate,
+	"AES256_GCM_HKDF_4KB":        AES256GCMHKDF4KBKeyTemplate,
+	"AES256_GCM_HKDF_1MB":        AES256GCMHKDF1MBKeyTemplate,
+	"AES128_CTR_HMAC_SHA256_4KB": AES128CTRHMACSHA256Segment4KBKeyTemplate,
+	"AES128_CTR_HMAC_SHA256_1MB": AES128CTRHMACSHA256Segment1MBKeyTemplate,
+	"AES256_CTR_HMAC_SHA256_4KB": AES256CTRHMACSHA256Segment4KBKeyTemplate,
+	"AES256_CTR_HMAC_SHA256_1MB": AES256CTRHMACSHA256Segment1MBKeyTemplate,
+	"CHACHA20_POLY1305_HKDF_4KB": ChaCha20Poly1305HKDF4KBKeyTemplate,
+	"CHACHA20_POLY1305_HKDF_1MB": ChaCha20Poly1305HKDF1MBKeyTemplate,
+}
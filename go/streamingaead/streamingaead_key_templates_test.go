@@ -19,12 +19,17 @@ package streamingaead_test
 import (
 	"bytes"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/subtle/random"
 	"github.com/google/tink/go/testutil"
+	chpb "github.com/google/tink/go/proto/chacha20_poly1305_hkdf_streaming_go_proto"
+	ctrhmacpb "github.com/google/tink/go/proto/aes_ctr_hmac_streaming_go_proto"
+	gcmhkdfpb "github.com/google/tink/go/proto/aes_gcm_hkdf_streaming_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 )
 
@@ -111,3 +116,109 @@ func TestKeyTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateOptions(t *testing.T) {
+	opts := []streamingaead.TemplateOption{
+		streamingaead.WithSegmentSize(1 << 16),
+		streamingaead.WithFirstSegmentOffset(100),
+		streamingaead.WithSegmentsPerKey(2),
+	}
+	for _, tc := range []struct {
+		name     string
+		template *tinkpb.KeyTemplate
+	}{
+		{"AES128GCMHKDF", streamingaead.AES128GCMHKDF(opts...)},
+		{"AES256GCMHKDF", streamingaead.AES256GCMHKDF(opts...)},
+		{"AES128CTRHMACSHA256", streamingaead.AES128CTRHMACSHA256(opts...)},
+		{"AES256CTRHMACSHA256", streamingaead.AES256CTRHMACSHA256(opts...)},
+		{"ChaCha20Poly1305HKDF", streamingaead.ChaCha20Poly1305HKDF(opts...)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var segmentSize, offset, segmentsPerKey uint32
+			if strings.Contains(tc.name, "ChaCha") {
+				format := &chpb.ChaCha20Poly1305HkdfStreamingKeyFormat{}
+				if err := proto.Unmarshal(tc.template.GetValue(), format); err != nil {
+					t.Fatalf("proto.Unmarshal failed: %v", err)
+				}
+				segmentSize, offset, segmentsPerKey = format.GetParams().GetCiphertextSegmentSize(), format.GetParams().GetFirstSegmentOffset(), format.GetParams().GetSegmentsPerKey()
+			} else if strings.Contains(tc.name, "GCM") {
+				format := &gcmhkdfpb.AesGcmHkdfStreamingKeyFormat{}
+				if err := proto.Unmarshal(tc.template.GetValue(), format); err != nil {
+					t.Fatalf("proto.Unmarshal failed: %v", err)
+				}
+				segmentSize, offset, segmentsPerKey = format.GetParams().GetCiphertextSegmentSize(), format.GetParams().GetFirstSegmentOffset(), format.GetParams().GetSegmentsPerKey()
+			} else {
+				format := &ctrhmacpb.AesCtrHmacStreamingKeyFormat{}
+				if err := proto.Unmarshal(tc.template.GetValue(), format); err != nil {
+					t.Fatalf("proto.Unmarshal failed: %v", err)
+				}
+				segmentSize, offset, segmentsPerKey = format.GetParams().GetCiphertextSegmentSize(), format.GetParams().GetFirstSegmentOffset(), format.GetParams().GetSegmentsPerKey()
+			}
+			if segmentSize != 1<<16 || offset != 100 || segmentsPerKey != 2 {
+				t.Errorf("segment size, offset, segments per key = %d, %d, %d, want %d, %d, %d", segmentSize, offset, segmentsPerKey, 1<<16, 100, 2)
+			}
+
+			handle, err := keyset.NewHandle(tc.template)
+			if err != nil {
+				t.Fatalf("keyset.NewHandle(template) failed: %v", err)
+			}
+			primitive, err := streamingaead.New(handle)
+			if err != nil {
+				t.Fatalf("streamingaead.New(handle) failed: %v", err)
+			}
+			plaintext := random.GetRandomBytes(200000)
+			aad := []byte("extra data to authenticate")
+			buf := &bytes.Buffer{}
+			w, err := primitive.NewEncryptingWriter(buf, aad)
+			if err != nil {
+				t.Fatalf("primitive.NewEncryptingWriter(buf, aad) failed: %v", err)
+			}
+			if _, err := w.Write(plaintext); err != nil {
+				t.Fatalf("w.Write(plaintext) failed: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("w.Close() failed: %v", err)
+			}
+			r, err := primitive.NewDecryptingReader(buf, aad)
+			if err != nil {
+				t.Fatalf("primitive.NewDecryptingReader(buf, aad) failed: %v", err)
+			}
+			decrypted, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ioutil.ReadAll(r) failed: %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Error("decrypted data doesn't match plaintext")
+			}
+		})
+	}
+}
+
+func TestTemplateOptionsDefaults(t *testing.T) {
+	if !proto.Equal(streamingaead.AES128GCMHKDF(), streamingaead.AES128GCMHKDF4KBKeyTemplate()) {
+		t.Error("AES128GCMHKDF() != AES128GCMHKDF4KBKeyTemplate()")
+	}
+	if !proto.Equal(streamingaead.AES256CTRHMACSHA256(streamingaead.WithSegmentSize(1048576)), streamingaead.AES256CTRHMACSHA256Segment1MBKeyTemplate()) {
+		t.Error("AES256CTRHMACSHA256(WithSegmentSize(1048576)) != AES256CTRHMACSHA256Segment1MBKeyTemplate()")
+	}
+	if !proto.Equal(streamingaead.ChaCha20Poly1305HKDF(), streamingaead.ChaCha20Poly1305HKDF4KBKeyTemplate()) {
+		t.Error("ChaCha20Poly1305HKDF() != ChaCha20Poly1305HKDF4KBKeyTemplate()")
+	}
+	if !proto.Equal(streamingaead.ChaCha20Poly1305HKDF(streamingaead.WithSegmentSize(1048576)), streamingaead.ChaCha20Poly1305HKDF1MBKeyTemplate()) {
+		t.Error("ChaCha20Poly1305HKDF(WithSegmentSize(1048576)) != ChaCha20Poly1305HKDF1MBKeyTemplate()")
+	}
+}
+
+func TestTemplateOptionsInvalidOffset(t *testing.T) {
+	for _, template := range []*tinkpb.KeyTemplate{
+		streamingaead.AES128GCMHKDF(streamingaead.WithFirstSegmentOffset(4096)),
+		streamingaead.AES128CTRHMACSHA256(streamingaead.WithFirstSegmentOffset(4096)),
+		streamingaead.AES256GCMHKDF(streamingaead.WithSegmentSize(100), streamingaead.WithFirstSegmentOffset(50)),
+		streamingaead.ChaCha20Poly1305HKDF(streamingaead.WithFirstSegmentOffset(4096)),
+		streamingaead.ChaCha20Poly1305HKDF(streamingaead.WithSegmentSize(56)),
+	} {
+		if _, err := keyset.NewHandle(template); err == nil {
+			t.Errorf("keyset.NewHandle(%v) succeeded, want error", template)
+		}
+	}
+}
@@ -55,6 +55,7 @@ type AESCTRHMAC struct {
 	ciphertextSegmentSize        int
 	plaintextSegmentSize         int
 	firstCiphertextSegmentOffset int
+	segmentsPerKey               uint32
 }
 
 // NewAESCTRHMAC initializes an AESCTRHMAC primitive with a key derivation key
@@ -83,6 +84,23 @@ func NewAESCTRHMAC(
 	tagSizeInBytes int,
 	ciphertextSegmentSize int,
 	firstSegmentOffset int,
+) (*AESCTRHMAC, error) {
+	return NewAESCTRHMACWithSegmentsPerKey(mainKey, hkdfAlg, keySizeInBytes, tagAlg, tagSizeInBytes, ciphertextSegmentSize, firstSegmentOffset, 0)
+}
+
+// NewAESCTRHMACWithSegmentsPerKey is like NewAESCTRHMAC, but derives fresh
+// AES-CTR and HMAC keys for every segmentsPerKey segments of a ciphertext,
+// which raises the amount of data that can safely be encrypted in one
+// ciphertext. If segmentsPerKey is zero, all segments use the same keys.
+func NewAESCTRHMACWithSegmentsPerKey(
+	mainKey []byte,
+	hkdfAlg string,
+	keySizeInBytes int,
+	tagAlg string,
+	tagSizeInBytes int,
+	ciphertextSegmentSize int,
+	firstSegmentOffset int,
+	segmentsPerKey uint32,
 ) (*AESCTRHMAC, error) {
 	if len(mainKey) < 16 || len(mainKey) < keySizeInBytes {
 		return nil, errors.New("mainKey too short")
@@ -117,6 +135,7 @@ func NewAESCTRHMAC(
 		ciphertextSegmentSize:        ciphertextSegmentSize,
 		firstCiphertextSegmentOffset: firstSegmentOffset + headerLen,
 		plaintextSegmentSize:         ciphertextSegmentSize - tagSizeInBytes,
+		segmentsPerKey:               segmentsPerKey,
 	}, nil
 }
 
@@ -132,6 +151,43 @@ func (a *AESCTRHMAC) deriveKeyMaterial(salt, aad []byte) ([]byte, error) {
 	return subtle.ComputeHKDF(a.hkdfAlg, a.MainKey, salt, aad, uint32(keyMaterialSize))
 }
 
+// newSegmentCipher returns the segmentCipher of a ciphertext with the given
+// derived key material.
+func (a *AESCTRHMAC) newSegmentCipher(km []byte) (segmentCipher, error) {
+	return newSegmentCipher(a.hkdfAlg, km, AESCTRHMACNoncePrefixSizeInBytes, a.segmentsPerKey, func(km []byte) (segmentCipher, error) {
+		aesKey := make([]byte, a.keySizeInBytes)
+		copy(aesKey, km)
+		blockCipher, err := aes.NewCipher(aesKey)
+		if err != nil {
+			return nil, err
+		}
+
+		hmacKey := make([]byte, AESCTRHMACKeySizeInBytes)
+		copy(hmacKey, km[a.keySizeInBytes:])
+		hmac, err := subtlemac.NewHMAC(a.tagAlg, hmacKey, uint32(a.tagSizeInBytes))
+		if err != nil {
+			return nil, err
+		}
+		return aesCTRHMACSegmentCipher{
+			aesCTRHMACSegmentEncrypter: aesCTRHMACSegmentEncrypter{
+				blockCipher:    blockCipher,
+				hmac:           hmac,
+				tagSizeInBytes: a.tagSizeInBytes,
+			},
+			aesCTRHMACSegmentDecrypter: aesCTRHMACSegmentDecrypter{
+				blockCipher:    blockCipher,
+				hmac:           hmac,
+				tagSizeInBytes: a.tagSizeInBytes,
+			},
+		}, nil
+	})
+}
+
+type aesCTRHMACSegmentCipher struct {
+	aesCTRHMACSegmentEncrypter
+	aesCTRHMACSegmentDecrypter
+}
+
 type aesCTRHMACSegmentEncrypter struct {
 	noncebased.SegmentEncrypter
 	blockCipher    cipher.Block
@@ -174,6 +230,19 @@ type aesCTRHMACWriter struct {
 // data is not included in the ciphertext and has to be passed in as parameter
 // for decryption.
 func (a *AESCTRHMAC) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: 1})
+}
+
+// NewEncryptingWriterWithWorkers is like NewEncryptingWriter, but encrypts up
+// to workers segments concurrently. The returned writer buffers up to workers
+// segments of plaintext.
+func (a *AESCTRHMAC) NewEncryptingWriterWithWorkers(w io.Writer, aad []byte, workers int) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: workers})
+}
+
+// NewEncryptingWriterWithParams is like NewEncryptingWriter, but configures
+// the returned writer with params.
+func (a *AESCTRHMAC) NewEncryptingWriterWithParams(w io.Writer, aad []byte, params StreamParams) (io.WriteCloser, error) {
 	salt := random.GetRandomBytes(uint32(a.keySizeInBytes))
 	noncePrefix := random.GetRandomBytes(AESCTRHMACNoncePrefixSizeInBytes)
 
@@ -182,16 +251,7 @@ func (a *AESCTRHMAC) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteClose
 		return nil, err
 	}
 
-	aesKey := make([]byte, a.keySizeInBytes)
-	copy(aesKey, km)
-	blockCipher, err := aes.NewCipher(aesKey)
-	if err != nil {
-		return nil, err
-	}
-
-	hmacKey := make([]byte, AESCTRHMACKeySizeInBytes)
-	copy(hmacKey, km[a.keySizeInBytes:])
-	hmac, err := subtlemac.NewHMAC(a.tagAlg, hmacKey, uint32(a.tagSizeInBytes))
+	sc, err := a.newSegmentCipher(km)
 	if err != nil {
 		return nil, err
 	}
@@ -205,16 +265,14 @@ func (a *AESCTRHMAC) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteClose
 	}
 
 	nw, err := noncebased.NewWriter(noncebased.WriterParams{
-		W: w,
-		SegmentEncrypter: aesCTRHMACSegmentEncrypter{
-			blockCipher:    blockCipher,
-			hmac:           hmac,
-			tagSizeInBytes: a.tagSizeInBytes,
-		},
+		W:                            w,
+		SegmentEncrypter:             sc,
 		NonceSize:                    AESCTRHMACNonceSizeInBytes,
 		NoncePrefix:                  noncePrefix,
 		PlaintextSegmentSize:         a.plaintextSegmentSize,
 		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
 	})
 	if err != nil {
 		return nil, err
@@ -260,58 +318,139 @@ type aesCTRHMACReader struct {
 // any read-operation via the wrapper results in AEAD-decryption of the
 // underlying ciphertext, using aad as associated authenticated data.
 func (a *AESCTRHMAC) NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
-	hlen := make([]byte, 1)
-	if _, err := io.ReadFull(r, hlen); err != nil {
-		return nil, err
-	}
-	if hlen[0] != byte(a.HeaderLength()) {
-		return nil, errors.New("invalid header length")
-	}
-
-	salt := make([]byte, a.keySizeInBytes)
-	if _, err := io.ReadFull(r, salt); err != nil {
-		return nil, fmt.Errorf("cannot read salt: %v", err)
-	}
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: 1})
+}
 
-	noncePrefix := make([]byte, AESCTRHMACNoncePrefixSizeInBytes)
-	if _, err := io.ReadFull(r, noncePrefix); err != nil {
-		return nil, fmt.Errorf("cannot read noncePrefix: %v", err)
-	}
+// NewDecryptingReaderWithWorkers is like NewDecryptingReader, but decrypts up
+// to workers segments concurrently. The returned reader reads ahead up to
+// workers segments of ciphertext.
+func (a *AESCTRHMAC) NewDecryptingReaderWithWorkers(r io.Reader, aad []byte, workers int) (io.Reader, error) {
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: workers})
+}
 
-	km, err := a.deriveKeyMaterial(salt, aad)
+// NewDecryptingReaderWithParams is like NewDecryptingReader, but configures
+// the returned reader with params.
+func (a *AESCTRHMAC) NewDecryptingReaderWithParams(r io.Reader, aad []byte, params StreamParams) (io.Reader, error) {
+	decrypter, noncePrefix, err := a.readHeader(r, aad)
 	if err != nil {
 		return nil, err
 	}
 
-	aesKey := make([]byte, a.keySizeInBytes)
-	copy(aesKey, km)
-	blockCipher, err := aes.NewCipher(aesKey)
+	nr, err := noncebased.NewReader(noncebased.ReaderParams{
+		R:                            r,
+		SegmentDecrypter:             decrypter,
+		NonceSize:                    AESCTRHMACNonceSizeInBytes,
+		NoncePrefix:                  noncePrefix,
+		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	hmacKey := make([]byte, AESCTRHMACKeySizeInBytes)
-	copy(hmacKey, km[a.keySizeInBytes:])
-	hmac, err := subtlemac.NewHMAC(a.tagAlg, hmacKey, uint32(a.tagSizeInBytes))
+	return &aesCTRHMACReader{Reader: nr}, nil
+}
+
+// NewDecryptingReaderAt returns a reader that decrypts the ciphertext of the
+// given size in r on demand, using aad as associated authenticated data. The
+// returned reader provides random access to the plaintext, and its size is
+// the size of the plaintext.
+func (a *AESCTRHMAC) NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen {
+		return nil, errors.New("ciphertext too short")
+	}
+	decrypter, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
 	if err != nil {
 		return nil, err
 	}
 
-	nr, err := noncebased.NewReader(noncebased.ReaderParams{
-		R: r,
-		SegmentDecrypter: aesCTRHMACSegmentDecrypter{
-			blockCipher:    blockCipher,
-			hmac:           hmac,
-			tagSizeInBytes: a.tagSizeInBytes,
-		},
+	nr, err := noncebased.NewReaderAt(noncebased.ReaderAtParams{
+		R:                            io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                         size - headerLen,
+		SegmentDecrypter:             decrypter,
 		NonceSize:                    AESCTRHMACNonceSizeInBytes,
 		NoncePrefix:                  noncePrefix,
 		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		PlaintextSegmentSize:         a.plaintextSegmentSize,
 		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
 	})
 	if err != nil {
 		return nil, err
 	}
+	return io.NewSectionReader(nr, 0, nr.Size()), nil
+}
+
+// NewResumingEncryptingWriter returns a writer that continues the encryption of
+// the unfinished ciphertext of the given size in r, e.g. an interrupted upload,
+// using aad as associated authenticated data. It also returns the size of the
+// part of the ciphertext that is kept and the size of the plaintext encrypted
+// in it: the caller must truncate the ciphertext to the former, and write the
+// plaintext after the latter to the returned writer, which writes to w.
+//
+// noncebased.ErrNoCompleteSegment is returned if the ciphertext has no
+// complete segment, in which case encryption has to start over.
+func (a *AESCTRHMAC) NewResumingEncryptingWriter(w io.Writer, r io.ReaderAt, size int64, aad []byte) (io.WriteCloser, int64, int64, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen+int64(a.ciphertextSegmentSize-a.firstCiphertextSegmentOffset) {
+		return nil, 0, 0, noncebased.ErrNoCompleteSegment
+	}
+	sc, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
+	if err != nil {
+		return nil, 0, 0, err
+	}
 
-	return &aesCTRHMACReader{Reader: nr}, nil
+	nw, ciphertextSize, plaintextSize, err := noncebased.NewResumingWriter(noncebased.ResumingWriterParams{
+		WriterParams: noncebased.WriterParams{
+			W:                            w,
+			SegmentEncrypter:             sc,
+			NonceSize:                    AESCTRHMACNonceSizeInBytes,
+			NoncePrefix:                  noncePrefix,
+			PlaintextSegmentSize:         a.plaintextSegmentSize,
+			FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		},
+		R:                     io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                  size - headerLen,
+		SegmentDecrypter:      sc,
+		CiphertextSegmentSize: a.ciphertextSegmentSize,
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &aesCTRHMACWriter{Writer: nw}, headerLen + ciphertextSize, plaintextSize, nil
+}
+
+// readHeader reads the header of a ciphertext from r and returns the segment
+// cipher and the nonce prefix of the ciphertext.
+func (a *AESCTRHMAC) readHeader(r io.Reader, aad []byte) (segmentCipher, []byte, error) {
+	hlen := make([]byte, 1)
+	if _, err := io.ReadFull(r, hlen); err != nil {
+		return nil, nil, err
+	}
+	if hlen[0] != byte(a.HeaderLength()) {
+		return nil, nil, errors.New("invalid header length")
+	}
+
+	salt := make([]byte, a.keySizeInBytes)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, nil, fmt.Errorf("cannot read salt: %v", err)
+	}
+
+	noncePrefix := make([]byte, AESCTRHMACNoncePrefixSizeInBytes)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, nil, fmt.Errorf("cannot read noncePrefix: %v", err)
+	}
+
+	km, err := a.deriveKeyMaterial(salt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := a.newSegmentCipher(km)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sc, noncePrefix, nil
 }
@@ -54,6 +54,7 @@ type AESGCMHKDF struct {
 	ciphertextSegmentSize        int
 	firstCiphertextSegmentOffset int
 	plaintextSegmentSize         int
+	segmentsPerKey               uint32
 }
 
 // NewAESGCMHKDF initializes a streaming primitive with a key derivation key
@@ -75,6 +76,21 @@ func NewAESGCMHKDF(
 	keySizeInBytes int,
 	ciphertextSegmentSize int,
 	firstSegmentOffset int,
+) (*AESGCMHKDF, error) {
+	return NewAESGCMHKDFWithSegmentsPerKey(mainKey, hkdfAlg, keySizeInBytes, ciphertextSegmentSize, firstSegmentOffset, 0)
+}
+
+// NewAESGCMHKDFWithSegmentsPerKey is like NewAESGCMHKDF, but derives a fresh
+// AES-GCM key for every segmentsPerKey segments of a ciphertext, which raises
+// the amount of data that can safely be encrypted in one ciphertext. If
+// segmentsPerKey is zero, all segments use the same key.
+func NewAESGCMHKDFWithSegmentsPerKey(
+	mainKey []byte,
+	hkdfAlg string,
+	keySizeInBytes int,
+	ciphertextSegmentSize int,
+	firstSegmentOffset int,
+	segmentsPerKey uint32,
 ) (*AESGCMHKDF, error) {
 	if len(mainKey) < 16 || len(mainKey) < keySizeInBytes {
 		return nil, errors.New("mainKey too short")
@@ -97,6 +113,7 @@ func NewAESGCMHKDF(
 		ciphertextSegmentSize:        ciphertextSegmentSize,
 		firstCiphertextSegmentOffset: firstSegmentOffset + headerLen,
 		plaintextSegmentSize:         ciphertextSegmentSize - AESGCMHKDFTagSizeInBytes,
+		segmentsPerKey:               segmentsPerKey,
 	}, nil
 }
 
@@ -124,6 +141,26 @@ func (a *AESGCMHKDF) newCipher(key []byte) (cipher.AEAD, error) {
 	return aesGCMCipher, nil
 }
 
+// newSegmentCipher returns the segmentCipher of a ciphertext with the given
+// derived key.
+func (a *AESGCMHKDF) newSegmentCipher(dkey []byte) (segmentCipher, error) {
+	return newSegmentCipher(a.hkdfAlg, dkey, AESGCMHKDFNoncePrefixSizeInBytes, a.segmentsPerKey, func(key []byte) (segmentCipher, error) {
+		c, err := a.newCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return aesGCMHKDFSegmentCipher{
+			aesGCMHKDFSegmentEncrypter: aesGCMHKDFSegmentEncrypter{cipher: c},
+			aesGCMHKDFSegmentDecrypter: aesGCMHKDFSegmentDecrypter{cipher: c},
+		}, nil
+	})
+}
+
+type aesGCMHKDFSegmentCipher struct {
+	aesGCMHKDFSegmentEncrypter
+	aesGCMHKDFSegmentDecrypter
+}
+
 type aesGCMHKDFSegmentEncrypter struct {
 	noncebased.SegmentEncrypter
 	cipher cipher.AEAD
@@ -149,6 +186,19 @@ type aesGCMHKDFWriter struct {
 // data is not included in the ciphertext and has to be passed in as parameter
 // for decryption.
 func (a *AESGCMHKDF) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: 1})
+}
+
+// NewEncryptingWriterWithWorkers is like NewEncryptingWriter, but encrypts up
+// to workers segments concurrently. The returned writer buffers up to workers
+// segments of plaintext.
+func (a *AESGCMHKDF) NewEncryptingWriterWithWorkers(w io.Writer, aad []byte, workers int) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: workers})
+}
+
+// NewEncryptingWriterWithParams is like NewEncryptingWriter, but configures
+// the returned writer with params.
+func (a *AESGCMHKDF) NewEncryptingWriterWithParams(w io.Writer, aad []byte, params StreamParams) (io.WriteCloser, error) {
 	salt := random.GetRandomBytes(uint32(a.keySizeInBytes))
 	noncePrefix := random.GetRandomBytes(AESGCMHKDFNoncePrefixSizeInBytes)
 
@@ -157,7 +207,7 @@ func (a *AESGCMHKDF) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteClose
 		return nil, err
 	}
 
-	cipher, err := a.newCipher(dkey)
+	sc, err := a.newSegmentCipher(dkey)
 	if err != nil {
 		return nil, err
 	}
@@ -172,11 +222,13 @@ func (a *AESGCMHKDF) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteClose
 
 	nw, err := noncebased.NewWriter(noncebased.WriterParams{
 		W:                            w,
-		SegmentEncrypter:             aesGCMHKDFSegmentEncrypter{cipher: cipher},
+		SegmentEncrypter:             sc,
 		NonceSize:                    AESGCMHKDFNonceSizeInBytes,
 		NoncePrefix:                  noncePrefix,
 		PlaintextSegmentSize:         a.plaintextSegmentSize,
 		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
 	})
 	if err != nil {
 		return nil, err
@@ -208,45 +260,139 @@ type aesGCMHKDFReader struct {
 // any read-operation via the wrapper results in AEAD-decryption of the
 // underlying ciphertext, using aad as associated authenticated data.
 func (a *AESGCMHKDF) NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
-	hlen := make([]byte, 1)
-	if _, err := io.ReadFull(r, hlen); err != nil {
-		return nil, err
-	}
-	if hlen[0] != byte(a.HeaderLength()) {
-		return nil, errors.New("invalid header length")
-	}
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: 1})
+}
 
-	salt := make([]byte, a.keySizeInBytes)
-	if _, err := io.ReadFull(r, salt); err != nil {
-		return nil, fmt.Errorf("cannot read salt: %v", err)
-	}
+// NewDecryptingReaderWithWorkers is like NewDecryptingReader, but decrypts up
+// to workers segments concurrently. The returned reader reads ahead up to
+// workers segments of ciphertext.
+func (a *AESGCMHKDF) NewDecryptingReaderWithWorkers(r io.Reader, aad []byte, workers int) (io.Reader, error) {
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: workers})
+}
 
-	noncePrefix := make([]byte, AESGCMHKDFNoncePrefixSizeInBytes)
-	if _, err := io.ReadFull(r, noncePrefix); err != nil {
-		return nil, fmt.Errorf("cannot read noncePrefix: %v", err)
+// NewDecryptingReaderWithParams is like NewDecryptingReader, but configures
+// the returned reader with params.
+func (a *AESGCMHKDF) NewDecryptingReaderWithParams(r io.Reader, aad []byte, params StreamParams) (io.Reader, error) {
+	decrypter, noncePrefix, err := a.readHeader(r, aad)
+	if err != nil {
+		return nil, err
 	}
 
-	dkey, err := a.deriveKey(salt, aad)
+	nr, err := noncebased.NewReader(noncebased.ReaderParams{
+		R:                            r,
+		SegmentDecrypter:             decrypter,
+		NonceSize:                    AESGCMHKDFNonceSizeInBytes,
+		NoncePrefix:                  noncePrefix,
+		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	cipher, err := a.newCipher(dkey)
+	return &aesGCMHKDFReader{Reader: nr}, nil
+}
+
+// NewDecryptingReaderAt returns a reader that decrypts the ciphertext of the
+// given size in r on demand, using aad as associated authenticated data. The
+// returned reader provides random access to the plaintext, and its size is
+// the size of the plaintext.
+func (a *AESGCMHKDF) NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen {
+		return nil, errors.New("ciphertext too short")
+	}
+	decrypter, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
 	if err != nil {
 		return nil, err
 	}
 
-	nr, err := noncebased.NewReader(noncebased.ReaderParams{
-		R:                            r,
-		SegmentDecrypter:             aesGCMHKDFSegmentDecrypter{cipher: cipher},
+	nr, err := noncebased.NewReaderAt(noncebased.ReaderAtParams{
+		R:                            io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                         size - headerLen,
+		SegmentDecrypter:             decrypter,
 		NonceSize:                    AESGCMHKDFNonceSizeInBytes,
 		NoncePrefix:                  noncePrefix,
 		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		PlaintextSegmentSize:         a.plaintextSegmentSize,
 		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
 	})
 	if err != nil {
 		return nil, err
 	}
+	return io.NewSectionReader(nr, 0, nr.Size()), nil
+}
 
-	return &aesGCMHKDFReader{Reader: nr}, nil
+// NewResumingEncryptingWriter returns a writer that continues the encryption of
+// the unfinished ciphertext of the given size in r, e.g. an interrupted upload,
+// using aad as associated authenticated data. It also returns the size of the
+// part of the ciphertext that is kept and the size of the plaintext encrypted
+// in it: the caller must truncate the ciphertext to the former, and write the
+// plaintext after the latter to the returned writer, which writes to w.
+//
+// noncebased.ErrNoCompleteSegment is returned if the ciphertext has no
+// complete segment, in which case encryption has to start over.
+func (a *AESGCMHKDF) NewResumingEncryptingWriter(w io.Writer, r io.ReaderAt, size int64, aad []byte) (io.WriteCloser, int64, int64, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen+int64(a.ciphertextSegmentSize-a.firstCiphertextSegmentOffset) {
+		return nil, 0, 0, noncebased.ErrNoCompleteSegment
+	}
+	sc, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	nw, ciphertextSize, plaintextSize, err := noncebased.NewResumingWriter(noncebased.ResumingWriterParams{
+		WriterParams: noncebased.WriterParams{
+			W:                            w,
+			SegmentEncrypter:             sc,
+			NonceSize:                    AESGCMHKDFNonceSizeInBytes,
+			NoncePrefix:                  noncePrefix,
+			PlaintextSegmentSize:         a.plaintextSegmentSize,
+			FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		},
+		R:                     io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                  size - headerLen,
+		SegmentDecrypter:      sc,
+		CiphertextSegmentSize: a.ciphertextSegmentSize,
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &aesGCMHKDFWriter{Writer: nw}, headerLen + ciphertextSize, plaintextSize, nil
+}
+
+// readHeader reads the header of a ciphertext from r and returns the segment
+// cipher and the nonce prefix of the ciphertext.
+func (a *AESGCMHKDF) readHeader(r io.Reader, aad []byte) (segmentCipher, []byte, error) {
+	hlen := make([]byte, 1)
+	if _, err := io.ReadFull(r, hlen); err != nil {
+		return nil, nil, err
+	}
+	if hlen[0] != byte(a.HeaderLength()) {
+		return nil, nil, errors.New("invalid header length")
+	}
+
+	salt := make([]byte, a.keySizeInBytes)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, nil, fmt.Errorf("cannot read salt: %v", err)
+	}
+
+	noncePrefix := make([]byte, AESGCMHKDFNoncePrefixSizeInBytes)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, nil, fmt.Errorf("cannot read noncePrefix: %v", err)
+	}
+
+	dkey, err := a.deriveKey(salt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := a.newSegmentCipher(dkey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sc, noncePrefix, nil
 }
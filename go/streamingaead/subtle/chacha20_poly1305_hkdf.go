@@ -0,0 +1,373 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/streamingaead/subtle/noncebased"
+	"github.com/google/tink/go/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// ChaCha20Poly1305HKDFKeySizeInBytes is the size of the main key and of the
+	// derived ChaCha20-Poly1305 keys.
+	ChaCha20Poly1305HKDFKeySizeInBytes = chacha20poly1305.KeySize
+
+	// ChaCha20Poly1305HKDFNonceSizeInBytes is the size of the nonces used for
+	// ChaCha20-Poly1305.
+	ChaCha20Poly1305HKDFNonceSizeInBytes = chacha20poly1305.NonceSize
+
+	// ChaCha20Poly1305HKDFNoncePrefixSizeInBytes is the size of the randomly
+	// generated nonce prefix.
+	ChaCha20Poly1305HKDFNoncePrefixSizeInBytes = 7
+
+	// ChaCha20Poly1305HKDFTagSizeInBytes is the size of the tags of each
+	// ciphertext segment.
+	ChaCha20Poly1305HKDFTagSizeInBytes = 16
+)
+
+// ChaCha20Poly1305HKDF implements streaming AEAD encryption using
+// ChaCha20-Poly1305. It is an alternative to AESGCMHKDF for platforms without
+// hardware support for AES.
+//
+// Each ciphertext uses a new ChaCha20-Poly1305 key. These keys are derived
+// using HKDF and are derived from the key derivation key, a randomly chosen
+// salt of the same size as the key and a nonce prefix.
+type ChaCha20Poly1305HKDF struct {
+	MainKey                      []byte
+	hkdfAlg                      string
+	ciphertextSegmentSize        int
+	firstCiphertextSegmentOffset int
+	plaintextSegmentSize         int
+	segmentsPerKey               uint32
+}
+
+// NewChaCha20Poly1305HKDF initializes a streaming primitive with a key
+// derivation key and encryption parameters.
+//
+// mainKey is an input keying material used to derive sub keys. It must be at
+// least 32 bytes long.
+//
+// hkdfAlg is a MAC algorithm name, e.g., HmacSha256, used for the HKDF key
+// derivation.
+//
+// ciphertextSegmentSize argument is the size of ciphertext segments.
+//
+// firstSegmentOffset argument is the offset of the first ciphertext segment.
+//
+// segmentsPerKey is the number of segments after which a fresh key is derived,
+// or zero if all segments use the same key.
+func NewChaCha20Poly1305HKDF(
+	mainKey []byte,
+	hkdfAlg string,
+	ciphertextSegmentSize int,
+	firstSegmentOffset int,
+	segmentsPerKey uint32,
+) (*ChaCha20Poly1305HKDF, error) {
+	if len(mainKey) < ChaCha20Poly1305HKDFKeySizeInBytes {
+		return nil, errors.New("mainKey too short")
+	}
+	headerLen := 1 + ChaCha20Poly1305HKDFKeySizeInBytes + ChaCha20Poly1305HKDFNoncePrefixSizeInBytes
+	if ciphertextSegmentSize <= firstSegmentOffset+headerLen+ChaCha20Poly1305HKDFTagSizeInBytes {
+		return nil, errors.New("ciphertextSegmentSize too small")
+	}
+
+	keyClone := make([]byte, len(mainKey))
+	copy(keyClone, mainKey)
+
+	return &ChaCha20Poly1305HKDF{
+		MainKey:                      keyClone,
+		hkdfAlg:                      hkdfAlg,
+		ciphertextSegmentSize:        ciphertextSegmentSize,
+		firstCiphertextSegmentOffset: firstSegmentOffset + headerLen,
+		plaintextSegmentSize:         ciphertextSegmentSize - ChaCha20Poly1305HKDFTagSizeInBytes,
+		segmentsPerKey:               segmentsPerKey,
+	}, nil
+}
+
+// HeaderLength returns the length of the encryption header.
+func (a *ChaCha20Poly1305HKDF) HeaderLength() int {
+	return 1 + ChaCha20Poly1305HKDFKeySizeInBytes + ChaCha20Poly1305HKDFNoncePrefixSizeInBytes
+}
+
+// deriveKey returns a key derived from the given main key using salt and aad
+// parameters.
+func (a *ChaCha20Poly1305HKDF) deriveKey(salt, aad []byte) ([]byte, error) {
+	return subtle.ComputeHKDF(a.hkdfAlg, a.MainKey, salt, aad, ChaCha20Poly1305HKDFKeySizeInBytes)
+}
+
+// newSegmentCipher returns the segmentCipher of a ciphertext with the given
+// derived key.
+func (a *ChaCha20Poly1305HKDF) newSegmentCipher(dkey []byte) (segmentCipher, error) {
+	return newSegmentCipher(a.hkdfAlg, dkey, ChaCha20Poly1305HKDFNoncePrefixSizeInBytes, a.segmentsPerKey, func(key []byte) (segmentCipher, error) {
+		c, err := chacha20poly1305.New(key)
+		if err != nil {
+			return nil, err
+		}
+		return chaCha20Poly1305HKDFSegmentCipher{
+			chaCha20Poly1305HKDFSegmentEncrypter: chaCha20Poly1305HKDFSegmentEncrypter{cipher: c},
+			chaCha20Poly1305HKDFSegmentDecrypter: chaCha20Poly1305HKDFSegmentDecrypter{cipher: c},
+		}, nil
+	})
+}
+
+type chaCha20Poly1305HKDFSegmentCipher struct {
+	chaCha20Poly1305HKDFSegmentEncrypter
+	chaCha20Poly1305HKDFSegmentDecrypter
+}
+
+type chaCha20Poly1305HKDFSegmentEncrypter struct {
+	noncebased.SegmentEncrypter
+	cipher cipher.AEAD
+}
+
+func (e chaCha20Poly1305HKDFSegmentEncrypter) EncryptSegment(segment, nonce []byte) ([]byte, error) {
+	result := make([]byte, len(segment))
+	result = e.cipher.Seal(result[0:0], nonce, segment, nil)
+	return result, nil
+}
+
+// chaCha20Poly1305HKDFWriter works as a wrapper around underlying io.Writer,
+// which is responsible for encrypting written data. The data is encrypted and
+// flushed in segments of a given size.  Once all the data is written
+// chaCha20Poly1305HKDFWriter must be closed.
+type chaCha20Poly1305HKDFWriter struct {
+	*noncebased.Writer
+}
+
+// NewEncryptingWriter returns a wrapper around underlying io.Writer, such that
+// any write-operation via the wrapper results in AEAD-encryption of the
+// written data, using aad as associated authenticated data. The associated
+// data is not included in the ciphertext and has to be passed in as parameter
+// for decryption.
+func (a *ChaCha20Poly1305HKDF) NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: 1})
+}
+
+// NewEncryptingWriterWithWorkers is like NewEncryptingWriter, but encrypts up
+// to workers segments concurrently. The returned writer buffers up to workers
+// segments of plaintext.
+func (a *ChaCha20Poly1305HKDF) NewEncryptingWriterWithWorkers(w io.Writer, aad []byte, workers int) (io.WriteCloser, error) {
+	return a.NewEncryptingWriterWithParams(w, aad, StreamParams{Workers: workers})
+}
+
+// NewEncryptingWriterWithParams is like NewEncryptingWriter, but configures
+// the returned writer with params.
+func (a *ChaCha20Poly1305HKDF) NewEncryptingWriterWithParams(w io.Writer, aad []byte, params StreamParams) (io.WriteCloser, error) {
+	salt := random.GetRandomBytes(ChaCha20Poly1305HKDFKeySizeInBytes)
+	noncePrefix := random.GetRandomBytes(ChaCha20Poly1305HKDFNoncePrefixSizeInBytes)
+
+	dkey, err := a.deriveKey(salt, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, err := a.newSegmentCipher(dkey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, a.HeaderLength())
+	header[0] = byte(a.HeaderLength())
+	copy(header[1:], salt)
+	copy(header[1+len(salt):], noncePrefix)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	nw, err := noncebased.NewWriter(noncebased.WriterParams{
+		W:                            w,
+		SegmentEncrypter:             sc,
+		NonceSize:                    ChaCha20Poly1305HKDFNonceSizeInBytes,
+		NoncePrefix:                  noncePrefix,
+		PlaintextSegmentSize:         a.plaintextSegmentSize,
+		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaCha20Poly1305HKDFWriter{Writer: nw}, nil
+}
+
+type chaCha20Poly1305HKDFSegmentDecrypter struct {
+	noncebased.SegmentDecrypter
+	cipher cipher.AEAD
+}
+
+func (d chaCha20Poly1305HKDFSegmentDecrypter) DecryptSegment(segment, nonce []byte) ([]byte, error) {
+	result := make([]byte, 0, len(segment))
+	result, err := d.cipher.Open(result, nonce, segment, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// chaCha20Poly1305HKDFReader works as a wrapper around underlying io.Reader.
+type chaCha20Poly1305HKDFReader struct {
+	*noncebased.Reader
+}
+
+// NewDecryptingReader returns a wrapper around underlying io.Reader, such that
+// any read-operation via the wrapper results in AEAD-decryption of the
+// underlying ciphertext, using aad as associated authenticated data.
+func (a *ChaCha20Poly1305HKDF) NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: 1})
+}
+
+// NewDecryptingReaderWithWorkers is like NewDecryptingReader, but decrypts up
+// to workers segments concurrently. The returned reader reads ahead up to
+// workers segments of ciphertext.
+func (a *ChaCha20Poly1305HKDF) NewDecryptingReaderWithWorkers(r io.Reader, aad []byte, workers int) (io.Reader, error) {
+	return a.NewDecryptingReaderWithParams(r, aad, StreamParams{Workers: workers})
+}
+
+// NewDecryptingReaderWithParams is like NewDecryptingReader, but configures
+// the returned reader with params.
+func (a *ChaCha20Poly1305HKDF) NewDecryptingReaderWithParams(r io.Reader, aad []byte, params StreamParams) (io.Reader, error) {
+	decrypter, noncePrefix, err := a.readHeader(r, aad)
+	if err != nil {
+		return nil, err
+	}
+
+	nr, err := noncebased.NewReader(noncebased.ReaderParams{
+		R:                            r,
+		SegmentDecrypter:             decrypter,
+		NonceSize:                    ChaCha20Poly1305HKDFNonceSizeInBytes,
+		NoncePrefix:                  noncePrefix,
+		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		Workers:                      params.Workers,
+		OnSegment:                    params.OnSegment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaCha20Poly1305HKDFReader{Reader: nr}, nil
+}
+
+// NewDecryptingReaderAt returns a reader that decrypts the ciphertext of the
+// given size in r on demand, using aad as associated authenticated data. The
+// returned reader provides random access to the plaintext, and its size is
+// the size of the plaintext.
+func (a *ChaCha20Poly1305HKDF) NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen {
+		return nil, errors.New("ciphertext too short")
+	}
+	decrypter, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
+	if err != nil {
+		return nil, err
+	}
+
+	nr, err := noncebased.NewReaderAt(noncebased.ReaderAtParams{
+		R:                            io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                         size - headerLen,
+		SegmentDecrypter:             decrypter,
+		NonceSize:                    ChaCha20Poly1305HKDFNonceSizeInBytes,
+		NoncePrefix:                  noncePrefix,
+		CiphertextSegmentSize:        a.ciphertextSegmentSize,
+		PlaintextSegmentSize:         a.plaintextSegmentSize,
+		FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return io.NewSectionReader(nr, 0, nr.Size()), nil
+}
+
+// NewResumingEncryptingWriter returns a writer that continues the encryption of
+// the unfinished ciphertext of the given size in r, e.g. an interrupted upload,
+// using aad as associated authenticated data. It also returns the size of the
+// part of the ciphertext that is kept and the size of the plaintext encrypted
+// in it: the caller must truncate the ciphertext to the former, and write the
+// plaintext after the latter to the returned writer, which writes to w.
+//
+// noncebased.ErrNoCompleteSegment is returned if the ciphertext has no
+// complete segment, in which case encryption has to start over.
+func (a *ChaCha20Poly1305HKDF) NewResumingEncryptingWriter(w io.Writer, r io.ReaderAt, size int64, aad []byte) (io.WriteCloser, int64, int64, error) {
+	headerLen := int64(a.HeaderLength())
+	if size < headerLen+int64(a.ciphertextSegmentSize-a.firstCiphertextSegmentOffset) {
+		return nil, 0, 0, noncebased.ErrNoCompleteSegment
+	}
+	sc, noncePrefix, err := a.readHeader(io.NewSectionReader(r, 0, headerLen), aad)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	nw, ciphertextSize, plaintextSize, err := noncebased.NewResumingWriter(noncebased.ResumingWriterParams{
+		WriterParams: noncebased.WriterParams{
+			W:                            w,
+			SegmentEncrypter:             sc,
+			NonceSize:                    ChaCha20Poly1305HKDFNonceSizeInBytes,
+			NoncePrefix:                  noncePrefix,
+			PlaintextSegmentSize:         a.plaintextSegmentSize,
+			FirstCiphertextSegmentOffset: a.firstCiphertextSegmentOffset,
+		},
+		R:                     io.NewSectionReader(r, headerLen, size-headerLen),
+		Size:                  size - headerLen,
+		SegmentDecrypter:      sc,
+		CiphertextSegmentSize: a.ciphertextSegmentSize,
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &chaCha20Poly1305HKDFWriter{Writer: nw}, headerLen + ciphertextSize, plaintextSize, nil
+}
+
+// readHeader reads the header of a ciphertext from r and returns the segment
+// cipher and the nonce prefix of the ciphertext.
+func (a *ChaCha20Poly1305HKDF) readHeader(r io.Reader, aad []byte) (segmentCipher, []byte, error) {
+	hlen := make([]byte, 1)
+	if _, err := io.ReadFull(r, hlen); err != nil {
+		return nil, nil, err
+	}
+	if hlen[0] != byte(a.HeaderLength()) {
+		return nil, nil, errors.New("invalid header length")
+	}
+
+	salt := make([]byte, ChaCha20Poly1305HKDFKeySizeInBytes)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, nil, fmt.Errorf("cannot read salt: %v", err)
+	}
+
+	noncePrefix := make([]byte, ChaCha20Poly1305HKDFNoncePrefixSizeInBytes)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, nil, fmt.Errorf("cannot read noncePrefix: %v", err)
+	}
+
+	dkey, err := a.deriveKey(salt, aad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := a.newSegmentCipher(dkey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sc, noncePrefix, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/tink/go/streamingaead/subtle"
+)
+
+func TestChaCha20Poly1305HKDFEncryptDecrypt(t *testing.T) {
+	testCases := []struct {
+		name               string
+		segmentSize        int
+		firstSegmentOffset int
+		plaintextSize      int
+		chunkSize          int
+	}{
+		{"small", 256, 0, 20, 64},
+		{"small-offset", 256, 8, 20, 64},
+		{"empty", 256, 0, 0, 128},
+		{"empty-offset", 256, 8, 0, 128},
+		{"medium", 512, 0, 3086, 128},
+		{"medium-offset", 512, 20, 3086, 256},
+		{"large-chunks", 1024, 0, 12345, 5000},
+		{"last-segment-full", 256, 0, 216, 64},
+		{"single-byte", 512, 0, 5086, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cipher, err := subtle.NewChaCha20Poly1305HKDF(ikm, "SHA256", tc.segmentSize, tc.firstSegmentOffset, 0)
+			if err != nil {
+				t.Fatalf("Cannot create a cipher: %v", err)
+			}
+
+			pt, ct, err := encrypt(cipher, aad, tc.plaintextSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := decrypt(cipher, aad, pt, ct, tc.chunkSize); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestChaCha20Poly1305HKDFModifiedCiphertext(t *testing.T) {
+	const (
+		segmentSize        = 256
+		firstSegmentOffset = 8
+		plaintextSize      = 1024
+		chunkSize          = 128
+	)
+	cipher, err := subtle.NewChaCha20Poly1305HKDF(ikm, "SHA256", segmentSize, firstSegmentOffset, 0)
+	if err != nil {
+		t.Fatalf("Cannot create a cipher: %v", err)
+	}
+	pt, ct, err := encrypt(cipher, aad, plaintextSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < len(ct); i += 8 {
+		if err := decrypt(cipher, aad, pt, ct[:i], chunkSize); err == nil {
+			t.Errorf("decrypt() of ciphertext truncated to %d bytes succeeded", i)
+		}
+	}
+	for i := range ct {
+		ct2 := append([]byte{}, ct...)
+		ct2[i] ^= byte(1)
+		if err := decrypt(cipher, aad, pt, ct2, chunkSize); err == nil {
+			t.Errorf("decrypt() of ciphertext with byte %d modified succeeded", i)
+		}
+	}
+	if err := decrypt(cipher, []byte("other aad"), pt, ct, chunkSize); err == nil {
+		t.Error("decrypt() with wrong aad succeeded")
+	}
+}
+
+func TestChaCha20Poly1305HKDFWorkersAndReaderAt(t *testing.T) {
+	cipher, err := subtle.NewChaCha20Poly1305HKDF(ikm, "SHA256", 256, 0, 3)
+	if err != nil {
+		t.Fatalf("Cannot create a cipher: %v", err)
+	}
+	pt := bytes.Repeat([]byte("chacha"), 1000)
+	buf := &bytes.Buffer{}
+	w, err := cipher.NewEncryptingWriterWithWorkers(buf, aad, 4)
+	if err != nil {
+		t.Fatalf("NewEncryptingWriterWithWorkers() err = %v", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("Write() err = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() err = %v", err)
+	}
+	ct := buf.Bytes()
+
+	if err := decrypt(cipher, aad, pt, ct, 100); err != nil {
+		t.Error(err)
+	}
+	r, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReaderAt() err = %v", err)
+	}
+	got := make([]byte, 500)
+	if _, err := r.ReadAt(got, 3000); err != nil {
+		t.Fatalf("ReadAt() err = %v", err)
+	}
+	if !bytes.Equal(got, pt[3000:3500]) {
+		t.Error("ReadAt() returned wrong plaintext")
+	}
+}
+
+func TestChaCha20Poly1305HKDFInvalidParameters(t *testing.T) {
+	if _, err := subtle.NewChaCha20Poly1305HKDF(ikm[:16], "SHA256", 256, 0, 0); err == nil {
+		t.Error("NewChaCha20Poly1305HKDF() with a 16 byte key succeeded")
+	}
+	if _, err := subtle.NewChaCha20Poly1305HKDF(ikm, "SHA256", 56, 0, 0); err == nil {
+		t.Error("NewChaCha20Poly1305HKDF() with a too small segment size succeeded")
+	}
+}
@@ -53,6 +53,7 @@ import (
 	"errors"
 	"io"
 	"math"
+	"sync"
 )
 
 var (
@@ -66,6 +67,10 @@ var (
 
 	// ErrTooManySegments indicates that the ciphertext has too many segments.
 	ErrTooManySegments = errors.New("too many segments")
+
+	// ErrNoCompleteSegment indicates that an unfinished ciphertext has no
+	// complete segment after which encryption can be resumed.
+	ErrNoCompleteSegment = errors.New("no complete segment")
 )
 
 // SegmentEncrypter facilitates implementing various streaming AEAD encryption
@@ -89,6 +94,48 @@ type Writer struct {
 	plaintextPos                 int
 	ciphertext                   []byte
 	closed                       bool
+	workers                      int
+	// batch holds the segments which are waiting to be encrypted by the
+	// workers.
+	batch     []pendingSegment
+	onSegment func(SegmentEvent) error
+	// plaintextBytes is the number of plaintext bytes encrypted so far.
+	plaintextBytes int64
+}
+
+// pendingSegment is a segment with its index and nonce, waiting to be
+// encrypted or decrypted.
+type pendingSegment struct {
+	index   uint64
+	segment []byte
+	nonce   []byte
+}
+
+// SegmentEvent describes a segment that was processed by a Writer or a
+// Reader.
+type SegmentEvent struct {
+	// Index is the index of the segment in the ciphertext, starting at 0.
+	Index uint64
+
+	// PlaintextBytes is the number of plaintext bytes processed up to and
+	// including this segment.
+	PlaintextBytes int64
+
+	// Err is the error encrypting or authenticating the segment, or nil if
+	// the segment was processed successfully.
+	Err error
+}
+
+// notifySegment calls onSegment, if it is not nil, with the given event. It
+// returns the error of the event, or else the error returned by onSegment.
+func notifySegment(onSegment func(SegmentEvent) error, ev SegmentEvent) error {
+	if onSegment == nil {
+		return ev.Err
+	}
+	if err := onSegment(ev); err != nil && ev.Err == nil {
+		return err
+	}
+	return ev.Err
 }
 
 // WriterParams contains the options for instantiating a Writer via NewWriter().
@@ -115,6 +162,18 @@ type WriterParams struct {
 	// W. This allows for the existence of overhead in the stream unrelated to
 	// this encryption scheme.
 	FirstCiphertextSegmentOffset int
+
+	// Workers is the number of segments which are encrypted concurrently. The
+	// Writer buffers up to Workers segments of plaintext before encrypting
+	// them. SegmentEncrypter must be safe for concurrent use if Workers is
+	// larger than 1. Segments are encrypted one at a time if Workers is 1 or
+	// less.
+	Workers int
+
+	// OnSegment, if not nil, is called after each segment is encrypted and
+	// written to W, or failed to be encrypted, in the order of the segments.
+	// If it returns an error, the Writer stops and returns that error.
+	OnSegment func(SegmentEvent) error
 }
 
 // NewWriter creates a new Writer instance.
@@ -129,6 +188,8 @@ func NewWriter(params WriterParams) (*Writer, error) {
 		noncePrefix:                  params.NoncePrefix,
 		firstCiphertextSegmentOffset: params.FirstCiphertextSegmentOffset,
 		plaintext:                    make([]byte, params.PlaintextSegmentSize),
+		workers:                      params.Workers,
+		onSegment:                    params.OnSegment,
 	}, nil
 }
 
@@ -156,13 +217,18 @@ func (w *Writer) Write(p []byte) (int, error) {
 			return pos, err
 		}
 
-		w.ciphertext, err = w.segmentEncrypter.EncryptSegment(w.plaintext[:ptLim], nonce)
-		if err != nil {
-			return pos, err
-		}
-
-		if _, err := w.w.Write(w.ciphertext); err != nil {
-			return pos, err
+		if w.workers > 1 {
+			w.batch = append(w.batch, pendingSegment{index: w.encryptedSegmentCnt, segment: w.plaintext[:ptLim], nonce: nonce})
+			w.plaintext = make([]byte, len(w.plaintext))
+			if len(w.batch) == w.workers {
+				if err := w.flushBatch(); err != nil {
+					return pos, err
+				}
+			}
+		} else {
+			if err := w.encryptSegment(w.encryptedSegmentCnt, w.plaintext[:ptLim], nonce); err != nil {
+				return pos, err
+			}
 		}
 
 		w.plaintextPos = 0
@@ -183,21 +249,164 @@ func (w *Writer) Close() error {
 		return err
 	}
 
-	w.ciphertext, err = w.segmentEncrypter.EncryptSegment(w.plaintext[:w.plaintextPos], nonce)
+	if w.workers > 1 {
+		w.batch = append(w.batch, pendingSegment{index: w.encryptedSegmentCnt, segment: w.plaintext[:w.plaintextPos], nonce: nonce})
+		if err := w.flushBatch(); err != nil {
+			return err
+		}
+	} else {
+		if err := w.encryptSegment(w.encryptedSegmentCnt, w.plaintext[:w.plaintextPos], nonce); err != nil {
+			return err
+		}
+	}
+
+	w.plaintextPos = 0
+	w.encryptedSegmentCnt++
+	w.closed = true
+	return nil
+}
+
+// encryptSegment encrypts the segment with the given index and writes its
+// ciphertext to the underlying writer.
+func (w *Writer) encryptSegment(index uint64, segment, nonce []byte) error {
+	var err error
+	w.ciphertext, err = w.segmentEncrypter.EncryptSegment(segment, nonce)
 	if err != nil {
-		return err
+		return notifySegment(w.onSegment, SegmentEvent{Index: index, PlaintextBytes: w.plaintextBytes, Err: err})
 	}
+	return w.writeSegment(index, len(segment), w.ciphertext)
+}
 
-	if _, err := w.w.Write(w.ciphertext); err != nil {
+// writeSegment writes the ciphertext of the segment with the given index and
+// plaintext size to the underlying writer.
+func (w *Writer) writeSegment(index uint64, plaintextSize int, ciphertext []byte) error {
+	if _, err := w.w.Write(ciphertext); err != nil {
 		return err
 	}
+	w.plaintextBytes += int64(plaintextSize)
+	return notifySegment(w.onSegment, SegmentEvent{Index: index, PlaintextBytes: w.plaintextBytes})
+}
 
-	w.plaintextPos = 0
-	w.encryptedSegmentCnt++
-	w.closed = true
+// flushBatch encrypts the pending segments concurrently and writes their
+// ciphertexts in order to the underlying writer.
+func (w *Writer) flushBatch() error {
+	ciphertexts, errs := processBatch(w.batch, w.segmentEncrypter.EncryptSegment)
+	batch := w.batch
+	w.batch = w.batch[:0]
+	for i, ct := range ciphertexts {
+		if errs[i] != nil {
+			return notifySegment(w.onSegment, SegmentEvent{Index: batch[i].index, PlaintextBytes: w.plaintextBytes, Err: errs[i]})
+		}
+		if err := w.writeSegment(batch[i].index, len(batch[i].segment), ct); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// processBatch applies f to each of the segments in its own goroutine, and
+// returns the results in the order of the segments.
+func processBatch(batch []pendingSegment, f func(segment, nonce []byte) ([]byte, error)) ([][]byte, []error) {
+	results := make([][]byte, len(batch))
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	wg.Add(len(batch))
+	for i := range batch {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = f(batch[i].segment, batch[i].nonce)
+		}(i)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// ResumingWriterParams contains the options for instantiating a Writer via
+// NewResumingWriter().
+type ResumingWriterParams struct {
+	WriterParams
+
+	// R contains the unfinished ciphertext, starting at the first segment, i.e.
+	// without header.
+	R io.ReaderAt
+
+	// Size is the size of the unfinished ciphertext in R.
+	Size int64
+
+	// SegmentDecrypter is used to authenticate the segments in R. It must
+	// align with WriterParams.SegmentEncrypter.
+	SegmentDecrypter SegmentDecrypter
+
+	// The size of the ciphertext segments.
+	CiphertextSegmentSize int
+}
+
+// NewResumingWriter creates a Writer that continues an unfinished ciphertext,
+// e.g. an interrupted upload, after its last complete segment. It returns the
+// Writer, the size of the part of R that is kept and the size of the
+// plaintext encrypted in it. The caller must truncate the ciphertext to the
+// kept size, and write the plaintext after the returned size to the Writer.
+//
+// The first and the last kept segments are authenticated. If the last complete
+// segment is the last segment of a finished ciphertext, it is not kept, so
+// that more plaintext can be appended to finished ciphertexts too.
+// ErrNoCompleteSegment is returned if no segment can be kept.
+func NewResumingWriter(params ResumingWriterParams) (*Writer, int64, int64, error) {
+	w, err := NewWriter(params.WriterParams)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	var (
+		ciphertextSegmentSize = int64(params.CiphertextSegmentSize)
+		plaintextSegmentSize  = int64(params.PlaintextSegmentSize)
+		offset                = int64(params.FirstCiphertextSegmentOffset)
+		firstSegmentSize      = ciphertextSegmentSize - offset
+	)
+	if params.Size < firstSegmentSize {
+		return nil, 0, 0, ErrNoCompleteSegment
+	}
+	segments := 1 + (params.Size-firstSegmentSize)/ciphertextSegmentSize
+
+	// authenticate returns nil if segment i is a valid segment, which is the
+	// last segment if last is true.
+	authenticate := func(i int64, last bool) error {
+		start, end := int64(0), firstSegmentSize+i*ciphertextSegmentSize
+		if i > 0 {
+			start = end - ciphertextSegmentSize
+		}
+		segment := make([]byte, end-start)
+		if _, err := params.R.ReadAt(segment, start); err != nil {
+			return err
+		}
+		nonce, err := generateSegmentNonce(params.NonceSize, params.NoncePrefix, uint64(i), last)
+		if err != nil {
+			return err
+		}
+		_, err = params.SegmentDecrypter.DecryptSegment(segment, nonce)
+		return err
+	}
+	if err := authenticate(segments-1, false); err != nil {
+		if authenticate(segments-1, true) != nil {
+			return nil, 0, 0, err
+		}
+		segments--
+		if segments == 0 {
+			return nil, 0, 0, ErrNoCompleteSegment
+		}
+	}
+	if segments > 1 {
+		if err := authenticate(0, false); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	w.encryptedSegmentCnt = uint64(segments)
+	ciphertextSize := firstSegmentSize + (segments-1)*ciphertextSegmentSize
+	plaintextSize := plaintextSegmentSize - offset + (segments-1)*plaintextSegmentSize
+	w.plaintextBytes = plaintextSize
+	return w, ciphertextSize, plaintextSize, nil
+}
+
 // SegmentDecrypter facilitates implementing various streaming AEAD encryption modes.
 type SegmentDecrypter interface {
 	DecryptSegment(segment, nonce []byte) ([]byte, error)
@@ -219,6 +428,18 @@ type Reader struct {
 	plaintextPos                 int
 	ciphertext                   []byte
 	ciphertextPos                int
+	// segmentEnd is the position in ciphertext of the byte read ahead after
+	// the last segment, or zero if there is none.
+	segmentEnd int
+	workers    int
+	// decrypted holds the plaintexts of the segments which were decrypted
+	// ahead by the workers.
+	decrypted [][]byte
+	// err is returned once decrypted is exhausted.
+	err       error
+	onSegment func(SegmentEvent) error
+	// plaintextBytes is the number of plaintext bytes decrypted so far.
+	plaintextBytes int64
 }
 
 // ReaderParams contains the options for instantiating a Reader via NewReader().
@@ -244,6 +465,20 @@ type ReaderParams struct {
 	// in R. This allows for the existence of overhead in the stream unrelated to
 	// this encryption scheme.
 	FirstCiphertextSegmentOffset int
+
+	// Workers is the number of segments which are decrypted concurrently. The
+	// Reader reads ahead up to Workers segments of ciphertext before
+	// decrypting them. SegmentDecrypter must be safe for concurrent use if
+	// Workers is larger than 1. Segments are decrypted one at a time if
+	// Workers is 1 or less.
+	Workers int
+
+	// OnSegment, if not nil, is called after each segment is read and
+	// decrypted, or failed to be authenticated, in the order of the segments.
+	// Segments are reported when they are decrypted, which can be ahead of the
+	// plaintext returned by Read if Workers is larger than 1. If OnSegment
+	// returns an error, the Reader stops and returns that error.
+	OnSegment func(SegmentEvent) error
 }
 
 // NewReader creates a new Reader instance.
@@ -257,6 +492,8 @@ func NewReader(params ReaderParams) (*Reader, error) {
 		nonceSize:                    params.NonceSize,
 		noncePrefix:                  params.NoncePrefix,
 		firstCiphertextSegmentOffset: params.FirstCiphertextSegmentOffset,
+		workers:                      params.Workers,
+		onSegment:                    params.OnSegment,
 
 		// Allocate an extra byte to detect the last segment.
 		ciphertext: make([]byte, params.CiphertextSegmentSize+1),
@@ -273,13 +510,102 @@ func (r *Reader) Read(p []byte) (int, error) {
 
 	r.plaintextPos = 0
 
+	var err error
+	if r.workers > 1 {
+		r.plaintext, err = r.nextDecryptedSegment()
+	} else {
+		r.plaintext, err = r.decryptSegment()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n := copy(p, r.plaintext)
+	r.plaintextPos = n
+	return n, nil
+}
+
+// decryptSegment reads and decrypts the next segment.
+func (r *Reader) decryptSegment() ([]byte, error) {
+	segment, nonce, _, err := r.readSegment()
+	if err != nil {
+		return nil, err
+	}
+	pt, err := r.segmentDecrypter.DecryptSegment(segment, nonce)
+	if err := r.segmentDecrypted(r.decryptedSegmentCnt-1, pt, err); err != nil {
+		return nil, err
+	}
+	return pt, nil
+}
+
+// segmentDecrypted records the result of decrypting the segment with the
+// given index, and reports it to the OnSegment callback.
+func (r *Reader) segmentDecrypted(index uint64, plaintext []byte, err error) error {
+	if err == nil {
+		r.plaintextBytes += int64(len(plaintext))
+	}
+	return notifySegment(r.onSegment, SegmentEvent{Index: index, PlaintextBytes: r.plaintextBytes, Err: err})
+}
+
+// nextDecryptedSegment returns the plaintext of the next segment. If no
+// segments were decrypted ahead, it reads up to r.workers segments and
+// decrypts them concurrently.
+func (r *Reader) nextDecryptedSegment() ([]byte, error) {
+	if len(r.decrypted) == 0 {
+		if r.err != nil {
+			return nil, r.err
+		}
+		var batch []pendingSegment
+		for len(batch) < r.workers {
+			segment, nonce, last, err := r.readSegment()
+			if err != nil {
+				r.err = err
+				break
+			}
+			// The segment is overwritten by the next call to readSegment.
+			batch = append(batch, pendingSegment{index: r.decryptedSegmentCnt - 1, segment: append([]byte(nil), segment...), nonce: nonce})
+			if last {
+				r.err = io.EOF
+				break
+			}
+		}
+		plaintexts, errs := processBatch(batch, r.segmentDecrypter.DecryptSegment)
+		for i, err := range errs {
+			if err := r.segmentDecrypted(batch[i].index, plaintexts[i], err); err != nil {
+				plaintexts = plaintexts[:i]
+				r.err = err
+				break
+			}
+		}
+		if len(plaintexts) == 0 {
+			return nil, r.err
+		}
+		r.decrypted = plaintexts
+	}
+	pt := r.decrypted[0]
+	r.decrypted = r.decrypted[1:]
+	return pt, nil
+}
+
+// readSegment reads the next segment of ciphertext from the underlying reader
+// and returns it with its nonce, and whether it is the last segment. The
+// returned segment is only valid until the next call.
+func (r *Reader) readSegment() ([]byte, []byte, bool, error) {
+	// Copy 1 byte remainder of the previous segment to the beginning of
+	// ciphertext.
+	if r.segmentEnd > 0 {
+		r.ciphertext[0] = r.ciphertext[r.segmentEnd]
+		r.ciphertextPos = 1
+		r.segmentEnd = 0
+	}
+
 	ctLim := len(r.ciphertext)
 	if r.decryptedSegmentCnt == 0 {
 		ctLim -= r.firstCiphertextSegmentOffset
 	}
 	n, err := io.ReadFull(r.r, r.ciphertext[r.ciphertextPos:ctLim])
 	if err != nil && err != io.ErrUnexpectedEOF {
-		return 0, err
+		return nil, nil, false, err
 	}
 
 	var (
@@ -294,33 +620,201 @@ func (r *Reader) Read(p []byte) (int, error) {
 	}
 
 	if segment < 0 {
-		return 0, ErrCiphertextSegmentTooShort
+		return nil, nil, false, ErrCiphertextSegmentTooShort
 	}
 
 	nonce, err := generateSegmentNonce(r.nonceSize, r.noncePrefix, r.decryptedSegmentCnt, lastSegment)
 	if err != nil {
-		return 0, err
+		return nil, nil, false, err
 	}
 
-	r.plaintext, err = r.segmentDecrypter.DecryptSegment(r.ciphertext[:segment], nonce)
-	if err != nil {
-		return 0, err
+	if !lastSegment {
+		r.segmentEnd = segment
 	}
+	r.decryptedSegmentCnt++
+	return r.ciphertext[:segment], nonce, lastSegment, nil
+}
 
-	// Copy 1 byte remainder to the beginning of ciphertext.
-	if !lastSegment {
-		remainderOffset := segment
-		r.ciphertext[0] = r.ciphertext[remainderOffset]
-		r.ciphertextPos = 1
+// ReaderAt facilitates the random access decryption of ciphertexts created
+// using a Writer. Segments are decrypted on demand, so that reading a range of
+// the plaintext only requires reading and authenticating the ciphertext
+// segments that contain it.
+//
+// Like Reader, ReaderAt needs a SegmentDecrypter that aligns with the
+// SegmentEncrypter used in the Writer. ReadAt may be called concurrently.
+type ReaderAt struct {
+	r                            io.ReaderAt
+	size                         int64
+	segmentDecrypter             SegmentDecrypter
+	nonceSize                    int
+	noncePrefix                  []byte
+	ciphertextSegmentSize        int64
+	plaintextSegmentSize         int64
+	firstCiphertextSegmentOffset int64
+	numSegments                  int64
+	plaintextSize                int64
+
+	mu sync.Mutex
+	// The index and plaintext of the most recently decrypted segment.
+	cachedSegment   int64
+	cachedPlaintext []byte
+}
+
+// ReaderAtParams contains the options for instantiating a ReaderAt via
+// NewReaderAt().
+type ReaderAtParams struct {
+	// R provides the ciphertext. Offset 0 of R is the beginning of the first
+	// segment, i.e. R does not include the header.
+	R io.ReaderAt
+
+	// Size is the size of the ciphertext in R.
+	Size int64
+
+	// SegmentDecrypter provides a method for decrypting segments.
+	SegmentDecrypter SegmentDecrypter
+
+	// NonceSize is the length of generated nonces. It must match the NonceSize
+	// of the Writer used to create the ciphertext.
+	NonceSize int
+
+	// NoncePrefix is a constant that all nonces throughout the ciphertext start
+	// with. It's extracted from the header of the ciphertext.
+	NoncePrefix []byte
+
+	// The size of the ciphertext segments.
+	CiphertextSegmentSize int
+
+	// The size of the plaintext segments, which must match the
+	// PlaintextSegmentSize of the Writer used to create the ciphertext.
+	PlaintextSegmentSize int
+
+	// FirstCiphertexSegmentOffset is the size of the data that preceded the
+	// first segment when the ciphertext was written, e.g. the header.
+	FirstCiphertextSegmentOffset int
+}
+
+// NewReaderAt creates a new ReaderAt instance. It decrypts the first segment
+// right away, so that it fails if the ciphertext was not created with the
+// same key and associated data.
+func NewReaderAt(params ReaderAtParams) (*ReaderAt, error) {
+	if params.NonceSize-len(params.NoncePrefix) < 5 {
+		return nil, ErrNonceSizeTooShort
+	}
+	ctSize := int64(params.CiphertextSegmentSize)
+	ptSize := int64(params.PlaintextSegmentSize)
+	offset := int64(params.FirstCiphertextSegmentOffset)
+	if ptSize <= offset || ctSize < ptSize {
+		return nil, errors.New("invalid segment sizes")
+	}
+	if params.Size < 0 {
+		return nil, ErrCiphertextSegmentTooShort
+	}
+	// All segments but the last one are complete.
+	numSegments := int64(1)
+	if params.Size > ctSize-offset {
+		numSegments += (params.Size - (ctSize - offset) + ctSize - 1) / ctSize
 	}
+	overhead := ctSize - ptSize
+	lastSegmentSize := params.Size - (numSegments-1)*ctSize
+	if numSegments > 1 {
+		lastSegmentSize += offset
+	}
+	if lastSegmentSize < overhead {
+		return nil, ErrCiphertextSegmentTooShort
+	}
+	r := &ReaderAt{
+		r:                            params.R,
+		size:                         params.Size,
+		segmentDecrypter:             params.SegmentDecrypter,
+		nonceSize:                    params.NonceSize,
+		noncePrefix:                  params.NoncePrefix,
+		ciphertextSegmentSize:        ctSize,
+		plaintextSegmentSize:         ptSize,
+		firstCiphertextSegmentOffset: offset,
+		numSegments:                  numSegments,
+		plaintextSize:                params.Size - numSegments*overhead,
+		cachedSegment:                -1,
+	}
+	if _, err := r.segment(0); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
 
-	r.decryptedSegmentCnt++
+// Size returns the size of the plaintext.
+func (r *ReaderAt) Size() int64 {
+	return r.plaintextSize
+}
 
-	n = copy(p, r.plaintext)
-	r.plaintextPos = n
+// ReadAt decrypts the plaintext at offset off into p. It returns io.EOF if
+// fewer than len(p) bytes are read because the end of the plaintext was
+// reached.
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	n := 0
+	for n < len(p) {
+		if off >= r.plaintextSize {
+			return n, io.EOF
+		}
+		i := (off + r.firstCiphertextSegmentOffset) / r.plaintextSegmentSize
+		pt, err := r.segment(i)
+		if err != nil {
+			return n, err
+		}
+		segmentStart := i*r.plaintextSegmentSize - r.firstCiphertextSegmentOffset
+		if i == 0 {
+			segmentStart = 0
+		}
+		m := copy(p[n:], pt[off-segmentStart:])
+		n += m
+		off += int64(m)
+	}
 	return n, nil
 }
 
+// segment returns the plaintext of the i-th segment.
+func (r *ReaderAt) segment(i int64) ([]byte, error) {
+	r.mu.Lock()
+	if r.cachedSegment == i {
+		pt := r.cachedPlaintext
+		r.mu.Unlock()
+		return pt, nil
+	}
+	r.mu.Unlock()
+
+	start := i*r.ciphertextSegmentSize - r.firstCiphertextSegmentOffset
+	if i == 0 {
+		start = 0
+	}
+	end := (i+1)*r.ciphertextSegmentSize - r.firstCiphertextSegmentOffset
+	if end > r.size {
+		end = r.size
+	}
+	ciphertext := make([]byte, end-start)
+	if n, err := r.r.ReadAt(ciphertext, start); n < len(ciphertext) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	nonce, err := generateSegmentNonce(r.nonceSize, r.noncePrefix, uint64(i), i == r.numSegments-1)
+	if err != nil {
+		return nil, err
+	}
+	pt, err := r.segmentDecrypter.DecryptSegment(ciphertext, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cachedSegment = i
+	r.cachedPlaintext = pt
+	r.mu.Unlock()
+	return pt, nil
+}
+
 // generateSegmentNonce returns a nonce for a segment.
 //
 // The format of the nonce is:
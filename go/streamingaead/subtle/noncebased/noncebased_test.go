@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/google/tink/go/streamingaead/subtle/noncebased"
@@ -178,6 +179,181 @@ func TestNonceBased_invalidParameters(t *testing.T) {
 	}
 }
 
+func TestNonceBased_workers(t *testing.T) {
+	const (
+		nonceSize                    = 10
+		noncePrefixSize              = 5
+		plaintextSegmentSize         = 20
+		firstCiphertextSegmentOffset = 10
+	)
+	for _, plaintextSize := range []int{0, 10, 20, 100, 1000, 1001} {
+		for _, tc := range []struct {
+			writerWorkers int
+			readerWorkers int
+		}{
+			{writerWorkers: 4, readerWorkers: 1},
+			{writerWorkers: 1, readerWorkers: 4},
+			{writerWorkers: 2, readerWorkers: 7},
+		} {
+			t.Run(fmt.Sprintf("%d/%d/%d", plaintextSize, tc.writerWorkers, tc.readerWorkers), func(t *testing.T) {
+				writerParams := noncebased.WriterParams{
+					NonceSize:                    nonceSize,
+					PlaintextSegmentSize:         plaintextSegmentSize,
+					FirstCiphertextSegmentOffset: firstCiphertextSegmentOffset,
+					Workers:                      tc.writerWorkers,
+				}
+				plaintext, ciphertext, noncePrefix, err := testEncrypt(plaintextSize, noncePrefixSize, writerParams)
+				if err != nil {
+					t.Fatalf("encrypting failed: %v", err)
+				}
+
+				readerParams := noncebased.ReaderParams{
+					NonceSize:                    nonceSize,
+					NoncePrefix:                  noncePrefix,
+					CiphertextSegmentSize:        plaintextSegmentSize + nonceSize,
+					FirstCiphertextSegmentOffset: firstCiphertextSegmentOffset,
+					Workers:                      tc.readerWorkers,
+				}
+				if err := testDecrypt(plaintext, ciphertext, 7, readerParams); err != nil {
+					t.Fatalf("decrypting failed: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestNonceBased_workersModifiedCiphertext(t *testing.T) {
+	const (
+		nonceSize            = 10
+		plaintextSegmentSize = 20
+	)
+	writerParams := noncebased.WriterParams{
+		NonceSize:            nonceSize,
+		PlaintextSegmentSize: plaintextSegmentSize,
+		Workers:              3,
+	}
+	plaintext, ciphertext, noncePrefix, err := testEncrypt(200, 5, writerParams)
+	if err != nil {
+		t.Fatalf("encrypting failed: %v", err)
+	}
+	// Modify the tag of the fifth segment.
+	ciphertext[5*(plaintextSegmentSize+nonceSize)-1] ^= 1
+
+	r, err := noncebased.NewReader(noncebased.ReaderParams{
+		R:                     bytes.NewReader(ciphertext),
+		SegmentDecrypter:      testDecrypter{},
+		NonceSize:             nonceSize,
+		NoncePrefix:           noncePrefix,
+		CiphertextSegmentSize: plaintextSegmentSize + nonceSize,
+		Workers:               3,
+	})
+	if err != nil {
+		t.Fatalf("noncebased.NewReader failed: %v", err)
+	}
+	got, err := io.ReadAll(r)
+	if err == nil {
+		t.Fatal("reading modified ciphertext succeeded")
+	}
+	if !bytes.Equal(got, plaintext[:4*plaintextSegmentSize]) {
+		t.Errorf("plaintext before the modified segment = %s, want %s", hex.EncodeToString(got), hex.EncodeToString(plaintext[:4*plaintextSegmentSize]))
+	}
+	if _, err := r.Read(make([]byte, 1)); err == nil {
+		t.Error("reading after an error succeeded")
+	}
+}
+
+func TestNonceBased_onSegment(t *testing.T) {
+	const (
+		nonceSize            = 10
+		plaintextSegmentSize = 20
+		plaintextSize        = 110
+	)
+	for _, workers := range []int{1, 3} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			var writerEvents []noncebased.SegmentEvent
+			writerParams := noncebased.WriterParams{
+				NonceSize:                    nonceSize,
+				PlaintextSegmentSize:         plaintextSegmentSize,
+				FirstCiphertextSegmentOffset: 10,
+				Workers:                      workers,
+				OnSegment: func(ev noncebased.SegmentEvent) error {
+					writerEvents = append(writerEvents, ev)
+					return nil
+				},
+			}
+			plaintext, ciphertext, noncePrefix, err := testEncrypt(plaintextSize, 5, writerParams)
+			if err != nil {
+				t.Fatalf("encrypting failed: %v", err)
+			}
+			// 10 bytes in the first segment and 5 full segments.
+			const segments = 6
+			if err := checkSegmentEvents(writerEvents, segments, plaintextSize); err != nil {
+				t.Errorf("writer: %v", err)
+			}
+
+			var readerEvents []noncebased.SegmentEvent
+			readerParams := noncebased.ReaderParams{
+				NonceSize:                    nonceSize,
+				NoncePrefix:                  noncePrefix,
+				CiphertextSegmentSize:        plaintextSegmentSize + nonceSize,
+				FirstCiphertextSegmentOffset: 10,
+				Workers:                      workers,
+				OnSegment: func(ev noncebased.SegmentEvent) error {
+					readerEvents = append(readerEvents, ev)
+					return nil
+				},
+			}
+			if err := testDecrypt(plaintext, ciphertext, 7, readerParams); err != nil {
+				t.Fatalf("decrypting failed: %v", err)
+			}
+			if err := checkSegmentEvents(readerEvents, segments, plaintextSize); err != nil {
+				t.Errorf("reader: %v", err)
+			}
+
+			// Modify the tag of the fourth segment.
+			modified := append([]byte{}, ciphertext...)
+			modified[10+nonceSize+3*(plaintextSegmentSize+nonceSize)-1] ^= 1
+			readerEvents = nil
+			if err := testDecrypt(plaintext, modified, 7, readerParams); err == nil {
+				t.Fatal("decrypting modified ciphertext succeeded")
+			}
+			last := readerEvents[len(readerEvents)-1]
+			if last.Index != 3 || last.Err == nil || last.PlaintextBytes != 50 {
+				t.Errorf("last event = %+v, want failure of segment 3 after 50 bytes", last)
+			}
+
+			// Abort after the second segment.
+			errAbort := errors.New("abort")
+			readerParams.OnSegment = func(ev noncebased.SegmentEvent) error {
+				if ev.Index == 1 {
+					return errAbort
+				}
+				return nil
+			}
+			if err := testDecrypt(plaintext, ciphertext, 7, readerParams); err == nil || !strings.Contains(err.Error(), errAbort.Error()) {
+				t.Errorf("decrypting with aborting callback err = %v, want %v", err, errAbort)
+			}
+		})
+	}
+}
+
+// checkSegmentEvents checks that events report the given number of segments
+// in order, and the given number of plaintext bytes in total.
+func checkSegmentEvents(events []noncebased.SegmentEvent, segments int, plaintextSize int64) error {
+	if len(events) != segments {
+		return fmt.Errorf("got %d events, want %d", len(events), segments)
+	}
+	for i, ev := range events {
+		if ev.Index != uint64(i) || ev.Err != nil {
+			return fmt.Errorf("event %d = %+v", i, ev)
+		}
+	}
+	if got := events[len(events)-1].PlaintextBytes; got != plaintextSize {
+		return fmt.Errorf("PlaintextBytes = %d, want %d", got, plaintextSize)
+	}
+	return nil
+}
+
 // testEncrypter is essentially a no-op cipher.
 //
 // It produces ciphertexts which contain the plaintext broken into segments,
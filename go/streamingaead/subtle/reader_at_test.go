@@ -0,0 +1,123 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/tink/go/streamingaead/subtle"
+)
+
+// readerAtDecrypter is implemented by the streaming AEAD primitives in subtle.
+type readerAtDecrypter interface {
+	NewDecryptingReaderAt(r io.ReaderAt, size int64, aad []byte) (*io.SectionReader, error)
+}
+
+func TestNewDecryptingReaderAt(t *testing.T) {
+	for _, segmentSize := range []int{256, 512} {
+		for _, firstSegmentOffset := range []int{0, 8} {
+			gcm, err := subtle.NewAESGCMHKDF(ikm, "SHA256", 16, segmentSize, firstSegmentOffset)
+			if err != nil {
+				t.Fatalf("subtle.NewAESGCMHKDF() err = %v", err)
+			}
+			ctr, err := subtle.NewAESCTRHMAC(ikm, "SHA256", 16, "SHA256", 12, segmentSize, firstSegmentOffset)
+			if err != nil {
+				t.Fatalf("subtle.NewAESCTRHMAC() err = %v", err)
+			}
+			for _, plaintextSize := range []int{0, 1, 200, 219, 220, 221, 1000, 2000} {
+				for name, cipher := range map[string]interface {
+					readerAtDecrypter
+					NewEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error)
+					NewDecryptingReader(r io.Reader, aad []byte) (io.Reader, error)
+				}{"AES-GCM-HKDF": gcm, "AES-CTR-HMAC": ctr} {
+					pt, ct, err := encrypt(cipher, aad, plaintextSize)
+					if err != nil {
+						t.Fatalf("%s: encrypt() err = %v", name, err)
+					}
+					r, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+					if err != nil {
+						t.Fatalf("%s (%d, %d, %d): NewDecryptingReaderAt() err = %v", name, segmentSize, firstSegmentOffset, plaintextSize, err)
+					}
+					if r.Size() != int64(len(pt)) {
+						t.Errorf("%s (%d, %d, %d): Size() = %d, want %d", name, segmentSize, firstSegmentOffset, plaintextSize, r.Size(), len(pt))
+					}
+					// Read every range of a few sizes, which covers all segment
+					// boundaries.
+					for _, n := range []int{1, 17, 300} {
+						for off := 0; off < len(pt); off += 13 {
+							want := pt[off:]
+							if len(want) > n {
+								want = want[:n]
+							}
+							got := make([]byte, n)
+							m, err := r.ReadAt(got, int64(off))
+							if m != len(want) || !bytes.Equal(got[:m], want) {
+								t.Fatalf("%s (%d, %d, %d): ReadAt(%d bytes, %d) = %d, %v, want %d bytes", name, segmentSize, firstSegmentOffset, plaintextSize, n, off, m, err, len(want))
+							}
+							if m < n && err != io.EOF {
+								t.Errorf("%s: ReadAt() at end err = %v, want io.EOF", name, err)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNewDecryptingReaderAtFailures(t *testing.T) {
+	cipher, err := subtle.NewAESGCMHKDF(ikm, "SHA256", 16, 256, 0)
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCMHKDF() err = %v", err)
+	}
+	pt, ct, err := encrypt(cipher, aad, 1000)
+	if err != nil {
+		t.Fatalf("encrypt() err = %v", err)
+	}
+	if _, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), []byte("other aad")); err == nil {
+		t.Error("NewDecryptingReaderAt() with wrong aad succeeded")
+	}
+	if _, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), 10, aad); err == nil {
+		t.Error("NewDecryptingReaderAt() with truncated header succeeded")
+	}
+	// Truncating the ciphertext at a segment boundary must be detected when
+	// the new last segment is read.
+	truncated, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), 512, aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReaderAt() err = %v", err)
+	}
+	if _, err := truncated.ReadAt(make([]byte, 10), truncated.Size()-10); err == nil {
+		t.Error("ReadAt() of truncated ciphertext succeeded")
+	}
+	if _, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), 256, aad); err == nil {
+		t.Error("NewDecryptingReaderAt() with ciphertext truncated after the first segment succeeded")
+	}
+	modified := append([]byte{}, ct...)
+	modified[len(modified)-1] ^= 1
+	r, err := cipher.NewDecryptingReaderAt(bytes.NewReader(modified), int64(len(modified)), aad)
+	if err != nil {
+		t.Fatalf("NewDecryptingReaderAt() err = %v", err)
+	}
+	if _, err := r.ReadAt(make([]byte, 10), 0); err != nil {
+		t.Errorf("ReadAt() of unmodified segment err = %v", err)
+	}
+	if _, err := r.ReadAt(make([]byte, 10), int64(len(pt)-10)); err == nil {
+		t.Error("ReadAt() of modified segment succeeded")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/google/tink/go/streamingaead/subtle/noncebased"
+	"github.com/google/tink/go/subtle"
+)
+
+// rekeyInfo is the HKDF info prefix used to derive the keys of a stream after
+// the first one.
+var rekeyInfo = []byte("tink streaming aead rekey")
+
+// segmentCipher encrypts and decrypts the segments of a stream.
+type segmentCipher interface {
+	noncebased.SegmentEncrypter
+	noncebased.SegmentDecrypter
+}
+
+// rekeyingSegmentCipher is a segmentCipher that uses a fresh key for every
+// segmentsPerKey segments of a stream, so that more data can be encrypted
+// under one stream header. The first key is the key material derived for the
+// stream. The i-th key is derived from it with HKDF, using rekeyInfo || i as
+// info, so that every key can be computed directly for random access.
+type rekeyingSegmentCipher struct {
+	hkdfAlg         string
+	keyMaterial     []byte
+	noncePrefixSize int
+	segmentsPerKey  uint32
+	newCipher       func(keyMaterial []byte) (segmentCipher, error)
+
+	mu       sync.Mutex
+	keyIndex uint32
+	cipher   segmentCipher
+}
+
+// newSegmentCipher returns newCipher(keyMaterial) if segmentsPerKey is zero,
+// and a rekeyingSegmentCipher otherwise.
+func newSegmentCipher(hkdfAlg string, keyMaterial []byte, noncePrefixSize int, segmentsPerKey uint32, newCipher func(keyMaterial []byte) (segmentCipher, error)) (segmentCipher, error) {
+	c, err := newCipher(keyMaterial)
+	if err != nil || segmentsPerKey == 0 {
+		return c, err
+	}
+	return &rekeyingSegmentCipher{
+		hkdfAlg:         hkdfAlg,
+		keyMaterial:     keyMaterial,
+		noncePrefixSize: noncePrefixSize,
+		segmentsPerKey:  segmentsPerKey,
+		newCipher:       newCipher,
+		cipher:          c,
+	}, nil
+}
+
+func (c *rekeyingSegmentCipher) EncryptSegment(segment, nonce []byte) ([]byte, error) {
+	sc, err := c.cipherForNonce(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return sc.EncryptSegment(segment, nonce)
+}
+
+func (c *rekeyingSegmentCipher) DecryptSegment(segment, nonce []byte) ([]byte, error) {
+	sc, err := c.cipherForNonce(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return sc.DecryptSegment(segment, nonce)
+}
+
+// cipherForNonce returns the cipher of the key of the segment with the given
+// nonce. The nonce contains the segment number after the nonce prefix. The
+// cipher of the last used key is cached, as segments are mostly processed in
+// order.
+func (c *rekeyingSegmentCipher) cipherForNonce(nonce []byte) (segmentCipher, error) {
+	keyIndex := binary.BigEndian.Uint32(nonce[c.noncePrefixSize:]) / c.segmentsPerKey
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if keyIndex == c.keyIndex {
+		return c.cipher, nil
+	}
+	keyMaterial := c.keyMaterial
+	if keyIndex > 0 {
+		info := make([]byte, len(rekeyInfo)+4)
+		copy(info, rekeyInfo)
+		binary.BigEndian.PutUint32(info[len(rekeyInfo):], keyIndex)
+		var err error
+		keyMaterial, err = subtle.ComputeHKDF(c.hkdfAlg, c.keyMaterial, nil, info, uint32(len(c.keyMaterial)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	sc, err := c.newCipher(keyMaterial)
+	if err != nil {
+		return nil, err
+	}
+	c.keyIndex, c.cipher = keyIndex, sc
+	return sc, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/tink"
+)
+
+// rekeyingTestCipher is implemented by the streaming AEAD primitives in subtle.
+type rekeyingTestCipher interface {
+	tink.StreamingAEAD
+	readerAtDecrypter
+	NewDecryptingReaderWithWorkers(r io.Reader, aad []byte, workers int) (io.Reader, error)
+}
+
+func newRekeyingTestCiphers(t *testing.T, segmentsPerKey uint32) map[string]rekeyingTestCipher {
+	t.Helper()
+	gcm, err := subtle.NewAESGCMHKDFWithSegmentsPerKey(ikm, "SHA256", 16, 256, 0, segmentsPerKey)
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCMHKDFWithSegmentsPerKey() err = %v", err)
+	}
+	ctr, err := subtle.NewAESCTRHMACWithSegmentsPerKey(ikm, "SHA256", 16, "SHA256", 12, 256, 0, segmentsPerKey)
+	if err != nil {
+		t.Fatalf("subtle.NewAESCTRHMACWithSegmentsPerKey() err = %v", err)
+	}
+	return map[string]rekeyingTestCipher{"AES-GCM-HKDF": gcm, "AES-CTR-HMAC": ctr}
+}
+
+func TestSegmentsPerKey(t *testing.T) {
+	for _, segmentsPerKey := range []uint32{1, 3} {
+		for name, cipher := range newRekeyingTestCiphers(t, segmentsPerKey) {
+			for _, plaintextSize := range []int{0, 100, 1000, 5000} {
+				pt, ct, err := encrypt(cipher, aad, plaintextSize)
+				if err != nil {
+					t.Fatalf("%s (%d, %d): encrypt() err = %v", name, segmentsPerKey, plaintextSize, err)
+				}
+				if err := decrypt(cipher, aad, pt, ct, 100); err != nil {
+					t.Errorf("%s (%d, %d): decrypt() err = %v", name, segmentsPerKey, plaintextSize, err)
+				}
+
+				r, err := cipher.NewDecryptingReaderWithWorkers(bytes.NewReader(ct), aad, 4)
+				if err != nil {
+					t.Fatalf("%s (%d, %d): NewDecryptingReaderWithWorkers() err = %v", name, segmentsPerKey, plaintextSize, err)
+				}
+				got, err := ioutil.ReadAll(r)
+				if err != nil || !bytes.Equal(got, pt) {
+					t.Errorf("%s (%d, %d): reading with workers = %d bytes, %v, want %d bytes", name, segmentsPerKey, plaintextSize, len(got), err, len(pt))
+				}
+
+				ra, err := cipher.NewDecryptingReaderAt(bytes.NewReader(ct), int64(len(ct)), aad)
+				if err != nil {
+					t.Fatalf("%s (%d, %d): NewDecryptingReaderAt() err = %v", name, segmentsPerKey, plaintextSize, err)
+				}
+				got = make([]byte, len(pt))
+				if _, err := ra.ReadAt(got, 0); err != nil && err != io.EOF || !bytes.Equal(got, pt) {
+					t.Errorf("%s (%d, %d): ReadAt() err = %v, or wrong plaintext", name, segmentsPerKey, plaintextSize, err)
+				}
+			}
+		}
+	}
+}
+
+func TestSegmentsPerKeyChangesKeys(t *testing.T) {
+	const segmentsPerKey = 2
+	rekeying := newRekeyingTestCiphers(t, segmentsPerKey)
+	for name, cipher := range newRekeyingTestCiphers(t, 0) {
+		pt, ct, err := encrypt(rekeying[name], aad, 2000)
+		if err != nil {
+			t.Fatalf("%s: encrypt() err = %v", name, err)
+		}
+		// The first key is the same, so that only the segments after the first
+		// segmentsPerKey segments fail to decrypt without rekeying.
+		r, err := cipher.NewDecryptingReader(bytes.NewReader(ct), aad)
+		if err != nil {
+			t.Fatalf("%s: NewDecryptingReader() err = %v", name, err)
+		}
+		got, err := ioutil.ReadAll(r)
+		if err == nil {
+			t.Fatalf("%s: decrypting without rekeying succeeded", name)
+		}
+		if len(got) == 0 || len(got) >= len(pt) || !bytes.Equal(got, pt[:len(got)]) {
+			t.Errorf("%s: decrypted %d bytes without rekeying, want a prefix of the plaintext", name, len(got))
+		}
+	}
+}
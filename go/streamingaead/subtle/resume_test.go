@@ -0,0 +1,107 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/streamingaead/subtle/noncebased"
+	"github.com/google/tink/go/tink"
+)
+
+// resumingEncrypter is implemented by the streaming AEAD primitives in subtle.
+type resumingEncrypter interface {
+	tink.StreamingAEAD
+	NewResumingEncryptingWriter(w io.Writer, r io.ReaderAt, size int64, aad []byte) (io.WriteCloser, int64, int64, error)
+}
+
+func TestNewResumingEncryptingWriter(t *testing.T) {
+	for _, firstSegmentOffset := range []int{0, 8} {
+		gcm, err := subtle.NewAESGCMHKDF(ikm, "SHA256", 16, 256, firstSegmentOffset)
+		if err != nil {
+			t.Fatalf("subtle.NewAESGCMHKDF() err = %v", err)
+		}
+		ctr, err := subtle.NewAESCTRHMAC(ikm, "SHA256", 16, "SHA256", 12, 256, firstSegmentOffset)
+		if err != nil {
+			t.Fatalf("subtle.NewAESCTRHMAC() err = %v", err)
+		}
+		for name, cipher := range map[string]resumingEncrypter{"AES-GCM-HKDF": gcm, "AES-CTR-HMAC": ctr} {
+			for _, plaintextSize := range []int{0, 100, 1000, 1024} {
+				pt, ct, err := encrypt(cipher, aad, plaintextSize)
+				if err != nil {
+					t.Fatalf("%s: encrypt() err = %v", name, err)
+				}
+				extra := bytes.Repeat([]byte{0x42}, 300)
+				for cut := 0; cut <= len(ct); cut += 37 {
+					// Cutting the ciphertext simulates an interrupted encryption.
+					// Resuming with the complete ciphertext appends to it.
+					if cut+37 > len(ct) {
+						cut = len(ct)
+					}
+					buf := &bytes.Buffer{}
+					w, ctSize, ptOffset, err := cipher.NewResumingEncryptingWriter(buf, bytes.NewReader(ct[:cut]), int64(cut), aad)
+					if err == noncebased.ErrNoCompleteSegment {
+						if cut >= 256 {
+							t.Errorf("%s (%d, %d, %d): NewResumingEncryptingWriter() err = %v, want complete segments", name, firstSegmentOffset, plaintextSize, cut, err)
+						}
+						continue
+					}
+					if err != nil {
+						t.Fatalf("%s (%d, %d, %d): NewResumingEncryptingWriter() err = %v", name, firstSegmentOffset, plaintextSize, cut, err)
+					}
+					if ctSize > int64(cut) || ptOffset > int64(len(pt)) {
+						t.Fatalf("%s (%d, %d, %d): NewResumingEncryptingWriter() = %d, %d, out of range", name, firstSegmentOffset, plaintextSize, cut, ctSize, ptOffset)
+					}
+					// Keep the complete segments, which the writer continues.
+					buf.Write(ct[:ctSize])
+					want := append(append([]byte{}, pt...), extra...)
+					if _, err := w.Write(want[ptOffset:]); err != nil {
+						t.Fatalf("%s: w.Write() err = %v", name, err)
+					}
+					if err := w.Close(); err != nil {
+						t.Fatalf("%s: w.Close() err = %v", name, err)
+					}
+					if err := decrypt(cipher, aad, want, buf.Bytes(), 50); err != nil {
+						t.Errorf("%s (%d, %d, %d): decrypt() err = %v", name, firstSegmentOffset, plaintextSize, cut, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNewResumingEncryptingWriterFailures(t *testing.T) {
+	cipher, err := subtle.NewAESGCMHKDF(ikm, "SHA256", 16, 256, 0)
+	if err != nil {
+		t.Fatalf("subtle.NewAESGCMHKDF() err = %v", err)
+	}
+	_, ct, err := encrypt(cipher, aad, 1000)
+	if err != nil {
+		t.Fatalf("encrypt() err = %v", err)
+	}
+	if _, _, _, err := cipher.NewResumingEncryptingWriter(&bytes.Buffer{}, bytes.NewReader(ct), int64(len(ct)), []byte("other aad")); err == nil {
+		t.Error("NewResumingEncryptingWriter() with wrong aad succeeded")
+	}
+	modified := append([]byte{}, ct...)
+	modified[100] ^= 1
+	if _, _, _, err := cipher.NewResumingEncryptingWriter(&bytes.Buffer{}, bytes.NewReader(modified), int64(len(modified)), aad); err == nil {
+		t.Error("NewResumingEncryptingWriter() with modified first segment succeeded")
+	}
+}
@@ -17,3 +17,20 @@
 // Package subtle provides subtle implementations of the Streaming AEAD
 // primitive.
 package subtle
+
+import "github.com/google/tink/go/streamingaead/subtle/noncebased"
+
+// StreamParams configures the writers and readers returned by the
+// NewEncryptingWriterWithParams and NewDecryptingReaderWithParams methods of
+// the primitives in this package.
+type StreamParams struct {
+	// Workers is the number of segments which are encrypted or decrypted
+	// concurrently. Writers buffer and readers read ahead up to Workers
+	// segments. Segments are processed one at a time if Workers is 1 or less.
+	Workers int
+
+	// OnSegment, if not nil, is called after each segment is encrypted or
+	// decrypted, or failed to be, in the order of the segments. If it returns
+	// an error, the writer or reader stops and returns that error.
+	OnSegment func(noncebased.SegmentEvent) error
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingdaead
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	subtledaead "github.com/google/tink/go/daead/subtle"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingdaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	sivpb "github.com/google/tink/go/proto/aes_siv_streaming_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+const (
+	aesSIVStreamingKeyVersion = 0
+	aesSIVStreamingTypeURL    = "type.googleapis.com/google.crypto.tink.AesSivStreamingKey"
+)
+
+var (
+	errInvalidAESSIVStreamingKey       = errors.New("aes_siv_streaming_key_manager: invalid key")
+	errInvalidAESSIVStreamingKeyFormat = errors.New("aes_siv_streaming_key_manager: invalid key format")
+)
+
+// aesSIVStreamingKeyManager is an implementation of KeyManager interface.
+// It generates new AesSivStreamingKey keys and produces new instances of AESSIVStreaming subtle.
+type aesSIVStreamingKeyManager struct{}
+
+// Primitive creates an AESSIVStreaming subtle for the given serialized AesSivStreamingKey proto.
+func (km *aesSIVStreamingKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidAESSIVStreamingKey
+	}
+	key := &sivpb.AesSivStreamingKey{}
+	if err := proto.Unmarshal(serializedKey, key); err != nil {
+		return nil, errInvalidAESSIVStreamingKey
+	}
+	if err := km.validateKey(key); err != nil {
+		return nil, err
+	}
+	ret, err := subtle.NewAESSIVStreaming(key.KeyValue, int(key.Params.CiphertextSegmentSize))
+	if err != nil {
+		return nil, fmt.Errorf("aes_siv_streaming_key_manager: cannot create new primitive: %s", err)
+	}
+	return ret, nil
+}
+
+// NewKey creates a new key according to specification in the given serialized
+// AesSivStreamingKeyFormat.
+func (km *aesSIVStreamingKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidAESSIVStreamingKeyFormat
+	}
+	keyFormat := &sivpb.AesSivStreamingKeyFormat{}
+	if err := proto.Unmarshal(serializedKeyFormat, keyFormat); err != nil {
+		return nil, errInvalidAESSIVStreamingKeyFormat
+	}
+	if err := km.validateKeyFormat(keyFormat); err != nil {
+		return nil, fmt.Errorf("aes_siv_streaming_key_manager: invalid key format: %s", err)
+	}
+	return &sivpb.AesSivStreamingKey{
+		Version:  aesSIVStreamingKeyVersion,
+		KeyValue: random.GetRandomBytes(keyFormat.KeySize),
+		Params:   keyFormat.Params,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to specification in the given serialized AesSivStreamingKeyFormat.
+// It should be used solely by the key management API.
+func (km *aesSIVStreamingKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	return &tinkpb.KeyData{
+		TypeUrl:         km.TypeURL(),
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_SYMMETRIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *aesSIVStreamingKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == aesSIVStreamingTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *aesSIVStreamingKeyManager) TypeURL() string {
+	return aesSIVStreamingTypeURL
+}
+
+// validateKey validates the given AesSivStreamingKey.
+func (km *aesSIVStreamingKeyManager) validateKey(key *sivpb.AesSivStreamingKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, aesSIVStreamingKeyVersion)
+	if err != nil {
+		return fmt.Errorf("aes_siv_streaming_key_manager: %s", err)
+	}
+	if err := subtledaead.ValidateAESSIVKeySize(uint32(len(key.KeyValue))); err != nil {
+		return fmt.Errorf("aes_siv_streaming_key_manager: %s", err)
+	}
+	if err := km.validateParams(key.Params); err != nil {
+		return fmt.Errorf("aes_siv_streaming_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateKeyFormat validates the given AesSivStreamingKeyFormat.
+func (km *aesSIVStreamingKeyManager) validateKeyFormat(format *sivpb.AesSivStreamingKeyFormat) error {
+	if err := subtledaead.ValidateAESSIVKeySize(format.KeySize); err != nil {
+		return fmt.Errorf("aes_siv_streaming_key_manager: %s", err)
+	}
+	if err := km.validateParams(format.Params); err != nil {
+		return fmt.Errorf("aes_siv_streaming_key_manager: %s", err)
+	}
+	return nil
+}
+
+// validateParams validates the given AesSivStreamingParams.
+func (km *aesSIVStreamingKeyManager) validateParams(params *sivpb.AesSivStreamingParams) error {
+	if params == nil {
+		return errors.New("missing params")
+	}
+	if params.CiphertextSegmentSize <= subtle.AESSIVStreamingTagSizeInBytes {
+		return fmt.Errorf("ciphertext segment_size must be larger than tagSizeInBytes")
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingdaead_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/streamingdaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/testutil"
+	sivpb "github.com/google/tink/go/proto/aes_siv_streaming_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestAESSIVStreamingGetPrimitiveBasic(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVStreamingTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-SIV streaming key manager: %s", err)
+	}
+	for _, keySize := range []uint32{32, 48, 64} {
+		key := newAESSIVStreamingKey(keySize, 4096)
+		serializedKey, _ := proto.Marshal(key)
+		p, err := km.Primitive(serializedKey)
+		if err != nil {
+			t.Errorf("km.Primitive() with %d-byte key failed: %s", keySize, err)
+			continue
+		}
+		if _, ok := p.(*subtle.AESSIVStreaming); !ok {
+			t.Errorf("km.Primitive() returned %T, want *subtle.AESSIVStreaming", p)
+		}
+	}
+}
+
+func TestAESSIVStreamingGetPrimitiveWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVStreamingTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-SIV streaming key manager: %s", err)
+	}
+	invalidKeys := []*sivpb.AesSivStreamingKey{
+		// Bad key size.
+		newAESSIVStreamingKey(16, 4096),
+		newAESSIVStreamingKey(63, 4096),
+		// Bad segment size.
+		newAESSIVStreamingKey(64, subtle.AESSIVStreamingTagSizeInBytes),
+		// Bad version.
+		&sivpb.AesSivStreamingKey{
+			Version:  testutil.AESSIVStreamingKeyVersion + 1,
+			KeyValue: random.GetRandomBytes(64),
+			Params:   &sivpb.AesSivStreamingParams{CiphertextSegmentSize: 4096},
+		},
+		// Missing params.
+		&sivpb.AesSivStreamingKey{
+			Version:  testutil.AESSIVStreamingKeyVersion,
+			KeyValue: random.GetRandomBytes(64),
+		},
+	}
+	for i, key := range invalidKeys {
+		serializedKey, _ := proto.Marshal(key)
+		if _, err := km.Primitive(serializedKey); err == nil {
+			t.Errorf("km.Primitive() with invalid key %d succeeded", i)
+		}
+	}
+	if _, err := km.Primitive(nil); err == nil {
+		t.Errorf("km.Primitive(nil) succeeded")
+	}
+	if _, err := km.Primitive([]byte{}); err == nil {
+		t.Errorf("km.Primitive([]byte{}) succeeded")
+	}
+}
+
+func TestAESSIVStreamingNewKeyData(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVStreamingTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-SIV streaming key manager: %s", err)
+	}
+	format := &sivpb.AesSivStreamingKeyFormat{
+		KeySize: 48,
+		Params:  &sivpb.AesSivStreamingParams{CiphertextSegmentSize: 4096},
+	}
+	serializedFormat, _ := proto.Marshal(format)
+	kd, err := km.NewKeyData(serializedFormat)
+	if err != nil {
+		t.Fatalf("km.NewKeyData() failed: %s", err)
+	}
+	if kd.TypeUrl != testutil.AESSIVStreamingTypeURL {
+		t.Errorf("TypeUrl: %v != %v", kd.TypeUrl, testutil.AESSIVStreamingTypeURL)
+	}
+	if kd.KeyMaterialType != tinkpb.KeyData_SYMMETRIC {
+		t.Errorf("KeyMaterialType: %v != SYMMETRIC", kd.KeyMaterialType)
+	}
+	key := new(sivpb.AesSivStreamingKey)
+	if err := proto.Unmarshal(kd.Value, key); err != nil {
+		t.Fatalf("proto.Unmarshal() failed: %s", err)
+	}
+	if len(key.KeyValue) != 48 {
+		t.Errorf("len(key.KeyValue) = %d, want 48", len(key.KeyValue))
+	}
+	if !proto.Equal(key.Params, format.Params) {
+		t.Errorf("key.Params = %v, want %v", key.Params, format.Params)
+	}
+	if _, err := km.Primitive(kd.Value); err != nil {
+		t.Errorf("km.Primitive() of new key failed: %s", err)
+	}
+}
+
+func TestAESSIVStreamingNewKeyWithInvalidInput(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVStreamingTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-SIV streaming key manager: %s", err)
+	}
+	invalidFormats := []*sivpb.AesSivStreamingKeyFormat{
+		{KeySize: 16, Params: &sivpb.AesSivStreamingParams{CiphertextSegmentSize: 4096}},
+		{KeySize: 64, Params: &sivpb.AesSivStreamingParams{CiphertextSegmentSize: 16}},
+		{KeySize: 64},
+	}
+	for i, format := range invalidFormats {
+		serializedFormat, _ := proto.Marshal(format)
+		if _, err := km.NewKey(serializedFormat); err == nil {
+			t.Errorf("km.NewKey() with invalid format %d succeeded", i)
+		}
+	}
+	if _, err := km.NewKey(nil); err == nil {
+		t.Errorf("km.NewKey(nil) succeeded")
+	}
+}
+
+func TestAESSIVStreamingDoesSupport(t *testing.T) {
+	km, err := registry.GetKeyManager(testutil.AESSIVStreamingTypeURL)
+	if err != nil {
+		t.Fatalf("cannot obtain AES-SIV streaming key manager: %s", err)
+	}
+	if !km.DoesSupport(testutil.AESSIVStreamingTypeURL) {
+		t.Errorf("AESSIVStreamingKeyManager must support %s", testutil.AESSIVStreamingTypeURL)
+	}
+	if km.DoesSupport("some bad type") {
+		t.Errorf("AESSIVStreamingKeyManager must support only %s", testutil.AESSIVStreamingTypeURL)
+	}
+	if kt := km.TypeURL(); kt != testutil.AESSIVStreamingTypeURL {
+		t.Errorf("km.TypeURL() = %s; want %s", kt, testutil.AESSIVStreamingTypeURL)
+	}
+}
+
+func newAESSIVStreamingKey(keySize, ciphertextSegmentSize uint32) *sivpb.AesSivStreamingKey {
+	return &sivpb.AesSivStreamingKey{
+		Version:  testutil.AESSIVStreamingKeyVersion,
+		KeyValue: random.GetRandomBytes(keySize),
+		Params:   &sivpb.AesSivStreamingParams{CiphertextSegmentSize: ciphertextSegmentSize},
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package streamingdaead provides implementations of the streaming
+// deterministic AEAD primitive.
+//
+// Unlike streaming AEAD, encrypting the same plaintext with the same key and
+// associated data always yields the same ciphertext. This allows to
+// deduplicate encrypted data, such as backups of large files, at the cost of
+// revealing whether two ciphertexts have the same plaintext.
+package streamingdaead
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+)
+
+func init() {
+	if err := registry.RegisterKeyManager(&aesSIVStreamingKeyManager{}); err != nil {
+		panic(fmt.Sprintf("streamingdaead.init() failed: %v", err))
+	}
+	for name, kt := range namedKeyTemplates {
+		if err := registry.RegisterKeyTemplate(name, kt); err != nil {
+			panic(fmt.Sprintf("streamingdaead.init() failed: %v", err))
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingdaead
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/tink"
+)
+
+// New returns a StreamingDeterministicAEAD primitive from the given keyset handle.
+func New(h *keyset.Handle) (tink.StreamingDeterministicAEAD, error) {
+	return NewWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewWithKeyManager returns a StreamingDeterministicAEAD primitive from the given keyset handle
+// and custom key manager.
+// Deprecated: register the KeyManager and use New above.
+func NewWithKeyManager(h *keyset.Handle, km registry.KeyManager) (tink.StreamingDeterministicAEAD, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("streamingdaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingDeterministicAEAD(ps)
+}
+
+// NewWithRegistry returns a StreamingDeterministicAEAD primitive from the
+// given keyset handle, using the key managers of r instead of the global
+// registry.
+func NewWithRegistry(h *keyset.Handle, r *registry.Registry) (tink.StreamingDeterministicAEAD, error) {
+	ps, err := h.PrimitivesWithRegistry(r)
+	if err != nil {
+		return nil, fmt.Errorf("streamingdaead_factory: cannot obtain primitive set: %s", err)
+	}
+	return newWrappedStreamingDeterministicAEAD(ps)
+}
+
+func newWrappedStreamingDeterministicAEAD(ps *primitiveset.PrimitiveSet) (tink.StreamingDeterministicAEAD, error) {
+	if _, ok := (ps.Primary.Primitive).(tink.StreamingDeterministicAEAD); !ok {
+		return nil, fmt.Errorf("streamingdaead_factory: not a StreamingDeterministicAEAD primitive")
+	}
+
+	for _, primitives := range ps.Entries {
+		for _, p := range primitives {
+			if _, ok := (p.Primitive).(tink.StreamingDeterministicAEAD); !ok {
+				return nil, fmt.Errorf("streamingdaead_factory: not a StreamingDeterministicAEAD primitive")
+			}
+		}
+	}
+
+	return &wrappedStreamingDeterministicAEAD{ps: ps}, nil
+}
+
+// wrappedStreamingDeterministicAEAD is a StreamingDeterministicAEAD implementation that uses the
+// underlying primitive set for deterministic streaming encryption and decryption.
+type wrappedStreamingDeterministicAEAD struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+// Asserts that wrappedStreamingDeterministicAEAD implements the StreamingDeterministicAEAD
+// interface.
+var _ tink.StreamingDeterministicAEAD = (*wrappedStreamingDeterministicAEAD)(nil)
+
+// NewDeterministicEncryptingWriter returns a wrapper around underlying io.Writer, such that any
+// write-operation via the wrapper results in deterministic AEAD-encryption of the written data,
+// using aad as associated authenticated data, with the primary key of the keyset.
+func (s *wrappedStreamingDeterministicAEAD) NewDeterministicEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	if err := s.ps.Primary.CheckNotExpired(); err != nil {
+		return nil, err
+	}
+	p, ok := (s.ps.Primary.Primitive).(tink.StreamingDeterministicAEAD)
+	if !ok {
+		return nil, fmt.Errorf("streamingdaead_factory: not a StreamingDeterministicAEAD primitive")
+	}
+	return p.NewDeterministicEncryptingWriter(w, aad)
+}
+
+// NewDeterministicDecryptingReader returns a wrapper around underlying io.Reader, such that any
+// read-operation via the wrapper results in deterministic AEAD-decryption of the underlying
+// ciphertext, using aad as associated authenticated data. The key is found by trying to decrypt
+// the first segment with each key of the keyset.
+func (s *wrappedStreamingDeterministicAEAD) NewDeterministicDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
+	return &decryptReader{
+		wrapped: s,
+		cr:      r,
+		aad:     aad,
+	}, nil
+}
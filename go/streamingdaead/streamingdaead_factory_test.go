@@ -0,0 +1,129 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingdaead_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead"
+	"github.com/google/tink/go/streamingdaead"
+	"github.com/google/tink/go/subtle/random"
+	"github.com/google/tink/go/tink"
+)
+
+func encrypt(t *testing.T, a tink.StreamingDeterministicAEAD, pt, aad []byte) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w, err := a.NewDeterministicEncryptingWriter(buf, aad)
+	if err != nil {
+		t.Fatalf("NewDeterministicEncryptingWriter failed: %v", err)
+	}
+	if _, err := w.Write(pt); err != nil {
+		t.Fatalf("w.Write(pt) failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(a tink.StreamingDeterministicAEAD, ct, aad []byte) ([]byte, error) {
+	r, err := a.NewDeterministicDecryptingReader(bytes.NewReader(ct), aad)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestFactoryMultipleKeys(t *testing.T) {
+	oldHandle, err := keyset.NewHandle(streamingdaead.AESSIVStreaming4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %v", err)
+	}
+	old, err := streamingdaead.New(oldHandle)
+	if err != nil {
+		t.Fatalf("streamingdaead.New failed: %v", err)
+	}
+	manager := keyset.NewManagerFromHandle(oldHandle)
+	if err := manager.Rotate(streamingdaead.AESSIVStreaming1MBKeyTemplate()); err != nil {
+		t.Fatalf("manager.Rotate failed: %v", err)
+	}
+	handle, err := manager.Handle()
+	if err != nil {
+		t.Fatalf("manager.Handle failed: %v", err)
+	}
+	a, err := streamingdaead.New(handle)
+	if err != nil {
+		t.Fatalf("streamingdaead.New failed: %v", err)
+	}
+	otherHandle, err := keyset.NewHandle(streamingdaead.AESSIVStreaming4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("keyset.NewHandle failed: %v", err)
+	}
+	other, err := streamingdaead.New(otherHandle)
+	if err != nil {
+		t.Fatalf("streamingdaead.New failed: %v", err)
+	}
+
+	aad := []byte("aad")
+	for _, size := range []uint32{0, 1, 4079, 4080, 4081, 20000} {
+		pt := random.GetRandomBytes(size)
+
+		ct := encrypt(t, a, pt, aad)
+		if got, err := decrypt(a, ct, aad); err != nil || !bytes.Equal(got, pt) {
+			t.Errorf("size %d: decrypt with the primary key failed: %v", size, err)
+		}
+		if !bytes.Equal(ct, encrypt(t, a, pt, aad)) {
+			t.Errorf("size %d: encryption is not deterministic", size)
+		}
+
+		oldCT := encrypt(t, old, pt, aad)
+		if got, err := decrypt(a, oldCT, aad); err != nil || !bytes.Equal(got, pt) {
+			t.Errorf("size %d: decrypt with a non-primary key failed: %v", size, err)
+		}
+		if _, err := decrypt(old, ct, aad); err == nil {
+			t.Errorf("size %d: decrypt without the primary key succeeded", size)
+		}
+		if _, err := decrypt(a, encrypt(t, other, pt, aad), aad); err == nil {
+			t.Errorf("size %d: decrypt with a key not in the keyset succeeded", size)
+		}
+	}
+}
+
+func TestFactoryWithInvalidPrimitiveSetType(t *testing.T) {
+	wrongKH, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("failed to build *keyset.Handle: %s", err)
+	}
+
+	if _, err := streamingdaead.New(wrongKH); err == nil {
+		t.Errorf("calling New() with wrong *keyset.Handle should fail")
+	}
+}
+
+func TestFactoryPrimitiveIsNotStreamingAEAD(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingdaead.AESSIVStreaming4KBKeyTemplate())
+	if err != nil {
+		t.Fatalf("failed to build *keyset.Handle: %s", err)
+	}
+	if _, err := streamingaead.New(kh); err == nil {
+		t.Errorf("streamingaead.New() with a deterministic key should fail")
+	}
+}
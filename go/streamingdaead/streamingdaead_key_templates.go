@@ -0,0 +1,71 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package streamingdaead
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	sivpb "github.com/google/tink/go/proto/aes_siv_streaming_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// This file contains pre-generated KeyTemplates for streaming deterministic AEAD keys. One can
+// use these templates to generate new Keysets.
+
+// AESSIVStreaming4KBKeyTemplate is a KeyTemplate that generates an AES-SIV streaming key with
+// the following parameters:
+//   - Key size: 64 bytes
+//   - Ciphertext segment size: 4096 bytes
+func AESSIVStreaming4KBKeyTemplate() *tinkpb.KeyTemplate {
+	return newAESSIVStreamingKeyTemplate(64, 4096)
+}
+
+// AESSIVStreaming1MBKeyTemplate is a KeyTemplate that generates an AES-SIV streaming key with
+// the following parameters:
+//   - Key size: 64 bytes
+//   - Ciphertext segment size: 1048576 bytes (1 MB)
+func AESSIVStreaming1MBKeyTemplate() *tinkpb.KeyTemplate {
+	return newAESSIVStreamingKeyTemplate(64, 1048576)
+}
+
+// newAESSIVStreamingKeyTemplate creates a KeyTemplate containing an AesSivStreamingKeyFormat
+// with the specified parameters.
+func newAESSIVStreamingKeyTemplate(keySize, ciphertextSegmentSize uint32) *tinkpb.KeyTemplate {
+	serializedFormat, err := proto.Marshal(&sivpb.AesSivStreamingKeyFormat{
+		KeySize: keySize,
+		Params: &sivpb.AesSivStreamingParams{
+			CiphertextSegmentSize: ciphertextSegmentSize,
+		},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal key: %s", err))
+	}
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          aesSIVStreamingTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
+
+// namedKeyTemplates maps the names that Tinkey uses for the key templates of
+// this package to the templates. They are registered with
+// registry.RegisterKeyTemplate.
+var namedKeyTemplates = map[string]func() *tinkpb.KeyTemplate{
+	"AES256_SIV_STREAMING_4KB": AESSIVStreaming4KBKeyTemplate,
+	"AES256_SIV_STREAMING_1MB": AESSIVStreaming1MBKeyTemplate,
+}
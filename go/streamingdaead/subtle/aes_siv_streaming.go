@@ -0,0 +1,267 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	subtledaead "github.com/google/tink/go/daead/subtle"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// AESSIVStreamingTagSizeInBytes is the size of the synthetic IV that is
+	// prepended to each ciphertext segment.
+	AESSIVStreamingTagSizeInBytes = 16
+
+	indexSizeInBytes = 8
+)
+
+// AESSIVStreaming implements deterministic streaming encryption using
+// AES-SIV.
+//
+// The plaintext is split into segments, each of which is encrypted with
+// AES-SIV. The associated data of a segment consists of a chaining value,
+// the index of the segment and a flag that is set for the last segment. The
+// chaining value of the first segment is the SHA-256 hash of the associated
+// data of the stream, and the chaining value of each following segment is the
+// SHA-256 hash of the previous chaining value and the synthetic IV of the
+// previous segment. Hence the synthetic IV of a segment depends on the
+// plaintext of all preceding segments, segments cannot be reordered or
+// truncated, and there is no header, so that equal plaintexts result in equal
+// ciphertexts.
+type AESSIVStreaming struct {
+	siv                   *subtledaead.AESSIV
+	ciphertextSegmentSize int
+	plaintextSegmentSize  int
+}
+
+// Asserts that AESSIVStreaming implements the StreamingDeterministicAEAD
+// interface.
+var _ tink.StreamingDeterministicAEAD = (*AESSIVStreaming)(nil)
+
+// NewAESSIVStreaming initializes a streaming primitive with an AES-SIV key
+// and the size of ciphertext segments, which includes the synthetic IV.
+func NewAESSIVStreaming(key []byte, ciphertextSegmentSize int) (*AESSIVStreaming, error) {
+	if ciphertextSegmentSize <= AESSIVStreamingTagSizeInBytes {
+		return nil, errors.New("aes_siv_streaming: ciphertextSegmentSize too small")
+	}
+	siv, err := subtledaead.NewAESSIV(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes_siv_streaming: %s", err)
+	}
+	return &AESSIVStreaming{
+		siv:                   siv,
+		ciphertextSegmentSize: ciphertextSegmentSize,
+		plaintextSegmentSize:  ciphertextSegmentSize - AESSIVStreamingTagSizeInBytes,
+	}, nil
+}
+
+// NewDeterministicEncryptingWriter returns a wrapper around underlying
+// io.Writer, such that any write-operation via the wrapper results in
+// deterministic AEAD-encryption of the written data, using aad as associated
+// authenticated data. The associated data is not included in the ciphertext
+// and has to be passed in as parameter for decryption.
+//
+// The returned writer must be closed to write the last segment; closing it
+// does not close w.
+func (a *AESSIVStreaming) NewDeterministicEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error) {
+	return &aesSIVStreamingWriter{
+		a:         a,
+		w:         w,
+		chain:     newSegmentChain(aad),
+		plaintext: make([]byte, 0, a.plaintextSegmentSize+1),
+	}, nil
+}
+
+// NewDeterministicDecryptingReader returns a wrapper around underlying
+// io.Reader, such that any read-operation via the wrapper results in
+// deterministic AEAD-decryption of the underlying ciphertext, using aad as
+// associated authenticated data.
+func (a *AESSIVStreaming) NewDeterministicDecryptingReader(r io.Reader, aad []byte) (io.Reader, error) {
+	return &aesSIVStreamingReader{
+		a:          a,
+		r:          r,
+		chain:      newSegmentChain(aad),
+		ciphertext: make([]byte, a.ciphertextSegmentSize+1),
+	}, nil
+}
+
+// segmentChain computes the associated data of consecutive segments.
+type segmentChain struct {
+	value []byte
+	index uint64
+}
+
+func newSegmentChain(aad []byte) *segmentChain {
+	h := sha256.Sum256(aad)
+	return &segmentChain{value: h[:]}
+}
+
+// associatedData returns the associated data of the current segment.
+func (c *segmentChain) associatedData(last bool) []byte {
+	ad := make([]byte, len(c.value)+indexSizeInBytes+1)
+	copy(ad, c.value)
+	binary.BigEndian.PutUint64(ad[len(c.value):], c.index)
+	if last {
+		ad[len(ad)-1] = 1
+	}
+	return ad
+}
+
+// advance moves to the next segment, given the ciphertext of the current one.
+func (c *segmentChain) advance(ciphertext []byte) error {
+	if c.index == ^uint64(0) {
+		return errors.New("aes_siv_streaming: too many segments")
+	}
+	h := sha256.New()
+	h.Write(c.value)
+	h.Write(ciphertext[:AESSIVStreamingTagSizeInBytes])
+	c.value = h.Sum(nil)
+	c.index++
+	return nil
+}
+
+// aesSIVStreamingWriter encrypts written data segment by segment. A segment
+// is only encrypted once it is known whether it is the last one, so up to one
+// segment of plaintext and one more byte are buffered.
+type aesSIVStreamingWriter struct {
+	a         *AESSIVStreaming
+	w         io.Writer
+	chain     *segmentChain
+	plaintext []byte
+	closed    bool
+}
+
+func (w *aesSIVStreamingWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("aes_siv_streaming: write on closed writer")
+	}
+	n := 0
+	for len(p) > 0 {
+		m := copy(w.plaintext[len(w.plaintext):cap(w.plaintext)], p)
+		w.plaintext = w.plaintext[:len(w.plaintext)+m]
+		p = p[m:]
+		n += m
+		if len(w.plaintext) <= w.a.plaintextSegmentSize {
+			continue
+		}
+		if err := w.writeSegment(w.plaintext[:w.a.plaintextSegmentSize], false); err != nil {
+			return n, err
+		}
+		// One byte is left over.
+		w.plaintext[0] = w.plaintext[w.a.plaintextSegmentSize]
+		w.plaintext = w.plaintext[:1]
+	}
+	return n, nil
+}
+
+// Close encrypts the remaining data as the last segment and flushes it to the
+// underlying writer.
+func (w *aesSIVStreamingWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.writeSegment(w.plaintext, true)
+}
+
+func (w *aesSIVStreamingWriter) writeSegment(segment []byte, last bool) error {
+	ct, err := w.a.siv.EncryptDeterministically(segment, w.chain.associatedData(last))
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(ct); err != nil {
+		return err
+	}
+	return w.chain.advance(ct)
+}
+
+// aesSIVStreamingReader decrypts the ciphertext read from the underlying
+// reader segment by segment. It reads one byte past each segment to determine
+// whether the segment is the last one.
+type aesSIVStreamingReader struct {
+	a     *AESSIVStreaming
+	r     io.Reader
+	chain *segmentChain
+	// ciphertext holds the ciphertext read but not yet decrypted.
+	ciphertext    []byte
+	ciphertextPos int
+	plaintext     []byte
+	done          bool
+	err           error
+}
+
+func (r *aesSIVStreamingReader) Read(p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.readSegment(); err != nil {
+			r.err = err
+			return 0, err
+		}
+	}
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	return n, nil
+}
+
+// readSegment reads and decrypts the next segment.
+func (r *aesSIVStreamingReader) readSegment() error {
+	n, err := io.ReadFull(r.r, r.ciphertext[r.ciphertextPos:])
+	r.ciphertextPos += n
+	last := false
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	default:
+		return err
+	}
+
+	segmentSize := r.a.ciphertextSegmentSize
+	if last {
+		segmentSize = r.ciphertextPos
+		if segmentSize < AESSIVStreamingTagSizeInBytes {
+			return errors.New("aes_siv_streaming: ciphertext too short")
+		}
+	}
+	ct := r.ciphertext[:segmentSize]
+	pt, err := r.a.siv.DecryptDeterministically(ct, r.chain.associatedData(last))
+	if err != nil {
+		return errors.New("aes_siv_streaming: invalid ciphertext")
+	}
+	if err := r.chain.advance(ct); err != nil {
+		return err
+	}
+	r.plaintext = pt
+	r.done = last
+	if !last {
+		// Keep the byte read past the segment.
+		r.ciphertext[0] = r.ciphertext[segmentSize]
+		r.ciphertextPos = 1
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package subtle_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/tink/go/streamingdaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+)
+
+func encrypt(t *testing.T, a *subtle.AESSIVStreaming, pt, aad []byte, chunkSize int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := a.NewDeterministicEncryptingWriter(&buf, aad)
+	if err != nil {
+		t.Fatalf("NewDeterministicEncryptingWriter failed: %v", err)
+	}
+	for i := 0; i < len(pt); i += chunkSize {
+		end := i + chunkSize
+		if end > len(pt) {
+			end = len(pt)
+		}
+		if _, err := w.Write(pt[i:end]); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func decrypt(a *subtle.AESSIVStreaming, ct, aad []byte) ([]byte, error) {
+	r, err := a.NewDeterministicDecryptingReader(bytes.NewReader(ct), aad)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestAESSIVStreamingEncryptDecrypt(t *testing.T) {
+	testCases := []struct {
+		name          string
+		keySize       uint32
+		segmentSize   int
+		plaintextSize int
+		chunkSize     int
+	}{
+		{name: "empty", keySize: 64, segmentSize: 64, plaintextSize: 0, chunkSize: 16},
+		{name: "one-segment", keySize: 64, segmentSize: 64, plaintextSize: 20, chunkSize: 16},
+		{name: "full-segment", keySize: 64, segmentSize: 64, plaintextSize: 48, chunkSize: 7},
+		{name: "two-full-segments", keySize: 64, segmentSize: 64, plaintextSize: 96, chunkSize: 96},
+		{name: "many-segments", keySize: 64, segmentSize: 64, plaintextSize: 1000, chunkSize: 33},
+		{name: "tiny-segments", keySize: 64, segmentSize: 17, plaintextSize: 100, chunkSize: 3},
+		{name: "aes128", keySize: 32, segmentSize: 256, plaintextSize: 1000, chunkSize: 100},
+		{name: "aes192", keySize: 48, segmentSize: 256, plaintextSize: 1000, chunkSize: 1000},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(tc.keySize), tc.segmentSize)
+			if err != nil {
+				t.Fatalf("NewAESSIVStreaming failed: %v", err)
+			}
+			pt := random.GetRandomBytes(uint32(tc.plaintextSize))
+			aad := []byte("aad")
+			ct := encrypt(t, a, pt, aad, tc.chunkSize)
+
+			plaintextSegmentSize := tc.segmentSize - subtle.AESSIVStreamingTagSizeInBytes
+			segments := 1
+			if tc.plaintextSize > 0 {
+				segments = (tc.plaintextSize + plaintextSegmentSize - 1) / plaintextSegmentSize
+			}
+			if want := tc.plaintextSize + segments*subtle.AESSIVStreamingTagSizeInBytes; len(ct) != want {
+				t.Errorf("len(ct) = %d, want %d", len(ct), want)
+			}
+
+			got, err := decrypt(a, ct, aad)
+			if err != nil {
+				t.Fatalf("decrypt failed: %v", err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.Errorf("decrypt = %x, want %x", got, pt)
+			}
+		})
+	}
+}
+
+func TestAESSIVStreamingIsDeterministic(t *testing.T) {
+	a, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(64), 64)
+	if err != nil {
+		t.Fatalf("NewAESSIVStreaming failed: %v", err)
+	}
+	pt := random.GetRandomBytes(500)
+	aad := []byte("aad")
+	ct1 := encrypt(t, a, pt, aad, 500)
+	ct2 := encrypt(t, a, pt, aad, 13)
+	if !bytes.Equal(ct1, ct2) {
+		t.Errorf("encrypting the same plaintext twice gave different ciphertexts")
+	}
+	if ct3 := encrypt(t, a, pt, []byte("other aad"), 500); bytes.Equal(ct1[:64], ct3[:64]) {
+		t.Errorf("encrypting with different associated data gave the same first segment")
+	}
+
+	// A change in the plaintext changes all following segments but not the
+	// preceding ones.
+	modified := append([]byte(nil), pt...)
+	modified[100] ^= 1
+	ct4 := encrypt(t, a, modified, aad, 500)
+	if !bytes.Equal(ct1[:128], ct4[:128]) {
+		t.Errorf("segments before the modified one differ")
+	}
+	for i := 128; i < len(ct1); i += 64 {
+		if bytes.Equal(ct1[i:i+16], ct4[i:i+16]) {
+			t.Errorf("segment at %d is unchanged after the modified one", i)
+		}
+	}
+}
+
+func TestAESSIVStreamingModifiedCiphertext(t *testing.T) {
+	a, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(64), 64)
+	if err != nil {
+		t.Fatalf("NewAESSIVStreaming failed: %v", err)
+	}
+	pt := random.GetRandomBytes(200)
+	aad := []byte("aad")
+	ct := encrypt(t, a, pt, aad, 200)
+
+	if _, err := decrypt(a, ct, []byte("other aad")); err == nil {
+		t.Errorf("decrypt with wrong aad succeeded")
+	}
+	for i := 0; i < len(ct); i++ {
+		modified := append([]byte(nil), ct...)
+		modified[i] ^= 1
+		if _, err := decrypt(a, modified, aad); err == nil {
+			t.Errorf("decrypt of ciphertext modified at byte %d succeeded", i)
+		}
+	}
+	// Truncation at and between segment boundaries.
+	for _, n := range []int{0, 15, 64, 100, 128, len(ct) - 1} {
+		if _, err := decrypt(a, ct[:n], aad); err == nil {
+			t.Errorf("decrypt of ciphertext truncated to %d bytes succeeded", n)
+		}
+	}
+	if _, err := decrypt(a, append(ct, 0), aad); err == nil {
+		t.Errorf("decrypt of ciphertext with trailing data succeeded")
+	}
+	swapped := append(append(append([]byte(nil), ct[64:128]...), ct[:64]...), ct[128:]...)
+	if _, err := decrypt(a, swapped, aad); err == nil {
+		t.Errorf("decrypt of ciphertext with reordered segments succeeded")
+	}
+}
+
+func TestAESSIVStreamingReadAfterError(t *testing.T) {
+	a, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(64), 64)
+	if err != nil {
+		t.Fatalf("NewAESSIVStreaming failed: %v", err)
+	}
+	r, err := a.NewDeterministicDecryptingReader(bytes.NewReader(make([]byte, 100)), nil)
+	if err != nil {
+		t.Fatalf("NewDeterministicDecryptingReader failed: %v", err)
+	}
+	buf := make([]byte, 10)
+	if _, err := r.Read(buf); err == nil || err == io.EOF {
+		t.Fatalf("Read() err = %v, want decryption error", err)
+	}
+	if _, err := r.Read(buf); err == nil || err == io.EOF {
+		t.Errorf("second Read() err = %v, want decryption error", err)
+	}
+}
+
+func TestAESSIVStreamingInvalidParameters(t *testing.T) {
+	for _, keySize := range []uint32{0, 16, 33, 65} {
+		if _, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(keySize), 64); err == nil {
+			t.Errorf("NewAESSIVStreaming succeeded with a %d-byte key", keySize)
+		}
+	}
+	for _, segmentSize := range []int{0, subtle.AESSIVStreamingTagSizeInBytes} {
+		if _, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(64), segmentSize); err == nil {
+			t.Errorf("NewAESSIVStreaming succeeded with segment size %d", segmentSize)
+		}
+	}
+}
+
+func TestAESSIVStreamingWriteAfterClose(t *testing.T) {
+	a, err := subtle.NewAESSIVStreaming(random.GetRandomBytes(64), 64)
+	if err != nil {
+		t.Fatalf("NewAESSIVStreaming failed: %v", err)
+	}
+	w, err := a.NewDeterministicEncryptingWriter(ioutil.Discard, nil)
+	if err != nil {
+		t.Fatalf("NewDeterministicEncryptingWriter failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := w.Write([]byte("data")); err == nil {
+		t.Errorf("Write after Close succeeded")
+	}
+}
@@ -26,6 +26,8 @@ import (
 	"errors"
 	"hash"
 	"math/big"
+
+	"golang.org/x/crypto/sha3"
 )
 
 var errNilHashFunc = errors.New("nil hash function")
@@ -37,6 +39,9 @@ var hashDigestSize = map[string]uint32{
 	"SHA256": uint32(32),
 	"SHA384": uint32(48),
 	"SHA512": uint32(64),
+
+	"SHA3_256": uint32(32),
+	"SHA3_512": uint32(64),
 }
 
 // GetHashDigestSize returns the digest size of the specified hash algorithm.
@@ -65,6 +70,10 @@ func ConvertHashName(name string) string {
 		return "SHA512"
 	case "SHA-1":
 		return "SHA1"
+	case "SHA3-256":
+		return "SHA3_256"
+	case "SHA3-512":
+		return "SHA3_512"
 	default:
 		return ""
 	}
@@ -80,6 +89,8 @@ func ConvertCurveName(name string) string {
 		return "NIST_P384"
 	case "secp521r1", "P-521":
 		return "NIST_P521"
+	case "secp256k1":
+		return "SECP256K1"
 	default:
 		return ""
 	}
@@ -98,6 +109,10 @@ func GetHashFunc(hash string) func() hash.Hash {
 		return sha512.New384
 	case "SHA512":
 		return sha512.New
+	case "SHA3_256":
+		return sha3.New256
+	case "SHA3_512":
+		return sha3.New512
 	default:
 		return nil
 	}
@@ -28,6 +28,7 @@ func TestConvertHashName(t *testing.T) {
 	if subtle.ConvertHashName("SHA-256") != "SHA256" ||
 		subtle.ConvertHashName("SHA-1") != "SHA1" ||
 		subtle.ConvertHashName("SHA-512") != "SHA512" ||
+		subtle.ConvertHashName("SHA3-256") != "SHA3_256" ||
 		subtle.ConvertHashName("UNKNOWN_HASH") != "" {
 		t.Errorf("incorrect hash name conversion")
 	}
@@ -50,6 +51,7 @@ func TestComputeHash(t *testing.T) {
 	}{
 		{subtle.GetHashFunc("SHA1"), "f7ff9e8b7bb2e09b70935a5d785e0cc5d9d0abf0"},
 		{subtle.GetHashFunc("SHA256"), "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969"},
+		{subtle.GetHashFunc("SHA3_256"), "8ca66ee6b2fe4bb928a8e3cd2f508de4119c0895f22e011117e22cf9b13de7ef"},
 		{subtle.GetHashFunc("SHA512"), "3615f80c9d293ed7402687f94b22d58e529b8cc7916f8fac7fddf7fbd5af4cf777d3d795a7a00a16bf7e7f3fb9561ee9baae480da9fe7a18769e71886b03f315"},
 	}
 
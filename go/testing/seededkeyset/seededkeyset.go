@@ -0,0 +1,115 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+// Package seededkeyset generates keysets deterministically from a seed, so
+// that tests and cross-language fixtures are reproducible without checking
+// in key material.
+//
+// Keys are derived with the DeriveKey method of their key managers, so only
+// templates of key types that support derivation can be used, such as
+// AES-GCM, ChaCha20-Poly1305, AES-SIV, HMAC, HKDF and HMAC PRFs. Keysets
+// generated from a seed are not secret, since anyone who knows the seed can
+// generate them. They must only be used in tests.
+package seededkeyset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/testkeyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// NewHandle returns a handle for a keyset with one key for each template,
+// derived from seed. The first key is the primary key. Equal seeds and
+// templates always produce equal keysets, including the key IDs.
+func NewHandle(seed []byte, templates ...*tinkpb.KeyTemplate) (*keyset.Handle, error) {
+	if len(templates) == 0 {
+		return nil, errors.New("seededkeyset: no key templates")
+	}
+	ks := &tinkpb.Keyset{}
+	ids := make(map[uint32]bool)
+	for i, template := range templates {
+		key, err := newKey(seed, i, template)
+		if err != nil {
+			return nil, err
+		}
+		// Resolve collisions of the derived key IDs deterministically.
+		for ids[key.KeyId] {
+			key.KeyId++
+		}
+		ids[key.KeyId] = true
+		ks.Key = append(ks.Key, key)
+	}
+	ks.PrimaryKeyId = ks.Key[0].KeyId
+	if err := keyset.Validate(ks); err != nil {
+		return nil, fmt.Errorf("seededkeyset: invalid keyset: %v", err)
+	}
+	return testkeyset.KeysetHandle(ks), nil
+}
+
+// newKey derives the key at the given index of the keyset from seed. The key
+// ID and the key material are read from HKDF-SHA256 of the seed, with the
+// index as info.
+func newKey(seed []byte, index int, template *tinkpb.KeyTemplate) (*tinkpb.Keyset_Key, error) {
+	if template == nil {
+		return nil, errors.New("seededkeyset: nil key template")
+	}
+	km, err := registry.GetKeyManager(template.TypeUrl)
+	if err != nil {
+		return nil, fmt.Errorf("seededkeyset: %v", err)
+	}
+	dkm, ok := km.(registry.DerivableKeyManager)
+	if !ok {
+		return nil, fmt.Errorf("seededkeyset: key type %q does not support key derivation", template.TypeUrl)
+	}
+	info := make([]byte, 4)
+	binary.BigEndian.PutUint32(info, uint32(index))
+	r := hkdf.New(sha256.New, seed, []byte("tink seededkeyset"), info)
+	var id [4]byte
+	if _, err := io.ReadFull(r, id[:]); err != nil {
+		return nil, fmt.Errorf("seededkeyset: %v", err)
+	}
+	key, err := dkm.DeriveKey(template.Value, r)
+	if err != nil {
+		return nil, fmt.Errorf("seededkeyset: cannot derive key: %v", err)
+	}
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("seededkeyset: %v", err)
+	}
+	materialType := tinkpb.KeyData_SYMMETRIC
+	if _, ok := km.(registry.PrivateKeyManager); ok {
+		materialType = tinkpb.KeyData_ASYMMETRIC_PRIVATE
+	}
+	return &tinkpb.Keyset_Key{
+		KeyData: &tinkpb.KeyData{
+			TypeUrl:         template.TypeUrl,
+			Value:           serializedKey,
+			KeyMaterialType: materialType,
+		},
+		Status:           tinkpb.KeyStatusType_ENABLED,
+		KeyId:            binary.BigEndian.Uint32(id[:]),
+		OutputPrefixType: template.OutputPrefixType,
+	}, nil
+}
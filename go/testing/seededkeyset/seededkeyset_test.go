@@ -0,0 +1,122 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package seededkeyset_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/mac"
+	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/testing/seededkeyset"
+	"github.com/google/tink/go/testkeyset"
+	aesgcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+func TestNewHandleIsDeterministic(t *testing.T) {
+	templates := []*tinkpb.KeyTemplate{
+		aead.AES256GCMKeyTemplate(),
+		aead.ChaCha20Poly1305KeyTemplate(),
+		mac.HMACSHA256Tag256KeyTemplate(),
+	}
+	for _, template := range templates {
+		h1, err := seededkeyset.NewHandle([]byte("test seed"), template)
+		if err != nil {
+			t.Fatalf("seededkeyset.NewHandle(%s) failed: %v", template.TypeUrl, err)
+		}
+		h2, err := seededkeyset.NewHandle([]byte("test seed"), template)
+		if err != nil {
+			t.Fatalf("seededkeyset.NewHandle(%s) failed: %v", template.TypeUrl, err)
+		}
+		if !proto.Equal(testkeyset.KeysetMaterial(h1), testkeyset.KeysetMaterial(h2)) {
+			t.Errorf("seededkeyset.NewHandle(%s) returned different keysets for the same seed", template.TypeUrl)
+		}
+		h3, err := seededkeyset.NewHandle([]byte("other seed"), template)
+		if err != nil {
+			t.Fatalf("seededkeyset.NewHandle(%s) failed: %v", template.TypeUrl, err)
+		}
+		if proto.Equal(testkeyset.KeysetMaterial(h1), testkeyset.KeysetMaterial(h3)) {
+			t.Errorf("seededkeyset.NewHandle(%s) returned equal keysets for different seeds", template.TypeUrl)
+		}
+	}
+}
+
+func TestNewHandleKnownAnswer(t *testing.T) {
+	// Changing how keys are derived from the seed breaks fixtures that rely
+	// on it, so the derivation is pinned.
+	h, err := seededkeyset.NewHandle([]byte("test seed"), aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("seededkeyset.NewHandle() failed: %v", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	key := &aesgcmpb.AesGcmKey{}
+	if err := proto.Unmarshal(ks.Key[0].KeyData.Value, key); err != nil {
+		t.Fatalf("proto.Unmarshal() failed: %v", err)
+	}
+	if got, want := ks.PrimaryKeyId, uint32(1988848441); got != want {
+		t.Errorf("primary key ID = %d, want %d", got, want)
+	}
+	if got, want := hex.EncodeToString(key.KeyValue), "f0c0a0b1811ccd391d5b20da7fb6e481"; got != want {
+		t.Errorf("key value = %s, want %s", got, want)
+	}
+}
+
+func TestNewHandleMultipleKeys(t *testing.T) {
+	h, err := seededkeyset.NewHandle([]byte("test seed"), aead.AES128GCMKeyTemplate(), aead.AES128GCMKeyTemplate())
+	if err != nil {
+		t.Fatalf("seededkeyset.NewHandle() failed: %v", err)
+	}
+	ks := testkeyset.KeysetMaterial(h)
+	if len(ks.Key) != 2 {
+		t.Fatalf("len(ks.Key) = %d, want 2", len(ks.Key))
+	}
+	if ks.PrimaryKeyId != ks.Key[0].KeyId {
+		t.Errorf("primary key ID = %d, want the ID of the first key %d", ks.PrimaryKeyId, ks.Key[0].KeyId)
+	}
+	if ks.Key[0].KeyId == ks.Key[1].KeyId || proto.Equal(ks.Key[0].KeyData, ks.Key[1].KeyData) {
+		t.Error("keys for the same template are equal")
+	}
+
+	a, err := aead.New(h)
+	if err != nil {
+		t.Fatalf("aead.New() failed: %v", err)
+	}
+	ct, err := a.Encrypt([]byte("plaintext"), nil)
+	if err != nil {
+		t.Fatalf("a.Encrypt() failed: %v", err)
+	}
+	if pt, err := a.Decrypt(ct, nil); err != nil || string(pt) != "plaintext" {
+		t.Errorf("a.Decrypt() = %q, %v, want %q, nil", pt, err, "plaintext")
+	}
+}
+
+func TestNewHandleInvalidTemplates(t *testing.T) {
+	if _, err := seededkeyset.NewHandle([]byte("test seed")); err == nil {
+		t.Error("seededkeyset.NewHandle() without templates succeeded, want error")
+	}
+	for _, template := range []*tinkpb.KeyTemplate{aead.AES128CTRHMACSHA256KeyTemplate(), signature.ED25519KeyTemplate()} {
+		if _, err := seededkeyset.NewHandle([]byte("test seed"), template); err == nil {
+			t.Errorf("seededkeyset.NewHandle(%s) with a template that doesn't support derivation succeeded, want error", template.TypeUrl)
+		}
+	}
+	if _, err := seededkeyset.NewHandle([]byte("test seed"), nil); err == nil {
+		t.Error("seededkeyset.NewHandle(nil template) succeeded, want error")
+	}
+}
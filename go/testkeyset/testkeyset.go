@@ -1,3 +1,6 @@
+//go:build !tink_nocleartext
+// +build !tink_nocleartext
+
 // Copyright 2019 Google LLC
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -28,6 +28,10 @@ const (
 	AESGCMKeyVersion = 0
 	// AESGCMTypeURL is the type URL of AES-GCM keys that Tink supports.
 	AESGCMTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	// AESGCMSIVKeyVersion is the maximal version of AES-GCM-SIV keys.
+	AESGCMSIVKeyVersion = 0
+	// AESGCMSIVTypeURL is the type URL of AES-GCM-SIV keys that Tink supports.
+	AESGCMSIVTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmSivKey"
 
 	// ChaCha20Poly1305KeyVersion is the maximal version of ChaCha20Poly1305 keys that Tink supports.
 	ChaCha20Poly1305KeyVersion = 0
@@ -43,6 +47,14 @@ const (
 	XChaCha20Poly1305KeyVersion = 0
 	// XChaCha20Poly1305TypeURL is the type URL of XChaCha20Poly1305 keys.
 	XChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+	// XAES256GCMKeyVersion is the maximal version of XAES-256-GCM keys that Tink supports.
+	XAES256GCMKeyVersion = 0
+	// XAES256GCMTypeURL is the type URL of XAES-256-GCM keys.
+	XAES256GCMTypeURL = "type.googleapis.com/google.crypto.tink.XAes256GcmKey"
+	// Ascon128aKeyVersion is the maximal version of Ascon-128a keys that Tink supports.
+	Ascon128aKeyVersion = 0
+	// Ascon128aTypeURL is the type URL of Ascon-128a keys.
+	Ascon128aTypeURL = "type.googleapis.com/google.crypto.tink.Ascon128aKey"
 
 	// EciesAeadHkdfPrivateKeyKeyVersion is the maximal version of keys that this key manager supports.
 	EciesAeadHkdfPrivateKeyKeyVersion = 0
@@ -73,6 +85,22 @@ const (
 	AESCMACKeyVersion = 0
 	// AESCMACTypeURL is the type URL of AES-CMAC keys.
 	AESCMACTypeURL = "type.googleapis.com/google.crypto.tink.AesCmacKey"
+	// KMACKeyVersion is the maximal version of KMAC keys that Tink supports.
+	KMACKeyVersion = 0
+	// KMACTypeURL is the type URL of KMAC keys.
+	KMACTypeURL = "type.googleapis.com/google.crypto.tink.KmacKey"
+	// SipHashKeyVersion is the maximal version of SipHash keys that Tink supports.
+	SipHashKeyVersion = 0
+	// SipHashTypeURL is the type URL of SipHash keys.
+	SipHashTypeURL = "type.googleapis.com/google.crypto.tink.SipHashKey"
+	// Poly1305KeyVersion is the maximal version of Poly1305 keys that Tink supports.
+	Poly1305KeyVersion = 0
+	// Poly1305TypeURL is the type URL of Poly1305 keys.
+	Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.Poly1305Key"
+	// AESGMACKeyVersion is the maximal version of AES-GMAC keys that Tink supports.
+	AESGMACKeyVersion = 0
+	// AESGMACTypeURL is the type URL of AES-GMAC keys.
+	AESGMACTypeURL = "type.googleapis.com/google.crypto.tink.AesGmacKey"
 
 	// PRF Set
 
@@ -124,4 +152,11 @@ const (
 	AESCTRHMACKeyVersion = 0
 	// AESCTRHMACTypeURL is the type URL of AES-CTR-HMAC keys that Tink supports.
 	AESCTRHMACTypeURL = "type.googleapis.com/google.crypto.tink.AesCtrHmacStreamingKey"
+
+	// Streaming Deterministic AEAD
+
+	// AESSIVStreamingKeyVersion is the maximum version of AES-SIV streaming keys that Tink supports.
+	AESSIVStreamingKeyVersion = 0
+	// AESSIVStreamingTypeURL is the type URL of AES-SIV streaming keys that Tink supports.
+	AESSIVStreamingTypeURL = "type.googleapis.com/google.crypto.tink.AesSivStreamingKey"
 )
@@ -0,0 +1,45 @@
+// Copyright 2021 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+////////////////////////////////////////////////////////////////////////////////
+
+package tink
+
+import "io"
+
+/*
+StreamingDeterministicAEAD is an interface for deterministic streaming authenticated encryption
+with associated data.
+
+Warning:
+Unlike StreamingAEAD, implementations of this interface are not semantically secure, because
+encrypting the same plaintext with the same associated data always yields the same ciphertext.
+This makes it possible to deduplicate encrypted copies of the same data, such as backups of
+large files, but it also reveals whether two streams contain the same plaintext.
+
+The ciphertext of a stream is a sequence of segments that each depend on the plaintext of all
+preceding segments, so ciphertexts of streams with a common prefix share a common prefix.
+*/
+type StreamingDeterministicAEAD interface {
+	// NewDeterministicEncryptingWriter returns a wrapper around underlying io.Writer, such that
+	// any write-operation via the wrapper results in deterministic AEAD-encryption of the written
+	// data, using aad as associated authenticated data. The associated data is not included in the
+	// ciphertext and has to be passed in as parameter for decryption.
+	NewDeterministicEncryptingWriter(w io.Writer, aad []byte) (io.WriteCloser, error)
+
+	// NewDeterministicDecryptingReader returns a wrapper around underlying io.Reader, such that
+	// any read-operation via the wrapper results in deterministic AEAD-decryption of the
+	// underlying ciphertext, using aad as associated authenticated data.
+	NewDeterministicDecryptingReader(r io.Reader, aad []byte) (io.Reader, error)
+}